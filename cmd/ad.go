@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var adCmd = &cobra.Command{
+	Use:   "ad",
+	Short: "Views of this week's ad: deals, summary, new, ending, bogo",
+	Long: "Group the views of a store's weekly ad under one command. `pubcli ad deals` is the " +
+		"same as bare `pubcli`, which keeps working; the other views are shorthands for the " +
+		"deal listing with a preset, or for the commands they name.",
+	Example: `  pubcli ad deals --zip 33101 --category produce
+  pubcli ad new --store 1425
+  pubcli ad ending --store 1425 --limit 10
+  pubcli ad summary --store 1425
+  pubcli ad bogo --store 1425 --json`,
+}
+
+var adDealsCmd = &cobra.Command{
+	Use:   "deals",
+	Short: "List this week's deals (same as bare pubcli)",
+	Example: `  pubcli ad deals --zip 33101
+  pubcli ad deals --store 1425 --bogo --sort savings --limit 10`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runDeals,
+}
+
+var adNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "List the deals that were not in the store's previous ad",
+	Long: "List the deals new this week: shorthand for `pubcli --new-only`. The first run for a " +
+		"store saves its ad, so new deals show from the next ad on.",
+	Example: `  pubcli ad new --store 1425
+  pubcli ad new --store 1425 --sort savings --json`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flagNewOnly = true
+		return runDeals(cmd, args)
+	},
+}
+
+var adEndingCmd = &cobra.Command{
+	Use:   "ending",
+	Short: "List this week's deals ending soonest first",
+	Long:  "List the deals in order of their end date: shorthand for `pubcli --sort ending`. --sort still picks another order.",
+	Example: `  pubcli ad ending --store 1425 --limit 10
+  pubcli ad ending --store 1425 --bogo`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("sort") {
+			flagSort = "ending"
+		}
+		return runDeals(cmd, args)
+	},
+}
+
+var adSummaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Ten-second overview of this week's ad (same as pubcli summary)",
+	Example: `  pubcli ad summary --store 1425
+  pubcli ad summary --zip 33101 --json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runSummary,
+}
+
+var adBogoCmd = &cobra.Command{
+	Use:   "bogo",
+	Short: "List this week's BOGO deals by department (same as pubcli bogo)",
+	Example: `  pubcli ad bogo --zip 33101
+  pubcli ad bogo --store 1425 --department produce`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runBogo,
+}
+
+func init() {
+	rootCmd.AddCommand(adCmd)
+	adCmd.AddCommand(adDealsCmd, adSummaryCmd, adNewCmd, adEndingCmd, adBogoCmd)
+	for _, view := range []*cobra.Command{adDealsCmd, adNewCmd, adEndingCmd} {
+		registerDealsFlags(view.Flags())
+	}
+	registerDealFilterFlags(adBogoCmd.Flags())
+	registerSummaryFlags(adSummaryCmd.Flags())
+}
@@ -0,0 +1,689 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/alert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/diag"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+const (
+	alertStateFile = "alert-state.json"
+	// alertEditFile keeps watchlist edits that failed validation.
+	alertEditFile = "watchlist.edit.json"
+)
+
+var (
+	flagAlertDryRun   bool
+	flagAlertStores   []string
+	flagAlertMaxPrice float64
+	flagAlertRenotify bool
+)
+
+var alertCmd = &cobra.Command{
+	Use:   "alert",
+	Short: "Check watched items against the weekly ad and send notifications",
+	Long: "Each alert rule has a name and criteria a deal must all meet: keywords matched against " +
+		"deal titles and descriptions, the category, department, query, and --bogo filters of deal " +
+		"listings, and a price cap. A rule may also list the stores it watches; rules without stores " +
+		"watch the --store or --zip store. Rules come from `alerts: rules:` in config.yaml and from " +
+		"the watchlist edited with `pubcli alert add` and `pubcli alert edit`. Destinations live under `alerts:` in config.yaml; matches are sent " +
+		"to every configured webhook and to Telegram.",
+	Example: `  pubcli alert add coffee coffee espresso
+  pubcli alert add beef "ground beef" --for-store 1425 --for-store 1500
+  pubcli alert add cheap-produce --category produce --bogo --max-price 3
+  pubcli alert edit
+  pubcli alert list
+  pubcli alert run --store 1425
+  pubcli alert run --dry-run`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var alertListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "Show alert rules and notification destinations",
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runAlertList,
+}
+
+var alertImportCmd = &cobra.Command{
+	Use:   "import FILE.csv",
+	Short: "Add the rules of a CSV file to the watchlist",
+	Long: "Add one rule per row of a CSV file (- reads stdin), replacing rules with the same name. " +
+		"The first row names the columns, in any order and case: name (required), keywords, " +
+		"category, department, query, bogo, max_price, min_percent_off, stores, sort, limit, and " +
+		"include_expired, " +
+		"as in the `alerts.rules` config. keywords and stores take several values separated by " +
+		"semicolons; bogo and include_expired take yes or no. Nothing is imported if a row is invalid.",
+	Example:     `  pubcli alert import watchlist.csv`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runAlertImport,
+}
+
+var alertAddCmd = &cobra.Command{
+	Use:   "add NAME [KEYWORD...]",
+	Short: "Add a rule to the watchlist, or replace the rule with that name",
+	Long: "Add a rule to the watchlist. A deal matches when it contains any KEYWORD and meets every " +
+		"filter flag given; a rule needs keywords or at least one filter. --sort and --limit order " +
+		"and cap the deals the rule reports per store.",
+	Example: `  pubcli alert add beef "ground beef" "chuck roast"
+  pubcli alert add coffee coffee --for-store 1425,1500
+  pubcli alert add meat-bogo --department meat --bogo
+  pubcli alert add cheap-coffee coffee --max-price 5 --sort savings --limit 3`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runAlertAdd,
+}
+
+var alertRemoveCmd = &cobra.Command{
+	Use:         "remove NAME",
+	Aliases:     []string{"rm"},
+	Short:       "Remove a rule from the watchlist",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runAlertRemove,
+}
+
+var alertEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the watchlist rules in $EDITOR",
+	Long: "Open the watchlist, " + alert.WatchlistFile + " in the data directory, in $VISUAL or $EDITOR " +
+		"(vi, or notepad on Windows, when neither is set). The rules are checked when the editor " +
+		"exits. Invalid edits leave the watchlist unchanged and are kept in " + alertEditFile +
+		", which the next `pubcli alert edit` reopens.",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runAlertEdit,
+}
+
+var alertRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Evaluate alert rules and notify destinations about matches",
+	Long: "Fetch the weekly ad of every store the alert rules watch, print the deals that match, " +
+		"and send each store's matches to the configured destinations. Each store is fetched once, " +
+		"several at a time, however many rules watch it. --store or --zip is only needed for rules " +
+		"without their own stores. Nothing is sent for a store when no rule matches. A deal is sent " +
+		"once per rule and store each ad week; later runs leave it out until the next ad, unless " +
+		"--renotify is given. Intended for cron:\n\n" +
+		"  0 8 * * * pubcli alert run --store 1425",
+	Example: `  pubcli alert run --store 1425
+  pubcli alert run --dry-run --json
+  pubcli alert run --renotify`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runAlertRun,
+}
+
+var alertHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the deals alert runs have sent",
+	Long: "List the deals `pubcli alert run` sent in the last four weeks, by ad week, store, and rule. " +
+		"Deals sent in the current ad week are left out of later runs unless --renotify is given.",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runAlertHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(alertCmd)
+	alertCmd.AddCommand(alertListCmd, alertAddCmd, alertEditCmd, alertImportCmd, alertRemoveCmd, alertRunCmd, alertHistoryCmd)
+	alertRunCmd.Flags().BoolVar(&flagAlertDryRun, "dry-run", false, "Print matches without sending notifications")
+	alertRunCmd.Flags().BoolVar(&flagAlertRenotify, "renotify", false, "Send matches again even if they were already sent this ad week")
+	registerDealFilterFlags(alertAddCmd.Flags())
+	alertAddCmd.Flags().Float64Var(&flagAlertMaxPrice, "max-price", 0, "Only match deals costing at most this much per item (0 = no cap)")
+	alertAddCmd.Flags().StringSliceVar(&flagAlertStores, "for-store", nil, "Store number the rule watches; repeat or comma-separate for several (default: the --store or --zip store)")
+}
+
+// alertRunJSON is the --json output of `alert run`.
+type alertRunJSON struct {
+	alert.RunReport
+	Deliveries []alert.Delivery `json:"deliveries"`
+}
+
+func loadWatchlist() (*alert.Watchlist, storage.Backend, error) {
+	store, err := dataStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	w, err := alert.LoadWatchlist(store, alert.WatchlistFile)
+	if err != nil {
+		return nil, nil, configError(err)
+	}
+	return w, store, nil
+}
+
+// alertRules returns the config rules followed by the watchlist rules.
+func alertRules(cfg config.Alerts) ([]alert.Rule, error) {
+	w, _, err := loadWatchlist()
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]alert.Rule, 0, len(cfg.Rules)+len(w.Rules))
+	for i, r := range cfg.Rules {
+		rule := configAlertRule(r)
+		if err := rule.Validate(); err != nil {
+			return nil, configError(fmt.Errorf("alerts.rules[%d]: %w", i, err))
+		}
+		rules = append(rules, rule)
+	}
+	return append(rules, w.Rules...), nil
+}
+
+func configAlertRule(r config.AlertRule) alert.Rule {
+	return alert.Rule{
+		Name:           r.Name,
+		Keywords:       r.Keywords,
+		Stores:         r.Stores,
+		Category:       r.Category,
+		Department:     r.Department,
+		Query:          r.Query,
+		BOGO:           r.BOGO,
+		MaxPrice:       r.MaxPrice,
+		MinPercentOff:  r.MinPercentOff,
+		Sort:           r.Sort,
+		Limit:          r.Limit,
+		IncludeExpired: r.IncludeExpired,
+	}
+}
+
+// alertNotifiers builds the configured destinations.
+func alertNotifiers(cfg config.Alerts) ([]alert.Notifier, error) {
+	var notifiers []alert.Notifier
+	for i, url := range cfg.Webhooks {
+		if strings.TrimSpace(url) == "" {
+			continue
+		}
+		url, err := resolveSecret(fmt.Sprintf("alerts.webhooks[%d]", i), url)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, &alert.Webhook{URL: url, Index: i + 1})
+	}
+
+	if tg := cfg.Telegram; tg != nil {
+		resolved := *tg
+		token, err := resolveSecret("alerts.telegram.bot_token", tg.BotToken)
+		if err != nil {
+			return nil, err
+		}
+		resolved.BotToken = token
+		notifier, err := telegramNotifier(&resolved)
+		if err != nil {
+			return nil, configError(err)
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, nil
+}
+
+// telegramNotifier builds the `alerts.telegram` destination.
+func telegramNotifier(tg *config.Telegram) (alert.Notifier, error) {
+	telegram, err := alert.NewTelegram(tg.BotToken, tg.ChatID, tg.Template)
+	if err != nil {
+		return nil, fmt.Errorf("alerts.telegram: %w", err)
+	}
+	if tg.MinInterval == "" {
+		return telegram, nil
+	}
+	interval, err := time.ParseDuration(tg.MinInterval)
+	if err != nil || interval < 0 {
+		return nil, fmt.Errorf("alerts.telegram.min_interval %q is not a duration like 1h", tg.MinInterval)
+	}
+	store, err := dataStore()
+	if err != nil {
+		return nil, err
+	}
+	return &alert.RateLimited{Notifier: telegram, Interval: interval, Store: store, StateKey: alertStateFile}, nil
+}
+
+func runAlertList(cmd *cobra.Command, _ []string) error {
+	cfg := activeConfig.Alerts
+	if _, err := alertNotifiers(cfg); err != nil {
+		return err
+	}
+
+	w, _, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(cfg.Rules) == 0 && len(w.Rules) == 0 {
+		fmt.Fprintln(out, "No alert rules. Add one with `pubcli alert add NAME [KEYWORD...]`.")
+	} else {
+		fmt.Fprintln(out, "Rules:")
+		for _, r := range cfg.Rules {
+			fmt.Fprintf(out, "  %s: %s (config.yaml)\n", r.Name, configAlertRule(r).Describe())
+		}
+		for _, r := range w.Rules {
+			fmt.Fprintf(out, "  %s: %s\n", r.Name, r.Describe())
+		}
+	}
+
+	fmt.Fprintln(out, "Destinations:")
+	if len(cfg.Webhooks) == 0 && cfg.Telegram == nil {
+		fmt.Fprintln(out, "  none (matches are only printed)")
+	}
+	for i := range cfg.Webhooks {
+		fmt.Fprintf(out, "  webhook #%d\n", i+1)
+	}
+	if tg := cfg.Telegram; tg != nil {
+		limit := ""
+		if tg.MinInterval != "" {
+			limit = ", at most once per " + tg.MinInterval
+		}
+		fmt.Fprintf(out, "  telegram (chat %s%s)\n", tg.ChatID, limit)
+	}
+	return nil
+}
+
+func runAlertAdd(cmd *cobra.Command, args []string) error {
+	w, store, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+	if err := validateSortMode(); err != nil {
+		return err
+	}
+	rule := alert.Rule{
+		Name:           strings.TrimSpace(args[0]),
+		Category:       strings.TrimSpace(flagCategory),
+		Department:     strings.TrimSpace(flagDepartment),
+		Query:          strings.TrimSpace(flagQuery),
+		BOGO:           flagBogo,
+		MaxPrice:       flagAlertMaxPrice,
+		MinPercentOff:  flagMinPercentOff,
+		Sort:           strings.TrimSpace(flagSort),
+		Limit:          flagLimit,
+		IncludeExpired: flagIncludeExpired,
+	}
+	for _, kw := range args[1:] {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			rule.Keywords = append(rule.Keywords, kw)
+		}
+	}
+	for _, s := range flagAlertStores {
+		s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+		if s == "" {
+			continue
+		}
+		if strings.Trim(s, "0123456789") != "" {
+			return invalidArgsError(fmt.Sprintf("--for-store %q is not a store number", s), "pubcli alert add coffee coffee --for-store 1425")
+		}
+		rule.Stores = append(rule.Stores, s)
+	}
+	if err := rule.Validate(); err != nil {
+		return invalidArgsError(err.Error(),
+			"pubcli alert add coffee coffee espresso",
+			"pubcli alert add produce-bogo --category produce --bogo",
+		)
+	}
+
+	replaced := w.Set(rule)
+	if err := w.Save(store, alert.WatchlistFile); err != nil {
+		return err
+	}
+	verb := "Added"
+	if replaced {
+		verb = "Replaced"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s rule %q: %s\n", verb, rule.Name, rule.Describe())
+	return nil
+}
+
+func runAlertImport(cmd *cobra.Command, args []string) error {
+	f, err := openImportFile(cmd, args[0], "pubcli alert import watchlist.csv")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rules, err := alert.ImportCSV(f)
+	if err != nil {
+		return invalidArgsError(fmt.Sprintf("%s: %v", args[0], err), "The first row must name the columns: "+strings.Join(alert.ImportColumns, ","))
+	}
+
+	w, store, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+	replaced := 0
+	for _, rule := range rules {
+		if w.Set(rule) {
+			replaced++
+		}
+	}
+	if err := w.Save(store, alert.WatchlistFile); err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	for _, rule := range rules {
+		fmt.Fprintf(out, "  %s: %s\n", rule.Name, rule.Describe())
+	}
+	fmt.Fprintf(out, "Imported %d rule(s)", len(rules))
+	if replaced > 0 {
+		fmt.Fprintf(out, ", replacing %d with the same name", replaced)
+	}
+	fmt.Fprintln(out, ".")
+	return nil
+}
+
+// runEditor opens path in the user's editor and waits for it to exit.
+var runEditor = func(cmd *cobra.Command, path string) error {
+	editor := editorCommand(os.Getenv)
+	c := exec.CommandContext(cmd.Context(), editor[0], append(editor[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running editor %s: %w", editor[0], err)
+	}
+	return nil
+}
+
+// editorCommand is $VISUAL or $EDITOR split into words, so values such as
+// "code --wait" work, defaulting to vi, or notepad on Windows.
+func editorCommand(getenv func(string) string) []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+func runAlertEdit(cmd *cobra.Command, _ []string) error {
+	w, store, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+	// The editor needs a real file, whatever the storage backend.
+	dir, err := config.DataDir()
+	if err != nil {
+		return configError(err)
+	}
+	editFiles := &storage.Files{Dir: dir}
+	editPath := filepath.Join(dir, alertEditFile)
+	if _, err := os.Stat(editPath); err == nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Reopening your unsaved edits from %s.\n", editPath)
+	} else if err := w.Save(editFiles, alertEditFile); err != nil {
+		return err
+	}
+
+	if err := runEditor(cmd, editPath); err != nil {
+		return err
+	}
+
+	edited, err := alert.LoadWatchlist(editFiles, alertEditFile)
+	if err == nil {
+		err = edited.Validate()
+	}
+	if err != nil {
+		return invalidArgsError(
+			fmt.Sprintf("the edited watchlist is invalid, so it was not saved: %v", err),
+			fmt.Sprintf("Your edits are kept in %s; run `pubcli alert edit` again to fix them.", editPath),
+		)
+	}
+	if err := edited.Save(store, alert.WatchlistFile); err != nil {
+		return err
+	}
+	if err := os.Remove(editPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Saved %d watchlist rule(s).\n", len(edited.Rules))
+	return nil
+}
+
+func runAlertHistory(cmd *cobra.Command, _ []string) error {
+	store, err := dataStore()
+	if err != nil {
+		return err
+	}
+	notified, err := alert.LoadNotifiedLog(store, alert.NotifiedFile)
+	if err != nil {
+		return configError(err)
+	}
+	entries := notified.Entries
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Week != entries[j].Week {
+			return entries[i].Week > entries[j].Week
+		}
+		if entries[i].Store != entries[j].Store {
+			return entries[i].Store < entries[j].Store
+		}
+		return entries[i].Rule < entries[j].Rule
+	})
+
+	if flagJSON {
+		if entries == nil {
+			entries = []alert.Notified{}
+		}
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "notified", entries)
+	}
+	out := cmd.OutOrStdout()
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No alerts sent in the last four weeks.")
+		return nil
+	}
+	week := ""
+	for _, e := range entries {
+		if e.Week != week {
+			if week != "" {
+				fmt.Fprintln(out)
+			}
+			week = e.Week
+			fmt.Fprintf(out, "Ad week of %s:\n", week)
+		}
+		fmt.Fprintf(out, "  #%s %s: %s (sent %s)\n", e.Store, e.Rule, e.Title, e.NotifiedAt.Local().Format("Jan 2 15:04"))
+	}
+	return nil
+}
+
+func runAlertRemove(cmd *cobra.Command, args []string) error {
+	w, store, err := loadWatchlist()
+	if err != nil {
+		return err
+	}
+	if !w.Remove(args[0]) {
+		return notFoundError(fmt.Sprintf("no watchlist rule named %q", args[0]), "pubcli alert list")
+	}
+	if err := w.Save(store, alert.WatchlistFile); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed rule %q.\n", args[0])
+	return nil
+}
+
+func runAlertRun(cmd *cobra.Command, _ []string) error {
+	cfg := activeConfig.Alerts
+	rules, err := alertRules(cfg)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return invalidArgsError(
+			"no alert rules configured",
+			"Add one with `pubcli alert add NAME [KEYWORD...]`, then run `pubcli alert list`.",
+		)
+	}
+	notifiers, err := alertNotifiers(cfg)
+	if err != nil {
+		return err
+	}
+
+	rec := &diag.Recorder{}
+	client := newAPIClient(api.WithObserver(rec.Record))
+	var defaultStore string
+	if alert.NeedsDefaultStore(rules) {
+		if defaultStore, err = resolveStore(cmd, client); err != nil {
+			return err
+		}
+	}
+	fetch := func(ctx context.Context, storeNumber string) (*api.SavingsResponse, error) {
+		return fetchSavingsCached(ctx, client, storeNumber)
+	}
+	run := alert.Run(cmd.Context(), rules, defaultStore, fetch, alert.DefaultConcurrency)
+	if len(run.Stores) == 0 && len(run.Failed) > 0 {
+		return upstreamError("fetching deals", run.Failed[0].Err)
+	}
+
+	deliveries, err := dispatchAlerts(cmd, &run, notifiers)
+	if err != nil {
+		return err
+	}
+
+	if flagJSON {
+		if err := printJSONWithDiagnostics(cmd.OutOrStdout(), "alert", alertRunJSON{RunReport: run, Deliveries: deliveries}, rec); err != nil {
+			return err
+		}
+	} else {
+		printAlertReport(cmd.OutOrStdout(), run, deliveries)
+		if note := diagnosticsNote(len(run.Failed), rec); note != "" {
+			fmt.Fprintln(cmd.ErrOrStderr(), note)
+		}
+	}
+	return deliveryError(deliveries)
+}
+
+// dispatchAlerts sends each store's matches to the notifiers, leaving out
+// deals already sent this ad week unless --renotify is given, and records
+// what reached the user. --dry-run skips sending and recording.
+func dispatchAlerts(cmd *cobra.Command, run *alert.RunReport, notifiers []alert.Notifier) ([]alert.Delivery, error) {
+	store, err := dataStore()
+	if err != nil {
+		return nil, err
+	}
+	notified, err := alert.LoadNotifiedLog(store, alert.NotifiedFile)
+	if err != nil {
+		return nil, configError(err)
+	}
+	now := time.Now()
+	week := history.WeekStart(now).Format("2006-01-02")
+	if !flagAlertRenotify {
+		notified.Suppress(run, week)
+	}
+
+	deliveries := []alert.Delivery{}
+	if flagAlertDryRun {
+		return deliveries, nil
+	}
+	recorded := false
+	for _, report := range run.Stores {
+		if len(report.Matches) == 0 {
+			continue
+		}
+		sent := alert.Dispatch(cmd.Context(), report, notifiers)
+		deliveries = append(deliveries, sent...)
+		if reportDelivered(notifiers, sent) {
+			notified.Record(report, week, now)
+			recorded = true
+		}
+	}
+	if recorded {
+		if err := notified.Save(store, alert.NotifiedFile, now); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, nil
+}
+
+// reportDelivered reports whether a store's matches reached the user: sent
+// to at least one destination, or only printed when none is configured.
+// Deals whose every send failed or was rate limited are tried again on the
+// next run.
+func reportDelivered(notifiers []alert.Notifier, deliveries []alert.Delivery) bool {
+	if len(notifiers) == 0 {
+		return true
+	}
+	for _, d := range deliveries {
+		if d.Sent {
+			return true
+		}
+	}
+	return false
+}
+
+// printAlertReport prints each store's matches with the default message
+// template, then, when rules watch more than one store, a per-rule
+// summary.
+func printAlertReport(w io.Writer, run alert.RunReport, deliveries []alert.Delivery) {
+	tmpl, _ := alert.ParseTemplate("")
+	for i, report := range run.Stores {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		switch {
+		case len(report.Matches) == 0 && report.Suppressed > 0:
+			fmt.Fprintf(w, "No new watched items at store #%s; %d already sent this ad week (--renotify sends them again).\n",
+				report.Store, report.Suppressed)
+		case len(report.Matches) == 0:
+			fmt.Fprintf(w, "No watched items are on sale at store #%s.\n", report.Store)
+		default:
+			tmpl.Execute(w, report)
+			if report.Suppressed > 0 {
+				fmt.Fprintf(w, "(%d more already sent this ad week)\n", report.Suppressed)
+			}
+		}
+	}
+
+	if len(run.Stores)+len(run.Failed) > 1 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "By rule:")
+		for _, rule := range run.Rules {
+			if len(rule.Matches) == 0 {
+				fmt.Fprintf(w, "  %s: no matches at #%s\n", rule.Rule, strings.Join(rule.Stores, ", #"))
+				continue
+			}
+			parts := make([]string, 0, len(rule.Matches))
+			for _, m := range rule.Matches {
+				parts = append(parts, fmt.Sprintf("%d at #%s", len(m.Deals), m.Store))
+			}
+			fmt.Fprintf(w, "  %s: %s\n", rule.Rule, strings.Join(parts, ", "))
+		}
+	}
+	printDeliveries(w, deliveries)
+}
+
+func printDeliveries(w io.Writer, deliveries []alert.Delivery) {
+	for _, d := range deliveries {
+		switch {
+		case d.Sent:
+			fmt.Fprintf(w, "sent store #%s to %s\n", d.Store, d.Target)
+		case d.Skipped != "":
+			fmt.Fprintf(w, "skipped %s for store #%s: %s\n", d.Target, d.Store, d.Skipped)
+		default:
+			fmt.Fprintf(w, "failed to send store #%s to %s: %s\n", d.Store, d.Target, d.Error)
+		}
+	}
+}
+
+// deliveryError turns failed deliveries into an upstream error so cron sees
+// a non-zero exit. Rate-limited skips are not failures.
+func deliveryError(deliveries []alert.Delivery) error {
+	var failed []string
+	for _, d := range deliveries {
+		if d.Error != "" {
+			failed = append(failed, fmt.Sprintf("%s (store #%s): %s", d.Target, d.Store, d.Error))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return upstreamError("sending alerts", errors.New(strings.Join(failed, "; ")))
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/alert"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestRunCLI_AlertAddWithFilters(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"alert", "add", "produce", "--category", "produce", "--bogo", "--max-price", "3", "--limit", "5"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `Added rule "produce": category produce; bogo only; max $3.00; top 5`)
+
+	w, err := alert.LoadWatchlist(&storage.Files{Dir: dataDir}, alert.WatchlistFile)
+	require.NoError(t, err)
+	require.Len(t, w.Rules, 1)
+	assert.Equal(t, alert.Rule{Name: "produce", Category: "produce", BOGO: true, MaxPrice: 3, Limit: 5}, w.Rules[0])
+
+	stdout.Reset()
+	stderr.Reset()
+	code = runCLI([]string{"alert", "add", "empty"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "needs keywords")
+}
+
+func TestRunCLI_AlertEdit(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+	original := runEditor
+	t.Cleanup(func() { runEditor = original })
+
+	var contents string
+	runEditor = func(_ *cobra.Command, path string) error {
+		return os.WriteFile(path, []byte(contents), 0o644)
+	}
+	watchlist := filepath.Join(dataDir, alert.WatchlistFile)
+	editFile := filepath.Join(dataDir, alertEditFile)
+
+	contents = `{"rules": [{"name": "cheap", "keywords": ["coffee"], "maxPrice": -1}]}`
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"alert", "edit"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "max price must not be negative")
+	assert.NoFileExists(t, watchlist)
+	assert.FileExists(t, editFile, "invalid edits are kept")
+
+	runEditor = func(_ *cobra.Command, path string) error {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"maxPrice": -1`, "the next edit reopens the kept edits")
+		return os.WriteFile(path, []byte(`{"rules": [{"name": "cheap", "keywords": ["coffee"], "maxPrice": 4.5}]}`), 0o644)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	code = runCLI([]string{"alert", "edit"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Saved 1 watchlist rule(s).")
+	assert.NoFileExists(t, editFile)
+
+	w, err := alert.LoadWatchlist(&storage.Files{Dir: dataDir}, alert.WatchlistFile)
+	require.NoError(t, err)
+	require.Len(t, w.Rules, 1)
+	assert.Equal(t, 4.5, w.Rules[0].MaxPrice)
+}
+
+func TestEditorCommand(t *testing.T) {
+	env := map[string]string{"EDITOR": "code --wait"}
+	assert.Equal(t, []string{"code", "--wait"}, editorCommand(func(k string) string { return env[k] }))
+	env["VISUAL"] = "nvim"
+	assert.Equal(t, []string{"nvim"}, editorCommand(func(k string) string { return env[k] }))
+}
+
+func TestRunCLI_AlertHistory(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"alert", "history", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "No alerts sent")
+
+	now := time.Now()
+	log := &alert.NotifiedLog{Entries: []alert.Notified{
+		{Store: "1425", Rule: "coffee", Key: "id:1", Title: "Publix Coffee", Week: "2026-10-07", NotifiedAt: now},
+		{Store: "1425", Rule: "coffee", Key: "id:2", Title: "Espresso Beans", Week: "2026-10-14", NotifiedAt: now},
+	}}
+	require.NoError(t, log.Save(&storage.Files{Dir: dataDir}, alert.NotifiedFile, now))
+
+	stdout.Reset()
+	code = runCLI([]string{"alert", "history", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	text := stdout.String()
+	assert.Contains(t, text, "Ad week of 2026-10-14:\n  #1425 coffee: Espresso Beans")
+	assert.Less(t, strings.Index(text, "2026-10-14"), strings.Index(text, "2026-10-07"), "newest week first")
+
+	stdout.Reset()
+	code = runCLI([]string{"alert", "history", "--json"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"notified":[{"store":"1425","rule":"coffee","key":"id:2"`)
+}
+
+func TestRunCLI_AlertImport(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"alert", "add", "coffee", "coffee"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+
+	csvPath := filepath.Join(t.TempDir(), "watchlist.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("name,keywords,max price\ncoffee,coffee;espresso,\nchicken,chicken thighs,3.99\n"), 0o644))
+	stdout.Reset()
+	code = runCLI([]string{"alert", "import", csvPath, "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Imported 2 rule(s), replacing 1 with the same name.")
+
+	w, err := alert.LoadWatchlist(&storage.Files{Dir: dataDir}, alert.WatchlistFile)
+	require.NoError(t, err)
+	assert.Equal(t, []alert.Rule{
+		{Name: "coffee", Keywords: []string{"coffee", "espresso"}},
+		{Name: "chicken", Keywords: []string{"chicken thighs"}, MaxPrice: 3.99},
+	}, w.Rules)
+
+	require.NoError(t, os.WriteFile(csvPath, []byte("name,keyword\nx,y\n"), 0o644))
+	stderr.Reset()
+	code = runCLI([]string{"alert", "import", csvPath, "--json=false"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "unknown column")
+}
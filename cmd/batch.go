@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/diag"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+var flagBatchFile string
+
+// batchSpec is one query in a batch file. Field names match the CLI flags.
+type batchSpec struct {
+	ID             string `json:"id"`
+	Command        string `json:"command"`
+	Store          string `json:"store"`
+	Zip            string `json:"zip"`
+	Category       string `json:"category"`
+	Department     string `json:"department"`
+	Query          string `json:"query"`
+	Bogo           bool   `json:"bogo"`
+	Sort           string `json:"sort"`
+	Limit          int    `json:"limit"`
+	IncludeExpired bool   `json:"includeExpired"`
+	Count          int    `json:"count"`
+}
+
+// batchResult is one NDJSON line of batch output. Exactly one of the payload
+// fields or Error is set, matching the command's normal JSON key.
+type batchResult struct {
+	SchemaVersion int                `json:"schemaVersion"`
+	Index         int                `json:"index"`
+	ID            string             `json:"id,omitempty"`
+	Command       string             `json:"command"`
+	OK            bool               `json:"ok"`
+	Store         string             `json:"store,omitempty"`
+	Deals         []display.DealJSON `json:"deals,omitempty"`
+	Stores        any                `json:"stores,omitempty"`
+	Categories    map[string]int     `json:"categories,omitempty"`
+	Error         *jsonErrorBody     `json:"error,omitempty"`
+}
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Run many queries in one invocation and stream NDJSON results",
+	Long: "Read a JSON array of query specs from --file (or stdin) and run them with one shared, " +
+		"memoizing API client, so repeated stores and ZIP codes are fetched once. " +
+		"Each spec has a command (deals, stores, categories, compare) plus flag-named fields; " +
+		"one JSON line is written per spec, in input order. If any upstream request failed, a " +
+		"final line with a diagnostics object tallies the failures by type.",
+	Example: `  pubcli batch --file queries.json
+  echo '[{"command":"deals","zip":"33101","bogo":true},{"command":"categories","zip":"33101"}]' | pubcli batch`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runBatch,
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+	batchCmd.Flags().StringVar(&flagBatchFile, "file", "", "JSON file with an array of query specs (default: stdin)")
+}
+
+func runBatch(cmd *cobra.Command, _ []string) error {
+	var in io.Reader = cmd.InOrStdin()
+	if flagBatchFile != "" && flagBatchFile != "-" {
+		f, err := os.Open(flagBatchFile)
+		if err != nil {
+			return invalidArgsError(
+				fmt.Sprintf("cannot read batch file: %v", err),
+				"pubcli batch --file queries.json",
+			)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	specs, err := parseBatchSpecs(in)
+	if err != nil {
+		return err
+	}
+
+	rec := &diag.Recorder{}
+	client := newAPIClient(api.WithMemo(), api.WithObserver(rec.Record))
+	failed, firstErr := runBatchQueries(cmd.Context(), cmd.OutOrStdout(), client, specs)
+	if summary := rec.Summary(); summary != nil {
+		// A last line, without an index, tallies the failed and retried
+		// upstream requests.
+		if err := display.EncodeVersioned(cmd.OutOrStdout(), display.LatestSchemaVersion, "diagnostics", summary); err != nil {
+			return err
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return &cliError{
+		Code:        firstErr.Code,
+		Message:     fmt.Sprintf("%d of %d batch queries failed; see the error lines in the output", failed, len(specs)),
+		Suggestions: []string{"Check each line's \"ok\" and \"error\" fields."},
+		ExitCode:    firstErr.ExitCode,
+	}
+}
+
+// parseBatchSpecs decodes the spec array. Each spec is kept raw so that one
+// malformed spec becomes an error line instead of failing the whole batch.
+func parseBatchSpecs(r io.Reader) ([]json.RawMessage, error) {
+	var specs []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&specs); err != nil {
+		return nil, invalidArgsError(
+			fmt.Sprintf("batch input must be a JSON array of query specs: %v", err),
+			`[{"command":"deals","zip":"33101","limit":5}]`,
+		)
+	}
+	if len(specs) == 0 {
+		return nil, invalidArgsError(
+			"batch input contains no queries",
+			`[{"command":"stores","zip":"33101"}]`,
+		)
+	}
+	return specs, nil
+}
+
+// runBatchQueries executes each spec in order, writing one NDJSON line per
+// spec. It returns the number of failures and the first failure's error.
+func runBatchQueries(ctx context.Context, w io.Writer, client *api.Client, specs []json.RawMessage) (int, *cliError) {
+	enc := json.NewEncoder(w)
+	failed := 0
+	var firstErr *cliError
+
+	for i, raw := range specs {
+		result := batchResult{SchemaVersion: display.LatestSchemaVersion, Index: i}
+
+		var spec batchSpec
+		err := decodeBatchSpec(raw, &spec)
+		if err == nil {
+			result.ID = spec.ID
+			result.Command = normalizeBatchCommand(spec.Command)
+			err = executeBatchSpec(ctx, client, spec, &result)
+		}
+		if err != nil {
+			cliErr := classifyCLIError(err)
+			result.Error = &jsonErrorBody{
+				Code:        cliErr.Code,
+				Message:     cliErr.Message,
+				Suggestions: cliErr.Suggestions,
+				ExitCode:    cliErr.ExitCode,
+			}
+			failed++
+			if firstErr == nil {
+				firstErr = cliErr
+			}
+		} else {
+			result.OK = true
+		}
+
+		if err := enc.Encode(result); err != nil {
+			return failed + len(specs) - i, classifyCLIError(err)
+		}
+	}
+	return failed, firstErr
+}
+
+func decodeBatchSpec(raw json.RawMessage, spec *batchSpec) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(spec); err != nil {
+		return invalidArgsError(
+			fmt.Sprintf("invalid query spec: %v", err),
+			`{"command":"deals","zip":"33101","category":"produce"}`,
+		)
+	}
+	return nil
+}
+
+func normalizeBatchCommand(command string) string {
+	command = strings.ToLower(strings.TrimSpace(command))
+	if command == "" {
+		return "deals"
+	}
+	return command
+}
+
+func executeBatchSpec(ctx context.Context, client *api.Client, spec batchSpec, result *batchResult) error {
+	if err := validateSort(spec.Sort); err != nil {
+		return err
+	}
+	if spec.Limit < 0 {
+		return invalidArgsError("limit must be zero or positive")
+	}
+	opts := filter.Options{
+		BOGO:           spec.Bogo,
+		Category:       spec.Category,
+		Department:     spec.Department,
+		Query:          spec.Query,
+		Sort:           spec.Sort,
+		Limit:          spec.Limit,
+		ExcludeExpired: !spec.IncludeExpired,
+	}
+
+	switch result.Command {
+	case "deals":
+		storeNumber, data, err := batchSavings(ctx, client, spec)
+		if err != nil {
+			return err
+		}
+		items := filter.Apply(data.Savings, opts)
+		if len(items) == 0 {
+			return notFoundError(
+				"no deals match your filters",
+				"Relax filters like category/department/query.",
+			)
+		}
+		result.Store = storeNumber
+		result.Deals = make([]display.DealJSON, 0, len(items))
+		for _, item := range items {
+			result.Deals = append(result.Deals, display.ToDealJSON(item))
+		}
+	case "categories":
+		storeNumber, data, err := batchSavings(ctx, client, spec)
+		if err != nil {
+			return err
+		}
+		result.Store = storeNumber
+		result.Categories = filter.Categories(data.Savings)
+	case "stores":
+		if spec.Zip == "" {
+			return invalidArgsError("zip is required for stores", `{"command":"stores","zip":"33101"}`)
+		}
+		stores, err := client.FetchStores(ctx, spec.Zip, 5)
+		if err != nil {
+			return upstreamError("fetching stores", err)
+		}
+		if len(stores) == 0 {
+			return notFoundError(fmt.Sprintf("no stores found near %s", spec.Zip), "Try a nearby ZIP code.")
+		}
+		out := make([]display.StoreJSON, 0, len(stores))
+		for _, store := range stores {
+			out = append(out, display.ToStoreJSON(store))
+		}
+		result.Stores = out
+	case "compare":
+		if spec.Zip == "" {
+			return invalidArgsError("zip is required for compare", `{"command":"compare","zip":"33101"}`)
+		}
+		count := spec.Count
+		if count == 0 {
+			count = 5
+		}
+		if count < 1 || count > 10 {
+			return invalidArgsError("count must be between 1 and 10")
+		}
+		results, _, err := compareStores(ctx, client, spec.Zip, count, opts)
+		if err != nil {
+			return err
+		}
+		result.Stores = results
+	default:
+		return invalidArgsError(
+			fmt.Sprintf("unknown batch command %q (use deals, stores, categories, or compare)", spec.Command),
+		)
+	}
+	return nil
+}
+
+// batchSavings resolves a spec's store (falling back to the configured
+// default location) and loads its weekly ad.
+func batchSavings(ctx context.Context, client *api.Client, spec batchSpec) (string, *api.SavingsResponse, error) {
+	storeNumber, zipCode := strings.TrimSpace(spec.Store), strings.TrimSpace(spec.Zip)
+	if storeNumber == "" && zipCode == "" {
+		storeNumber = strings.TrimSpace(activeConfig.DefaultStore)
+		zipCode = strings.TrimSpace(activeConfig.DefaultZip)
+	}
+	if storeNumber == "" {
+		if zipCode == "" {
+			return "", nil, invalidArgsError(
+				"store or zip is required",
+				`{"command":"deals","store":"1425"}`,
+				`{"command":"deals","zip":"33101"}`,
+			)
+		}
+		stores, err := client.FetchStores(ctx, zipCode, 1)
+		if err != nil {
+			return "", nil, upstreamError("finding stores", err)
+		}
+		if len(stores) == 0 {
+			return "", nil, notFoundError(fmt.Sprintf("no Publix stores found near %s", zipCode), "Try a nearby ZIP code.")
+		}
+		storeNumber = api.StoreNumber(stores[0].Key)
+	}
+
+	data, err := client.FetchSavings(ctx, storeNumber)
+	if err != nil {
+		return "", nil, upstreamError("fetching deals", err)
+	}
+	if len(data.Savings) == 0 {
+		return "", nil, notFoundError(fmt.Sprintf("no deals found for store #%s", storeNumber), "Try another store.")
+	}
+	return storeNumber, data, nil
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func newBatchTestClient(t *testing.T) (*api.Client, *atomic.Int32) {
+	t.Helper()
+	var savingsCalls atomic.Int32
+	savings := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		savingsCalls.Add(1)
+		json.NewEncoder(w).Encode(api.SavingsResponse{Savings: []api.SavingItem{
+			{ID: "1", Title: strPtr("Chicken"), Categories: []string{"meat"}},
+			{ID: "2", Title: strPtr("Nutella"), Categories: []string{"bogo"}},
+		}})
+	}))
+	t.Cleanup(savings.Close)
+	stores := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(api.StoreResponse{Stores: []api.Store{{Key: "01425", Name: "Peachers Mill"}}})
+	}))
+	t.Cleanup(stores.Close)
+	return api.NewClientWithBaseURLs(savings.URL, stores.URL, api.WithMemo()), &savingsCalls
+}
+
+func decodeBatchLines(t *testing.T, out string) []batchResult {
+	t.Helper()
+	var results []batchResult
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		var r batchResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r), scanner.Text())
+		results = append(results, r)
+	}
+	return results
+}
+
+func TestRunBatchQueries_StreamsResultsWithSharedClient(t *testing.T) {
+	client, calls := newBatchTestClient(t)
+	specs, err := parseBatchSpecs(strings.NewReader(`[
+		{"id":"a","command":"deals","zip":"33101","bogo":true},
+		{"id":"b","command":"categories","store":"1425"},
+		{"id":"c","command":"stores","zip":"33101"},
+		{"id":"d","command":"flyer"},
+		{"id":"e","store":"1425","colour":"red"}
+	]`))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	failed, firstErr := runBatchQueries(context.Background(), &buf, client, specs)
+
+	assert.Equal(t, 2, failed)
+	require.NotNil(t, firstErr)
+	assert.Equal(t, "INVALID_ARGS", firstErr.Code)
+
+	results := decodeBatchLines(t, buf.String())
+	require.Len(t, results, 5)
+
+	assert.True(t, results[0].OK)
+	assert.Equal(t, "1425", results[0].Store)
+	require.Len(t, results[0].Deals, 1)
+	assert.Equal(t, "Nutella", results[0].Deals[0].Title)
+
+	assert.Equal(t, 1, results[1].Categories["meat"])
+	assert.NotNil(t, results[2].Stores)
+
+	assert.False(t, results[3].OK)
+	assert.Contains(t, results[3].Error.Message, "unknown batch command")
+	assert.False(t, results[4].OK)
+	assert.Contains(t, results[4].Error.Message, "colour")
+
+	assert.Equal(t, int32(1), calls.Load(), "store 1425 is fetched once across specs")
+}
+
+func TestRunCLI_BatchRejectsNonArrayInput(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	batchCmd.SetIn(strings.NewReader(`{"command":"deals"}`))
+	t.Cleanup(func() { batchCmd.SetIn(nil) })
+
+	code := runCLI([]string{"batch"}, &stdout, &stderr)
+
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "JSON array")
+}
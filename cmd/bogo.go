@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+var bogoCmd = &cobra.Command{
+	Use:   "bogo",
+	Short: "List this week's BOGO deals by department",
+	Long: "List the weekly ad's buy-one-get-one deals, grouped by department with the departments " +
+		"holding the best deals first, and each department's deals ranked by deal score. The " +
+		"effective price is what one item costs when the second is free, read from the deal's " +
+		"price text; it is blank when the ad states no price. Shorthand for " +
+		"`pubcli --bogo --sort savings` with grouping; the other deal filter flags still apply.",
+	Example: `  pubcli bogo --zip 33101
+  pubcli bogo --store 1425 --department produce
+  pubcli bogo --store 1425 --json`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runBogo,
+}
+
+func init() {
+	rootCmd.AddCommand(bogoCmd)
+	registerDealFilterFlags(bogoCmd.Flags())
+}
+
+// bogoDeal is one BOGO deal with its per-item price.
+type bogoDeal struct {
+	display.DealJSON
+	// EffectivePrice is the price of one item with the second free; 0 when
+	// the ad states no price.
+	EffectivePrice float64 `json:"effectivePrice,omitempty"`
+}
+
+// bogoDepartment is one department's BOGO deals.
+type bogoDepartment struct {
+	Department string     `json:"department"`
+	Deals      []bogoDeal `json:"deals"`
+}
+
+// bogoJSON is the --json output of `bogo`.
+type bogoJSON struct {
+	Store       string           `json:"store"`
+	Deals       int              `json:"deals"`
+	Departments []bogoDepartment `json:"departments"`
+}
+
+func runBogo(cmd *cobra.Command, _ []string) error {
+	if err := validateSortMode(); err != nil {
+		return err
+	}
+	if err := validateMinPercentOff(); err != nil {
+		return err
+	}
+
+	client := newAPIClient()
+	storeNumber, err := resolveStore(cmd, client)
+	if err != nil {
+		return err
+	}
+	data, err := client.FetchSavings(cmd.Context(), storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	sortMode := flagSort
+	if sortMode == "" {
+		sortMode = "savings"
+	}
+	items := filter.Apply(data.Savings, filter.Options{
+		BOGO:           true,
+		Category:       flagCategory,
+		Department:     flagDepartment,
+		Query:          flagQuery,
+		Sort:           sortMode,
+		Limit:          flagLimit,
+		ExcludeExpired: !flagIncludeExpired,
+		MinPercentOff:  flagMinPercentOff,
+	})
+	if len(items) == 0 {
+		return notFoundError(
+			fmt.Sprintf("no BOGO deals match at store #%s", storeNumber),
+			"Relax filters like --category/--department/--query.",
+		)
+	}
+
+	result := buildBogo(storeNumber, items)
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "bogo", result)
+	}
+	printBogo(cmd.OutOrStdout(), result)
+	return nil
+}
+
+// buildBogo groups already sorted deals by department, keeping their order
+// within each department. Departments are ordered by their best deal score.
+func buildBogo(storeNumber string, items []api.SavingItem) bogoJSON {
+	result := bogoJSON{Store: storeNumber, Deals: len(items), Departments: []bogoDepartment{}}
+	index := map[string]int{}
+	best := map[string]float64{}
+	for _, item := range items {
+		dept := filter.CleanText(filter.Deref(item.Department))
+		if dept == "" {
+			dept = "Other"
+		}
+		i, ok := index[dept]
+		if !ok {
+			i = len(result.Departments)
+			index[dept] = i
+			result.Departments = append(result.Departments, bogoDepartment{Department: dept})
+		}
+		deal := bogoDeal{
+			DealJSON:       display.ToDealJSON(item),
+			EffectivePrice: filter.ParseSavings(item).EffectivePrice(),
+		}
+		best[dept] = max(best[dept], deal.Score)
+		result.Departments[i].Deals = append(result.Departments[i].Deals, deal)
+	}
+	sort.SliceStable(result.Departments, func(i, j int) bool {
+		a, b := result.Departments[i].Department, result.Departments[j].Department
+		if best[a] != best[b] {
+			return best[a] > best[b]
+		}
+		return a < b
+	})
+	return result
+}
+
+func printBogo(w io.Writer, result bogoJSON) {
+	if display.Accessible() {
+		fmt.Fprintf(w, "%d BOGO deals at store #%s.\n", result.Deals, result.Store)
+		for _, dept := range result.Departments {
+			fmt.Fprintf(w, "%s, %d deals.\n", dept.Department, len(dept.Deals))
+			for _, d := range dept.Deals {
+				line := d.Title
+				if d.EffectivePrice > 0 {
+					line += fmt.Sprintf(", %s each", display.FormatMoney(d.EffectivePrice))
+				}
+				fmt.Fprintf(w, "  %s.\n", line)
+			}
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "BOGO deals at store #%s (%d)\n", result.Store, result.Deals)
+	for _, dept := range result.Departments {
+		fmt.Fprintf(w, "\n%s\n", dept.Department)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  DEAL\tEACH\tSCORE")
+		for _, d := range dept.Deals {
+			each := "—"
+			if d.EffectivePrice > 0 {
+				each = display.FormatMoney(d.EffectivePrice)
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", d.Title, each, display.FormatNumber(d.Score, 1))
+		}
+		tw.Flush()
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestBuildBogo_GroupsByDepartmentWithEffectivePrice(t *testing.T) {
+	items := []api.SavingItem{
+		{Title: strPtr("Coffee"), Department: strPtr("Grocery"), Savings: strPtr("Buy 1 Get 1 FREE"), AdditionalDealInfo: strPtr("Save Up To $9.00"), Categories: []string{"bogo"}},
+		{Title: strPtr("Berries"), Department: strPtr("Produce"), Savings: strPtr("$4.00"), Categories: []string{"bogo"}},
+		{Title: strPtr("Crackers"), Department: strPtr("Grocery"), Categories: []string{"bogo"}},
+	}
+
+	result := buildBogo("1425", items)
+	assert.Equal(t, 3, result.Deals)
+	require.Len(t, result.Departments, 2)
+	assert.Equal(t, "Grocery", result.Departments[0].Department, "the department with the best deal comes first")
+	require.Len(t, result.Departments[0].Deals, 2)
+	assert.Equal(t, 4.5, result.Departments[0].Deals[0].EffectivePrice)
+	assert.Zero(t, result.Departments[0].Deals[1].EffectivePrice)
+	assert.Equal(t, 2.0, result.Departments[1].Deals[0].EffectivePrice)
+
+	var out bytes.Buffer
+	printBogo(&out, result)
+	assert.Contains(t, out.String(), "BOGO deals at store #1425 (3)\n\nGrocery\n")
+	assert.Contains(t, out.String(), "Coffee    $4.50")
+	assert.Contains(t, out.String(), "Crackers  —")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the weekly ad cache",
+	Long: "pubcli keeps each store's weekly ad in the cache directory (" + config.EnvCacheDir +
+		", or ~/.cache/pubcli on Linux) and reuses it for the rest of the ad week, so repeat " +
+		"runs do not download it again. --no-cache skips it for one run.",
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:         "clear",
+	Short:       "Delete every cached weekly ad",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runCacheClear,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+// adCacheDir returns the directory of the weekly ad cache, unless --no-cache
+// turns it off or the cache directory cannot be located.
+func adCacheDir() (string, bool) {
+	if flagNoCache {
+		return "", false
+	}
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(dir, api.AdCacheDir), true
+}
+
+func runCacheClear(cmd *cobra.Command, _ []string) error {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return configError(err)
+	}
+	removed, err := api.ClearDiskCache(filepath.Join(dir, api.AdCacheDir))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cached weekly ad(s).\n", removed)
+	return nil
+}
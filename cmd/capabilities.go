@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+// annotationNetwork marks commands that call the Publix API.
+const annotationNetwork = "pubcli/network"
+
+// flagEnumValues lists the accepted values of enum-like flags. Aliases are
+// accepted too but only canonical values are advertised.
+var flagEnumValues = map[string][]string{
+	"sort":       {"relevance", "savings", "ending"},
+	"group-by":   {"department", "category"},
+	"format":     {"text", "json", "markdown", "alfred"},
+	"store-type": storeTypeValues(),
+}
+
+// commandFlagEnumValues overrides flagEnumValues for commands whose flag of
+// the same name accepts different values.
+var commandFlagEnumValues = map[string]map[string][]string{
+	"status":   {"format": {"text", "waybar", "polybar"}},
+	"report":   {"format": {"text", "markdown", "json"}},
+	"links":    {"format": {"text", "markdown", "json"}},
+	"diff":     {"format": {"text", "markdown", "patch", "json"}},
+	"examples": {"format": {"text", "markdown", "json"}},
+}
+
+var outputFormats = []string{"text", "json"}
+
+type capabilitiesJSON struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	Name          string               `json:"name"`
+	Usage         string               `json:"usage"`
+	Commands      []capabilityCommand  `json:"commands"`
+	GlobalFlags   []capabilityFlag     `json:"globalFlags"`
+	Enums         map[string][]string  `json:"enums"`
+	ExitCodes     []capabilityExitCode `json:"exitCodes"`
+	Behaviors     []string             `json:"behaviors"`
+}
+
+type capabilityCommand struct {
+	Name            string           `json:"name"`
+	Path            string           `json:"path"`
+	Summary         string           `json:"summary"`
+	RequiresNetwork bool             `json:"requiresNetwork"`
+	Flags           []capabilityFlag `json:"flags"`
+	Examples        []string         `json:"examples,omitempty"`
+}
+
+type capabilityFlag struct {
+	Name      string   `json:"name"`
+	Shorthand string   `json:"shorthand,omitempty"`
+	Type      string   `json:"type"`
+	Default   string   `json:"default,omitempty"`
+	Usage     string   `json:"usage"`
+	Values    []string `json:"values,omitempty"`
+}
+
+type capabilityExitCode struct {
+	Code      int    `json:"code"`
+	ErrorCode string `json:"errorCode,omitempty"`
+	Meaning   string `json:"meaning"`
+}
+
+var capabilitiesCmd = &cobra.Command{
+	Use:   "capabilities",
+	Short: "Describe commands, flags, enums, and exit codes in one document",
+	Long: "Print a machine-readable description of the CLI surface so agents can " +
+		"load it once instead of probing --help output.",
+	Example: `  pubcli capabilities --json
+  pubcli capabilities`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runCapabilities,
+}
+
+func init() {
+	rootCmd.AddCommand(capabilitiesCmd)
+}
+
+func runCapabilities(cmd *cobra.Command, _ []string) error {
+	caps := describeCapabilities(rootCmd)
+	if flagJSON {
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(caps)
+	}
+	printCapabilities(cmd.OutOrStdout(), caps)
+	return nil
+}
+
+func describeCapabilities(root *cobra.Command) capabilitiesJSON {
+	commands := []capabilityCommand{describeCommand(root)}
+	children := append([]*cobra.Command(nil), root.Commands()...)
+	sort.Slice(children, func(i, j int) bool { return children[i].Name() < children[j].Name() })
+	for _, child := range children {
+		if child.Hidden {
+			continue
+		}
+		commands = append(commands, describeCommand(child))
+		for _, sub := range child.Commands() {
+			if !sub.Hidden {
+				commands = append(commands, describeCommand(sub))
+			}
+		}
+	}
+
+	return capabilitiesJSON{
+		SchemaVersion: display.SchemaVersion(),
+		Name:          root.Name(),
+		Usage:         "pubcli [command] [flags]",
+		Commands:      commands,
+		GlobalFlags:   describeFlags(root.PersistentFlags(), root.Name()),
+		Enums: map[string][]string{
+			"sort":          flagEnumValues["sort"],
+			"output":        outputFormats,
+			"schemaVersion": {"1", "2"},
+		},
+		ExitCodes: []capabilityExitCode{
+			{Code: ExitSuccess, Meaning: "success"},
+			{Code: ExitNotFound, ErrorCode: "NOT_FOUND", Meaning: "no stores or deals matched"},
+			{Code: ExitInvalidArgs, ErrorCode: "INVALID_ARGS", Meaning: "invalid arguments or configuration"},
+			{Code: ExitUpstream, ErrorCode: "UPSTREAM_ERROR", Meaning: "Publix API or network failure"},
+			{Code: ExitUpstream, ErrorCode: "MALFORMED_RESPONSE", Meaning: "Publix API answered with a body that is not the expected JSON"},
+			{Code: ExitUpstream, ErrorCode: "CIRCUIT_OPEN", Meaning: "Publix API failed repeatedly; calls are paused for a cooldown"},
+			{Code: ExitInternal, ErrorCode: "INTERNAL_ERROR", Meaning: "unexpected internal failure"},
+			{Code: ExitInternal, ErrorCode: "CORRUPT_DATA", Meaning: "a saved ad history snapshot failed its integrity check"},
+		},
+		Behaviors: []string{
+			"JSON output is enabled automatically when stdout is not a TTY.",
+			"Minor syntax mistakes are auto-corrected and reported on stderr as `note:` lines.",
+			"In JSON mode errors are printed to stderr as {\"error\":{...}}.",
+			"--strict turns off auto-correction, automatic JSON, and did-you-mean suggestions; its errors are always JSON.",
+		},
+	}
+}
+
+func describeCommand(cmd *cobra.Command) capabilityCommand {
+	out := capabilityCommand{
+		Name:            cmd.Name(),
+		Path:            cmd.CommandPath(),
+		Summary:         cmd.Short,
+		RequiresNetwork: cmd.Annotations[annotationNetwork] == "true",
+		Flags:           describeFlags(cmd.LocalNonPersistentFlags(), cmd.Name()),
+	}
+	if cmd.HasParent() {
+		// Persistent flags below the root (e.g. daemon --socket) are specific
+		// to that command tree rather than global.
+		out.Flags = append(out.Flags, describeFlags(cmd.PersistentFlags(), cmd.Name())...)
+	}
+	for _, line := range strings.Split(cmd.Example, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out.Examples = append(out.Examples, line)
+		}
+	}
+	return out
+}
+
+func describeFlags(fs *pflag.FlagSet, command string) []capabilityFlag {
+	flags := []capabilityFlag{}
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" {
+			return
+		}
+		flag := capabilityFlag{
+			Name:      f.Name,
+			Shorthand: f.Shorthand,
+			Type:      f.Value.Type(),
+			Usage:     f.Usage,
+			Values:    enumValues(command, f.Name),
+		}
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" && f.DefValue != "[]" {
+			flag.Default = f.DefValue
+		}
+		flags = append(flags, flag)
+	})
+	return flags
+}
+
+func enumValues(command, flag string) []string {
+	if values, ok := commandFlagEnumValues[command][flag]; ok {
+		return values
+	}
+	return flagEnumValues[flag]
+}
+
+func printCapabilities(w io.Writer, caps capabilitiesJSON) {
+	fmt.Fprintf(w, "%s — %s\n\ncommands:\n", caps.Name, caps.Usage)
+	for _, c := range caps.Commands {
+		network := ""
+		if c.RequiresNetwork {
+			network = " [network]"
+		}
+		fmt.Fprintf(w, "  %-14s %s%s\n", c.Name, c.Summary, network)
+	}
+
+	fmt.Fprintln(w, "\nenums:")
+	keys := mapKeys(caps.Enums)
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s: %s\n", key, strings.Join(caps.Enums[key], ", "))
+	}
+
+	fmt.Fprintln(w, "\nexit codes:")
+	for _, code := range caps.ExitCodes {
+		label := code.ErrorCode
+		if label == "" {
+			label = "OK"
+		}
+		fmt.Fprintf(w, "  %d %-15s %s\n", code.Code, label, code.Meaning)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/tayloree/publix-deals/internal/api"
 	"github.com/tayloree/publix-deals/internal/display"
 	"github.com/tayloree/publix-deals/internal/filter"
 )
@@ -13,16 +12,19 @@ var categoriesCmd = &cobra.Command{
 	Use:   "categories",
 	Short: "List available categories for the current week",
 	Example: `  pubcli categories --store 1425
+  pubcli categories --store 1425 --chart
   pubcli categories -z 33101 --json`,
-	RunE: runCategories,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runCategories,
 }
 
 func init() {
 	rootCmd.AddCommand(categoriesCmd)
+	registerChartFlag(categoriesCmd.Flags())
 }
 
 func runCategories(cmd *cobra.Command, _ []string) error {
-	client := api.NewClient()
+	client := newAPIClient()
 
 	storeNumber, err := resolveStore(cmd, client)
 	if err != nil {
@@ -46,6 +48,11 @@ func runCategories(cmd *cobra.Command, _ []string) error {
 	if flagJSON {
 		return display.PrintCategoriesJSON(cmd.OutOrStdout(), cats)
 	}
-	display.PrintCategories(cmd.OutOrStdout(), cats, storeNumber)
+	if flagChart {
+		display.PrintCategoryChart(cmd.OutOrStdout(), cats, storeNumber)
+	} else {
+		display.PrintCategories(cmd.OutOrStdout(), cats, storeNumber)
+	}
+	printDefaultFiltersNote(cmd.ErrOrStderr())
 	return nil
 }
@@ -11,24 +11,132 @@ type flagSpec struct {
 }
 
 var knownFlags = map[string]flagSpec{
-	"store":      {name: "store", requiresValue: true},
-	"zip":        {name: "zip", requiresValue: true},
-	"json":       {name: "json", requiresValue: false},
-	"category":   {name: "category", requiresValue: true},
-	"department": {name: "department", requiresValue: true},
-	"bogo":       {name: "bogo", requiresValue: false},
-	"query":      {name: "query", requiresValue: true},
-	"sort":       {name: "sort", requiresValue: true},
-	"limit":      {name: "limit", requiresValue: true},
-	"count":      {name: "count", requiresValue: true},
-	"help":       {name: "help", requiresValue: false},
+	"store":              {name: "store", requiresValue: true},
+	"zip":                {name: "zip", requiresValue: true},
+	"json":               {name: "json", requiresValue: false},
+	"category":           {name: "category", requiresValue: true},
+	"department":         {name: "department", requiresValue: true},
+	"bogo":               {name: "bogo", requiresValue: false},
+	"include-expired":    {name: "include-expired", requiresValue: false},
+	"all-stores":         {name: "all-stores", requiresValue: false},
+	"store-type":         {name: "store-type", requiresValue: true},
+	"header":             {name: "header", requiresValue: true},
+	"no-default-filters": {name: "no-default-filters", requiresValue: false},
+	"profile":            {name: "profile", requiresValue: true},
+	"no-cache":           {name: "no-cache", requiresValue: false},
+	"quiet":              {name: "quiet", requiresValue: false},
+	"query":              {name: "query", requiresValue: true},
+	"sort":               {name: "sort", requiresValue: true},
+	"limit":              {name: "limit", requiresValue: true},
+	"min-percent-off":    {name: "min-percent-off", requiresValue: true},
+	"group-by":           {name: "group-by", requiresValue: true},
+	"limit-per-group":    {name: "limit-per-group", requiresValue: true},
+	"offset":             {name: "offset", requiresValue: true},
+	"new-only":           {name: "new-only", requiresValue: false},
+	"count":              {name: "count", requiresValue: true},
+	"max-items":          {name: "max-items", requiresValue: true},
+	"max-bytes":          {name: "max-bytes", requiresValue: true},
+	"script":             {name: "script", requiresValue: true},
+	"script-size":        {name: "script-size", requiresValue: true},
+	"schema-version":     {name: "schema-version", requiresValue: true},
+	"file":               {name: "file", requiresValue: true},
+	"socket":             {name: "socket", requiresValue: true},
+	"refresh":            {name: "refresh", requiresValue: true},
+	"addr":               {name: "addr", requiresValue: true},
+	"max-age":            {name: "max-age", requiresValue: true},
+	"port":               {name: "port", requiresValue: true},
+	"format":             {name: "format", requiresValue: true},
+	"new-for":            {name: "new-for", requiresValue: true},
+	"dry-run":            {name: "dry-run", requiresValue: false},
+	"for-store":          {name: "for-store", requiresValue: true},
+	"max-price":          {name: "max-price", requiresValue: true},
+	"renotify":           {name: "renotify", requiresValue: false},
+	"below":              {name: "below", requiresValue: true},
+	"wallet":             {name: "wallet", requiresValue: false},
+	"clear":              {name: "clear", requiresValue: false},
+	"qty":                {name: "qty", requiresValue: true},
+	"note":               {name: "note", requiresValue: true},
+	"output":             {name: "output", requiresValue: true},
+	"out":                {name: "out", requiresValue: true},
+	"base-url":           {name: "base-url", requiresValue: true},
+	"download-images":    {name: "download-images", requiresValue: true},
+	"image-max-bytes":    {name: "image-max-bytes", requiresValue: true},
+	"image-concurrency":  {name: "image-concurrency", requiresValue: true},
+	"openai":             {name: "openai", requiresValue: false},
+	"server-url":         {name: "server-url", requiresValue: true},
+	"weekly":             {name: "weekly", requiresValue: false},
+	"ending-within":      {name: "ending-within", requiresValue: true},
+	"hourly":             {name: "hourly", requiresValue: false},
+	"daily":              {name: "daily", requiresValue: false},
+	"day":                {name: "day", requiresValue: true},
+	"at":                 {name: "at", requiresValue: true},
+	"name":               {name: "name", requiresValue: true},
+	"weeks":              {name: "weeks", requiresValue: true},
+	"chart":              {name: "chart", requiresValue: false},
+	"n":                  {name: "n", requiresValue: true},
+	"by":                 {name: "by", requiresValue: true},
+	"strict":             {name: "strict", requiresValue: false},
+	"har":                {name: "har", requiresValue: true},
+	"locale":             {name: "locale", requiresValue: true},
+	"lang":               {name: "lang", requiresValue: true},
+	"color":              {name: "color", requiresValue: true},
+	"deals":              {name: "deals", requiresValue: true},
+	"store-count":        {name: "store-count", requiresValue: true},
+	"seed":               {name: "seed", requiresValue: true},
+	"week-start":         {name: "week-start", requiresValue: true},
+	"year":               {name: "year", requiresValue: true},
+	"to":                 {name: "to", requiresValue: true},
+	"keep-weeks":         {name: "keep-weeks", requiresValue: true},
+	"repair":             {name: "repair", requiresValue: false},
+	"from":               {name: "from", requiresValue: true},
+	"interval":           {name: "interval", requiresValue: true},
+	"reindex":            {name: "reindex", requiresValue: false},
+	"help":               {name: "help", requiresValue: false},
 }
 
 var knownCommands = []string{
+	"ad",
+	"examples",
+	"fav",
 	"categories",
 	"stores",
 	"compare",
 	"tui",
+	"capabilities",
+	"serve",
+	"batch",
+	"shell",
+	"daemon",
+	"status",
+	"summary",
+	"alert",
+	"list",
+	"sync",
+	"publish",
+	"manifest",
+	"report",
+	"schedule",
+	"trends",
+	"top",
+	"insights",
+	"ping",
+	"bogo",
+	"env",
+	"config",
+	"secret",
+	"history",
+	"rerun",
+	"last",
+	"cookies",
+	"fixtures",
+	"track",
+	"savings",
+	"data",
+	"cache",
+	"snapshot",
+	"diff",
+	"watch",
+	"search",
 	"completion",
 	"help",
 }
@@ -162,7 +270,7 @@ func bareFlagRewriteAllowed(command string) bool {
 	// Some commands (for example `stores` and `categories`) are flag-only, so
 	// rewriting bare tokens like `zip` -> `--zip` is helpful there.
 	switch command {
-	case "stores", "categories", "compare", "tui":
+	case "stores", "categories", "compare", "tui", "shell":
 		return true
 	default:
 		return false
@@ -267,6 +375,11 @@ func closestMatch(target string, candidates []string, maxDistance int) (string,
 
 	for _, candidate := range candidates {
 		d := levenshtein(target, candidate)
+		// Rewriting every character of a short name (e.g. `5` to `at`)
+		// would match unrelated words.
+		if d >= len(candidate) {
+			continue
+		}
 		if d < bestDist {
 			bestDist = d
 			best = candidate
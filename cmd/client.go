@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/daemon"
+)
+
+// newAPIClient returns a client that reads through the running daemon when
+// there is one, and talks to the Publix API directly otherwise. With --har
+// it always talks to the API directly, so the capture holds the real traffic,
+// and with --store-type, added headers, or the cookie jar too, since the
+// daemon sends none of them upstream.
+// Direct clients retry transient failures and stop calling an API that
+// keeps failing; see api.WithRetries and api.Breaker.
+// They read this week's ads from the disk cache unless --no-cache or --har
+// is given. Every client applies the config file's default filters and
+// profile.
+func newAPIClient(opts ...api.Option) *api.Client {
+	if fn := defaultSavingsFilter(); fn != nil {
+		opts = append(opts[:len(opts):len(opts)], api.WithSavingsFilter(fn))
+	}
+	// The daemon has its own breaker and retries; only the caller's options
+	// apply to it.
+	daemonOpts := opts
+	opts = append(opts[:len(opts):len(opts)],
+		api.WithBreaker(api.NewBreaker()),
+		api.WithRetries(api.DefaultRetries, api.DefaultRetryBackoff))
+	if storeTypeCodes != "" {
+		opts = append(opts, api.WithStoreTypes(storeTypeCodes))
+	}
+	if requestHeaders != nil {
+		opts = append(opts, api.WithHeaders(requestHeaders))
+	}
+	if cookieJar != nil {
+		opts = append(opts, api.WithCookieJar(cookieJar))
+	}
+	if harRecorder != nil {
+		return api.NewClient(append(opts, api.WithHAR(harRecorder))...)
+	}
+	if dir, ok := adCacheDir(); ok {
+		opts = append(opts, api.WithDiskCache(dir))
+	}
+	if storeTypeCodes == "" && requestHeaders == nil && cookieJar == nil {
+		if client, ok := daemon.Client(daemon.SocketPath(), daemonOpts...); ok {
+			return client
+		}
+	}
+	return api.NewClient(opts...)
+}
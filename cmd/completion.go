@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// flagValueHelp describes the values of enum flags for shell completion. The
+// values offered for each command come from enumValues.
+var flagValueHelp = map[string]map[string]string{
+	"sort": {
+		"relevance": "Best-scoring deals first",
+		"savings":   "Biggest savings first",
+		"ending":    "Deals ending soonest first",
+	},
+	"group-by": {
+		"department": "A section per department",
+		"category":   "A section per category, ignoring bogo",
+	},
+	"format": {
+		"text":     "Terminal output (default)",
+		"json":     "JSON, same as --json",
+		"alfred":   "Alfred/Raycast Script Filter JSON",
+		"markdown": "Markdown for notes and chat",
+		"patch":    "JSON Patch-style operations",
+		"waybar":   "Waybar custom module JSON",
+		"polybar":  "Polybar label text",
+	},
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion",
+	Short: "Generate or install shell completion scripts",
+	Long: "Print a completion script for bash, zsh, fish, or powershell, or install one " +
+		"where the shell loads it automatically with `pubcli completion install`. " +
+		"Completions include flag values such as --sort, --format, and --store-type, with descriptions.",
+	Example: `  pubcli completion install
+  pubcli completion install zsh
+  source <(pubcli completion bash)
+  pubcli completion fish > ~/.config/fish/completions/pubcli.fish`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var completionInstallCmd = &cobra.Command{
+	Use:   "install [bash|zsh|fish]",
+	Short: "Write the completion script to your shell's completion directory",
+	Long: "Write the completion script where the shell finds it: " +
+		"$XDG_DATA_HOME/bash-completion/completions for bash, " +
+		"$XDG_DATA_HOME/zsh/site-functions for zsh, and $XDG_CONFIG_HOME/fish/completions for fish. " +
+		"The shell defaults to the one in $SHELL. Re-run it after upgrading pubcli.",
+	Example: `  pubcli completion install
+  pubcli completion install fish`,
+	Args:        cobra.MaximumNArgs(1),
+	ValidArgs:   []string{"bash\tBash 4.1+ with bash-completion", "zsh\tZsh", "fish\tFish"},
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runCompletionInstall,
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	completionCmd.AddCommand(completionInstallCmd)
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		completionCmd.AddCommand(&cobra.Command{
+			Use:                   shell,
+			Short:                 "Print the completion script for " + shell,
+			Args:                  cobra.NoArgs,
+			DisableFlagsInUseLine: true,
+			Annotations:           map[string]string{annotationNetwork: "false"},
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				return writeCompletionScript(cmd.OutOrStdout(), cmd.Name())
+			},
+		})
+	}
+}
+
+func writeCompletionScript(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(w, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(w)
+	case "fish":
+		return rootCmd.GenFishCompletion(w, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(w)
+	default:
+		return invalidArgsError(
+			fmt.Sprintf("unsupported shell %q (use bash, zsh, fish, or powershell)", shell),
+			"pubcli completion zsh",
+		)
+	}
+}
+
+var registerValueCompletionsOnce sync.Once
+
+// registerValueCompletions adds completion of flagValueHelp values to every
+// command with one of those flags. It runs after all commands are added.
+func registerValueCompletions(root *cobra.Command) {
+	registerValueCompletionsOnce.Do(func() {
+		walkCommands(root, func(cmd *cobra.Command) {
+			for name := range flagValueHelp {
+				if cmd.Flags().Lookup(name) == nil {
+					continue
+				}
+				_ = cmd.RegisterFlagCompletionFunc(name, completeFlagValues(name))
+			}
+		})
+	})
+}
+
+func walkCommands(cmd *cobra.Command, fn func(*cobra.Command)) {
+	fn(cmd)
+	for _, child := range cmd.Commands() {
+		walkCommands(child, fn)
+	}
+}
+
+func completeFlagValues(flag string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		values := enumValues(cmd.Name(), flag)
+		out := make([]string, 0, len(values))
+		for _, v := range values {
+			if help := flagValueHelp[flag][v]; help != "" {
+				v += "\t" + help
+			}
+			out = append(out, v)
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func runCompletionInstall(cmd *cobra.Command, args []string) error {
+	shell := filepath.Base(os.Getenv("SHELL"))
+	if len(args) == 1 {
+		shell = args[0]
+	}
+	path, hint, err := completionInstallPath(shell)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return configError(fmt.Errorf("creating completion directory: %w", err))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return configError(fmt.Errorf("writing completion script: %w", err))
+	}
+	if err := writeCompletionScript(f, shell); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return configError(fmt.Errorf("writing completion script: %w", err))
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Installed %s completion to %s\n", shell, path)
+	fmt.Fprintln(cmd.OutOrStdout(), hint)
+	return nil
+}
+
+// completionInstallPath returns where a shell loads completion scripts from
+// and what the user must do before new shells pick it up.
+func completionInstallPath(shell string) (path, hint string, err error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", configError(fmt.Errorf("locating home directory: %w", err))
+	}
+	dataHome := filepath.Join(home, ".local", "share")
+	if base := strings.TrimSpace(os.Getenv("XDG_DATA_HOME")); base != "" {
+		dataHome = base
+	}
+	configHome := filepath.Join(home, ".config")
+	if base := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); base != "" {
+		configHome = base
+	}
+
+	switch shell {
+	case "bash":
+		return filepath.Join(dataHome, "bash-completion", "completions", "pubcli"),
+			"Open a new shell to use it (needs the bash-completion package).", nil
+	case "zsh":
+		dir := filepath.Join(dataHome, "zsh", "site-functions")
+		return filepath.Join(dir, "_pubcli"),
+			fmt.Sprintf("If %s is not on your fpath, add `fpath=(%s $fpath)` before `compinit` in ~/.zshrc, then open a new shell.", dir, dir), nil
+	case "fish":
+		return filepath.Join(configHome, "fish", "completions", "pubcli.fish"),
+			"Open a new shell to use it.", nil
+	case "powershell", "pwsh":
+		return "", "", invalidArgsError(
+			"powershell has no completion directory; load the script from your profile instead",
+			"pubcli completion powershell | Out-String | Invoke-Expression",
+		)
+	default:
+		return "", "", invalidArgsError(
+			fmt.Sprintf("cannot install completion for shell %q (use bash, zsh, or fish)", shell),
+			"pubcli completion install zsh",
+		)
+	}
+}
@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/profile"
+	"github.com/tayloree/publix-deals/internal/secrets"
+	"github.com/tayloree/publix-deals/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// configEditFile keeps config edits that failed validation.
+const configEditFile = "config.edit.yaml"
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change the config file",
+	Long: "Read and change config.yaml without editing the YAML by hand. Settings are named by " +
+		"their dotted path, e.g. default_store, profile.diets, or alerts.telegram.chat_id. " +
+		"Every change is checked before it is written, so a typo cannot break later commands, " +
+		"and comments in the file are kept. `pubcli env` shows where the file is.",
+	Example: `  pubcli config get default_store
+  pubcli config set default_store 1425
+  pubcli config set exclude.categories alcohol tobacco
+  pubcli config unset profile.hide
+  pubcli config validate`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [KEY]",
+	Short: "Print a setting, or the whole config file",
+	Long: "Print the value of a setting. Sections and lists print as YAML. Without a key, print " +
+		"the whole file.",
+	Example: `  pubcli config get default_store
+  pubcli config get profile
+  pubcli config get --json`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE...",
+	Short: "Change a setting",
+	Long: "Set a setting to a value, checked against the setting's type: true or false for " +
+		"switches, numbers for numbers. List settings take every value given, replacing the " +
+		"list. Headers are set as headers.NAME. Alert rules are lists of sections; change them " +
+		"with `pubcli config edit`.",
+	Example: `  pubcli config set default_zip 33101
+  pubcli config set accessible true
+  pubcli config set profile.allergens peanut shellfish
+  pubcli config set headers.User-Agent "pubcli (me@example.com)"`,
+	Args:        cobra.MinimumNArgs(2),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:         "unset KEY",
+	Short:       "Remove a setting, restoring its default",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runConfigUnset,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the config file in $EDITOR",
+	Long: "Open the config file in $VISUAL or $EDITOR (vi, or notepad on Windows, when neither is " +
+		"set). The file is checked when the editor exits. Invalid edits leave the config file " +
+		"unchanged and are kept in " + configEditFile + ", which the next `pubcli config edit` " +
+		"reopens.",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runConfigEdit,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file",
+	Long: "Check every setting in the config file, including ones only some commands read, such " +
+		"as alert destinations and sync. Settings pubcli does not know are reported, since they " +
+		"are usually typos. Exits with status 2 when the file is invalid.",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runConfigValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configEditCmd, configValidateCmd)
+}
+
+// managesConfigFile reports whether args run `pubcli config`, which must
+// work with an invalid config file so it can report and fix it.
+func managesConfigFile(args []string) bool {
+	return firstCommand(args) == "config"
+}
+
+// checkConfig checks the settings every command reads, so a bad value is
+// reported before any command runs.
+func checkConfig(cfg *config.Config) error {
+	if cfg.SchemaVersion != 0 && !display.ValidSchemaVersion(cfg.SchemaVersion) {
+		return fmt.Errorf("schema_version %d is not supported (use 1 or 2)", cfg.SchemaVersion)
+	}
+	if cfg.Locale != "" {
+		if _, ok := display.CanonicalLocale(cfg.Locale); !ok {
+			return fmt.Errorf("locale %q is not supported (use one of %s)", cfg.Locale, strings.Join(display.Locales(), ", "))
+		}
+	}
+	if cfg.Lang != "" {
+		if _, ok := display.CanonicalLanguage(cfg.Lang); !ok {
+			return fmt.Errorf("lang %q is not supported (use one of %s)", cfg.Lang, strings.Join(display.Languages(), ", "))
+		}
+	}
+	for name, value := range cfg.Headers {
+		if _, _, err := api.ParseHeader(name + ": " + value); err != nil {
+			return fmt.Errorf("headers: %w", err)
+		}
+	}
+	p := cfg.Profile
+	if _, err := profile.New(profile.Profile{Allergens: p.Allergens, Dislikes: p.Dislikes, Diets: p.Diets}); err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+	return nil
+}
+
+// validateConfig checks config YAML as `pubcli config validate` does: every
+// setting must be known and valid, including the ones only some commands
+// read.
+func validateConfig(data []byte) error {
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return err
+	}
+	if err := checkConfig(cfg); err != nil {
+		return err
+	}
+	for i, r := range cfg.Alerts.Rules {
+		if err := configAlertRule(r).Validate(); err != nil {
+			return fmt.Errorf("alerts.rules[%d]: %w", i, err)
+		}
+	}
+	if tg := cfg.Alerts.Telegram; tg != nil {
+		if _, err := telegramNotifier(tg); err != nil {
+			return err
+		}
+	}
+	if cfg.Sync != nil {
+		if _, err := syncProvider(cfg.Sync); err != nil {
+			return err
+		}
+	}
+	if _, err := secrets.Open(cfg.Secrets.Backend, "", ""); err != nil {
+		return fmt.Errorf("secrets.backend: %w", err)
+	}
+	if err := storage.ValidateBackend(cfg.Storage.Backend); err != nil {
+		return fmt.Errorf("storage.backend: %w", err)
+	}
+	return nil
+}
+
+// readConfigDocument reads the config file for changing it.
+func readConfigDocument() (*config.Document, string, error) {
+	path, err := config.Path()
+	if err != nil {
+		return nil, "", configError(err)
+	}
+	doc, err := config.ReadDocument(path)
+	if err != nil {
+		return nil, "", configError(err)
+	}
+	return doc, path, nil
+}
+
+// configKeyError reports a key that names no setting, suggesting the
+// closest one.
+func configKeyError(err error) error {
+	var unknown *config.UnknownKeyError
+	if !errors.As(err, &unknown) {
+		return invalidArgsError(err.Error(), "pubcli config edit")
+	}
+	suggestions := []string{"pubcli config get"}
+	if match, ok := closestMatch(unknown.Key, config.Keys(), 3); ok {
+		suggestions = []string{fmt.Sprintf("Did you mean %s?", match)}
+	}
+	return invalidArgsError(err.Error(), suggestions...)
+}
+
+// saveConfigDocument checks the changed document and writes it.
+func saveConfigDocument(doc *config.Document, path string) error {
+	data, err := doc.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := validateConfig(data); err != nil {
+		return invalidArgsError(
+			fmt.Sprintf("the change would make the config invalid, so it was not saved: %v", err),
+			"pubcli config get",
+		)
+	}
+	if err := config.WriteFile(path, data); err != nil {
+		return configError(err)
+	}
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	doc, _, err := readConfigDocument()
+	if err != nil {
+		return err
+	}
+	key := ""
+	if len(args) > 0 {
+		key = args[0]
+	}
+	node, err := doc.Get(key)
+	if err != nil {
+		return configKeyError(err)
+	}
+	if node == nil {
+		return notFoundError(fmt.Sprintf("%s is not set", key), fmt.Sprintf("pubcli config set %s VALUE", key))
+	}
+
+	out := cmd.OutOrStdout()
+	if flagJSON {
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return configError(err)
+		}
+		if value == nil && key == "" {
+			value = map[string]any{}
+		}
+		return display.PrintVersionedJSON(out, "config", value)
+	}
+	if node.Kind == yaml.ScalarNode {
+		fmt.Fprintln(out, node.Value)
+		return nil
+	}
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	if node.Kind == yaml.MappingNode && len(node.Content) == 0 {
+		return nil
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	doc, path, err := readConfigDocument()
+	if err != nil {
+		return err
+	}
+	if err := doc.Set(args[0], args[1:]); err != nil {
+		return configKeyError(err)
+	}
+	if err := saveConfigDocument(doc, path); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Set %s.\n", args[0])
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	doc, path, err := readConfigDocument()
+	if err != nil {
+		return err
+	}
+	removed, err := doc.Unset(args[0])
+	if err != nil {
+		return configKeyError(err)
+	}
+	if !removed {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s was not set.\n", args[0])
+		return nil
+	}
+	if err := saveConfigDocument(doc, path); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Unset %s.\n", args[0])
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command, _ []string) error {
+	path, err := config.Path()
+	if err != nil {
+		return configError(err)
+	}
+	editPath := filepath.Join(filepath.Dir(path), configEditFile)
+	if _, err := os.Stat(editPath); err == nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Reopening your unsaved edits from %s.\n", editPath)
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return configError(err)
+		}
+		if err := config.WriteFile(editPath, data); err != nil {
+			return configError(err)
+		}
+	}
+
+	if err := runEditor(cmd, editPath); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(editPath)
+	if err != nil {
+		return configError(err)
+	}
+	if err := validateConfig(data); err != nil {
+		return invalidArgsError(
+			fmt.Sprintf("the edited config is invalid, so it was not saved: %v", err),
+			fmt.Sprintf("Your edits are kept in %s; run `pubcli config edit` again to fix them.", editPath),
+		)
+	}
+	if err := os.Rename(editPath, path); err != nil {
+		return configError(err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Saved %s.\n", path)
+	return nil
+}
+
+func runConfigValidate(cmd *cobra.Command, _ []string) error {
+	path, err := config.Path()
+	if err != nil {
+		return configError(err)
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(cmd.OutOrStdout(), "No config file at %s; the built-in defaults apply.\n", path)
+		return nil
+	}
+	if err != nil {
+		return configError(err)
+	}
+	if err := validateConfig(data); err != nil {
+		return configError(err)
+	}
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return configError(err)
+	}
+	if _, err := checkSecretRefs(cfg); err != nil {
+		return configError(err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s is valid.\n", path)
+	return nil
+}
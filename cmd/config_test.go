@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/config"
+)
+
+func TestRunCLI_ConfigSetGetUnset(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	t.Cleanup(resetCLIState)
+
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := runCLI(args, &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, stdout, stderr := run("config", "set", "default_store", "1425")
+	require.Equal(t, ExitSuccess, code, stderr)
+	assert.Contains(t, stdout, "Set default_store.")
+	code, _, stderr = run("config", "set", "exclude.categories", "alcohol", "tobacco")
+	require.Equal(t, ExitSuccess, code, stderr)
+
+	code, stdout, _ = run("config", "get", "default_store", "--json=false")
+	require.Equal(t, ExitSuccess, code)
+	assert.Equal(t, "1425\n", stdout)
+	code, stdout, _ = run("config", "get", "exclude", "--json=false")
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout, "categories: [alcohol, tobacco]")
+	code, stdout, _ = run("config", "get", "--json")
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout, `"default_store":"1425"`)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "1425", cfg.DefaultStore)
+	assert.Equal(t, []string{"alcohol", "tobacco"}, cfg.Exclude.Categories)
+
+	code, stdout, _ = run("config", "unset", "default_store")
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout, "Unset default_store.")
+	code, _, stderr = run("config", "get", "default_store")
+	assert.Equal(t, ExitNotFound, code)
+	assert.Contains(t, stderr, "default_store is not set")
+}
+
+func TestRunCLI_ConfigSetRejectsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"config", "set", "default_stor", "1425"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "Did you mean default_store?")
+
+	stderr.Reset()
+	code = runCLI([]string{"config", "set", "locale", "xx-XX"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "so it was not saved")
+	assert.Contains(t, stderr.String(), "INVALID_ARGS")
+
+	stderr.Reset()
+	code = runCLI([]string{"config", "set", "profile.diets", "keto"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "unknown diet")
+	assert.NoFileExists(t, filepath.Join(dir, "config.yaml"))
+}
+
+func TestRunCLI_ConfigFixesInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	t.Cleanup(resetCLIState)
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("locale: xx-XX\n"), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"categories", "--store", "1425"}, &stdout, &stderr)
+	require.Equal(t, ExitInvalidArgs, code, "other commands refuse an invalid config")
+
+	stderr.Reset()
+	code = runCLI([]string{"config", "validate"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), `locale \"xx-XX\" is not supported`)
+
+	stdout.Reset()
+	stderr.Reset()
+	code = runCLI([]string{"config", "set", "locale", "en-GB"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	code = runCLI([]string{"config", "validate"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "is valid.")
+}
+
+func TestRunCLI_ConfigEdit(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	t.Cleanup(resetCLIState)
+	original := runEditor
+	t.Cleanup(func() { runEditor = original })
+
+	runEditor = func(_ *cobra.Command, path string) error {
+		return os.WriteFile(path, []byte("default_stor: \"1425\"\n"), 0o600)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	editFile := filepath.Join(dir, configEditFile)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"config", "edit"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "field default_stor not found")
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, editFile, "invalid edits are kept")
+
+	runEditor = func(_ *cobra.Command, path string) error {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "default_stor:", "the next edit reopens the kept edits")
+		return os.WriteFile(path, []byte("default_store: \"1425\"\n"), 0o600)
+	}
+	stdout.Reset()
+	stderr.Reset()
+	code = runCLI([]string{"config", "edit"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.NoFileExists(t, editFile)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "1425", cfg.DefaultStore)
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/cookies"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+var cookiesCmd = &cobra.Command{
+	Use:   "cookies",
+	Short: "Manage the persistent Publix session cookie jar",
+	Long: "With cookie_jar: true in the config file, pubcli keeps cookies from the Publix API in " +
+		"cookies.json in the data directory and sends them on later runs, so features that need a " +
+		"Publix session keep working between invocations. Import a signed-in browser session from " +
+		"a cookies.txt export to start one.",
+	Example: `  pubcli cookies import publix-cookies.txt
+  pubcli cookies list
+  pubcli cookies clear`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var cookiesListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "Show stored cookies without their values",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runCookiesList,
+}
+
+var cookiesImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Add cookies from a Netscape cookies.txt file",
+	Long: "Add cookies from a cookies.txt file, as written by browser export extensions and " +
+		"curl's --cookie-jar. Use - to read standard input. Imported cookies replace stored " +
+		"ones with the same domain, path, and name.",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runCookiesImport,
+}
+
+var cookiesClearCmd = &cobra.Command{
+	Use:         "clear",
+	Short:       "Delete every stored cookie",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runCookiesClear,
+}
+
+func init() {
+	rootCmd.AddCommand(cookiesCmd)
+	cookiesCmd.AddCommand(cookiesListCmd, cookiesImportCmd, cookiesClearCmd)
+}
+
+// cookieJSON is a stored cookie in `cookies list --json`. Values are
+// session credentials and never printed.
+type cookieJSON struct {
+	Domain   string     `json:"domain"`
+	Path     string     `json:"path"`
+	Name     string     `json:"name"`
+	Expires  *time.Time `json:"expires"`
+	Secure   bool       `json:"secure"`
+	HttpOnly bool       `json:"httpOnly"`
+}
+
+func openCookieJar() (*cookies.Jar, error) {
+	path, err := config.DataPath(cookies.FileName)
+	if err != nil {
+		return nil, configError(err)
+	}
+	jar, err := cookies.Open(path)
+	if err != nil {
+		return nil, configError(err)
+	}
+	return jar, nil
+}
+
+// saveCookieJar writes back cookies the command received.
+func saveCookieJar() error {
+	if cookieJar == nil {
+		return nil
+	}
+	if err := cookieJar.Save(); err != nil {
+		return configError(err)
+	}
+	return nil
+}
+
+func runCookiesList(cmd *cobra.Command, _ []string) error {
+	jar, err := openCookieJar()
+	if err != nil {
+		return err
+	}
+	out := make([]cookieJSON, 0)
+	for _, c := range jar.List() {
+		entry := cookieJSON{Domain: c.Domain, Path: c.Path, Name: c.Name, Secure: c.Secure, HttpOnly: c.HttpOnly}
+		if !c.Expires.IsZero() {
+			expires := c.Expires.UTC()
+			entry.Expires = &expires
+		}
+		out = append(out, entry)
+	}
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "cookies", out)
+	}
+	printCookies(cmd.OutOrStdout(), out)
+	return nil
+}
+
+func printCookies(w io.Writer, list []cookieJSON) {
+	if len(list) == 0 {
+		fmt.Fprintln(w, "No cookies stored.")
+		return
+	}
+	if display.Accessible() {
+		for _, c := range list {
+			fmt.Fprintf(w, "%s on %s%s, %s.\n", c.Name, c.Domain, c.Path, cookieExpiry(c))
+		}
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DOMAIN\tPATH\tNAME\tEXPIRES")
+	for _, c := range list {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Domain, c.Path, c.Name, cookieExpiry(c))
+	}
+	tw.Flush()
+}
+
+func cookieExpiry(c cookieJSON) string {
+	if c.Expires == nil {
+		return "session"
+	}
+	return c.Expires.Local().Format("2006-01-02 15:04")
+}
+
+func runCookiesImport(cmd *cobra.Command, args []string) error {
+	in := cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return invalidArgsError(fmt.Sprintf("opening cookie file: %v", err), "pubcli cookies import cookies.txt")
+		}
+		defer f.Close()
+		in = f
+	}
+	imported, err := cookies.ParseNetscape(in)
+	if err != nil {
+		return invalidArgsError(
+			fmt.Sprintf("reading cookie file: %v", err),
+			"Export cookies for publix.com in the Netscape cookies.txt format.",
+		)
+	}
+
+	jar, err := openCookieJar()
+	if err != nil {
+		return err
+	}
+	jar.Add(imported...)
+	if err := jar.Save(); err != nil {
+		return configError(err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %d cookies.\n", len(imported))
+	if !activeConfig.CookieJar {
+		display.PrintWarning(cmd.ErrOrStderr(), "Set cookie_jar: true in the config file to send them with requests.")
+	}
+	return nil
+}
+
+func runCookiesClear(cmd *cobra.Command, _ []string) error {
+	path, err := config.DataPath(cookies.FileName)
+	if err != nil {
+		return configError(err)
+	}
+	if err := cookies.Clear(path); err != nil {
+		return configError(err)
+	}
+	// Keep this run from writing the jar back.
+	cookieJar = nil
+	fmt.Fprintln(cmd.OutOrStdout(), "Cleared stored cookies.")
+	return nil
+}
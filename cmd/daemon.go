@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/daemon"
+)
+
+var (
+	flagDaemonSocket  string
+	flagDaemonRefresh time.Duration
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep weekly ads warm in memory and serve other pubcli commands over a unix socket",
+	Long: "Run in the foreground, holding the weekly ad for the configured store (and any store " +
+		"requested since) in memory, re-fetching on --refresh so updated ads are picked up. " +
+		"While it runs, other pubcli commands read through the daemon's socket and return in " +
+		"milliseconds; when it is not running they fetch from Publix directly.",
+	Example: `  pubcli daemon --store 1425
+  pubcli daemon --zip 33101 --refresh 30m
+  PUBCLI_NO_DAEMON=1 pubcli --store 1425   # bypass the daemon`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runDaemon,
+}
+
+var daemonWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream events from a running daemon (ads warmed, updated, or failing to refresh)",
+	Long: "Connect to the daemon's control API and print an event each time a weekly ad is " +
+		"warmed, changes upstream, or fails to refresh. With --json, events are printed as " +
+		"NDJSON, one object per line.",
+	Example: `  pubcli daemon watch
+  pubcli daemon watch --json | jq -c 'select(.type == "updated")'`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runDaemonWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	daemonCmd.AddCommand(daemonWatchCmd)
+	daemonCmd.PersistentFlags().StringVar(&flagDaemonSocket, "socket", "", "Unix socket path (default: $PUBCLI_DAEMON_SOCKET, $XDG_RUNTIME_DIR/pubcli.sock, or a temp dir)")
+	daemonCmd.Flags().DurationVar(&flagDaemonRefresh, "refresh", daemon.DefaultRefresh, "How often warm ads are re-fetched")
+}
+
+func daemonSocketPath() string {
+	if flagDaemonSocket != "" {
+		return flagDaemonSocket
+	}
+	return daemon.SocketPath()
+}
+
+func runDaemon(cmd *cobra.Command, _ []string) error {
+	if flagDaemonRefresh < time.Minute {
+		return invalidArgsError("--refresh must be at least 1m", "pubcli daemon --refresh 15m")
+	}
+	path := daemonSocketPath()
+
+	stderr := cmd.ErrOrStderr()
+	logf := func(format string, args ...any) {
+		fmt.Fprintf(stderr, "%s "+format+"\n", append([]any{time.Now().Format(time.TimeOnly)}, args...)...)
+	}
+	// The daemon must never read through itself.
+	upstreamOpts := []api.Option{
+		api.WithBreaker(api.NewBreaker()),
+		api.WithRetries(api.DefaultRetries, api.DefaultRetryBackoff),
+	}
+	if requestHeaders != nil {
+		upstreamOpts = append(upstreamOpts, api.WithHeaders(requestHeaders))
+	}
+	if harRecorder != nil {
+		upstreamOpts = append(upstreamOpts, api.WithHAR(harRecorder))
+	}
+	upstream := api.NewClient(upstreamOpts...)
+	d := daemon.New(upstream, flagDaemonRefresh, logf)
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if flagStore != "" || flagZip != "" {
+		storeNumber, err := resolveStore(cmd, upstream)
+		if err != nil {
+			return err
+		}
+		if err := d.Warm(ctx, storeNumber); err != nil {
+			return upstreamError("warming store #"+storeNumber, err)
+		}
+	}
+
+	listener, err := daemon.Listen(path)
+	if err != nil {
+		if strings.Contains(err.Error(), "already listening") {
+			return invalidArgsError(err.Error(), "Stop the running daemon first, or pass --socket PATH.")
+		}
+		return fmt.Errorf("opening daemon socket: %w", err)
+	}
+
+	srv := &http.Server{Handler: d.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go d.Run(ctx)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	logf("daemon listening on %s", path)
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving: %w", err)
+	}
+	return nil
+}
+
+func runDaemonWatch(cmd *cobra.Command, _ []string) error {
+	path := daemonSocketPath()
+	if !daemon.Running(path) {
+		return notFoundError(
+			"no daemon is running on "+path,
+			"pubcli daemon --store 1425",
+		)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, daemon.SocketHost+"/v1/events", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := daemon.HTTPClient(path).Do(req)
+	if err != nil {
+		return upstreamError("connecting to daemon", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return upstreamError("connecting to daemon", fmt.Errorf("unexpected status %s", resp.Status))
+	}
+
+	out := cmd.OutOrStdout()
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var ev daemon.Event
+		if err := dec.Decode(&ev); err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return upstreamError("reading daemon events", err)
+		}
+		if flagJSON {
+			if err := json.NewEncoder(out).Encode(ev); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintln(out, formatDaemonEvent(ev))
+	}
+}
+
+func formatDaemonEvent(ev daemon.Event) string {
+	stamp := ev.Time.Local().Format(time.TimeOnly)
+	switch ev.Type {
+	case "warmed":
+		return fmt.Sprintf("%s store #%s warmed (%d deals)", stamp, ev.Store, ev.Deals)
+	case "updated":
+		return fmt.Sprintf("%s store #%s weekly ad updated (%d deals, %s)", stamp, ev.Store, ev.Deals, ev.Updated)
+	case "refresh_failed":
+		return fmt.Sprintf("%s store #%s refresh failed: %s", stamp, ev.Store, ev.Error)
+	default:
+		return fmt.Sprintf("%s store #%s %s", stamp, ev.Store, ev.Type)
+	}
+}
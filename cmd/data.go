@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/alert"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/favorites"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+	"github.com/tayloree/publix-deals/internal/storage"
+	"github.com/tayloree/publix-deals/internal/track"
+)
+
+var (
+	flagDataMigrateTo   string
+	flagDataPruneWeeks  int
+	flagDataPruneDryRun bool
+)
+
+// storedKeys are the key prefixes of the state kept in the storage
+// backend: the shopping lists, the watchlist and what alerts have sent, the
+// tracked items, the favorites, and the ad history.
+var storedKeys = []string{
+	shoplist.FileName, shoplist.ListsDir + "/",
+	alert.WatchlistFile, alert.NotifiedFile, alertStateFile,
+	track.FileName, favorites.FileName, history.DirName + "/",
+}
+
+// dataBackend is the storage backend opened for this run; nil until
+// dataStore opens it.
+var dataBackend storage.Backend
+
+// dataPruneJSON is the --json output of `data prune`. History is what the
+// archive holds afterwards; after a dry run, what it holds now.
+type dataPruneJSON struct {
+	KeepWeeks    int           `json:"keepWeeks"`
+	Cutoff       string        `json:"cutoff,omitempty"`
+	DryRun       bool          `json:"dryRun"`
+	Removed      []string      `json:"removed"`
+	RemovedBytes int64         `json:"removedBytes"`
+	History      dataUsageJSON `json:"history"`
+}
+
+type dataUsageJSON struct {
+	Stores    int   `json:"stores"`
+	Snapshots int   `json:"snapshots"`
+	Bytes     int64 `json:"bytes"`
+}
+
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Manage where pubcli keeps its saved state",
+	Long: "Shopping lists, the alert watchlist, and the ad history are kept in JSON files in " +
+		"the data directory, or in one SQLite database with `storage: {backend: sqlite}` in " +
+		"config.yaml.",
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var dataMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy saved state to another storage backend and switch to it",
+	Long: "Copy the shopping lists, the watchlist, and the ad history from the configured " +
+		"storage backend to the one --to names, then set storage.backend in config.yaml. The " +
+		"old copy is left in place.",
+	Example: `  pubcli data migrate --to sqlite
+  pubcli data migrate --to json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runDataMigrate,
+}
+
+var dataPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove ad history older than the retention period",
+	Long: "Remove the weekly ad snapshots saved before the last retention.history_weeks ad " +
+		"weeks (26 by default; a negative value keeps everything), and report how much the " +
+		"history holds. Commands that save snapshots prune each store's older ones as they go; " +
+		"this prunes every store at once, including ones no longer fetched.",
+	Example: `  pubcli data prune
+  pubcli data prune --keep-weeks 8 --dry-run`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runDataPrune,
+}
+
+func init() {
+	rootCmd.AddCommand(dataCmd)
+	dataCmd.AddCommand(dataMigrateCmd)
+	dataCmd.AddCommand(dataPruneCmd)
+	dataPruneCmd.Flags().IntVar(&flagDataPruneWeeks, "keep-weeks", 0, "Ad weeks of history to keep, counting this one (default: retention.history_weeks)")
+	dataPruneCmd.Flags().BoolVar(&flagDataPruneDryRun, "dry-run", false, "Report what would be removed without removing it")
+	dataMigrateCmd.Flags().StringVar(&flagDataMigrateTo, "to", "", "Backend to move to: json or sqlite")
+	_ = dataMigrateCmd.MarkFlagRequired("to")
+	_ = dataMigrateCmd.RegisterFlagCompletionFunc("to", cobra.FixedCompletions(
+		[]string{storage.BackendJSON, storage.BackendSQLite}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// dataMigrateJSON is the --json output of `data migrate`.
+type dataMigrateJSON struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Keys []string `json:"keys"`
+}
+
+// dataStore opens the storage backend config.yaml names, once per run.
+func dataStore() (storage.Backend, error) {
+	if dataBackend != nil {
+		return dataBackend, nil
+	}
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, configError(err)
+	}
+	b, err := storage.Open(activeConfig.Storage.Backend, dir)
+	if err != nil {
+		return nil, configError(fmt.Errorf("storage.backend: %w", err))
+	}
+	dataBackend = b
+	return b, nil
+}
+
+// closeDataStore closes the backend dataStore opened, if any.
+func closeDataStore() {
+	if dataBackend != nil {
+		_ = dataBackend.Close()
+		dataBackend = nil
+	}
+}
+
+func runDataMigrate(cmd *cobra.Command, _ []string) error {
+	to := strings.ToLower(strings.TrimSpace(flagDataMigrateTo))
+	if err := storage.ValidateBackend(to); err != nil {
+		return invalidArgsError(err.Error(), "pubcli data migrate --to sqlite")
+	}
+	from := strings.ToLower(strings.TrimSpace(activeConfig.Storage.Backend))
+	if from == "" {
+		from = storage.BackendJSON
+	}
+	if from == to {
+		fmt.Fprintf(cmd.OutOrStdout(), "Already using the %s backend.\n", to)
+		return nil
+	}
+
+	src, err := dataStore()
+	if err != nil {
+		return err
+	}
+	dir, err := config.DataDir()
+	if err != nil {
+		return configError(err)
+	}
+	dst, err := storage.Open(to, dir)
+	if err != nil {
+		return configError(err)
+	}
+	defer dst.Close()
+	copied, err := storage.Migrate(src, dst, storedKeys)
+	if err != nil {
+		return configError(err)
+	}
+
+	doc, path, err := readConfigDocument()
+	if err != nil {
+		return err
+	}
+	if err := doc.Set("storage.backend", []string{to}); err != nil {
+		return configError(err)
+	}
+	if err := saveConfigDocument(doc, path); err != nil {
+		return err
+	}
+
+	if flagJSON {
+		if copied == nil {
+			copied = []string{}
+		}
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "migration", dataMigrateJSON{
+			From: src.Name(), To: dst.Name(), Keys: copied,
+		})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Copied %d item(s) from %s to %s; storage.backend is now %s.\n",
+		len(copied), src.Name(), dst.Name(), to)
+	return nil
+}
+
+func runDataPrune(cmd *cobra.Command, _ []string) error {
+	if flagDataPruneWeeks < 0 {
+		return invalidArgsError("--keep-weeks must be at least 1", "pubcli data prune --keep-weeks 26")
+	}
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	if flagDataPruneWeeks > 0 {
+		archive.KeepWeeks = flagDataPruneWeeks
+	}
+
+	out := dataPruneJSON{KeepWeeks: archive.KeepWeeks, DryRun: flagDataPruneDryRun, Removed: []string{}}
+	if archive.KeepWeeks > 0 {
+		cutoff := history.Cutoff(time.Now(), archive.KeepWeeks)
+		pruned, err := archive.Prune(cutoff, flagDataPruneDryRun)
+		if err != nil {
+			return configError(err)
+		}
+		out.Cutoff = cutoff.Format("2006-01-02")
+		if pruned.Keys != nil {
+			out.Removed = pruned.Keys
+		}
+		out.RemovedBytes = pruned.Bytes
+	}
+	usage, err := archive.Usage()
+	if err != nil {
+		return configError(err)
+	}
+	out.History = dataUsageJSON{Stores: usage.Stores, Snapshots: usage.Snapshots, Bytes: usage.Bytes}
+
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "prune", out)
+	}
+	w := cmd.OutOrStdout()
+	switch {
+	case out.KeepWeeks == 0:
+		fmt.Fprintln(w, "retention.history_weeks keeps every snapshot; nothing to prune.")
+	case out.DryRun:
+		fmt.Fprintf(w, "Would remove %d snapshot(s) (%s) saved before %s.\n", len(out.Removed), formatBytes(out.RemovedBytes), out.Cutoff)
+	default:
+		fmt.Fprintf(w, "Removed %d snapshot(s) (%s) saved before %s.\n", len(out.Removed), formatBytes(out.RemovedBytes), out.Cutoff)
+	}
+	fmt.Fprintf(w, "History holds %d snapshot(s) of %d store(s), %s.\n",
+		out.History.Snapshots, out.History.Stores, formatBytes(out.History.Bytes))
+	return nil
+}
+
+// formatBytes renders a size in bytes, KB, or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
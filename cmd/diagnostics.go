@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/tayloree/publix-deals/internal/diag"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+// diagnosticsNote is the one-line footer of a multi-request command: how
+// many stores were skipped, which upstream errors caused it, and how many
+// requests were retried. It is empty when every request succeeded at once.
+func diagnosticsNote(skipped int, rec *diag.Recorder) string {
+	summary := rec.Summary()
+	switch {
+	case summary != nil && skipped > 0:
+		return fmt.Sprintf("note: skipped %d store(s); %s.", skipped, summary)
+	case summary != nil:
+		return fmt.Sprintf("note: %s.", summary)
+	case skipped > 0:
+		return fmt.Sprintf("note: skipped %d store(s) due to upstream fetch errors.", skipped)
+	default:
+		return ""
+	}
+}
+
+// printJSONWithDiagnostics is display.PrintVersionedJSON with a
+// "diagnostics" member when any upstream request failed or was retried.
+func printJSONWithDiagnostics(w io.Writer, key string, payload any, rec *diag.Recorder) error {
+	if summary := rec.Summary(); summary != nil {
+		return display.PrintVersionedJSONWith(w, key, payload, map[string]any{"diagnostics": summary})
+	}
+	return display.PrintVersionedJSON(w, key, payload)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/diag"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+func TestCompareDiagnostics_SummarizeSkippedStores(t *testing.T) {
+	savings := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PublixStore") == "1500" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(api.SavingsResponse{Savings: []api.SavingItem{{ID: "1", Title: strPtr("Chicken")}}})
+	}))
+	t.Cleanup(savings.Close)
+	stores := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(api.StoreResponse{Stores: []api.Store{{Key: "01425"}, {Key: "01500"}}})
+	}))
+	t.Cleanup(stores.Close)
+
+	rec := &diag.Recorder{}
+	client := api.NewClientWithBaseURLs(savings.URL, stores.URL, api.WithObserver(rec.Record))
+	results, skipped, err := compareStores(context.Background(), client, "33101", 2, filter.Options{})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "note: skipped 1 store(s); 1 of 3 upstream requests failed (http_503 ×1).", diagnosticsNote(skipped, rec))
+
+	var buf bytes.Buffer
+	require.NoError(t, printJSONWithDiagnostics(&buf, "stores", results, rec))
+	var payload struct {
+		Diagnostics diag.Summary `json:"diagnostics"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &payload))
+	assert.Equal(t, 1, payload.Diagnostics.Failed)
+	assert.Equal(t, "http_503", payload.Diagnostics.Errors[0].Type)
+	assert.Contains(t, payload.Diagnostics.Errors[0].Samples[0], "store #1500: unexpected status 503")
+}
+
+func TestDiagnosticsNote_Clean(t *testing.T) {
+	rec := &diag.Recorder{}
+	rec.Record("1425", 1, nil)
+	assert.Empty(t, diagnosticsNote(0, rec))
+
+	var buf bytes.Buffer
+	require.NoError(t, printJSONWithDiagnostics(&buf, "stores", []string{}, rec))
+	assert.NotContains(t, buf.String(), "diagnostics")
+}
+
+func TestPrintDealsJSON_NextOffset(t *testing.T) {
+	items := []api.SavingItem{{ID: "1", Title: strPtr("Chicken")}}
+
+	var buf bytes.Buffer
+	require.NoError(t, printDealsJSON(&buf, items, nil, 20))
+	var payload struct {
+		Deals      []json.RawMessage `json:"deals"`
+		NextOffset int               `json:"nextOffset"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &payload))
+	assert.Len(t, payload.Deals, 1)
+	assert.Equal(t, 20, payload.NextOffset)
+
+	buf.Reset()
+	require.NoError(t, printDealsJSON(&buf, items, nil, 0))
+	assert.NotContains(t, buf.String(), "nextOffset")
+}
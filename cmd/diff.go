@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/addiff"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+const diffDateLayout = "2006-01-02"
+
+var (
+	flagDiffFrom   string
+	flagDiffTo     string
+	flagDiffFormat string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare this week's ad with the previous one, or two saved ads",
+	Long: "List the deals added, removed, and changed between two weekly ads of a store, with the " +
+		"fields that changed. By default the current ad is fetched, saved to the history, and " +
+		"compared with the previous ad saved there. --from and --to pick saved ads instead: each " +
+		"date picks the newest snapshot saved on or before it, so --from 2025-02-11 --to " +
+		"2025-02-18 compares the ads a week apart without fetching anything. --from alone " +
+		"compares that ad with the current one, and --to alone compares that ad with the one " +
+		"before it. --format patch writes JSON Patch-style operations on /deals/<title>.",
+	Example: `  pubcli diff --store 1425
+  pubcli diff --store 1425 --json
+  pubcli diff --store 1425 --from 2025-02-11 --to 2025-02-18
+  pubcli diff --store 1425 --from 2025-02-11 --to 2025-02-18 --format markdown
+  pubcli diff --store 1425 --from 2025-02-11 --to 2025-02-18 --format patch`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVar(&flagDiffFrom, "from", "", "Date of the older ad, YYYY-MM-DD")
+	diffCmd.Flags().StringVar(&flagDiffTo, "to", "", "Date of the newer ad, YYYY-MM-DD")
+	diffCmd.Flags().StringVar(&flagDiffFormat, "format", "text", "Output format: text, markdown, patch, or json")
+}
+
+func runDiff(cmd *cobra.Command, _ []string) error {
+	format := strings.ToLower(strings.TrimSpace(flagDiffFormat))
+	switch format {
+	case "text", "markdown", "md", "patch", "json":
+	default:
+		return invalidArgsError(
+			"invalid value for --format (use text, markdown, patch, or json)",
+			"pubcli diff --store 1425 --from 2025-02-11 --to 2025-02-18 --format markdown",
+		)
+	}
+	if !cmd.Flags().Changed("format") && flagJSON {
+		format = "json"
+	}
+	var from, to time.Time
+	var err error
+	if flagDiffFrom != "" {
+		if from, err = parseDiffDate("from", flagDiffFrom); err != nil {
+			return err
+		}
+	}
+	if flagDiffTo != "" {
+		if to, err = parseDiffDate("to", flagDiffTo); err != nil {
+			return err
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return invalidArgsError("--to must not be before --from",
+			fmt.Sprintf("pubcli diff --store 1425 --from %s --to %s", flagDiffTo, flagDiffFrom))
+	}
+
+	client := newAPIClient()
+	storeNumber := strings.TrimSpace(flagStore)
+	if storeNumber == "" {
+		if storeNumber, err = resolveStore(cmd, client); err != nil {
+			return err
+		}
+	}
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	result, err := diffAds(cmd.Context(), client, archive, storeNumber, from, to)
+	if err != nil {
+		return err
+	}
+	return writeDiff(cmd, result, format)
+}
+
+// diffAds compares the store's ads saved on or before from and to. A zero
+// to is the current ad, fetched and saved to the archive; a zero from is
+// the ad saved before the newer one.
+func diffAds(ctx context.Context, client *api.Client, archive history.Archive, store string, from, to time.Time) (addiff.Result, error) {
+	snapshots, err := archive.List(store)
+	if err != nil {
+		return addiff.Result{}, err
+	}
+
+	var newer history.Snapshot
+	if to.IsZero() {
+		data, err := fetchSavingsCached(ctx, client, store)
+		if err != nil {
+			return addiff.Result{}, upstreamError("fetching deals", err)
+		}
+		newer = history.Snapshot{
+			Store:   store,
+			Updated: data.WeeklyAdLatestUpdatedDateTime,
+			SavedAt: time.Now(),
+			Deals:   data.Savings,
+		}
+		if err := archive.Save(newer); err != nil {
+			return addiff.Result{}, configError(err)
+		}
+	} else if newer, err = snapshotAsOf(snapshots, store, to); err != nil {
+		return addiff.Result{}, err
+	}
+
+	var older history.Snapshot
+	if from.IsZero() {
+		older, err = snapshotBefore(snapshots, newer)
+	} else {
+		older, err = snapshotAsOf(snapshots, store, from)
+	}
+	if err != nil {
+		return addiff.Result{}, err
+	}
+
+	result := addiff.Diff(older.Deals, newer.Deals)
+	result.Store = store
+	result.From = addiff.Side{Date: older.SavedAt.Local().Format(diffDateLayout), Updated: older.Updated}
+	result.To = addiff.Side{Date: newer.SavedAt.Local().Format(diffDateLayout), Updated: newer.Updated}
+	return result, nil
+}
+
+func writeDiff(cmd *cobra.Command, result addiff.Result, format string) error {
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		return display.PrintVersionedJSON(out, "diff", result)
+	case "patch":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result.Patch())
+	case "markdown", "md":
+		addiff.Write(out, result, true)
+	default:
+		addiff.Write(out, result, false)
+	}
+	return nil
+}
+
+func parseDiffDate(flag, value string) (time.Time, error) {
+	t, err := time.ParseInLocation(diffDateLayout, strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return time.Time{}, invalidArgsError(
+			fmt.Sprintf("invalid --%s date %q (use YYYY-MM-DD)", flag, value),
+			"pubcli diff --store 1425 --from 2025-02-11 --to 2025-02-18",
+		)
+	}
+	return t, nil
+}
+
+// snapshotBefore returns the newest snapshot of another ad version saved
+// no later than s: the ad s replaced.
+func snapshotBefore(snapshots []history.Snapshot, s history.Snapshot) (history.Snapshot, error) {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		p := snapshots[i]
+		if p.SavedAt.After(s.SavedAt) || (s.Updated != "" && p.Updated == s.Updated) {
+			continue
+		}
+		if s.Updated == "" && p.SavedAt.Equal(s.SavedAt) {
+			continue
+		}
+		return p, nil
+	}
+	return history.Snapshot{}, notFoundError(
+		fmt.Sprintf("no earlier ad saved for store #%s to compare with", s.Store),
+		"The ad is saved now; run pubcli diff again after the next ad comes out (ads change on Wednesdays).",
+	)
+}
+
+// snapshotAsOf returns the newest snapshot saved on or before day.
+// snapshots are oldest first, as Archive.List returns them.
+func snapshotAsOf(snapshots []history.Snapshot, store string, day time.Time) (history.Snapshot, error) {
+	want := day.Format(diffDateLayout)
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].SavedAt.Local().Format(diffDateLayout) <= want {
+			return snapshots[i], nil
+		}
+	}
+	if len(snapshots) == 0 {
+		return history.Snapshot{}, notFoundError(
+			fmt.Sprintf("no saved ads for store #%s", store),
+			fmt.Sprintf("pubcli report --weekly --store %s", store),
+		)
+	}
+	return history.Snapshot{}, notFoundError(
+		fmt.Sprintf("no saved ad for store #%s on or before %s; the oldest is from %s",
+			store, want, snapshots[0].SavedAt.Local().Format(diffDateLayout)),
+	)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestDiffAds_ComparesTheCurrentAdWithThePreviousOne(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+
+	ad := api.SavingsResponse{WeeklyAdLatestUpdatedDateTime: "b", Savings: []api.SavingItem{
+		{ID: "3", Title: strPtr("Nutella"), Categories: []string{"bogo"}},
+		{ID: "4", Title: strPtr("Salsa")},
+	}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(ad)
+	}))
+	defer srv.Close()
+	client := api.NewClientWithBaseURLs(srv.URL, "")
+	archive := history.Archive{Backend: &storage.Files{Dir: dataDir}}
+
+	_, err := diffAds(context.Background(), client, archive, "1425", time.Time{}, time.Time{})
+	var cliErr *cliError
+	require.ErrorAs(t, err, &cliErr, "nothing to compare the first ad with")
+	assert.Equal(t, ExitNotFound, cliErr.ExitCode)
+
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: time.Now().AddDate(0, 0, -7),
+		Deals: []api.SavingItem{{ID: "1", Title: strPtr("Nutella")}, {ID: "2", Title: strPtr("Chips")}}}))
+	result, err := diffAds(context.Background(), client, archive, "1425", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "a", result.From.Updated)
+	assert.Equal(t, "b", result.To.Updated)
+	assert.Len(t, result.Added, 1)
+	assert.Len(t, result.Removed, 1)
+	require.Len(t, result.Changed, 1)
+	assert.Equal(t, "Nutella", result.Changed[0].Title)
+
+	// The current ad was saved, so running again compares the same two ads.
+	again, err := diffAds(context.Background(), client, archive, "1425", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, result.From, again.From)
+	assert.Len(t, again.Changed, 1)
+}
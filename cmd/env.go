@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/console"
+	"github.com/tayloree/publix-deals/internal/daemon"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print pubcli's effective configuration",
+	Long: "Print the build, the config file and data directory in use, the defaults loaded from " +
+		"the config file, the environment variables pubcli reads, and what is cached on disk, " +
+		"like `go env`. Secrets are shown only as set or unset. Useful for bug reports and for " +
+		"checking a packaged install.",
+	Example: `  pubcli env
+  pubcli env --json`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runEnv,
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+}
+
+// envVars are the environment variables pubcli reads, in display order.
+var envVars = []struct {
+	name   string
+	secret bool
+}{
+	{name: config.EnvConfigDir},
+	{name: config.EnvDataDir},
+	{name: config.EnvCacheDir},
+	{name: config.EnvProfile},
+	{name: daemon.EnvSocket},
+	{name: daemon.EnvDisable},
+	{name: console.EnvASCII},
+	{name: "NO_COLOR"},
+	{name: "CLICOLOR"},
+	{name: "CLICOLOR_FORCE"},
+	{name: envSyncPassword, secret: true},
+	{name: envSlackSigningSecret, secret: true},
+	{name: envSecretsPassphrase, secret: true},
+	{name: "XDG_DATA_HOME"},
+	{name: "XDG_RUNTIME_DIR"},
+}
+
+// envJSON is the --json output of `env`.
+type envJSON struct {
+	Version          string        `json:"version"`
+	GoVersion        string        `json:"goVersion"`
+	Platform         string        `json:"platform"`
+	Profile          string        `json:"profile"`
+	ConfigFile       string        `json:"configFile"`
+	ConfigFileExists bool          `json:"configFileExists"`
+	DataDir          string        `json:"dataDir"`
+	Storage          string        `json:"storage"`
+	DaemonSocket     string        `json:"daemonSocket"`
+	DaemonRunning    bool          `json:"daemonRunning"`
+	Defaults         envDefaults   `json:"defaults"`
+	Env              []envVariable `json:"env"`
+	Cache            envCache      `json:"cache"`
+}
+
+type envDefaults struct {
+	Store         string `json:"store"`
+	Zip           string `json:"zip"`
+	Command       string `json:"command"`
+	Locale        string `json:"locale"`
+	Lang          string `json:"lang"`
+	SchemaVersion int    `json:"schemaVersion"`
+	Accessible    bool   `json:"accessible"`
+	Quiet         bool   `json:"quiet"`
+}
+
+// envVariable is one environment variable. Value is empty for secrets.
+type envVariable struct {
+	Name   string `json:"name"`
+	Set    bool   `json:"set"`
+	Value  string `json:"value,omitempty"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+type envCache struct {
+	AdCacheDir       string `json:"adCacheDir"`
+	AdCacheStores    int    `json:"adCacheStores"`
+	HistoryDir       string `json:"historyDir"`
+	HistoryStores    int    `json:"historyStores"`
+	HistorySnapshots int    `json:"historySnapshots"`
+}
+
+func runEnv(cmd *cobra.Command, _ []string) error {
+	env, err := buildEnv(activeConfig)
+	if err != nil {
+		return configError(err)
+	}
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "env", env)
+	}
+	printEnv(cmd.OutOrStdout(), env)
+	return nil
+}
+
+func buildEnv(cfg *config.Config) (envJSON, error) {
+	configFile, err := config.Path()
+	if err != nil {
+		return envJSON{}, err
+	}
+	dataDir, err := config.DataDir()
+	if err != nil {
+		return envJSON{}, err
+	}
+	socket := daemon.SocketPath()
+
+	env := envJSON{
+		Version:          buildVersion(),
+		GoVersion:        runtime.Version(),
+		Platform:         runtime.GOOS + "/" + runtime.GOARCH,
+		Profile:          config.ActiveProfile(),
+		ConfigFile:       configFile,
+		ConfigFileExists: fileExists(configFile),
+		DataDir:          dataDir,
+		DaemonSocket:     socket,
+		DaemonRunning:    daemon.Running(socket),
+		Defaults: envDefaults{
+			Store:         cfg.DefaultStore,
+			Zip:           cfg.DefaultZip,
+			Command:       cfg.DefaultCommand,
+			Locale:        display.DefaultLocale,
+			Lang:          display.DefaultLanguage,
+			SchemaVersion: display.LatestSchemaVersion,
+			Accessible:    cfg.Accessible,
+			Quiet:         cfg.Quiet,
+		},
+		Env: make([]envVariable, 0, len(envVars)),
+		Cache: envCache{
+			HistoryDir: filepath.Join(dataDir, history.DirName),
+		},
+	}
+	if cacheDir, err := config.CacheDir(); err == nil {
+		env.Cache.AdCacheDir = filepath.Join(cacheDir, api.AdCacheDir)
+	}
+	if cfg.Locale != "" {
+		env.Defaults.Locale = cfg.Locale
+	}
+	if cfg.Lang != "" {
+		env.Defaults.Lang = cfg.Lang
+	}
+	if cfg.SchemaVersion != 0 {
+		env.Defaults.SchemaVersion = cfg.SchemaVersion
+	}
+
+	for _, v := range envVars {
+		value, set := os.LookupEnv(v.name)
+		e := envVariable{Name: v.name, Set: set, Secret: v.secret}
+		if !v.secret {
+			e.Value = value
+		}
+		env.Env = append(env.Env, e)
+	}
+
+	if env.Cache.AdCacheDir != "" {
+		ads, _ := filepath.Glob(filepath.Join(env.Cache.AdCacheDir, "*.json"))
+		env.Cache.AdCacheStores = len(ads)
+	}
+	if store, err := dataStore(); err == nil {
+		env.Storage = store.Name()
+		keys, _ := store.Keys(history.DirName + "/")
+		stores := map[string]bool{}
+		for _, key := range keys {
+			if parts := strings.Split(strings.TrimPrefix(key, history.DirName+"/"), "/"); len(parts) == 2 {
+				stores[parts[0]] = true
+				env.Cache.HistorySnapshots++
+			}
+		}
+		env.Cache.HistoryStores = len(stores)
+	}
+	return env, nil
+}
+
+// buildVersion returns the module version pubcli was built from, which is
+// "(devel)" for builds from a source checkout.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
+	}
+	return info.Main.Version
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+func printEnv(w io.Writer, env envJSON) {
+	rows := [][2]string{
+		{"version", env.Version},
+		{"go", env.GoVersion + " " + env.Platform},
+		{"profile", orDefault(env.Profile)},
+		{"config file", env.ConfigFile + existsNote(env.ConfigFileExists, "not found")},
+		{"data dir", env.DataDir},
+		{"storage", env.Storage},
+		{"daemon socket", env.DaemonSocket + existsNote(env.DaemonRunning, "not running")},
+		{"default store", orNone(env.Defaults.Store)},
+		{"default zip", orNone(env.Defaults.Zip)},
+		{"default command", orNone(env.Defaults.Command)},
+		{"locale", env.Defaults.Locale},
+		{"lang", env.Defaults.Lang},
+		{"schema version", fmt.Sprint(env.Defaults.SchemaVersion)},
+		{"accessible", fmt.Sprint(env.Defaults.Accessible)},
+		{"quiet", fmt.Sprint(env.Defaults.Quiet)},
+	}
+	for _, v := range env.Env {
+		value := "(unset)"
+		switch {
+		case v.Set && v.Secret:
+			value = "(set)"
+		case v.Set:
+			value = v.Value
+		}
+		rows = append(rows, [2]string{v.Name, value})
+	}
+	rows = append(rows,
+		[2]string{"ad cache", fmt.Sprintf("%d stores in %s", env.Cache.AdCacheStores, env.Cache.AdCacheDir)},
+		[2]string{"history", fmt.Sprintf("%d stores, %d snapshots in %s", env.Cache.HistoryStores, env.Cache.HistorySnapshots, env.Cache.HistoryDir)},
+	)
+
+	if display.Accessible() {
+		for _, r := range rows {
+			fmt.Fprintf(w, "%s: %s\n", r[0], r[1])
+		}
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\n", r[0], r[1])
+	}
+	tw.Flush()
+}
+
+// existsNote returns " (note)" when ok is false.
+func existsNote(ok bool, note string) string {
+	if ok {
+		return ""
+	}
+	return " (" + note + ")"
+}
+
+func orDefault(s string) string {
+	if s == "" {
+		return "(default)"
+	}
+	return s
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
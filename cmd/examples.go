@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+var flagExamplesFormat string
+
+// recipe is one copy-pasteable task of `pubcli examples`.
+type recipe struct {
+	Section  string   `json:"section"`
+	Title    string   `json:"title"`
+	Commands []string `json:"commands"`
+}
+
+// recipes are grouped like the root help, in the order they are printed.
+var recipes = []recipe{
+	{helpCommonTasks, "Find your store and remember it", []string{
+		"pubcli stores --zip 33101",
+		"pubcli config set default_store 1425",
+	}},
+	{helpCommonTasks, "See whether this week's ad is worth a trip", []string{"pubcli summary --store 1425"}},
+	{helpCommonTasks, "See this week's deals", []string{"pubcli --store 1425"}},
+	{helpCommonTasks, "See what is new in this week's ad", []string{"pubcli ad new --store 1425"}},
+	{helpCommonTasks, "See which deals end soon", []string{"pubcli ad ending --store 1425 --limit 10"}},
+	{helpCommonTasks, "Find the store near you with the best produce deals", []string{
+		"pubcli compare --zip 33101 --category produce",
+	}},
+	{helpCommonTasks, "Take a shopping list to the store", []string{
+		`pubcli list add eggs`,
+		`pubcli list add "chicken thighs"`,
+		"pubcli list match --store 1425",
+	}},
+	{helpCommonTasks, "Browse deals interactively", []string{"pubcli tui --store 1425"}},
+	{helpFilters, "Search for a keyword", []string{"pubcli --store 1425 --query chicken"}},
+	{helpFilters, "Show only BOGO deals in one department", []string{"pubcli --store 1425 --bogo --department meat"}},
+	{helpFilters, "Show deals saving at least 30%", []string{"pubcli --store 1425 --min-percent-off 30 --sort savings"}},
+	{helpFilters, "Show only deals that are new since last week", []string{"pubcli --store 1425 --new-only"}},
+	{helpOutput, "Show the three best deals of each department", []string{
+		"pubcli --store 1425 --sort savings --group-by department --limit-per-group 3",
+	}},
+	{helpOutput, "Page through deals 20 at a time", []string{
+		"pubcli --store 1425 --limit 20",
+		"pubcli --store 1425 --limit 20 --offset 20",
+	}},
+	{helpOutput, "Paste deals into chat or notes as a Markdown table", []string{
+		"pubcli --store 1425 --bogo --format markdown",
+	}},
+	{helpOutput, "Pipe deals to jq", []string{
+		`pubcli --store 1425 --json | jq -r '.deals[].title'`,
+	}},
+	{helpAutomation, "Get a weekly Markdown report from cron", []string{
+		`pubcli schedule install --weekly "report --weekly --store 1425 --format markdown"`,
+	}},
+	{helpAutomation, "Get notified when coffee is on sale", []string{
+		"pubcli alert add coffee coffee",
+		"pubcli alert run --store 1425",
+	}},
+	{helpAutomation, "Serve deals as JSON over HTTP", []string{
+		"pubcli serve --port 8080",
+		"curl '127.0.0.1:8080/deals?store=1425&bogo=true'",
+	}},
+	{helpAutomation, "Run several queries in one invocation", []string{
+		`echo '[{"command":"deals","store":"1425","bogo":true},{"command":"stores","zip":"33101"}]' | pubcli batch`,
+	}},
+	{helpAutomation, "Describe every command and flag for an agent", []string{"pubcli capabilities --json"}},
+}
+
+var examplesCmd = &cobra.Command{
+	Use:   "examples",
+	Short: "Print copy-pasteable recipes for common tasks",
+	Long: "Print recipes for common tasks, grouped like `pubcli --help`: common tasks, filters, output, " +
+		"and automation. Replace the store number 1425 and ZIP code 33101 with your own.",
+	Example: `  pubcli examples
+  pubcli examples --format markdown > RECIPES.md`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runExamples,
+}
+
+func init() {
+	rootCmd.AddCommand(examplesCmd)
+	examplesCmd.Flags().StringVar(&flagExamplesFormat, "format", "text", "Output format: text, markdown, or json")
+}
+
+func runExamples(cmd *cobra.Command, _ []string) error {
+	format := strings.ToLower(strings.TrimSpace(flagExamplesFormat))
+	switch format {
+	case "text", "markdown", "md", "json":
+	default:
+		return invalidArgsError(
+			"invalid value for --format (use text, markdown, or json)",
+			"pubcli examples --format markdown",
+		)
+	}
+	if !cmd.Flags().Changed("format") && flagJSON {
+		format = "json"
+	}
+
+	w := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		return display.PrintVersionedJSON(w, "examples", recipes)
+	case "markdown", "md":
+		printRecipesMarkdown(w, recipes)
+	default:
+		printRecipes(w, recipes)
+	}
+	return nil
+}
+
+// printRecipes writes recipes as shell comments and commands, so a section
+// can be pasted into a terminal as is.
+func printRecipes(w io.Writer, recipes []recipe) {
+	section := ""
+	for _, r := range recipes {
+		if r.Section != section {
+			if section != "" {
+				fmt.Fprintln(w)
+			}
+			section = r.Section
+			fmt.Fprintf(w, "%s:\n", section)
+		}
+		fmt.Fprintf(w, "\n  # %s\n", r.Title)
+		for _, c := range r.Commands {
+			fmt.Fprintf(w, "  %s\n", c)
+		}
+	}
+}
+
+// printRecipesMarkdown writes recipes as Markdown, one heading per section
+// and one shell code block per recipe.
+func printRecipesMarkdown(w io.Writer, recipes []recipe) {
+	fmt.Fprint(w, "# pubcli recipes\n")
+	section := ""
+	for _, r := range recipes {
+		if r.Section != section {
+			section = r.Section
+			fmt.Fprintf(w, "\n## %s\n", section)
+		}
+		fmt.Fprintf(w, "\n%s:\n\n```sh\n%s\n```\n", r.Title, strings.Join(r.Commands, "\n"))
+	}
+}
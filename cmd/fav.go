@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/favorites"
+)
+
+var favCmd = &cobra.Command{
+	Use:   "fav",
+	Short: "Star deals and see when starred items are on sale again",
+	Long: "Keep a list of starred deals in the data directory. Deal IDs change with every weekly " +
+		"ad, so a star remembers the deal's title, and `pubcli fav show` finds the deals with that " +
+		"title in the current ad. Press F in `pubcli tui` to star or unstar the selected deal.",
+	Example: `  pubcli fav add "Publix Ice Cream" --store 1425
+  pubcli fav
+  pubcli fav show --store 1425
+  pubcli fav remove 2`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runFavList,
+}
+
+var favAddCmd = &cobra.Command{
+	Use:   "add DEAL...",
+	Short: "Star a deal of the current ad by ID, title, or title words",
+	Example: `  pubcli fav add "Publix Ice Cream" --store 1425
+  pubcli fav add 123456 --zip 33101`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runFavAdd,
+}
+
+var favListCmd = &cobra.Command{
+	Use:         "list",
+	Aliases:     []string{"ls"},
+	Short:       "Print the starred deals",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runFavList,
+}
+
+var favRemoveCmd = &cobra.Command{
+	Use:         "remove NUMBER|TITLE",
+	Aliases:     []string{"rm"},
+	Short:       "Unstar a deal by its number in `fav list` or by title",
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runFavRemove,
+}
+
+var favShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show which starred items are on sale this week",
+	Long: "Look up every starred deal by title in the store's current weekly ad, then list the " +
+		"starred items that are not on sale. The store is --store, --zip, or the one the first " +
+		"star was added at.",
+	Example: `  pubcli fav show --store 1425
+  pubcli fav show --zip 33101 --json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runFavShow,
+}
+
+func init() {
+	rootCmd.AddCommand(favCmd)
+	favCmd.AddCommand(favAddCmd, favListCmd, favRemoveCmd, favShowCmd)
+}
+
+// favShowJSON is the --json output of `fav show`.
+type favShowJSON struct {
+	Store string `json:"store"`
+	// OnSale counts the starred items the ad carries.
+	OnSale int               `json:"onSale"`
+	Items  []favorites.Match `json:"items"`
+}
+
+func loadFavorites() (*favorites.List, error) {
+	store, err := dataStore()
+	if err != nil {
+		return nil, err
+	}
+	l, err := favorites.Load(store, favorites.FileName)
+	if err != nil {
+		return nil, configError(err)
+	}
+	return l, nil
+}
+
+func saveFavorites(l *favorites.List) error {
+	store, err := dataStore()
+	if err != nil {
+		return err
+	}
+	if err := l.Save(store, favorites.FileName); err != nil {
+		return configError(err)
+	}
+	return nil
+}
+
+// toggleFavorite stars the deal of store, or unstars it, and reports
+// whether it is starred now.
+func toggleFavorite(store string, deal api.SavingItem) (bool, error) {
+	l, err := loadFavorites()
+	if err != nil {
+		return false, err
+	}
+	starred := l.Toggle(favorites.NewStar(store, deal, time.Now()))
+	return starred, saveFavorites(l)
+}
+
+// favoriteKeys returns the keys of the starred deals, for marking them.
+func favoriteKeys() (map[string]bool, error) {
+	l, err := loadFavorites()
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]bool, len(l.Stars))
+	for _, s := range l.Stars {
+		keys[s.Key] = true
+	}
+	return keys, nil
+}
+
+func runFavAdd(cmd *cobra.Command, args []string) error {
+	l, err := loadFavorites()
+	if err != nil {
+		return err
+	}
+	client := newAPIClient()
+	storeNumber, err := resolveStore(cmd, client)
+	if err != nil {
+		return err
+	}
+	data, err := fetchSavingsCached(cmd.Context(), client, storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	text := strings.TrimSpace(strings.Join(args, " "))
+	deal, matches := findListDeal(data.Savings, text)
+	switch {
+	case matches == 0:
+		return notFoundError(
+			fmt.Sprintf("%q is not in store #%s's weekly ad", text, storeNumber),
+			fmt.Sprintf("pubcli --store %s --query %q", storeNumber, text),
+		)
+	case matches > 1:
+		return invalidArgsError(
+			fmt.Sprintf("%d deals match %q; use more of the deal title or its ID", matches, text),
+			fmt.Sprintf("pubcli --store %s --query %q --json", storeNumber, text),
+		)
+	}
+
+	star := favorites.NewStar(storeNumber, deal, time.Now())
+	if !l.Add(star) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%q is already starred.\n", star.Title)
+		return nil
+	}
+	if err := saveFavorites(l); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Starred %q (%d favorite(s)).\n", star.Title, len(l.Stars))
+	return nil
+}
+
+func runFavList(cmd *cobra.Command, _ []string) error {
+	l, err := loadFavorites()
+	if err != nil {
+		return err
+	}
+	if flagJSON {
+		stars := l.Stars
+		if stars == nil {
+			stars = []favorites.Star{}
+		}
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "favorites", stars)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(l.Stars) == 0 {
+		fmt.Fprintln(out, "No favorites yet. Star deals with `pubcli fav add TITLE` or F in `pubcli tui`.")
+		return nil
+	}
+	for i, s := range l.Stars {
+		line := s.Title
+		if s.Department != "" {
+			line += " (" + s.Department + ")"
+		}
+		fmt.Fprintf(out, "%2d. %s\n", i+1, line)
+	}
+	return nil
+}
+
+func runFavRemove(cmd *cobra.Command, args []string) error {
+	l, err := loadFavorites()
+	if err != nil {
+		return err
+	}
+	removed, err := l.Remove(strings.Join(args, " "))
+	if err != nil {
+		return notFoundError(err.Error(), "pubcli fav list")
+	}
+	if err := saveFavorites(l); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Unstarred %q.\n", removed.Title)
+	return nil
+}
+
+func runFavShow(cmd *cobra.Command, _ []string) error {
+	l, err := loadFavorites()
+	if err != nil {
+		return err
+	}
+	if len(l.Stars) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No favorites yet. Star deals with `pubcli fav add TITLE` or F in `pubcli tui`.")
+		return nil
+	}
+
+	client := newAPIClient()
+	storeNumber := ""
+	if flagStore == "" && flagZip == "" {
+		storeNumber = l.Stars[0].Store
+	}
+	if storeNumber == "" {
+		if storeNumber, err = resolveStore(cmd, client); err != nil {
+			return err
+		}
+	}
+	data, err := fetchSavingsCached(cmd.Context(), client, storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	matches := l.Match(data.Savings)
+	if flagJSON {
+		onSale := 0
+		for _, m := range matches {
+			if m.OnSale() {
+				onSale++
+			}
+		}
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "favorites",
+			favShowJSON{Store: storeNumber, OnSale: onSale, Items: matches})
+	}
+	printFavShow(cmd.OutOrStdout(), storeNumber, matches)
+	return nil
+}
+
+func printFavShow(w io.Writer, store string, matches []favorites.Match) {
+	var onSale, notOnSale []favorites.Match
+	for _, m := range matches {
+		if m.OnSale() {
+			onSale = append(onSale, m)
+		} else {
+			notOnSale = append(notOnSale, m)
+		}
+	}
+	fmt.Fprintf(w, "%d of %d favorite(s) are on sale at store #%s.\n", len(onSale), len(matches), store)
+	for _, m := range onSale {
+		for _, d := range m.Deals {
+			line := d.Title
+			if d.Savings != "" {
+				line += " — " + d.Savings
+			}
+			if d.ValidTo != "" {
+				line += " (through " + d.ValidTo + ")"
+			}
+			fmt.Fprintf(w, "  ★ %s\n", line)
+		}
+	}
+	if len(notOnSale) > 0 {
+		titles := make([]string, 0, len(notOnSale))
+		for _, m := range notOnSale {
+			titles = append(titles, m.Title)
+		}
+		fmt.Fprintf(w, "Not on sale: %s\n", strings.Join(titles, ", "))
+	}
+}
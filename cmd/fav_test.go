@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/favorites"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestTUIModel_StarsSelectedDeal(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(closeDataStore)
+
+	model := loadedTUIModel(t, manyDeals(3), 120, 40)
+	model.storeNumber = "1425"
+	selected, ok := model.list.SelectedItem().(tuiDealItem)
+	require.True(t, ok)
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	require.NotNil(t, cmd)
+	msg := cmd()
+	toggled, ok := msg.(tuiFavoriteToggledMsg)
+	require.True(t, ok)
+	require.NoError(t, toggled.err)
+	assert.True(t, toggled.starred)
+
+	updated, _ := model.Update(msg)
+	model = updated.(dealsTUIModel)
+	assert.Equal(t, "★ "+selected.title, model.list.SelectedItem().(tuiDealItem).title)
+	assert.Contains(t, model.footerView(), "Starred "+selected.title)
+
+	l, err := favorites.Load(&storage.Files{Dir: dataDir}, favorites.FileName)
+	require.NoError(t, err)
+	require.Len(t, l.Stars, 1)
+	assert.Equal(t, "1425", l.Stars[0].Store)
+
+	starred, err := toggleFavorite("1425", selected.deal)
+	require.NoError(t, err)
+	assert.False(t, starred, "F again unstars")
+}
+
+func TestRunCLI_FavListAndRemove(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+
+	l := &favorites.List{Stars: []favorites.Star{
+		{Key: "publix coffee", Title: "Publix Coffee", Department: "Grocery"},
+		{Key: "bananas", Title: "Bananas"},
+	}}
+	require.NoError(t, l.Save(&storage.Files{Dir: dataDir}, favorites.FileName))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"fav", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Equal(t, " 1. Publix Coffee (Grocery)\n 2. Bananas\n", stdout.String())
+
+	stdout.Reset()
+	code = runCLI([]string{"fav", "rm", "publix coffee"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Equal(t, "Unstarred \"Publix Coffee\".\n", stdout.String())
+
+	code = runCLI([]string{"fav", "rm", "7"}, &stdout, &stderr)
+	assert.Equal(t, ExitNotFound, code)
+}
+
+func TestPrintFavShow(t *testing.T) {
+	l := &favorites.List{Stars: []favorites.Star{{Key: "publix coffee", Title: "Publix Coffee"}, {Key: "bananas", Title: "Bananas"}}}
+	deals := []api.SavingItem{{Title: strPtr("Publix Coffee"), Savings: strPtr("Buy 1 Get 1 FREE")}}
+
+	var buf bytes.Buffer
+	printFavShow(&buf, "1425", l.Match(deals))
+	assert.Equal(t, "1 of 2 favorite(s) are on sale at store #1425.\n  ★ Publix Coffee — Buy 1 Get 1 FREE\nNot on sale: Bananas\n", buf.String())
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/fixtures"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+const fixturesMaxDeals = 100000
+
+var (
+	flagFixturesDeals     int
+	flagFixturesStores    int
+	flagFixturesSeed      uint64
+	flagFixturesOut       string
+	flagFixturesWeekStart string
+)
+
+var fixturesCmd = &cobra.Command{
+	Use:         "fixtures",
+	Short:       "Generate test data shaped like the Publix API",
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var fixturesGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Write randomized savings and store locator responses",
+	Long: "Write savings.json and stores.json, shaped like the responses of the Publix savings " +
+		"and store locator APIs, filled with realistic randomized deals and stores. Use them to " +
+		"serve a fake upstream in tests and benchmarks. The same --seed and --week-start always " +
+		"write the same files; --week-start defaults to the start of the current ad week.",
+	Example: `  pubcli fixtures generate --deals 5000
+  pubcli fixtures generate --deals 200 --store-count 5 --seed 42 --week-start 2026-10-14 --out testdata`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runFixturesGenerate,
+}
+
+func init() {
+	rootCmd.AddCommand(fixturesCmd)
+	fixturesCmd.AddCommand(fixturesGenerateCmd)
+	fixturesGenerateCmd.Flags().IntVar(&flagFixturesDeals, "deals", 500, "Number of deals to generate")
+	fixturesGenerateCmd.Flags().IntVar(&flagFixturesStores, "store-count", 10, "Number of stores to generate")
+	fixturesGenerateCmd.Flags().Uint64Var(&flagFixturesSeed, "seed", 1, "Random seed; the same seed writes the same files")
+	fixturesGenerateCmd.Flags().StringVar(&flagFixturesOut, "out", "fixtures", "Directory to write the files to")
+	fixturesGenerateCmd.Flags().StringVar(&flagFixturesWeekStart, "week-start", "", "First day of the ad week the deals run, as YYYY-MM-DD")
+}
+
+// fixturesJSON is the --json output of `fixtures generate`.
+type fixturesJSON struct {
+	Files     []string `json:"files"`
+	Deals     int      `json:"deals"`
+	Stores    int      `json:"stores"`
+	Seed      uint64   `json:"seed"`
+	WeekStart string   `json:"weekStart"`
+}
+
+func runFixturesGenerate(cmd *cobra.Command, _ []string) error {
+	if flagFixturesDeals < 0 || flagFixturesDeals > fixturesMaxDeals {
+		return invalidArgsError(
+			fmt.Sprintf("--deals must be between 0 and %d", fixturesMaxDeals),
+			"pubcli fixtures generate --deals 5000",
+		)
+	}
+	if flagFixturesStores < 0 || flagFixturesStores > fixtures.MaxStores {
+		return invalidArgsError(
+			fmt.Sprintf("--store-count must be between 0 and %d", fixtures.MaxStores),
+			"pubcli fixtures generate --store-count 10",
+		)
+	}
+	if strings.TrimSpace(flagFixturesOut) == "" {
+		return invalidArgsError("--out must name a directory", "pubcli fixtures generate --out testdata")
+	}
+	week := history.WeekStart(time.Now())
+	if raw := strings.TrimSpace(flagFixturesWeekStart); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return invalidArgsError(
+				fmt.Sprintf("invalid --week-start %q (use YYYY-MM-DD)", flagFixturesWeekStart),
+				"pubcli fixtures generate --week-start 2026-10-14",
+			)
+		}
+		week = parsed
+	}
+
+	paths, err := fixtures.Write(flagFixturesOut, fixtures.Options{
+		Deals:     flagFixturesDeals,
+		Stores:    flagFixturesStores,
+		Seed:      flagFixturesSeed,
+		WeekStart: week,
+	})
+	if err != nil {
+		return err
+	}
+
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "fixtures", fixturesJSON{
+			Files:     paths,
+			Deals:     flagFixturesDeals,
+			Stores:    flagFixturesStores,
+			Seed:      flagFixturesSeed,
+			WeekStart: week.Format("2006-01-02"),
+		})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d deals and %d stores (seed %d, week of %s):\n",
+		flagFixturesDeals, flagFixturesStores, flagFixturesSeed, week.Format("2006-01-02"))
+	for _, p := range paths {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", p)
+	}
+	return nil
+}
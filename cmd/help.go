@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Titles of the groups in the root help and in `pubcli examples`.
+const (
+	helpCommonTasks = "Common tasks"
+	helpFilters     = "Filters"
+	helpOutput      = "Output"
+	helpAutomation  = "Automation"
+)
+
+// helpSection is one group of the root help.
+type helpSection struct {
+	Title   string
+	Entries []helpEntry
+}
+
+// helpEntry is a command of the root, such as "ad new", or one of its
+// flags, such as "--bogo", with an example. The summary comes from the
+// command's Short or the flag's usage, so the help cannot drift from them.
+type helpEntry struct {
+	Name    string
+	Example string
+}
+
+var rootHelpSections = []helpSection{
+	{Title: helpCommonTasks, Entries: []helpEntry{
+		{"--zip", "pubcli --zip 33101"},
+		{"--store", "pubcli --store 1425"},
+		{"stores", "pubcli stores --zip 33101"},
+		{"summary", "pubcli summary --zip 33101"},
+		{"ad", "pubcli ad new --store 1425"},
+		{"bogo", "pubcli bogo --store 1425"},
+		{"compare", "pubcli compare --zip 33101 --category produce"},
+		{"list", `pubcli list add eggs && pubcli list match --store 1425`},
+		{"tui", "pubcli tui --store 1425"},
+	}},
+	{Title: helpFilters, Entries: []helpEntry{
+		{"--category", "pubcli --store 1425 --category produce"},
+		{"--department", "pubcli --store 1425 --department deli"},
+		{"--query", "pubcli --store 1425 --query chicken"},
+		{"--bogo", "pubcli --store 1425 --bogo"},
+		{"--min-percent-off", "pubcli --store 1425 --min-percent-off 30"},
+		{"--new-only", "pubcli --store 1425 --new-only"},
+		{"--include-expired", "pubcli --store 1425 --include-expired"},
+	}},
+	{Title: helpOutput, Entries: []helpEntry{
+		{"--sort", "pubcli --store 1425 --sort savings"},
+		{"--limit", "pubcli --store 1425 --limit 10"},
+		{"--offset", "pubcli --store 1425 --limit 20 --offset 20"},
+		{"--group-by", "pubcli --store 1425 --group-by department --limit-per-group 3"},
+		{"--json", "pubcli --store 1425 --json"},
+		{"--format", "pubcli --store 1425 --format markdown"},
+		{"--max-items", "pubcli --store 1425 --json --max-items 25"},
+		{"--accessible", "pubcli --store 1425 --accessible"},
+	}},
+	{Title: helpAutomation, Entries: []helpEntry{
+		{"serve", "pubcli serve --port 8080"},
+		{"batch", `echo '[{"command":"deals","zip":"33101","limit":5}]' | pubcli batch`},
+		{"alert", "pubcli alert add coffee coffee && pubcli alert run --store 1425"},
+		{"watch", "pubcli watch --store 1425 -q chicken --interval 1h"},
+		{"schedule", `pubcli schedule install --weekly "report --weekly --store 1425"`},
+		{"capabilities", "pubcli capabilities --json"},
+		{"--strict", "pubcli --strict --zip 33101 --json"},
+	}},
+}
+
+func init() {
+	defaultHelp := rootCmd.HelpFunc()
+	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		if cmd != rootCmd {
+			defaultHelp(cmd, args)
+			return
+		}
+		printRootHelp(cmd.OutOrStdout(), cmd)
+	})
+}
+
+// printRootHelp writes the curated help of the root command: the grouped
+// sections, then every command and flag they leave out.
+func printRootHelp(w io.Writer, root *cobra.Command) {
+	fmt.Fprintf(w, "%s\n\nUsage:\n  pubcli [flags]\n  pubcli [command] [flags]\n", root.Long)
+
+	covered := map[string]bool{"help": true}
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	for _, section := range rootHelpSections {
+		fmt.Fprintf(tw, "\n%s:\n", section.Title)
+		for _, entry := range section.Entries {
+			name, summary := describeHelpEntry(root, entry.Name)
+			covered[strings.TrimPrefix(entry.Name, "--")] = true
+			fmt.Fprintf(tw, "  %s\t%s\n", name, summary)
+			fmt.Fprintf(tw, "  \te.g. %s\n", entry.Example)
+		}
+	}
+	tw.Flush()
+
+	var more []*cobra.Command
+	for _, c := range root.Commands() {
+		if c.IsAvailableCommand() && !covered[c.Name()] {
+			more = append(more, c)
+		}
+	}
+	sort.Slice(more, func(i, j int) bool { return more[i].Name() < more[j].Name() })
+	if len(more) > 0 {
+		fmt.Fprint(w, "\nMore commands:\n")
+		tw = tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		for _, c := range more {
+			fmt.Fprintf(tw, "  %s\t%s\n", c.Name(), c.Short)
+		}
+		tw.Flush()
+	}
+
+	rest := pflag.NewFlagSet("more", pflag.ContinueOnError)
+	root.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		if !f.Hidden && !covered[f.Name] {
+			rest.AddFlag(f)
+		}
+	})
+	if rest.HasFlags() {
+		fmt.Fprintf(w, "\nMore flags:\n%s", rest.FlagUsages())
+	}
+
+	fmt.Fprint(w, "\nRun `pubcli examples` for copy-pasteable recipes, and `pubcli COMMAND --help` for a command's flags.\n")
+}
+
+// describeHelpEntry returns how a help entry is shown, such as
+// "-c, --category string", and its summary. Unknown names are shown as
+// they are, without a summary.
+func describeHelpEntry(root *cobra.Command, name string) (string, string) {
+	if flagName, ok := strings.CutPrefix(name, "--"); ok {
+		f := root.LocalFlags().Lookup(flagName)
+		if f == nil {
+			return name, ""
+		}
+		varname, usage := pflag.UnquoteUsage(f)
+		shown := "--" + f.Name
+		if f.Shorthand != "" {
+			shown = "-" + f.Shorthand + ", " + shown
+		}
+		if varname != "" {
+			shown += " " + varname
+		}
+		return shown, usage
+	}
+	c, _, err := root.Find(strings.Fields(name))
+	if err != nil || c == root {
+		return name, ""
+	}
+	return name, c.Short
+}
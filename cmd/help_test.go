@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootHelpSections_NameRealCommandsAndFlags(t *testing.T) {
+	for _, section := range rootHelpSections {
+		for _, entry := range section.Entries {
+			_, summary := describeHelpEntry(rootCmd, entry.Name)
+			assert.NotEmpty(t, summary, "%s: %s", section.Title, entry.Name)
+			assert.Contains(t, entry.Example, "pubcli", entry.Name)
+		}
+	}
+}
+
+func TestRunCLI_RootHelpIsGrouped(t *testing.T) {
+	t.Cleanup(resetCLIState)
+	t.Cleanup(func() {
+		help := rootCmd.Flags().Lookup("help")
+		_ = help.Value.Set("false")
+		help.Changed = false
+	})
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"--help"}, &stdout, &stderr)
+	assert.Equal(t, ExitSuccess, code)
+	out := stdout.String()
+	for _, title := range []string{helpCommonTasks, helpFilters, helpOutput, helpAutomation, "More commands", "More flags"} {
+		assert.Contains(t, out, "\n"+title+":\n", title)
+	}
+	assert.Contains(t, out, "e.g. pubcli --store 1425 --bogo")
+	assert.Contains(t, out, "--no-cache", "flags outside the sections are still listed")
+	assert.Contains(t, out, "\n  examples ", "commands outside the sections are still listed")
+
+	stdout.Reset()
+	code = runCLI([]string{"stores", "--help"}, &stdout, &stderr)
+	assert.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), "pubcli stores [flags]", "subcommands keep cobra's help")
+	assert.NotContains(t, stdout.String(), helpCommonTasks+":")
+}
+
+func TestRunCLI_Examples(t *testing.T) {
+	t.Cleanup(resetCLIState)
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"examples", "--format", "markdown"}, &stdout, &stderr)
+	assert.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "## "+helpAutomation+"\n")
+	assert.Contains(t, stdout.String(), "```sh\npubcli stores --zip 33101\npubcli config set default_store 1425\n```")
+
+	stdout.Reset()
+	code = runCLI([]string{"examples", "--format", "text"}, &stdout, &stderr)
+	assert.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), helpFilters+":\n\n  # Search for a keyword\n  pubcli --store 1425 --query chicken\n")
+
+	stdout.Reset()
+	code = runCLI([]string{"examples", "--format", "yaml"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+}
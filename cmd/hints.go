@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+// hintMinDeals is how many deals a text listing shows before a hint
+// suggests a more focused command or flag.
+const hintMinDeals = 50
+
+// dealsHint describes a deal listing for choosing a hint.
+type dealsHint struct {
+	// Shown is how many deals the listing printed.
+	Shown int
+	// Store is the store listed, so suggested commands can be pasted as
+	// is. It is empty for --all-stores.
+	Store string
+	// Interactive is set when stdout is a terminal, where the TUI works.
+	Interactive bool
+}
+
+// text returns the one-line hint for the listing, or "" when it is short
+// enough or the flags given already focus it. Hints look at what was asked
+// for: a plain listing gets other commands, a filtered one --limit and the
+// filters it does not use yet, and a grouped one --limit-per-group.
+func (h dealsHint) text() string {
+	if flagQuiet || flagLimit > 0 || h.Shown <= hintMinDeals {
+		return ""
+	}
+	if flagGroupBy != "" {
+		if flagLimitPerGroup > 0 {
+			return ""
+		}
+		return display.T("hint.grouped", h.Shown)
+	}
+
+	var unused []string
+	for _, f := range []struct {
+		name string
+		used bool
+	}{
+		{"--department", flagDepartment != ""},
+		{"--category", flagCategory != ""},
+		{"--query", flagQuery != ""},
+		{"--bogo", flagBogo},
+	} {
+		if !f.used {
+			unused = append(unused, f.name)
+		}
+	}
+	filtered := len(unused) < 4 || flagMinPercentOff > 0 || flagNewOnly
+	if filtered || h.Store == "" {
+		add := "--sort savings --limit 20"
+		if flagSort != "" {
+			add = "--limit 20"
+		}
+		hint := display.T("hint.filtered", h.Shown, add)
+		if len(unused) > 0 {
+			hint = display.T("hint.narrow", hint, strings.Join(unused, display.T("hint.or")))
+		}
+		return hint
+	}
+
+	var options []string
+	if h.Interactive {
+		options = append(options, display.T("hint.browse", h.Store))
+	}
+	options = append(options,
+		display.T("hint.summary", h.Store),
+		display.T("hint.limit"),
+	)
+	return display.T("hint.plain", h.Shown, joinOptions(options))
+}
+
+// joinOptions joins options as "a, b, or c", in the interface language.
+func joinOptions(options []string) string {
+	if len(options) < 2 {
+		return strings.Join(options, "")
+	}
+	return display.T("hint.or_last", strings.Join(options[:len(options)-1], ", "), options[len(options)-1])
+}
+
+// printDealsHint writes the listing's hint, if any, to w.
+func printDealsHint(w io.Writer, h dealsHint) {
+	if hint := h.text(); hint != "" {
+		fmt.Fprintln(w, hint)
+	}
+}
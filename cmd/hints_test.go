@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func TestDealsHint_SuggestsCommandsForPlainListings(t *testing.T) {
+	t.Cleanup(resetCLIState)
+
+	assert.Empty(t, dealsHint{Shown: hintMinDeals, Store: "1425"}.text(), "short listings get no hint")
+	assert.Equal(t,
+		"hint: 120 deals; browse them with `pubcli tui --store 1425`, see the highlights with `pubcli summary --store 1425`, or add --limit 20",
+		dealsHint{Shown: 120, Store: "1425", Interactive: true}.text())
+	assert.Equal(t,
+		"hint: 120 deals; see the highlights with `pubcli summary --store 1425`, or add --limit 20",
+		dealsHint{Shown: 120, Store: "1425"}.text(), "no TUI when stdout is not a terminal")
+}
+
+func TestDealsHint_FollowsTheFlagsGiven(t *testing.T) {
+	t.Cleanup(resetCLIState)
+	h := dealsHint{Shown: 80, Store: "1425", Interactive: true}
+
+	flagDepartment = "grocery"
+	assert.Equal(t,
+		"hint: 80 deals match; add --sort savings --limit 20 for the best of them, or narrow with --category or --query or --bogo",
+		h.text())
+
+	flagSort = "savings"
+	assert.Equal(t,
+		"hint: 80 deals match; add --limit 20 for the best of them, or narrow with --category or --query or --bogo",
+		h.text())
+
+	flagGroupBy = "department"
+	assert.Equal(t, "hint: 80 deals; add --limit-per-group 5 to show the top of each group", h.text())
+	flagLimitPerGroup = 3
+	assert.Empty(t, h.text())
+
+	resetCLIState()
+	assert.Contains(t, dealsHint{Shown: 80}.text(), "--sort savings --limit 20", "--all-stores has no single store to suggest")
+
+	flagLimit = 100
+	assert.Empty(t, h.text(), "--limit already focuses the listing")
+}
+
+func TestDealsHint_Quiet(t *testing.T) {
+	t.Cleanup(resetCLIState)
+	h := dealsHint{Shown: 80, Store: "1425"}
+
+	flagQuiet = true
+	var buf bytes.Buffer
+	printDealsHint(&buf, h)
+	assert.Empty(t, buf.String())
+
+	flagQuiet = false
+	printDealsHint(&buf, h)
+	assert.Contains(t, buf.String(), "hint: 80 deals")
+}
+
+func TestDealsHint_Translated(t *testing.T) {
+	t.Cleanup(resetCLIState)
+	display.SetLanguage("es")
+
+	assert.Equal(t,
+		"sugerencia: 120 ofertas; vea lo más destacado con `pubcli summary --store 1425` o añada --limit 20",
+		dealsHint{Shown: 120, Store: "1425"}.text())
+}
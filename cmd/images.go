@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/images"
+)
+
+var (
+	flagDownloadImages   string
+	flagImageMaxBytes    int64
+	flagImageConcurrency int
+)
+
+// registerImageFlags adds the flags that save deal images locally.
+func registerImageFlags(f *pflag.FlagSet) {
+	f.StringVar(&flagDownloadImages, "download-images", "", "Save the deals' images into DIR for offline use (reuses earlier downloads)")
+	f.Int64Var(&flagImageMaxBytes, "image-max-bytes", images.DefaultMaxBytes, "With --download-images: skip images larger than N bytes")
+	f.IntVar(&flagImageConcurrency, "image-concurrency", images.DefaultConcurrency, "With --download-images: simultaneous downloads (1-16)")
+}
+
+func validateImageFlags() error {
+	if flagImageMaxBytes < 1 || flagImageConcurrency < 1 || flagImageConcurrency > 16 {
+		return invalidArgsError(
+			"--image-max-bytes must be positive and --image-concurrency between 1 and 16",
+			"pubcli --zip 33101 --download-images ./images --image-concurrency 4",
+		)
+	}
+	return nil
+}
+
+func imageDownloader() *images.Downloader {
+	d := &images.Downloader{
+		Dir:         flagDownloadImages,
+		Concurrency: flagImageConcurrency,
+		MaxBytes:    flagImageMaxBytes,
+		Variants:    api.ImageVariants,
+	}
+	if harRecorder != nil {
+		d.Client = &http.Client{Timeout: 30 * time.Second, Transport: harRecorder}
+	}
+	return d
+}
+
+func dealImageURLs(items []api.SavingItem) []string {
+	urls := make([]string, 0, len(items))
+	for _, item := range items {
+		if u := filter.Deref(item.ImageURL); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// downloadDealImages saves the deals' images and reports the outcome on
+// stderr. Individual failed images do not fail the command.
+func downloadDealImages(ctx context.Context, stderr io.Writer, items []api.SavingItem) error {
+	result, err := imageDownloader().Download(ctx, dealImageURLs(items))
+	if err != nil {
+		return invalidArgsError(err.Error(), "pubcli --zip 33101 --download-images ./images")
+	}
+	fmt.Fprintf(stderr, "Images in %s: %d downloaded, %d already saved", flagDownloadImages, result.Downloaded, result.Cached)
+	if len(result.Failed) > 0 {
+		fmt.Fprintf(stderr, ", %d failed", len(result.Failed))
+	}
+	fmt.Fprintln(stderr)
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/insights"
+)
+
+var insightsCmd = &cobra.Command{
+	Use:   "insights",
+	Short: "Summarize which departments and categories dominate this week's deals",
+	Long: "Report which categories and departments hold this week's BOGO deals, the average deal " +
+		"score of each department, and the largest week-over-week changes in deal counts. Changes " +
+		"compare against the previous ad cycle in the ad history, which `pubcli insights`, " +
+		"`pubcli report`, and `pubcli trends` save to the data directory.",
+	Example: `  pubcli insights --store 1425
+  pubcli insights --zip 33101 --json`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runInsights,
+}
+
+func init() {
+	rootCmd.AddCommand(insightsCmd)
+}
+
+func runInsights(cmd *cobra.Command, _ []string) error {
+	client := newAPIClient()
+	storeNumber, storeLabel, err := resolveStoreForTUI(cmd.Context(), client, flagStore, flagZip)
+	if err != nil {
+		return err
+	}
+	data, err := client.FetchSavings(cmd.Context(), storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+	if len(data.Savings) == 0 {
+		return notFoundError(
+			fmt.Sprintf("no deals found for store #%s", storeNumber),
+			"Try another store with --store.",
+		)
+	}
+
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	snapshots, err := archive.List(storeNumber)
+	if err != nil {
+		return configError(err)
+	}
+	var previous *history.Snapshot
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if data.WeeklyAdLatestUpdatedDateTime == "" || snapshots[i].Updated != data.WeeklyAdLatestUpdatedDateTime {
+			previous = &snapshots[i]
+			break
+		}
+	}
+
+	metrics := insights.Build(storeNumber, storeLabel, data.Savings, previous)
+	if err := archive.Save(history.Snapshot{
+		Store:   storeNumber,
+		Updated: data.WeeklyAdLatestUpdatedDateTime,
+		SavedAt: time.Now(),
+		Deals:   data.Savings,
+	}); err != nil {
+		return configError(err)
+	}
+
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "insights", metrics)
+	}
+	insights.Write(cmd.OutOrStdout(), metrics)
+	return nil
+}
@@ -0,0 +1,857 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+)
+
+var (
+	flagListWallet      bool
+	flagListOutput      string
+	flagListLinksFormat string
+	flagListQty         int
+	flagListNote        string
+	flagListName        string
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Keep a shopping list and take it to the store",
+	Long: "Maintain a shopping list saved in the data directory. Items added while a store is " +
+		"known (--store, --zip, or the config default) are linked to the matching weekly ad deal, " +
+		"so the list shows their savings and end dates. --name picks a separate named list, e.g. " +
+		"for a holiday or another person, instead of the default weekly one.",
+	Example: `  pubcli list add "chicken thighs" --store 1425
+  pubcli list add paper towels
+  pubcli list add eggs --qty 2 --note "for Sunday"
+  pubcli list
+  pubcli list shop
+  pubcli list --name thanksgiving add turkey
+  pubcli list export --wallet -o list.html`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListShow,
+}
+
+var listAddCmd = &cobra.Command{
+	Use:   "add TEXT...",
+	Short: "Add an item, linked to a matching deal when a store is known",
+	Long: "Add an item, linked to a matching deal when a store is known. --qty and --note set how " +
+		"many to buy and a note shown with the item; given for an item already on the list, they " +
+		"update it.",
+	Example: `  pubcli list add "chicken thighs" --store 1425
+  pubcli list add 123456 --qty 2 --note "for Sunday"`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runListAdd,
+}
+
+var listRemoveCmd = &cobra.Command{
+	Use:         "remove NUMBER|NAME",
+	Aliases:     []string{"rm"},
+	Short:       "Remove an item by its number in `list show` or by name",
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListRemove,
+}
+
+var listShowCmd = &cobra.Command{
+	Use:         "show",
+	Short:       "Print the shopping list",
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListShow,
+}
+
+var listClearCmd = &cobra.Command{
+	Use:         "clear",
+	Short:       "Remove every item",
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListClear,
+}
+
+var listExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the list as a checklist, or as an offline page for your phone",
+	Long: "Print the list as a Markdown checklist. With --wallet, write a self-contained, " +
+		"mobile-friendly HTML page instead, headed with the store's name, address, and ad week. " +
+		"Save it to your phone (AirDrop, Files, or any cloud drive) to use it offline in the store; " +
+		"ticked items are remembered by the browser.",
+	Example: `  pubcli list export
+  pubcli list export --wallet --zip 33101 -o list.html`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runListExport,
+}
+
+var listRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Re-match the list with a new weekly ad",
+	Long: "Check the items linked to deals against the current weekly ad, e.g. after a new ad " +
+		"week starts. Items whose deal is still in the ad get its current savings and end date; " +
+		"others are linked to the deal with the most similar title. Items nothing resembles are " +
+		"flagged as no longer on sale, with up to three deals from the same category suggested " +
+		"in their place. The store is --store, --zip, or the one the items were linked at.",
+	Example: `  pubcli list refresh
+  pubcli list refresh --store 1425 --json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runListRefresh,
+}
+
+var listDoneCmd = &cobra.Command{
+	Use:   "done",
+	Short: "Mark the list complete and archive it in the spending log",
+	Long: "Archive the list as a finished shopping trip in the ad history, with the ad week, the " +
+		"store, and the estimated cost and savings, then empty the list. When items were ticked " +
+		"with `pubcli list shop`, only those are archived and the others stay on the list. " +
+		"`pubcli savings` totals the archived trips.",
+	Example: `  pubcli list done
+  pubcli list --name thanksgiving done`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListDone,
+}
+
+var listImportCmd = &cobra.Command{
+	Use:   "import FILE.csv",
+	Short: "Add the items of a CSV file, such as a grocery spreadsheet",
+	Long: "Add one item per row of a CSV file (- reads stdin). The first row names the columns: " +
+		"name (required), qty, and note, in any order and case. Rows already on the list are " +
+		"skipped. With --store, --zip, or a configured default store, items are linked to deals " +
+		"as `list add` does, fetching the weekly ad once.",
+	Example: `  pubcli list import groceries.csv
+  pubcli list --name thanksgiving import menu.csv --store 1425`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runListImport,
+}
+
+var listMatchCmd = &cobra.Command{
+	Use:   "match",
+	Short: "Show which items on the list are on sale this week",
+	Long: "Look up every item on the list in the store's current weekly ad and print the deals " +
+		"each one matches, then the items that are not on sale. An item matches a deal when each " +
+		"of its words starts a word of the deal's title or description, so \"chicken thigh\" " +
+		"finds \"Boneless Chicken Thighs\"; items linked to a deal still in the ad match that deal. " +
+		"The list is not changed. The store is --store, --zip, or the one the items were linked at.",
+	Example: `  pubcli list match --zip 33101
+  pubcli list match --store 1425 --json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runListMatch,
+}
+
+var listLinksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "Print online ordering links for each item",
+	Long: "Print a search link for each item on Publix.com, for curbside pickup, and on Publix " +
+		"Delivery, which is run by Instacart, so the list can be moved into an online cart " +
+		"quickly. Trailing package sizes are left out of the search.",
+	Example: `  pubcli list links
+  pubcli list links --format markdown > cart.md`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListLinks,
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.AddCommand(listAddCmd, listRemoveCmd, listShowCmd, listClearCmd, listExportCmd, listLinksCmd, listShopCmd, listRefreshCmd, listMatchCmd, listDoneCmd, listImportCmd)
+	listCmd.PersistentFlags().StringVar(&flagListName, "name", "", "Use the named list instead of the default one")
+	listAddCmd.Flags().IntVar(&flagListQty, "qty", 0, "How many to buy (default 1)")
+	listAddCmd.Flags().StringVar(&flagListNote, "note", "", "Note shown with the item")
+	listLinksCmd.Flags().StringVar(&flagListLinksFormat, "format", "text", "Output format: text, markdown, or json")
+	listExportCmd.Flags().BoolVar(&flagListWallet, "wallet", false, "Write a mobile-friendly HTML page for offline use in the store")
+	listExportCmd.Flags().StringVarP(&flagListOutput, "output", "o", "", "Write to FILE instead of stdout")
+}
+
+// listName is the list picked with --name, normalized.
+func listName() (string, error) {
+	name := shoplist.NormalizeName(flagListName)
+	if !shoplist.ValidName(name) {
+		return "", invalidArgsError(
+			fmt.Sprintf("invalid list name %q (use up to 40 letters, digits, dashes, and underscores)", flagListName),
+			"pubcli list --name thanksgiving add turkey",
+		)
+	}
+	return name, nil
+}
+
+func shoppingListKey() (string, error) {
+	name, err := listName()
+	if err != nil {
+		return "", err
+	}
+	return shoplist.KeyFor(name), nil
+}
+
+// listLabel names the --name list in messages: "the list" for the default
+// one.
+func listLabel() string {
+	if name := shoplist.NormalizeName(flagListName); name != shoplist.DefaultName {
+		return fmt.Sprintf("the %q list", name)
+	}
+	return "the list"
+}
+
+// emptyListMessage is printed in place of an empty list.
+func emptyListMessage() string {
+	if name := shoplist.NormalizeName(flagListName); name != shoplist.DefaultName {
+		return fmt.Sprintf("The %q list is empty. Add items with `pubcli list --name %s add TEXT`.", name, name)
+	}
+	return "The shopping list is empty. Add items with `pubcli list add TEXT`."
+}
+
+func loadShoppingList() (*shoplist.List, string, error) {
+	key, err := shoppingListKey()
+	if err != nil {
+		return nil, "", err
+	}
+	store, err := dataStore()
+	if err != nil {
+		return nil, "", err
+	}
+	l, err := shoplist.Load(store, key)
+	if err != nil {
+		return nil, "", configError(err)
+	}
+	return l, key, nil
+}
+
+// saveShoppingList stores the list loadShoppingList returned under key.
+func saveShoppingList(l *shoplist.List, key string) error {
+	store, err := dataStore()
+	if err != nil {
+		return err
+	}
+	return l.Save(store, key)
+}
+
+func runListAdd(cmd *cobra.Command, args []string) error {
+	if flagListQty < 0 {
+		return invalidArgsError("--qty must be at least 1", `pubcli list add eggs --qty 2`)
+	}
+	qtySet, noteSet := flagListQty > 0, strings.TrimSpace(flagListNote) != ""
+	l, key, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+
+	text := strings.TrimSpace(strings.Join(args, " "))
+	item := shoplist.Item{Name: text, Qty: flagListQty, Note: strings.TrimSpace(flagListNote), AddedAt: time.Now().UTC()}
+	if flagStore != "" || flagZip != "" {
+		linkListItem(cmd, &item)
+	}
+
+	if existing := l.Existing(item); existing != nil {
+		if !qtySet && !noteSet {
+			fmt.Fprintf(cmd.OutOrStdout(), "%q is already on %s.\n", item.Name, listLabel())
+			return nil
+		}
+		if qtySet {
+			existing.Qty = item.Qty
+		}
+		if noteSet {
+			existing.Note = item.Note
+		}
+		if err := saveShoppingList(l, key); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Updated %s.\n", describeListItem(*existing))
+		return nil
+	}
+	l.Add(item)
+	if err := saveShoppingList(l, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Added %s (%d item(s) on %s).\n", describeListItem(item), len(l.Items), listLabel())
+	return nil
+}
+
+// linkListItem attaches the deal matching the item's text. The item is kept
+// as plain text when the ad cannot be fetched or the text is ambiguous.
+func linkListItem(cmd *cobra.Command, item *shoplist.Item) {
+	if storeNumber, deals, ok := listAd(cmd, "added"); ok {
+		linkListDeal(cmd.ErrOrStderr(), storeNumber, deals, item)
+	}
+}
+
+// listAd fetches the weekly ad of the --store/--zip store for linking items.
+// On failure it prints a note that items are kept as plain text, saying
+// they were verb ("added" or "imported").
+func listAd(cmd *cobra.Command, verb string) (string, []api.SavingItem, bool) {
+	client := newAPIClient()
+	storeNumber, _, err := resolveStoreForTUI(cmd.Context(), client, flagStore, flagZip)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "note: %s; %s as plain text.\n", shellErrorMessage(err), verb)
+		return "", nil, false
+	}
+	data, err := client.FetchSavings(cmd.Context(), storeNumber)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "note: could not fetch the weekly ad (%v); %s as plain text.\n", err, verb)
+		return "", nil, false
+	}
+	return storeNumber, data.Savings, true
+}
+
+// linkListDeal links the item to the one deal its text matches, or notes
+// on stderr why it stays plain text.
+func linkListDeal(stderr io.Writer, storeNumber string, deals []api.SavingItem, item *shoplist.Item) {
+	deal, matches := findListDeal(deals, item.Name)
+	switch {
+	case matches == 0:
+		fmt.Fprintf(stderr, "note: %q is not in store #%s's weekly ad; added as plain text.\n", item.Name, storeNumber)
+		return
+	case matches > 1:
+		fmt.Fprintf(stderr, "note: %d deals match %q; added as plain text. Use more of the deal title to link one.\n", matches, item.Name)
+		return
+	}
+	item.Link(storeNumber, deal)
+}
+
+// findListDeal looks the text up as a deal ID, then as an exact title, then
+// as a title/description search. It returns the deal and how many matched.
+func findListDeal(items []api.SavingItem, text string) (api.SavingItem, int) {
+	for _, item := range items {
+		if item.ID == text || strings.EqualFold(filter.Title(item), text) {
+			return item, 1
+		}
+	}
+	found := filter.Apply(items, filter.Options{Query: text})
+	if len(found) == 1 {
+		return found[0], 1
+	}
+	return api.SavingItem{}, len(found)
+}
+
+// listRefreshJSON is the --json output of `list refresh`.
+type listRefreshJSON struct {
+	Store string               `json:"store"`
+	Items []shoplist.Refreshed `json:"items"`
+}
+
+func runListRefresh(cmd *cobra.Command, _ []string) error {
+	l, key, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	linked := false
+	for _, item := range l.Items {
+		linked = linked || item.DealID != ""
+	}
+	if !linked {
+		return invalidArgsError("no item on the list is linked to a deal", `pubcli list add "chicken thighs" --store 1425`)
+	}
+
+	client := newAPIClient()
+	storeNumber := ""
+	if flagStore == "" && flagZip == "" {
+		storeNumber = listStore(l)
+	}
+	if storeNumber == "" {
+		if storeNumber, err = resolveStore(cmd, client); err != nil {
+			return err
+		}
+	}
+	data, err := fetchSavingsCached(cmd.Context(), client, storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	results := l.Refresh(storeNumber, data.Savings)
+	if err := saveShoppingList(l, key); err != nil {
+		return err
+	}
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "refresh", listRefreshJSON{Store: storeNumber, Items: results})
+	}
+	printListRefresh(cmd.OutOrStdout(), storeNumber, results)
+	return nil
+}
+
+func runListMatch(cmd *cobra.Command, _ []string) error {
+	l, _, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	if len(l.Items) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), emptyListMessage())
+		return nil
+	}
+
+	client := newAPIClient()
+	storeNumber := ""
+	if flagStore == "" && flagZip == "" {
+		storeNumber = listStore(l)
+	}
+	if storeNumber == "" {
+		if storeNumber, err = resolveStore(cmd, client); err != nil {
+			return err
+		}
+	}
+	data, err := fetchSavingsCached(cmd.Context(), client, storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	matches := l.Match(data.Savings)
+	if flagJSON {
+		onSale := 0
+		for _, m := range matches {
+			if m.OnSale() {
+				onSale++
+			}
+		}
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "match",
+			listMatchJSON{Store: storeNumber, OnSale: onSale, Items: matches})
+	}
+	printListMatch(cmd.OutOrStdout(), storeNumber, matches)
+	return nil
+}
+
+// listMatchJSON is the --json output of `list match`.
+type listMatchJSON struct {
+	Store string `json:"store"`
+	// OnSale counts the items that matched at least one deal.
+	OnSale int                `json:"onSale"`
+	Items  []shoplist.Matched `json:"items"`
+}
+
+func printListMatch(w io.Writer, store string, matches []shoplist.Matched) {
+	var onSale, notOnSale []shoplist.Matched
+	for _, m := range matches {
+		if m.OnSale() {
+			onSale = append(onSale, m)
+		} else {
+			notOnSale = append(notOnSale, m)
+		}
+	}
+	fmt.Fprintf(w, "%d of %d item(s) on %s are on sale at store #%s.\n", len(onSale), len(matches), listLabel(), store)
+	for _, m := range onSale {
+		fmt.Fprintf(w, "  %s\n", m.Name)
+		for _, d := range m.Deals {
+			line := d.Title
+			if d.Savings != "" {
+				line += " — " + d.Savings
+			}
+			if d.ValidTo != "" {
+				line += " (through " + d.ValidTo + ")"
+			}
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	if len(notOnSale) > 0 {
+		names := make([]string, 0, len(notOnSale))
+		for _, m := range notOnSale {
+			names = append(names, m.Name)
+		}
+		fmt.Fprintf(w, "Not on sale: %s\n", strings.Join(names, ", "))
+	}
+}
+
+// listStore is the store the first linked item was added at.
+func listStore(l *shoplist.List) string {
+	for _, item := range l.Items {
+		if item.Store != "" {
+			return item.Store
+		}
+	}
+	return ""
+}
+
+func printListRefresh(w io.Writer, store string, results []shoplist.Refreshed) {
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	fmt.Fprintf(w, "Checked the list against store #%s's weekly ad: %d still on sale, %d relinked, %d no longer on sale.\n",
+		store, counts[shoplist.RefreshCurrent], counts[shoplist.RefreshRelinked], counts[shoplist.RefreshEnded])
+	for _, r := range results {
+		switch r.Status {
+		case shoplist.RefreshRelinked:
+			fmt.Fprintf(w, "  %s → %s\n", r.Previous, r.Name)
+		case shoplist.RefreshEnded:
+			fmt.Fprintf(w, "  %s is no longer on sale.\n", r.Name)
+			for _, s := range r.Suggestions {
+				line := s.Title
+				if s.Savings != "" {
+					line += " — " + s.Savings
+				}
+				fmt.Fprintf(w, "    try: %s (pubcli list add %s --store %s)\n", line, s.DealID, store)
+			}
+		}
+	}
+}
+
+func runListDone(cmd *cobra.Command, _ []string) error {
+	name, err := listName()
+	if err != nil {
+		return err
+	}
+	l, key, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	if len(l.Items) == 0 {
+		return invalidArgsError("nothing to archive: "+listLabel()+" is empty", "pubcli list add TEXT")
+	}
+
+	bought := l.Items
+	var kept []shoplist.Item
+	if checkedCount(l) > 0 {
+		bought = nil
+		for _, item := range l.Items {
+			if item.Checked {
+				bought = append(bought, item)
+			} else {
+				kept = append(kept, item)
+			}
+		}
+	}
+
+	done := &shoplist.List{Items: bought}
+	totals := done.Totals()
+	now := time.Now()
+	trip := history.Trip{
+		List:        name,
+		Week:        history.WeekStart(now).Format("2006-01-02"),
+		Store:       listStore(done),
+		CompletedAt: now.UTC(),
+		Items:       make([]history.TripItem, 0, len(bought)),
+		Cost:        totals.Cost,
+		Savings:     totals.Savings,
+	}
+	if trip.Store == "" {
+		trip.Store = flagStore
+	}
+	for _, item := range bought {
+		cost, savings := item.Estimate()
+		trip.Items = append(trip.Items, history.TripItem{
+			Name:             item.Name,
+			Qty:              item.Qty,
+			Savings:          item.Savings,
+			EstimatedCost:    cost,
+			EstimatedSavings: savings,
+		})
+	}
+
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	if err := archive.AddTrip(trip); err != nil {
+		return configError(err)
+	}
+	l.Items = kept
+	if err := saveShoppingList(l, key); err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Archived %d item(s) from %s for the ad week of %s, saving about %s.\n",
+		len(bought), listLabel(), trip.Week, display.FormatMoney(trip.Savings))
+	if len(kept) > 0 {
+		fmt.Fprintf(out, "%d unticked item(s) stay on %s.\n", len(kept), listLabel())
+	}
+	return nil
+}
+
+// openImportFile opens a file argument of an import command; "-" is stdin.
+func openImportFile(cmd *cobra.Command, name, example string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(cmd.InOrStdin()), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, invalidArgsError(fmt.Sprintf("cannot read %s: %v", name, err), example)
+	}
+	return f, nil
+}
+
+func runListImport(cmd *cobra.Command, args []string) error {
+	f, err := openImportFile(cmd, args[0], "pubcli list import groceries.csv")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	items, err := shoplist.ImportCSV(f, time.Now().UTC())
+	if err != nil {
+		return invalidArgsError(fmt.Sprintf("%s: %v", args[0], err), "The first row must name the columns: "+strings.Join(shoplist.ImportColumns, ","))
+	}
+
+	l, key, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	if flagStore != "" || flagZip != "" {
+		if storeNumber, deals, ok := listAd(cmd, "imported"); ok {
+			for i := range items {
+				linkListDeal(cmd.ErrOrStderr(), storeNumber, deals, &items[i])
+			}
+		}
+	}
+	added := 0
+	for _, item := range items {
+		if l.Add(item) {
+			added++
+		}
+	}
+	if err := saveShoppingList(l, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %d item(s) to %s", added, listLabel())
+	if skipped := len(items) - added; skipped > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "; %d already on it", skipped)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), ".")
+	return nil
+}
+
+func runListRemove(cmd *cobra.Command, args []string) error {
+	l, key, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	removed, err := l.Remove(strings.Join(args, " "))
+	if err != nil {
+		return notFoundError(err.Error(), "pubcli list show")
+	}
+	if err := saveShoppingList(l, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed %s.\n", removed.Name)
+	return nil
+}
+
+func runListClear(cmd *cobra.Command, _ []string) error {
+	l, key, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	n := len(l.Items)
+	l.Items = nil
+	if err := saveShoppingList(l, key); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed %d item(s).\n", n)
+	return nil
+}
+
+func runListShow(cmd *cobra.Command, _ []string) error {
+	l, _, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	if flagJSON {
+		items := l.Items
+		if items == nil {
+			items = []shoplist.Item{}
+		}
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "items", items)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(l.Items) == 0 {
+		fmt.Fprintln(out, emptyListMessage())
+		return nil
+	}
+	for i, item := range l.Items {
+		line := describeListItem(item)
+		if item.Checked {
+			line += " ✓"
+		}
+		fmt.Fprintf(out, "%2d. %s\n", i+1, line)
+	}
+	if line := listTotalsLine(l); line != "" {
+		fmt.Fprintf(out, "\n%s\n", line)
+	}
+	return nil
+}
+
+func describeListItem(item shoplist.Item) string {
+	name := item.Name
+	if qty := item.Quantity(); qty > 1 {
+		name = fmt.Sprintf("%d × %s", qty, name)
+	}
+	var details []string
+	if item.Savings != "" {
+		details = append(details, item.Savings)
+	}
+	if item.ValidTo != "" {
+		details = append(details, "through "+item.ValidTo)
+	}
+	if item.OffSale {
+		details = append(details, "no longer on sale")
+	}
+	if len(details) > 0 {
+		name = fmt.Sprintf("%s — %s", name, strings.Join(details, ", "))
+	}
+	if item.Note != "" {
+		name += " (" + item.Note + ")"
+	}
+	return name
+}
+
+// listTotalsLine summarizes the list's estimated cost and savings, or is
+// empty when no item's savings text states an amount.
+func listTotalsLine(l *shoplist.List) string {
+	t := l.Totals()
+	if t.Priced == 0 && t.Savings == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Estimated total %s for %d of %d item(s) with a price, saving %s.",
+		display.FormatMoney(t.Cost), t.Priced, len(l.Items), display.FormatMoney(t.Savings))
+}
+
+func runListExport(cmd *cobra.Command, _ []string) error {
+	l, _, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	var f *os.File
+	if flagListOutput != "" {
+		f, err = os.Create(flagListOutput)
+		if err != nil {
+			return invalidArgsError(fmt.Sprintf("cannot write %s: %v", flagListOutput, err), "pubcli list export --wallet -o list.html")
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if flagListWallet {
+		err = shoplist.RenderWallet(out, l, listPass(cmd.Context(), cmd.ErrOrStderr(), l))
+	} else {
+		err = writeListChecklist(out, l)
+	}
+	if err != nil {
+		return err
+	}
+	if f != nil {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %d item(s) to %s\n", len(l.Items), flagListOutput)
+	}
+	return nil
+}
+
+func runListLinks(cmd *cobra.Command, _ []string) error {
+	format := strings.ToLower(strings.TrimSpace(flagListLinksFormat))
+	switch format {
+	case "text", "markdown", "md", "json":
+	default:
+		return invalidArgsError(
+			"invalid value for --format (use text, markdown, or json)",
+			"pubcli list links --format markdown",
+		)
+	}
+	if !cmd.Flags().Changed("format") && flagJSON {
+		format = "json"
+	}
+
+	l, _, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	links := shoplist.ItemLinks(l.Items)
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		return display.PrintVersionedJSON(out, "links", links)
+	case "markdown", "md":
+		for _, link := range links {
+			fmt.Fprintf(out, "- %s — [Publix](%s) · [Instacart](%s)\n", link.Name, link.Publix, link.Instacart)
+		}
+	default:
+		if len(links) == 0 {
+			fmt.Fprintln(out, emptyListMessage())
+			return nil
+		}
+		for i, link := range links {
+			fmt.Fprintf(out, "%2d. %s\n    Publix:    %s\n    Instacart: %s\n", i+1, link.Name, link.Publix, link.Instacart)
+		}
+	}
+	return nil
+}
+
+func writeListChecklist(w io.Writer, l *shoplist.List) error {
+	for _, item := range l.Items {
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+		if _, err := fmt.Fprintf(w, "- %s %s\n", box, describeListItem(item)); err != nil {
+			return err
+		}
+	}
+	if line := listTotalsLine(l); line != "" {
+		if _, err := fmt.Fprintf(w, "\n%s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listPass gathers the wallet header: the store from the flags, the config
+// default, or the items themselves, and the ad week from its weekly ad.
+// Lookup failures leave fields empty rather than failing the export.
+func listPass(ctx context.Context, stderr io.Writer, l *shoplist.List) shoplist.Pass {
+	pass := shoplist.Pass{GeneratedAt: time.Now()}
+	storeNumber, zipCode := flagStore, flagZip
+	if storeNumber == "" && zipCode == "" {
+		storeNumber = listStore(l)
+	}
+	if storeNumber == "" && zipCode == "" {
+		return pass
+	}
+
+	client := newAPIClient()
+	if storeNumber == "" {
+		stores, err := client.FetchStores(ctx, zipCode, 1)
+		if err != nil || len(stores) == 0 {
+			fmt.Fprintf(stderr, "note: could not look up a store near %s; exporting without store details.\n", zipCode)
+			return pass
+		}
+		store := stores[0]
+		storeNumber = api.StoreNumber(store.Key)
+		pass.StoreName = fmt.Sprintf("Publix #%s — %s", storeNumber, store.Name)
+		pass.StoreAddress = display.ToStoreJSON(store).Address
+	} else {
+		pass.StoreName = "Publix #" + storeNumber
+	}
+
+	data, err := client.FetchSavings(ctx, storeNumber)
+	if err != nil {
+		fmt.Fprintf(stderr, "note: could not fetch the weekly ad (%v); exporting without the ad week.\n", err)
+		return pass
+	}
+	pass.AdWeek = adWeek(data.Savings)
+	return pass
+}
+
+// adWeek returns the most common start–end range among the ad's deals.
+func adWeek(items []api.SavingItem) string {
+	counts := map[string]int{}
+	best := ""
+	for _, item := range items {
+		if item.StartFormatted == "" || item.EndFormatted == "" {
+			continue
+		}
+		week := display.FormatDealDate(item.StartFormatted) + " – " + display.FormatDealDate(item.EndFormatted)
+		counts[week]++
+		if counts[week] > counts[best] {
+			best = week
+		}
+	}
+	return best
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+)
+
+var listNamesCmd = &cobra.Command{
+	Use:         "names",
+	Short:       "Show the shopping lists and how many items each has",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListNames,
+}
+
+var listRenameCmd = &cobra.Command{
+	Use:         "rename OLD NEW",
+	Short:       "Rename a shopping list",
+	Example:     `  pubcli list rename thanksgiving thanksgiving-2026`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListRename,
+}
+
+var listDeleteCmd = &cobra.Command{
+	Use:         "delete NAME",
+	Short:       "Delete a shopping list and its items",
+	Example:     `  pubcli list delete thanksgiving`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListDelete,
+}
+
+func init() {
+	listCmd.AddCommand(listNamesCmd, listRenameCmd, listDeleteCmd)
+}
+
+// listSummary is one entry of `list names --json`.
+type listSummary struct {
+	Name  string `json:"name"`
+	Items int    `json:"items"`
+}
+
+func runListNames(cmd *cobra.Command, _ []string) error {
+	store, err := dataStore()
+	if err != nil {
+		return err
+	}
+	names, err := shoplist.Names(store)
+	if err != nil {
+		return configError(err)
+	}
+	summaries := make([]listSummary, 0, len(names))
+	for _, name := range names {
+		l, err := shoplist.Load(store, shoplist.KeyFor(name))
+		if err != nil {
+			return configError(err)
+		}
+		summaries = append(summaries, listSummary{Name: name, Items: len(l.Items)})
+	}
+
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "lists", summaries)
+	}
+	for _, s := range summaries {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %d item(s)\n", s.Name, s.Items)
+	}
+	return nil
+}
+
+// validListArg normalizes a list name given as an argument.
+func validListArg(raw string) (string, error) {
+	name := shoplist.NormalizeName(raw)
+	if !shoplist.ValidName(name) {
+		return "", invalidArgsError(
+			fmt.Sprintf("invalid list name %q (use up to 40 letters, digits, dashes, and underscores)", raw),
+			"pubcli list names",
+		)
+	}
+	return name, nil
+}
+
+func runListRename(cmd *cobra.Command, args []string) error {
+	from, err := validListArg(args[0])
+	if err != nil {
+		return err
+	}
+	to, err := validListArg(args[1])
+	if err != nil {
+		return err
+	}
+	store, err := dataStore()
+	if err != nil {
+		return err
+	}
+
+	switch err := shoplist.Rename(store, from, to); {
+	case errors.Is(err, fs.ErrNotExist):
+		return notFoundError(fmt.Sprintf("there is no %q list", from), "pubcli list names")
+	case errors.Is(err, fs.ErrExist):
+		return invalidArgsError(fmt.Sprintf("a %q list already exists", to), "pubcli list delete "+to)
+	case err != nil:
+		return configError(err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Renamed the %q list to %q.\n", from, to)
+	return nil
+}
+
+func runListDelete(cmd *cobra.Command, args []string) error {
+	name, err := validListArg(args[0])
+	if err != nil {
+		return err
+	}
+	store, err := dataStore()
+	if err != nil {
+		return err
+	}
+	switch err := shoplist.Delete(store, name); {
+	case errors.Is(err, fs.ErrNotExist):
+		return notFoundError(fmt.Sprintf("there is no %q list", name), "pubcli list names")
+	case err != nil:
+		return configError(err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Deleted the %q list.\n", name)
+	return nil
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+var listReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Enter what each item of the last finished trip actually cost",
+	Long: "Ask, item by item, what the last trip archived with `pubcli list done` cost at the " +
+		"register, and store the prices with the trip. Paying more than the deal's price counts " +
+		"as that much less saved, so `pubcli savings` reflects the receipt rather than the ad. " +
+		"Press enter to skip an item and q to stop; running it again shows the prices already " +
+		"entered and keeps them on enter.",
+	Example: `  pubcli list reconcile
+  pubcli list --name thanksgiving reconcile`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListReconcile,
+}
+
+func init() {
+	listCmd.AddCommand(listReconcileCmd)
+}
+
+func runListReconcile(cmd *cobra.Command, _ []string) error {
+	name, err := listName()
+	if err != nil {
+		return err
+	}
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	trips, err := archive.Trips()
+	if err != nil {
+		return configError(err)
+	}
+	last := -1
+	for i, trip := range trips {
+		if trip.List == name {
+			last = i
+		}
+	}
+	if last < 0 {
+		return notFoundError(fmt.Sprintf("no finished trip of %s to reconcile", listLabel()), "pubcli list done")
+	}
+
+	trip := &trips[last]
+	if err := reconcileTrip(cmd.InOrStdin(), cmd.OutOrStdout(), trip); err != nil {
+		return err
+	}
+	if err := archive.SaveTrips(trips); err != nil {
+		return configError(err)
+	}
+
+	estimated := 0.0
+	for _, it := range trip.Items {
+		estimated += it.EstimatedSavings
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Paid %s in total; saved about %s (the ad estimated %s).\n",
+		display.FormatMoney(trip.Paid), display.FormatMoney(trip.Savings), display.FormatMoney(estimated))
+	return nil
+}
+
+// reconcileTrip asks for the paid price of each of the trip's items and
+// reconciles the trip with them.
+func reconcileTrip(in io.Reader, out io.Writer, trip *history.Trip) error {
+	where := ""
+	if trip.Store != "" {
+		where = " at store #" + trip.Store
+	}
+	fmt.Fprintf(out, "Trip of %s%s, %d item(s). Enter what each cost in total; enter skips, q stops.\n",
+		trip.CompletedAt.Local().Format("Jan 2, 2006"), where, len(trip.Items))
+
+	scanner := bufio.NewScanner(in)
+items:
+	for i := range trip.Items {
+		it := &trip.Items[i]
+		for {
+			fmt.Fprintf(out, "%s: $", reconcilePrompt(*it))
+			if !scanner.Scan() {
+				fmt.Fprintln(out)
+				if err := scanner.Err(); err != nil {
+					return err
+				}
+				break items
+			}
+			answer := strings.TrimSpace(scanner.Text())
+			if answer == "" {
+				break
+			}
+			if strings.EqualFold(answer, "q") {
+				break items
+			}
+			paid, err := strconv.ParseFloat(strings.TrimPrefix(answer, "$"), 64)
+			if err != nil || paid < 0 {
+				fmt.Fprintf(out, "%q is not a price; enter e.g. 4.99.\n", answer)
+				continue
+			}
+			it.Paid = &paid
+			break
+		}
+	}
+	trip.Reconcile()
+	return nil
+}
+
+// reconcilePrompt describes an item with its deal and the estimated cost,
+// and the price entered before, if any.
+func reconcilePrompt(it history.TripItem) string {
+	text := it.Name
+	if it.Qty > 1 {
+		text = fmt.Sprintf("%d × %s", it.Qty, text)
+	}
+	var details []string
+	if it.Savings != "" {
+		details = append(details, it.Savings)
+	}
+	if it.EstimatedCost > 0 {
+		details = append(details, "estimated "+display.FormatMoney(it.EstimatedCost))
+	}
+	if it.Paid != nil {
+		details = append(details, "entered "+display.FormatMoney(*it.Paid))
+	}
+	if len(details) > 0 {
+		text += " (" + strings.Join(details, ", ") + ")"
+	}
+	return text
+}
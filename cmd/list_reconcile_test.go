@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+func TestReconcileTrip(t *testing.T) {
+	trip := history.Trip{
+		Store:       "1425",
+		CompletedAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local),
+		Items: []history.TripItem{
+			{Name: "Yogurt", Qty: 4, Savings: "2/$5.00", EstimatedCost: 10},
+			{Name: "Coffee", Savings: "Save $3.00", EstimatedSavings: 3},
+			{Name: "paper towels"},
+		},
+	}
+	var out bytes.Buffer
+	require.NoError(t, reconcileTrip(strings.NewReader("abc\n$10.50\n\nq\n"), &out, &trip))
+
+	text := out.String()
+	assert.Contains(t, text, "Trip of Oct 16, 2026 at store #1425, 3 item(s).")
+	assert.Contains(t, text, "4 × Yogurt (2/$5.00, estimated $10.00): $")
+	assert.Contains(t, text, `"abc" is not a price`)
+	require.NotNil(t, trip.Items[0].Paid)
+	assert.InDelta(t, 10.5, *trip.Items[0].Paid, 0.001)
+	assert.Nil(t, trip.Items[1].Paid, "enter skips")
+	assert.True(t, trip.Reconciled)
+	assert.InDelta(t, 10.5, trip.Paid, 0.001)
+	assert.InDelta(t, 3.0, trip.Savings, 0.001, "yogurt cost 50¢ over the estimate, which saved nothing")
+
+	out.Reset()
+	require.NoError(t, reconcileTrip(strings.NewReader(""), &out, &trip))
+	assert.Contains(t, out.String(), "entered $10.50", "prices entered before are shown")
+	assert.InDelta(t, 10.5, *trip.Items[0].Paid, 0.001, "EOF keeps them")
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+)
+
+var listShopCmd = &cobra.Command{
+	Use:   "shop",
+	Short: "Tick items off the list while shopping",
+	Long: "Show the list grouped by department and tick items off as they go into the cart. " +
+		"Every tick is saved right away, so the list can be closed, reopened, or synced with " +
+		"`pubcli sync` mid-trip. The screen is kept small for a phone over SSH or Termux. " +
+		"With --accessible, a numbered prompt replaces the full-screen view.",
+	Example: `  pubcli list shop
+  pubcli list shop --accessible`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runListShop,
+}
+
+var (
+	shopCursorStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229"))
+	shopCheckedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Strikethrough(true)
+	shopErrorStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("203"))
+)
+
+func runListShop(cmd *cobra.Command, _ []string) error {
+	l, key, err := loadShoppingList()
+	if err != nil {
+		return err
+	}
+	if len(l.Items) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), emptyListMessage())
+		return nil
+	}
+	if flagAccessible {
+		return runListShopPrompt(cmd.InOrStdin(), cmd.OutOrStdout(), l, key)
+	}
+	if !isInteractiveSession(cmd.InOrStdin(), cmd.OutOrStdout()) {
+		return invalidArgsError(
+			"`pubcli list shop` requires an interactive terminal",
+			"pubcli list shop --accessible",
+		)
+	}
+
+	program := tea.NewProgram(
+		newListShopModel(l, key),
+		tea.WithAltScreen(),
+		tea.WithInput(cmd.InOrStdin()),
+		tea.WithOutput(tuiOutput(cmd.OutOrStdout())),
+	)
+	finalModel, err := program.Run()
+	if err != nil {
+		return fmt.Errorf("running list shop: %w", err)
+	}
+	if final, ok := finalModel.(listShopModel); ok && final.err != nil {
+		return final.err
+	}
+	return nil
+}
+
+// listShopModel is the full-screen view of `list shop`. Items are shown in
+// shoplist.ShoppingOrder; cursor indexes that order.
+type listShopModel struct {
+	list   *shoplist.List
+	key    string
+	order  []int
+	cursor int
+	width  int
+	height int
+	// err is the last save failure, shown in the footer until a save works.
+	err error
+}
+
+func newListShopModel(l *shoplist.List, key string) listShopModel {
+	return listShopModel{list: l, key: key, order: shoplist.ShoppingOrder(l.Items)}
+}
+
+func (m listShopModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m listShopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.order)-1 {
+				m.cursor++
+			}
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = len(m.order) - 1
+		case " ", "enter", "x":
+			item := &m.list.Items[m.order[m.cursor]]
+			item.Checked = !item.Checked
+			m.err = saveShoppingList(m.list, m.key)
+			// Move on after ticking, as the next item is usually next in the aisle.
+			if item.Checked && m.cursor < len(m.order)-1 {
+				m.cursor++
+			}
+		case "u":
+			for i := range m.list.Items {
+				m.list.Items[i].Checked = false
+			}
+			m.err = saveShoppingList(m.list, m.key)
+		}
+	}
+	return m, nil
+}
+
+func (m listShopModel) View() string {
+	var lines []string
+	cursorLine := 0
+	dept := ""
+	for pos, i := range m.order {
+		item := m.list.Items[i]
+		if d := shoplist.DepartmentOf(item); d != dept || pos == 0 {
+			dept = d
+			lines = append(lines, tuiSectionStyle.Render(dept))
+		}
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+		text := describeListItem(item)
+		switch {
+		case pos == m.cursor:
+			cursorLine = len(lines)
+			text = shopCursorStyle.Render("› " + box + " " + text)
+		case item.Checked:
+			text = "  " + box + " " + shopCheckedStyle.Render(text)
+		default:
+			text = "  " + box + " " + text
+		}
+		lines = append(lines, text)
+	}
+
+	// Keep the cursor on screen below the header and above the footer.
+	if rows := m.height - 2; m.height > 0 && len(lines) > rows && rows > 0 {
+		start := 0
+		if cursorLine >= rows {
+			start = cursorLine - rows + 1
+		}
+		lines = lines[start : start+rows]
+	}
+
+	footer := tuiHintStyle.Render("space tick • j/k move • u untick all • q quit")
+	if m.err != nil {
+		footer = shopErrorStyle.Render("could not save: " + m.err.Error())
+	}
+	header := tuiHeaderStyle.Render("Shopping list") + "  " + tuiMetaStyle.Render(shopProgress(m.list))
+
+	view := strings.Join(append(append([]string{header}, lines...), footer), "\n")
+	if m.width > 0 {
+		view = lipgloss.NewStyle().MaxWidth(m.width).Render(view)
+	}
+	return view
+}
+
+// shopProgress is e.g. "3/10 in cart".
+func shopProgress(l *shoplist.List) string {
+	return fmt.Sprintf("%d/%d in cart", checkedCount(l), len(l.Items))
+}
+
+// checkedCount counts the ticked items.
+func checkedCount(l *shoplist.List) int {
+	checked := 0
+	for _, item := range l.Items {
+		if item.Checked {
+			checked++
+		}
+	}
+	return checked
+}
+
+// runListShopPrompt is `list shop --accessible`: the list printed as plain
+// numbered lines, and a prompt that ticks an item by its number.
+func runListShopPrompt(in io.Reader, out io.Writer, l *shoplist.List, key string) error {
+	order := shoplist.ShoppingOrder(l.Items)
+	printListShopItems(out, l, order)
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "Number to tick or untick, l to list, u to untick all, q to quit: ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		switch answer {
+		case "":
+			continue
+		case "q", "quit":
+			return nil
+		case "l", "list":
+			printListShopItems(out, l, order)
+			continue
+		case "u":
+			for i := range l.Items {
+				l.Items[i].Checked = false
+			}
+			if err := saveShoppingList(l, key); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Unticked everything (%s).\n", shopProgress(l))
+			continue
+		}
+
+		n, err := strconv.Atoi(answer)
+		if err != nil || n < 1 || n > len(order) {
+			fmt.Fprintf(out, "No item %q; enter a number from 1 to %d.\n", answer, len(order))
+			continue
+		}
+		item := &l.Items[order[n-1]]
+		item.Checked = !item.Checked
+		if err := saveShoppingList(l, key); err != nil {
+			return err
+		}
+		verb := "Unticked"
+		if item.Checked {
+			verb = "Ticked"
+		}
+		fmt.Fprintf(out, "%s %s (%s).\n", verb, item.Name, shopProgress(l))
+	}
+}
+
+func printListShopItems(w io.Writer, l *shoplist.List, order []int) {
+	fmt.Fprintf(w, "Shopping list, %s.\n", shopProgress(l))
+	dept := ""
+	for pos, i := range order {
+		item := l.Items[i]
+		if d := shoplist.DepartmentOf(item); d != dept || pos == 0 {
+			dept = d
+			fmt.Fprintf(w, "%s:\n", dept)
+		}
+		state := "not in cart"
+		if item.Checked {
+			state = "in cart"
+		}
+		fmt.Fprintf(w, "%2d. %s, %s\n", pos+1, describeListItem(item), state)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func shopTestList(t *testing.T) (*shoplist.List, storage.Backend) {
+	t.Helper()
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	t.Cleanup(resetCLIState)
+	store, err := dataStore()
+	require.NoError(t, err)
+	l := &shoplist.List{Items: []shoplist.Item{
+		{Name: "paper towels"},
+		{Name: "Bananas", DealID: "1", Department: "Produce"},
+		{Name: "Ground Beef", DealID: "2", Department: "Meat", Qty: 2},
+	}}
+	require.NoError(t, l.Save(store, shoplist.FileName))
+	return l, store
+}
+
+func TestListShopModel_TicksInShoppingOrder(t *testing.T) {
+	l, store := shopTestList(t)
+	var model tea.Model = newListShopModel(l, shoplist.FileName)
+
+	view := model.View()
+	meat := strings.Index(view, "Meat")
+	produce := strings.Index(view, "Produce")
+	other := strings.Index(view, "Other")
+	assert.True(t, meat < produce && produce < other, "grouped by department, free text last")
+	assert.Contains(t, view, "0/3 in cart")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace})
+	assert.Equal(t, 1, model.(listShopModel).cursor, "ticking moves to the next item")
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, model.View(), "2/3 in cart")
+
+	saved, err := shoplist.Load(store, shoplist.FileName)
+	require.NoError(t, err)
+	assert.True(t, saved.Items[2].Checked, "ground beef, first in the meat department")
+	assert.False(t, saved.Items[1].Checked)
+	assert.True(t, saved.Items[0].Checked)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	saved, err = shoplist.Load(store, shoplist.FileName)
+	require.NoError(t, err)
+	for _, item := range saved.Items {
+		assert.False(t, item.Checked)
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+func TestListShopModel_ScrollsToCursor(t *testing.T) {
+	l, _ := shopTestList(t)
+	var model tea.Model = newListShopModel(l, shoplist.FileName)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 40, Height: 4})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnd})
+
+	view := model.View()
+	assert.Contains(t, view, "paper towels")
+	assert.NotContains(t, view, "Ground Beef")
+	assert.Len(t, strings.Split(view, "\n"), 4)
+}
+
+func TestRunListShopPrompt(t *testing.T) {
+	l, store := shopTestList(t)
+	var out bytes.Buffer
+	in := strings.NewReader("3\nx\n3\n1\nq\n")
+	require.NoError(t, runListShopPrompt(in, &out, l, shoplist.FileName))
+
+	text := out.String()
+	assert.Contains(t, text, "Meat:\n 1. 2 × Ground Beef, not in cart\n")
+	assert.Contains(t, text, "Ticked paper towels (1/3 in cart).")
+	assert.Contains(t, text, `No item "x"; enter a number from 1 to 3.`)
+	assert.Contains(t, text, "Unticked paper towels (0/3 in cart).")
+	assert.Contains(t, text, "Ticked Ground Beef (1/3 in cart).")
+
+	saved, err := shoplist.Load(store, shoplist.FileName)
+	require.NoError(t, err)
+	assert.True(t, saved.Items[2].Checked)
+	assert.False(t, saved.Items[0].Checked)
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestFindListDeal(t *testing.T) {
+	items := []api.SavingItem{
+		{ID: "10", Title: strPtr("Boneless Chicken Thighs")},
+		{ID: "11", Title: strPtr("Chicken Breasts")},
+		{ID: "12", Title: strPtr("Oat Milk"), Description: strPtr("Half gallon")},
+	}
+
+	deal, n := findListDeal(items, "chicken thighs")
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "10", deal.ID)
+
+	deal, n = findListDeal(items, "11")
+	assert.Equal(t, 1, n, "deal IDs link directly")
+	assert.Equal(t, "11", deal.ID)
+
+	_, n = findListDeal(items, "chicken")
+	assert.Equal(t, 2, n, "ambiguous text is not linked")
+
+	_, n = findListDeal(items, "caviar")
+	assert.Zero(t, n)
+}
+
+func TestAdWeek(t *testing.T) {
+	items := []api.SavingItem{
+		{StartFormatted: "10/15", EndFormatted: "10/21"},
+		{StartFormatted: "10/15", EndFormatted: "10/21"},
+		{StartFormatted: "10/10", EndFormatted: "10/30"},
+		{},
+	}
+	assert.Equal(t, "10/15 – 10/21", adWeek(items))
+	assert.Empty(t, adWeek(nil))
+}
+
+func TestRunCLI_ListAddQtyAndNote(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"list", "add", "eggs", "--qty", "2", "--note", "for Sunday"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Added 2 × eggs (for Sunday)")
+
+	stdout.Reset()
+	code = runCLI([]string{"list", "add", "Eggs", "--qty", "3"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Updated 3 × eggs (for Sunday).", "existing items are updated, keeping the note")
+
+	stdout.Reset()
+	code = runCLI([]string{"list", "show", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Equal(t, " 1. 3 × eggs (for Sunday)\n", stdout.String())
+
+	stderr.Reset()
+	code = runCLI([]string{"list", "add", "milk", "--qty", "-1"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--qty must be at least 1")
+}
+
+func TestWriteListChecklist_Totals(t *testing.T) {
+	l := &shoplist.List{Items: []shoplist.Item{
+		{Name: "Yogurt", DealID: "1", Savings: "2/$5.00", ValidTo: "10/21", Qty: 4},
+		{Name: "paper towels", Note: "big pack"},
+	}}
+	var buf bytes.Buffer
+	require.NoError(t, writeListChecklist(&buf, l))
+	assert.Equal(t, "- [ ] 4 × Yogurt — 2/$5.00, through 10/21\n"+
+		"- [ ] paper towels (big pack)\n"+
+		"\nEstimated total $10.00 for 1 of 2 item(s) with a price, saving $0.00.\n", buf.String())
+}
+
+func TestPrintListRefresh(t *testing.T) {
+	var buf bytes.Buffer
+	printListRefresh(&buf, "1425", []shoplist.Refreshed{
+		{Name: "Greek Yogurt", Status: shoplist.RefreshCurrent},
+		{Name: "Publix Chicken Thighs", Previous: "Chicken Thighs", Status: shoplist.RefreshRelinked},
+		{Name: "Kettle Chips", Status: shoplist.RefreshEnded, Suggestions: []shoplist.Suggestion{{DealID: "31", Title: "Pretzels", Savings: "Save $2.00"}}},
+	})
+	assert.Equal(t, "Checked the list against store #1425's weekly ad: 1 still on sale, 1 relinked, 1 no longer on sale.\n"+
+		"  Chicken Thighs → Publix Chicken Thighs\n"+
+		"  Kettle Chips is no longer on sale.\n"+
+		"    try: Pretzels — Save $2.00 (pubcli list add 31 --store 1425)\n", buf.String())
+}
+
+func TestPrintListMatch(t *testing.T) {
+	l := &shoplist.List{Items: []shoplist.Item{{Name: "chicken thigh"}, {Name: "oat milk"}, {Name: "eggs"}}}
+	matches := l.Match([]api.SavingItem{
+		{ID: "1", Title: strPtr("Boneless Chicken Thighs"), Savings: strPtr("Save $2.00"), EndFormatted: "10/21"},
+		{ID: "2", Title: strPtr("Goat Cheese")},
+		{ID: "3", Title: strPtr("Eggland's Best Eggs")},
+	})
+	var buf bytes.Buffer
+	printListMatch(&buf, "1425", matches)
+	assert.Equal(t, "2 of 3 item(s) on the list are on sale at store #1425.\n"+
+		"  chicken thigh\n"+
+		"    Boneless Chicken Thighs — Save $2.00 (through 10/21)\n"+
+		"  eggs\n"+
+		"    Eggland's Best Eggs\n"+
+		"Not on sale: oat milk\n", buf.String())
+}
+
+func TestRunCLI_NamedLists(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"list", "--name", "thanksgiving", "add", "turkey"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `Added turkey (1 item(s) on the "thanksgiving" list).`)
+
+	stdout.Reset()
+	code = runCLI([]string{"list", "add", "milk"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "on the list).")
+
+	stdout.Reset()
+	code = runCLI([]string{"list", "names", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Equal(t, "default: 1 item(s)\nthanksgiving: 1 item(s)\n", stdout.String())
+
+	stdout.Reset()
+	code = runCLI([]string{"list", "rename", "thanksgiving", "holiday"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+
+	stdout.Reset()
+	code = runCLI([]string{"list", "show", "--name", "holiday", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Equal(t, " 1. turkey\n", stdout.String())
+
+	stdout.Reset()
+	code = runCLI([]string{"list", "delete", "holiday"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	code = runCLI([]string{"list", "delete", "holiday"}, &stdout, &stderr)
+	assert.Equal(t, ExitNotFound, code)
+
+	stdout.Reset()
+	code = runCLI([]string{"list", "--name", "holiday", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `The "holiday" list is empty.`)
+
+	code = runCLI([]string{"list", "--name", "../x", "add", "milk"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+}
+
+func TestRunCLI_ListImport(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"list", "add", "milk"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+
+	csvPath := filepath.Join(t.TempDir(), "groceries.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("Name,Qty,Note\nMilk,,\neggs,2,large\n"), 0o644))
+	stdout.Reset()
+	code = runCLI([]string{"list", "import", csvPath, "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Imported 1 item(s) to the list; 1 already on it.")
+
+	l, err := shoplist.Load(&storage.Files{Dir: dataDir}, shoplist.FileName)
+	require.NoError(t, err)
+	require.Len(t, l.Items, 2)
+	assert.Equal(t, "eggs", l.Items[1].Name)
+	assert.Equal(t, 2, l.Items[1].Qty)
+	assert.Equal(t, "large", l.Items[1].Note)
+
+	stderr.Reset()
+	code = runCLI([]string{"list", "import", filepath.Join(t.TempDir(), "missing.csv")}, &stdout, &stderr)
+	assert.NotEqual(t, ExitSuccess, code)
+}
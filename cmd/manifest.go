@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/server"
+)
+
+var (
+	flagManifestOpenAI    bool
+	flagManifestServerURL string
+)
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Generate tool definitions for calling a pubcli serve instance",
+	Long: "Print function-calling tool definitions for the deals, categories, stores, and compare " +
+		"endpoints of `pubcli serve`, so hosted agents can call a deployed instance directly. " +
+		"Each tool maps its arguments to the query parameters of one GET endpoint, and every " +
+		"response uses the versioned robot-mode envelope. For GPT Actions, import the server's " +
+		"/openapi.json instead.",
+	Example: `  pubcli manifest --openai --server-url https://deals.example.com
+  pubcli manifest --openai --server-url https://deals.example.com --schema-version 1 | jq .tools`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runManifest,
+}
+
+func init() {
+	rootCmd.AddCommand(manifestCmd)
+	manifestCmd.Flags().BoolVar(&flagManifestOpenAI, "openai", false, "Emit OpenAI function-calling tool definitions")
+	manifestCmd.Flags().StringVar(&flagManifestServerURL, "server-url", "", "Public URL of the pubcli serve instance the tools call")
+}
+
+// openAIManifest is the output of `manifest --openai`. Tools is the list to
+// pass as `tools` to the Chat Completions or Responses API; Endpoints tells
+// the agent's runtime which request each tool call becomes.
+type openAIManifest struct {
+	SchemaVersion int                         `json:"schemaVersion"`
+	Format        string                      `json:"format"`
+	ServerURL     string                      `json:"serverUrl,omitempty"`
+	Tools         []openAITool                `json:"tools"`
+	Endpoints     map[string]manifestEndpoint `json:"endpoints"`
+	Errors        string                      `json:"errors"`
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+type manifestEndpoint struct {
+	Method string `json:"method"`
+	// URL includes the fixed schemaVersion query parameter; tool arguments
+	// are appended as further query parameters.
+	URL         string `json:"url"`
+	ResponseKey string `json:"responseKey"`
+}
+
+func runManifest(cmd *cobra.Command, _ []string) error {
+	if !flagManifestOpenAI {
+		return invalidArgsError(
+			"choose a manifest format; --openai is the only one so far",
+			"pubcli manifest --openai --server-url https://deals.example.com",
+		)
+	}
+	serverURL := strings.TrimRight(strings.TrimSpace(flagManifestServerURL), "/")
+	if serverURL != "" {
+		if u, err := url.Parse(serverURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalidArgsError(
+				fmt.Sprintf("invalid --server-url %q: must be an http(s) URL", flagManifestServerURL),
+				"pubcli manifest --openai --server-url https://deals.example.com",
+			)
+		}
+	}
+
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent("", "  ")
+	return enc.Encode(buildOpenAIManifest(serverURL, display.SchemaVersion()))
+}
+
+func buildOpenAIManifest(serverURL string, schemaVersion int) openAIManifest {
+	manifest := openAIManifest{
+		SchemaVersion: schemaVersion,
+		Format:        "openai",
+		ServerURL:     serverURL,
+		Endpoints:     map[string]manifestEndpoint{},
+		Errors: "Failed calls return a non-2xx status with {\"error\":{\"code\":...,\"message\":...}}; " +
+			"code is one of INVALID_ARGS, NOT_FOUND, UPSTREAM_ERROR, or INTERNAL_ERROR.",
+	}
+	for _, op := range server.Operations() {
+		properties := map[string]any{}
+		required := []string{}
+		for _, p := range op.Parameters {
+			// The endpoint URL pins the schema version so every response
+			// has the envelope documented in the tool description.
+			if p.Name == "schemaVersion" {
+				continue
+			}
+			schema := server.ParameterSchema(p)
+			schema["description"] = p.Description
+			properties[p.Name] = schema
+			if p.Required {
+				required = append(required, p.Name)
+			}
+		}
+
+		description := op.Summary + "."
+		if op.Description != "" {
+			description += " " + op.Description
+		}
+		if schemaVersion >= 2 {
+			description += fmt.Sprintf(" Returns {\"schemaVersion\":%d,%q:...}.", schemaVersion, op.Key)
+		} else {
+			description += fmt.Sprintf(" Returns the %s payload without an envelope.", op.Key)
+		}
+
+		manifest.Tools = append(manifest.Tools, openAITool{
+			Type: "function",
+			Function: openAIFunction{
+				Name:        op.ID,
+				Description: description,
+				Parameters: map[string]any{
+					"type":                 "object",
+					"properties":           properties,
+					"required":             required,
+					"additionalProperties": false,
+				},
+			},
+		})
+		manifest.Endpoints[op.ID] = manifestEndpoint{
+			Method:      "GET",
+			URL:         fmt.Sprintf("%s%s?schemaVersion=%d", serverURL, op.Path, schemaVersion),
+			ResponseKey: op.Key,
+		}
+	}
+	return manifest
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildOpenAIManifest(t *testing.T) {
+	manifest := buildOpenAIManifest("https://deals.example.com", 2)
+
+	names := make([]string, 0, len(manifest.Tools))
+	for _, tool := range manifest.Tools {
+		assert.Equal(t, "function", tool.Type)
+		names = append(names, tool.Function.Name)
+		assert.NotContains(t, tool.Function.Parameters["properties"], "schemaVersion")
+	}
+	assert.Equal(t, []string{"listDeals", "listCategories", "listStores", "compareStores"}, names)
+
+	deals := manifest.Tools[0].Function
+	assert.Contains(t, deals.Description, `Returns {"schemaVersion":2,"deals":...}`)
+	assert.Contains(t, deals.Parameters["properties"], "bogo")
+	assert.Equal(t, []string{"zip"}, manifest.Tools[2].Function.Parameters["required"])
+
+	assert.Equal(t, manifestEndpoint{Method: "GET", URL: "https://deals.example.com/compare?schemaVersion=2", ResponseKey: "stores"},
+		manifest.Endpoints["compareStores"])
+}
+
+func TestRunCLI_ManifestRequiresFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"manifest"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--openai")
+
+	stdout.Reset()
+	stderr.Reset()
+	code = runCLI([]string{"manifest", "--openai", "--server-url", "https://deals.example.com/", "--schema-version", "1"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	var manifest openAIManifest
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &manifest))
+	assert.Equal(t, 1, manifest.SchemaVersion)
+	assert.Equal(t, "https://deals.example.com/deals?schemaVersion=1", manifest.Endpoints["listDeals"].URL)
+}
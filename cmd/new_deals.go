@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/addiff"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+// previousSnapshot returns the newest snapshot of an ad that became valid
+// before start, or nil when none is saved. snapshots are oldest first, as
+// Archive.List returns them; earlier versions of the current ad are
+// skipped, so a mid-week update is still compared with last week's ad.
+func previousSnapshot(snapshots []history.Snapshot, start time.Time) *history.Snapshot {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if adStart(snapshots[i].Deals, snapshots[i].SavedAt).Before(start) {
+			return &snapshots[i]
+		}
+	}
+	return nil
+}
+
+// adStart returns the day an ad became valid: the most common start date
+// among its deals, with yearless dates resolved against saved, or the
+// Wednesday that starts saved's ad week when no deal has a start date.
+func adStart(deals []api.SavingItem, saved time.Time) time.Time {
+	saved = saved.Local()
+	counts := map[time.Time]int{}
+	var best time.Time
+	for _, item := range deals {
+		start, ok := api.ParseDealDate(item.StartFormatted, saved)
+		if !ok {
+			continue
+		}
+		counts[start]++
+		if counts[start] > counts[best] {
+			best = start
+		}
+	}
+	if best.IsZero() {
+		return api.AdWeekStart(saved)
+	}
+	return best
+}
+
+// markNewDeals sets IsNew on the ad's deals by comparing them with the
+// store's previous ad week in the history, then saves the ad there so the next
+// one has something to compare with. It reports whether an earlier ad was
+// saved.
+func markNewDeals(archive history.Archive, storeNumber string, data *api.SavingsResponse) (bool, error) {
+	snapshots, err := archive.List(storeNumber)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	previous := previousSnapshot(snapshots, adStart(data.Savings, now))
+	if previous != nil {
+		addiff.MarkNew(previous.Deals, data.Savings)
+	}
+	err = archive.Save(history.Snapshot{
+		Store:   storeNumber,
+		Updated: data.WeeklyAdLatestUpdatedDateTime,
+		SavedAt: now,
+		Deals:   data.Savings,
+	})
+	return previous != nil, err
+}
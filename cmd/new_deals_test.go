@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestMarkNewDeals_ComparesWithThePreviousAd(t *testing.T) {
+	archive := history.Archive{Backend: &storage.Files{Dir: t.TempDir()}}
+	ad := func() *api.SavingsResponse {
+		return &api.SavingsResponse{WeeklyAdLatestUpdatedDateTime: "b", Savings: []api.SavingItem{
+			{ID: "30", Title: strPtr("Salsa")},
+			{ID: "31", Title: strPtr("nutella")},
+		}}
+	}
+
+	first := ad()
+	found, err := markNewDeals(archive, "1425", first)
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Nil(t, first.Savings[0].IsNew, "nothing to compare the first ad with")
+
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: time.Now().AddDate(0, 0, -7),
+		Deals: []api.SavingItem{{ID: "1", Title: strPtr("Nutella")}, {ID: "2", Title: strPtr("Chips")}}}))
+	data := ad()
+	found, err = markNewDeals(archive, "1425", data)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	items := filter.Apply(data.Savings, filter.Options{NewOnly: true})
+	require.Len(t, items, 1)
+	assert.Equal(t, "Salsa", *items[0].Title)
+	require.NotNil(t, data.Savings[1].IsNew)
+	assert.False(t, *data.Savings[1].IsNew, "deals match by title, not ID")
+}
+
+func TestMarkNewDeals_MidWeekUpdateComparesWithLastWeek(t *testing.T) {
+	archive := history.Archive{Backend: &storage.Files{Dir: t.TempDir()}}
+	now := time.Now()
+	lastWeek := now.AddDate(0, 0, -7).Format("1/2/2006")
+	thisWeek := now.Format("1/2/2006")
+
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: now.AddDate(0, 0, -7),
+		Deals: []api.SavingItem{{ID: "1", Title: strPtr("Nutella"), StartFormatted: lastWeek}}}))
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "b", SavedAt: now.Add(-time.Hour),
+		Deals: []api.SavingItem{{ID: "30", Title: strPtr("Salsa"), StartFormatted: thisWeek}}}))
+
+	data := &api.SavingsResponse{WeeklyAdLatestUpdatedDateTime: "c", Savings: []api.SavingItem{
+		{ID: "30", Title: strPtr("Salsa"), StartFormatted: thisWeek},
+		{ID: "31", Title: strPtr("nutella"), StartFormatted: thisWeek},
+	}}
+	found, err := markNewDeals(archive, "1425", data)
+	require.NoError(t, err)
+	assert.True(t, found)
+	require.NotNil(t, data.Savings[0].IsNew)
+	assert.True(t, *data.Savings[0].IsNew, "new since last week, though already in this week's first version")
+	require.NotNil(t, data.Savings[1].IsNew)
+	assert.False(t, *data.Savings[1].IsNew)
+}
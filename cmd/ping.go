@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that the Publix savings and store APIs are reachable",
+	Long: "Send a HEAD request to the savings and store location endpoints and report each one's " +
+		"status and latency. Exits with UPSTREAM_ERROR (exit code 3) when either endpoint is " +
+		"unreachable or answers with a server error, so scripts can check before a long compare " +
+		"or publish run. The daemon is bypassed; ping always talks to the API.",
+	Example: `  pubcli ping
+  pubcli ping --json && pubcli compare --zip 33101`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runPing,
+}
+
+func init() {
+	rootCmd.AddCommand(pingCmd)
+}
+
+// pingJSON is the --json output of `ping`.
+type pingJSON struct {
+	Up        bool           `json:"up"`
+	Endpoints []pingEndpoint `json:"endpoints"`
+}
+
+type pingEndpoint struct {
+	Name      string  `json:"name"`
+	URL       string  `json:"url"`
+	Up        bool    `json:"up"`
+	Status    int     `json:"status,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func runPing(cmd *cobra.Command, _ []string) error {
+	var opts []api.Option
+	if requestHeaders != nil {
+		opts = append(opts, api.WithHeaders(requestHeaders))
+	}
+	if harRecorder != nil {
+		opts = append(opts, api.WithHAR(harRecorder))
+	}
+	result := buildPing(api.NewClient(opts...).Ping(cmd.Context()))
+
+	if flagJSON {
+		if err := display.PrintVersionedJSON(cmd.OutOrStdout(), "ping", result); err != nil {
+			return err
+		}
+	} else {
+		printPing(cmd.OutOrStdout(), result)
+	}
+
+	if !result.Up {
+		var down []string
+		for _, e := range result.Endpoints {
+			if !e.Up {
+				down = append(down, e.Name)
+			}
+		}
+		return upstreamError("pinging the Publix API", fmt.Errorf("%s endpoint down", strings.Join(down, " and ")))
+	}
+	return nil
+}
+
+func buildPing(health []api.EndpointHealth) pingJSON {
+	result := pingJSON{Up: true, Endpoints: make([]pingEndpoint, 0, len(health))}
+	for _, h := range health {
+		e := pingEndpoint{
+			Name:      h.Name,
+			URL:       h.URL,
+			Up:        h.Up(),
+			Status:    h.Status,
+			LatencyMs: float64(h.Latency.Microseconds()) / 1000,
+		}
+		if h.Err != nil {
+			e.Error = h.Err.Error()
+		}
+		result.Up = result.Up && e.Up
+		result.Endpoints = append(result.Endpoints, e)
+	}
+	return result
+}
+
+func printPing(w io.Writer, result pingJSON) {
+	if display.Accessible() {
+		for _, e := range result.Endpoints {
+			fmt.Fprintf(w, "%s endpoint: %s, %s, %.0f milliseconds.\n", e.Name, pingState(e), pingDetail(e), e.LatencyMs)
+		}
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, e := range result.Endpoints {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.0fms\t%s\n", e.Name, pingState(e), pingDetail(e), e.LatencyMs, e.URL)
+	}
+	tw.Flush()
+}
+
+func pingState(e pingEndpoint) string {
+	if e.Up {
+		return "up"
+	}
+	return "down"
+}
+
+func pingDetail(e pingEndpoint) string {
+	if e.Error != "" {
+		return e.Error
+	}
+	return fmt.Sprintf("HTTP %d", e.Status)
+}
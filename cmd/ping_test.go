@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestBuildPing(t *testing.T) {
+	result := buildPing([]api.EndpointHealth{
+		{Name: "savings", URL: "https://example.com/savings", Status: 200, Latency: 120 * time.Millisecond},
+		{Name: "stores", URL: "https://example.com/stores", Latency: 15 * time.Second, Err: errors.New("timeout")},
+	})
+	assert.False(t, result.Up)
+	assert.True(t, result.Endpoints[0].Up)
+	assert.Equal(t, 120.0, result.Endpoints[0].LatencyMs)
+	assert.Equal(t, "timeout", result.Endpoints[1].Error)
+
+	var out bytes.Buffer
+	printPing(&out, result)
+	assert.Equal(t, "savings  up    HTTP 200  120ms    https://example.com/savings\n"+
+		"stores   down  timeout   15000ms  https://example.com/stores\n", out.String())
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/config"
+)
+
+// profileFromArgs returns the --profile value, which is needed before the
+// config file is read, or fallback when there is none.
+func profileFromArgs(args []string, fallback string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "--profile" && i+1 < len(args) {
+			return strings.TrimSpace(args[i+1])
+		}
+		if value, ok := strings.CutPrefix(arg, "--profile="); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return strings.TrimSpace(fallback)
+}
+
+// completeProfiles completes --profile with the profiles already in use.
+func completeProfiles(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	names, err := config.Profiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
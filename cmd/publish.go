@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/publish"
+)
+
+var (
+	flagPublishOut     string
+	flagPublishCount   int
+	flagPublishBaseURL string
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Generate a static website mirroring this week's ad",
+	Long: "Write a read-only static site with an HTML page, JSON files, and an RSS feed per " +
+		"store, so others can browse the deals without installing pubcli. The output works " +
+		"as-is on GitHub Pages or any static host; rerun it each ad week (e.g. from CI or cron) " +
+		"to refresh it. With --zip, the --count nearest stores are published.",
+	Example: `  pubcli publish --store 1425 --out ./site
+  pubcli publish --zip 33101 --count 3 --out ./docs --base-url https://me.github.io/deals/`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runPublish,
+}
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+	publishCmd.Flags().StringVar(&flagPublishOut, "out", "site", "Directory to write the site to")
+	publishCmd.Flags().IntVar(&flagPublishCount, "count", 1, "Number of nearby stores to publish with --zip (1-10)")
+	publishCmd.Flags().StringVar(&flagPublishBaseURL, "base-url", "", "Public URL of the site, for absolute links in RSS feeds")
+}
+
+// publishJSON is the --json output of `publish`.
+type publishJSON struct {
+	Out    string   `json:"out"`
+	Stores []string `json:"stores"`
+}
+
+func runPublish(cmd *cobra.Command, _ []string) error {
+	if strings.TrimSpace(flagPublishOut) == "" {
+		return invalidArgsError("--out must name a directory", "pubcli publish --store 1425 --out ./site")
+	}
+	if flagPublishCount < 1 || flagPublishCount > 10 {
+		return invalidArgsError("--count must be between 1 and 10", "pubcli publish --zip 33101 --count 3")
+	}
+
+	client := newAPIClient()
+	stores, err := publishStores(cmd, client)
+	if err != nil {
+		return err
+	}
+
+	site := publish.Site{
+		BaseURL:       strings.TrimSpace(flagPublishBaseURL),
+		GeneratedAt:   time.Now(),
+		SchemaVersion: display.SchemaVersion(),
+	}
+	numbers := make([]string, 0, len(stores))
+	for _, store := range stores {
+		data, err := client.FetchSavings(cmd.Context(), store.Number)
+		if err != nil {
+			return upstreamError(fmt.Sprintf("fetching deals for store #%s", store.Number), err)
+		}
+		site.Stores = append(site.Stores, publish.StoreAd{Store: store, Updated: data.WeeklyAdLatestUpdatedDateTime, Deals: data.Savings})
+		numbers = append(numbers, store.Number)
+	}
+
+	if err := publish.Write(flagPublishOut, site); err != nil {
+		return err
+	}
+
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "publish", publishJSON{Out: flagPublishOut, Stores: numbers})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Published %d store(s) to %s: %s\n", len(numbers), flagPublishOut, strings.Join(numbers, ", "))
+	return nil
+}
+
+// publishStores returns the store given with --store, or the --count stores
+// nearest to --zip.
+func publishStores(cmd *cobra.Command, client *api.Client) ([]display.StoreJSON, error) {
+	if flagStore != "" {
+		return []display.StoreJSON{{Number: flagStore}}, nil
+	}
+	if flagZip == "" {
+		return nil, invalidArgsError(
+			"please provide --store NUMBER or --zip ZIPCODE",
+			"pubcli publish --store 1425 --out ./site",
+			"pubcli publish --zip 33101 --count 3 --out ./site",
+		)
+	}
+	found, err := client.FetchStores(cmd.Context(), flagZip, flagPublishCount)
+	if err != nil {
+		return nil, upstreamError("finding stores", err)
+	}
+	if len(found) == 0 {
+		return nil, notFoundError(fmt.Sprintf("no Publix stores found near %s", flagZip), "Try a nearby ZIP code.")
+	}
+	stores := make([]display.StoreJSON, 0, len(found))
+	for _, s := range found {
+		stores = append(stores, display.ToStoreJSON(s))
+	}
+	return stores, nil
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/report"
+	"github.com/tayloree/publix-deals/internal/search"
+)
+
+const reportDefaultEndingWithin = 72 * time.Hour
+
+var (
+	flagReportWeekly       bool
+	flagReportFormat       string
+	flagReportEndingWithin time.Duration
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Consolidated weekly report of watched items for cron",
+	Long: "Compose one report from the weekly ad: watchlist changes since the previous ad cycle, " +
+		"alert rule matches, deals about to expire, and watched deals at their best savings so far. " +
+		"Each run saves the ad to the history in the data directory, which the next cycle is " +
+		"compared against, so run it once per ad cycle from cron or a systemd timer:\n\n" +
+		"  0 9 * * 3 pubcli report --weekly --store 1425 --format markdown\n\n" +
+		"An explicit --format takes precedence over the automatic JSON used when stdout is not a terminal.",
+	Example: `  pubcli report --weekly --store 1425
+  pubcli report --weekly --zip 33101 --format markdown > report.md
+  pubcli report --weekly --ending-within 48h --json`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().BoolVar(&flagReportWeekly, "weekly", false, "Report on the current ad cycle against the previous one")
+	reportCmd.Flags().StringVar(&flagReportFormat, "format", "text", "Output format: text, markdown, or json")
+	reportCmd.Flags().DurationVar(&flagReportEndingWithin, "ending-within", reportDefaultEndingWithin, "List deals that expire within this long")
+}
+
+func runReport(cmd *cobra.Command, _ []string) error {
+	if !flagReportWeekly {
+		return invalidArgsError(
+			"choose a report period; --weekly is the only one so far",
+			"pubcli report --weekly --store 1425",
+		)
+	}
+	format := strings.ToLower(strings.TrimSpace(flagReportFormat))
+	switch format {
+	case "text", "markdown", "md", "json":
+	default:
+		return invalidArgsError(
+			"invalid value for --format (use text, markdown, or json)",
+			"pubcli report --weekly --format markdown",
+		)
+	}
+	if !cmd.Flags().Changed("format") && flagJSON {
+		format = "json"
+	}
+	if flagReportEndingWithin < 0 {
+		return invalidArgsError("--ending-within must not be negative", "pubcli report --weekly --ending-within 48h")
+	}
+
+	rules, err := alertRules(activeConfig.Alerts)
+	if err != nil {
+		return err
+	}
+
+	client := newAPIClient()
+	storeNumber, storeLabel, err := resolveStoreForTUI(cmd.Context(), client, flagStore, flagZip)
+	if err != nil {
+		return err
+	}
+	data, err := fetchSavingsCached(cmd.Context(), client, storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	snapshots, err := archive.List(storeNumber)
+	if err != nil {
+		return configError(err)
+	}
+	var past []history.Snapshot
+	for _, s := range snapshots {
+		if data.WeeklyAdLatestUpdatedDateTime == "" || s.Updated != data.WeeklyAdLatestUpdatedDateTime {
+			past = append(past, s)
+		}
+	}
+
+	now := time.Now()
+	weekly := report.Build(report.Input{
+		Store:        storeNumber,
+		StoreName:    storeLabel,
+		Updated:      data.WeeklyAdLatestUpdatedDateTime,
+		Deals:        data.Savings,
+		Rules:        rules,
+		History:      past,
+		EndingWithin: flagReportEndingWithin,
+		Now:          now,
+	})
+	if err := archive.Save(history.Snapshot{
+		Store:   storeNumber,
+		Updated: data.WeeklyAdLatestUpdatedDateTime,
+		SavedAt: now,
+		Deals:   data.Savings,
+	}); err != nil {
+		return configError(err)
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		return display.PrintVersionedJSON(out, "report", weekly)
+	case "markdown", "md":
+		report.Write(out, weekly, true)
+	default:
+		report.Write(out, weekly, false)
+	}
+	return nil
+}
+
+// adArchive opens the ad history in the storage backend, keeping the weeks
+// retention.history_weeks allows and the search index up to date.
+func adArchive() (history.Archive, error) {
+	store, err := dataStore()
+	if err != nil {
+		return history.Archive{}, err
+	}
+	archive := history.Archive{Backend: store, KeepWeeks: activeConfig.Retention.KeepWeeks()}
+	if idx, err := search.Open(store); err == nil {
+		archive.Index = idx
+	}
+	return archive, nil
+}
+
+// fetchSavingsCached fetches a store's ad through the weekly ad cache after
+// checking that the cached copy, if any, is still the ad Publix serves:
+// scheduled runs reuse an unchanged ad without downloading it again, and
+// pick up an ad updated mid-week. The check only saves bandwidth, so it
+// failing leaves the cached copy in use.
+func fetchSavingsCached(ctx context.Context, client *api.Client, storeNumber string) (*api.SavingsResponse, error) {
+	_ = client.RevalidateCachedAd(ctx, storeNumber)
+	return client.FetchSavings(ctx, storeNumber)
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/cmdhistory"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+var (
+	flagHistoryLimit int
+	flagHistoryClear bool
+
+	// rerunArgs are the arguments `rerun` or `last` chose; runCLI runs them
+	// once the current command returns.
+	rerunArgs []string
+)
+
+const historyDefaultLimit = 20
+
+var historyCmd = &cobra.Command{
+	Use:         "history",
+	Short:       "Show pubcli's own history",
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var historyCmdsCmd = &cobra.Command{
+	Use:   "cmds",
+	Short: "List recent pubcli commands, numbered for `pubcli rerun`",
+	Long: "List the pubcli commands that succeeded, oldest first, numbered for `pubcli rerun N`. " +
+		"The last " + strconv.Itoa(cmdhistory.MaxEntries) + " are kept in " + cmdhistory.FileName +
+		" in the data directory; a command repeated right after itself is kept once. `config`, " +
+		"`secret`, and commands with --header are not recorded, since they may carry secrets.",
+	Example: `  pubcli history cmds
+  pubcli history cmds --limit 0
+  pubcli history cmds --clear`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runHistoryCmds,
+}
+
+var rerunCmd = &cobra.Command{
+	Use:   "rerun N",
+	Short: "Run command N of `pubcli history cmds` again",
+	Long: "Run a command from `pubcli history cmds` again, as if it were typed. The command is " +
+		"printed to stderr first.",
+	Example: `  pubcli history cmds
+  pubcli rerun 12`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runRerun,
+}
+
+var lastCmd = &cobra.Command{
+	Use:         "last",
+	Short:       "Run the last pubcli command again, like !! in a shell",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runLast,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd, rerunCmd, lastCmd)
+	historyCmd.AddCommand(historyCmdsCmd)
+	historyCmdsCmd.Flags().IntVarP(&flagHistoryLimit, "limit", "n", historyDefaultLimit, "Show the most recent N commands (0 = all)")
+	historyCmdsCmd.Flags().BoolVar(&flagHistoryClear, "clear", false, "Forget every recorded command")
+}
+
+// unrecordedCommands are not added to the command history: rerunning them
+// is not useful, or their arguments may hold secrets.
+var unrecordedCommands = map[string]bool{
+	"history":                       true,
+	"rerun":                         true,
+	"last":                          true,
+	"config":                        true,
+	"secret":                        true,
+	"completion":                    true,
+	"help":                          true,
+	cobra.ShellCompRequestCmd:       true,
+	cobra.ShellCompNoDescRequestCmd: true,
+}
+
+// recordCommand adds a successful invocation to the command history.
+// Failing to record it does not fail the command.
+func recordCommand(args []string) {
+	if len(args) == 0 || hasHelpRequest(args) || unrecordedCommands[firstCommand(args)] {
+		return
+	}
+	for _, arg := range args {
+		if arg == "--header" || strings.HasPrefix(arg, "--header=") {
+			return
+		}
+	}
+	path, err := config.DataPath(cmdhistory.FileName)
+	if err != nil {
+		return
+	}
+	_ = cmdhistory.Append(path, cmdhistory.Entry{Args: args, At: time.Now()})
+}
+
+func loadCommandHistory() ([]cmdhistory.Entry, string, error) {
+	path, err := config.DataPath(cmdhistory.FileName)
+	if err != nil {
+		return nil, "", configError(err)
+	}
+	entries, err := cmdhistory.Load(path)
+	if err != nil {
+		return nil, "", configError(err)
+	}
+	return entries, path, nil
+}
+
+// historyCmdJSON is one command in `history cmds --json`.
+type historyCmdJSON struct {
+	N       int       `json:"n"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args"`
+	At      time.Time `json:"at"`
+}
+
+func runHistoryCmds(cmd *cobra.Command, _ []string) error {
+	if flagHistoryLimit < 0 {
+		return invalidArgsError("--limit must be >= 0", "pubcli history cmds --limit 50")
+	}
+	entries, path, err := loadCommandHistory()
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if flagHistoryClear {
+		if err := cmdhistory.Clear(path); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Forgot %d command(s).\n", len(entries))
+		return nil
+	}
+
+	first := 0
+	if flagHistoryLimit > 0 && len(entries) > flagHistoryLimit {
+		first = len(entries) - flagHistoryLimit
+	}
+	if flagJSON {
+		cmds := make([]historyCmdJSON, 0, len(entries)-first)
+		for i := first; i < len(entries); i++ {
+			e := entries[i]
+			cmds = append(cmds, historyCmdJSON{N: i + 1, Command: e.Command(), Args: e.Args, At: e.At})
+		}
+		return display.PrintVersionedJSON(out, "commands", cmds)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No commands recorded yet.")
+		return nil
+	}
+	width := len(strconv.Itoa(len(entries)))
+	for i := first; i < len(entries); i++ {
+		e := entries[i]
+		fmt.Fprintf(out, "%*d  %s  %s\n", width, i+1, e.At.Local().Format("Jan 2 15:04"), e.Command())
+	}
+	return nil
+}
+
+func runRerun(cmd *cobra.Command, args []string) error {
+	entries, _, err := loadCommandHistory()
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > len(entries) {
+		if len(entries) == 0 {
+			return notFoundError("no commands recorded yet", "pubcli --zip 33101")
+		}
+		return invalidArgsError(
+			fmt.Sprintf("no command %s in the history (use 1 to %d)", args[0], len(entries)),
+			"pubcli history cmds",
+		)
+	}
+	return scheduleRerun(cmd, entries[n-1])
+}
+
+func runLast(cmd *cobra.Command, _ []string) error {
+	entries, _, err := loadCommandHistory()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return notFoundError("no commands recorded yet", "pubcli --zip 33101")
+	}
+	return scheduleRerun(cmd, entries[len(entries)-1])
+}
+
+// scheduleRerun prints the command and has runCLI run it next.
+func scheduleRerun(cmd *cobra.Command, e cmdhistory.Entry) error {
+	fmt.Fprintln(cmd.ErrOrStderr(), e.Command())
+	rerunArgs = e.Args
+	return nil
+}
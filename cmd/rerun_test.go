@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestRunCLI_HistoryAndRerun(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := runCLI(args, &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, _, stderr := run("last")
+	assert.Equal(t, ExitNotFound, code)
+	assert.Contains(t, stderr, "no commands recorded yet")
+
+	code, _, stderr = run("list", "add", "eggs")
+	require.Equal(t, ExitSuccess, code, stderr)
+	code, _, stderr = run("list", "add", "ice cream", "--note", "vanilla")
+	require.Equal(t, ExitSuccess, code, stderr)
+	code, _, _ = run("list", "add", "milk", "--qty", "-1")
+	require.Equal(t, ExitInvalidArgs, code)
+	code, _, _ = run("config", "get", "default_store")
+	require.NotEqual(t, ExitSuccess, code)
+
+	code, stdout, _ := run("history", "cmds", "--json=false")
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout, "1  ")
+	assert.Contains(t, stdout, "pubcli list add eggs\n")
+	assert.Contains(t, stdout, "pubcli list add 'ice cream' --note vanilla\n")
+	assert.NotContains(t, stdout, "milk", "failed commands are not recorded")
+	assert.NotContains(t, stdout, "history", "history commands are not recorded")
+
+	code, _, stderr = run("rerun", "1")
+	require.Equal(t, ExitSuccess, code, stderr)
+	assert.Contains(t, stderr, "pubcli list add eggs\n")
+	code, _, stderr = run("last")
+	require.Equal(t, ExitSuccess, code, stderr)
+
+	l, err := shoplist.Load(&storage.Files{Dir: dataDir}, shoplist.FileName)
+	require.NoError(t, err)
+	require.Len(t, l.Items, 2)
+
+	code, _, stderr = run("rerun", "9")
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr, "use 1 to 3")
+
+	code, stdout, _ = run("history", "cmds", "--clear")
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout, "Forgot 3 command(s).")
+}
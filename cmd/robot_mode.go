@@ -5,10 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strings"
+	"time"
 
-	"golang.org/x/term"
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/compare"
+	"github.com/tayloree/publix-deals/internal/console"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
 )
 
 const (
@@ -57,16 +62,69 @@ func notFoundError(message string, suggestions ...string) error {
 }
 
 func upstreamError(action string, err error) error {
+	return upstreamCLIError(fmt.Sprintf("%s: %v", action, err), err)
+}
+
+// upstreamCLIError classifies a failed Publix API call by the api error it
+// wraps.
+func upstreamCLIError(msg string, err error) *cliError {
+	var open *api.CircuitOpenError
+	if errors.As(err, &open) {
+		return &cliError{
+			Code:        "CIRCUIT_OPEN",
+			Message:     msg,
+			Suggestions: []string{fmt.Sprintf("The Publix API kept failing; retry after %s.", open.Until.Format(time.TimeOnly))},
+			ExitCode:    ExitUpstream,
+		}
+	}
+	if errors.Is(err, api.ErrDecode) {
+		return malformedResponseError(msg)
+	}
 	return &cliError{
 		Code:        "UPSTREAM_ERROR",
-		Message:     fmt.Sprintf("%s: %v", action, err),
+		Message:     msg,
 		Suggestions: []string{"Retry in a moment."},
 		ExitCode:    ExitUpstream,
 	}
 }
 
+// malformedResponseError reports an API response pubcli could not read,
+// usually an outage page or a change to the API.
+func malformedResponseError(msg string) *cliError {
+	return &cliError{
+		Code:    "MALFORMED_RESPONSE",
+		Message: msg,
+		Suggestions: []string{
+			"Retry in a moment; the Publix API may be returning an error page.",
+			"If it persists, capture the response with --har FILE and report it.",
+		},
+		ExitCode: ExitUpstream,
+	}
+}
+
+// corruptDataError reports saved ad history that failed its integrity
+// check.
+func corruptDataError(msg string) error {
+	return &cliError{
+		Code:        "CORRUPT_DATA",
+		Message:     msg,
+		Suggestions: []string{"pubcli snapshot verify --repair"},
+		ExitCode:    ExitInternal,
+	}
+}
+
+func configError(err error) error {
+	return &cliError{
+		Code:        "INVALID_ARGS",
+		Message:     fmt.Sprintf("invalid config file: %v", err),
+		Suggestions: []string{"Fix or remove the config file, or point PUBCLI_CONFIG_DIR elsewhere."},
+		ExitCode:    ExitInvalidArgs,
+	}
+}
+
 type jsonErrorPayload struct {
-	Error jsonErrorBody `json:"error"`
+	SchemaVersion int           `json:"schemaVersion,omitempty"`
+	Error         jsonErrorBody `json:"error"`
 }
 
 type jsonErrorBody struct {
@@ -88,9 +146,25 @@ func printCLIErrorJSON(w io.Writer, err *cliError) error {
 			ExitCode:    err.ExitCode,
 		},
 	}
+	if v := display.SchemaVersion(); v >= display.SchemaV2 {
+		payload.SchemaVersion = v
+	}
 	return json.NewEncoder(w).Encode(payload)
 }
 
+// printCLIError writes err to w as JSON or text and returns its exit code.
+func printCLIError(w io.Writer, err *cliError, asJSON bool) int {
+	if !asJSON {
+		fmt.Fprintln(w, formatCLIErrorText(err))
+		return err.ExitCode
+	}
+	if jerr := printCLIErrorJSON(w, err); jerr != nil {
+		fmt.Fprintln(w, formatCLIErrorText(classifyCLIError(jerr)))
+		return ExitInternal
+	}
+	return err.ExitCode
+}
+
 func formatCLIErrorText(err *cliError) string {
 	if err == nil {
 		return ""
@@ -119,15 +193,28 @@ func classifyCLIError(err error) *cliError {
 	}
 
 	msg := strings.TrimSpace(err.Error())
-	lowerMsg := strings.ToLower(msg)
-
 	switch {
+	case errors.Is(err, api.ErrCircuitOpen),
+		errors.Is(err, api.ErrDecode),
+		errors.Is(err, api.ErrUpstreamStatus),
+		errors.Is(err, api.ErrRequest):
+		return upstreamCLIError(msg, err)
+	case errors.Is(err, history.ErrCorrupt):
+		return corruptDataError(msg).(*cliError)
+	case errors.Is(err, api.ErrNoStores),
+		errors.Is(err, compare.ErrNoMatches):
+		return &cliError{
+			Code:     "NOT_FOUND",
+			Message:  msg,
+			ExitCode: ExitNotFound,
+		}
+	// Cobra reports usage errors as plain strings.
 	case strings.Contains(msg, "unknown command"):
 		suggestions := []string{
 			"pubcli stores --zip 33101",
 			"pubcli categories --zip 33101",
 		}
-		if bad := extractUnknownValue(msg, "unknown command"); bad != "" {
+		if bad := extractUnknownValue(msg, "unknown command"); bad != "" && !strictMode {
 			if suggestion, ok := closestMatch(strings.ToLower(bad), knownCommands, 2); ok {
 				suggestions = append([]string{fmt.Sprintf("Did you mean `%s`?", suggestion)}, suggestions...)
 			}
@@ -143,7 +230,7 @@ func classifyCLIError(err error) *cliError {
 			"pubcli --zip 33101",
 			"pubcli --store 1425 --bogo",
 		}
-		if bad := extractUnknownValue(msg, "unknown flag"); bad != "" {
+		if bad := extractUnknownValue(msg, "unknown flag"); bad != "" && !strictMode {
 			trimmed := strings.TrimLeft(bad, "-")
 			if suggestion, ok := resolveFlagName(trimmed); ok {
 				suggestions = append([]string{fmt.Sprintf("Try `--%s`.", suggestion)}, suggestions...)
@@ -164,28 +251,6 @@ func classifyCLIError(err error) *cliError {
 			Suggestions: []string{"pubcli --zip 33101", "pubcli --store 1425"},
 			ExitCode:    ExitInvalidArgs,
 		}
-	case strings.Contains(lowerMsg, "no publix stores found"),
-		strings.Contains(lowerMsg, "no stores found near"),
-		strings.Contains(lowerMsg, "no deals found"),
-		strings.Contains(lowerMsg, "no deals match"):
-		return &cliError{
-			Code:     "NOT_FOUND",
-			Message:  msg,
-			ExitCode: ExitNotFound,
-		}
-	case strings.Contains(lowerMsg, "unexpected status"),
-		strings.Contains(lowerMsg, "executing request"),
-		strings.Contains(lowerMsg, "decoding response"),
-		strings.Contains(lowerMsg, "fetching deals"),
-		strings.Contains(lowerMsg, "fetching savings"),
-		strings.Contains(lowerMsg, "fetching stores"),
-		strings.Contains(lowerMsg, "finding stores"):
-		return &cliError{
-			Code:        "UPSTREAM_ERROR",
-			Message:     msg,
-			Suggestions: []string{"Retry in a moment."},
-			ExitCode:    ExitUpstream,
-		}
 	default:
 		return &cliError{
 			Code:        "INTERNAL_ERROR",
@@ -197,11 +262,11 @@ func classifyCLIError(err error) *cliError {
 }
 
 func isTTY(w io.Writer) bool {
-	file, ok := w.(*os.File)
+	file, ok := console.File(w)
 	if !ok {
 		return false
 	}
-	return term.IsTerminal(int(file.Fd()))
+	return console.IsTerminal(file)
 }
 
 func hasJSONPreference(args []string) bool {
@@ -213,6 +278,56 @@ func hasJSONPreference(args []string) bool {
 	return false
 }
 
+// strictMode is set by --strict for the current run. It turns off argument
+// rewriting, automatic JSON, and did-you-mean suggestions.
+var strictMode bool
+
+// hasStrictFlag reports whether args turn on strict mode. Only the exact
+// spelling counts, since strict mode must be known before any rewriting.
+func hasStrictFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--strict" || arg == "--strict=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStrictArgs rejects arguments that only parse by guessing. A token like
+// -zip would otherwise be read as -z ip.
+func checkStrictArgs(args []string) error {
+	expectingValue := false
+	for _, arg := range args {
+		if expectingValue {
+			expectingValue = false
+			continue
+		}
+		if arg == "--" {
+			return nil
+		}
+		if strings.HasPrefix(arg, "--") {
+			name, rest := splitFlag(strings.TrimPrefix(arg, "--"))
+			if spec, ok := knownFlags[name]; ok && spec.requiresValue && rest == "" {
+				expectingValue = true
+			}
+			continue
+		}
+		if strings.HasPrefix(arg, "-") && len(arg) > 2 {
+			return invalidArgsError(
+				fmt.Sprintf("ambiguous argument %q: long flags take two dashes, and --strict does not combine shorthands", arg),
+				fmt.Sprintf("Use `-%s` or `-%c VALUE`.", arg, arg[1]),
+			)
+		}
+		if len(arg) == 2 && arg[0] == '-' && knownShorthands[arg[1]] {
+			expectingValue = true
+		}
+	}
+	return nil
+}
+
 func hasHelpRequest(args []string) bool {
 	for _, arg := range args {
 		if arg == "-h" || arg == "--help" {
@@ -222,15 +337,27 @@ func hasHelpRequest(args []string) bool {
 	return false
 }
 
+// hasColorAlways reports whether args ask for --color always, which means
+// a person is reading piped output, e.g. through `less -R`.
+func hasColorAlways(args []string) bool {
+	for i, arg := range args {
+		if arg == "--color="+console.ColorAlways || (arg == "--color" && i+1 < len(args) && args[i+1] == console.ColorAlways) {
+			return true
+		}
+	}
+	return false
+}
+
 func shouldAutoJSON(args []string, stdoutIsTTY bool) bool {
 	if stdoutIsTTY || len(args) == 0 {
 		return false
 	}
-	if hasJSONPreference(args) || hasHelpRequest(args) {
+	if hasJSONPreference(args) || hasHelpRequest(args) || hasColorAlways(args) {
 		return false
 	}
 	switch firstCommand(args) {
-	case "completion", "help":
+	case "completion", "help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		// Shell completion always runs with stdout piped to the shell.
 		return false
 	default:
 		return true
@@ -244,7 +371,8 @@ var knownShorthands = map[byte]bool{
 	'c': true, // --category
 	'd': true, // --department
 	'q': true, // --query
-	'n': true, // --limit
+	'n': true, // --limit, top --n
+	'o': true, // list export --output
 }
 
 func firstCommand(args []string) string {
@@ -276,9 +404,10 @@ func firstCommand(args []string) string {
 }
 
 type quickStartJSON struct {
-	Name     string   `json:"name"`
-	Usage    string   `json:"usage"`
-	Examples []string `json:"examples"`
+	SchemaVersion int      `json:"schemaVersion,omitempty"`
+	Name          string   `json:"name"`
+	Usage         string   `json:"usage"`
+	Examples      []string `json:"examples"`
 }
 
 func printQuickStart(w io.Writer, asJSON bool) error {
@@ -293,6 +422,9 @@ func printQuickStart(w io.Writer, asJSON bool) error {
 	}
 
 	if asJSON {
+		if v := display.SchemaVersion(); v >= display.SchemaV2 {
+			help.SchemaVersion = v
+		}
 		return json.NewEncoder(w).Encode(help)
 	}
 
@@ -3,16 +3,25 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/compare"
 )
 
 func TestShouldAutoJSON(t *testing.T) {
 	assert.True(t, shouldAutoJSON([]string{"stores", "--zip", "33101"}, false))
 	assert.False(t, shouldAutoJSON([]string{"stores", "--zip", "33101", "--json"}, false))
 	assert.False(t, shouldAutoJSON([]string{"completion", "zsh"}, false))
+	assert.False(t, shouldAutoJSON([]string{"stores", "--zip", "33101", "--color", "always"}, false))
+	assert.False(t, shouldAutoJSON([]string{"stores", "--zip", "33101", "--color=always"}, false))
+	assert.True(t, shouldAutoJSON([]string{"stores", "--zip", "33101", "--color", "never"}, false))
+	assert.False(t, shouldAutoJSON([]string{"__complete", "stores", "--store-type", ""}, false))
 	assert.False(t, shouldAutoJSON([]string{"--help"}, false))
 	assert.False(t, shouldAutoJSON([]string{"stores", "--zip", "33101"}, true))
 }
@@ -50,3 +59,67 @@ func TestPrintCLIErrorJSON(t *testing.T) {
 	assert.Equal(t, "INVALID_ARGS", errorObject["code"])
 	assert.Equal(t, "bad flag", errorObject["message"])
 }
+
+func TestRunCLI_CapabilitiesJSON(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	code := runCLI([]string{"capabilities", "--json"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	var payload capabilitiesJSON
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &payload))
+
+	network := map[string]bool{}
+	for _, c := range payload.Commands {
+		network[c.Name] = c.RequiresNetwork
+	}
+	assert.True(t, network["stores"])
+	assert.True(t, network["compare"])
+	assert.False(t, network["capabilities"])
+	assert.Equal(t, []string{"relevance", "savings", "ending"}, payload.Enums["sort"])
+	assert.Contains(t, payload.Enums["output"], "json")
+	assert.Len(t, payload.ExitCodes, 8)
+
+	var globals []string
+	for _, f := range payload.GlobalFlags {
+		globals = append(globals, f.Name)
+	}
+	assert.Contains(t, globals, "zip")
+	assert.Contains(t, globals, "json")
+}
+
+func TestUpstreamError_MalformedResponse(t *testing.T) {
+	_, parseErr := api.ParseSavingsResponse([]byte("<html>Service Unavailable</html>"))
+	require.Error(t, parseErr)
+
+	classified := classifyCLIError(upstreamError("fetching deals", fmt.Errorf("fetching savings: %w", parseErr)))
+	assert.Equal(t, "MALFORMED_RESPONSE", classified.Code)
+	assert.Equal(t, ExitUpstream, classified.ExitCode)
+	assert.Equal(t, "fetching deals: fetching savings: malformed savings response: not a JSON object at byte 0", classified.Message)
+
+	unwrapped := classifyCLIError(fmt.Errorf("fetching savings: %w", parseErr))
+	assert.Equal(t, "MALFORMED_RESPONSE", unwrapped.Code)
+}
+
+func TestClassifyCLIError_TypedAPIErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+		exit int
+	}{
+		{"status", fmt.Errorf("fetching savings: %w", &api.StatusError{Code: 503, URL: "https://example.com"}), "UPSTREAM_ERROR", ExitUpstream},
+		{"request", fmt.Errorf("fetching stores: %w", &api.RequestError{URL: "https://example.com", Err: errors.New("timeout")}), "UPSTREAM_ERROR", ExitUpstream},
+		{"circuit open", &api.CircuitOpenError{Failures: 3, Until: time.Now()}, "CIRCUIT_OPEN", ExitUpstream},
+		{"no stores", compare.ErrNoStores, "NOT_FOUND", ExitNotFound},
+		{"other", errors.New("fetching deals went sideways"), "INTERNAL_ERROR", ExitInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classified := classifyCLIError(tt.err)
+			assert.Equal(t, tt.code, classified.Code)
+			assert.Equal(t, tt.exit, classified.ExitCode)
+		})
+	}
+}
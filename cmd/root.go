@@ -1,30 +1,96 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/compare"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/console"
+	"github.com/tayloree/publix-deals/internal/cookies"
+	"github.com/tayloree/publix-deals/internal/daemon"
+	"github.com/tayloree/publix-deals/internal/diag"
 	"github.com/tayloree/publix-deals/internal/display"
 	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/images"
+	"github.com/tayloree/publix-deals/internal/profile"
+	"github.com/tayloree/publix-deals/internal/server"
 )
 
+// allStoresCount is how many nearby stores --all-stores merges, the same
+// stores `pubcli stores` lists.
+const allStoresCount = 5
+
 var (
-	flagStore      string
-	flagZip        string
-	flagCategory   string
-	flagDepartment string
-	flagBogo       bool
-	flagQuery      string
-	flagSort       string
-	flagLimit      int
-	flagJSON       bool
+	flagStore          string
+	flagZip            string
+	flagCategory       string
+	flagDepartment     string
+	flagBogo           bool
+	flagQuery          string
+	flagSort           string
+	flagLimit          int
+	flagIncludeExpired bool
+	flagMinPercentOff  float64
+	flagGroupBy        string
+	flagLimitPerGroup  int
+	flagOffset         int
+	flagNewOnly        bool
+	flagAllStores      bool
+	flagJSON           bool
+	flagAccessible     bool
+	flagMaxItems       int
+	flagMaxBytes       int
+	flagFormat         string
+	flagChart          bool
+
+	flagSchemaVersion int
+	flagLocale        string
+	flagLang          string
+	flagColor         string
+	flagStrict        bool
+	flagHAR           string
+	flagStoreType     string
+	flagHeaders       []string
+
+	flagNoDefaultFilters bool
+	flagProfile          string
+	flagNoCache          bool
+	flagQuiet            bool
 )
 
+// harRecorder captures upstream HTTP traffic for --har; nil when it is off.
+var harRecorder *api.HARRecorder
+
+// requestHeaders are the extra upstream request headers from the config
+// file and --header; nil when there are none.
+var requestHeaders http.Header
+
+// cookieJar is the persistent cookie jar when cookie_jar is on; nil
+// otherwise.
+var cookieJar *cookies.Jar
+
+// householdProfile flags deals for the profile in the config file; nil
+// when there is none.
+var householdProfile *profile.Checker
+
+// storeTypeCodes are the locator type codes parsed from --store-type; empty
+// means the API client's default.
+var storeTypeCodes string
+
+// activeConfig is the user configuration loaded at the start of runCLI.
+var activeConfig = &config.Config{}
+
 var rootCmd = &cobra.Command{
 	Use:   "pubcli",
 	Short: "Fetch current Publix weekly ad deals",
@@ -38,7 +104,9 @@ var rootCmd = &cobra.Command{
   pubcli categories --zip 33101
   pubcli stores --zip 33101 --json
   pubcli compare --zip 33101 --category produce`,
-	RunE: runDeals,
+	Annotations:       map[string]string{annotationNetwork: "true"},
+	PersistentPreRunE: applyConfigDefaults,
+	RunE:              runDeals,
 }
 
 func init() {
@@ -49,51 +117,136 @@ func init() {
 	pf.StringVarP(&flagStore, "store", "s", "", "Publix store number (e.g., 1425)")
 	pf.StringVarP(&flagZip, "zip", "z", "", "Zip code to find nearby stores")
 	pf.BoolVar(&flagJSON, "json", false, "Output as JSON")
+	pf.BoolVar(&flagAccessible, "accessible", false, "Screen-reader-friendly output: no color, box drawing, or two-pane layouts")
+	pf.IntVar(&flagSchemaVersion, "schema-version", 0, "JSON schema version: 1 (legacy bare arrays) or 2 (default, versioned objects)")
+	pf.BoolVar(&flagStrict, "strict", false, "Turn off input auto-correction, automatic JSON, and did-you-mean suggestions; errors are always JSON")
+	pf.StringVar(&flagHAR, "har", "", "Record upstream HTTP traffic to this HAR file, with secrets redacted")
+	pf.StringVar(&flagLocale, "locale", "", "Locale for numbers, dollar amounts, and dates, e.g. en-GB or de-DE (default en-US)")
+	pf.StringVar(&flagColor, "color", console.ColorAuto, "Color styled output: auto, always, or never; auto honors NO_COLOR, CLICOLOR, CLICOLOR_FORCE, and TERM=dumb")
+	_ = rootCmd.RegisterFlagCompletionFunc("color", cobra.FixedCompletions(console.ColorModes, cobra.ShellCompDirectiveNoFileComp))
+	pf.StringVar(&flagLang, "lang", "", "Interface language for labels and messages: "+strings.Join(display.Languages(), " or ")+" (default en)")
+	pf.StringArrayVar(&flagHeaders, "header", nil, `Add a header to Publix API requests, as "Name: value" (repeatable)`)
+	pf.StringVar(&flagStoreType, "store-type", "", "Only find stores of these comma-separated types by --zip: "+storeTypeNames()+" (default all but liquor)")
+	_ = rootCmd.RegisterFlagCompletionFunc("store-type", completeStoreTypes)
+	pf.BoolVar(&flagNoDefaultFilters, "no-default-filters", false, "Show the deals hidden by exclude and profile.hide in config.yaml")
+	pf.BoolVar(&flagNoCache, "no-cache", false, "Fetch weekly ads from the API even when this week's copy is cached")
+	pf.BoolVar(&flagQuiet, "quiet", false, "Don't print hints suggesting other commands or flags")
+	pf.StringVar(&flagProfile, "profile", "", "Use this profile's own config, lists, and history, e.g. work or home (default $"+config.EnvProfile+")")
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+
+	registerDealsFlags(rootCmd.Flags())
+}
 
-	registerDealFilterFlags(rootCmd.Flags())
+// registerDealsFlags adds the flags of the deal listing, which the bare
+// root command and the `ad` views share.
+func registerDealsFlags(f *pflag.FlagSet) {
+	registerDealFilterFlags(f)
+	f.BoolVar(&flagAllStores, "all-stores", false, "Merge the deals of every store near --zip, noting which stores carry each deal")
+	f.StringVar(&flagGroupBy, "group-by", "", "Group deals by department or category")
+	registerLimitPerGroupFlag(f)
+	f.IntVar(&flagOffset, "offset", 0, "Skip the first N matching deals, to page through results with --limit")
+	f.BoolVar(&flagNewOnly, "new-only", false, "Show only deals that were not in the store's previous ad")
+	registerOutputBudgetFlags(f)
+	registerOutputFormatFlag(f)
+	registerImageFlags(f)
 }
 
-// Execute runs the root command.
+// Execute runs the root command on the process's standard streams. It
+// readies Windows consoles for styled output first, dropping Unicode glyphs
+// on legacy code pages; applyConfigDefaults decides on colors.
 func Execute() {
-	os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	caps := console.Prepare(os.Stdout)
+	console.Prepare(os.Stderr)
+	console.Configure(caps)
+	if !caps.Unicode {
+		stdout, stderr = console.NewASCIIWriter(os.Stdout), console.NewASCIIWriter(os.Stderr)
+	}
+	os.Exit(runCLI(os.Args[1:], stdout, stderr))
 }
 
 func runCLI(args []string, stdout, stderr io.Writer) int {
 	resetCLIState()
 
-	normalizedArgs, notes := normalizeCLIArgs(args)
-	for _, note := range notes {
-		fmt.Fprintf(stderr, "note: %s\n", note)
+	// Strict mode is decided from the raw arguments, before any rewriting.
+	strictMode = hasStrictFlag(args)
+	rootCmd.DisableSuggestions = strictMode
+	normalizedArgs := args
+	if !strictMode {
+		var notes []string
+		normalizedArgs, notes = normalizeCLIArgs(args)
+		for _, note := range notes {
+			fmt.Fprintf(stderr, "note: %s\n", note)
+		}
+	}
+	errorsAsJSON := strictMode || hasJSONPreference(normalizedArgs)
+	recorded := append([]string(nil), normalizedArgs...)
+	if strictMode {
+		if err := checkStrictArgs(args); err != nil {
+			return printCLIError(stderr, classifyCLIError(err), true)
+		}
+	}
+
+	if err := config.SetProfile(profileFromArgs(normalizedArgs, os.Getenv(config.EnvProfile))); err != nil {
+		return printCLIError(stderr, classifyCLIError(invalidArgsError(err.Error(), "pubcli --profile work --zip 33101")), errorsAsJSON)
+	}
+
+	cfg, err := config.Load()
+	if err == nil {
+		err = checkConfig(cfg)
+	}
+	if err != nil {
+		// `pubcli config` runs anyway, with the built-in defaults, so it
+		// can report and fix the file.
+		if !managesConfigFile(normalizedArgs) {
+			return printCLIError(stderr, classifyCLIError(configError(err)), errorsAsJSON)
+		}
+		cfg = &config.Config{}
+	}
+	activeConfig = cfg
+	display.SetSchemaVersion(cfg.SchemaVersion)
+	if cfg.Locale != "" {
+		display.SetLocale(cfg.Locale)
+	}
+	if cfg.Lang != "" {
+		display.SetLanguage(cfg.Lang)
+	}
+
+	if len(normalizedArgs) == 0 && shouldLaunchDefaultTUI(activeConfig, isInteractiveSession(os.Stdin, stdout)) {
+		normalizedArgs = []string{"tui"}
 	}
 
 	if len(normalizedArgs) == 0 {
 		if err := printQuickStart(stdout, !isTTY(stdout)); err != nil {
-			cliErr := classifyCLIError(err)
-			fmt.Fprintln(stderr, formatCLIErrorText(cliErr))
-			return cliErr.ExitCode
+			return printCLIError(stderr, classifyCLIError(err), false)
 		}
 		return ExitSuccess
 	}
 
-	if shouldAutoJSON(normalizedArgs, isTTY(stdout)) {
+	if !strictMode && shouldAutoJSON(normalizedArgs, isTTY(stdout)) {
 		normalizedArgs = append(normalizedArgs, "--json")
+		errorsAsJSON = true
 	}
 
+	registerValueCompletions(rootCmd)
 	setCommandIO(rootCmd, stdout, stderr)
 	rootCmd.SetArgs(normalizedArgs)
 
-	if err := rootCmd.Execute(); err != nil {
-		cliErr := classifyCLIError(err)
-		if hasJSONPreference(normalizedArgs) {
-			if jerr := printCLIErrorJSON(stderr, cliErr); jerr != nil {
-				fmt.Fprintln(stderr, formatCLIErrorText(classifyCLIError(jerr)))
-				return ExitInternal
-			}
-		} else {
-			fmt.Fprintln(stderr, formatCLIErrorText(cliErr))
-		}
-		return cliErr.ExitCode
+	err = rootCmd.Execute()
+	closeDataStore()
+	if harErr := saveHAR(); harErr != nil && err == nil {
+		err = harErr
+	}
+	if jarErr := saveCookieJar(); jarErr != nil && err == nil {
+		err = jarErr
+	}
+	if err != nil {
+		return printCLIError(stderr, classifyCLIError(err), errorsAsJSON)
 	}
+	if rerunArgs != nil {
+		return runCLI(rerunArgs, stdout, stderr)
+	}
+	recordCommand(recorded)
 	return ExitSuccess
 }
 
@@ -114,8 +267,285 @@ func resetCLIState() {
 	flagQuery = ""
 	flagSort = ""
 	flagLimit = 0
+	flagIncludeExpired = false
+	flagMinPercentOff = 0
+	flagGroupBy = ""
+	flagLimitPerGroup = 0
+	flagOffset = 0
+	flagNewOnly = false
+	flagAllStores = false
 	flagCompareCount = 5
 	flagJSON = false
+	flagAccessible = false
+	flagMaxItems = 0
+	flagMaxBytes = 0
+	flagFormat = "text"
+	flagChart = false
+	display.SetAccessible(false)
+	flagSchemaVersion = 0
+	display.SetSchemaVersion(display.LatestSchemaVersion)
+	flagLocale = ""
+	display.SetLocale(display.DefaultLocale)
+	flagLang = ""
+	flagColor = console.ColorAuto
+	display.SetLanguage(display.DefaultLanguage)
+	flagStrict = false
+	flagHAR = ""
+	harRecorder = nil
+	flagStoreType = ""
+	flagHeaders = nil
+	flagNoDefaultFilters = false
+	flagProfile = ""
+	_ = config.SetProfile("")
+	flagNoCache = false
+	flagQuiet = false
+	householdProfile = nil
+	requestHeaders = nil
+	cookieJar = nil
+	closeDataStore()
+	flagDataMigrateTo = ""
+	flagDataPruneWeeks = 0
+	flagDataPruneDryRun = false
+	flagSnapshotRepair = false
+	flagDiffFrom = ""
+	flagDiffTo = ""
+	flagDiffFormat = "text"
+	flagWatchInterval = 0
+	flagSearchLimit = searchDefaultLimit
+	flagSearchReindex = false
+	storeTypeCodes = ""
+	strictMode = false
+	rootCmd.DisableSuggestions = false
+	flagTUIScript = ""
+	flagTUIScriptSize = "120x40"
+	flagBatchFile = ""
+	flagDaemonSocket = ""
+	flagDaemonRefresh = daemon.DefaultRefresh
+	flagServeAddr = "127.0.0.1:8080"
+	flagServePort = 0
+	flagServeMaxAge = server.DefaultMaxAge
+	flagStatusFormat = "text"
+	flagStatusNewFor = statusDefaultNewFor
+	flagAlertDryRun = false
+	flagAlertStores = nil
+	flagAlertMaxPrice = 0
+	flagAlertRenotify = false
+	flagTrackBelow = 0
+	flagListWallet = false
+	flagListOutput = ""
+	flagListLinksFormat = "text"
+	flagListQty = 0
+	flagListNote = ""
+	flagListName = ""
+	flagSavingsYear = 0
+	flagFixturesDeals = 500
+	flagFixturesStores = 10
+	flagFixturesSeed = 1
+	flagFixturesOut = "fixtures"
+	flagFixturesWeekStart = ""
+	flagPublishOut = "site"
+	flagPublishCount = 1
+	flagPublishBaseURL = ""
+	flagManifestOpenAI = false
+	flagManifestServerURL = ""
+	flagReportWeekly = false
+	flagReportFormat = "text"
+	flagExamplesFormat = "text"
+	flagReportEndingWithin = reportDefaultEndingWithin
+	flagSummaryEndingWithin = reportDefaultEndingWithin
+	flagScheduleHourly = false
+	flagScheduleDaily = false
+	flagScheduleWeekly = false
+	flagScheduleDay = "wed"
+	flagScheduleAt = "09:00"
+	flagScheduleName = ""
+	flagScheduleDryRun = false
+	flagTrendsWeeks = trendsDefaultWeeks
+	flagTopN = topDefaultN
+	flagTopBy = "score"
+	flagDownloadImages = ""
+	flagImageMaxBytes = images.DefaultMaxBytes
+	flagImageConcurrency = images.DefaultConcurrency
+	flagHistoryLimit = historyDefaultLimit
+	flagHistoryClear = false
+	rerunArgs = nil
+	activeConfig = &config.Config{}
+}
+
+// saveHAR writes the --har capture, which is kept even when the command
+// failed, since failures are what captures are for.
+func saveHAR() error {
+	if harRecorder == nil {
+		return nil
+	}
+	if err := harRecorder.Save(flagHAR); err != nil {
+		return invalidArgsError(fmt.Sprintf("writing --har file: %v", err), "pubcli --zip 33101 --har /tmp/pubcli.har")
+	}
+	return nil
+}
+
+// shouldLaunchDefaultTUI reports whether a bare `pubcli` should open the TUI
+// instead of printing the quick start.
+func shouldLaunchDefaultTUI(cfg *config.Config, interactive bool) bool {
+	if !interactive || cfg == nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(cfg.DefaultCommand), "tui") && cfg.HasDefaultLocation()
+}
+
+// applyConfigDefaults fills --store/--zip from the config file when the user
+// gave neither, so every command shares the same default location. It also
+// applies config-level output preferences such as accessible mode, the
+// pinned JSON schema version, the locale, and the interface language;
+// explicit flags win over the config file.
+func applyConfigDefaults(cmd *cobra.Command, _ []string) error {
+	if !console.ValidColorMode(flagColor) {
+		return invalidArgsError(
+			fmt.Sprintf("invalid --color %q (use auto, always, or never)", flagColor),
+			"pubcli --zip 33101 --color always | less -R",
+		)
+	}
+	colorProfile := console.ColorProfile(flagColor, cmd.OutOrStdout())
+	lipgloss.SetColorProfile(colorProfile)
+	if colorProfile == termenv.Ascii {
+		// Without colors the background does not matter; settling it keeps
+		// adaptive colors from querying the terminal for it.
+		lipgloss.SetHasDarkBackground(true)
+	}
+
+	if activeConfig.Accessible {
+		flagAccessible = true
+	}
+	display.SetAccessible(flagAccessible)
+	if activeConfig.Quiet {
+		flagQuiet = true
+	}
+
+	if flagSchemaVersion != 0 {
+		if !display.ValidSchemaVersion(flagSchemaVersion) {
+			return invalidArgsError(
+				"invalid value for --schema-version (use 1 or 2)",
+				"pubcli --zip 33101 --json --schema-version 2",
+				"pubcli --zip 33101 --json --schema-version 1",
+			)
+		}
+		display.SetSchemaVersion(flagSchemaVersion)
+	}
+
+	headers, err := buildRequestHeaders(activeConfig.Headers, flagHeaders)
+	if err != nil {
+		return err
+	}
+	requestHeaders = headers
+
+	if activeConfig.CookieJar && cookieJar == nil {
+		jar, err := openCookieJar()
+		if err != nil {
+			return err
+		}
+		cookieJar = jar
+	}
+
+	if flagHAR != "" && harRecorder == nil {
+		harRecorder = &api.HARRecorder{SecretHeaders: secretHeaderNames(requestHeaders)}
+	}
+
+	if p := activeConfig.Profile; householdProfile == nil && (len(p.Allergens) > 0 || len(p.Dislikes) > 0 || len(p.Diets) > 0) {
+		checker, err := profile.New(profile.Profile{
+			Allergens: p.Allergens,
+			Dislikes:  p.Dislikes,
+			Diets:     p.Diets,
+			Hide:      p.Hide && !flagNoDefaultFilters,
+		})
+		if err != nil {
+			return configError(fmt.Errorf("profile: %w", err))
+		}
+		householdProfile = checker
+	}
+
+	if flagStoreType != "" {
+		codes, err := api.ParseStoreTypes(flagStoreType)
+		if err != nil {
+			return invalidArgsError(
+				fmt.Sprintf("invalid --store-type: %v (use %s)", err, storeTypeNames()),
+				"pubcli stores --zip 33101 --store-type greenwise",
+				"pubcli --zip 33101 --store-type regular,liquor",
+			)
+		}
+		storeTypeCodes = codes
+	}
+
+	if flagLocale != "" {
+		if _, ok := display.CanonicalLocale(flagLocale); !ok {
+			return invalidArgsError(
+				fmt.Sprintf("unsupported --locale %q (use one of %s)", flagLocale, strings.Join(display.Locales(), ", ")),
+				"pubcli --zip 33101 --locale en-GB",
+			)
+		}
+		display.SetLocale(flagLocale)
+	}
+
+	if flagLang != "" {
+		if _, ok := display.CanonicalLanguage(flagLang); !ok {
+			return invalidArgsError(
+				fmt.Sprintf("unsupported --lang %q (use one of %s)", flagLang, strings.Join(display.Languages(), ", ")),
+				"pubcli --zip 33101 --lang es",
+			)
+		}
+		display.SetLanguage(flagLang)
+	}
+
+	if flagStore != "" || flagZip != "" {
+		return nil
+	}
+	flagStore = strings.TrimSpace(activeConfig.DefaultStore)
+	if flagStore == "" {
+		flagZip = strings.TrimSpace(activeConfig.DefaultZip)
+	}
+	return nil
+}
+
+// buildRequestHeaders merges the config file's headers with --header flags,
+// which replace config headers of the same name.
+func buildRequestHeaders(fromConfig map[string]string, fromFlags []string) (http.Header, error) {
+	if len(fromConfig) == 0 && len(fromFlags) == 0 {
+		return nil, nil
+	}
+	headers := http.Header{}
+	for name, value := range fromConfig {
+		value, err := resolveSecret("headers."+name, value)
+		if err != nil {
+			return nil, err
+		}
+		name, value, err := api.ParseHeader(name + ": " + value)
+		if err != nil {
+			return nil, configError(err)
+		}
+		headers.Set(name, value)
+	}
+	for _, raw := range fromFlags {
+		name, value, err := api.ParseHeader(raw)
+		if err != nil {
+			return nil, invalidArgsError(
+				fmt.Sprintf("invalid --header: %v", err),
+				`pubcli --zip 33101 --header "X-Experiment: weekly-ad-b"`,
+			)
+		}
+		headers.Set(name, value)
+	}
+	return headers, nil
+}
+
+// secretHeaderNames returns the added headers to redact from --har captures:
+// all but User-Agent, since session headers are the usual reason to add one.
+func secretHeaderNames(h http.Header) []string {
+	var names []string
+	for name := range h {
+		if name != "User-Agent" {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 func registerDealFilterFlags(f *pflag.FlagSet) {
@@ -125,19 +555,142 @@ func registerDealFilterFlags(f *pflag.FlagSet) {
 	f.StringVarP(&flagQuery, "query", "q", "", "Search deals by keyword in title/description")
 	f.StringVar(&flagSort, "sort", "", "Sort deals by relevance, savings, or ending")
 	f.IntVarP(&flagLimit, "limit", "n", 0, "Limit number of results (0 = all)")
+	f.BoolVar(&flagIncludeExpired, "include-expired", false, "Include deals whose end date has passed")
+	f.Float64Var(&flagMinPercentOff, "min-percent-off", 0, "Show only deals saving at least this percent, stated or estimated (0 = all)")
 }
 
-func validateSortMode() error {
-	switch strings.ToLower(strings.TrimSpace(flagSort)) {
-	case "", "relevance", "savings", "ending", "end", "expiry", "expiration":
-		return nil
+// registerLimitPerGroupFlag adds --limit-per-group to commands that show
+// deals in groups.
+func registerLimitPerGroupFlag(f *pflag.FlagSet) {
+	f.IntVar(&flagLimitPerGroup, "limit-per-group", 0, "Show at most N deals of each group (0 = all)")
+}
+
+// registerOutputBudgetFlags adds the robot-mode size limits for JSON deal output.
+func registerOutputBudgetFlags(f *pflag.FlagSet) {
+	f.IntVar(&flagMaxItems, "max-items", 0, "JSON: keep at most N highest-scoring deals and wrap output in an envelope (0 = no limit)")
+	f.IntVar(&flagMaxBytes, "max-bytes", 0, "JSON: trim output to at most N bytes, dropping lowest-scoring deals first (0 = no limit)")
+}
+
+// registerChartFlag adds --chart to commands that can draw their counts as
+// a bar chart.
+func registerChartFlag(f *pflag.FlagSet) {
+	f.BoolVar(&flagChart, "chart", false, "Draw the results as a bar chart")
+}
+
+// registerOutputFormatFlag adds --format to commands that can render
+// launcher-friendly output.
+func registerOutputFormatFlag(f *pflag.FlagSet) {
+	f.StringVar(&flagFormat, "format", "text", "Output format: text, json, markdown, or alfred (Alfred/Raycast Script Filter JSON)")
+}
+
+// validateOutputFormat checks --format. `--format json` is the same as --json.
+func validateOutputFormat() error {
+	flagFormat = strings.ToLower(strings.TrimSpace(flagFormat))
+	switch flagFormat {
+	case "text", "alfred", "markdown":
+	case "md":
+		flagFormat = "markdown"
+	case "json":
+		flagJSON = true
 	default:
 		return invalidArgsError(
-			"invalid value for --sort (use relevance, savings, or ending)",
-			"pubcli --zip 33101 --sort savings",
-			"pubcli --zip 33101 --sort ending",
+			"invalid value for --format (use text, json, markdown, or alfred)",
+			"pubcli --zip 33101 --format markdown",
+			"pubcli stores --zip 33101 --format alfred",
+		)
+	}
+	return nil
+}
+
+// structuredOutput reports whether stdout carries only the requested
+// output, machine-readable or for pasting, so informational text must stay
+// off it.
+func structuredOutput() bool {
+	return flagJSON || flagFormat == "alfred" || flagFormat == "markdown"
+}
+
+func validateOutputBudget() error {
+	if flagMaxItems < 0 || flagMaxBytes < 0 {
+		return invalidArgsError(
+			"--max-items and --max-bytes must be zero or positive",
+			"pubcli --zip 33101 --json --max-items 20",
+			"pubcli --zip 33101 --json --max-bytes 8000",
 		)
 	}
+	return nil
+}
+
+// printDealsJSON writes deals in the versioned envelope under "deals", or
+// in the truncation-aware envelope when an output budget is set. rec, if
+// not nil, adds "diagnostics" of failed or retried upstream requests, and a
+// nextOffset above 0 adds "nextOffset", where the next page of deals
+// starts. Both are left out of budgeted output, whose size is capped, and
+// of schema v1, which is a plain array.
+func printDealsJSON(w io.Writer, items []api.SavingItem, rec *diag.Recorder, nextOffset int) error {
+	budget := display.Budget{MaxItems: flagMaxItems, MaxBytes: flagMaxBytes}
+	if budget.Enabled() {
+		return display.PrintDealsBudgetJSON(w, items, budget)
+	}
+	extra := map[string]any{}
+	if rec != nil {
+		if summary := rec.Summary(); summary != nil {
+			extra["diagnostics"] = summary
+		}
+	}
+	if nextOffset > 0 {
+		extra["nextOffset"] = nextOffset
+	}
+	out := make([]display.DealJSON, 0, len(items))
+	for _, item := range items {
+		out = append(out, display.ToDealJSON(item))
+	}
+	return display.PrintVersionedJSONWith(w, "deals", out, extra)
+}
+
+func validateSortMode() error {
+	return validateSort(flagSort)
+}
+
+func validateMinPercentOff() error {
+	if flagMinPercentOff < 0 || flagMinPercentOff > 100 {
+		return invalidArgsError(
+			"--min-percent-off must be between 0 and 100",
+			"pubcli --zip 33101 --min-percent-off 30",
+		)
+	}
+	return nil
+}
+
+// validateGrouping checks --group-by and --limit-per-group, and groups by
+// department when only --limit-per-group is given.
+func validateGrouping() error {
+	if !filter.ValidGroupBy(flagGroupBy) {
+		return invalidArgsError(
+			"invalid value for --group-by (use department or category)",
+			"pubcli --zip 33101 --group-by department --limit-per-group 3",
+		)
+	}
+	if flagLimitPerGroup < 0 {
+		return invalidArgsError(
+			"--limit-per-group must not be negative",
+			"pubcli --zip 33101 --group-by department --limit-per-group 3",
+		)
+	}
+	if flagLimitPerGroup > 0 && strings.TrimSpace(flagGroupBy) == "" {
+		flagGroupBy = "department"
+	}
+	return nil
+}
+
+func validateSort(value string) error {
+	if filter.ValidSort(value) {
+		return nil
+	}
+	return invalidArgsError(
+		"invalid value for --sort (use relevance, savings, or ending)",
+		"pubcli --zip 33101 --sort savings",
+		"pubcli --zip 33101 --sort ending",
+	)
 }
 
 func resolveStore(cmd *cobra.Command, client *api.Client) (string, error) {
@@ -164,56 +717,263 @@ func resolveStore(cmd *cobra.Command, client *api.Client) (string, error) {
 	}
 
 	num := api.StoreNumber(stores[0].Key)
-	if !flagJSON {
+	if !structuredOutput() {
 		display.PrintStoreContext(cmd.OutOrStdout(), stores[0])
+		if warning := display.StoreClosedWarning(stores[0], time.Now()); warning != "" {
+			display.PrintWarning(cmd.ErrOrStderr(), warning)
+		}
 	}
 	return num, nil
 }
 
+// fetchAllStoresDeals merges the ads of every store `pubcli stores` lists
+// for --zip, for --all-stores.
+func fetchAllStoresDeals(cmd *cobra.Command, client *api.Client, rec *diag.Recorder) ([]api.SavingItem, error) {
+	if flagStore != "" || flagZip == "" {
+		return nil, invalidArgsError(
+			"--all-stores needs --zip instead of --store",
+			"pubcli --zip 33101 --all-stores",
+		)
+	}
+	items, stores, skipped, err := compare.Merge(cmd.Context(), client, flagZip, allStoresCount)
+	var upstream *compare.UpstreamError
+	switch {
+	case errors.As(err, &upstream):
+		return nil, upstreamError(upstream.Action, upstream.Err)
+	case errors.Is(err, compare.ErrNoStores):
+		return nil, notFoundError(
+			fmt.Sprintf("no Publix stores found near %s", flagZip),
+			"Try a nearby ZIP code.",
+		)
+	case err != nil:
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, notFoundError(
+			fmt.Sprintf("no deals found at stores near %s", flagZip),
+			"Try a nearby ZIP code.",
+		)
+	}
+	if note := diagnosticsNote(skipped, rec); note != "" {
+		display.PrintWarning(cmd.ErrOrStderr(), note)
+	}
+	if !structuredOutput() {
+		display.PrintMergedStoreContext(cmd.OutOrStdout(), flagZip, stores)
+	}
+	return items, nil
+}
+
 func runDeals(cmd *cobra.Command, _ []string) error {
 	if err := validateSortMode(); err != nil {
 		return err
 	}
-
-	client := api.NewClient()
-
-	storeNumber, err := resolveStore(cmd, client)
-	if err != nil {
+	if err := validateMinPercentOff(); err != nil {
+		return err
+	}
+	if err := validateGrouping(); err != nil {
+		return err
+	}
+	if flagOffset < 0 {
+		return invalidArgsError(
+			"--offset must not be negative",
+			"pubcli --zip 33101 --limit 20 --offset 20",
+		)
+	}
+	if flagNewOnly && flagAllStores {
+		return invalidArgsError(
+			"--new-only works with one store, not --all-stores",
+			"pubcli --zip 33101 --new-only",
+		)
+	}
+	if err := validateOutputBudget(); err != nil {
+		return err
+	}
+	if err := validateOutputFormat(); err != nil {
+		return err
+	}
+	if err := validateImageFlags(); err != nil {
 		return err
 	}
 
-	data, err := client.FetchSavings(cmd.Context(), storeNumber)
-	if err != nil {
-		return upstreamError("fetching deals", err)
+	// rec collects upstream failures across the stores --all-stores merges.
+	var rec *diag.Recorder
+	var opts []api.Option
+	if flagAllStores {
+		rec = &diag.Recorder{}
+		opts = append(opts, api.WithObserver(rec.Record))
 	}
+	client := newAPIClient(opts...)
 
-	items := data.Savings
-	if len(items) == 0 {
-		return notFoundError(
-			fmt.Sprintf("no deals found for store #%s", storeNumber),
-			"Try another store with --store.",
-		)
+	var items []api.SavingItem
+	var storeNumber string
+	if flagAllStores {
+		merged, err := fetchAllStoresDeals(cmd, client, rec)
+		if err != nil {
+			return err
+		}
+		items = merged
+	} else {
+		var err error
+		storeNumber, err = resolveStore(cmd, client)
+		if err != nil {
+			return err
+		}
+
+		data, err := client.FetchSavings(cmd.Context(), storeNumber)
+		if err != nil {
+			return upstreamError("fetching deals", err)
+		}
+
+		items = data.Savings
+		if len(items) == 0 {
+			return notFoundError(
+				fmt.Sprintf("no deals found for store #%s", storeNumber),
+				"Try another store with --store.",
+			)
+		}
+
+		// Without --new-only, the history only adds isNew, so a data
+		// directory that cannot be read or written does not fail the listing.
+		archive, err := adArchive()
+		found := false
+		if err == nil {
+			found, err = markNewDeals(archive, storeNumber, data)
+		}
+		if flagNewOnly {
+			if err != nil {
+				return configError(err)
+			}
+			if !found {
+				return notFoundError(
+					fmt.Sprintf("no earlier ad saved for store #%s to tell new deals from", storeNumber),
+					"The ad is saved now; run again after the next ad comes out (ads change on Wednesdays).",
+				)
+			}
+		}
 	}
 
+	// One deal past the page tells whether another page follows.
+	limit := flagLimit
+	if limit > 0 {
+		limit++
+	}
 	items = filter.Apply(items, filter.Options{
-		BOGO:       flagBogo,
-		Category:   flagCategory,
-		Department: flagDepartment,
-		Query:      flagQuery,
-		Sort:       flagSort,
-		Limit:      flagLimit,
+		BOGO:           flagBogo,
+		Category:       flagCategory,
+		Department:     flagDepartment,
+		Query:          flagQuery,
+		Sort:           flagSort,
+		Limit:          limit,
+		ExcludeExpired: !flagIncludeExpired,
+		MinPercentOff:  flagMinPercentOff,
+		GroupBy:        flagGroupBy,
+		LimitPerGroup:  flagLimitPerGroup,
+		Offset:         flagOffset,
+		NewOnly:        flagNewOnly,
 	})
+	nextOffset := 0
+	if flagLimit > 0 && len(items) > flagLimit {
+		items = items[:flagLimit]
+		nextOffset = flagOffset + flagLimit
+	}
 
 	if len(items) == 0 {
+		if flagNewOnly {
+			return notFoundError(
+				"no new deals match your filters since the store's previous ad",
+				"Drop --new-only to see every deal.",
+			)
+		}
+		if flagOffset > 0 {
+			return notFoundError(
+				fmt.Sprintf("no deals past offset %d", flagOffset),
+				"Use a smaller --offset.",
+			)
+		}
 		return notFoundError(
 			"no deals match your filters",
 			"Relax filters like --category/--department/--query.",
 		)
 	}
 
+	if flagDownloadImages != "" {
+		if err := downloadDealImages(cmd.Context(), cmd.ErrOrStderr(), items); err != nil {
+			return err
+		}
+	}
+
+	if flagFormat == "alfred" {
+		return display.PrintDealsAlfred(cmd.OutOrStdout(), items)
+	}
+	if flagFormat == "markdown" {
+		display.PrintDealsMarkdown(cmd.OutOrStdout(), items)
+		printDefaultFiltersNote(cmd.ErrOrStderr())
+		printNextPageNote(cmd.ErrOrStderr(), nextOffset)
+		return nil
+	}
 	if flagJSON {
-		return display.PrintDealsJSON(cmd.OutOrStdout(), items)
+		return printDealsJSON(cmd.OutOrStdout(), items, rec, nextOffset)
 	}
-	display.PrintDeals(cmd.OutOrStdout(), items)
+	display.PrintDealsGrouped(cmd.OutOrStdout(), items, flagGroupBy)
+	printDefaultFiltersNote(cmd.ErrOrStderr())
+	printNextPageNote(cmd.ErrOrStderr(), nextOffset)
+	printDealsHint(cmd.ErrOrStderr(), dealsHint{
+		Shown:       len(items),
+		Store:       storeNumber,
+		Interactive: isTTY(cmd.OutOrStdout()),
+	})
 	return nil
 }
+
+// printNextPageNote tells how to see the next page when more deals match
+// than --limit showed.
+func printNextPageNote(w io.Writer, nextOffset int) {
+	if nextOffset == 0 {
+		return
+	}
+	fmt.Fprintln(w, display.T("note.next_page", nextOffset))
+}
+
+// defaultExclusions returns the categories and departments exclude in the
+// config file hides; none with --no-default-filters.
+func defaultExclusions() filter.Exclusions {
+	if flagNoDefaultFilters {
+		return filter.Exclusions{}
+	}
+	return filter.Exclusions{
+		Categories:  activeConfig.Exclude.Categories,
+		Departments: activeConfig.Exclude.Departments,
+	}
+}
+
+// defaultSavingsFilter is what newAPIClient passes every ad through: the
+// default exclusions, then the household profile. It is nil when neither is
+// configured.
+func defaultSavingsFilter() func([]api.SavingItem) []api.SavingItem {
+	exclusions, checker := defaultExclusions(), householdProfile
+	if exclusions.Empty() && checker == nil {
+		return nil
+	}
+	return func(items []api.SavingItem) []api.SavingItem {
+		return checker.Apply(exclusions.Apply(items))
+	}
+}
+
+// defaultHidden names what the config file hides by default, for notes.
+func defaultHidden() []string {
+	hidden := defaultExclusions().Names()
+	if householdProfile.Hides() {
+		hidden = append(hidden, "deals the profile flags")
+	}
+	return hidden
+}
+
+// printDefaultFiltersNote notes under a deal listing what the config
+// file's default filters hide, so missing deals are not a mystery.
+func printDefaultFiltersNote(w io.Writer) {
+	hidden := defaultHidden()
+	if len(hidden) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "note: hiding %s (see config.yaml); --no-default-filters shows them\n", strings.Join(hidden, ", "))
+}
@@ -2,12 +2,45 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/daemon"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/schedule"
+	"github.com/tayloree/publix-deals/internal/search"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+	"github.com/tayloree/publix-deals/internal/storage"
 )
 
+// TestMain isolates the suite from the developer's real config file, saved
+// state, and any running daemon.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "pubcli-cmd-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(config.EnvConfigDir, dir)
+	os.Setenv(config.EnvDataDir, filepath.Join(dir, "data"))
+	os.Setenv(config.EnvCacheDir, filepath.Join(dir, "cache"))
+	os.Setenv(daemon.EnvDisable, "1")
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestRunCLI_CompletionZsh(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -19,6 +52,154 @@ func TestRunCLI_CompletionZsh(t *testing.T) {
 	assert.Empty(t, stderr.String())
 }
 
+func TestRunCLI_CompletionInstall(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"completion", "install", "zsh"}, &stdout, &stderr)
+
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	path := filepath.Join(dataHome, "zsh", "site-functions", "_pubcli")
+	assert.Contains(t, stdout.String(), path)
+	script, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "#compdef pubcli")
+}
+
+func TestRunCLI_CompletionInstallRejectsUnknownShell(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"completion", "install", "tcsh"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), `cannot install completion for shell "tcsh"`)
+}
+
+func TestRunCLI_CompletionFlagValues(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"__complete", "--sort", ""}, &stdout, &stderr)
+	assert.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), "savings\tBiggest savings first")
+
+	stdout.Reset()
+	code = runCLI([]string{"__complete", "status", "--format", ""}, &stdout, &stderr)
+	assert.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), "waybar\t")
+	assert.NotContains(t, stdout.String(), "alfred")
+}
+
+func TestRunCLI_Env(t *testing.T) {
+	configDir := t.TempDir()
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, configDir)
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Setenv(envSyncPassword, "hunter2")
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("default_store: \"1425\"\nlocale: en-GB\nlang: es\n"), 0o644))
+	// The config's locale and language outlive the run; reset them for later tests.
+	t.Cleanup(resetCLIState)
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "history", "1425"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "history", "1425", "2026-10-14.json"), []byte("{}"), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"env", "--json"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.NotContains(t, stdout.String(), "hunter2")
+
+	var payload struct {
+		Env envJSON `json:"env"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &payload))
+	env := payload.Env
+	assert.Equal(t, filepath.Join(configDir, "config.yaml"), env.ConfigFile)
+	assert.True(t, env.ConfigFileExists)
+	assert.Equal(t, dataDir, env.DataDir)
+	assert.Equal(t, "1425", env.Defaults.Store)
+	assert.Equal(t, "en-GB", env.Defaults.Locale)
+	assert.Equal(t, "es", env.Defaults.Lang)
+	assert.Equal(t, 1, env.Cache.HistoryStores)
+	assert.Equal(t, 1, env.Cache.HistorySnapshots)
+	assert.Contains(t, env.Env, envVariable{Name: envSyncPassword, Set: true, Secret: true})
+	assert.Contains(t, env.Env, envVariable{Name: config.EnvDataDir, Set: true, Value: dataDir})
+
+	stdout.Reset()
+	code = runCLI([]string{"env", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), "PUBCLI_SYNC_PASSWORD")
+	assert.Contains(t, stdout.String(), "(set)")
+	assert.NotContains(t, stdout.String(), "hunter2")
+}
+
+func TestBuildRequestHeaders(t *testing.T) {
+	headers, err := buildRequestHeaders(
+		map[string]string{"user-agent": "from-config", "X-Experiment": "a"},
+		[]string{"X-Experiment: b", "X-Store-Session: abc"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "from-config", headers.Get("User-Agent"))
+	assert.Equal(t, "b", headers.Get("X-Experiment"), "--header wins over the config file")
+	assert.ElementsMatch(t, []string{"X-Experiment", "X-Store-Session"}, secretHeaderNames(headers))
+
+	headers, err = buildRequestHeaders(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, headers)
+}
+
+func TestRunCLI_InvalidHeader(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--store", "1425", "--header", "no-colon", "--json"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "invalid --header")
+}
+
+func TestRunCLI_CookiesImportListClear(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	file := filepath.Join(t.TempDir(), "cookies.txt")
+	require.NoError(t, os.WriteFile(file, []byte(".publix.com\tTRUE\t/\tTRUE\t0\tsession\tsecret-value\n"), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"cookies", "import", file}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Imported 1 cookies.")
+	assert.Contains(t, stderr.String(), "cookie_jar: true")
+
+	stdout.Reset()
+	code = runCLI([]string{"cookies", "list", "--json"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), `"name":"session"`)
+	assert.NotContains(t, stdout.String(), "secret-value")
+
+	stdout.Reset()
+	code = runCLI([]string{"cookies", "clear"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code)
+	stdout.Reset()
+	code = runCLI([]string{"cookies", "list", "--json"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), `"cookies":[]`)
+}
+
+func TestRunCLI_ColorFlag(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Cleanup(func() { lipgloss.SetColorProfile(termenv.Ascii) })
+	file := filepath.Join(t.TempDir(), "cookies.txt")
+	require.NoError(t, os.WriteFile(file, []byte(".publix.com\tTRUE\t/\tTRUE\t0\tsession\tsecret-value\n"), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"cookies", "import", file, "--color", "always"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stderr.String(), "\x1b[", "the warning is colored even though stderr is not a terminal")
+
+	stderr.Reset()
+	code = runCLI([]string{"cookies", "import", file, "--color", "never"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.NotContains(t, stderr.String(), "\x1b[")
+
+	stderr.Reset()
+	code = runCLI([]string{"cookies", "list", "--color", "sometimes"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--color")
+}
+
 func TestRunCLI_HelpStores(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -41,6 +222,23 @@ func TestRunCLI_HelpCompare(t *testing.T) {
 	assert.Empty(t, stderr.String())
 }
 
+func TestRunCLI_AdViews(t *testing.T) {
+	t.Cleanup(resetCLIState)
+	var stdout, stderr bytes.Buffer
+
+	code := runCLI([]string{"help", "ad"}, &stdout, &stderr)
+	assert.Equal(t, ExitSuccess, code)
+	for _, view := range []string{"deals", "summary", "new", "ending", "bogo"} {
+		assert.Contains(t, stdout.String(), "\n  "+view+" ", view)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	code = runCLI([]string{"ad", "new", "--zip", "33101", "--all-stores"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code, "ad new presets --new-only")
+	assert.Contains(t, stderr.String(), "--new-only works with one store")
+}
+
 func TestRunCLI_TolerantRewriteWithoutNetworkCall(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -52,6 +250,40 @@ func TestRunCLI_TolerantRewriteWithoutNetworkCall(t *testing.T) {
 	assert.Contains(t, stderr.String(), "interpreted `-zip` as `--zip`")
 }
 
+func TestRunCLI_StrictModeFailsFast(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	code := runCLI([]string{"--strict", "stores", "-zip", "33101"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.NotContains(t, stderr.String(), "note:")
+	assert.Contains(t, stderr.String(), `"code":"INVALID_ARGS"`, "strict errors are JSON")
+
+	stderr.Reset()
+	code = runCLI([]string{"--strict", "categoriess"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "unknown command")
+	assert.NotContains(t, stderr.String(), "Did you mean")
+
+	stderr.Reset()
+	code = runCLI([]string{"capabilities", "--strict"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.False(t, strings.HasPrefix(stdout.String(), "{"), "no automatic JSON when stdout is not a terminal")
+}
+
+func TestRunCLI_HARWrittenOnExit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.har")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"capabilities", "--har", path}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"version": "1.2"`)
+	assert.Contains(t, string(data), `"entries": []`)
+}
+
 func TestRunCLI_DoubleDashBoundary(t *testing.T) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -62,3 +294,527 @@ func TestRunCLI_DoubleDashBoundary(t *testing.T) {
 	assert.Contains(t, stdout.String(), "pubcli stores [flags]")
 	assert.False(t, strings.Contains(stderr.String(), "interpreted `zip` as `--zip`"))
 }
+
+func TestRunCLI_InvalidConfigIsReported(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("default_store: [oops"), 0o600))
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	code := runCLI([]string{"stores", "--zip", "33101"}, &stdout, &stderr)
+
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "invalid config file")
+}
+
+func TestShouldLaunchDefaultTUI(t *testing.T) {
+	cfg := &config.Config{DefaultStore: "1425", DefaultCommand: "tui"}
+
+	assert.True(t, shouldLaunchDefaultTUI(cfg, true))
+	assert.False(t, shouldLaunchDefaultTUI(cfg, false))
+	assert.False(t, shouldLaunchDefaultTUI(&config.Config{DefaultCommand: "tui"}, true))
+	assert.False(t, shouldLaunchDefaultTUI(&config.Config{DefaultStore: "1425"}, true))
+}
+
+func TestApplyConfigDefaults(t *testing.T) {
+	resetCLIState()
+	defer resetCLIState()
+
+	activeConfig = &config.Config{DefaultStore: "1425", DefaultZip: "33101"}
+	require.NoError(t, applyConfigDefaults(rootCmd, nil))
+	assert.Equal(t, "1425", flagStore)
+	assert.Empty(t, flagZip)
+
+	resetCLIState()
+	activeConfig = &config.Config{DefaultStore: "1425"}
+	flagZip = "32801"
+	require.NoError(t, applyConfigDefaults(rootCmd, nil))
+	assert.Empty(t, flagStore, "explicit --zip wins over the configured store")
+	assert.Equal(t, "32801", flagZip)
+}
+
+func TestDefaultExclusions(t *testing.T) {
+	resetCLIState()
+	defer resetCLIState()
+
+	var note bytes.Buffer
+	printDefaultFiltersNote(&note)
+	assert.Empty(t, note.String(), "nothing is hidden without config")
+
+	activeConfig = &config.Config{Exclude: config.Exclude{Categories: []string{"tobacco"}, Departments: []string{"pet"}}}
+	assert.Equal(t, []string{"tobacco", "pet"}, defaultExclusions().Names())
+	printDefaultFiltersNote(&note)
+	assert.Equal(t, "note: hiding tobacco, pet (see config.yaml); --no-default-filters shows them\n", note.String())
+
+	flagNoDefaultFilters = true
+	assert.True(t, defaultExclusions().Empty())
+}
+
+func TestRunCLI_Profile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("profile:\n  allergens: [peanut]\n  hide: true\n"), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"capabilities"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	require.NotNil(t, householdProfile)
+	assert.Equal(t, []string{"deals the profile flags"}, defaultHidden())
+
+	kept := defaultSavingsFilter()([]api.SavingItem{{ID: "1", Title: strPtr("Peanut Butter")}, {ID: "2", Title: strPtr("Bananas")}})
+	require.Len(t, kept, 1)
+	assert.Equal(t, "2", kept[0].ID)
+
+	code = runCLI([]string{"capabilities", "--no-default-filters"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Empty(t, defaultHidden())
+	marked := defaultSavingsFilter()([]api.SavingItem{{ID: "1", Title: strPtr("Peanut Butter")}})
+	require.Len(t, marked, 1)
+	assert.Equal(t, []string{"peanut"}, marked[0].Flags)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("profile:\n  diets: [keto]\n"), 0o600))
+	stderr.Reset()
+	code = runCLI([]string{"capabilities", "--json=false"}, &stdout, &stderr)
+	assert.NotEqual(t, ExitSuccess, code)
+	assert.Contains(t, stderr.String(), `unknown diet`)
+}
+
+func TestRunCLI_SchemaVersion(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	code := runCLI(nil, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), `"schemaVersion":2`)
+
+	stdout.Reset()
+	code = runCLI([]string{"capabilities", "--json", "--schema-version", "1"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"schemaVersion":1`)
+
+	stderr.Reset()
+	code = runCLI([]string{"capabilities", "--json", "--schema-version", "3"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--schema-version")
+}
+
+func TestRunCLI_SchemaVersionConfigPin(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("schema_version: 1\n"), 0o600))
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	code := runCLI(nil, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code)
+	assert.NotContains(t, stdout.String(), "schemaVersion")
+
+	stdout.Reset()
+	code = runCLI([]string{"capabilities", "--json", "--schema-version", "2"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"schemaVersion":2`, "flag overrides the config pin")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("schema_version: 9\n"), 0o600))
+	stderr.Reset()
+	code = runCLI([]string{"capabilities"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "schema_version 9")
+}
+
+func TestRunCLI_LocaleValidation(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"capabilities", "--locale", "xx-XX"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--locale")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("locale: klingon\n"), 0o600))
+	stderr.Reset()
+	code = runCLI([]string{"capabilities"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), `locale "klingon"`)
+}
+
+func TestRunCLI_LangValidation(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"capabilities", "--lang", "tlh"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--lang")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("lang: klingon\n"), 0o600))
+	stderr.Reset()
+	code = runCLI([]string{"capabilities"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), `lang "klingon"`)
+}
+
+func TestRunCLI_InvalidFormat(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	code := runCLI([]string{"--store", "1425", "--format", "xml"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--format")
+	assert.Empty(t, stdout.String())
+}
+
+func TestRunCLI_ShoppingListRoundTrip(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	run := func(args ...string) (string, int) {
+		var stdout, stderr bytes.Buffer
+		code := runCLI(args, &stdout, &stderr)
+		return stdout.String() + stderr.String(), code
+	}
+
+	out, code := run("list", "add", "paper", "towels")
+	require.Equal(t, ExitSuccess, code, out)
+	assert.Contains(t, out, "Added paper towels (1 item(s) on the list)")
+	run("list", "add", "milk")
+
+	out, _ = run("list", "export")
+	assert.Equal(t, "- [ ] paper towels\n- [ ] milk\n", out)
+
+	out, code = run("list", "remove", "1")
+	require.Equal(t, ExitSuccess, code, out)
+	out, _ = run("list", "show", "--json")
+	assert.Contains(t, out, `"items":[{"name":"milk"`)
+
+	_, code = run("list", "remove", "eggs")
+	assert.Equal(t, ExitNotFound, code)
+
+	out, code = run("list", "links", "--format", "markdown")
+	require.Equal(t, ExitSuccess, code, out)
+	assert.Equal(t, "- milk — [Publix](https://www.publix.com/search?searchTerm=milk) · "+
+		"[Instacart](https://delivery.publix.com/store/publix/s?k=milk)\n", out)
+	out, _ = run("list", "links", "--format", "json")
+	assert.Contains(t, out, `"links":[{"name":"milk","search":"milk"`)
+}
+
+func TestRunCLI_ProfileFlagIsolatesLists(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	run := func(args ...string) (string, int) {
+		var stdout, stderr bytes.Buffer
+		code := runCLI(args, &stdout, &stderr)
+		return stdout.String() + stderr.String(), code
+	}
+
+	_, code := run("--profile", "work", "list", "add", "coffee")
+	require.Equal(t, ExitSuccess, code)
+	run("list", "add", "milk")
+
+	out, _ := run("list", "export", "--profile=work")
+	assert.Equal(t, "- [ ] coffee\n", out)
+	t.Setenv(config.EnvProfile, "work")
+	out, _ = run("list", "export")
+	assert.Equal(t, "- [ ] coffee\n", out)
+	out, _ = run("--profile", "", "list", "export")
+	assert.Equal(t, "- [ ] milk\n", out)
+
+	_, code = run("--profile", "../home", "list", "export")
+	assert.Equal(t, ExitInvalidArgs, code)
+}
+
+func TestRunCLI_DataMigrateToSQLite(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, t.TempDir())
+	t.Setenv(config.EnvDataDir, dataDir)
+	run := func(args ...string) (string, int) {
+		var stdout, stderr bytes.Buffer
+		code := runCLI(args, &stdout, &stderr)
+		return stdout.String() + stderr.String(), code
+	}
+
+	run("list", "add", "milk")
+	run("list", "--name", "party", "add", "chips")
+	out, code := run("data", "migrate", "--to", "sqlite", "--json=false")
+	require.Equal(t, ExitSuccess, code, out)
+	assert.Contains(t, out, "Copied 2 item(s)")
+	assert.FileExists(t, filepath.Join(dataDir, storage.DBFile))
+
+	// The JSON copy is left behind; changes now go to the database.
+	require.NoError(t, os.Remove(filepath.Join(dataDir, shoplist.FileName)))
+	run("list", "add", "eggs")
+	out, _ = run("list", "export")
+	assert.Equal(t, "- [ ] milk\n- [ ] eggs\n", out)
+	assert.NoFileExists(t, filepath.Join(dataDir, shoplist.FileName))
+	out, _ = run("list", "names", "--json=false")
+	assert.Contains(t, out, "party: 1 item(s)")
+
+	out, code = run("data", "migrate", "--to", "json")
+	require.Equal(t, ExitSuccess, code, out)
+	l, err := shoplist.Load(&storage.Files{Dir: dataDir}, shoplist.FileName)
+	require.NoError(t, err)
+	assert.Len(t, l.Items, 2)
+
+	_, code = run("data", "migrate", "--to", "postgres")
+	assert.Equal(t, ExitInvalidArgs, code)
+}
+
+func TestRunCLI_DataPruneRemovesOldSnapshots(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, t.TempDir())
+	t.Setenv(config.EnvDataDir, dataDir)
+	archive := history.Archive{Backend: &storage.Files{Dir: dataDir}}
+	now := time.Now()
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "old", SavedAt: now.AddDate(0, 0, -7*30)}))
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "new", SavedAt: now}))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"data", "prune", "--dry-run", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Would remove 1 snapshot(s)")
+	assert.Contains(t, stdout.String(), "History holds 2 snapshot(s) of 1 store(s)")
+
+	stdout.Reset()
+	code = runCLI([]string{"data", "prune", "--json"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	var out struct {
+		Prune dataPruneJSON `json:"prune"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	assert.Equal(t, config.DefaultHistoryWeeks, out.Prune.KeepWeeks)
+	assert.Len(t, out.Prune.Removed, 1)
+	assert.Equal(t, 1, out.Prune.History.Snapshots)
+
+	code = runCLI([]string{"data", "prune", "--keep-weeks", "-1"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+}
+
+func TestRunCLI_SnapshotVerifyReportsCorruption(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, t.TempDir())
+	t.Setenv(config.EnvDataDir, dataDir)
+	archive := history.Archive{Backend: &storage.Files{Dir: dataDir}}
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: time.Now()}))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"snapshot", "verify", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Checked 1 snapshot(s): 1 ok, 0 unhashed, 0 corrupt.")
+
+	keys, err := archive.Backend.Keys(history.DirName + "/")
+	require.NoError(t, err)
+	path := filepath.Join(dataDir, filepath.FromSlash(keys[0]))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), `"updated":"a"`, `"updated":"z"`, 1)), 0o644))
+
+	stdout.Reset()
+	stderr.Reset()
+	code = runCLI([]string{"snapshot", "verify", "--json=false"}, &stdout, &stderr)
+	assert.Equal(t, ExitInternal, code)
+	assert.Contains(t, stdout.String(), "hash does not match")
+	assert.Contains(t, stderr.String(), "CORRUPT_DATA")
+
+	stdout.Reset()
+	code = runCLI([]string{"snapshot", "verify", "--repair", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "(removed)")
+	assert.NoFileExists(t, path)
+}
+
+func TestRunCLI_DiffComparesSavedAds(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, t.TempDir())
+	t.Setenv(config.EnvDataDir, dataDir)
+	archive := history.Archive{Backend: &storage.Files{Dir: dataDir}}
+	nutella, coffee := "Nutella", "Coffee"
+	oldPrice, newPrice := "$7.99", "$6.99"
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: time.Now().AddDate(0, 0, -7),
+		Deals: []api.SavingItem{{ID: "1", Title: &nutella}, {ID: "2", Title: &coffee, Savings: &oldPrice}}}))
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "b", SavedAt: time.Now(),
+		Deals: []api.SavingItem{{ID: "3", Title: &coffee, Savings: &newPrice}}}))
+	from := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	to := time.Now().Format("2006-01-02")
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"diff", "--store", "1425", "--from", from, "--to", to, "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "0 added, 1 removed, 1 changed, 0 unchanged.")
+	assert.Contains(t, stdout.String(), `- Coffee: savings "$7.99" → "$6.99"`)
+
+	stdout.Reset()
+	code = runCLI([]string{"diff", "--store", "1425", "--from", from, "--to", to, "--format", "patch"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	var ops []map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &ops))
+	require.Len(t, ops, 2)
+	assert.Equal(t, "/deals/Nutella", ops[0]["path"])
+
+	// --to alone compares with the ad saved before it.
+	stdout.Reset()
+	code = runCLI([]string{"diff", "--store", "1425", "--to", to, "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "0 added, 1 removed, 1 changed, 0 unchanged.")
+
+	code = runCLI([]string{"diff", "--store", "1425", "--from", "2001-01-01", "--to", to}, &stdout, &stderr)
+	assert.Equal(t, ExitNotFound, code)
+	code = runCLI([]string{"diff", "--store", "1425", "--from", "last week", "--to", to}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+}
+
+func TestRunCLI_SearchFindsDealsAcrossWeeks(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, t.TempDir())
+	t.Setenv(config.EnvDataDir, dataDir)
+	archive := history.Archive{Backend: &storage.Files{Dir: dataDir}}
+	coffee, chips, iced := "Coffee Beans", "Chips", "Iced Coffee"
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: time.Now().AddDate(0, 0, -7),
+		Deals: []api.SavingItem{{ID: "1", Title: &coffee}, {ID: "2", Title: &chips}}}))
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "b", SavedAt: time.Now(),
+		Deals: []api.SavingItem{{ID: "3", Title: &iced}}}))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"search", "coffee", "--json"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	var out struct {
+		Search searchJSON `json:"search"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	require.Equal(t, 2, out.Search.Total)
+	assert.Equal(t, "Iced Coffee", out.Search.Hits[0].Deal.Title)
+	assert.FileExists(t, filepath.Join(dataDir, search.IndexKey))
+
+	stdout.Reset()
+	code = runCLI([]string{"search", "coffee", "--limit", "1", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Showing 1 of 2")
+
+	code = runCLI([]string{"search", "salsa", "--json=false"}, &stdout, &stderr)
+	assert.Equal(t, ExitNotFound, code)
+}
+
+func TestRunCLI_SyncSharesListThroughDirectory(t *testing.T) {
+	remote := t.TempDir()
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"),
+		[]byte("sync:\n  provider: dir\n  path: "+remote+"\n  device: laptop\n"), 0o600))
+	t.Setenv(config.EnvConfigDir, configDir)
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	run := func(args ...string) (string, int) {
+		var stdout, stderr bytes.Buffer
+		code := runCLI(args, &stdout, &stderr)
+		return stdout.String() + stderr.String(), code
+	}
+
+	run("list", "add", "milk")
+	run("alert", "add", "coffee", "coffee")
+	out, code := run("sync", "now")
+	require.Equal(t, ExitSuccess, code, out)
+	assert.FileExists(t, filepath.Join(remote, "list.json"))
+	assert.FileExists(t, filepath.Join(remote, "watchlist.json"))
+
+	// A second device pulls both files.
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	out, code = run("sync", "status", "--json")
+	require.Equal(t, ExitSuccess, code, out)
+	assert.Contains(t, out, `"action":"pull"`)
+	run("sync", "now", "--json")
+	out, _ = run("list", "show", "--json")
+	assert.Contains(t, out, `"name":"milk"`)
+}
+
+func TestRunCLI_SyncNotConfigured(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"sync", "status"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "sync is not configured")
+}
+
+func TestRunCLI_ReportRequiresPeriod(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"report", "--store", "1425"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--weekly")
+
+	stderr.Reset()
+	code = runCLI([]string{"report", "--weekly", "--format", "pdf"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "markdown")
+}
+
+func TestRunCLI_AllStoresNeedsZip(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--store", "1425", "--all-stores"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--all-stores needs --zip")
+}
+
+func TestRunCLI_StoreTypeInvalid(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"--zip", "33101", "--store-type", "bakery", "--json"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), `unknown store type \"bakery\"`)
+}
+
+func TestRunCLI_StoreTypeCompletion(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"__complete", "--store-type", "regular,"}, &stdout, &stderr)
+	assert.Equal(t, ExitSuccess, code)
+	assert.Contains(t, stdout.String(), "regular,greenwise\tGreenWise Market stores")
+	assert.NotContains(t, stdout.String(), "regular,regular")
+}
+
+func TestRunCLI_ScheduleInstallListRemove(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("uses the systemd backend")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	var ran []string
+	scheduleRunner = func(_ context.Context, name string, args ...string) error {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		return nil
+	}
+	t.Cleanup(func() { scheduleRunner = schedule.Exec })
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"schedule", "install", "--weekly", "--at", "07:30", "report --weekly --store 1425"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, ran, "systemctl --user enable --now pubcli-report.timer")
+
+	stdout.Reset()
+	code = runCLI([]string{"schedule", "list", "--json"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"args":["report","--weekly","--store","1425"]`)
+	assert.Contains(t, stdout.String(), `"at":"07:30"`)
+
+	code = runCLI([]string{"schedule", "remove", "report"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	code = runCLI([]string{"schedule", "remove", "report"}, &stdout, &stderr)
+	assert.Equal(t, ExitNotFound, code)
+
+	stderr.Reset()
+	code = runCLI([]string{"schedule", "install", "--weekly", "--daily", "report"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "exactly one")
+}
+
+func TestRunCLI_FixturesGenerate(t *testing.T) {
+	dir := t.TempDir()
+	args := []string{"fixtures", "generate", "--deals", "25", "--store-count", "3", "--seed", "9", "--week-start", "2026-10-14", "--out", dir, "--json"}
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI(args, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"weekStart":"2026-10-14"`)
+	first, err := os.ReadFile(filepath.Join(dir, "savings.json"))
+	require.NoError(t, err)
+
+	code = runCLI(args, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	second, err := os.ReadFile(filepath.Join(dir, "savings.json"))
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	stderr.Reset()
+	code = runCLI([]string{"fixtures", "generate", "--week-start", "next week", "--out", dir}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "YYYY-MM-DD")
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+var flagSavingsYear int
+
+var savingsCmd = &cobra.Command{
+	Use:   "savings",
+	Short: "Total the estimated savings of finished shopping trips",
+	Long: "Add up the estimated savings of the shopping lists archived with `pubcli list done`, " +
+		"per year, or per month of one year with --year. Savings are read from each item's deal " +
+		"text when the list was archived, times its quantity, and corrected by the prices entered " +
+		"with `pubcli list reconcile`.",
+	Example: `  pubcli savings
+  pubcli savings --year 2025
+  pubcli savings --year 2025 --json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runSavings,
+}
+
+func init() {
+	rootCmd.AddCommand(savingsCmd)
+	savingsCmd.Flags().IntVar(&flagSavingsYear, "year", 0, "Only count trips in this year, totaled per month")
+}
+
+// savingsPeriod is the trips of one year, or one month with --year.
+type savingsPeriod struct {
+	// Period is "2025", or "2025-03" with --year.
+	Period  string  `json:"period"`
+	Trips   int     `json:"trips"`
+	Cost    float64 `json:"cost"`
+	Savings float64 `json:"savings"`
+	// Paid is what reconciled trips cost at the register.
+	Paid float64 `json:"paid"`
+}
+
+// savingsJSON is the --json output of `savings`.
+type savingsJSON struct {
+	Year    int             `json:"year,omitempty"`
+	Trips   int             `json:"trips"`
+	Cost    float64         `json:"cost"`
+	Savings float64         `json:"savings"`
+	Paid    float64         `json:"paid"`
+	Periods []savingsPeriod `json:"periods"`
+}
+
+func runSavings(cmd *cobra.Command, _ []string) error {
+	if flagSavingsYear < 0 {
+		return invalidArgsError("--year must be a year such as 2025", "pubcli savings --year 2025")
+	}
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	trips, err := archive.Trips()
+	if err != nil {
+		return configError(err)
+	}
+	summary := summarizeTrips(trips, flagSavingsYear)
+
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "savings", summary)
+	}
+	out := cmd.OutOrStdout()
+	if summary.Trips == 0 {
+		if flagSavingsYear > 0 {
+			fmt.Fprintf(out, "No finished shopping trips in %d.\n", flagSavingsYear)
+		} else {
+			fmt.Fprintln(out, "No finished shopping trips yet. Archive a list with `pubcli list done`.")
+		}
+		return nil
+	}
+	scope := ""
+	if flagSavingsYear > 0 {
+		scope = " in " + strconv.Itoa(flagSavingsYear)
+	}
+	fmt.Fprintf(out, "Estimated savings%s: %s over %d trip(s), spending about %s on priced items.\n",
+		scope, display.FormatMoney(summary.Savings), summary.Trips, display.FormatMoney(summary.Cost))
+	if summary.Paid > 0 {
+		fmt.Fprintf(out, "Receipts entered with `pubcli list reconcile` add up to %s.\n", display.FormatMoney(summary.Paid))
+	}
+	for _, p := range summary.Periods {
+		fmt.Fprintf(out, "  %-8s %3d trip(s)  saved %s\n", p.Period, p.Trips, display.FormatMoney(p.Savings))
+	}
+	return nil
+}
+
+// summarizeTrips totals trips per year, or per month of year when it is
+// not zero. Periods are in order and only those with trips are listed.
+func summarizeTrips(trips []history.Trip, year int) savingsJSON {
+	summary := savingsJSON{Year: year, Periods: []savingsPeriod{}}
+	index := map[string]int{}
+	for _, trip := range trips {
+		at := trip.CompletedAt.In(time.Local)
+		period := at.Format("2006")
+		if year > 0 {
+			if at.Year() != year {
+				continue
+			}
+			period = at.Format("2006-01")
+		}
+		i, ok := index[period]
+		if !ok {
+			i = len(summary.Periods)
+			index[period] = i
+			summary.Periods = append(summary.Periods, savingsPeriod{Period: period})
+		}
+		summary.Periods[i].Trips++
+		summary.Periods[i].Cost += trip.Cost
+		summary.Periods[i].Savings += trip.Savings
+		summary.Periods[i].Paid += trip.Paid
+		summary.Trips++
+		summary.Cost += trip.Cost
+		summary.Savings += trip.Savings
+		summary.Paid += trip.Paid
+	}
+	return summary
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestRunCLI_ListDoneAndSavings(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dir)
+	store := &storage.Files{Dir: dir}
+	t.Cleanup(resetCLIState)
+
+	l := &shoplist.List{Items: []shoplist.Item{
+		{Name: "Coffee", DealID: "1", Savings: "Save Up To $3.00", Qty: 2, Checked: true, Store: "1425"},
+		{Name: "Yogurt", DealID: "2", Savings: "2/$5.00"},
+	}}
+	require.NoError(t, l.Save(store, shoplist.FileName))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"list", "done"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Archived 1 item(s) from the list for the ad week of ")
+	assert.Contains(t, stdout.String(), "saving about $6.00.\n1 unticked item(s) stay on the list.\n")
+
+	left, err := shoplist.Load(store, shoplist.FileName)
+	require.NoError(t, err)
+	require.Len(t, left.Items, 1)
+	assert.Equal(t, "Yogurt", left.Items[0].Name)
+
+	trips, err := history.Archive{Backend: store}.Trips()
+	require.NoError(t, err)
+	require.Len(t, trips, 1)
+	assert.Equal(t, "1425", trips[0].Store)
+	assert.Equal(t, "default", trips[0].List)
+
+	stdout.Reset()
+	code = runCLI([]string{"savings", "--year", "1999", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Equal(t, "No finished shopping trips in 1999.\n", stdout.String())
+
+	stdout.Reset()
+	code = runCLI([]string{"savings", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Estimated savings: $6.00 over 1 trip(s)")
+}
+
+func TestSummarizeTrips(t *testing.T) {
+	at := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 12, 0, 0, 0, time.Local) }
+	trips := []history.Trip{
+		{CompletedAt: at(2025, 3, 5), Savings: 4, Cost: 20},
+		{CompletedAt: at(2025, 3, 19), Savings: 6, Cost: 30},
+		{CompletedAt: at(2025, 11, 2), Savings: 1},
+		{CompletedAt: at(2026, 1, 7), Savings: 10},
+	}
+
+	all := summarizeTrips(trips, 0)
+	assert.Equal(t, 4, all.Trips)
+	assert.InDelta(t, 21.0, all.Savings, 0.001)
+	assert.Equal(t, []savingsPeriod{
+		{Period: "2025", Trips: 3, Cost: 50, Savings: 11},
+		{Period: "2026", Trips: 1, Savings: 10},
+	}, all.Periods)
+
+	year := summarizeTrips(trips, 2025)
+	assert.Equal(t, 3, year.Trips)
+	assert.Equal(t, []savingsPeriod{
+		{Period: "2025-03", Trips: 2, Cost: 50, Savings: 10},
+		{Period: "2025-11", Trips: 1, Savings: 1},
+	}, year.Periods)
+
+	assert.Empty(t, summarizeTrips(trips, 1999).Periods)
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/schedule"
+)
+
+var (
+	flagScheduleHourly bool
+	flagScheduleDaily  bool
+	flagScheduleWeekly bool
+	flagScheduleDay    string
+	flagScheduleAt     string
+	flagScheduleName   string
+	flagScheduleDryRun bool
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run pubcli commands on a schedule without editing crontab",
+	Long: "Install a pubcli command as a recurring job in the system scheduler: a systemd user " +
+		"timer on Linux, a launchd agent on macOS, or a Task Scheduler task on Windows. Installed " +
+		"jobs are recorded in schedules.json in the data directory.",
+	Example: `  pubcli schedule install --weekly "report --weekly --store 1425 --format markdown"
+  pubcli schedule install --daily --at 07:30 "alert run --store 1425"
+  pubcli schedule list
+  pubcli schedule remove report`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var scheduleInstallCmd = &cobra.Command{
+	Use:   "install COMMAND",
+	Short: "Schedule a pubcli command",
+	Long: "Schedule COMMAND, the pubcli arguments as one quoted string. Weekly jobs run on " +
+		"Wednesday, when the new ad starts, unless --day says otherwise. Installing a job with " +
+		"an existing name replaces it. --dry-run prints the files and commands without running them.",
+	Example: `  pubcli schedule install --weekly "report --weekly --store 1425"
+  pubcli schedule install --weekly --day thu --at 18:00 --name thursday "report --weekly --zip 33101"
+  pubcli schedule install --hourly --at :15 "status --store 1425" --dry-run`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runScheduleInstall,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "Show scheduled jobs",
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runScheduleList,
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:         "remove NAME",
+	Aliases:     []string{"rm"},
+	Short:       "Unschedule a job and delete its files",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runScheduleRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleInstallCmd, scheduleListCmd, scheduleRemoveCmd)
+
+	f := scheduleInstallCmd.Flags()
+	f.BoolVar(&flagScheduleHourly, "hourly", false, "Run every hour")
+	f.BoolVar(&flagScheduleDaily, "daily", false, "Run every day")
+	f.BoolVar(&flagScheduleWeekly, "weekly", false, "Run every week")
+	f.StringVar(&flagScheduleDay, "day", "wed", "Weekday of weekly jobs")
+	f.StringVar(&flagScheduleAt, "at", "09:00", "Time of day, HH:MM (minutes only for hourly jobs)")
+	f.StringVar(&flagScheduleName, "name", "", "Job name (default: the command's first word)")
+	f.BoolVar(&flagScheduleDryRun, "dry-run", false, "Print what would be installed without changing anything")
+	scheduleRemoveCmd.Flags().BoolVar(&flagScheduleDryRun, "dry-run", false, "Print what would be removed without changing anything")
+}
+
+// scheduleRunner runs scheduler commands; tests replace it.
+var scheduleRunner schedule.Runner = schedule.Exec
+
+func loadScheduleRegistry() (*schedule.Registry, string, error) {
+	path, err := config.DataPath(schedule.RegistryFile)
+	if err != nil {
+		return nil, "", configError(err)
+	}
+	r, err := schedule.LoadRegistry(path)
+	if err != nil {
+		return nil, "", configError(err)
+	}
+	return r, path, nil
+}
+
+func runScheduleInstall(cmd *cobra.Command, args []string) error {
+	job, err := scheduleJobFromFlags(args)
+	if err != nil {
+		return err
+	}
+	backend, err := schedule.ForOS(runtime.GOOS)
+	if err != nil {
+		return invalidArgsError(err.Error())
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating the pubcli executable: %w", err)
+	}
+
+	plan := backend.InstallPlan(job, executable)
+	job.Backend = backend.Name()
+	job.InstalledAt = time.Now().UTC()
+	for _, f := range plan.Write {
+		job.Files = append(job.Files, f.Path)
+	}
+
+	out := cmd.OutOrStdout()
+	if flagScheduleDryRun {
+		printSchedulePlan(out, plan)
+		return nil
+	}
+
+	registry, path, err := loadScheduleRegistry()
+	if err != nil {
+		return err
+	}
+	if err := plan.Apply(cmd.Context(), scheduleRunner); err != nil {
+		return fmt.Errorf("installing %s job: %w", backend.Name(), err)
+	}
+	registry.Set(job)
+	if err := registry.Save(path); err != nil {
+		return err
+	}
+
+	if flagJSON {
+		return display.PrintVersionedJSON(out, "schedule", job)
+	}
+	fmt.Fprintf(out, "Scheduled %q (%s) with %s: pubcli %s\n", job.Name, job.Describe(), job.Backend, strings.Join(job.Args, " "))
+	return nil
+}
+
+// scheduleJobFromFlags builds the job for `schedule install`. The command is
+// usually one quoted string, but separate words are accepted too.
+func scheduleJobFromFlags(args []string) (schedule.Job, error) {
+	words := args
+	if len(args) == 1 {
+		split, err := schedule.SplitArgs(args[0])
+		if err != nil {
+			return schedule.Job{}, invalidArgsError(fmt.Sprintf("cannot parse command %q: %v", args[0], err))
+		}
+		words = split
+	}
+	if len(words) > 0 && (words[0] == "pubcli" || words[0] == rootCmd.Name()) {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return schedule.Job{}, invalidArgsError("no pubcli command to schedule", `pubcli schedule install --weekly "report --weekly --store 1425"`)
+	}
+
+	job := schedule.Job{Args: words, Day: flagScheduleDay, At: flagScheduleAt, Name: flagScheduleName}
+	set := 0
+	for frequency, on := range map[string]bool{schedule.Hourly: flagScheduleHourly, schedule.Daily: flagScheduleDaily, schedule.Weekly: flagScheduleWeekly} {
+		if on {
+			job.Frequency = frequency
+			set++
+		}
+	}
+	if set != 1 {
+		return schedule.Job{}, invalidArgsError(
+			"choose exactly one of --hourly, --daily, or --weekly",
+			`pubcli schedule install --weekly "report --weekly --store 1425"`,
+		)
+	}
+	if job.Frequency == schedule.Hourly && strings.HasPrefix(job.At, ":") {
+		job.At = "00" + job.At
+	}
+	if job.Name == "" {
+		job.Name = strings.ToLower(words[0])
+	}
+	if err := job.Validate(); err != nil {
+		return schedule.Job{}, invalidArgsError(err.Error(), `pubcli schedule install --weekly --day wed --at 09:00 "report --weekly --store 1425"`)
+	}
+	return job, nil
+}
+
+func runScheduleList(cmd *cobra.Command, _ []string) error {
+	registry, _, err := loadScheduleRegistry()
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if flagJSON {
+		jobs := registry.Jobs
+		if jobs == nil {
+			jobs = []schedule.Job{}
+		}
+		return display.PrintVersionedJSON(out, "schedules", jobs)
+	}
+	if len(registry.Jobs) == 0 {
+		fmt.Fprintln(out, "No scheduled jobs. Add one with `pubcli schedule install --weekly COMMAND`.")
+		return nil
+	}
+	for _, job := range registry.Jobs {
+		fmt.Fprintf(out, "%s: pubcli %s — %s (%s)\n", job.Name, strings.Join(job.Args, " "), job.Describe(), job.Backend)
+	}
+	return nil
+}
+
+func runScheduleRemove(cmd *cobra.Command, args []string) error {
+	registry, path, err := loadScheduleRegistry()
+	if err != nil {
+		return err
+	}
+	job, ok := registry.Find(args[0])
+	if !ok {
+		return notFoundError(fmt.Sprintf("no scheduled job named %q", args[0]), "pubcli schedule list")
+	}
+	backend, err := schedule.ForOS(runtime.GOOS)
+	if err != nil {
+		return invalidArgsError(err.Error())
+	}
+	if backend.Name() != job.Backend {
+		return invalidArgsError(fmt.Sprintf("job %q was installed with %s, which is not available here", job.Name, job.Backend))
+	}
+
+	plan := backend.RemovePlan(job)
+	out := cmd.OutOrStdout()
+	if flagScheduleDryRun {
+		printSchedulePlan(out, plan)
+		return nil
+	}
+	if err := plan.Apply(cmd.Context(), scheduleRunner); err != nil {
+		return fmt.Errorf("removing %s job: %w", backend.Name(), err)
+	}
+	registry.Remove(job.Name)
+	if err := registry.Save(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Removed scheduled job %q.\n", job.Name)
+	return nil
+}
+
+func printSchedulePlan(w io.Writer, plan schedule.Plan) {
+	for _, c := range plan.Before {
+		fmt.Fprintf(w, "run (errors ignored): %s\n", strings.Join(c, " "))
+	}
+	for _, f := range plan.Write {
+		fmt.Fprintf(w, "write %s:\n%s\n", f.Path, f.Content)
+	}
+	for _, p := range plan.Remove {
+		fmt.Fprintf(w, "remove %s\n", p)
+	}
+	for _, c := range plan.After {
+		fmt.Fprintf(w, "run: %s\n", strings.Join(c, " "))
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/search"
+)
+
+const searchDefaultLimit = 50
+
+var (
+	flagSearchLimit   int
+	flagSearchReindex bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search WORDS...",
+	Short: "Search the deals of every saved weekly ad",
+	Long: "Find deals whose title, description, or brand contains every word, each as the start " +
+		"of a word, across all the ads saved in the history, newest first. --store limits the " +
+		"search to one store's ads. The history is indexed as it is saved (in an FTS5 table with " +
+		"the SQLite storage backend), so searching many weeks reads only the ads that match. " +
+		"--reindex rebuilds the index from the history.",
+	Example: `  pubcli search coffee
+  pubcli search cold brew --store 1425
+  pubcli search --reindex nutella --json`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().IntVarP(&flagSearchLimit, "limit", "n", searchDefaultLimit, "Show at most N deals (0 = all)")
+	searchCmd.Flags().BoolVar(&flagSearchReindex, "reindex", false, "Rebuild the search index from the history first")
+}
+
+// searchJSON is the --json output of `search`.
+type searchJSON struct {
+	Query string          `json:"query"`
+	Total int             `json:"total"`
+	Hits  []searchHitJSON `json:"hits"`
+}
+
+type searchHitJSON struct {
+	Store   string           `json:"store"`
+	Date    string           `json:"date"`
+	Updated string           `json:"updated,omitempty"`
+	Deal    display.DealJSON `json:"deal"`
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+	if len(search.Words(query)) == 0 && !flagSearchReindex {
+		return invalidArgsError("give words to search for", "pubcli search coffee")
+	}
+	if flagSearchLimit < 0 {
+		return invalidArgsError("--limit must not be negative", "pubcli search coffee --limit 20")
+	}
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	idx, err := search.Open(archive.Backend)
+	if err != nil {
+		return configError(err)
+	}
+	if flagSearchReindex {
+		if err := search.Rebuild(archive, idx); err != nil {
+			return err
+		}
+		if len(search.Words(query)) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Rebuilt the search index.")
+			return nil
+		}
+	}
+
+	hits, err := search.Search(archive, idx, query, strings.TrimSpace(flagStore))
+	if err != nil {
+		return err
+	}
+	out := searchJSON{Query: query, Total: len(hits), Hits: []searchHitJSON{}}
+	if flagSearchLimit > 0 && len(hits) > flagSearchLimit {
+		hits = hits[:flagSearchLimit]
+	}
+	for _, h := range hits {
+		out.Hits = append(out.Hits, searchHitJSON{Store: h.Store, Date: h.Date, Updated: h.Updated, Deal: display.ToDealJSON(h.Deal)})
+	}
+
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "search", out)
+	}
+	if out.Total == 0 {
+		return notFoundError(
+			fmt.Sprintf("no saved deals match %q", query),
+			"Ads are saved by pubcli report, trends, insights, watch, and track check.",
+		)
+	}
+	printSearch(cmd.OutOrStdout(), out)
+	return nil
+}
+
+func printSearch(w io.Writer, out searchJSON) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, h := range out.Hits {
+		fmt.Fprintf(tw, "%s\t#%s\t%s\t%s\n", h.Date, h.Store, h.Deal.Title, h.Deal.Savings)
+	}
+	tw.Flush()
+	if len(out.Hits) < out.Total {
+		fmt.Fprintf(w, "Showing %d of %d; use --limit 0 for all.\n", len(out.Hits), out.Total)
+	}
+}
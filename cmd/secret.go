@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/console"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/secrets"
+)
+
+// envSecretsPassphrase encrypts the secrets file with a passphrase instead
+// of the random key beside it.
+const envSecretsPassphrase = "PUBCLI_SECRETS_PASSPHRASE"
+
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Keep tokens and passwords out of the config file",
+	Long: "Store webhook URLs, bot tokens, and passwords in the OS keyring (the macOS Keychain, " +
+		"the Windows Credential Manager, or the Linux kernel keyring) or in an encrypted file in " +
+		"the data directory, and refer to them from config.yaml as secret:NAME. `secrets.backend` " +
+		"in config.yaml chooses the store: auto (the default) uses the keyring on macOS and " +
+		"Windows and the encrypted file elsewhere, since the Linux kernel keyring is cleared on " +
+		"reboot. The file is encrypted with $" + envSecretsPassphrase + " when it is set, and " +
+		"with a random key kept beside it otherwise.",
+	Example: `  pubcli secret set telegram-token < token.txt
+  pubcli config set alerts.telegram.bot_token secret:telegram-token
+  pubcli secret list`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set NAME",
+	Short: "Store a secret read from standard input",
+	Long: "Store a secret, replacing any earlier value. The value is read from standard input, " +
+		"so it stays out of the shell history; a trailing newline is dropped.",
+	Example: `  pubcli secret set telegram-token < token.txt
+  printf %s "$WEBHOOK_URL" | pubcli secret set webhook`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runSecretSet,
+}
+
+var secretRemoveCmd = &cobra.Command{
+	Use:         "remove NAME",
+	Aliases:     []string{"rm"},
+	Short:       "Delete a stored secret",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runSecretRemove,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the settings that refer to secrets, and whether each is stored",
+	Long: "List the config settings written as secret:NAME and whether the secret is stored. " +
+		"Values are never printed.",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runSecretList,
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretSetCmd, secretRemoveCmd, secretListCmd)
+}
+
+// openSecretStore opens the store a `secrets.backend` setting names.
+func openSecretStore(backend string) (secrets.Store, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return nil, configError(err)
+	}
+	store, err := secrets.Open(backend, dir, os.Getenv(envSecretsPassphrase))
+	if err != nil {
+		return nil, configError(fmt.Errorf("secrets.backend: %w", err))
+	}
+	return store, nil
+}
+
+// resolveSecret returns a config value, or the secret it names when it is
+// written as secret:NAME. setting names the value in errors.
+func resolveSecret(setting, value string) (string, error) {
+	if _, ok := secrets.Ref(value); !ok {
+		return value, nil
+	}
+	store, err := openSecretStore(activeConfig.Secrets.Backend)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := secrets.Resolve(store, value)
+	if err != nil {
+		return "", configError(fmt.Errorf("%s: %w", setting, err))
+	}
+	return resolved, nil
+}
+
+// secretRef is a setting that refers to a secret.
+type secretRef struct {
+	Setting string `json:"setting"`
+	Name    string `json:"name"`
+	Stored  bool   `json:"stored"`
+}
+
+// secretRefs lists the settings of cfg written as secret:NAME.
+func secretRefs(cfg *config.Config) []secretRef {
+	var refs []secretRef
+	add := func(setting, value string) {
+		if name, ok := secrets.Ref(value); ok {
+			refs = append(refs, secretRef{Setting: setting, Name: name})
+		}
+	}
+	add("slack_signing_secret", cfg.SlackSigningSecret)
+	names := make([]string, 0, len(cfg.Headers))
+	for name := range cfg.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add("headers."+name, cfg.Headers[name])
+	}
+	for i, url := range cfg.Alerts.Webhooks {
+		add(fmt.Sprintf("alerts.webhooks[%d]", i), url)
+	}
+	if tg := cfg.Alerts.Telegram; tg != nil {
+		add("alerts.telegram.bot_token", tg.BotToken)
+	}
+	if s := cfg.Sync; s != nil {
+		add("sync.password", s.Password)
+	}
+	return refs
+}
+
+// checkSecretRefs looks up every secret cfg refers to, and reports the
+// first that is not stored.
+func checkSecretRefs(cfg *config.Config) ([]secretRef, error) {
+	refs := secretRefs(cfg)
+	if len(refs) == 0 {
+		return refs, nil
+	}
+	store, err := openSecretStore(cfg.Secrets.Backend)
+	if err != nil {
+		return nil, err
+	}
+	var missing error
+	for i, ref := range refs {
+		_, err := secrets.Resolve(store, secrets.Prefix+ref.Name)
+		refs[i].Stored = err == nil
+		if err != nil && missing == nil {
+			missing = fmt.Errorf("%s: %w", ref.Setting, err)
+		}
+	}
+	return refs, missing
+}
+
+func runSecretSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := secrets.ValidateName(name); err != nil {
+		return invalidArgsError(err.Error(), "pubcli secret set telegram-token < token.txt")
+	}
+	in := cmd.InOrStdin()
+	if file, ok := in.(*os.File); ok && console.IsTerminal(file) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Value of %s: ", name)
+	}
+	value, err := readSecretValue(in)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return invalidArgsError("no secret value on standard input", "pubcli secret set "+name+" < value.txt")
+	}
+
+	store, err := openSecretStore(activeConfig.Secrets.Backend)
+	if err != nil {
+		return err
+	}
+	if err := store.Set(name, value); err != nil {
+		return fmt.Errorf("storing secret %q: %w", name, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Stored %s in the %s. Refer to it as %s%s in config.yaml.\n", name, store.Name(), secrets.Prefix, name)
+	return nil
+}
+
+// readSecretValue reads a secret from the first line of standard input, or
+// all of it when it is piped without a trailing newline.
+func readSecretValue(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func runSecretRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	store, err := openSecretStore(activeConfig.Secrets.Backend)
+	if err != nil {
+		return err
+	}
+	err = store.Delete(name)
+	if errors.Is(err, secrets.ErrNotFound) {
+		return notFoundError(fmt.Sprintf("no secret named %q in the %s", name, store.Name()), "pubcli secret list")
+	}
+	if err != nil {
+		return fmt.Errorf("deleting secret %q: %w", name, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s.\n", name)
+	return nil
+}
+
+// secretListJSON is the --json output of `secret list`.
+type secretListJSON struct {
+	Backend string      `json:"backend"`
+	Refs    []secretRef `json:"refs"`
+}
+
+func runSecretList(cmd *cobra.Command, _ []string) error {
+	store, err := openSecretStore(activeConfig.Secrets.Backend)
+	if err != nil {
+		return err
+	}
+	refs, _ := checkSecretRefs(activeConfig)
+	if refs == nil {
+		refs = []secretRef{}
+	}
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "secrets", secretListJSON{Backend: store.Name(), Refs: refs})
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Secrets are kept in the %s.\n", store.Name())
+	if len(refs) == 0 {
+		fmt.Fprintln(out, "No settings refer to a secret; write one as secret:NAME in config.yaml.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, ref := range refs {
+		status := "stored"
+		if !ref.Stored {
+			status = "missing; run `pubcli secret set " + ref.Name + "`"
+		}
+		fmt.Fprintf(tw, "%s\t%s%s\t%s\n", ref.Setting, secrets.Prefix, ref.Name, status)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/secrets"
+)
+
+func TestRunCLI_Secret(t *testing.T) {
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	t.Setenv(config.EnvConfigDir, configDir)
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Setenv(envSecretsPassphrase, "")
+	t.Cleanup(resetCLIState)
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(
+		"secrets:\n  backend: file\nalerts:\n  telegram:\n    bot_token: secret:telegram-token\n    chat_id: \"42\"\n"), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"secret", "list", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "alerts.telegram.bot_token  secret:telegram-token  missing")
+
+	stdout.Reset()
+	code = runCLI([]string{"config", "validate"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), `secret \"telegram-token\" is not stored`)
+
+	secretSetCmd.SetIn(strings.NewReader("123:abc\n"))
+	t.Cleanup(func() { secretSetCmd.SetIn(nil) })
+	stdout.Reset()
+	stderr.Reset()
+	code = runCLI([]string{"secret", "set", "telegram-token"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Stored telegram-token")
+
+	value, err := secrets.NewFile(dataDir, "").Get("telegram-token")
+	require.NoError(t, err)
+	assert.Equal(t, "123:abc", value)
+
+	notifiers, err := alertNotifiers(config.Alerts{Telegram: &config.Telegram{BotToken: "secret:telegram-token", ChatID: "42"}})
+	require.NoError(t, err)
+	assert.Len(t, notifiers, 1)
+
+	stdout.Reset()
+	code = runCLI([]string{"config", "validate"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+
+	stdout.Reset()
+	code = runCLI([]string{"secret", "rm", "telegram-token"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	code = runCLI([]string{"secret", "rm", "telegram-token"}, &stdout, &stderr)
+	assert.Equal(t, ExitNotFound, code)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/server"
+)
+
+// envSlackSigningSecret holds the Slack app's signing secret for /slack.
+const envSlackSigningSecret = "PUBCLI_SLACK_SIGNING_SECRET"
+
+var (
+	flagServeAddr   string
+	flagServePort   int
+	flagServeMaxAge time.Duration
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve deals, categories, and stores as JSON over HTTP",
+	Long: "Run a local HTTP server exposing /deals, /categories, /stores, and /compare. " +
+		"Responses carry ETag, Last-Modified, and Cache-Control headers derived from the " +
+		"weekly ad's update time, so pollers can send If-None-Match and get 304 Not Modified. " +
+		"When a Slack signing secret is configured ($" + envSlackSigningSecret + " or " +
+		"slack_signing_secret in config.yaml), POST /slack answers slash commands such as " +
+		"`/publix bogo produce` for the --store/--zip store.",
+	Example: `  pubcli serve
+  pubcli serve --port 9000
+  pubcli serve --addr 127.0.0.1:9000 --max-age 10m
+  curl 'http://127.0.0.1:8080/deals?zip=33101&category=produce&min-percent-off=25'`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&flagServeAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().IntVar(&flagServePort, "port", 0, "Port to listen on, keeping the host of --addr")
+	serveCmd.Flags().DurationVar(&flagServeMaxAge, "max-age", server.DefaultMaxAge, "Cache-Control max-age for deal responses")
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	if flagServeMaxAge < 0 {
+		return invalidArgsError("--max-age must be >= 0", "pubcli serve --max-age 5m")
+	}
+	if cmd.Flags().Changed("port") {
+		if flagServePort < 0 || flagServePort > 65535 {
+			return invalidArgsError("--port must be between 0 and 65535", "pubcli serve --port 8080")
+		}
+		host, _, err := net.SplitHostPort(flagServeAddr)
+		if err != nil {
+			return invalidArgsError(
+				fmt.Sprintf("invalid --addr %q: %v", flagServeAddr, err),
+				"pubcli serve --addr 127.0.0.1:8080",
+			)
+		}
+		flagServeAddr = net.JoinHostPort(host, strconv.Itoa(flagServePort))
+	}
+
+	listener, err := net.Listen("tcp", flagServeAddr)
+	if err != nil {
+		return invalidArgsError(
+			fmt.Sprintf("cannot listen on %s: %v", flagServeAddr, err),
+			"pubcli serve --addr 127.0.0.1:9000",
+		)
+	}
+
+	slackSecret := strings.TrimSpace(os.Getenv(envSlackSigningSecret))
+	if slackSecret == "" {
+		slackSecret, err = resolveSecret("slack_signing_secret", strings.TrimSpace(activeConfig.SlackSigningSecret))
+		if err != nil {
+			return err
+		}
+	}
+
+	srv := &http.Server{
+		Handler: server.New(newAPIClient(), server.Config{
+			MaxAge:             flagServeMaxAge,
+			SchemaVersion:      display.SchemaVersion(),
+			SlackSigningSecret: slackSecret,
+			DefaultStore:       flagStore,
+			DefaultZip:         flagZip,
+		}).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "serving on http://%s\n", listener.Addr())
+	if slackSecret != "" {
+		fmt.Fprintf(cmd.ErrOrStderr(), "slack slash commands enabled at http://%s/slack\n", listener.Addr())
+	}
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving: %w", err)
+	}
+	return nil
+}
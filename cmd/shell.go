@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+const shellPrompt = "pubcli> "
+
+// shellCommands are the REPL verbs. Any unique prefix is accepted, so `dep`
+// completes to `department` and `ex` to `export`.
+var shellCommands = []string{
+	"bogo", "category", "categories", "department", "export", "help",
+	"history", "limit", "query", "quit", "reset", "show", "sort", "store",
+}
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Interactive shell that keeps the weekly ad in memory between queries",
+	Long: "Fetch the weekly ad once, then refine it with successive commands " +
+		"(bogo, category meat, sort savings, export csv) without re-fetching. " +
+		"Type `help` inside the shell for the command list.",
+	Example: `  pubcli shell --zip 33101
+  pubcli shell --store 1425 --category produce`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runShell,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+	registerDealFilterFlags(shellCmd.Flags())
+}
+
+type shellLoader func(ctx context.Context, storeNumber, zipCode string) (resolvedStoreNumber, storeLabel string, items []api.SavingItem, err error)
+
+type shellSession struct {
+	ctx  context.Context
+	out  io.Writer
+	load shellLoader
+
+	storeNumber string
+	storeLabel  string
+	items       []api.SavingItem
+
+	initialOpts filter.Options
+	opts        filter.Options
+	history     []string
+}
+
+func runShell(cmd *cobra.Command, _ []string) error {
+	if err := validateSortMode(); err != nil {
+		return err
+	}
+	if err := validateMinPercentOff(); err != nil {
+		return err
+	}
+
+	opts := filter.Options{
+		BOGO:           flagBogo,
+		Category:       flagCategory,
+		Department:     flagDepartment,
+		Query:          flagQuery,
+		Sort:           flagSort,
+		Limit:          flagLimit,
+		ExcludeExpired: !flagIncludeExpired,
+		MinPercentOff:  flagMinPercentOff,
+	}
+	session := &shellSession{
+		ctx:         cmd.Context(),
+		out:         cmd.OutOrStdout(),
+		load:        loadTUIData,
+		initialOpts: opts,
+		opts:        opts,
+	}
+	if err := session.switchStore(flagStore, flagZip); err != nil {
+		return err
+	}
+	return session.run(cmd.InOrStdin())
+}
+
+func (s *shellSession) switchStore(storeNumber, zipCode string) error {
+	resolved, label, items, err := s.load(s.ctx, storeNumber, zipCode)
+	if err != nil {
+		return err
+	}
+	s.storeNumber, s.storeLabel, s.items = resolved, label, items
+	fmt.Fprintf(s.out, "Loaded %d deals for store %s. Type `help` for commands.\n", len(items), label)
+	return nil
+}
+
+func (s *shellSession) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(s.out, shellPrompt)
+		if !scanner.Scan() {
+			fmt.Fprintln(s.out)
+			return scanner.Err()
+		}
+
+		line, err := s.expandHistory(strings.TrimSpace(scanner.Text()))
+		if err != nil {
+			fmt.Fprintf(s.out, "error: %v\n", err)
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		s.history = append(s.history, line)
+
+		quit, err := s.exec(line)
+		if err != nil {
+			fmt.Fprintf(s.out, "error: %s\n", shellErrorMessage(err))
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// expandHistory replaces `!!` with the previous command and `!N` with the
+// Nth entry shown by `history`.
+func (s *shellSession) expandHistory(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") {
+		return line, nil
+	}
+	if len(s.history) == 0 {
+		return "", fmt.Errorf("history is empty")
+	}
+	if line == "!!" {
+		line = s.history[len(s.history)-1]
+	} else {
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 1 || n > len(s.history) {
+			return "", fmt.Errorf("no history entry %s", line)
+		}
+		line = s.history[n-1]
+	}
+	fmt.Fprintln(s.out, line)
+	return line, nil
+}
+
+func (s *shellSession) exec(line string) (quit bool, err error) {
+	verb, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	command, err := resolveShellCommand(verb)
+	if err != nil {
+		return false, err
+	}
+
+	switch command {
+	case "quit":
+		return true, nil
+	case "help":
+		s.printHelp()
+	case "history":
+		for i, entry := range s.history {
+			fmt.Fprintf(s.out, "%4d  %s\n", i+1, entry)
+		}
+	case "show":
+		s.show()
+	case "categories":
+		display.PrintCategories(s.out, filter.Categories(s.items), s.storeNumber)
+	case "store":
+		if arg == "" {
+			fmt.Fprintf(s.out, "Store: %s\n", s.storeLabel)
+			return false, nil
+		}
+		storeNumber, zipCode := parseStoreOrZip(arg)
+		return false, s.switchStore(storeNumber, zipCode)
+	case "export":
+		return false, s.export(arg)
+	case "reset":
+		s.opts = s.initialOpts
+		s.summary()
+	default:
+		if err := s.setFilter(command, arg); err != nil {
+			return false, err
+		}
+		s.summary()
+	}
+	return false, nil
+}
+
+func (s *shellSession) setFilter(command, arg string) error {
+	switch command {
+	case "bogo":
+		switch strings.ToLower(arg) {
+		case "":
+			s.opts.BOGO = !s.opts.BOGO
+		case "on", "true", "yes":
+			s.opts.BOGO = true
+		case "off", "false", "no":
+			s.opts.BOGO = false
+		default:
+			return fmt.Errorf("usage: bogo [on|off]")
+		}
+	case "category":
+		s.opts.Category = arg
+	case "department":
+		s.opts.Department = arg
+	case "query":
+		s.opts.Query = arg
+	case "sort":
+		if err := validateSort(arg); err != nil {
+			return err
+		}
+		s.opts.Sort = arg
+	case "limit":
+		if arg == "" {
+			s.opts.Limit = 0
+			return nil
+		}
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			return fmt.Errorf("usage: limit N (0 = all)")
+		}
+		s.opts.Limit = n
+	}
+	return nil
+}
+
+func (s *shellSession) filtered() []api.SavingItem {
+	return filter.Apply(s.items, s.opts)
+}
+
+func (s *shellSession) summary() {
+	fmt.Fprintf(s.out, "%d of %d deals match (%s)\n", len(s.filtered()), len(s.items), describeShellFilters(s.opts))
+}
+
+func (s *shellSession) show() {
+	items := s.filtered()
+	if len(items) == 0 {
+		fmt.Fprintln(s.out, "No deals match the current filters.")
+		return
+	}
+	display.PrintDeals(s.out, items)
+}
+
+// export writes the filtered deals as csv or json, to stdout or to a file.
+func (s *shellSession) export(arg string) error {
+	format, path, _ := strings.Cut(arg, " ")
+	format = strings.ToLower(strings.TrimSpace(format))
+	path = strings.TrimSpace(path)
+
+	var write func(io.Writer, []api.SavingItem) error
+	switch format {
+	case "csv":
+		write = display.PrintDealsCSV
+	case "json":
+		write = display.PrintDealsJSON
+	default:
+		return fmt.Errorf("usage: export csv|json [FILE]")
+	}
+
+	items := s.filtered()
+	if path == "" {
+		return write(s.out, items)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f, items); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Wrote %d deals to %s\n", len(items), path)
+	return nil
+}
+
+func (s *shellSession) printHelp() {
+	fmt.Fprint(s.out, `Commands (any unique prefix works):
+  show                   print deals matching the current filters
+  bogo [on|off]          toggle or set the BOGO-only filter
+  category [NAME]        filter by category (no NAME clears it)
+  department [NAME]      filter by department (no NAME clears it)
+  query [TEXT]           search titles and descriptions (no TEXT clears it)
+  sort [MODE]            relevance, savings, or ending
+  limit [N]              cap results (0 or no N = all)
+  reset                  restore the filters the shell started with
+  categories             list categories with deal counts
+  store [NUMBER|ZIP]     show or switch the store (re-fetches the ad)
+  export csv|json [FILE] write matching deals to stdout or FILE
+  history, !!, !N        list or re-run previous commands
+  quit                   leave the shell
+`)
+}
+
+func resolveShellCommand(verb string) (string, error) {
+	verb = strings.ToLower(verb)
+	switch verb {
+	case "exit", "q":
+		return "quit", nil
+	case "?":
+		return "help", nil
+	case "ls", "list":
+		return "show", nil
+	case "search":
+		return "query", nil
+	}
+
+	var matches []string
+	for _, name := range shellCommands {
+		if name == verb {
+			return name, nil
+		}
+		if strings.HasPrefix(name, verb) {
+			matches = append(matches, name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("unknown command %q (type `help`)", verb)
+	case 1:
+		return matches[0], nil
+	default:
+		sort.Strings(matches)
+		return "", fmt.Errorf("%q is ambiguous: %s", verb, strings.Join(matches, ", "))
+	}
+}
+
+func describeShellFilters(opts filter.Options) string {
+	var parts []string
+	if opts.BOGO {
+		parts = append(parts, "bogo")
+	}
+	if opts.Category != "" {
+		parts = append(parts, "category="+opts.Category)
+	}
+	if opts.Department != "" {
+		parts = append(parts, "department="+opts.Department)
+	}
+	if opts.Query != "" {
+		parts = append(parts, "query="+opts.Query)
+	}
+	if opts.Sort != "" {
+		parts = append(parts, "sort="+opts.Sort)
+	}
+	if opts.Limit > 0 {
+		parts = append(parts, fmt.Sprintf("limit=%d", opts.Limit))
+	}
+	if len(parts) == 0 {
+		return "no filters"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func shellErrorMessage(err error) string {
+	if cliErr, ok := err.(*cliError); ok {
+		return cliErr.Message
+	}
+	return err.Error()
+}
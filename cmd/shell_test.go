@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func newTestShell(out *bytes.Buffer) *shellSession {
+	return &shellSession{
+		ctx: context.Background(),
+		out: out,
+		load: func(_ context.Context, storeNumber, zipCode string) (string, string, []api.SavingItem, error) {
+			return storeNumber, "#" + storeNumber, []api.SavingItem{
+				{ID: "1", Title: strPtr("Chicken"), Department: strPtr("Meat"), Categories: []string{"meat"}},
+				{ID: "2", Title: strPtr("Nutella"), Categories: []string{"bogo", "grocery"}},
+				{ID: "3", Title: strPtr("Spinach"), Categories: []string{"produce"}},
+			}, nil
+		},
+	}
+}
+
+func TestShellSession_FiltersWithoutRefetch(t *testing.T) {
+	var out bytes.Buffer
+	s := newTestShell(&out)
+	require.NoError(t, s.switchStore("1425", ""))
+
+	input := strings.Join([]string{"bogo", "show", "bogo off", "cat", "category meat", "!!", "history", "sort nope", "quit"}, "\n")
+	require.NoError(t, s.run(strings.NewReader(input)))
+
+	text := out.String()
+	assert.Contains(t, text, "1 of 3 deals match (bogo)")
+	assert.Contains(t, text, "Nutella")
+	assert.Contains(t, text, "3 of 3 deals match (no filters)")
+	assert.Contains(t, text, `"cat" is ambiguous: categories, category`)
+	assert.Contains(t, text, "1 of 3 deals match (category=meat)")
+	assert.Contains(t, text, "   6  category meat")
+	assert.Contains(t, text, "invalid value for --sort")
+}
+
+func TestShellSession_ExportCSVToFile(t *testing.T) {
+	var out bytes.Buffer
+	s := newTestShell(&out)
+	require.NoError(t, s.switchStore("1425", ""))
+	path := filepath.Join(t.TempDir(), "deals.csv")
+
+	require.NoError(t, s.run(strings.NewReader("dep meat\nexport csv "+path+"\n")))
+
+	assert.Contains(t, out.String(), "Wrote 1 deals to "+path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Chicken")
+	assert.NotContains(t, string(data), "Nutella")
+}
+
+func TestResolveShellCommand(t *testing.T) {
+	for verb, want := range map[string]string{"so": "sort", "exit": "quit", "ls": "show", "export": "export"} {
+		got, err := resolveShellCommand(verb)
+		require.NoError(t, err, verb)
+		assert.Equal(t, want, got, verb)
+	}
+	_, err := resolveShellCommand("zzz")
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+var flagSnapshotRepair bool
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Inspect the saved weekly ad history",
+	Long: "Each weekly ad snapshot in the history is saved with a SHA-256 hash of its " +
+		"contents, and every read checks it, so a damaged snapshot is an error rather than a " +
+		"wrong report.",
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var snapshotVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check every saved snapshot against its hash",
+	Long: "Check every weekly ad snapshot in the history against its hash and list the ones " +
+		"that are corrupt. Snapshots saved before hashing are reported as unhashed. With " +
+		"--repair, corrupt snapshots are removed; the next command that fetches that store's ad " +
+		"saves a good one. Exits non-zero when corrupt snapshots are left in place.",
+	Example: `  pubcli snapshot verify
+  pubcli snapshot verify --repair`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runSnapshotVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotVerifyCmd)
+	snapshotVerifyCmd.Flags().BoolVar(&flagSnapshotRepair, "repair", false, "Remove corrupt snapshots")
+}
+
+// snapshotVerifyJSON is the --json output of `snapshot verify`.
+type snapshotVerifyJSON struct {
+	Checked  int                 `json:"checked"`
+	OK       int                 `json:"ok"`
+	Unhashed int                 `json:"unhashed"`
+	Corrupt  []snapshotCheckJSON `json:"corrupt"`
+}
+
+type snapshotCheckJSON struct {
+	Key     string `json:"key"`
+	Store   string `json:"store"`
+	Error   string `json:"error"`
+	Removed bool   `json:"removed"`
+}
+
+func runSnapshotVerify(cmd *cobra.Command, _ []string) error {
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	checks, err := archive.Verify(flagSnapshotRepair)
+	if err != nil {
+		return configError(err)
+	}
+
+	out := snapshotVerifyJSON{Checked: len(checks), Corrupt: []snapshotCheckJSON{}}
+	left := 0
+	for _, c := range checks {
+		switch c.Status {
+		case history.CheckOK:
+			out.OK++
+		case history.CheckUnhashed:
+			out.Unhashed++
+		case history.CheckCorrupt:
+			out.Corrupt = append(out.Corrupt, snapshotCheckJSON{Key: c.Key, Store: c.Store, Error: c.Err.Error(), Removed: c.Removed})
+			if !c.Removed {
+				left++
+			}
+		}
+	}
+
+	w := cmd.OutOrStdout()
+	if flagJSON {
+		if err := display.PrintVersionedJSON(w, "verify", out); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range out.Corrupt {
+			note := ""
+			if c.Removed {
+				note = " (removed)"
+			}
+			fmt.Fprintf(w, "corrupt %s: %s%s\n", c.Key, c.Error, note)
+		}
+		fmt.Fprintf(w, "Checked %d snapshot(s): %d ok, %d unhashed, %d corrupt.\n",
+			out.Checked, out.OK, out.Unhashed, len(out.Corrupt))
+	}
+	if left > 0 {
+		return corruptDataError(fmt.Sprintf("%d corrupt snapshot(s) in the ad history", left))
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+const (
+	statusTopBogos      = 3
+	statusDefaultNewFor = 24 * time.Hour
+	statusSeenFile      = "status-seen.json"
+)
+
+var (
+	flagStatusFormat string
+	flagStatusNewFor time.Duration
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "One-line weekly ad summary for status bars (waybar, polybar)",
+	Long: "Print a compact summary of the weekly ad: the BOGO count, the top BOGO deals, and " +
+		"whether the ad changed recently. --format waybar emits the JSON object waybar's custom " +
+		"modules expect; --format polybar emits a single plain line. Run `pubcli daemon` so " +
+		"frequent polling is answered from memory instead of the network.",
+	Example: `  pubcli status --store 1425
+  pubcli status --format waybar
+  pubcli status --format polybar --new-for 12h`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().StringVar(&flagStatusFormat, "format", "text", "Output format: text, waybar, or polybar")
+	statusCmd.Flags().DurationVar(&flagStatusNewFor, "new-for", statusDefaultNewFor, "How long a changed weekly ad is flagged as new")
+}
+
+// statusReport is the summary shared by every status format.
+type statusReport struct {
+	Store     string             `json:"store"`
+	StoreName string             `json:"storeName"`
+	Deals     int                `json:"deals"`
+	BogoDeals int                `json:"bogoDeals"`
+	Updated   string             `json:"updated,omitempty"`
+	NewAd     bool               `json:"newAd"`
+	TopBogos  []display.DealJSON `json:"topBogos"`
+}
+
+// waybarOutput is the JSON shape read by waybar's custom modules with
+// "return-type": "json".
+type waybarOutput struct {
+	Text    string `json:"text"`
+	Alt     string `json:"alt"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class,omitempty"`
+}
+
+// statusSeen remembers when each store's current ad version was first seen.
+type statusSeen map[string]statusSeenEntry
+
+type statusSeenEntry struct {
+	Updated string    `json:"updated"`
+	SeenAt  time.Time `json:"seenAt"`
+	// Changed is false for the first version ever seen, which is not
+	// reported as new.
+	Changed bool `json:"changed"`
+}
+
+func runStatus(cmd *cobra.Command, _ []string) error {
+	format := strings.ToLower(strings.TrimSpace(flagStatusFormat))
+	switch format {
+	case "text", "waybar", "polybar":
+	default:
+		return invalidArgsError(
+			"invalid value for --format (use text, waybar, or polybar)",
+			"pubcli status --format waybar",
+		)
+	}
+	if flagStatusNewFor < 0 {
+		return invalidArgsError("--new-for must not be negative", "pubcli status --new-for 24h")
+	}
+
+	report, err := loadStatus(cmd)
+	if err != nil {
+		// Bars render whatever is on stdout, so show the failure there too.
+		printStatusError(cmd.OutOrStdout(), format, err)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "waybar":
+		return json.NewEncoder(out).Encode(report.waybar())
+	case "polybar":
+		fmt.Fprintln(out, report.line())
+		return nil
+	}
+	if flagJSON {
+		return display.PrintVersionedJSON(out, "status", report)
+	}
+	fmt.Fprintln(out, report.line())
+	fmt.Fprintln(out, report.tooltip())
+	return nil
+}
+
+func loadStatus(cmd *cobra.Command) (statusReport, error) {
+	client := newAPIClient()
+	storeNumber, storeLabel, err := resolveStoreForTUI(cmd.Context(), client, flagStore, flagZip)
+	if err != nil {
+		return statusReport{}, err
+	}
+	resp, err := client.FetchSavings(cmd.Context(), storeNumber)
+	if err != nil {
+		return statusReport{}, upstreamError("fetching deals", err)
+	}
+
+	seen, path := loadStatusSeen()
+	isNew := seen.observe(storeNumber, resp.WeeklyAdLatestUpdatedDateTime, time.Now(), flagStatusNewFor)
+	if path != "" {
+		// Losing the seen state only costs the "new" flag, so errors are ignored.
+		_ = saveStatusSeen(path, seen)
+	}
+	return buildStatusReport(storeNumber, storeLabel, resp, isNew), nil
+}
+
+func buildStatusReport(storeNumber, storeLabel string, resp *api.SavingsResponse, isNew bool) statusReport {
+	bogos := filter.Apply(resp.Savings, filter.Options{BOGO: true, Sort: "savings"})
+	top := bogos
+	if len(top) > statusTopBogos {
+		top = top[:statusTopBogos]
+	}
+	report := statusReport{
+		Store:     storeNumber,
+		StoreName: storeLabel,
+		Deals:     len(resp.Savings),
+		BogoDeals: len(bogos),
+		Updated:   resp.WeeklyAdLatestUpdatedDateTime,
+		NewAd:     isNew,
+		TopBogos:  make([]display.DealJSON, 0, len(top)),
+	}
+	for _, item := range top {
+		report.TopBogos = append(report.TopBogos, display.ToDealJSON(item))
+	}
+	return report
+}
+
+func (r statusReport) line() string {
+	line := fmt.Sprintf("Publix: %d BOGO", r.BogoDeals)
+	if r.NewAd {
+		line += " (new ad)"
+	}
+	return line
+}
+
+func (r statusReport) tooltip() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Store %s: %d deals, %d BOGO", r.StoreName, r.Deals, r.BogoDeals)
+	if r.NewAd {
+		b.WriteString("\nNew weekly ad")
+	}
+	if len(r.TopBogos) > 0 {
+		b.WriteString("\nTop BOGO:")
+		for _, deal := range r.TopBogos {
+			fmt.Fprintf(&b, "\n• %s", deal.Title)
+			if deal.Savings != "" {
+				fmt.Fprintf(&b, " (%s)", deal.Savings)
+			}
+		}
+	}
+	return b.String()
+}
+
+func (r statusReport) waybar() waybarOutput {
+	out := waybarOutput{
+		Text:    fmt.Sprintf("%d BOGO", r.BogoDeals),
+		Alt:     "default",
+		Tooltip: r.tooltip(),
+	}
+	if r.NewAd {
+		out.Alt = "new"
+		out.Class = "new"
+	}
+	return out
+}
+
+func printStatusError(w io.Writer, format string, err error) {
+	message := err.Error()
+	var cliErr *cliError
+	if errors.As(err, &cliErr) {
+		message = cliErr.Message
+	}
+	switch format {
+	case "waybar":
+		json.NewEncoder(w).Encode(waybarOutput{Text: "Publix ?", Alt: "error", Tooltip: message, Class: "error"})
+	case "polybar":
+		fmt.Fprintln(w, "Publix ?")
+	}
+}
+
+// observe records the ad version for a store and reports whether it should
+// be flagged as new: it replaced an earlier version within newFor of now.
+func (s statusSeen) observe(storeNumber, updated string, now time.Time, newFor time.Duration) bool {
+	prev, ok := s[storeNumber]
+	switch {
+	case !ok:
+		s[storeNumber] = statusSeenEntry{Updated: updated, SeenAt: now}
+		return false
+	case prev.Updated != updated:
+		s[storeNumber] = statusSeenEntry{Updated: updated, SeenAt: now, Changed: true}
+		return true
+	default:
+		return prev.Changed && now.Sub(prev.SeenAt) < newFor
+	}
+}
+
+// loadStatusSeen reads the seen state from the data directory. It returns
+// an empty path when the directory cannot be located.
+func loadStatusSeen() (statusSeen, string) {
+	seen := statusSeen{}
+	path, err := config.DataPath(statusSeenFile)
+	if err != nil {
+		return seen, ""
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, &seen); err != nil {
+			seen = statusSeen{}
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return seen, ""
+	}
+	return seen, path
+}
+
+func saveStatusSeen(path string, seen statusSeen) error {
+	data, err := json.Marshal(seen)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
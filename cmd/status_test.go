@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestBuildStatusReport_TopBogosBySavings(t *testing.T) {
+	resp := &api.SavingsResponse{
+		WeeklyAdLatestUpdatedDateTime: "2026-10-14T08:00:00",
+		Savings: []api.SavingItem{
+			{ID: "1", Title: strPtr("Chicken"), Categories: []string{"meat"}},
+			{ID: "2", Title: strPtr("Nutella"), Savings: strPtr("$2.00 off"), Categories: []string{"bogo"}},
+			{ID: "3", Title: strPtr("Coffee"), Savings: strPtr("Save up to $8.00"), Categories: []string{"bogo"}},
+			{ID: "4", Title: strPtr("Chips"), Savings: strPtr("$1.00 off"), Categories: []string{"bogo"}},
+			{ID: "5", Title: strPtr("Soda"), Savings: strPtr("$3.00 off"), Categories: []string{"bogo"}},
+		},
+	}
+
+	report := buildStatusReport("1425", "#1425", resp, false)
+	assert.Equal(t, 5, report.Deals)
+	assert.Equal(t, 4, report.BogoDeals)
+	require.Len(t, report.TopBogos, 3)
+	assert.Equal(t, "Coffee", report.TopBogos[0].Title)
+	assert.Equal(t, "Publix: 4 BOGO", report.line())
+
+	bar := report.waybar()
+	assert.Equal(t, "4 BOGO", bar.Text)
+	assert.Empty(t, bar.Class)
+	assert.Contains(t, bar.Tooltip, "• Coffee (Save up to $8.00)")
+	assert.NotContains(t, bar.Tooltip, "Chips")
+}
+
+func TestBuildStatusReport_NewAdSetsClass(t *testing.T) {
+	report := buildStatusReport("1425", "#1425", &api.SavingsResponse{}, true)
+	bar := report.waybar()
+	assert.Equal(t, "new", bar.Class)
+	assert.Equal(t, "new", bar.Alt)
+	assert.Equal(t, "Publix: 0 BOGO (new ad)", report.line())
+}
+
+func TestStatusSeen_Observe(t *testing.T) {
+	seen := statusSeen{}
+	now := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+
+	assert.False(t, seen.observe("1425", "v1", now, time.Hour), "first sighting is not new")
+	assert.False(t, seen.observe("1425", "v1", now.Add(time.Minute), time.Hour))
+	assert.True(t, seen.observe("1425", "v2", now.Add(2*time.Minute), time.Hour), "changed ad is new")
+	assert.True(t, seen.observe("1425", "v2", now.Add(30*time.Minute), time.Hour))
+	assert.False(t, seen.observe("1425", "v2", now.Add(3*time.Hour), time.Hour), "new flag expires")
+}
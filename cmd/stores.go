@@ -2,26 +2,36 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tayloree/publix-deals/internal/api"
 	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
 )
 
 var storesCmd = &cobra.Command{
 	Use:   "stores",
 	Short: "List nearby Publix stores",
-	Long:  "Find Publix stores near a zip code. Use this to discover store numbers for fetching deals.",
+	Long: "Find Publix stores near a zip code. Use this to discover store numbers for fetching deals.\n\n" +
+		"--store-type limits the search to some kinds of location:\n" + storeTypeHelp(),
 	Example: `  pubcli stores --zip 33101
-  pubcli stores -z 32801 --json`,
-	RunE: runStores,
+  pubcli stores --zip 33101 --store-type greenwise
+  pubcli stores -z 32801 --json
+  pubcli stores --zip 33101 --format alfred`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runStores,
 }
 
 func init() {
 	rootCmd.AddCommand(storesCmd)
+	registerOutputFormatFlag(storesCmd.Flags())
 }
 
 func runStores(cmd *cobra.Command, _ []string) error {
+	if err := validateOutputFormat(); err != nil {
+		return err
+	}
 	if flagZip == "" {
 		return invalidArgsError(
 			"--zip is required for store lookup",
@@ -30,7 +40,7 @@ func runStores(cmd *cobra.Command, _ []string) error {
 		)
 	}
 
-	client := api.NewClient()
+	client := newAPIClient()
 	stores, err := client.FetchStores(cmd.Context(), flagZip, 5)
 	if err != nil {
 		return upstreamError("fetching stores", err)
@@ -42,9 +52,57 @@ func runStores(cmd *cobra.Command, _ []string) error {
 		)
 	}
 
+	if flagFormat == "alfred" {
+		return display.PrintStoresAlfred(cmd.OutOrStdout(), stores)
+	}
+	if flagFormat == "markdown" {
+		display.PrintStoresMarkdown(cmd.OutOrStdout(), stores)
+		return nil
+	}
 	if flagJSON {
 		return display.PrintStoresJSON(cmd.OutOrStdout(), stores)
 	}
 	display.PrintStores(cmd.OutOrStdout(), stores, flagZip)
 	return nil
 }
+
+// storeTypeValues returns the --store-type names.
+func storeTypeValues() []string {
+	names := make([]string, 0, len(api.StoreTypes))
+	for _, t := range api.StoreTypes {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// storeTypeNames lists the --store-type names for help and error text.
+func storeTypeNames() string {
+	return strings.Join(storeTypeValues(), ", ")
+}
+
+// storeTypeHelp describes each --store-type name, one per line.
+func storeTypeHelp() string {
+	var b strings.Builder
+	for _, t := range api.StoreTypes {
+		fmt.Fprintf(&b, "  %-10s %s\n", t.Name, t.Description)
+	}
+	return b.String()
+}
+
+// completeStoreTypes completes --store-type names, including after a comma
+// in a list such as "regular,gr", skipping names already in the list.
+func completeStoreTypes(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	chosen := strings.Split(prefix, ",")
+	out := make([]string, 0, len(api.StoreTypes))
+	for _, t := range api.StoreTypes {
+		if filter.ContainsIgnoreCase(chosen, t.Name) {
+			continue
+		}
+		out = append(out, prefix+t.Name+"\t"+t.Description)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+const summaryTopDeals = 5
+
+var flagSummaryEndingWithin time.Duration
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Ten-second overview of this week's ad: is it worth a trip",
+	Long: "Print a compact overview of a store's weekly ad: the week it runs, how many deals and " +
+		"BOGO deals it has, the top 5 deals by savings, the department with the most deals, and " +
+		"how many deals end within --ending-within. Expired deals are not counted.",
+	Example: `  pubcli summary --zip 33101
+  pubcli summary --store 1425 --json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runSummary,
+}
+
+func init() {
+	rootCmd.AddCommand(summaryCmd)
+	registerSummaryFlags(summaryCmd.Flags())
+}
+
+// registerSummaryFlags adds the flags of `pubcli summary` to f, which
+// `pubcli ad summary` shares.
+func registerSummaryFlags(f *pflag.FlagSet) {
+	f.DurationVar(&flagSummaryEndingWithin, "ending-within", reportDefaultEndingWithin, "Count deals that expire within this long")
+}
+
+// summaryReport is the overview printed by `pubcli summary`.
+type summaryReport struct {
+	Store     string `json:"store"`
+	StoreName string `json:"storeName"`
+	// Week is the ad's most common start–end range, empty when the deals
+	// carry no dates.
+	Week      string             `json:"week,omitempty"`
+	Deals     int                `json:"deals"`
+	BogoDeals int                `json:"bogoDeals"`
+	TopDeals  []display.DealJSON `json:"topDeals"`
+	// BiggestDepartment is nil when no deal names its department.
+	BiggestDepartment *summaryDepartment `json:"biggestDepartment,omitempty"`
+	EndingSoon        int                `json:"endingSoon"`
+	EndingWithin      string             `json:"endingWithin"`
+}
+
+type summaryDepartment struct {
+	Name  string `json:"name"`
+	Deals int    `json:"deals"`
+}
+
+func runSummary(cmd *cobra.Command, _ []string) error {
+	if flagSummaryEndingWithin < 0 {
+		return invalidArgsError("--ending-within must not be negative", "pubcli summary --ending-within 48h")
+	}
+	client := newAPIClient()
+	storeNumber, storeLabel, err := resolveStoreForTUI(cmd.Context(), client, flagStore, flagZip)
+	if err != nil {
+		return err
+	}
+	data, err := fetchSavingsCached(cmd.Context(), client, storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	report := buildSummary(storeNumber, storeLabel, data.Savings, time.Now(), flagSummaryEndingWithin)
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "summary", report)
+	}
+	report.print(cmd.OutOrStdout())
+	return nil
+}
+
+// buildSummary summarizes the unexpired deals of a store's ad as of now.
+func buildSummary(storeNumber, storeLabel string, items []api.SavingItem, now time.Time, endingWithin time.Duration) summaryReport {
+	current := make([]api.SavingItem, 0, len(items))
+	for _, item := range items {
+		if !filter.Expired(item, now) {
+			current = append(current, item)
+		}
+	}
+	report := summaryReport{
+		Store:        storeNumber,
+		StoreName:    storeLabel,
+		Week:         adWeek(current),
+		Deals:        len(current),
+		TopDeals:     make([]display.DealJSON, 0, summaryTopDeals),
+		EndingWithin: endingWithin.String(),
+	}
+
+	departments := map[string]int{}
+	for _, item := range current {
+		if filter.ContainsIgnoreCase(item.Categories, "bogo") {
+			report.BogoDeals++
+		}
+		if dept := filter.Group(item, "department"); dept != "" {
+			departments[dept]++
+		}
+		if end, ok := filter.EndsAt(item, now); ok && end.Sub(now) <= endingWithin {
+			report.EndingSoon++
+		}
+	}
+	names := make([]string, 0, len(departments))
+	for name := range departments {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if departments[names[i]] != departments[names[j]] {
+			return departments[names[i]] > departments[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > 0 {
+		report.BiggestDepartment = &summaryDepartment{Name: names[0], Deals: departments[names[0]]}
+	}
+
+	for _, item := range filter.Apply(current, filter.Options{Sort: "savings", Limit: summaryTopDeals}) {
+		report.TopDeals = append(report.TopDeals, display.ToDealJSON(item))
+	}
+	return report
+}
+
+func (r summaryReport) print(w io.Writer) {
+	header := "Store " + r.StoreName
+	if r.Week != "" {
+		header += ", week of " + r.Week
+	}
+	fmt.Fprintln(w, header)
+	fmt.Fprintf(w, "%d deals, %d BOGO\n", r.Deals, r.BogoDeals)
+	if r.BiggestDepartment != nil {
+		fmt.Fprintf(w, "Biggest department: %s (%d deals)\n", r.BiggestDepartment.Name, r.BiggestDepartment.Deals)
+	}
+	fmt.Fprintf(w, "Ending within %s: %d deals\n", summaryDuration(r.EndingWithin), r.EndingSoon)
+	if len(r.TopDeals) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Top deals:")
+	for i, deal := range r.TopDeals {
+		line := deal.Title
+		if deal.Savings != "" {
+			line += " — " + deal.Savings
+		}
+		fmt.Fprintf(w, "  %d. %s\n", i+1, line)
+	}
+}
+
+// summaryDuration drops the zero units time.Duration prints: 72h0m0s
+// becomes 72h and 1h30m0s becomes 1h30m.
+func summaryDuration(d string) string {
+	if strings.HasSuffix(d, "m0s") {
+		d = strings.TrimSuffix(d, "0s")
+	}
+	if strings.HasSuffix(d, "h0m") {
+		d = strings.TrimSuffix(d, "0m")
+	}
+	return d
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestBuildSummary(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	deal := func(id, title, savings, dept, end string, bogo bool) api.SavingItem {
+		item := api.SavingItem{ID: id, Title: strPtr(title), Department: strPtr(dept), StartFormatted: "10/15", EndFormatted: end}
+		if savings != "" {
+			item.Savings = strPtr(savings)
+		}
+		if bogo {
+			item.Categories = []string{"bogo"}
+		}
+		return item
+	}
+	items := []api.SavingItem{
+		deal("1", "Chicken", "$1.00 off", "Meat", "10/21", false),
+		deal("2", "Coffee", "Save up to $8.00", "Grocery", "10/21", true),
+		deal("3", "Chips", "$2.00 off", "Grocery", "10/18", true),
+		deal("4", "Soda", "$3.00 off", "Grocery", "10/21", false),
+		deal("5", "Steak", "$5.00 off", "Meat", "10/21", false),
+		deal("6", "Salsa", "$4.00 off", "", "10/21", false),
+		deal("7", "Bread", "$0.50 off", "Bakery", "10/21", false),
+		deal("8", "Old", "$9.00 off", "Bakery", "10/16", true),
+	}
+
+	s := buildSummary("1425", "#1425", items, now, 48*time.Hour)
+	assert.Equal(t, 7, s.Deals, "expired deals are not counted")
+	assert.Equal(t, 2, s.BogoDeals)
+	assert.Equal(t, "10/15 – 10/21", s.Week)
+	require.NotNil(t, s.BiggestDepartment)
+	assert.Equal(t, summaryDepartment{Name: "Grocery", Deals: 3}, *s.BiggestDepartment)
+	assert.Equal(t, 1, s.EndingSoon)
+	assert.Equal(t, "48h0m0s", s.EndingWithin)
+	require.Len(t, s.TopDeals, summaryTopDeals)
+	assert.Equal(t, "Coffee", s.TopDeals[0].Title)
+
+	var out bytes.Buffer
+	s.print(&out)
+	assert.Contains(t, out.String(), "Store #1425, week of 10/15 – 10/21\n7 deals, 2 BOGO\n")
+	assert.Contains(t, out.String(), "Biggest department: Grocery (3 deals)\n")
+	assert.Contains(t, out.String(), "Ending within 48h: 1 deals\n")
+	assert.Contains(t, out.String(), "  1. Coffee — Save up to $8.00\n")
+}
+
+func TestBuildSummary_Empty(t *testing.T) {
+	s := buildSummary("1425", "#1425", nil, time.Now(), reportDefaultEndingWithin)
+	assert.Zero(t, s.Deals)
+	assert.Nil(t, s.BiggestDepartment)
+	assert.NotNil(t, s.TopDeals, "topDeals is [] in JSON, not null")
+}
+
+func TestSummaryDuration(t *testing.T) {
+	assert.Equal(t, "72h", summaryDuration("72h0m0s"))
+	assert.Equal(t, "1h30m", summaryDuration("1h30m0s"))
+	assert.Equal(t, "30m", summaryDuration("30m0s"))
+	assert.Equal(t, "45s", summaryDuration("45s"))
+}
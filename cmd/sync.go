@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/alert"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+	"github.com/tayloree/publix-deals/internal/statesync"
+)
+
+const (
+	// envSyncPassword holds the WebDAV password for `pubcli sync`.
+	envSyncPassword = "PUBCLI_SYNC_PASSWORD"
+	syncStateFile   = "sync-state.json"
+)
+
+// syncedFiles are the data-directory files shared between devices.
+var syncedFiles = []string{shoplist.FileName, alert.WatchlistFile}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Share the shopping list and watchlist with other devices",
+	Long: "Sync the shopping list and the alert watchlist through a WebDAV server (Nextcloud, " +
+		"ownCloud, ...) or a shared directory, configured under `sync:` in config.yaml. A file " +
+		"changed on one device since the last sync is copied to the other; when both changed, " +
+		"the newer edit wins.",
+	Example: `  pubcli sync now
+  pubcli sync status`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+}
+
+var syncNowCmd = &cobra.Command{
+	Use:         "now",
+	Short:       "Push local changes and pull remote ones",
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runSyncNow,
+}
+
+var syncStatusCmd = &cobra.Command{
+	Use:         "status",
+	Short:       "Show what `sync now` would push or pull, without changing anything",
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runSyncStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncNowCmd, syncStatusCmd)
+}
+
+// newSyncer builds a Syncer from the `sync:` config section.
+func newSyncer(cfg *config.Sync) (*statesync.Syncer, error) {
+	if cfg == nil || strings.TrimSpace(cfg.Provider) == "" {
+		return nil, invalidArgsError(
+			"sync is not configured",
+			"Add a `sync:` section with `provider: webdav` and `url:` (or `provider: dir` and `path:`) to config.yaml.",
+		)
+	}
+
+	provider, err := syncProvider(cfg)
+	if err != nil {
+		return nil, configError(err)
+	}
+	if webdav, ok := provider.(*statesync.WebDAV); ok {
+		if webdav.Password, err = resolveSecret("sync.password", webdav.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	store, err := dataStore()
+	if err != nil {
+		return nil, err
+	}
+	statePath, err := config.DataPath(syncStateFile)
+	if err != nil {
+		return nil, configError(err)
+	}
+	device := strings.TrimSpace(cfg.Device)
+	if device == "" {
+		device, _ = os.Hostname()
+	}
+	return &statesync.Syncer{
+		Provider:  provider,
+		Local:     store,
+		Files:     syncedFiles,
+		StatePath: statePath,
+		Device:    device,
+	}, nil
+}
+
+// syncProvider builds the provider a `sync:` section names.
+func syncProvider(cfg *config.Sync) (statesync.Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "webdav":
+		if strings.TrimSpace(cfg.URL) == "" {
+			return nil, errors.New("sync.url is required for the webdav provider")
+		}
+		password := os.Getenv(envSyncPassword)
+		if password == "" {
+			password = cfg.Password
+		}
+		return &statesync.WebDAV{URL: strings.TrimSpace(cfg.URL), Username: cfg.Username, Password: password}, nil
+	case "dir":
+		if strings.TrimSpace(cfg.Path) == "" {
+			return nil, errors.New("sync.path is required for the dir provider")
+		}
+		return &statesync.Dir{Path: strings.TrimSpace(cfg.Path)}, nil
+	default:
+		return nil, fmt.Errorf("sync.provider %q is not supported; use webdav or dir", cfg.Provider)
+	}
+}
+
+func runSyncNow(cmd *cobra.Command, _ []string) error {
+	return runSync(cmd, (*statesync.Syncer).Sync, true)
+}
+
+func runSyncStatus(cmd *cobra.Command, _ []string) error {
+	return runSync(cmd, (*statesync.Syncer).Status, false)
+}
+
+func runSync(cmd *cobra.Command, op func(*statesync.Syncer, context.Context) (statesync.Report, error), applied bool) error {
+	syncer, err := newSyncer(activeConfig.Sync)
+	if err != nil {
+		return err
+	}
+	report, err := op(syncer, cmd.Context())
+	if err != nil {
+		return upstreamError("syncing", err)
+	}
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "sync", report)
+	}
+	printSyncReport(cmd.OutOrStdout(), report, applied)
+	return nil
+}
+
+func printSyncReport(w io.Writer, report statesync.Report, applied bool) {
+	fmt.Fprintf(w, "Remote: %s\n", report.Remote)
+	if report.LastSync != nil {
+		fmt.Fprintf(w, "Last sync: %s\n", report.LastSync.Local().Format(time.RFC1123))
+	} else {
+		fmt.Fprintln(w, "Last sync: never")
+	}
+	for _, f := range report.Files {
+		fmt.Fprintf(w, "  %-16s %s\n", f.File, describeSyncResult(f, applied))
+	}
+}
+
+func describeSyncResult(f statesync.FileResult, applied bool) string {
+	from := ""
+	if f.RemoteDevice != "" {
+		from = " (from " + f.RemoteDevice + ")"
+	}
+	var msg string
+	switch {
+	case f.Action == statesync.ActionNone && f.LocalModified == nil && f.RemoteModified == nil:
+		return "not created yet"
+	case f.Action == statesync.ActionNone:
+		return "up to date"
+	case f.Action == statesync.ActionPush && applied:
+		msg = "pushed"
+	case f.Action == statesync.ActionPush:
+		msg = "local changes to push"
+	case applied:
+		msg = "pulled" + from
+	default:
+		msg = "remote changes to pull" + from
+	}
+	if f.Conflict {
+		side := "local"
+		if f.Action == statesync.ActionPull {
+			side = "remote"
+		}
+		msg += "; changed on both sides, newer " + side + " copy wins"
+	}
+	return msg
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+const topDefaultN = 10
+
+var (
+	flagTopN  int
+	flagTopBy string
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Rank this week's best deals in a numbered leaderboard",
+	Long: "Rank the weekly ad's deals by deal score (the default), dollars saved, or percent off, " +
+		"as read from each deal's savings text. Deals whose text states no dollar amount or " +
+		"percentage are left out of those rankings. --by department lists the top deals by score " +
+		"within each department, departments with the best deals first.",
+	Example: `  pubcli top --store 1425
+  pubcli top --zip 33101 --n 5 --by dollars
+  pubcli top --store 1425 --by department --n 3`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runTop,
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+	topCmd.Flags().IntVarP(&flagTopN, "n", "n", topDefaultN, "Number of deals to rank (per department with --by department)")
+	topCmd.Flags().StringVar(&flagTopBy, "by", "score", "Rank by score, dollars, or percent, or group by department")
+}
+
+// topEntry is one ranked deal.
+type topEntry struct {
+	Rank int `json:"rank"`
+	// Value is the measure ranked by: the deal score, dollars, or percent.
+	Value float64 `json:"value"`
+	display.DealJSON
+}
+
+// topDepartment is one department's leaderboard.
+type topDepartment struct {
+	Department string     `json:"department"`
+	Deals      []topEntry `json:"deals"`
+}
+
+// topJSON is the --json output of `top`. Entries is set unless ranking by
+// department, which sets Departments instead.
+type topJSON struct {
+	By          string          `json:"by"`
+	Entries     []topEntry      `json:"entries,omitempty"`
+	Departments []topDepartment `json:"departments,omitempty"`
+}
+
+func runTop(cmd *cobra.Command, _ []string) error {
+	by := strings.ToLower(strings.TrimSpace(flagTopBy))
+	switch by {
+	case "score", "dollars", "percent", "department":
+	default:
+		return invalidArgsError(
+			"invalid value for --by (use score, dollars, percent, or department)",
+			"pubcli top --store 1425 --by dollars",
+		)
+	}
+	if flagTopN < 1 {
+		return invalidArgsError("--n must be at least 1", "pubcli top --store 1425 --n 10")
+	}
+
+	client := newAPIClient()
+	storeNumber, err := resolveStore(cmd, client)
+	if err != nil {
+		return err
+	}
+	data, err := client.FetchSavings(cmd.Context(), storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+	if len(data.Savings) == 0 {
+		return notFoundError(
+			fmt.Sprintf("no deals found for store #%s", storeNumber),
+			"Try another store with --store.",
+		)
+	}
+
+	board := buildTop(data.Savings, by, flagTopN)
+	if len(board.Entries) == 0 && len(board.Departments) == 0 {
+		return notFoundError(
+			fmt.Sprintf("no deals at store #%s state a %s amount", storeNumber, strings.TrimSuffix(by, "s")),
+			"pubcli top --store "+storeNumber+" --by score",
+		)
+	}
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "top", board)
+	}
+	printTop(cmd.OutOrStdout(), board, storeNumber)
+	return nil
+}
+
+// topValue returns a deal's measure for a ranking, and false when the deal
+// has none.
+func topValue(item api.SavingItem, by string) (float64, bool) {
+	switch by {
+	case "dollars":
+		v := filter.ParseSavings(item).Dollars
+		return v, v > 0
+	case "percent":
+		v := filter.ParseSavings(item).Percent
+		return v, v > 0
+	default:
+		return filter.DealScore(item), true
+	}
+}
+
+func rankDeals(items []api.SavingItem, by string, n int) []topEntry {
+	type scored struct {
+		item  api.SavingItem
+		value float64
+	}
+	var ranked []scored
+	for _, item := range items {
+		if v, ok := topValue(item, by); ok {
+			ranked = append(ranked, scored{item, v})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].value > ranked[j].value })
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	entries := make([]topEntry, 0, len(ranked))
+	for i, r := range ranked {
+		entries = append(entries, topEntry{Rank: i + 1, Value: r.value, DealJSON: display.ToDealJSON(r.item)})
+	}
+	return entries
+}
+
+func buildTop(items []api.SavingItem, by string, n int) topJSON {
+	if by != "department" {
+		return topJSON{By: by, Entries: rankDeals(items, by, n)}
+	}
+
+	byDept := map[string][]api.SavingItem{}
+	for _, item := range items {
+		dept := filter.CleanText(filter.Deref(item.Department))
+		if dept == "" {
+			dept = "Other"
+		}
+		byDept[dept] = append(byDept[dept], item)
+	}
+	board := topJSON{By: by}
+	for dept, deptItems := range byDept {
+		board.Departments = append(board.Departments, topDepartment{Department: dept, Deals: rankDeals(deptItems, "score", n)})
+	}
+	sort.Slice(board.Departments, func(i, j int) bool {
+		a, b := board.Departments[i].Deals[0].Value, board.Departments[j].Deals[0].Value
+		if a != b {
+			return a > b
+		}
+		return board.Departments[i].Department < board.Departments[j].Department
+	})
+	return board
+}
+
+func printTop(w io.Writer, board topJSON, storeNumber string) {
+	titles := map[string]string{
+		"score":      "by deal score",
+		"dollars":    "by dollars saved",
+		"percent":    "by percent off",
+		"department": "by department",
+	}
+	fmt.Fprintf(w, "Top deals at store #%s %s\n", storeNumber, titles[board.By])
+	if board.By != "department" {
+		fmt.Fprintln(w)
+		printTopEntries(w, board.Entries, board.By)
+		return
+	}
+	for _, dept := range board.Departments {
+		fmt.Fprintf(w, "\n%s\n", dept.Department)
+		printTopEntries(w, dept.Deals, "score")
+	}
+}
+
+func printTopEntries(w io.Writer, entries []topEntry, by string) {
+	for _, e := range entries {
+		value := "score " + display.FormatNumber(e.Value, 1)
+		switch by {
+		case "dollars":
+			value = display.FormatMoney(e.Value)
+		case "percent":
+			value = display.FormatNumber(e.Value, 0) + "%"
+		}
+		line := fmt.Sprintf("%2d. %s", e.Rank, e.Title)
+		if e.Savings != "" {
+			line += " — " + e.Savings
+		}
+		fmt.Fprintf(w, "%s (%s)\n", line, value)
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestBuildTop_RanksByDollars(t *testing.T) {
+	items := []api.SavingItem{
+		{Title: strPtr("Cereal"), Savings: strPtr("Save $1.00")},
+		{Title: strPtr("Steak"), Savings: strPtr("Save Up To $6.50")},
+		{Title: strPtr("Bread"), Savings: strPtr("2/$5.00")},
+		{Title: strPtr("Soda"), Savings: strPtr("Save $2.00")},
+	}
+
+	board := buildTop(items, "dollars", 2)
+	require.Len(t, board.Entries, 2)
+	assert.Equal(t, "Steak", board.Entries[0].Title)
+	assert.Equal(t, 6.5, board.Entries[0].Value)
+	assert.Equal(t, 2, board.Entries[1].Rank)
+	assert.Equal(t, "Soda", board.Entries[1].Title)
+
+	var out bytes.Buffer
+	printTop(&out, board, "1425")
+	assert.Equal(t, "Top deals at store #1425 by dollars saved\n\n"+
+		" 1. Steak — Save Up To $6.50 ($6.50)\n"+
+		" 2. Soda — Save $2.00 ($2.00)\n", out.String())
+}
+
+func TestBuildTop_GroupsByDepartment(t *testing.T) {
+	items := []api.SavingItem{
+		{Title: strPtr("Apples"), Department: strPtr("Produce")},
+		{Title: strPtr("Chips"), Department: strPtr("Grocery"), Categories: []string{"bogo"}},
+		{Title: strPtr("Pears"), Department: strPtr("Produce")},
+		{Title: strPtr("Mystery")},
+	}
+
+	board := buildTop(items, "department", 1)
+	assert.Nil(t, board.Entries)
+	require.Len(t, board.Departments, 3)
+	assert.Equal(t, "Grocery", board.Departments[0].Department, "the department with the best deal comes first")
+	for _, dept := range board.Departments {
+		assert.Len(t, dept.Deals, 1)
+	}
+	assert.Contains(t, []string{board.Departments[1].Department, board.Departments[2].Department}, "Other")
+}
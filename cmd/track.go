@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/alert"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+	"github.com/tayloree/publix-deals/internal/track"
+)
+
+var flagTrackBelow float64
+
+var trackCmd = &cobra.Command{
+	Use:   "track QUERY --below PRICE",
+	Short: "Track an item and get notified when its price drops below a threshold",
+	Long: "Track deals whose title or description contains every word of QUERY, and fire when one " +
+		"costs less than --below per item. Prices come from the deal's savings text, so BOGO deals " +
+		"count at half price and \"2/$5\" at $2.50; deals whose text states no price never fire. " +
+		"Tracking the same query again replaces its threshold. `pubcli track check` compares the " +
+		"weekly ad with every threshold and sends the items that fire to the alert destinations.",
+	Example: `  pubcli track "boneless chicken" --below 2.99
+  pubcli track list
+  pubcli track check --store 1425
+  pubcli track remove "boneless chicken"`,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runTrackAdd,
+}
+
+var trackListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "Show tracked items and their thresholds",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runTrackList,
+}
+
+var trackRemoveCmd = &cobra.Command{
+	Use:         "remove QUERY",
+	Aliases:     []string{"rm"},
+	Short:       "Stop tracking an item",
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationNetwork: "false"},
+	RunE:        runTrackRemove,
+}
+
+var trackCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Compare the weekly ad with tracked thresholds and notify about items below them",
+	Long: "Fetch the weekly ad, show each tracked item's lowest price this week and in the ad " +
+		"history, and send the items priced below their threshold to the alert destinations " +
+		"configured under `alerts:`. The ad is added to the history in the data directory. Like " +
+		"`pubcli alert run`, a deal is sent once per ad week unless --renotify is given. Intended " +
+		"for cron:\n\n" +
+		"  0 8 * * * pubcli track check --store 1425",
+	Example: `  pubcli track check --store 1425
+  pubcli track check --zip 33101 --dry-run --json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runTrackCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(trackCmd)
+	trackCmd.AddCommand(trackListCmd, trackRemoveCmd, trackCheckCmd)
+	trackCmd.Flags().Float64Var(&flagTrackBelow, "below", 0, "Fire when one item costs less than this")
+	trackCheckCmd.Flags().BoolVar(&flagAlertDryRun, "dry-run", false, "Print prices without sending notifications")
+	trackCheckCmd.Flags().BoolVar(&flagAlertRenotify, "renotify", false, "Send items again even if they were already sent this ad week")
+}
+
+// trackCheckJSON is the --json output of `track check`.
+type trackCheckJSON struct {
+	Store      string           `json:"store"`
+	Items      []track.Status   `json:"items"`
+	Suppressed int              `json:"suppressed"`
+	Deliveries []alert.Delivery `json:"deliveries"`
+}
+
+func loadTracked() (*track.List, storage.Backend, error) {
+	store, err := dataStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	l, err := track.Load(store, track.FileName)
+	if err != nil {
+		return nil, nil, configError(err)
+	}
+	return l, store, nil
+}
+
+// trackRuleName names a tracked item in notifications. It includes the
+// threshold, so lowering it sends matching deals again.
+func trackRuleName(it track.Item) string {
+	return fmt.Sprintf("%s under %s", it.Query, display.FormatMoney(it.Below))
+}
+
+func runTrackAdd(cmd *cobra.Command, args []string) error {
+	query := strings.Join(strings.Fields(strings.Join(args, " ")), " ")
+	if query == "" {
+		return invalidArgsError("name the item to track", `pubcli track "boneless chicken" --below 2.99`)
+	}
+	if flagTrackBelow <= 0 {
+		return invalidArgsError("--below must be a price above zero", `pubcli track "boneless chicken" --below 2.99`)
+	}
+
+	l, store, err := loadTracked()
+	if err != nil {
+		return err
+	}
+	item := track.Item{Query: query, Below: flagTrackBelow}
+	replaced := l.Set(item)
+	if err := l.Save(store, track.FileName); err != nil {
+		return err
+	}
+	verb := "Tracking"
+	if replaced {
+		verb = "Updated"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s %q: fires below %s.\n", verb, query, display.FormatMoney(item.Below))
+	return nil
+}
+
+func runTrackList(cmd *cobra.Command, _ []string) error {
+	l, _, err := loadTracked()
+	if err != nil {
+		return err
+	}
+	if flagJSON {
+		items := l.Items
+		if items == nil {
+			items = []track.Item{}
+		}
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "tracked", items)
+	}
+	out := cmd.OutOrStdout()
+	if len(l.Items) == 0 {
+		fmt.Fprintln(out, "Nothing is tracked. Add an item with `pubcli track QUERY --below PRICE`.")
+		return nil
+	}
+	for _, it := range l.Items {
+		fmt.Fprintf(out, "%s: below %s\n", it.Query, display.FormatMoney(it.Below))
+	}
+	return nil
+}
+
+func runTrackRemove(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+	l, store, err := loadTracked()
+	if err != nil {
+		return err
+	}
+	if !l.Remove(query) {
+		return notFoundError(fmt.Sprintf("%q is not tracked", query), "pubcli track list")
+	}
+	if err := l.Save(store, track.FileName); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Stopped tracking %q.\n", query)
+	return nil
+}
+
+func runTrackCheck(cmd *cobra.Command, _ []string) error {
+	l, _, err := loadTracked()
+	if err != nil {
+		return err
+	}
+	if len(l.Items) == 0 {
+		return invalidArgsError("nothing is tracked", `pubcli track "boneless chicken" --below 2.99`)
+	}
+	notifiers, err := alertNotifiers(activeConfig.Alerts)
+	if err != nil {
+		return err
+	}
+
+	client := newAPIClient()
+	storeNumber, err := resolveStore(cmd, client)
+	if err != nil {
+		return err
+	}
+	data, err := fetchSavingsCached(cmd.Context(), client, storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	snapshots, err := archive.List(storeNumber)
+	if err != nil {
+		return configError(err)
+	}
+	var past []history.Snapshot
+	for _, s := range snapshots {
+		if data.WeeklyAdLatestUpdatedDateTime == "" || s.Updated != data.WeeklyAdLatestUpdatedDateTime {
+			past = append(past, s)
+		}
+	}
+	now := time.Now()
+	if err := archive.Save(history.Snapshot{
+		Store:   storeNumber,
+		Updated: data.WeeklyAdLatestUpdatedDateTime,
+		SavedAt: now,
+		Deals:   data.Savings,
+	}); err != nil {
+		return configError(err)
+	}
+
+	statuses := track.Check(l.Items, data.Savings, past)
+	report := alert.Report{
+		Store:       storeNumber,
+		Updated:     data.WeeklyAdLatestUpdatedDateTime,
+		GeneratedAt: now.UTC(),
+		Matches:     []alert.Match{},
+	}
+	for i, st := range statuses {
+		if st.Firing {
+			report.Matches = append(report.Matches, alert.NewMatch(trackRuleName(l.Items[i]), st.Hits))
+		}
+	}
+	run := alert.RunReport{GeneratedAt: report.GeneratedAt, Stores: []alert.Report{report}}
+	deliveries, err := dispatchAlerts(cmd, &run, notifiers)
+	if err != nil {
+		return err
+	}
+
+	if flagJSON {
+		if err := display.PrintVersionedJSON(cmd.OutOrStdout(), "track", trackCheckJSON{
+			Store:      storeNumber,
+			Items:      statuses,
+			Suppressed: run.Suppressed,
+			Deliveries: deliveries,
+		}); err != nil {
+			return err
+		}
+	} else {
+		printTrackStatuses(cmd.OutOrStdout(), storeNumber, statuses, run.Suppressed, deliveries)
+	}
+	return deliveryError(deliveries)
+}
+
+func printTrackStatuses(w io.Writer, store string, statuses []track.Status, suppressed int, deliveries []alert.Delivery) {
+	fmt.Fprintf(w, "Tracked prices at store #%s:\n", store)
+	for _, st := range statuses {
+		var line string
+		switch {
+		case st.Firing:
+			line = fmt.Sprintf("%s, below %s", display.FormatMoney(st.Price), display.FormatMoney(st.Below))
+		case st.Price > 0:
+			line = fmt.Sprintf("%s, not below %s", display.FormatMoney(st.Price), display.FormatMoney(st.Below))
+		default:
+			line = "no priced deal this week"
+		}
+		if st.PreviousLow > 0 {
+			line += fmt.Sprintf(" (previous low %s, week of %s)", display.FormatMoney(st.PreviousLow), st.PreviousLowWeek)
+		}
+		fmt.Fprintf(w, "  %s: %s\n", st.Query, line)
+		for _, d := range st.Deals {
+			fmt.Fprintf(w, "    • %s — %s\n", d.Title, display.FormatMoney(d.Price))
+		}
+	}
+	if suppressed > 0 {
+		fmt.Fprintf(w, "%d deal(s) below threshold were already sent this ad week (--renotify sends them again).\n", suppressed)
+	}
+	printDeliveries(w, deliveries)
+}
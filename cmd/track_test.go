@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/config"
+)
+
+func TestRunCLI_TrackAddListRemove(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"track", "boneless", "chicken", "--below", "2.99"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `Tracking "boneless chicken": fires below $2.99.`)
+
+	stdout.Reset()
+	code = runCLI([]string{"track", "Boneless Chicken", "--below", "2.49"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), "Updated")
+
+	stdout.Reset()
+	code = runCLI([]string{"track", "list", "--json=false"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Equal(t, "Boneless Chicken: below $2.49\n", stdout.String())
+
+	stdout.Reset()
+	code = runCLI([]string{"track", "remove", "boneless chicken"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+
+	stdout.Reset()
+	code = runCLI([]string{"track", "list"}, &stdout, &stderr)
+	require.Equal(t, ExitSuccess, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"tracked":[]`)
+}
+
+func TestRunCLI_TrackRejectsMissingThreshold(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"track", "salmon"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--below must be a price above zero")
+
+	stderr.Reset()
+	code = runCLI([]string{"track", "check", "--store", "1425"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "nothing is tracked")
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+const trendsDefaultWeeks = 12
+
+var flagTrendsWeeks int
+
+var trendsCmd = &cobra.Command{
+	Use:   "trends",
+	Short: "Show how a store's weekly deal and BOGO counts change over time",
+	Long: "Chart the number of deals and BOGO deals in each of the last --weeks ad weeks, from " +
+		"the ad history in the data directory. The current ad is fetched and added to the history " +
+		"first; earlier weeks come from previous `pubcli trends` and `pubcli report` runs. Weeks " +
+		"without a saved ad are shown as gaps.",
+	Example: `  pubcli trends --store 1425
+  pubcli trends --zip 33101 --weeks 26 --json`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runTrends,
+}
+
+func init() {
+	rootCmd.AddCommand(trendsCmd)
+	trendsCmd.Flags().IntVar(&flagTrendsWeeks, "weeks", trendsDefaultWeeks, "Number of ad weeks to show (1-104)")
+}
+
+// trendsJSON is the --json output of `trends`.
+type trendsJSON struct {
+	Store     string      `json:"store"`
+	StoreName string      `json:"storeName"`
+	Weeks     []trendWeek `json:"weeks"`
+}
+
+// trendWeek is one ad week. The counts are null for weeks without a saved ad.
+type trendWeek struct {
+	Week      string `json:"week"`
+	Updated   string `json:"updated,omitempty"`
+	Deals     *int   `json:"deals"`
+	BogoDeals *int   `json:"bogoDeals"`
+}
+
+func runTrends(cmd *cobra.Command, _ []string) error {
+	if flagTrendsWeeks < 1 || flagTrendsWeeks > 104 {
+		return invalidArgsError("--weeks must be between 1 and 104", "pubcli trends --store 1425 --weeks 12")
+	}
+
+	client := newAPIClient()
+	storeNumber, storeLabel, err := resolveStoreForTUI(cmd.Context(), client, flagStore, flagZip)
+	if err != nil {
+		return err
+	}
+	data, err := client.FetchSavings(cmd.Context(), storeNumber)
+	if err != nil {
+		return upstreamError("fetching deals", err)
+	}
+
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := archive.Save(history.Snapshot{
+		Store:   storeNumber,
+		Updated: data.WeeklyAdLatestUpdatedDateTime,
+		SavedAt: now,
+		Deals:   data.Savings,
+	}); err != nil {
+		return configError(err)
+	}
+	snapshots, err := archive.List(storeNumber)
+	if err != nil {
+		return configError(err)
+	}
+
+	trends := buildTrends(storeNumber, storeLabel, snapshots, flagTrendsWeeks, now)
+	if flagJSON {
+		return display.PrintVersionedJSON(cmd.OutOrStdout(), "trends", trends)
+	}
+	printTrends(cmd.OutOrStdout(), trends)
+	return nil
+}
+
+// buildTrends buckets snapshots into the n ad weeks ending with now's week,
+// keeping the latest snapshot of each week.
+func buildTrends(store, storeName string, snapshots []history.Snapshot, n int, now time.Time) trendsJSON {
+	current := history.WeekStart(now)
+	byWeek := map[time.Time]history.Snapshot{}
+	for _, s := range snapshots {
+		byWeek[history.WeekStart(s.SavedAt.In(now.Location()))] = s
+	}
+
+	trends := trendsJSON{Store: store, StoreName: storeName, Weeks: make([]trendWeek, 0, n)}
+	for i := n - 1; i >= 0; i-- {
+		week := current.AddDate(0, 0, -7*i)
+		entry := trendWeek{Week: week.Format("2006-01-02")}
+		if s, ok := byWeek[week]; ok {
+			deals, bogos := len(s.Deals), 0
+			for _, item := range s.Deals {
+				if filter.ContainsIgnoreCase(item.Categories, "bogo") {
+					bogos++
+				}
+			}
+			entry.Updated = s.Updated
+			entry.Deals, entry.BogoDeals = &deals, &bogos
+		}
+		trends.Weeks = append(trends.Weeks, entry)
+	}
+	return trends
+}
+
+func printTrends(w io.Writer, t trendsJSON) {
+	var deals, bogos []*int
+	withData := 0
+	for _, week := range t.Weeks {
+		deals = append(deals, week.Deals)
+		bogos = append(bogos, week.BogoDeals)
+		if week.Deals != nil {
+			withData++
+		}
+	}
+
+	fmt.Fprintf(w, "Publix %s — last %d week(s), %s to %s\n", t.StoreName, len(t.Weeks), t.Weeks[0].Week, t.Weeks[len(t.Weeks)-1].Week)
+	if withData < 2 {
+		fmt.Fprintln(w, "Only one week of history so far; run `pubcli trends` or `pubcli report` each week to build it up.")
+	}
+	if display.Accessible() {
+		for _, week := range t.Weeks {
+			if week.Deals != nil {
+				fmt.Fprintf(w, "  Week of %s: %d deals, %d BOGO.\n", week.Week, *week.Deals, *week.BogoDeals)
+			}
+		}
+		fmt.Fprintf(w, "  Deals: %s.\n  BOGO: %s.\n", trendSummary(deals), trendSummary(bogos))
+		return
+	}
+	fmt.Fprintf(w, "  Deals  %s  %s\n", display.Sparkline(trendSeries(deals)), trendSummary(deals))
+	fmt.Fprintf(w, "  BOGO   %s  %s\n", display.Sparkline(trendSeries(bogos)), trendSummary(bogos))
+}
+
+// trendSeries converts counts to chart values, with NaN for missing weeks.
+func trendSeries(values []*int) []float64 {
+	series := make([]float64, len(values))
+	for i, v := range values {
+		if v == nil {
+			series[i] = math.NaN()
+		} else {
+			series[i] = float64(*v)
+		}
+	}
+	return series
+}
+
+// trendSummary gives the latest value with the series range and the change
+// from the average of the earlier weeks.
+func trendSummary(values []*int) string {
+	lo, hi, ok := seriesRange(values)
+	if !ok {
+		return "no data"
+	}
+	latest, sum, earlier := -1, 0, 0
+	for i := len(values) - 1; i >= 0; i-- {
+		if values[i] == nil {
+			continue
+		}
+		if latest < 0 {
+			latest = *values[i]
+			continue
+		}
+		sum += *values[i]
+		earlier++
+	}
+	summary := fmt.Sprintf("%d now (min %d, max %d)", latest, lo, hi)
+	if earlier > 0 && sum > 0 {
+		avg := float64(sum) / float64(earlier)
+		summary += fmt.Sprintf(", %+.0f%% vs. earlier average", (float64(latest)-avg)/avg*100)
+	}
+	return summary
+}
+
+func seriesRange(values []*int) (lo, hi int, ok bool) {
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if !ok || *v < lo {
+			lo = *v
+		}
+		if !ok || *v > hi {
+			hi = *v
+		}
+		ok = true
+	}
+	return lo, hi, ok
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+func TestBuildTrends_BucketsByAdWeek(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) // a Friday
+	bogo := api.SavingItem{Categories: []string{"bogo"}}
+	plain := api.SavingItem{}
+	snapshots := []history.Snapshot{
+		{Updated: "a", SavedAt: now.AddDate(0, 0, -15), Deals: []api.SavingItem{bogo, plain, plain}},
+		{Updated: "b", SavedAt: now.AddDate(0, 0, -1), Deals: []api.SavingItem{bogo}},
+		{Updated: "c", SavedAt: now, Deals: []api.SavingItem{bogo, bogo, plain, plain}},
+	}
+
+	trends := buildTrends("1425", "#1425", snapshots, 4, now)
+	require.Len(t, trends.Weeks, 4)
+	assert.Equal(t, "2026-09-23", trends.Weeks[0].Week)
+	assert.Nil(t, trends.Weeks[0].Deals)
+	assert.Equal(t, 3, *trends.Weeks[1].Deals)
+	assert.Nil(t, trends.Weeks[2].Deals, "weeks without a snapshot are gaps")
+	assert.Equal(t, "c", trends.Weeks[3].Updated, "the latest snapshot of a week wins")
+	assert.Equal(t, 2, *trends.Weeks[3].BogoDeals)
+
+	var out bytes.Buffer
+	printTrends(&out, trends)
+	assert.Contains(t, out.String(), "  Deals   ▁ █  4 now (min 3, max 4), +33% vs. earlier average")
+	assert.Contains(t, out.String(), "  BOGO    ▁ █  2 now (min 1, max 2), +100% vs. earlier average")
+}
@@ -9,36 +9,66 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/tayloree/publix-deals/internal/api"
-	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/console"
 	"github.com/tayloree/publix-deals/internal/filter"
-	"golang.org/x/term"
+)
+
+var (
+	flagTUIScript     string
+	flagTUIScriptSize string
 )
 
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
 	Short: "Browse deals in a full-screen interactive terminal UI",
 	Example: `  pubcli tui --zip 33101
-  pubcli tui --store 1425 --category produce --sort ending`,
-	RunE: runTUI,
+  pubcli tui --store 1425 --category produce --sort ending
+  pubcli tui --store 1425 --script keys.txt --script-size 160x48`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runTUI,
 }
 
 func init() {
 	rootCmd.AddCommand(tuiCmd)
 	registerDealFilterFlags(tuiCmd.Flags())
+	registerLimitPerGroupFlag(tuiCmd.Flags())
+	registerOutputBudgetFlags(tuiCmd.Flags())
+	registerImageFlags(tuiCmd.Flags())
+	tuiCmd.Flags().StringVar(&flagTUIScript, "script", "", "Replay key events from a file (- for stdin) headlessly and print the final frame")
+	tuiCmd.Flags().StringVar(&flagTUIScriptSize, "script-size", "120x40", "Terminal size used with --script (WIDTHxHEIGHT)")
 }
 
 func runTUI(cmd *cobra.Command, _ []string) error {
 	if err := validateSortMode(); err != nil {
 		return err
 	}
+	if err := validateMinPercentOff(); err != nil {
+		return err
+	}
+	if flagLimitPerGroup < 0 {
+		return invalidArgsError("--limit-per-group must not be negative", "pubcli tui --store 1425 --limit-per-group 3")
+	}
+	if err := validateOutputBudget(); err != nil {
+		return err
+	}
+	if err := validateImageFlags(); err != nil {
+		return err
+	}
 
 	initialOpts := filter.Options{
-		BOGO:       flagBogo,
-		Category:   flagCategory,
-		Department: flagDepartment,
-		Query:      flagQuery,
-		Sort:       flagSort,
-		Limit:      flagLimit,
+		BOGO:           flagBogo,
+		Category:       flagCategory,
+		Department:     flagDepartment,
+		Query:          flagQuery,
+		Sort:           flagSort,
+		Limit:          flagLimit,
+		ExcludeExpired: !flagIncludeExpired,
+		MinPercentOff:  flagMinPercentOff,
+		LimitPerGroup:  flagLimitPerGroup,
+	}
+
+	if flagTUIScript != "" {
+		return runTUIScriptFromFlags(cmd, initialOpts)
 	}
 
 	if flagJSON {
@@ -53,7 +83,12 @@ func runTUI(cmd *cobra.Command, _ []string) error {
 				"Relax filters like --category/--department/--query.",
 			)
 		}
-		return display.PrintDealsJSON(cmd.OutOrStdout(), items)
+		if flagDownloadImages != "" {
+			if err := downloadDealImages(cmd.Context(), cmd.ErrOrStderr(), items); err != nil {
+				return err
+			}
+		}
+		return printDealsJSON(cmd.OutOrStdout(), items, nil, 0)
 	}
 
 	if !isInteractiveSession(cmd.InOrStdin(), cmd.OutOrStdout()) {
@@ -68,13 +103,16 @@ func runTUI(cmd *cobra.Command, _ []string) error {
 		storeNumber: flagStore,
 		zipCode:     flagZip,
 		initialOpts: initialOpts,
+		accessible:  flagAccessible,
+		imageDir:    flagDownloadImages,
+		hidden:      defaultHidden(),
 	})
 
 	program := tea.NewProgram(
 		model,
 		tea.WithAltScreen(),
 		tea.WithInput(cmd.InOrStdin()),
-		tea.WithOutput(cmd.OutOrStdout()),
+		tea.WithOutput(tuiOutput(cmd.OutOrStdout())),
 	)
 
 	finalModel, err := program.Run()
@@ -87,6 +125,34 @@ func runTUI(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+func runTUIScriptFromFlags(cmd *cobra.Command, initialOpts filter.Options) error {
+	width, height, err := parseTUISize(flagTUIScriptSize)
+	if err != nil {
+		return invalidArgsError(err.Error(), "pubcli tui --store 1425 --script keys.txt --script-size 120x40")
+	}
+
+	var script io.Reader
+	if flagTUIScript == "-" {
+		script = cmd.InOrStdin()
+	} else {
+		file, err := os.Open(flagTUIScript)
+		if err != nil {
+			return invalidArgsError(fmt.Sprintf("opening script: %v", err), "pubcli tui --store 1425 --script keys.txt")
+		}
+		defer file.Close()
+		script = file
+	}
+
+	return runTUIScript(cmd.OutOrStdout(), tuiLoadConfig{
+		ctx:         cmd.Context(),
+		storeNumber: flagStore,
+		zipCode:     flagZip,
+		initialOpts: initialOpts,
+		accessible:  flagAccessible,
+		hidden:      defaultHidden(),
+	}, script, width, height)
+}
+
 func resolveStoreForTUI(ctx context.Context, client *api.Client, storeNumber, zipCode string) (resolvedStoreNumber, storeLabel string, err error) {
 	if storeNumber != "" {
 		return storeNumber, "#" + storeNumber, nil
@@ -117,7 +183,7 @@ func resolveStoreForTUI(ctx context.Context, client *api.Client, storeNumber, zi
 }
 
 func loadTUIData(ctx context.Context, storeNumber, zipCode string) (resolvedStoreNumber, storeLabel string, items []api.SavingItem, err error) {
-	client := api.NewClient()
+	client := newAPIClient()
 
 	resolvedStoreNumber, storeLabel, err = resolveStoreForTUI(ctx, client, storeNumber, zipCode)
 	if err != nil {
@@ -143,8 +209,17 @@ func isInteractiveSession(stdin io.Reader, stdout io.Writer) bool {
 	if !ok {
 		return false
 	}
-	if !term.IsTerminal(int(inputFile.Fd())) {
+	if !console.IsTerminal(inputFile) {
 		return false
 	}
 	return isTTY(stdout)
 }
+
+// tuiOutput returns the terminal behind out, so Bubble Tea can size and
+// drive it directly; the TUI applies ASCII glyphs itself in View.
+func tuiOutput(out io.Writer) io.Writer {
+	if f, ok := console.File(out); ok {
+		return f
+	}
+	return out
+}
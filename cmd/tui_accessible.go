@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// tuiAccessibleDelegate renders one plain line per item, marking the
+// selection with a text cue instead of color or a border.
+type tuiAccessibleDelegate struct{}
+
+func (tuiAccessibleDelegate) Height() int                         { return 1 }
+func (tuiAccessibleDelegate) Spacing() int                        { return 0 }
+func (tuiAccessibleDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+func (tuiAccessibleDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	marker := "  "
+	if index == m.Index() {
+		marker = "> "
+	}
+
+	var line string
+	switch value := item.(type) {
+	case tuiGroupItem:
+		line = display.T("tui.section_line", value.ordinal, value.name, value.count)
+	case tuiDealItem:
+		line = value.title
+		if savings := filter.CleanText(filter.Deref(value.deal.Savings)); savings != "" {
+			line += ", " + savings
+		}
+	}
+	fmt.Fprint(w, truncateRunes(marker+line, m.Width()))
+}
+
+// resizeLinear lays the list and detail out top to bottom without borders.
+func (m *dealsTUIModel) resizeLinear() {
+	m.listColumns = 1
+	m.listPaneWidth = m.width - 2
+	m.detailPaneWidth = m.width - 2
+
+	listHeight := maxInt(4, m.bodyHeight/2)
+	m.setListLayout(m.listPaneWidth, listHeight)
+	m.detail.Width = m.detailPaneWidth
+	m.detail.Height = maxInt(3, m.bodyHeight-listHeight-1)
+	m.refreshDetail(false)
+}
+
+func (m dealsTUIModel) linearBodyView() string {
+	return lipgloss.NewStyle().Padding(0, 1).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.list.View(),
+		display.T("tui.details"),
+		m.detail.View(),
+	))
+}
+
+func renderAccessibleDealDetail(item api.SavingItem, width int) string {
+	lines := []string{display.T("tui.deal", filter.Title(item))}
+	for _, field := range display.AccessibleDealFields(item) {
+		lines = append(lines, wrapText(field[0]+": "+field[1], maxInt(24, width)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func truncateRunes(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+var tuiErrorTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("203"))
+
+func newStoreSwitchInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = display.T("tui.store_placeholder")
+	input.Prompt = display.T("tui.store_prompt")
+	input.CharLimit = 10
+	input.Width = 40
+	return input
+}
+
+// updateLoadError handles keys while the load error panel is shown. The panel
+// keeps transient upstream failures inside the TUI: the user can retry, point
+// the TUI at a different store, or quit and get the error on the shell.
+func (m dealsTUIModel) updateLoadError(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.switchingStore {
+		switch msg.String() {
+		case "esc":
+			m.switchingStore = false
+			m.storeInput.Blur()
+			return m, nil
+		case "enter":
+			value := strings.TrimSpace(m.storeInput.Value())
+			if value == "" {
+				return m, nil
+			}
+			m.switchingStore = false
+			m.storeInput.Blur()
+			m.loadCfg.storeNumber, m.loadCfg.zipCode = parseStoreOrZip(value)
+			return m.retryLoad()
+		}
+		var cmd tea.Cmd
+		m.storeInput, cmd = m.storeInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "r":
+		return m.retryLoad()
+	case "S":
+		m.switchingStore = true
+		m.storeInput.SetValue("")
+		return m, m.storeInput.Focus()
+	case "q", "esc":
+		m.fatalErr = m.loadErr
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m dealsTUIModel) retryLoad() (tea.Model, tea.Cmd) {
+	m.loadErr = nil
+	m.loading = true
+	m.loadCmd = loadTUIDataCmd(m.loadCfg)
+	return m, tea.Batch(m.spinner.Tick, m.loadCmd)
+}
+
+func (m dealsTUIModel) loadErrorView() string {
+	width := m.width
+	if width == 0 {
+		width = 80
+	}
+
+	cliErr := classifyCLIError(m.loadErr)
+	lines := []string{
+		tuiHeaderStyle.Render("pubcli tui"),
+		"",
+		tuiErrorTitleStyle.Render(display.T("tui.load_failed")),
+	}
+	for _, line := range strings.Split(formatCLIErrorText(cliErr), "\n") {
+		lines = append(lines, wrapText(line, maxInt(24, width-8)))
+	}
+	lines = append(lines, "")
+	if m.switchingStore {
+		lines = append(lines,
+			m.storeInput.View(),
+			tuiHintStyle.Render(display.T("tui.store_hint")),
+		)
+	} else {
+		lines = append(lines, tuiHintStyle.Render(display.T("tui.error_hint")))
+	}
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// parseStoreOrZip treats five-digit input as a ZIP code and anything else as
+// a store number, matching how users type either into the switch prompt.
+func parseStoreOrZip(value string) (storeNumber, zipCode string) {
+	if len(value) == 5 && strings.Trim(value, "0123456789") == "" {
+		return "", value
+	}
+	return strings.TrimPrefix(value, "#"), ""
+}
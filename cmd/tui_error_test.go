@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func failedTUIModel(t *testing.T) dealsTUIModel {
+	t.Helper()
+	model := newLoadingDealsTUIModel(tuiLoadConfig{storeNumber: "1425"})
+	updated, cmd := model.Update(tuiDataLoadErrMsg{err: upstreamError("fetching deals", errors.New("unexpected status 503"))})
+	assert.Nil(t, cmd, "load errors must not quit the program")
+	return updated.(dealsTUIModel)
+}
+
+func TestTUIModel_LoadErrorRendersPanel(t *testing.T) {
+	model := failedTUIModel(t)
+
+	view := model.View()
+	assert.Contains(t, view, "Could not load deals")
+	assert.Contains(t, view, "unexpected status 503")
+	assert.Contains(t, view, "r retry")
+}
+
+func TestTUIModel_LoadErrorRetry(t *testing.T) {
+	model := failedTUIModel(t)
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	retried := updated.(dealsTUIModel)
+
+	require.NotNil(t, cmd)
+	assert.True(t, retried.loading)
+	assert.Nil(t, retried.loadErr)
+}
+
+func TestTUIModel_LoadErrorSwitchStore(t *testing.T) {
+	model := failedTUIModel(t)
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	model = updated.(dealsTUIModel)
+	require.True(t, model.switchingStore)
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("33101")})
+	updated, _ = updated.(dealsTUIModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(dealsTUIModel)
+
+	assert.True(t, model.loading)
+	assert.Equal(t, "33101", model.loadCfg.zipCode)
+	assert.Empty(t, model.loadCfg.storeNumber)
+}
+
+func TestTUIModel_LoadErrorQuitKeepsError(t *testing.T) {
+	model := failedTUIModel(t)
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	require.NotNil(t, cmd)
+	assert.Error(t, updated.(dealsTUIModel).fatalErr)
+}
+
+func TestParseStoreOrZip(t *testing.T) {
+	store, zip := parseStoreOrZip("33101")
+	assert.Equal(t, "", store)
+	assert.Equal(t, "33101", zip)
+
+	store, zip = parseStoreOrZip("#1425")
+	assert.Equal(t, "1425", store)
+	assert.Equal(t, "", zip)
+}
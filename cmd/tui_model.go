@@ -5,19 +5,34 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/console"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/favorites"
 	"github.com/tayloree/publix-deals/internal/filter"
 )
 
 const (
 	minTUIWidth  = 92
 	minTUIHeight = 24
+
+	// tuiMultiColumnMinWidth is the terminal width at which the deal list
+	// switches to a two-column grid.
+	tuiMultiColumnMinWidth = 160
+	tuiMultiColumnCount    = 2
+	tuiListColumnGap       = 2
+	// tuiMultiColumnChrome reserves rows for the title, status, and pagination
+	// lines rendered above and below the column grid.
+	tuiMultiColumnChrome = 3
 )
 
 var (
@@ -29,6 +44,8 @@ var (
 	tuiDealStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229"))
 	tuiMutedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
 	tuiSectionStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("81"))
+	tuiFlaggedStyle = tuiDealStyle.Strikethrough(true)
+	tuiAvoidStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
 )
 
 type tuiLoadConfig struct {
@@ -36,18 +53,42 @@ type tuiLoadConfig struct {
 	storeNumber string
 	zipCode     string
 	initialOpts filter.Options
+	// accessible selects the linear, border-free layout.
+	accessible bool
+	// imageDir, when set, receives the deals' images in the background.
+	imageDir string
+	// hidden names what the config file's default filters hide, listed with
+	// the active filters.
+	hidden []string
 }
 
 type tuiDataLoadedMsg struct {
+	storeNumber string
 	storeLabel  string
 	allDeals    []api.SavingItem
 	initialOpts filter.Options
+	// starred holds the favorites.Key of every starred deal.
+	starred map[string]bool
+}
+
+// tuiFavoriteToggledMsg reports the star toggled with F.
+type tuiFavoriteToggledMsg struct {
+	key     string
+	title   string
+	starred bool
+	err     error
 }
 
 type tuiDataLoadErrMsg struct {
 	err error
 }
 
+type tuiImagesSavedMsg struct {
+	paths  map[string]string
+	failed int
+	err    error
+}
+
 type tuiFocus int
 
 const (
@@ -64,7 +105,7 @@ type tuiGroupItem struct {
 func (g tuiGroupItem) FilterValue() string { return strings.ToLower(g.name) }
 func (g tuiGroupItem) Title() string       { return fmt.Sprintf("%d. %s", g.ordinal, g.name) }
 func (g tuiGroupItem) Description() string {
-	return fmt.Sprintf("Section header • %d deals", g.count)
+	return display.T("tui.section_header", g.count)
 }
 
 type tuiDealItem struct {
@@ -83,10 +124,18 @@ type dealsTUIModel struct {
 	loading  bool
 	spinner  spinner.Model
 	loadCmd  tea.Cmd
+	loadCfg  tuiLoadConfig
+	loadErr  error
 	fatalErr error
 
-	storeLabel string
-	allDeals   []api.SavingItem
+	switchingStore bool
+	storeInput     textinput.Model
+
+	storeNumber string
+	storeLabel  string
+	allDeals    []api.SavingItem
+	// starred holds the favorites.Key of every starred deal, marked ★.
+	starred map[string]bool
 
 	opts        filter.Options
 	initialOpts filter.Options
@@ -100,13 +149,24 @@ type dealsTUIModel struct {
 	limitChoices      []int
 	limitIndex        int
 
-	list   list.Model
-	detail viewport.Model
+	list     list.Model
+	delegate list.DefaultDelegate
+	detail   viewport.Model
 
 	focus      tuiFocus
 	showHelp   bool
 	selectedID string
 
+	// pendingLetterJump is set after `f` so the next rune jumps to a deal.
+	pendingLetterJump bool
+
+	toasts tuiToasts
+
+	accessible bool
+
+	// localImages maps image URLs to files saved with --download-images.
+	localImages map[string]string
+
 	groupStarts  []int
 	visibleDeals int
 
@@ -114,6 +174,9 @@ type dealsTUIModel struct {
 	bodyHeight      int
 	listPaneWidth   int
 	detailPaneWidth int
+	listColumns     int
+	listColumnRows  int
+	listColumnWidth int
 	tooSmall        bool
 }
 
@@ -122,9 +185,17 @@ func newLoadingDealsTUIModel(cfg tuiLoadConfig) dealsTUIModel {
 	delegate.SetHeight(2)
 	delegate.SetSpacing(1)
 
-	lst := list.New([]list.Item{}, delegate, 0, 0)
-	lst.Title = "Deals"
-	lst.SetStatusBarItemName("item", "items")
+	var itemDelegate list.ItemDelegate = delegate
+	if cfg.accessible {
+		itemDelegate = tuiAccessibleDelegate{}
+	}
+
+	lst := list.New([]list.Item{}, itemDelegate, 0, 0)
+	lst.Title = display.T("tui.deals")
+	if cfg.accessible {
+		lst.Styles.Title = lipgloss.NewStyle()
+	}
+	lst.SetStatusBarItemName(display.T("tui.item"), display.T("tui.items"))
 	lst.SetShowStatusBar(true)
 	lst.SetFilteringEnabled(true)
 	lst.SetShowHelp(false)
@@ -145,28 +216,52 @@ func newLoadingDealsTUIModel(cfg tuiLoadConfig) dealsTUIModel {
 		loading:     true,
 		spinner:     spin,
 		loadCmd:     loadTUIDataCmd(cfg),
+		loadCfg:     cfg,
+		storeInput:  newStoreSwitchInput(),
 		initialOpts: cfg.initialOpts,
 		opts:        cfg.initialOpts,
 		list:        lst,
+		delegate:    delegate,
 		detail:      detail,
 		focus:       tuiFocusList,
+		listColumns: 1,
+		accessible:  cfg.accessible,
 	}
 }
 
 func loadTUIDataCmd(cfg tuiLoadConfig) tea.Cmd {
 	return func() tea.Msg {
-		_, storeLabel, allDeals, err := loadTUIData(cfg.ctx, cfg.storeNumber, cfg.zipCode)
+		storeNumber, storeLabel, allDeals, err := loadTUIData(cfg.ctx, cfg.storeNumber, cfg.zipCode)
 		if err != nil {
 			return tuiDataLoadErrMsg{err: err}
 		}
+		// Stars only mark deals, so unreadable favorites do not stop the TUI.
+		starred, _ := favoriteKeys()
 		return tuiDataLoadedMsg{
+			storeNumber: storeNumber,
 			storeLabel:  storeLabel,
 			allDeals:    allDeals,
 			initialOpts: cfg.initialOpts,
+			starred:     starred,
 		}
 	}
 }
 
+func saveTUIImagesCmd(ctx context.Context, deals []api.SavingItem) tea.Cmd {
+	return func() tea.Msg {
+		result, err := imageDownloader().Download(ctx, dealImageURLs(deals))
+		return tuiImagesSavedMsg{paths: result.Paths, failed: len(result.Failed), err: err}
+	}
+}
+
+// toggleFavoriteCmd stars or unstars the deal in the background.
+func toggleFavoriteCmd(store string, deal api.SavingItem) tea.Cmd {
+	return func() tea.Msg {
+		starred, err := toggleFavorite(store, deal)
+		return tuiFavoriteToggledMsg{key: favorites.Key(deal), title: filter.Title(deal), starred: starred, err: err}
+	}
+}
+
 func (m dealsTUIModel) Init() tea.Cmd {
 	return tea.Batch(m.spinner.Tick, m.loadCmd)
 }
@@ -181,19 +276,59 @@ func (m dealsTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tuiDataLoadedMsg:
 		m.loading = false
+		m.storeNumber = msg.storeNumber
 		m.storeLabel = msg.storeLabel
 		m.allDeals = msg.allDeals
+		m.starred = msg.starred
 		m.initialOpts = canonicalizeTUIOptions(msg.initialOpts)
 		m.opts = m.initialOpts
 		m.initializeInlineChoices()
 		m.applyCurrentFilters(true)
 		m.resize()
+		toast := m.toasts.push(tuiToastSuccess, display.T("tui.loaded", len(m.allDeals)))
+		if m.loadCfg.imageDir != "" {
+			return m, tea.Batch(toast, saveTUIImagesCmd(m.loadCfg.ctx, m.allDeals))
+		}
+		return m, toast
+
+	case tuiImagesSavedMsg:
+		if msg.err != nil {
+			return m, m.toasts.push(tuiToastError, display.T("tui.images_failed", msg.err))
+		}
+		m.localImages = msg.paths
+		m.refreshDetail(false)
+		text := display.T("tui.images_saved", len(msg.paths))
+		if msg.failed > 0 {
+			text += display.T("tui.images_some_failed", msg.failed)
+		}
+		return m, m.toasts.push(tuiToastInfo, text)
+
+	case tuiFavoriteToggledMsg:
+		if msg.err != nil {
+			return m, m.toasts.push(tuiToastError, display.T("tui.star_failed", msg.err))
+		}
+		if m.starred == nil {
+			m.starred = map[string]bool{}
+		}
+		if msg.starred {
+			m.starred[msg.key] = true
+		} else {
+			delete(m.starred, msg.key)
+		}
+		m.applyCurrentFilters(false)
+		if msg.starred {
+			return m, m.toasts.push(tuiToastSuccess, display.T("tui.starred", msg.title))
+		}
+		return m, m.toasts.push(tuiToastInfo, display.T("tui.unstarred", msg.title))
+
+	case tuiToastExpiredMsg:
+		m.toasts.expire(msg.id)
 		return m, nil
 
 	case tuiDataLoadErrMsg:
 		m.loading = false
-		m.fatalErr = msg.err
-		return m, tea.Quit
+		m.loadErr = msg.err
+		return m, nil
 
 	case spinner.TickMsg:
 		if m.loading {
@@ -214,9 +349,12 @@ func (m dealsTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if m.loadErr != nil {
+			return m.updateLoadError(keyMsg)
+		}
 	}
 
-	if m.loading {
+	if m.loading || m.loadErr != nil {
 		return m, nil
 	}
 
@@ -224,6 +362,14 @@ func (m dealsTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		filtering := m.list.FilterState() == list.Filtering
 		key := keyMsg.String()
 
+		if m.pendingLetterJump {
+			m.pendingLetterJump = false
+			if keyMsg.Type == tea.KeyRunes && len(keyMsg.Runes) == 1 {
+				return m, m.jumpToLetter(keyMsg.Runes[0])
+			}
+			return m, nil
+		}
+
 		switch key {
 		case "q":
 			if !filtering {
@@ -280,12 +426,24 @@ func (m dealsTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.opts = m.initialOpts
 				m.syncChoiceIndexesFromOptions()
 				m.applyCurrentFilters(false)
+				return m, m.toasts.push(tuiToastInfo, display.T("tui.filters_reset"))
+			}
+		case "F":
+			if !filtering {
+				if item, ok := m.list.SelectedItem().(tuiDealItem); ok {
+					return m, toggleFavoriteCmd(m.storeNumber, item.deal)
+				}
+				return m, nil
+			}
+		case "f":
+			if !filtering && m.focus == tuiFocusList {
+				m.pendingLetterJump = true
 				return m, nil
 			}
 		case "]":
 			if !filtering {
 				if m.list.IsFiltered() {
-					return m, m.list.NewStatusMessage("Clear fuzzy filter before section jumps.")
+					return m, m.toasts.push(tuiToastWarning, display.T("tui.clear_fuzzy_section"))
 				}
 				m.jumpSection(1)
 				return m, nil
@@ -293,16 +451,27 @@ func (m dealsTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "[":
 			if !filtering {
 				if m.list.IsFiltered() {
-					return m, m.list.NewStatusMessage("Clear fuzzy filter before section jumps.")
+					return m, m.toasts.push(tuiToastWarning, display.T("tui.clear_fuzzy_section"))
 				}
 				m.jumpSection(-1)
 				return m, nil
 			}
 		}
 
+		if !filtering && m.focus == tuiFocusList && m.listColumns > 1 {
+			switch key {
+			case "left":
+				m.moveColumn(-1)
+				return m, nil
+			case "right":
+				m.moveColumn(1)
+				return m, nil
+			}
+		}
+
 		if !filtering && len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
 			if m.list.IsFiltered() {
-				return m, m.list.NewStatusMessage("Clear fuzzy filter before section jumps.")
+				return m, m.toasts.push(tuiToastWarning, display.T("tui.clear_fuzzy_section"))
 			}
 			m.jumpToSection(int(key[0] - '1'))
 			return m, nil
@@ -321,21 +490,30 @@ func (m dealsTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// View renders the current frame, with ASCII glyphs on terminals that
+// cannot show Unicode.
 func (m dealsTUIModel) View() string {
+	if !console.Unicode() {
+		return console.ASCII(m.frame())
+	}
+	return m.frame()
+}
+
+func (m dealsTUIModel) frame() string {
 	if m.loading {
 		return m.loadingView()
 	}
+	if m.loadErr != nil {
+		return m.loadErrorView()
+	}
 	if m.width == 0 || m.height == 0 {
-		return tuiMetaStyle.Render("Loading interface...")
+		return tuiMetaStyle.Render(display.T("tui.loading"))
 	}
 	if m.tooSmall {
 		return lipgloss.NewStyle().
 			Padding(1, 2).
 			Render(
-				fmt.Sprintf(
-					"Terminal too small (%dx%d).\nResize to at least %dx%d for the two-pane deal explorer.",
-					m.width, m.height, minTUIWidth, minTUIHeight,
-				),
+				display.T("tui.too_small", m.width, m.height, minTUIWidth, minTUIHeight),
 			)
 	}
 
@@ -357,17 +535,21 @@ func (m dealsTUIModel) loadingView() string {
 
 	lines := []string{
 		tuiHeaderStyle.Render("pubcli tui"),
-		tuiMetaStyle.Render("Preparing interactive interface..."),
-		"",
-		fmt.Sprintf("%s Fetching store and weekly deals", m.spinner.View()),
-		tuiHintStyle.Render("Tip: press q to cancel."),
+		tuiMetaStyle.Render(display.T("tui.preparing")),
 		"",
-		skeletonStyle.Render("┌──────────────────────────────┬─────────────────────────────────────────┐"),
-		skeletonStyle.Render("│  Loading deal list...        │  Loading detail panel...               │"),
-		skeletonStyle.Render("│  • categories                │  • pricing and validity metadata       │"),
-		skeletonStyle.Render("│  • sections                  │  • wrapped description text            │"),
-		skeletonStyle.Render("│  • filter index              │  • scroll viewport                     │"),
-		skeletonStyle.Render("└──────────────────────────────┴─────────────────────────────────────────┘"),
+		m.spinner.View() + " " + display.T("tui.fetching"),
+		tuiHintStyle.Render(display.T("tui.tip_cancel")),
+	}
+	if !m.accessible {
+		lines = append(lines,
+			"",
+			skeletonStyle.Render("┌──────────────────────────────┬─────────────────────────────────────────┐"),
+			skeletonStyle.Render("│  Loading deal list...        │  Loading detail panel...               │"),
+			skeletonStyle.Render("│  • categories                │  • pricing and validity metadata       │"),
+			skeletonStyle.Render("│  • sections                  │  • wrapped description text            │"),
+			skeletonStyle.Render("│  • filter index              │  • scroll viewport                     │"),
+			skeletonStyle.Render("└──────────────────────────────┴─────────────────────────────────────────┘"),
+		)
 	}
 
 	return lipgloss.NewStyle().
@@ -396,7 +578,19 @@ func (m *dealsTUIModel) resize() {
 	}
 	m.bodyHeight = maxInt(8, m.height-headerH-footerH-1)
 
-	listWidth := maxInt(40, int(float64(m.width)*0.43))
+	if m.accessible {
+		m.resizeLinear()
+		return
+	}
+
+	m.listColumns = 1
+	listRatio := 0.43
+	if m.width >= tuiMultiColumnMinWidth {
+		m.listColumns = tuiMultiColumnCount
+		listRatio = 0.6
+	}
+
+	listWidth := maxInt(40, int(float64(m.width)*listRatio))
 	if listWidth > m.width-42 {
 		listWidth = m.width / 2
 	}
@@ -413,23 +607,111 @@ func (m *dealsTUIModel) resize() {
 	detailInnerWidth := maxInt(24, detailWidth-4)
 	panelInnerHeight := maxInt(6, m.bodyHeight-2)
 
-	m.list.SetSize(listInnerWidth, panelInnerHeight)
+	m.setListLayout(listInnerWidth, panelInnerHeight)
 	m.detail.Width = detailInnerWidth
 	m.detail.Height = panelInnerHeight
 	m.refreshDetail(false)
 }
 
+// setListLayout sizes the list widget for either the single-column view or
+// the multi-column grid. In grid mode the list keeps owning selection and
+// pagination, but its height is stretched so one page holds every column and
+// the chrome is rendered by multiColumnListView instead.
+func (m *dealsTUIModel) setListLayout(innerWidth, innerHeight int) {
+	single := m.listColumns <= 1
+	m.list.SetShowTitle(single)
+	m.list.SetShowFilter(single)
+	m.list.SetShowStatusBar(single)
+	m.list.SetShowPagination(single)
+
+	if single {
+		m.listColumnRows = 0
+		m.listColumnWidth = innerWidth
+		m.list.SetSize(innerWidth, innerHeight)
+		return
+	}
+
+	rowHeight := m.delegate.Height() + m.delegate.Spacing()
+	m.listColumnRows = maxInt(1, (innerHeight-tuiMultiColumnChrome)/rowHeight)
+	m.listColumnWidth = maxInt(20, (innerWidth-tuiListColumnGap*(m.listColumns-1))/m.listColumns)
+	m.list.SetSize(m.listColumnWidth, m.listColumnRows*m.listColumns*rowHeight)
+}
+
+// moveColumn moves the cursor one column left or right in the grid, flowing
+// onto the neighbouring page at the edges so reading order stays linear.
+func (m *dealsTUIModel) moveColumn(delta int) {
+	visible := len(m.list.VisibleItems())
+	if visible == 0 || m.listColumnRows == 0 {
+		return
+	}
+	target := m.list.Index() + delta*m.listColumnRows
+	if target < 0 {
+		target = 0
+	}
+	if target >= visible {
+		target = visible - 1
+	}
+	m.list.Select(target)
+	m.refreshDetail(false)
+}
+
+func (m dealsTUIModel) listView() string {
+	if m.listColumns <= 1 {
+		return m.list.View()
+	}
+	return m.multiColumnListView()
+}
+
+func (m dealsTUIModel) multiColumnListView() string {
+	visible := m.list.VisibleItems()
+
+	head := tuiSectionStyle.Render(m.list.Title)
+	if m.list.FilterState() == list.Filtering {
+		head = m.list.FilterInput.View()
+	}
+
+	pager := m.list.Paginator
+	status := tuiMetaStyle.Render(display.T("deals.count", len(visible)))
+	if len(visible) == 0 {
+		return strings.Join([]string{head, status, "", tuiMutedStyle.Render(display.T("tui.no_items"))}, "\n")
+	}
+
+	start, end := pager.GetSliceBounds(len(visible))
+	page := visible[start:end]
+	columnStyle := lipgloss.NewStyle().Width(m.listColumnWidth)
+
+	columns := make([]string, 0, m.listColumns*2)
+	for col := 0; col < m.listColumns; col++ {
+		var b strings.Builder
+		lo := col * m.listColumnRows
+		hi := minInt(lo+m.listColumnRows, len(page))
+		for i := lo; i < hi; i++ {
+			m.delegate.Render(&b, m.list, start+i, page[i])
+			if i != hi-1 {
+				b.WriteString(strings.Repeat("\n", m.delegate.Spacing()+1))
+			}
+		}
+		if col > 0 {
+			columns = append(columns, strings.Repeat(" ", tuiListColumnGap))
+		}
+		columns = append(columns, columnStyle.Render(b.String()))
+	}
+
+	lines := []string{head, status, lipgloss.JoinHorizontal(lipgloss.Top, columns...)}
+	if pager.TotalPages > 1 {
+		lines = append(lines, tuiHintStyle.Render(display.T("tui.page", pager.Page+1, pager.TotalPages)))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m dealsTUIModel) headerView() string {
-	focus := "list"
+	focus := display.T("tui.focus_list")
 	if m.focus == tuiFocusDetail {
-		focus = "detail"
+		focus = display.T("tui.focus_detail")
 	}
 
 	top := fmt.Sprintf("pubcli tui  |  %s", m.storeLabel)
-	bottom := fmt.Sprintf(
-		"deals: %d visible / %d total  |  filters: %s  |  focus: %s",
-		m.visibleDeals, len(m.allDeals), m.activeFilterSummary(), focus,
-	)
+	bottom := display.T("tui.header_status", m.visibleDeals, len(m.allDeals), m.activeFilterSummary(), focus)
 
 	return lipgloss.NewStyle().
 		Width(m.width).
@@ -438,6 +720,10 @@ func (m dealsTUIModel) headerView() string {
 }
 
 func (m dealsTUIModel) bodyView() string {
+	if m.accessible {
+		return m.linearBodyView()
+	}
+
 	listBorder := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("241")).
@@ -453,7 +739,7 @@ func (m dealsTUIModel) bodyView() string {
 	left := listBorder.
 		Width(m.listPaneWidth).
 		Height(m.bodyHeight).
-		Render(m.list.View())
+		Render(m.listView())
 	right := detailBorder.
 		Width(m.detailPaneWidth).
 		Height(m.bodyHeight).
@@ -463,25 +749,37 @@ func (m dealsTUIModel) bodyView() string {
 }
 
 func (m dealsTUIModel) footerView() string {
-	base := "Tab switch pane • / fuzzy filter • s sort • g bogo • c category • a department • l limit • r reset • [/] section jump • 1-9 section index • q quit"
+	base := display.T("tui.footer")
+	if m.listColumns > 1 {
+		base = display.T("tui.footer_columns") + base
+	}
+	if m.pendingLetterJump {
+		base = display.T("tui.footer_letter")
+	}
 	if m.focus == tuiFocusDetail {
-		base = "Detail: j/k or ↑/↓ scroll • u/d half-page • b/f page • esc list • ? help • q quit"
+		base = display.T("tui.footer_detail")
+	}
+
+	toastLine := ""
+	if !m.toasts.empty() {
+		toastLine = strings.ReplaceAll(m.toasts.view(), "\n", " ") + "\n"
 	}
+	// resize reserves one row per footer line, so lines are cut at the
+	// window's edge rather than wrapping.
+	footer := lipgloss.NewStyle().Padding(0, 1).MaxWidth(m.width)
 
 	if !m.showHelp {
-		return lipgloss.NewStyle().Padding(0, 1).Render(tuiHintStyle.Render(base))
+		return footer.Render(toastLine + tuiHintStyle.Render(base))
 	}
 
 	lines := []string{
-		"Key Help",
-		"list pane: ↑/↓ or j/k move • / fuzzy filter • c category • a department • g bogo • s sort • l limit",
-		"group jumps: ] next section • [ previous section • 1..9 jump to numbered section header",
-		"detail pane: j/k or ↑/↓ scroll • u/d half-page • b/f page up/down",
-		"global: tab switch pane • esc list • r reset inline options • ? toggle help • q quit • ctrl+c force quit",
+		display.T("tui.help_title"),
+		display.T("tui.help_list"),
+		display.T("tui.help_groups"),
+		display.T("tui.help_detail"),
+		display.T("tui.help_global"),
 	}
-	return lipgloss.NewStyle().
-		Padding(0, 1).
-		Render(tuiHintStyle.Render(strings.Join(lines, "\n")))
+	return footer.Render(toastLine + tuiHintStyle.Render(strings.Join(lines, "\n")))
 }
 
 func (m *dealsTUIModel) initializeInlineChoices() {
@@ -581,11 +879,17 @@ func (m dealsTUIModel) activeFilterSummary() string {
 	if m.opts.Limit > 0 {
 		parts = append(parts, fmt.Sprintf("limit:%d", m.opts.Limit))
 	}
+	if m.opts.LimitPerGroup > 0 {
+		parts = append(parts, fmt.Sprintf("per-group:%d", m.opts.LimitPerGroup))
+	}
 	if fuzzy := strings.TrimSpace(m.list.FilterValue()); fuzzy != "" {
 		parts = append(parts, "fuzzy:"+fuzzy)
 	}
+	if len(m.loadCfg.hidden) > 0 {
+		parts = append(parts, "hidden:"+strings.Join(m.loadCfg.hidden, ","))
+	}
 	if len(parts) == 0 {
-		return "none"
+		return display.T("tui.filters_none")
 	}
 	return strings.Join(parts, ", ")
 }
@@ -595,10 +899,16 @@ func (m *dealsTUIModel) applyCurrentFilters(resetSelection bool) {
 	filtered := filter.Apply(m.allDeals, m.opts)
 	m.visibleDeals = len(filtered)
 
-	items, starts := buildGroupedListItems(filtered)
+	items, starts := buildGroupedListItems(filtered, m.opts.LimitPerGroup)
 	m.groupStarts = starts
+	for i, it := range items {
+		if deal, ok := it.(tuiDealItem); ok && m.starred[favorites.Key(deal.deal)] {
+			deal.title = "★ " + deal.title
+			items[i] = deal
+		}
+	}
 
-	m.list.Title = fmt.Sprintf("Deals • %d visible", m.visibleDeals)
+	m.list.Title = display.T("tui.deals_visible", m.visibleDeals)
 	m.list.SetItems(items)
 
 	target := -1
@@ -625,7 +935,14 @@ func (m *dealsTUIModel) refreshDetail(resetScroll bool) {
 	if selected := m.list.SelectedItem(); selected != nil {
 		switch item := selected.(type) {
 		case tuiDealItem:
-			content = renderDealDetailContent(item.deal, m.detail.Width)
+			if m.accessible {
+				content = renderAccessibleDealDetail(item.deal, m.detail.Width)
+			} else {
+				content = renderDealDetailContent(item.deal, m.detail.Width)
+			}
+			if local := m.localImages[strings.TrimSpace(filter.Deref(item.deal.ImageURL))]; local != "" {
+				content += "\n\n" + m.renderLocalImage(local)
+			}
 			nextID = stableIDForDeal(item.deal, item.title)
 		case tuiGroupItem:
 			content = m.renderGroupDetail(item)
@@ -633,7 +950,7 @@ func (m *dealsTUIModel) refreshDetail(resetScroll bool) {
 		}
 	}
 	if content == "" {
-		content = "No deals match the current inline filters.\n\nTry pressing r to reset filters."
+		content = display.T("tui.no_matches")
 	}
 
 	if resetScroll || nextID != m.selectedID {
@@ -643,20 +960,29 @@ func (m *dealsTUIModel) refreshDetail(resetScroll bool) {
 	m.detail.SetContent(content)
 }
 
+// renderLocalImage points at the image saved with --download-images, which
+// terminals cannot draw inline but an image viewer opens offline.
+func (m dealsTUIModel) renderLocalImage(path string) string {
+	if m.accessible {
+		return wrapText(display.T("tui.saved_image")+" "+path, maxInt(24, m.detail.Width))
+	}
+	return tuiMutedStyle.Render(display.T("tui.saved_image")) + "\n" + tuiMutedStyle.Render(wrapText(path, maxInt(24, m.detail.Width)))
+}
+
 func (m dealsTUIModel) renderGroupDetail(group tuiGroupItem) string {
 	preview := m.groupPreviewTitles(group.name, 5)
 
 	lines := []string{
-		tuiSectionStyle.Render(fmt.Sprintf("Section %d: %s", group.ordinal, group.name)),
-		tuiMetaStyle.Render(fmt.Sprintf("%d deals in this section", group.count)),
+		tuiSectionStyle.Render(display.T("tui.section", group.ordinal, group.name)),
+		tuiMetaStyle.Render(display.T("tui.section_count", group.count)),
 		"",
-		tuiMetaStyle.Render("Jump keys:"),
-		"- `]` next section, `[` previous section",
-		"- `1..9` jump directly to section number",
+		tuiMetaStyle.Render(display.T("tui.jump_keys")),
+		display.T("tui.jump_sections"),
+		display.T("tui.jump_numbers"),
 	}
 	if len(preview) > 0 {
 		lines = append(lines, "")
-		lines = append(lines, tuiMetaStyle.Render("Preview:"))
+		lines = append(lines, tuiMetaStyle.Render(display.T("tui.preview")))
 		for _, title := range preview {
 			lines = append(lines, "• "+title)
 		}
@@ -712,6 +1038,52 @@ func (m *dealsTUIModel) jumpSection(delta int) {
 	m.jumpToSection(next)
 }
 
+// jumpToLetter moves the cursor to the next deal in the current section whose
+// title starts with letter, wrapping around within the section.
+func (m *dealsTUIModel) jumpToLetter(letter rune) tea.Cmd {
+	if m.list.IsFiltered() {
+		return m.toasts.push(tuiToastWarning, display.T("tui.clear_fuzzy_letter"))
+	}
+	items := m.list.Items()
+	if len(items) == 0 {
+		return nil
+	}
+
+	start, end := 0, len(items)
+	if section := m.currentSectionIndex(); section >= 0 {
+		start = m.groupStarts[section]
+		if section+1 < len(m.groupStarts) {
+			end = m.groupStarts[section+1]
+		}
+	}
+
+	want := unicode.ToLower(letter)
+	cursor := m.list.GlobalIndex()
+	span := end - start
+	for step := 1; step <= span; step++ {
+		idx := start + (cursor-start+step)%span
+		deal, ok := items[idx].(tuiDealItem)
+		if !ok {
+			continue
+		}
+		if first, ok := firstAlphanumeric(deal.title); ok && unicode.ToLower(first) == want {
+			m.list.Select(idx)
+			m.refreshDetail(true)
+			return nil
+		}
+	}
+	return m.toasts.push(tuiToastInfo, display.T("tui.no_letter", letter))
+}
+
+func firstAlphanumeric(s string) (rune, bool) {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
 func (m dealsTUIModel) currentSectionIndex() int {
 	if len(m.groupStarts) == 0 {
 		return -1
@@ -728,7 +1100,9 @@ func (m dealsTUIModel) currentSectionIndex() int {
 	return current
 }
 
-func buildGroupedListItems(deals []api.SavingItem) (items []list.Item, starts []int) {
+// buildGroupedListItems lays deals out under their group headers, keeping at
+// most perGroup deals of each group when perGroup is positive.
+func buildGroupedListItems(deals []api.SavingItem, perGroup int) (items []list.Item, starts []int) {
 	if len(deals) == 0 {
 		return nil, nil
 	}
@@ -736,6 +1110,9 @@ func buildGroupedListItems(deals []api.SavingItem) (items []list.Item, starts []
 	groups := map[string][]api.SavingItem{}
 	for _, deal := range deals {
 		group := dealGroupLabel(deal)
+		if perGroup > 0 && len(groups[group]) >= perGroup {
+			continue
+		}
 		groups[group] = append(groups[group], deal)
 	}
 
@@ -793,24 +1170,33 @@ func dealGroupLabel(item api.SavingItem) string {
 	if dept := strings.TrimSpace(filter.CleanText(filter.Deref(item.Department))); dept != "" {
 		return humanizeLabel(dept)
 	}
-	return "Other"
+	return display.T("tui.group_other")
 }
 
 func buildTUIDealItem(item api.SavingItem, group string) tuiDealItem {
-	title := topDealTitle(item)
+	title := filter.Title(item)
 	savings := filter.CleanText(filter.Deref(item.Savings))
 	if savings == "" {
-		savings = "No savings text"
+		savings = display.T("tui.no_savings_text")
 	}
 	dept := filter.CleanText(filter.Deref(item.Department))
 	end := strings.TrimSpace(item.EndFormatted)
 
 	descParts := []string{savings}
+	if len(item.Flags) > 0 {
+		descParts = []string{display.T("field.avoid") + ": " + strings.Join(item.Flags, ", "), savings}
+	}
 	if dept != "" {
 		descParts = append(descParts, dept)
 	}
-	if end != "" {
-		descParts = append(descParts, "ends "+end)
+	now := time.Now()
+	switch text, _, ok := display.EndsIn(item, now); {
+	case ok && display.Accessible():
+		descParts = append(descParts, text)
+	case ok:
+		descParts = append(descParts, display.RenderEndsIn(item, now))
+	case end != "":
+		descParts = append(descParts, display.T("tui.ends", end))
 	}
 
 	filterTokens := []string{
@@ -822,6 +1208,7 @@ func buildTUIDealItem(item api.SavingItem, group string) tuiDealItem {
 		dept,
 		end,
 		group,
+		strings.Join(item.Flags, " "),
 	}
 
 	return tuiDealItem{
@@ -836,62 +1223,70 @@ func buildTUIDealItem(item api.SavingItem, group string) tuiDealItem {
 func renderDealDetailContent(item api.SavingItem, width int) string {
 	maxWidth := maxInt(24, width)
 
-	title := topDealTitle(item)
+	title := filter.Title(item)
 	savings := filter.CleanText(filter.Deref(item.Savings))
 	if savings == "" {
-		savings = "No savings value provided"
+		savings = display.T("tui.no_savings_value")
 	}
 
-	desc := filter.CleanText(filter.Deref(item.Description))
+	desc := filter.CleanRichText(filter.Deref(item.Description))
 	if desc == "" {
-		desc = "No description provided."
+		desc = display.T("tui.no_description")
 	}
 
 	dept := filter.CleanText(filter.Deref(item.Department))
 	brand := filter.CleanText(filter.Deref(item.Brand))
 	dealInfo := filter.CleanText(filter.Deref(item.AdditionalDealInfo))
-	validity := strings.TrimSpace(item.StartFormatted + " - " + item.EndFormatted)
+	validity := strings.TrimSpace(display.FormatDealDate(item.StartFormatted) + " - " + display.FormatDealDate(item.EndFormatted))
 	imageURL := strings.TrimSpace(filter.Deref(item.ImageURL))
 
 	lines := []string{
 		tuiDealStyle.Render(wrapText(title, maxWidth)),
 	}
+	if len(item.Flags) > 0 {
+		lines = []string{
+			tuiFlaggedStyle.Render(wrapText(title, maxWidth)),
+			tuiAvoidStyle.Render(wrapText(display.T("field.avoid")+": "+strings.Join(item.Flags, ", "), maxWidth)),
+		}
+	}
 
 	metaBits := []string{}
 	if filter.ContainsIgnoreCase(item.Categories, "bogo") {
 		metaBits = append(metaBits, tuiBogoStyle.Render("BOGO"))
 	}
 	if len(item.Categories) > 0 {
-		metaBits = append(metaBits, "categories: "+strings.Join(item.Categories, ", "))
+		metaBits = append(metaBits, display.T("field.categories")+": "+strings.Join(item.Categories, ", "))
 	}
 	if len(metaBits) > 0 {
 		lines = append(lines, tuiMetaStyle.Render(wrapText(strings.Join(metaBits, "  |  "), maxWidth)))
 	}
 
 	lines = append(lines, "")
-	lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render("Savings:"), tuiValueStyle.Render(savings)))
+	lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render(display.T("field.savings")+":"), tuiValueStyle.Render(savings)))
 	if dealInfo != "" {
-		lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render("Deal info:"), wrapText(dealInfo, maxWidth)))
+		lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render(display.T("field.deal_info")+":"), wrapText(dealInfo, maxWidth)))
 	}
 	lines = append(lines, "")
-	lines = append(lines, tuiMetaStyle.Render("Description:"))
-	lines = append(lines, wrapText(desc, maxWidth))
+	lines = append(lines, tuiMetaStyle.Render(display.T("field.description")+":"))
+	for _, line := range strings.Split(desc, "\n") {
+		lines = append(lines, wrapText(line, maxWidth))
+	}
 	lines = append(lines, "")
 
 	if dept != "" {
-		lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render("Department:"), dept))
+		lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render(display.T("field.department")+":"), dept))
 	}
 	if brand != "" {
-		lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render("Brand:"), brand))
+		lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render(display.T("field.brand")+":"), brand))
 	}
 	if strings.Trim(validity, " -") != "" {
-		lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render("Valid:"), strings.Trim(validity, " -")))
+		lines = append(lines, fmt.Sprintf("%s %s", tuiMetaStyle.Render(display.T("field.valid")+":"), strings.Trim(validity, " -")))
 	}
-	lines = append(lines, fmt.Sprintf("%s %.2f", tuiMetaStyle.Render("Score:"), filter.DealScore(item)))
+	lines = append(lines, fmt.Sprintf("%s %.2f", tuiMetaStyle.Render(display.T("field.score")+":"), filter.DealScore(item)))
 
 	if imageURL != "" {
 		lines = append(lines, "")
-		lines = append(lines, tuiMutedStyle.Render("Image URL:"))
+		lines = append(lines, tuiMutedStyle.Render(display.T("field.image_url")+":"))
 		lines = append(lines, tuiMutedStyle.Render(wrapText(imageURL, maxWidth)))
 	}
 
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/console"
 )
 
 func strPtr(value string) *string { return &value }
@@ -26,7 +31,7 @@ func TestBuildGroupedListItems_BogoFirstAndNumberedHeaders(t *testing.T) {
 		{ID: "4", Title: strPtr("Ground Beef"), Categories: []string{"meat"}},
 	}
 
-	items, starts := buildGroupedListItems(deals)
+	items, starts := buildGroupedListItems(deals, 0)
 
 	assert.NotEmpty(t, items)
 	assert.Equal(t, []int{0, 2, 5}, starts)
@@ -47,6 +52,20 @@ func TestBuildGroupedListItems_BogoFirstAndNumberedHeaders(t *testing.T) {
 	assert.Equal(t, 1, header3.count)
 }
 
+func TestBuildGroupedListItems_LimitPerGroup(t *testing.T) {
+	deals := []api.SavingItem{
+		{ID: "1", Title: strPtr("Bananas"), Categories: []string{"produce"}},
+		{ID: "2", Title: strPtr("Apples"), Categories: []string{"produce"}},
+		{ID: "3", Title: strPtr("Pears"), Categories: []string{"produce"}},
+		{ID: "4", Title: strPtr("Ground Beef"), Categories: []string{"meat"}},
+	}
+
+	items, starts := buildGroupedListItems(deals, 2)
+	assert.Equal(t, []int{0, 3}, starts)
+	assert.Len(t, items, 5)
+	assert.Equal(t, 2, items[0].(tuiGroupItem).count)
+}
+
 func TestBuildCategoryChoices_AlwaysIncludesCurrent(t *testing.T) {
 	deals := []api.SavingItem{
 		{Categories: []string{"produce"}},
@@ -60,3 +79,122 @@ func TestBuildCategoryChoices_AlwaysIncludesCurrent(t *testing.T) {
 	assert.Contains(t, choices, "meat")
 	assert.Contains(t, choices, "seafood")
 }
+
+func loadedTUIModel(t *testing.T, deals []api.SavingItem, width, height int) dealsTUIModel {
+	t.Helper()
+	model := newLoadingDealsTUIModel(tuiLoadConfig{})
+	updated, _ := model.Update(tuiDataLoadedMsg{storeLabel: "#1425", allDeals: deals})
+	updated, _ = updated.(dealsTUIModel).Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return updated.(dealsTUIModel)
+}
+
+func manyDeals(count int) []api.SavingItem {
+	deals := make([]api.SavingItem, 0, count)
+	for i := range count {
+		deals = append(deals, api.SavingItem{
+			ID:         fmt.Sprintf("%d", i),
+			Title:      strPtr(fmt.Sprintf("Deal %02d", i)),
+			Categories: []string{"grocery"},
+		})
+	}
+	return deals
+}
+
+func TestTUIModel_WideTerminalUsesColumns(t *testing.T) {
+	narrow := loadedTUIModel(t, manyDeals(30), 120, 40)
+	assert.Equal(t, 1, narrow.listColumns)
+
+	wide := loadedTUIModel(t, manyDeals(30), 200, 40)
+	assert.Equal(t, 2, wide.listColumns)
+	assert.Greater(t, wide.listColumnRows, 0)
+	assert.Equal(t, wide.listColumnRows*2, wide.list.Paginator.PerPage)
+}
+
+func TestTUIModel_ColumnNavigationMovesByRows(t *testing.T) {
+	model := loadedTUIModel(t, manyDeals(30), 200, 40)
+	start := model.list.Index()
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	moved := updated.(dealsTUIModel)
+	assert.Equal(t, start+model.listColumnRows, moved.list.Index())
+
+	updated, _ = moved.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	assert.Equal(t, start, updated.(dealsTUIModel).list.Index())
+}
+
+func TestTUIModel_LetterJumpWrapsWithinSection(t *testing.T) {
+	deals := []api.SavingItem{
+		{ID: "1", Title: strPtr("Apples"), Categories: []string{"produce"}},
+		{ID: "2", Title: strPtr("Bananas"), Categories: []string{"produce"}},
+		{ID: "3", Title: strPtr("Blueberries"), Categories: []string{"produce"}},
+		{ID: "4", Title: strPtr("Cherries"), Categories: []string{"produce"}},
+	}
+	model := loadedTUIModel(t, deals, 120, 40)
+
+	press := func(m dealsTUIModel, r rune) dealsTUIModel {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		return updated.(dealsTUIModel)
+	}
+	selectedTitle := func(m dealsTUIModel) string {
+		return m.list.SelectedItem().(tuiDealItem).title
+	}
+
+	model = press(press(model, 'f'), 'b')
+	assert.Equal(t, "Bananas", selectedTitle(model))
+	model = press(press(model, 'f'), 'B')
+	assert.Equal(t, "Blueberries", selectedTitle(model))
+	model = press(press(model, 'f'), 'b')
+	assert.Equal(t, "Bananas", selectedTitle(model))
+	assert.False(t, model.pendingLetterJump)
+}
+
+func TestTUIModel_FooterFitsItsRows(t *testing.T) {
+	model := loadedTUIModel(t, manyDeals(5), minTUIWidth, minTUIHeight)
+	for _, text := range []string{"Starred Extra Large Family Size Chicken Thighs", "Star not saved: disk\nfull", "Filters reset"} {
+		model.toasts.push(tuiToastInfo, text)
+	}
+
+	footer := model.footerView()
+	assert.Equal(t, 2, lipgloss.Height(footer), "a toast line and a hint line")
+	for _, line := range strings.Split(footer, "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), minTUIWidth, "footer lines must not wrap")
+	}
+	assert.LessOrEqual(t, lipgloss.Height(model.View()), minTUIHeight)
+}
+
+func TestTUIModel_AccessibleLayoutIsLinear(t *testing.T) {
+	model := newLoadingDealsTUIModel(tuiLoadConfig{accessible: true})
+	updated, _ := model.Update(tuiDataLoadedMsg{storeLabel: "#1425", allDeals: manyDeals(5)})
+	updated, _ = updated.(dealsTUIModel).Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+	model = updated.(dealsTUIModel)
+
+	view := model.View()
+	assert.Equal(t, 1, model.listColumns)
+	assert.Contains(t, view, "> Deal 00")
+	assert.Contains(t, view, "Details:")
+	assert.NotContains(t, view, "╭")
+}
+
+func TestTUIModel_SavedImageShownInDetail(t *testing.T) {
+	deals := []api.SavingItem{
+		{ID: "1", Title: strPtr("Coffee"), ImageURL: strPtr("https://img.example.com/coffee.jpg"), Categories: []string{"grocery"}},
+	}
+	model := loadedTUIModel(t, deals, 120, 40)
+	model.list.Select(1)
+	model.refreshDetail(true)
+	assert.NotContains(t, model.detail.View(), "Saved image:")
+
+	updated, _ := model.Update(tuiImagesSavedMsg{paths: map[string]string{"https://img.example.com/coffee.jpg": "/tmp/img/ab12.jpg"}})
+	assert.Contains(t, updated.(dealsTUIModel).detail.View(), "/tmp/img/ab12.jpg")
+}
+
+func TestTUIModel_ASCIIGlyphsWithoutUnicode(t *testing.T) {
+	console.Configure(console.Capabilities{VT: true, Unicode: false})
+	t.Cleanup(func() { console.Configure(console.Capabilities{VT: true, Unicode: true}) })
+
+	view := loadedTUIModel(t, manyDeals(5), 120, 40).View()
+	assert.Contains(t, view, "+-")
+	for _, glyph := range []string{"╭", "─", "│", "•"} {
+		assert.NotContains(t, view, glyph)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var tuiScriptKeyTypes = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"backspace": tea.KeyBackspace,
+	"space":     tea.KeySpace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"ctrl+c":    tea.KeyCtrlC,
+}
+
+// parseTUIScript reads a key script. Each non-blank line that does not start
+// with `#` is one of:
+//
+//	<key>          a key name (enter, esc, tab, up, down, ctrl+c, ...) or a single character
+//	type <text>    every rune of text as individual key presses
+//	resize WxH     a terminal resize
+func parseTUIScript(r io.Reader) ([]tea.Msg, error) {
+	var msgs []tea.Msg
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "type "):
+			for _, r := range strings.TrimPrefix(line, "type ") {
+				msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			}
+		case strings.HasPrefix(line, "resize "):
+			width, height, err := parseTUISize(strings.TrimPrefix(line, "resize "))
+			if err != nil {
+				return nil, fmt.Errorf("script line %d: %w", lineNo, err)
+			}
+			msgs = append(msgs, tea.WindowSizeMsg{Width: width, Height: height})
+		default:
+			msg, err := parseTUIScriptKey(line)
+			if err != nil {
+				return nil, fmt.Errorf("script line %d: %w", lineNo, err)
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading script: %w", err)
+	}
+	return msgs, nil
+}
+
+func parseTUIScriptKey(name string) (tea.KeyMsg, error) {
+	if keyType, ok := tuiScriptKeyTypes[strings.ToLower(name)]; ok {
+		return tea.KeyMsg{Type: keyType}, nil
+	}
+	runes := []rune(name)
+	if len(runes) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: runes}, nil
+	}
+	return tea.KeyMsg{}, fmt.Errorf("unknown key %q (use a key name, a single character, or `type <text>`)", name)
+}
+
+func parseTUISize(raw string) (width, height int, err error) {
+	parts := strings.SplitN(strings.ToLower(strings.TrimSpace(raw)), "x", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid size %q (use WIDTHxHEIGHT, e.g. 120x40)", raw)
+	}
+	width, werr := strconv.Atoi(parts[0])
+	height, herr := strconv.Atoi(parts[1])
+	if werr != nil || herr != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid size %q (use WIDTHxHEIGHT, e.g. 120x40)", raw)
+	}
+	return width, height, nil
+}
+
+// runTUIScript loads deals, replays the script against the TUI in a
+// bubbletea program with no terminal attached, and writes the final
+// rendered frame. Commands the keys start run as they would interactively;
+// ones still running when the script ends are not waited for.
+func runTUIScript(w io.Writer, cfg tuiLoadConfig, script io.Reader, width, height int) error {
+	msgs, err := parseTUIScript(script)
+	if err != nil {
+		return invalidArgsError(err.Error(), "pubcli tui --store 1425 --script keys.txt")
+	}
+
+	// The deals are loaded before the script starts, not by Init, so the
+	// first key already sees them.
+	model := newLoadingDealsTUIModel(cfg)
+	model.loadCmd = nil
+	program := tea.NewProgram(model, tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutSignalHandler())
+	go func() {
+		program.Send(tea.WindowSizeMsg{Width: width, Height: height})
+		program.Send(loadTUIDataCmd(cfg)())
+		for _, msg := range msgs {
+			program.Send(msg)
+		}
+		program.Quit()
+	}()
+	final, err := program.Run()
+	if err != nil {
+		return fmt.Errorf("replaying script: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, final.View())
+	return err
+}
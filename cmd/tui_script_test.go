@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func scriptFixtureDeals() []api.SavingItem {
+	return []api.SavingItem{
+		{ID: "1", Title: strPtr("Bananas"), Savings: strPtr("$0.59 lb"), Categories: []string{"produce"}},
+		{ID: "2", Title: strPtr("Chicken Thighs"), Savings: strPtr("Buy 1 Get 1 FREE"), Categories: []string{"meat", "bogo"}},
+		{ID: "3", Title: strPtr("Apples"), Savings: strPtr("$2.00 off"), Categories: []string{"produce"}},
+		{ID: "4", Title: strPtr("Ground Beef"), Savings: strPtr("$5.00 off"), Categories: []string{"meat"}},
+	}
+}
+
+// startTUIFixture runs the TUI on the fixture deals under teatest and waits
+// until the list is on screen.
+func startTUIFixture(t *testing.T) *teatest.TestModel {
+	t.Helper()
+	model := newLoadingDealsTUIModel(tuiLoadConfig{})
+	model.loadCmd = func() tea.Msg {
+		return tuiDataLoadedMsg{storeLabel: "#1425", allDeals: scriptFixtureDeals()}
+	}
+	tm := teatest.NewTestModel(t, model, teatest.WithInitialTermSize(120, 40))
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Bananas"))
+	}, teatest.WithDuration(2*time.Second))
+	return tm
+}
+
+// runScriptAgainstFixture replays script against the fixture TUI, quits,
+// and returns the final model, whose View is the last frame drawn.
+func runScriptAgainstFixture(t *testing.T, script string) dealsTUIModel {
+	t.Helper()
+	msgs, err := parseTUIScript(strings.NewReader(script))
+	require.NoError(t, err)
+
+	tm := startTUIFixture(t)
+	for _, msg := range msgs {
+		tm.Send(msg)
+	}
+	require.NoError(t, tm.Quit())
+	return tm.FinalModel(t, teatest.WithFinalTimeout(2*time.Second)).(dealsTUIModel)
+}
+
+func TestParseTUIScript(t *testing.T) {
+	msgs, err := parseTUIScript(strings.NewReader("# comment\n\ndown\ns\ntype ab\nresize 160x48\n"))
+
+	require.NoError(t, err)
+	require.Len(t, msgs, 5)
+	assert.Equal(t, tea.KeyMsg{Type: tea.KeyDown}, msgs[0])
+	assert.Equal(t, "s", msgs[1].(tea.KeyMsg).String())
+	assert.Equal(t, "a", msgs[2].(tea.KeyMsg).String())
+	assert.Equal(t, "b", msgs[3].(tea.KeyMsg).String())
+	assert.Equal(t, tea.WindowSizeMsg{Width: 160, Height: 48}, msgs[4])
+}
+
+func TestParseTUIScript_RejectsUnknownKey(t *testing.T) {
+	_, err := parseTUIScript(strings.NewReader("down\nbogus-key\n"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestTUIScript_SortCyclesToSavings(t *testing.T) {
+	model := runScriptAgainstFixture(t, "s\n")
+
+	assert.Equal(t, "savings", model.opts.Sort)
+	assert.Contains(t, model.View(), "sort:savings")
+}
+
+func TestTUIScript_SectionJumpAndBogoFilter(t *testing.T) {
+	model := runScriptAgainstFixture(t, "]\n")
+	selected := model.list.SelectedItem().(tuiDealItem)
+	assert.Equal(t, "Produce", selected.group)
+
+	model = runScriptAgainstFixture(t, "g\n")
+	assert.True(t, model.opts.BOGO)
+	assert.Equal(t, 1, model.visibleDeals)
+	assert.Contains(t, model.View(), "Chicken Thighs")
+}
+
+func TestTUIScript_QuitKeyEndsTheProgram(t *testing.T) {
+	tm := startTUIFixture(t)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	// No Quit from the test: the program must end on its own.
+	model := tm.FinalModel(t, teatest.WithFinalTimeout(2*time.Second)).(dealsTUIModel)
+	assert.False(t, model.loading)
+}
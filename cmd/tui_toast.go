@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	tuiToastTTL        = 3 * time.Second
+	tuiToastMaxVisible = 3
+)
+
+type tuiToastLevel int
+
+const (
+	tuiToastInfo tuiToastLevel = iota
+	tuiToastSuccess
+	tuiToastWarning
+	tuiToastError
+)
+
+var tuiToastStyles = map[tuiToastLevel]lipgloss.Style{
+	tuiToastInfo:    lipgloss.NewStyle().Foreground(lipgloss.Color("81")),
+	tuiToastSuccess: lipgloss.NewStyle().Foreground(lipgloss.Color("78")),
+	tuiToastWarning: lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+	tuiToastError:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("203")),
+}
+
+var tuiToastIcons = map[tuiToastLevel]string{
+	tuiToastInfo:    "•",
+	tuiToastSuccess: "✓",
+	tuiToastWarning: "!",
+	tuiToastError:   "✗",
+}
+
+type tuiToast struct {
+	id    int
+	level tuiToastLevel
+	text  string
+}
+
+type tuiToastExpiredMsg struct {
+	id int
+}
+
+// tuiToasts is a small queue of transient notifications. Unlike the list
+// widget's status message it is independent of which pane has focus, so
+// background events can report progress without touching the list state.
+type tuiToasts struct {
+	items  []tuiToast
+	nextID int
+}
+
+// push queues a toast and returns the command that expires it.
+func (t *tuiToasts) push(level tuiToastLevel, text string) tea.Cmd {
+	t.nextID++
+	id := t.nextID
+	t.items = append(t.items, tuiToast{id: id, level: level, text: text})
+	if len(t.items) > tuiToastMaxVisible {
+		t.items = t.items[len(t.items)-tuiToastMaxVisible:]
+	}
+	return tea.Tick(tuiToastTTL, func(time.Time) tea.Msg {
+		return tuiToastExpiredMsg{id: id}
+	})
+}
+
+func (t *tuiToasts) expire(id int) {
+	for i, toast := range t.items {
+		if toast.id == id {
+			t.items = append(t.items[:i], t.items[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t tuiToasts) empty() bool {
+	return len(t.items) == 0
+}
+
+// view renders the queued toasts on a single line, newest last.
+func (t tuiToasts) view() string {
+	if t.empty() {
+		return ""
+	}
+	parts := make([]string, 0, len(t.items))
+	for _, toast := range t.items {
+		parts = append(parts, tuiToastStyles[toast.level].Render(tuiToastIcons[toast.level]+" "+toast.text))
+	}
+	return strings.Join(parts, "   ")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTUIToasts_PushAndExpire(t *testing.T) {
+	var toasts tuiToasts
+	require.NotNil(t, toasts.push(tuiToastSuccess, "Saved"))
+	require.NotNil(t, toasts.push(tuiToastWarning, "Careful"))
+
+	assert.Contains(t, toasts.view(), "Saved")
+	assert.Contains(t, toasts.view(), "Careful")
+
+	toasts.expire(1)
+	assert.NotContains(t, toasts.view(), "Saved")
+	assert.Contains(t, toasts.view(), "Careful")
+
+	toasts.expire(2)
+	assert.True(t, toasts.empty())
+}
+
+func TestTUIToasts_CapsVisibleQueue(t *testing.T) {
+	var toasts tuiToasts
+	for _, text := range []string{"one", "two", "three", "four"} {
+		toasts.push(tuiToastInfo, text)
+	}
+
+	assert.Len(t, toasts.items, tuiToastMaxVisible)
+	assert.NotContains(t, toasts.view(), "one")
+	assert.Contains(t, toasts.view(), "four")
+}
+
+func TestTUIModel_DataLoadShowsToast(t *testing.T) {
+	model := loadedTUIModel(t, manyDeals(3), 120, 40)
+
+	assert.Contains(t, model.footerView(), "Loaded 3 deals")
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tayloree/publix-deals/internal/addiff"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+// watchMinInterval keeps --interval from polling Publix harder than the
+// ad changes.
+const watchMinInterval = time.Minute
+
+var flagWatchInterval time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print deals that are new since the previous weekly ad",
+	Long: "Fetch the store's weekly ad and print the deals matching the filter flags that were not " +
+		"in the previous ad saved in the history. Each check saves the ad to the history, so run " +
+		"it from cron, or keep it running with --interval to check again on that schedule and " +
+		"print only when a new ad comes out. The first check for a store has no earlier ad to " +
+		"compare with. With --interval and --json, each report is one line of NDJSON.",
+	Example: `  pubcli watch --store 1425 --query coffee
+  pubcli watch --store 1425 --category bogo --interval 1h
+  pubcli watch --store 1425 -q coffee --interval 30m --json | jq -c '.new[]'`,
+	Annotations: map[string]string{annotationNetwork: "true"},
+	RunE:        runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	registerDealFilterFlags(watchCmd.Flags())
+	watchCmd.Flags().DurationVar(&flagWatchInterval, "interval", 0, "Check again this often until interrupted, e.g. 1h (0 = check once)")
+}
+
+// watchJSON is one report of `watch --json`.
+type watchJSON struct {
+	Store   string `json:"store"`
+	Updated string `json:"updated,omitempty"`
+	// Since is the date the previous ad was saved; empty when there is none
+	// to compare with.
+	Since     string             `json:"since,omitempty"`
+	CheckedAt time.Time          `json:"checkedAt"`
+	New       []display.DealJSON `json:"new"`
+}
+
+func runWatch(cmd *cobra.Command, _ []string) error {
+	if err := validateSortMode(); err != nil {
+		return err
+	}
+	if err := validateMinPercentOff(); err != nil {
+		return err
+	}
+	if flagWatchInterval != 0 && flagWatchInterval < watchMinInterval {
+		return invalidArgsError(
+			fmt.Sprintf("--interval must be at least %s", watchMinInterval),
+			"pubcli watch --store 1425 --query coffee --interval 1h",
+		)
+	}
+
+	client := newAPIClient()
+	storeNumber, err := resolveStore(cmd, client)
+	if err != nil {
+		return err
+	}
+	archive, err := adArchive()
+	if err != nil {
+		return err
+	}
+
+	report, err := checkWatch(cmd.Context(), client, archive, storeNumber)
+	if err != nil {
+		return err
+	}
+	if flagWatchInterval == 0 {
+		if flagJSON {
+			return display.PrintVersionedJSON(cmd.OutOrStdout(), "watch", report)
+		}
+		printWatch(cmd.OutOrStdout(), report)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := emitWatch(cmd.OutOrStdout(), report); err != nil {
+		return err
+	}
+	seen := report.Updated
+	ticker := time.NewTicker(flagWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		report, err := checkWatch(ctx, client, archive, storeNumber)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: %v\n", err)
+			continue
+		}
+		if report.Updated == seen {
+			continue
+		}
+		seen = report.Updated
+		if err := emitWatch(cmd.OutOrStdout(), report); err != nil {
+			return err
+		}
+	}
+}
+
+// checkWatch fetches the store's ad, saves it to the history, and returns
+// the filtered deals that were not in the previous ad version.
+func checkWatch(ctx context.Context, client *api.Client, archive history.Archive, storeNumber string) (watchJSON, error) {
+	data, err := fetchSavingsCached(ctx, client, storeNumber)
+	if err != nil {
+		return watchJSON{}, upstreamError("fetching deals", err)
+	}
+	snapshots, err := archive.List(storeNumber)
+	if err != nil {
+		return watchJSON{}, err
+	}
+	var previous *history.Snapshot
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if data.WeeklyAdLatestUpdatedDateTime == "" || snapshots[i].Updated != data.WeeklyAdLatestUpdatedDateTime {
+			previous = &snapshots[i]
+			break
+		}
+	}
+	now := time.Now()
+	if err := archive.Save(history.Snapshot{
+		Store:   storeNumber,
+		Updated: data.WeeklyAdLatestUpdatedDateTime,
+		SavedAt: now,
+		Deals:   data.Savings,
+	}); err != nil {
+		return watchJSON{}, configError(err)
+	}
+
+	report := watchJSON{
+		Store:     storeNumber,
+		Updated:   data.WeeklyAdLatestUpdatedDateTime,
+		CheckedAt: now,
+		New:       []display.DealJSON{},
+	}
+	if previous == nil {
+		return report, nil
+	}
+	report.Since = previous.SavedAt.Local().Format(diffDateLayout)
+	items := filter.Apply(data.Savings, filter.Options{
+		BOGO:           flagBogo,
+		Category:       flagCategory,
+		Department:     flagDepartment,
+		Query:          flagQuery,
+		Sort:           flagSort,
+		ExcludeExpired: !flagIncludeExpired,
+		MinPercentOff:  flagMinPercentOff,
+	})
+	report.New = addiff.Diff(previous.Deals, items).Added
+	if flagLimit > 0 && len(report.New) > flagLimit {
+		report.New = report.New[:flagLimit]
+	}
+	return report, nil
+}
+
+// emitWatch writes one report of a polling watch: an NDJSON line with
+// --json, text otherwise.
+func emitWatch(w io.Writer, report watchJSON) error {
+	if flagJSON {
+		return json.NewEncoder(w).Encode(report)
+	}
+	fmt.Fprintf(w, "[%s] ", report.CheckedAt.Format(time.DateTime))
+	printWatch(w, report)
+	return nil
+}
+
+func printWatch(w io.Writer, report watchJSON) {
+	switch {
+	case report.Since == "":
+		fmt.Fprintf(w, "No earlier ad saved for store #%s; new deals are reported from the next ad.\n", report.Store)
+	case len(report.New) == 0:
+		fmt.Fprintf(w, "No new deals at store #%s since the ad of %s.\n", report.Store, report.Since)
+	default:
+		fmt.Fprintf(w, "%d new deal(s) at store #%s since the ad of %s:\n", len(report.New), report.Store, report.Since)
+		for _, d := range report.New {
+			line := "- " + d.Title
+			if d.Savings != "" {
+				line += " (" + d.Savings + ")"
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/config"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestCheckWatch_ReportsDealsNewSinceThePreviousAd(t *testing.T) {
+	dataDir := t.TempDir()
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(resetCLIState)
+
+	ad := api.SavingsResponse{WeeklyAdLatestUpdatedDateTime: "a", Savings: []api.SavingItem{
+		{ID: "1", Title: strPtr("Coffee Beans")},
+		{ID: "2", Title: strPtr("Chips")},
+	}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(ad)
+	}))
+	defer srv.Close()
+	client := api.NewClientWithBaseURLs(srv.URL, "")
+	archive := history.Archive{Backend: &storage.Files{Dir: dataDir}}
+
+	report, err := checkWatch(context.Background(), client, archive, "1500")
+	require.NoError(t, err)
+	assert.Empty(t, report.Since, "nothing to compare the first ad with")
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: time.Now().AddDate(0, 0, -7), Deals: ad.Savings}))
+
+	ad = api.SavingsResponse{WeeklyAdLatestUpdatedDateTime: "b", Savings: []api.SavingItem{
+		{ID: "3", Title: strPtr("Coffee Beans")},
+		{ID: "4", Title: strPtr("Iced Coffee")},
+		{ID: "5", Title: strPtr("Salsa")},
+	}}
+	flagQuery = "coffee"
+	report, err = checkWatch(context.Background(), client, archive, "1425")
+	require.NoError(t, err)
+	assert.NotEmpty(t, report.Since)
+	assert.Equal(t, "b", report.Updated)
+	require.Len(t, report.New, 1)
+	assert.Equal(t, "Iced Coffee", report.New[0].Title)
+
+	// Checking the same ad again still compares with the ad before it.
+	report, err = checkWatch(context.Background(), client, archive, "1425")
+	require.NoError(t, err)
+	assert.Len(t, report.New, 1)
+
+	var out bytes.Buffer
+	printWatch(&out, report)
+	assert.Contains(t, out.String(), "1 new deal(s) at store #1425")
+	assert.Contains(t, out.String(), "- Iced Coffee\n")
+}
+
+func TestRunCLI_WatchRejectsShortInterval(t *testing.T) {
+	t.Setenv(config.EnvDataDir, t.TempDir())
+	t.Cleanup(resetCLIState)
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI([]string{"watch", "--store", "1425", "--interval", "5s"}, &stdout, &stderr)
+	assert.Equal(t, ExitInvalidArgs, code)
+	assert.Contains(t, stderr.String(), "--interval must be at least 1m0s")
+}
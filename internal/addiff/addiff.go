@@ -0,0 +1,217 @@
+// Package addiff compares two weekly ads deal by deal: which deals were
+// added, which were removed, and which fields of the rest changed.
+package addiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// Side identifies one of the two ads compared.
+type Side struct {
+	// Date is the day the ad was saved or fetched, as YYYY-MM-DD.
+	Date string `json:"date"`
+	// Updated is the ad's WeeklyAdLatestUpdatedDateTime.
+	Updated string `json:"updated,omitempty"`
+}
+
+// Result is the difference between two ads of one store.
+type Result struct {
+	Store     string             `json:"store"`
+	From      Side               `json:"from"`
+	To        Side               `json:"to"`
+	Added     []display.DealJSON `json:"added"`
+	Removed   []display.DealJSON `json:"removed"`
+	Changed   []Change           `json:"changed"`
+	Unchanged int                `json:"unchanged"`
+}
+
+// Change is a deal in both ads whose fields differ.
+type Change struct {
+	Title string `json:"title"`
+	// Deal is the deal as it is in the newer ad.
+	Deal   display.DealJSON `json:"deal"`
+	Fields []FieldChange    `json:"fields"`
+}
+
+// FieldChange is one changed field, named as in DealJSON.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// Diff compares the deals of two ads. Deals are matched by title, as
+// deal IDs change from week to week; validity dates are not compared,
+// since every deal carried into a new week has new ones.
+func Diff(from, to []api.SavingItem) Result {
+	r := Result{Added: []display.DealJSON{}, Removed: []display.DealJSON{}, Changed: []Change{}}
+
+	before := map[string][]api.SavingItem{}
+	for _, item := range from {
+		before[key(item)] = append(before[key(item)], item)
+	}
+	for _, item := range to {
+		k := key(item)
+		olds := before[k]
+		if len(olds) == 0 {
+			r.Added = append(r.Added, display.ToDealJSON(item))
+			continue
+		}
+		old := olds[0]
+		before[k] = olds[1:]
+
+		deal := display.ToDealJSON(item)
+		fields := changedFields(display.ToDealJSON(old), deal)
+		if len(fields) == 0 {
+			r.Unchanged++
+			continue
+		}
+		r.Changed = append(r.Changed, Change{Title: deal.Title, Deal: deal, Fields: fields})
+	}
+	for _, item := range from {
+		k := key(item)
+		if len(before[k]) > 0 {
+			r.Removed = append(r.Removed, display.ToDealJSON(before[k][0]))
+			before[k] = before[k][1:]
+		}
+	}
+	return r
+}
+
+// MarkNew sets IsNew on the deals of the current ad: true for those the
+// previous ad did not have. Deals are matched by title as in Diff, so new
+// deal IDs and a reordered ad do not make carried-over deals look new.
+func MarkNew(previous, current []api.SavingItem) {
+	before := map[string]int{}
+	for _, item := range previous {
+		before[key(item)]++
+	}
+	for i := range current {
+		k := key(current[i])
+		isNew := before[k] == 0
+		if !isNew {
+			before[k]--
+		}
+		current[i].IsNew = &isNew
+	}
+}
+
+func key(item api.SavingItem) string {
+	return strings.ToLower(filter.Title(item))
+}
+
+func changedFields(a, b display.DealJSON) []FieldChange {
+	var fields []FieldChange
+	text := func(field, x, y string) {
+		if x != y {
+			fields = append(fields, FieldChange{Field: field, From: x, To: y})
+		}
+	}
+	text("savings", a.Savings, b.Savings)
+	text("description", a.Description, b.Description)
+	text("department", a.Department, b.Department)
+	text("brand", a.Brand, b.Brand)
+	text("additionalDealInfo", a.DealInfo, b.DealInfo)
+	if strings.Join(a.Categories, ",") != strings.Join(b.Categories, ",") {
+		fields = append(fields, FieldChange{Field: "categories", From: a.Categories, To: b.Categories})
+	}
+	if a.IsBogo != b.IsBogo {
+		fields = append(fields, FieldChange{Field: "isBogo", From: a.IsBogo, To: b.IsBogo})
+	}
+	text("imageUrl", a.ImageURL, b.ImageURL)
+	return fields
+}
+
+// Empty reports whether the two ads have the same deals.
+func (r Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// Write renders the result as text or Markdown.
+func Write(w io.Writer, r Result, markdown bool) {
+	heading := func(text string) {
+		if markdown {
+			fmt.Fprintf(w, "\n## %s\n\n", text)
+		} else {
+			fmt.Fprintf(w, "\n%s\n%s\n", text, strings.Repeat("-", len(text)))
+		}
+	}
+	bullet := func(format string, args ...any) {
+		fmt.Fprintf(w, "- "+format+"\n", args...)
+	}
+
+	title := fmt.Sprintf("Ad changes for Publix #%s: %s to %s", r.Store, r.From.Date, r.To.Date)
+	if markdown {
+		fmt.Fprintf(w, "# %s\n\n", title)
+	} else {
+		fmt.Fprintln(w, title)
+	}
+	fmt.Fprintf(w, "%d added, %d removed, %d changed, %d unchanged.\n",
+		len(r.Added), len(r.Removed), len(r.Changed), r.Unchanged)
+	if r.Empty() {
+		return
+	}
+
+	if len(r.Added) > 0 {
+		heading("Added")
+		for _, d := range r.Added {
+			bullet("%s", dealLine(d, markdown))
+		}
+	}
+	if len(r.Removed) > 0 {
+		heading("Removed")
+		for _, d := range r.Removed {
+			bullet("%s", dealLine(d, markdown))
+		}
+	}
+	if len(r.Changed) > 0 {
+		heading("Changed")
+		for _, c := range r.Changed {
+			parts := make([]string, 0, len(c.Fields))
+			for _, f := range c.Fields {
+				parts = append(parts, describe(f))
+			}
+			bullet("%s: %s", emphasize(c.Title, markdown), strings.Join(parts, "; "))
+		}
+	}
+}
+
+// describe renders a field change, e.g. `savings "$3.99" → "$2.99"` or
+// "now BOGO".
+func describe(f FieldChange) string {
+	switch from := f.From.(type) {
+	case bool:
+		if f.Field == "isBogo" {
+			if f.To == true {
+				return "now BOGO"
+			}
+			return "no longer BOGO"
+		}
+		return fmt.Sprintf("%s %t → %t", f.Field, from, f.To)
+	case []string:
+		to, _ := f.To.([]string)
+		return fmt.Sprintf("%s %s → %s", f.Field, strings.Join(from, ", "), strings.Join(to, ", "))
+	}
+	return fmt.Sprintf("%s %q → %q", f.Field, f.From, f.To)
+}
+
+func dealLine(d display.DealJSON, markdown bool) string {
+	line := emphasize(d.Title, markdown)
+	if d.Savings != "" {
+		line += " (" + d.Savings + ")"
+	}
+	return line
+}
+
+func emphasize(text string, markdown bool) string {
+	if markdown {
+		return "**" + text + "**"
+	}
+	return text
+}
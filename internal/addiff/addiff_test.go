@@ -0,0 +1,85 @@
+package addiff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/addiff"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func ptr(s string) *string { return &s }
+
+func deal(id, title, savings string, categories ...string) api.SavingItem {
+	return api.SavingItem{ID: id, Title: ptr(title), Savings: ptr(savings), Categories: categories, StartFormatted: id}
+}
+
+func TestDiff(t *testing.T) {
+	from := []api.SavingItem{
+		deal("1", "Nutella", "$3.99"),
+		deal("2", "Chips", "$2.50"),
+		deal("3", "Coffee", "$7.99"),
+		deal("4", "Soda", "$5.00"),
+	}
+	to := []api.SavingItem{
+		deal("11", "nutella", "$3.99"),
+		deal("13", "Coffee", "$6.99"),
+		deal("14", "Soda", "Buy 1 Get 1 FREE", "bogo"),
+		deal("15", "Bread/Rolls", "$1.99"),
+	}
+
+	r := addiff.Diff(from, to)
+	assert.Equal(t, 1, r.Unchanged, "deals match by title, ignoring case, IDs, and dates")
+	require.Len(t, r.Added, 1)
+	assert.Equal(t, "Bread/Rolls", r.Added[0].Title)
+	require.Len(t, r.Removed, 1)
+	assert.Equal(t, "Chips", r.Removed[0].Title)
+	require.Len(t, r.Changed, 2)
+	assert.Equal(t, []addiff.FieldChange{{Field: "savings", From: "$7.99", To: "$6.99"}}, r.Changed[0].Fields)
+	assert.Equal(t, "isBogo", r.Changed[1].Fields[2].Field)
+
+	var text bytes.Buffer
+	r.Store, r.From.Date, r.To.Date = "1425", "2025-02-11", "2025-02-18"
+	addiff.Write(&text, r, false)
+	assert.Contains(t, text.String(), "Ad changes for Publix #1425: 2025-02-11 to 2025-02-18")
+	assert.Contains(t, text.String(), "1 added, 1 removed, 2 changed, 1 unchanged.")
+	assert.Contains(t, text.String(), `- Coffee: savings "$7.99" → "$6.99"`)
+	assert.Contains(t, text.String(), "now BOGO")
+
+	var md bytes.Buffer
+	addiff.Write(&md, r, true)
+	assert.Contains(t, md.String(), "## Added\n\n- **Bread/Rolls** ($1.99)")
+
+	ops := r.Patch()
+	require.Len(t, ops, 6)
+	assert.Equal(t, "remove", ops[0].Op)
+	assert.Equal(t, "/deals/Bread~1Rolls", ops[1].Path)
+	assert.Equal(t, addiff.PatchOp{Op: "replace", Path: "/deals/Coffee/savings", Old: "$7.99", Value: "$6.99"}, ops[2])
+}
+
+func TestDiff_Identical(t *testing.T) {
+	ad := []api.SavingItem{deal("1", "Nutella", "$3.99"), deal("2", "Nutella", "$4.99")}
+	r := addiff.Diff(ad, ad)
+	assert.True(t, r.Empty())
+	assert.Equal(t, 2, r.Unchanged)
+	assert.Empty(t, r.Patch())
+}
+
+func TestMarkNew(t *testing.T) {
+	previous := []api.SavingItem{deal("1", "Nutella", "$3.99"), deal("2", "Chips", "$2.50")}
+	current := []api.SavingItem{
+		deal("12", "Chips", "$1.99"),
+		deal("11", "NUTELLA", "$3.99"),
+		deal("13", "Chips", "$1.99"),
+	}
+
+	addiff.MarkNew(previous, current)
+	var isNew []bool
+	for _, item := range current {
+		require.NotNil(t, item.IsNew)
+		isNew = append(isNew, *item.IsNew)
+	}
+	assert.Equal(t, []bool{false, false, true}, isNew, "each earlier deal accounts for one deal of the same title")
+}
@@ -0,0 +1,40 @@
+package addiff
+
+import (
+	"strings"
+)
+
+// PatchOp is one operation of a Result written in the style of a JSON
+// Patch (RFC 6902): paths are JSON Pointers to /deals/<title> and its
+// fields. Unlike a JSON Patch, "remove" and "replace" carry the old value,
+// so the patch also reads as a changelog.
+type PatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Old   any    `json:"old,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Patch returns the result as patch operations: removals, then additions,
+// then field replacements.
+func (r Result) Patch() []PatchOp {
+	ops := []PatchOp{}
+	for _, d := range r.Removed {
+		ops = append(ops, PatchOp{Op: "remove", Path: dealPath(d.Title), Old: d})
+	}
+	for _, d := range r.Added {
+		ops = append(ops, PatchOp{Op: "add", Path: dealPath(d.Title), Value: d})
+	}
+	for _, c := range r.Changed {
+		for _, f := range c.Fields {
+			ops = append(ops, PatchOp{Op: "replace", Path: dealPath(c.Title) + "/" + f.Field, Old: f.From, Value: f.To})
+		}
+	}
+	return ops
+}
+
+// dealPath is the JSON Pointer of a deal, escaping "~" and "/" in its
+// title as RFC 6901 requires.
+func dealPath(title string) string {
+	return "/deals/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(title)
+}
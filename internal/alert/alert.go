@@ -0,0 +1,267 @@
+// Package alert evaluates watch rules against a weekly ad and delivers the
+// matches to notification targets such as webhooks and Telegram.
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/compare"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// Rule matches deals that meet all of its criteria: any of the keywords in
+// the title or description, case-insensitively, and the same category,
+// department, query, BOGO, and percent-off filters as deal listings, plus a
+// price cap.
+// A rule needs at least one criterion. Stores lists the store numbers the
+// rule watches; a rule without stores watches the store given on the
+// command line.
+type Rule struct {
+	Name       string   `json:"name"`
+	Keywords   []string `json:"keywords,omitempty"`
+	Stores     []string `json:"stores,omitempty"`
+	Category   string   `json:"category,omitempty"`
+	Department string   `json:"department,omitempty"`
+	Query      string   `json:"query,omitempty"`
+	BOGO       bool     `json:"bogo,omitempty"`
+	// MaxPrice is the most one item may cost on the deal, as
+	// filter.Savings.EffectivePrice reads it. Deals whose price cannot be
+	// read from their savings text never match a price cap.
+	MaxPrice float64 `json:"maxPrice,omitempty"`
+	// MinPercentOff is the least discount a deal may have, as
+	// filter.Options.MinPercentOff reads it.
+	MinPercentOff float64 `json:"minPercentOff,omitempty"`
+	// Sort and Limit order and cap the deals the rule reports per store.
+	Sort  string `json:"sort,omitempty"`
+	Limit int    `json:"limit,omitempty"`
+	// IncludeExpired keeps deals whose end date has passed.
+	IncludeExpired bool `json:"includeExpired,omitempty"`
+}
+
+// Validate reports a rule without a name or criteria, or with an invalid
+// sort, limit, price cap, or percent off.
+func (r Rule) Validate() error {
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		return errors.New("an alert rule needs a name")
+	case !r.hasCriteria():
+		return fmt.Errorf("rule %q needs keywords, a category, a department, a query, bogo, a max price, or a min percent off", r.Name)
+	case r.MaxPrice < 0:
+		return fmt.Errorf("rule %q: max price must not be negative", r.Name)
+	case r.MinPercentOff < 0 || r.MinPercentOff > 100:
+		return fmt.Errorf("rule %q: min percent off must be between 0 and 100", r.Name)
+	case r.Limit < 0:
+		return fmt.Errorf("rule %q: limit must not be negative", r.Name)
+	case !filter.ValidSort(r.Sort):
+		return fmt.Errorf("rule %q: sort %q is not relevance, savings, or ending", r.Name, r.Sort)
+	}
+	return nil
+}
+
+func (r Rule) hasCriteria() bool {
+	for _, kw := range r.Keywords {
+		if strings.TrimSpace(kw) != "" {
+			return true
+		}
+	}
+	return r.Category != "" || r.Department != "" || r.Query != "" || r.BOGO || r.MaxPrice > 0 || r.MinPercentOff > 0
+}
+
+// Describe summarizes the rule's criteria for listings, e.g.
+// "coffee, espresso; bogo only; max $3.00; at #1425".
+func (r Rule) Describe() string {
+	var parts []string
+	if len(r.Keywords) > 0 {
+		parts = append(parts, strings.Join(r.Keywords, ", "))
+	}
+	if r.Query != "" {
+		parts = append(parts, "query "+r.Query)
+	}
+	if r.Category != "" {
+		parts = append(parts, "category "+r.Category)
+	}
+	if r.Department != "" {
+		parts = append(parts, "department "+r.Department)
+	}
+	if r.BOGO {
+		parts = append(parts, "bogo only")
+	}
+	if r.MaxPrice > 0 {
+		parts = append(parts, fmt.Sprintf("max $%.2f", r.MaxPrice))
+	}
+	if r.MinPercentOff > 0 {
+		parts = append(parts, fmt.Sprintf("%g%%+ off", r.MinPercentOff))
+	}
+	if r.Sort != "" {
+		parts = append(parts, "sorted by "+r.Sort)
+	}
+	if r.Limit > 0 {
+		parts = append(parts, fmt.Sprintf("top %d", r.Limit))
+	}
+	if r.IncludeExpired {
+		parts = append(parts, "including expired")
+	}
+	if len(r.Stores) > 0 {
+		parts = append(parts, "at #"+strings.Join(r.Stores, ", #"))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Match is the deals one rule matched.
+type Match struct {
+	Rule  string             `json:"rule"`
+	Deals []display.DealJSON `json:"deals"`
+
+	// keys identifies each deal for the notified log.
+	keys []string
+}
+
+// Report is the result of one alert run. It is the payload sent to
+// webhooks and the data passed to message templates.
+type Report struct {
+	Store       string    `json:"store"`
+	Updated     string    `json:"updated,omitempty"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Matches     []Match   `json:"matches"`
+	// Suppressed counts matched deals left out because they were already
+	// sent this ad week.
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// NewMatch reports items as the matches of the named rule, for callers
+// that select deals themselves, such as price tracking.
+func NewMatch(rule string, items []api.SavingItem) Match {
+	m := Match{Rule: rule}
+	for _, item := range items {
+		m.Deals = append(m.Deals, display.ToDealJSON(item))
+		m.keys = append(m.keys, compare.DealKey(item))
+	}
+	return m
+}
+
+// DealCount is the number of matched deals across all rules.
+func (r Report) DealCount() int {
+	n := 0
+	for _, m := range r.Matches {
+		n += len(m.Deals)
+	}
+	return n
+}
+
+// Notifier delivers a report to one destination.
+type Notifier interface {
+	// Name identifies the destination in output and rate-limit state. It
+	// must not contain secrets.
+	Name() string
+	Notify(ctx context.Context, r Report) error
+}
+
+// Delivery is the outcome of sending a report to one notifier.
+type Delivery struct {
+	Target  string `json:"target"`
+	Store   string `json:"store"`
+	Sent    bool   `json:"sent"`
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Evaluate runs every rule against the ad. Rules without matches are
+// omitted; a deal matched by several rules appears under each.
+func Evaluate(rules []Rule, items []api.SavingItem) []Match {
+	var matches []Match
+	for _, rule := range rules {
+		if deals, keys := matchDeals(rule, items); len(deals) > 0 {
+			matches = append(matches, Match{Rule: rule.Name, Deals: deals, keys: keys})
+		}
+	}
+	return matches
+}
+
+// matchDeals returns the deals the rule reports, and their keys: those it
+// matches that have not expired, ordered and capped by its Sort and Limit.
+func matchDeals(rule Rule, items []api.SavingItem) ([]display.DealJSON, []string) {
+	var matched []api.SavingItem
+	for _, item := range items {
+		if rule.Matches(item) {
+			matched = append(matched, item)
+		}
+	}
+	matched = filter.Apply(matched, filter.Options{
+		Sort:           rule.Sort,
+		Limit:          rule.Limit,
+		ExcludeExpired: !rule.IncludeExpired,
+	})
+	var deals []display.DealJSON
+	var keys []string
+	for _, item := range matched {
+		deals = append(deals, display.ToDealJSON(item))
+		keys = append(keys, compare.DealKey(item))
+	}
+	return deals, keys
+}
+
+// Matches reports whether the deal meets every criterion of the rule. It
+// does not check expiry, so it also applies to past ads.
+func (r Rule) Matches(item api.SavingItem) bool {
+	if !r.matchesKeywords(item) {
+		return false
+	}
+	if r.Category != "" || r.Department != "" || r.Query != "" || r.BOGO || r.MinPercentOff > 0 {
+		opts := filter.Options{BOGO: r.BOGO, Category: r.Category, Department: r.Department, Query: r.Query, MinPercentOff: r.MinPercentOff}
+		if len(filter.Apply([]api.SavingItem{item}, opts)) == 0 {
+			return false
+		}
+	}
+	if r.MaxPrice > 0 {
+		price := filter.ParseSavings(item).EffectivePrice()
+		if price <= 0 || price > r.MaxPrice {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesKeywords reports whether the deal's title or description contains
+// any of the rule's keywords. A rule without keywords matches every deal.
+func (r Rule) matchesKeywords(item api.SavingItem) bool {
+	title := strings.ToLower(filter.CleanText(filter.Deref(item.Title)))
+	desc := strings.ToLower(filter.CleanText(filter.Deref(item.Description)))
+	checked := false
+	for _, kw := range r.Keywords {
+		kw = strings.ToLower(strings.TrimSpace(kw))
+		if kw == "" {
+			continue
+		}
+		checked = true
+		if strings.Contains(title, kw) || strings.Contains(desc, kw) {
+			return true
+		}
+	}
+	return !checked
+}
+
+// Dispatch sends the report to every notifier and records each outcome.
+// One failing destination does not stop the others.
+func Dispatch(ctx context.Context, r Report, notifiers []Notifier) []Delivery {
+	deliveries := make([]Delivery, 0, len(notifiers))
+	for _, n := range notifiers {
+		d := Delivery{Target: n.Name(), Store: r.Store}
+		err := n.Notify(ctx, r)
+		switch {
+		case err == nil:
+			d.Sent = true
+		case isRateLimited(err):
+			d.Skipped = err.Error()
+		default:
+			d.Error = err.Error()
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries
+}
@@ -0,0 +1,333 @@
+package alert_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/alert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func ptr(s string) *string { return &s }
+
+func sampleReport() alert.Report {
+	items := []api.SavingItem{
+		{ID: "1", Title: ptr("Publix Coffee"), Savings: ptr("Buy 1 Get 1 FREE"), EndFormatted: "10/20"},
+		{ID: "2", Title: ptr("Ground Beef"), Description: ptr("85% lean")},
+		{ID: "3", Title: ptr("Bananas")},
+	}
+	rules := []alert.Rule{
+		{Name: "coffee", Keywords: []string{"COFFEE", "espresso"}},
+		{Name: "beef", Keywords: []string{"lean"}},
+		{Name: "nothing", Keywords: []string{"caviar"}},
+	}
+	return alert.Report{Store: "1425", Matches: alert.Evaluate(rules, items)}
+}
+
+func TestEvaluate(t *testing.T) {
+	report := sampleReport()
+
+	require.Len(t, report.Matches, 2)
+	assert.Equal(t, "coffee", report.Matches[0].Rule)
+	assert.Equal(t, "Publix Coffee", report.Matches[0].Deals[0].Title)
+	assert.Equal(t, "beef", report.Matches[1].Rule, "keywords match descriptions too")
+	assert.Equal(t, 2, report.DealCount())
+}
+
+func TestRule_FilterCriteria(t *testing.T) {
+	items := []api.SavingItem{
+		{ID: "1", Title: ptr("Strawberries"), Department: ptr("Produce"), Categories: []string{"produce", "bogo"}, Savings: ptr("$4.00")},
+		{ID: "2", Title: ptr("Blueberries"), Department: ptr("Produce"), Categories: []string{"produce", "bogo"}, Savings: ptr("$9.00")},
+		{ID: "3", Title: ptr("Raspberries"), Department: ptr("Produce"), Categories: []string{"produce"}, Savings: ptr("$2.00")},
+		{ID: "4", Title: ptr("Berry Yogurt"), Department: ptr("Dairy"), Categories: []string{"bogo"}, Savings: ptr("$3.00")},
+		{ID: "5", Title: ptr("Mystery Berries"), Categories: []string{"produce", "bogo"}},
+	}
+	rule := alert.Rule{Name: "berries", Keywords: []string{"berr"}, Category: "produce", BOGO: true, MaxPrice: 2.5}
+
+	matches := alert.Evaluate([]alert.Rule{rule}, items)
+
+	require.Len(t, matches, 1)
+	require.Len(t, matches[0].Deals, 1, "BOGO halves $4.00; $9.00 is over the cap; unpriced deals never match a cap")
+	assert.Equal(t, "Strawberries", matches[0].Deals[0].Title)
+
+	limited := alert.Rule{Name: "produce", Department: "produce", Limit: 2}
+	matches = alert.Evaluate([]alert.Rule{limited}, items)
+	require.Len(t, matches, 1)
+	assert.Len(t, matches[0].Deals, 2)
+}
+
+func TestRule_Validate(t *testing.T) {
+	assert.NoError(t, alert.Rule{Name: "bogo", BOGO: true}.Validate())
+	assert.ErrorContains(t, alert.Rule{Name: "none", Keywords: []string{" "}}.Validate(), "needs keywords")
+	assert.ErrorContains(t, alert.Rule{Name: "x", BOGO: true, Sort: "price"}.Validate(), "sort")
+	assert.ErrorContains(t, alert.Rule{Keywords: []string{"x"}}.Validate(), "name")
+
+	w := &alert.Watchlist{Rules: []alert.Rule{{Name: "a", BOGO: true}, {Name: "A", BOGO: true}}}
+	assert.ErrorContains(t, w.Validate(), "more than one rule")
+}
+
+func TestImportCSV(t *testing.T) {
+	input := "Name,Keywords,BOGO,Max Price,Stores\n" +
+		"coffee,coffee;espresso,,,#1425;1500\n" +
+		"deals,,yes,$4,\n"
+	rules, err := alert.ImportCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, []alert.Rule{
+		{Name: "coffee", Keywords: []string{"coffee", "espresso"}, Stores: []string{"1425", "1500"}},
+		{Name: "deals", BOGO: true, MaxPrice: 4},
+	}, rules)
+
+	_, err = alert.ImportCSV(strings.NewReader("name,keywords\ncoffee,coffee\nCoffee,espresso\n"))
+	assert.ErrorContains(t, err, `line 3: rule "Coffee" is already on line 2`)
+	_, err = alert.ImportCSV(strings.NewReader("name,category\nempty,\n"))
+	assert.ErrorContains(t, err, "line 2: rule \"empty\" needs keywords")
+	_, err = alert.ImportCSV(strings.NewReader("name,bogo,stores\nx,yes,Lakeland\n"))
+	assert.ErrorContains(t, err, `line 2: store "Lakeland" is not a store number`)
+}
+
+func TestRun_FetchesEachStoreOnceAndGroupsMatches(t *testing.T) {
+	ads := map[string][]api.SavingItem{
+		"1425": {{ID: "1", Title: ptr("Publix Coffee")}, {ID: "2", Title: ptr("Ground Beef")}},
+		"1500": {{ID: "3", Title: ptr("Espresso Beans")}},
+	}
+	var mu sync.Mutex
+	fetches := map[string]int{}
+	fetch := func(_ context.Context, store string) (*api.SavingsResponse, error) {
+		mu.Lock()
+		fetches[store]++
+		mu.Unlock()
+		if store == "1600" {
+			return nil, errors.New("HTTP 503")
+		}
+		return &api.SavingsResponse{Savings: ads[store]}, nil
+	}
+	rules := []alert.Rule{
+		{Name: "coffee", Keywords: []string{"coffee", "espresso"}, Stores: []string{"#01425", "1500"}},
+		{Name: "beef", Keywords: []string{"beef"}},
+		{Name: "bananas", Keywords: []string{"banana"}, Stores: []string{"1500", "1600"}},
+	}
+
+	run := alert.Run(context.Background(), rules, "1425", fetch, 2)
+
+	assert.Equal(t, map[string]int{"1425": 1, "1500": 1, "1600": 1}, fetches)
+	require.Len(t, run.Stores, 2)
+	assert.Equal(t, "1425", run.Stores[0].Store)
+	require.Len(t, run.Stores[0].Matches, 2)
+	assert.Equal(t, "coffee", run.Stores[0].Matches[0].Rule)
+	assert.Equal(t, "beef", run.Stores[0].Matches[1].Rule)
+	assert.Equal(t, "1500", run.Stores[1].Store)
+	require.Len(t, run.Stores[1].Matches, 1, "beef does not watch 1500")
+	assert.Equal(t, 3, run.DealCount())
+
+	require.Len(t, run.Rules, 3)
+	assert.Equal(t, []string{"1425", "1500"}, run.Rules[0].Stores)
+	require.Len(t, run.Rules[0].Matches, 2)
+	assert.Equal(t, "Espresso Beans", run.Rules[0].Matches[1].Deals[0].Title)
+	assert.Equal(t, []string{"1425"}, run.Rules[1].Stores)
+	assert.Empty(t, run.Rules[2].Matches)
+
+	require.Len(t, run.Failed, 1)
+	assert.Equal(t, "1600", run.Failed[0].Store)
+}
+
+func TestNotifiedLog_SuppressesDealsSentThisWeek(t *testing.T) {
+	items := []api.SavingItem{
+		{ID: "1", Title: ptr("Publix Coffee")},
+		{ID: "2", Title: ptr("Espresso Beans")},
+	}
+	fetch := func(context.Context, string) (*api.SavingsResponse, error) {
+		return &api.SavingsResponse{Savings: items}, nil
+	}
+	rules := []alert.Rule{{Name: "coffee", Keywords: []string{"coffee", "espresso"}}}
+	b := &storage.Files{Dir: t.TempDir()}
+	now := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+
+	log, err := alert.LoadNotifiedLog(b, alert.NotifiedFile)
+	require.NoError(t, err)
+	run := alert.Run(context.Background(), rules, "1425", fetch, 1)
+	log.Suppress(&run, "2026-10-14")
+	require.Equal(t, 2, run.DealCount())
+	log.Record(run.Stores[0], "2026-10-14", now)
+	require.NoError(t, log.Save(b, alert.NotifiedFile, now))
+
+	log, err = alert.LoadNotifiedLog(b, alert.NotifiedFile)
+	require.NoError(t, err)
+	require.Len(t, log.Entries, 2)
+	assert.Equal(t, "id:1", log.Entries[0].Key)
+
+	items = append(items, api.SavingItem{ID: "3", Title: ptr("Iced Coffee")})
+	run = alert.Run(context.Background(), rules, "1425", fetch, 1)
+	log.Suppress(&run, "2026-10-14")
+	assert.Equal(t, 2, run.Suppressed)
+	assert.Equal(t, 2, run.Stores[0].Suppressed)
+	require.Len(t, run.Stores[0].Matches[0].Deals, 1)
+	assert.Equal(t, "Iced Coffee", run.Stores[0].Matches[0].Deals[0].Title)
+	require.Len(t, run.Rules[0].Matches[0].Deals, 1, "the per-rule view is filtered too")
+
+	run = alert.Run(context.Background(), rules, "1425", fetch, 1)
+	log.Suppress(&run, "2026-10-21")
+	assert.Zero(t, run.Suppressed, "a new ad week sends everything again")
+
+	require.NoError(t, log.Save(b, alert.NotifiedFile, now.Add(30*24*time.Hour)))
+	log, err = alert.LoadNotifiedLog(b, alert.NotifiedFile)
+	require.NoError(t, err)
+	assert.Empty(t, log.Entries, "old entries are dropped")
+}
+
+func TestNeedsDefaultStore(t *testing.T) {
+	assert.False(t, alert.NeedsDefaultStore([]alert.Rule{{Name: "a", Stores: []string{"1425"}}}))
+	assert.True(t, alert.NeedsDefaultStore([]alert.Rule{{Name: "a", Stores: []string{"1425"}}, {Name: "b", Stores: []string{" "}}}))
+}
+
+type telegramStub struct {
+	*httptest.Server
+	messages []map[string]any
+	paths    []string
+}
+
+func newTelegramStub(t *testing.T) *telegramStub {
+	t.Helper()
+	stub := &telegramStub{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]any
+		json.NewDecoder(r.Body).Decode(&msg)
+		stub.messages = append(stub.messages, msg)
+		stub.paths = append(stub.paths, r.URL.Path)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func TestTelegram_SendsRenderedTemplate(t *testing.T) {
+	stub := newTelegramStub(t)
+	tg, err := alert.NewTelegram("123:ABC", "42", "")
+	require.NoError(t, err)
+	tg.APIBase = stub.URL
+
+	require.NoError(t, tg.Notify(context.Background(), sampleReport()))
+	require.Len(t, stub.messages, 1)
+	assert.Equal(t, "/bot123:ABC/sendMessage", stub.paths[0])
+	assert.Equal(t, "42", stub.messages[0]["chat_id"])
+	text := stub.messages[0]["text"].(string)
+	assert.Contains(t, text, "2 watched item(s) on sale at Publix #1425")
+	assert.Contains(t, text, "• Publix Coffee — Buy 1 Get 1 FREE (through 10/20)")
+}
+
+func TestTelegram_CustomTemplateAndErrors(t *testing.T) {
+	stub := newTelegramStub(t)
+	tg, err := alert.NewTelegram("123:ABC", "42", "{{range .Matches}}{{.Rule}} {{end}}")
+	require.NoError(t, err)
+	tg.APIBase = stub.URL
+	require.NoError(t, tg.Notify(context.Background(), sampleReport()))
+	assert.Equal(t, "coffee beef", stub.messages[0]["text"])
+
+	_, err = alert.NewTelegram("", "42", "")
+	assert.Error(t, err)
+	_, err = alert.NewTelegram("t", "42", "{{.Nope")
+	assert.ErrorContains(t, err, "template")
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+	tg.APIBase = failing.URL
+	err = tg.Notify(context.Background(), sampleReport())
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "123:ABC", "bot token must not leak into errors")
+}
+
+func TestTelegram_SplitsLongMessages(t *testing.T) {
+	stub := newTelegramStub(t)
+	tg, err := alert.NewTelegram("t", "42", strings.Repeat("line of text\n", 400))
+	require.NoError(t, err)
+	tg.APIBase = stub.URL
+
+	require.NoError(t, tg.Notify(context.Background(), sampleReport()))
+	require.Len(t, stub.messages, 2)
+	for _, msg := range stub.messages {
+		assert.LessOrEqual(t, len([]rune(msg["text"].(string))), 4096)
+	}
+}
+
+func TestRateLimited_SkipsWithinInterval(t *testing.T) {
+	stub := newTelegramStub(t)
+	tg, err := alert.NewTelegram("t", "42", "")
+	require.NoError(t, err)
+	tg.APIBase = stub.URL
+
+	now := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	limited := &alert.RateLimited{
+		Notifier: tg,
+		Interval: time.Hour,
+		Store:    &storage.Files{Dir: t.TempDir()},
+		StateKey: "state.json",
+		Now:      func() time.Time { return now },
+	}
+
+	deliveries := alert.Dispatch(context.Background(), sampleReport(), []alert.Notifier{limited})
+	assert.True(t, deliveries[0].Sent)
+
+	now = now.Add(30 * time.Minute)
+	deliveries = alert.Dispatch(context.Background(), sampleReport(), []alert.Notifier{limited})
+	assert.False(t, deliveries[0].Sent)
+	assert.Contains(t, deliveries[0].Skipped, "rate limited")
+	assert.Empty(t, deliveries[0].Error)
+
+	other := sampleReport()
+	other.Store = "1500"
+	deliveries = alert.Dispatch(context.Background(), other, []alert.Notifier{limited})
+	assert.True(t, deliveries[0].Sent, "the limit is kept per store")
+	assert.Equal(t, "1500", deliveries[0].Store)
+
+	now = now.Add(time.Hour)
+	deliveries = alert.Dispatch(context.Background(), sampleReport(), []alert.Notifier{limited})
+	assert.True(t, deliveries[0].Sent)
+	assert.Len(t, stub.messages, 3)
+}
+
+func TestWebhook_PostsReportJSON(t *testing.T) {
+	var got alert.Report
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	hook := &alert.Webhook{URL: srv.URL, Index: 1}
+	deliveries := alert.Dispatch(context.Background(), sampleReport(), []alert.Notifier{hook})
+	require.True(t, deliveries[0].Sent)
+	assert.Equal(t, "webhook #1", deliveries[0].Target)
+	assert.Equal(t, "1425", got.Store)
+	assert.Len(t, got.Matches, 2)
+}
+
+func TestWatchlist_SetRemovePersist(t *testing.T) {
+	store := &storage.Files{Dir: t.TempDir()}
+	w, err := alert.LoadWatchlist(store, alert.WatchlistFile)
+	require.NoError(t, err)
+	assert.Empty(t, w.Rules)
+
+	assert.False(t, w.Set(alert.Rule{Name: "coffee", Keywords: []string{"coffee"}}))
+	assert.True(t, w.Set(alert.Rule{Name: "Coffee", Keywords: []string{"espresso"}}), "same name replaces")
+	w.Set(alert.Rule{Name: "beef", Keywords: []string{"ground beef"}})
+	require.NoError(t, w.Save(store, alert.WatchlistFile))
+
+	loaded, err := alert.LoadWatchlist(store, alert.WatchlistFile)
+	require.NoError(t, err)
+	require.Len(t, loaded.Rules, 2)
+	assert.Equal(t, []string{"espresso"}, loaded.Rules[0].Keywords)
+	assert.True(t, loaded.Remove("BEEF"))
+	assert.False(t, loaded.Remove("beef"))
+	assert.Len(t, loaded.Rules, 1)
+}
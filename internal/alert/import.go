@@ -0,0 +1,69 @@
+package alert
+
+import (
+	"io"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/csvimport"
+)
+
+// ImportColumns are the columns ImportCSV reads, named after the rule fields
+// in config.yaml; only name is required.
+var ImportColumns = []string{
+	"name", "keywords", "category", "department", "query", "bogo",
+	"max_price", "min_percent_off", "stores", "sort", "limit", "include_expired",
+}
+
+// ImportCSV reads rules from CSV with a header row naming ImportColumns.
+// keywords and stores hold several values separated by semicolons or
+// commas. Every rule is validated, and names must be unique in the file.
+func ImportCSV(r io.Reader) ([]Rule, error) {
+	rows, err := csvimport.Read(r, ImportColumns, "name")
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]int{}
+	rules := make([]Rule, 0, len(rows))
+	for _, row := range rows {
+		rule := Rule{
+			Name:       row.Get("name"),
+			Keywords:   csvimport.List(row, "keywords"),
+			Category:   row.Get("category"),
+			Department: row.Get("department"),
+			Query:      row.Get("query"),
+			Sort:       row.Get("sort"),
+		}
+		if rule.BOGO, err = csvimport.Bool(row, "bogo"); err != nil {
+			return nil, err
+		}
+		if rule.IncludeExpired, err = csvimport.Bool(row, "include_expired"); err != nil {
+			return nil, err
+		}
+		if rule.MaxPrice, err = csvimport.Float(row, "max_price"); err != nil {
+			return nil, err
+		}
+		if rule.MinPercentOff, err = csvimport.Float(row, "min_percent_off"); err != nil {
+			return nil, err
+		}
+		if rule.Limit, err = csvimport.Int(row, "limit"); err != nil {
+			return nil, err
+		}
+		for _, s := range strings.Fields(strings.NewReplacer(";", " ", ",", " ").Replace(row.Get("stores"))) {
+			s = strings.TrimPrefix(s, "#")
+			if strings.Trim(s, "0123456789") != "" {
+				return nil, row.Errorf("store %q is not a store number", s)
+			}
+			rule.Stores = append(rule.Stores, s)
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, row.Errorf("%v", err)
+		}
+		key := strings.ToLower(rule.Name)
+		if first, ok := seen[key]; ok {
+			return nil, row.Errorf("rule %q is already on line %d", rule.Name, first)
+		}
+		seen[key] = row.Line
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,149 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// NotifiedFile is the notified log's file name inside the data directory.
+const NotifiedFile = "alert-notified.json"
+
+// notifiedKeep is how long entries stay in the log for `alert history`,
+// well past the week they suppress duplicates in.
+const notifiedKeep = 28 * 24 * time.Hour
+
+// Notified is one deal an alert run sent for one rule and store.
+type Notified struct {
+	Store string `json:"store"`
+	Rule  string `json:"rule"`
+	// Key identifies the deal within the ad, as compare.DealKey does.
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	// Week is the first day of the ad week the deal was sent in, as
+	// YYYY-MM-DD.
+	Week       string    `json:"week"`
+	NotifiedAt time.Time `json:"notifiedAt"`
+}
+
+// NotifiedLog records the deals alert runs have sent, so that a deal is
+// sent once per rule, store, and ad week instead of on every cron run.
+type NotifiedLog struct {
+	Entries []Notified `json:"entries"`
+}
+
+// LoadNotifiedLog reads the log stored under key. A missing log is empty.
+func LoadNotifiedLog(b storage.Backend, key string) (*NotifiedLog, error) {
+	obj, err := b.Get(key)
+	if errors.Is(err, storage.ErrNotExist) {
+		return &NotifiedLog{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading notified log: %w", err)
+	}
+	l := &NotifiedLog{}
+	if err := json.Unmarshal(obj.Data, l); err != nil {
+		return nil, fmt.Errorf("parsing notified log %s: %w", key, err)
+	}
+	return l, nil
+}
+
+// Save drops entries older than four weeks and stores the log under key,
+// replacing the previous one.
+func (l *NotifiedLog) Save(b storage.Backend, key string, now time.Time) error {
+	kept := l.Entries[:0]
+	for _, e := range l.Entries {
+		if now.Sub(e.NotifiedAt) < notifiedKeep {
+			kept = append(kept, e)
+		}
+	}
+	l.Entries = kept
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.Put(key, storage.Object{Data: data}); err != nil {
+		return fmt.Errorf("writing notified log: %w", err)
+	}
+	return nil
+}
+
+// Record adds the deals of r to the log as sent at time at in week.
+func (l *NotifiedLog) Record(r Report, week string, at time.Time) {
+	for _, m := range r.Matches {
+		for i, d := range m.Deals {
+			l.Entries = append(l.Entries, Notified{
+				Store:      r.Store,
+				Rule:       m.Rule,
+				Key:        m.keys[i],
+				Title:      d.Title,
+				Week:       week,
+				NotifiedAt: at,
+			})
+		}
+	}
+}
+
+// Suppress removes from run the deals already sent in week for the same
+// rule and store, counting them in the Suppressed fields. Matches left
+// without deals are dropped.
+func (l *NotifiedLog) Suppress(run *RunReport, week string) {
+	sent := map[string]bool{}
+	for _, e := range l.Entries {
+		if e.Week == week {
+			sent[notifiedKey(e.Store, e.Rule, e.Key)] = true
+		}
+	}
+
+	run.Suppressed = 0
+	for i := range run.Stores {
+		report := &run.Stores[i]
+		report.Suppressed = 0
+		matches := report.Matches[:0]
+		for _, m := range report.Matches {
+			var removed int
+			m.Deals, m.keys, removed = unsent(sent, report.Store, m.Rule, m.Deals, m.keys)
+			report.Suppressed += removed
+			if len(m.Deals) > 0 {
+				matches = append(matches, m)
+			}
+		}
+		report.Matches = matches
+		run.Suppressed += report.Suppressed
+	}
+	for i := range run.Rules {
+		rule := &run.Rules[i]
+		matches := rule.Matches[:0]
+		for _, m := range rule.Matches {
+			m.Deals, m.keys, _ = unsent(sent, m.Store, rule.Rule, m.Deals, m.keys)
+			if len(m.Deals) > 0 {
+				matches = append(matches, m)
+			}
+		}
+		rule.Matches = matches
+	}
+}
+
+// unsent returns the deals, and their keys, not yet sent for rule at store,
+// and how many were left out.
+func unsent(sent map[string]bool, store, rule string, deals []display.DealJSON, keys []string) ([]display.DealJSON, []string, int) {
+	var keptDeals []display.DealJSON
+	var keptKeys []string
+	for i, d := range deals {
+		if sent[notifiedKey(store, rule, keys[i])] {
+			continue
+		}
+		keptDeals = append(keptDeals, d)
+		keptKeys = append(keptKeys, keys[i])
+	}
+	return keptDeals, keptKeys, len(deals) - len(keptDeals)
+}
+
+func notifiedKey(store, rule, key string) string {
+	return store + "\x00" + rule + "\x00" + key
+}
@@ -0,0 +1,231 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+const (
+	// DefaultTelegramAPI is the Telegram Bot API base URL.
+	DefaultTelegramAPI = "https://api.telegram.org"
+
+	// telegramMaxMessage is Telegram's limit on message text, in characters.
+	telegramMaxMessage = 4096
+	notifyTimeout      = 15 * time.Second
+)
+
+// DefaultTemplate is the message used when a destination has no template.
+const DefaultTemplate = `{{.DealCount}} watched item(s) on sale at Publix #{{.Store}}
+{{range .Matches}}
+{{.Rule}}:
+{{range .Deals}}• {{.Title}}{{if .Savings}} — {{.Savings}}{{end}}{{if .ValidTo}} (through {{.ValidTo}}){{end}}{{with .Flags}} [avoid: {{range $i, $f := .}}{{if $i}}, {{end}}{{$f}}{{end}}]{{end}}
+{{end}}{{end}}`
+
+// ErrRateLimited reports that a notifier skipped a send because its last
+// message was too recent.
+var ErrRateLimited = errors.New("rate limited")
+
+func isRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// Webhook POSTs the report as JSON.
+type Webhook struct {
+	URL    string
+	Index  int
+	Client *http.Client
+}
+
+// Name omits the URL, which often embeds a token.
+func (w *Webhook) Name() string {
+	return fmt.Sprintf("webhook #%d", w.Index)
+}
+
+// Notify implements Notifier.
+func (w *Webhook) Notify(ctx context.Context, r Report) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, w.Client, w.URL, body)
+}
+
+// Telegram sends the report as a bot message to one chat.
+type Telegram struct {
+	Token  string
+	ChatID string
+	// APIBase defaults to DefaultTelegramAPI.
+	APIBase string
+	Client  *http.Client
+
+	tmpl *template.Template
+}
+
+// NewTelegram creates a Telegram notifier. An empty text uses
+// DefaultTemplate.
+func NewTelegram(token, chatID, text string) (*Telegram, error) {
+	if strings.TrimSpace(token) == "" || strings.TrimSpace(chatID) == "" {
+		return nil, fmt.Errorf("telegram needs both bot_token and chat_id")
+	}
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Telegram{Token: token, ChatID: chatID, tmpl: tmpl}, nil
+}
+
+// ParseTemplate parses a message template. An empty text uses
+// DefaultTemplate.
+func ParseTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing message template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Name implements Notifier.
+func (t *Telegram) Name() string {
+	return "telegram"
+}
+
+// Notify renders the template and sends it, split into several messages
+// when it exceeds Telegram's length limit.
+func (t *Telegram) Notify(ctx context.Context, r Report) error {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, r); err != nil {
+		return fmt.Errorf("rendering message: %w", err)
+	}
+
+	base := t.APIBase
+	if base == "" {
+		base = DefaultTelegramAPI
+	}
+	endpoint := strings.TrimRight(base, "/") + "/bot" + t.Token + "/sendMessage"
+	for _, chunk := range splitMessage(strings.TrimSpace(buf.String()), telegramMaxMessage) {
+		body, err := json.Marshal(map[string]any{
+			"chat_id":                  t.ChatID,
+			"text":                     chunk,
+			"disable_web_page_preview": true,
+		})
+		if err != nil {
+			return err
+		}
+		if err := postJSON(ctx, t.Client, endpoint, body); err != nil {
+			// The endpoint embeds the bot token; keep it out of errors.
+			return errors.New(strings.ReplaceAll(err.Error(), t.Token, "<token>"))
+		}
+	}
+	return nil
+}
+
+// splitMessage breaks text into pieces of at most limit characters,
+// preferring line boundaries.
+func splitMessage(text string, limit int) []string {
+	var chunks []string
+	for utf8.RuneCountInString(text) > limit {
+		runes := []rune(text)
+		cut := string(runes[:limit])
+		if i := strings.LastIndex(cut, "\n"); i > 0 {
+			cut = cut[:i]
+		}
+		chunks = append(chunks, cut)
+		text = strings.TrimLeft(text[len(cut):], "\n")
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte) error {
+	if client == nil {
+		client = &http.Client{Timeout: notifyTimeout}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// RateLimited wraps a notifier so it sends at most once per Interval for
+// each store. Send times are kept in Store under StateKey so separate cron
+// runs share the limit.
+type RateLimited struct {
+	Notifier
+	Interval time.Duration
+	Store    storage.Backend
+	StateKey string
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+var stateMu sync.Mutex
+
+// Notify implements Notifier.
+func (l *RateLimited) Notify(ctx context.Context, r Report) error {
+	now := time.Now
+	if l.Now != nil {
+		now = l.Now
+	}
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	key := l.Name() + " #" + r.Store
+	state := loadSendState(l.Store, l.StateKey)
+	if last, ok := state[key]; ok && now().Sub(last) < l.Interval {
+		return fmt.Errorf("%w: last message %s ago, min_interval is %s",
+			ErrRateLimited, now().Sub(last).Round(time.Second), l.Interval)
+	}
+	if err := l.Notifier.Notify(ctx, r); err != nil {
+		return err
+	}
+	state[key] = now()
+	return saveSendState(l.Store, l.StateKey, state)
+}
+
+func loadSendState(b storage.Backend, key string) map[string]time.Time {
+	state := map[string]time.Time{}
+	obj, err := b.Get(key)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(obj.Data, &state); err != nil {
+		return map[string]time.Time{}
+	}
+	return state
+}
+
+func saveSendState(b storage.Backend, key string, state map[string]time.Time) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, storage.Object{Data: data})
+}
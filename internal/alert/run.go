@@ -0,0 +1,177 @@
+package alert
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+// DefaultConcurrency is the number of weekly ads Run fetches at once.
+const DefaultConcurrency = 4
+
+// FetchFunc fetches the weekly ad of one store.
+type FetchFunc func(ctx context.Context, storeNumber string) (*api.SavingsResponse, error)
+
+// RunReport is the result of evaluating rules across the stores they
+// watch. Stores holds one Report per fetched store, the unit sent to
+// notifiers; Rules groups the same matches by rule.
+type RunReport struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Stores      []Report       `json:"stores"`
+	Rules       []RuleReport   `json:"rules"`
+	Failed      []StoreFailure `json:"failed,omitempty"`
+	// Suppressed counts matched deals left out because they were already
+	// sent this ad week; see NotifiedLog.Suppress.
+	Suppressed int `json:"suppressed"`
+}
+
+// RuleReport is one rule's matches at each store it watches. Stores whose
+// ad had no match, or could not be fetched, are left out of Matches.
+type RuleReport struct {
+	Rule    string       `json:"rule"`
+	Stores  []string     `json:"stores"`
+	Matches []StoreMatch `json:"matches"`
+}
+
+// StoreMatch is the deals one rule matched at one store.
+type StoreMatch struct {
+	Store string             `json:"store"`
+	Deals []display.DealJSON `json:"deals"`
+
+	keys []string
+}
+
+// StoreFailure is a store whose weekly ad could not be fetched.
+type StoreFailure struct {
+	Store string `json:"store"`
+	Error string `json:"error"`
+	Err   error  `json:"-"`
+}
+
+// StoresFor returns the stores the rule watches: its own list, or
+// defaultStore when the list is empty.
+func (r Rule) StoresFor(defaultStore string) []string {
+	var stores []string
+	seen := map[string]bool{}
+	for _, s := range r.Stores {
+		s = normalizeStore(s)
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		stores = append(stores, s)
+	}
+	if len(stores) == 0 && defaultStore != "" {
+		stores = []string{normalizeStore(defaultStore)}
+	}
+	return stores
+}
+
+// NeedsDefaultStore reports whether any rule lacks its own store list, so
+// Run needs a default store for it.
+func NeedsDefaultStore(rules []Rule) bool {
+	for _, r := range rules {
+		if len(r.StoresFor("")) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Run fetches the weekly ad of every store any rule watches, each store
+// once and up to concurrency at a time, and evaluates every rule against
+// the ads of its stores. Stores whose ad cannot be fetched are listed in
+// Failed; the remaining stores are still evaluated. Reports and rules keep
+// the order in which stores and rules first appear.
+func Run(ctx context.Context, rules []Rule, defaultStore string, fetch FetchFunc, concurrency int) RunReport {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var order []string
+	watched := make([][]string, len(rules))
+	seen := map[string]bool{}
+	for i, rule := range rules {
+		watched[i] = rule.StoresFor(defaultStore)
+		if watched[i] == nil {
+			watched[i] = []string{}
+		}
+		for _, s := range watched[i] {
+			if !seen[s] {
+				seen[s] = true
+				order = append(order, s)
+			}
+		}
+	}
+
+	ads := map[string]*api.SavingsResponse{}
+	failures := map[string]error{}
+	var mu sync.Mutex
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(order); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for store := range jobs {
+				data, err := fetch(ctx, store)
+				mu.Lock()
+				if err != nil {
+					failures[store] = err
+				} else {
+					ads[store] = data
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, store := range order {
+		jobs <- store
+	}
+	close(jobs)
+	wg.Wait()
+
+	run := RunReport{GeneratedAt: time.Now().UTC(), Stores: []Report{}, Rules: make([]RuleReport, len(rules))}
+	for i, rule := range rules {
+		run.Rules[i] = RuleReport{Rule: rule.Name, Stores: watched[i], Matches: []StoreMatch{}}
+	}
+	for _, store := range order {
+		if err, ok := failures[store]; ok {
+			run.Failed = append(run.Failed, StoreFailure{Store: store, Error: err.Error(), Err: err})
+			continue
+		}
+		data := ads[store]
+		report := Report{Store: store, Updated: data.WeeklyAdLatestUpdatedDateTime, GeneratedAt: run.GeneratedAt, Matches: []Match{}}
+		for i, rule := range rules {
+			if !slices.Contains(watched[i], store) {
+				continue
+			}
+			if deals, keys := matchDeals(rule, data.Savings); len(deals) > 0 {
+				report.Matches = append(report.Matches, Match{Rule: rule.Name, Deals: deals, keys: keys})
+				run.Rules[i].Matches = append(run.Rules[i].Matches, StoreMatch{Store: store, Deals: deals, keys: keys})
+			}
+		}
+		run.Stores = append(run.Stores, report)
+	}
+	return run
+}
+
+// DealCount is the number of matched deals across all stores.
+func (r RunReport) DealCount() int {
+	n := 0
+	for _, report := range r.Stores {
+		n += report.DealCount()
+	}
+	return n
+}
+
+// normalizeStore strips the "#" and leading zeros people write store
+// numbers with, so "#01425" and "1425" are the same store.
+func normalizeStore(s string) string {
+	return api.StoreNumber(strings.TrimPrefix(strings.TrimSpace(s), "#"))
+}
@@ -0,0 +1,88 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// WatchlistFile is the watchlist's file name inside the data directory.
+const WatchlistFile = "watchlist.json"
+
+// Watchlist holds the rules added with `pubcli alert add`. They are kept
+// apart from config.yaml so the CLI can edit them and devices can share them.
+type Watchlist struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadWatchlist reads the watchlist stored under key. A missing watchlist
+// is empty.
+func LoadWatchlist(b storage.Backend, key string) (*Watchlist, error) {
+	obj, err := b.Get(key)
+	if errors.Is(err, storage.ErrNotExist) {
+		return &Watchlist{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading watchlist: %w", err)
+	}
+	w := &Watchlist{}
+	if err := json.Unmarshal(obj.Data, w); err != nil {
+		return nil, fmt.Errorf("parsing watchlist %s: %w", key, err)
+	}
+	return w, nil
+}
+
+// Save stores the watchlist under key, replacing the previous one.
+func (w *Watchlist) Save(b storage.Backend, key string) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.Put(key, storage.Object{Data: data}); err != nil {
+		return fmt.Errorf("writing watchlist: %w", err)
+	}
+	return nil
+}
+
+// Validate checks every rule and that no two rules share a name.
+func (w *Watchlist) Validate() error {
+	seen := map[string]bool{}
+	for _, r := range w.Rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		name := strings.ToLower(strings.TrimSpace(r.Name))
+		if seen[name] {
+			return fmt.Errorf("more than one rule is named %q", r.Name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// Set adds a rule, replacing any rule with the same name. It reports whether
+// an existing rule was replaced.
+func (w *Watchlist) Set(rule Rule) bool {
+	for i, existing := range w.Rules {
+		if strings.EqualFold(existing.Name, rule.Name) {
+			w.Rules[i] = rule
+			return true
+		}
+	}
+	w.Rules = append(w.Rules, rule)
+	return false
+}
+
+// Remove deletes the rule with the given name, case-insensitively.
+func (w *Watchlist) Remove(name string) bool {
+	for i, existing := range w.Rules {
+		if strings.EqualFold(existing.Name, name) {
+			w.Rules = append(w.Rules[:i], w.Rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBreakerThreshold is how many consecutive failures open a breaker.
+	DefaultBreakerThreshold = 3
+	// DefaultBreakerCooldown is how long an open breaker refuses calls.
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen matches the *CircuitOpenError returned for calls refused by
+// an open breaker.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitOpenError reports a call refused without reaching the API because
+// the previous calls kept failing.
+type CircuitOpenError struct {
+	// Failures is the number of consecutive failures that opened the breaker.
+	Failures int
+	// Until is when the breaker lets the next call through.
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open after %d consecutive upstream failures; calls resume at %s",
+		e.Failures, e.Until.Format(time.TimeOnly))
+}
+
+// Is makes errors.Is(err, ErrCircuitOpen) match.
+func (e *CircuitOpenError) Is(target error) bool { return target == ErrCircuitOpen }
+
+// Breaker stops calls to an API that keeps failing. After Threshold
+// consecutive server errors or timeouts it opens, and calls fail at once
+// with a *CircuitOpenError until Cooldown has passed. It is then half-open:
+// the next call is let through as a trial and the others are still refused
+// until Record reports how the trial went. A success closes the breaker and
+// a failure reopens it.
+type Breaker struct {
+	Threshold int
+	Cooldown  time.Duration
+	// Now returns the current time; nil uses time.Now.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	// trial is set while the one call let through half-open is running.
+	trial bool
+}
+
+// NewBreaker returns a breaker with the default threshold and cooldown.
+func NewBreaker() *Breaker {
+	return &Breaker{Threshold: DefaultBreakerThreshold, Cooldown: DefaultBreakerCooldown}
+}
+
+// WithBreaker guards the client's API calls with b. Share one breaker
+// between clients that talk to the same API.
+func WithBreaker(b *Breaker) Option {
+	return func(c *Client) {
+		c.breaker = b
+	}
+}
+
+func (b *Breaker) now() time.Time {
+	if b.Now != nil {
+		return b.Now()
+	}
+	return time.Now()
+}
+
+// Allow returns a *CircuitOpenError while the breaker is open, and while
+// a half-open trial call is running. A call it allows must be followed by
+// Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.now().Before(b.openUntil) || b.trial {
+		return &CircuitOpenError{Failures: b.failures, Until: b.openUntil}
+	}
+	if b.failures >= b.Threshold {
+		b.trial = true
+	}
+	return nil
+}
+
+// Record notes the outcome of a call: err from the transport, or the
+// response status. Cancellations by the caller count as neither, but end a
+// half-open trial so the next call can try again.
+func (b *Breaker) Record(ctx context.Context, status int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if ctx.Err() != nil {
+		return
+	}
+	if err == nil && status < http.StatusInternalServerError {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.failures++
+	if b.failures >= b.Threshold {
+		b.openUntil = b.now().Add(b.Cooldown)
+	}
+}
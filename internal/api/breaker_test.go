@@ -0,0 +1,116 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestBreaker_OpensAfterRepeatedServerErrors(t *testing.T) {
+	var calls, failing atomic.Int32
+	failing.Store(1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"Savings":[]}`))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	breaker := &api.Breaker{Threshold: 2, Cooldown: time.Minute, Now: func() time.Time { return now }}
+	client := api.NewClientWithBaseURLs(srv.URL, srv.URL, api.WithBreaker(breaker))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.FetchSavings(ctx, "1425")
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, api.ErrCircuitOpen))
+	}
+	_, err := client.FetchSavings(ctx, "1425")
+	require.ErrorIs(t, err, api.ErrCircuitOpen)
+	var open *api.CircuitOpenError
+	require.ErrorAs(t, err, &open)
+	assert.Equal(t, now.Add(time.Minute), open.Until)
+	assert.Equal(t, int32(2), calls.Load(), "an open breaker makes no request")
+
+	// After the cooldown one call is let through; it fails and reopens.
+	now = now.Add(time.Minute)
+	_, err = client.FetchSavings(ctx, "1425")
+	assert.False(t, errors.Is(err, api.ErrCircuitOpen))
+	_, err = client.FetchSavings(ctx, "1425")
+	assert.ErrorIs(t, err, api.ErrCircuitOpen)
+
+	// A success closes it again.
+	now = now.Add(time.Minute)
+	failing.Store(0)
+	_, err = client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	failing.Store(1)
+	_, err = client.FetchSavings(ctx, "1425")
+	assert.False(t, errors.Is(err, api.ErrCircuitOpen), "the failure count starts over")
+}
+
+func TestBreaker_IgnoresClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURLs(srv.URL, srv.URL, api.WithBreaker(&api.Breaker{Threshold: 1, Cooldown: time.Minute}))
+	for i := 0; i < 3; i++ {
+		_, err := client.FetchSavings(context.Background(), "1425")
+		assert.False(t, errors.Is(err, api.ErrCircuitOpen))
+	}
+}
+
+func TestBreaker_HalfOpenLetsOneTrialThrough(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		<-release
+		w.Write([]byte(`{"Savings":[]}`))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	breaker := &api.Breaker{Threshold: 1, Cooldown: time.Minute, Now: func() time.Time { return now }}
+	client := api.NewClientWithBaseURLs(srv.URL, srv.URL, api.WithBreaker(breaker))
+	_, err := client.FetchSavings(context.Background(), "1425")
+	require.Error(t, err)
+
+	now = now.Add(time.Minute)
+	const callers = 8
+	results := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, err := client.FetchSavings(context.Background(), "1425")
+			results <- err
+		}()
+	}
+	// The trial call waits on release, so every other caller is refused
+	// while it runs.
+	for i := 0; i < callers-1; i++ {
+		assert.ErrorIs(t, <-results, api.ErrCircuitOpen)
+	}
+	close(release)
+	assert.NoError(t, <-results, "the trial call reaches the API")
+	assert.Equal(t, int32(2), calls.Load(), "only the trial call was sent after the cooldown")
+
+	_, err = client.FetchSavings(context.Background(), "1425")
+	assert.NoError(t, err, "the trial's success closes the breaker")
+}
@@ -2,14 +2,16 @@ package api
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/tayloree/publix-deals/internal/normalize"
 )
 
 const (
@@ -23,91 +25,297 @@ type Client struct {
 	httpClient *http.Client
 	savingsURL string
 	storeURL   string
+	storeTypes string
+	headers    http.Header
+
+	memoMu sync.Mutex
+	memo   map[string][]byte
+
+	diskCache string
+
+	breaker       *Breaker
+	retries       int
+	retryBackoff  time.Duration
+	observe       func(storeNumber string, attempts int, err error)
+	savingsFilter func([]SavingItem) []SavingItem
 }
 
-// NewClient creates a new Publix API client.
-func NewClient() *Client {
-	return &Client{
-		httpClient: &http.Client{Timeout: 15 * time.Second},
-		savingsURL: defaultSavingsAPI,
-		storeURL:   defaultStoreAPI,
+// Option configures a Client.
+type Option func(*Client)
+
+// WithMemo makes the client remember successful responses for its lifetime,
+// so repeated lookups of the same store or ZIP hit the network once.
+func WithMemo() Option {
+	return func(c *Client) {
+		c.memo = make(map[string][]byte)
+	}
+}
+
+// WithHTTPClient replaces the underlying HTTP client, for example to route
+// requests through a custom transport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
 	}
 }
 
+// WithObserver calls fn after every request the client sends, with the
+// request's store number ("" for store lookups), how many times it was sent
+// (more than 1 after WithRetries retried it), and its error, nil on
+// success. Responses served from WithMemo are not requests.
+func WithObserver(fn func(storeNumber string, attempts int, err error)) Option {
+	return func(c *Client) {
+		c.observe = fn
+	}
+}
+
+// WithSavingsFilter passes the deals of every ad FetchSavings returns through
+// fn, to hide deals the user never wants to see.
+func WithSavingsFilter(fn func([]SavingItem) []SavingItem) Option {
+	return func(c *Client) {
+		c.savingsFilter = fn
+	}
+}
+
+// NewClient creates a new Publix API client.
+func NewClient(opts ...Option) *Client {
+	return NewClientWithBaseURLs(defaultSavingsAPI, defaultStoreAPI, opts...)
+}
+
 // NewClientWithBaseURLs creates a client with custom base URLs (for testing).
-func NewClientWithBaseURLs(savingsURL, storeURL string) *Client {
-	return &Client{
+func NewClientWithBaseURLs(savingsURL, storeURL string, opts ...Option) *Client {
+	c := &Client{
 		httpClient: &http.Client{Timeout: 15 * time.Second},
 		savingsURL: savingsURL,
 		storeURL:   storeURL,
+		storeTypes: DefaultStoreTypes,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-func (c *Client) getAndDecode(ctx context.Context, reqURL, storeNumber string, out any) error {
+// get fetches reqURL and passes the body to parse. With WithMemo, bodies
+// that parsed are kept and reused for later calls with the same URL and
+// store.
+func (c *Client) get(ctx context.Context, reqURL, storeNumber string, parse func([]byte) error) error {
+	key := storeNumber + "\x00" + reqURL
+	if c.memo != nil {
+		c.memoMu.Lock()
+		body, ok := c.memo[key]
+		c.memoMu.Unlock()
+		if ok {
+			return parse(body)
+		}
+	}
+
+	body, attempts, err := c.fetchWithRetries(ctx, reqURL, storeNumber)
+	if err == nil {
+		err = parse(body)
+	}
+	if c.observe != nil {
+		c.observe(storeNumber, attempts, err)
+	}
+	if err != nil {
+		return err
+	}
+	if c.memo != nil {
+		c.memoMu.Lock()
+		c.memo[key] = body
+		c.memoMu.Unlock()
+	}
+	return nil
+}
+
+func (c *Client) fetchBody(ctx context.Context, reqURL, storeNumber string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", userAgent)
 	if storeNumber != "" {
 		req.Header.Set("PublixStore", storeNumber)
 	}
+	c.setHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("executing request: %w", err)
+		return nil, &RequestError{URL: reqURL, Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, reqURL)
-	}
-
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(out); err != nil {
-		return fmt.Errorf("decoding response: %w", err)
+		return nil, &StatusError{Code: resp.StatusCode, URL: reqURL}
 	}
-	if err := dec.Decode(new(struct{})); !errors.Is(err, io.EOF) {
-		return fmt.Errorf("decoding response: trailing JSON content")
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &RequestError{URL: reqURL, Err: fmt.Errorf("reading response: %w", err)}
 	}
-	return nil
+	return body, nil
 }
 
 // FetchStores finds Publix stores near the given zip code.
 func (c *Client) FetchStores(ctx context.Context, zipCode string, count int) ([]Store, error) {
 	params := url.Values{
-		"types":                    {"R,G,H,N,S"},
+		"types":                    {c.storeTypes},
 		"option":                   {""},
 		"count":                    {fmt.Sprintf("%d", count)},
 		"includeOpenAndCloseDates": {"true"},
 		"zipCode":                  {zipCode},
 	}
 
-	var resp StoreResponse
-	if err := c.getAndDecode(ctx, c.storeURL+"?"+params.Encode(), "", &resp); err != nil {
+	var resp *StoreResponse
+	err := c.get(ctx, c.storeURL+"?"+params.Encode(), "", func(body []byte) (err error) {
+		resp, err = ParseStoreResponse(body)
+		return err
+	})
+	if err != nil {
 		return nil, fmt.Errorf("fetching stores: %w", err)
 	}
 	return resp.Stores, nil
 }
 
-// FetchSavings fetches all weekly ad savings for the given store.
+// FetchSavings fetches all weekly ad savings for the given store, passed
+// through the WithSavingsFilter filter.
 func (c *Client) FetchSavings(ctx context.Context, storeNumber string) (*SavingsResponse, error) {
+	resp, err := c.FetchUnfilteredSavings(ctx, storeNumber)
+	if err != nil {
+		return nil, err
+	}
+	resp.Savings = c.FilterSavings(resp.Savings)
+	return resp, nil
+}
+
+// FetchUnfilteredSavings is FetchSavings without the WithSavingsFilter
+// filter, for callers that keep whole ads, such as the ad cache.
+func (c *Client) FetchUnfilteredSavings(ctx context.Context, storeNumber string) (*SavingsResponse, error) {
+	if body, ok := c.cachedAd(storeNumber); ok {
+		if resp, err := ParseSavingsResponse(body); err == nil {
+			return resp, nil
+		}
+	}
+
+	var resp *SavingsResponse
+	var raw []byte
+	err := c.get(ctx, c.savingsRequestURL(0), storeNumber, func(body []byte) (err error) {
+		resp, err = ParseSavingsResponse(body)
+		raw = body
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching savings: %w", err)
+	}
+	c.cacheAd(storeNumber, raw)
+	return resp, nil
+}
+
+// FilterSavings applies the WithSavingsFilter filter to deals fetched
+// without it; items is returned as is when the client has none.
+func (c *Client) FilterSavings(items []SavingItem) []SavingItem {
+	if c.savingsFilter == nil {
+		return items
+	}
+	return c.savingsFilter(items)
+}
+
+// normalizeSavings canonicalizes the categories, departments, and brands of
+// freshly decoded deals.
+func normalizeSavings(items []SavingItem) {
+	for i := range items {
+		item := &items[i]
+		item.Categories = normalize.Categories(item.Categories)
+		if item.Department != nil {
+			dept := normalize.Department(*item.Department)
+			item.Department = &dept
+		}
+		if item.Brand != nil {
+			brand := normalize.Brand(*item.Brand)
+			item.Brand = &brand
+		}
+	}
+}
+
+// FetchAdVersion returns the store's WeeklyAdLatestUpdatedDateTime from a
+// one-deal page, a cheap way to tell whether a saved ad is still current.
+// A WithDiskCache copy of another version is dropped, so the next
+// FetchSavings downloads the ad again.
+func (c *Client) FetchAdVersion(ctx context.Context, storeNumber string) (string, error) {
+	var resp *SavingsResponse
+	err := c.get(ctx, c.savingsRequestURL(1), storeNumber, func(body []byte) (err error) {
+		resp, err = ParseSavingsResponse(body)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("fetching ad version: %w", err)
+	}
+	c.checkCachedAd(storeNumber, resp.WeeklyAdLatestUpdatedDateTime)
+	return resp.WeeklyAdLatestUpdatedDateTime, nil
+}
+
+// savingsRequestURL builds a weekly ad request; a pageSize of 0 means all
+// deals.
+func (c *Client) savingsRequestURL(pageSize int) string {
 	params := url.Values{
 		"page":                     {"1"},
-		"pageSize":                 {"0"},
+		"pageSize":                 {strconv.Itoa(pageSize)},
 		"includePersonalizedDeals": {"false"},
 		"languageID":               {"1"},
 		"isWeb":                    {"true"},
 		"getSavingType":            {"WeeklyAd"},
 	}
+	return c.savingsURL + "?" + params.Encode()
+}
 
-	var resp SavingsResponse
-	if err := c.getAndDecode(ctx, c.savingsURL+"?"+params.Encode(), storeNumber, &resp); err != nil {
-		return nil, fmt.Errorf("fetching savings: %w", err)
+// EndpointHealth is the result of pinging one API endpoint.
+type EndpointHealth struct {
+	// Name is "savings" or "stores".
+	Name    string
+	URL     string
+	Status  int
+	Latency time.Duration
+	// Err is set when no response arrived.
+	Err error
+}
+
+// Up reports whether the endpoint answered without a server error. Any
+// other status, such as 405 for HEAD, still shows the service is reachable.
+func (h EndpointHealth) Up() bool {
+	return h.Err == nil && h.Status > 0 && h.Status < 500
+}
+
+// Ping sends a HEAD request to the savings and store endpoints in parallel
+// and reports how each answered.
+func (c *Client) Ping(ctx context.Context) []EndpointHealth {
+	results := []EndpointHealth{{Name: "savings", URL: c.savingsURL}, {Name: "stores", URL: c.storeURL}}
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(h *EndpointHealth) {
+			defer wg.Done()
+			h.Status, h.Latency, h.Err = c.head(ctx, h.URL)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *Client) head(ctx context.Context, reqURL string) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, reqURL, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("creating request: %w", err)
+	}
+	c.setHeaders(req)
+
+	started := time.Now()
+	resp, err := c.httpClient.Do(req)
+	latency := time.Since(started)
+	if err != nil {
+		return 0, latency, &RequestError{URL: reqURL, Err: err}
 	}
-	return &resp, nil
+	resp.Body.Close()
+	return resp.StatusCode, latency, nil
 }
 
 // StoreNumber returns the numeric portion of a store key (strips leading zeros).
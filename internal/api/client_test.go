@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -74,6 +75,52 @@ func TestFetchSavings(t *testing.T) {
 	assert.Equal(t, "Buy 1 Get 1 FREE", *resp.Savings[1].Savings)
 }
 
+func TestFetchSavings_NormalizesLabels(t *testing.T) {
+	srv := newTestSavingsServer(t, "1425", []api.SavingItem{{
+		ID:         "a",
+		Department: ptr("MEAT & SEAFOOD"),
+		Brand:      ptr("publix"),
+		Categories: []string{"Meat", "meat & seafood", "BOGO"},
+	}})
+	defer srv.Close()
+
+	resp, err := api.NewClientWithBaseURLs(srv.URL, "").FetchSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	item := resp.Savings[0]
+	assert.Equal(t, "Meat", *item.Department)
+	assert.Equal(t, "Publix", *item.Brand)
+	assert.Equal(t, []string{"meat", "bogo"}, item.Categories)
+}
+
+func TestFetchSavings_ResolvesValidityDates(t *testing.T) {
+	srv := newTestSavingsServer(t, "1425", []api.SavingItem{
+		{ID: "a", StartFormatted: "2/18/2026", EndFormatted: "2/24/2026"},
+		{ID: "b", EndFormatted: "soon"},
+	})
+	defer srv.Close()
+
+	resp, err := api.NewClientWithBaseURLs(srv.URL, "").FetchSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 2, 18, 0, 0, 0, 0, time.Local), resp.Savings[0].Start)
+	assert.Equal(t, time.Date(2026, 2, 24, 0, 0, 0, 0, time.Local), resp.Savings[0].End)
+	assert.True(t, resp.Savings[1].End.IsZero())
+}
+
+func TestParseDealDate(t *testing.T) {
+	now := time.Date(2026, 12, 30, 12, 0, 0, 0, time.UTC)
+
+	day, ok := api.ParseDealDate("1/5", now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC), day, "a yearless date resolves to the nearest year")
+
+	day, ok = api.ParseDealDate("12/29/2026", now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 12, 29, 0, 0, 0, 0, time.UTC), day)
+
+	_, ok = api.ParseDealDate("soon", now)
+	assert.False(t, ok)
+}
+
 func TestFetchSavings_EmptyStore(t *testing.T) {
 	srv := newTestSavingsServer(t, "", nil)
 	defer srv.Close()
@@ -94,10 +141,40 @@ func TestFetchSavings_ServerError(t *testing.T) {
 	client := api.NewClientWithBaseURLs(srv.URL, "")
 	_, err := client.FetchSavings(context.Background(), "1425")
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, api.ErrUpstreamStatus)
+	var status *api.StatusError
+	require.ErrorAs(t, err, &status)
+	assert.Equal(t, http.StatusInternalServerError, status.Code)
 	assert.Contains(t, err.Error(), "500")
 }
 
+func TestFetchStores_ConnectionRefusedIsRequestError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client := api.NewClientWithBaseURLs("", srv.URL)
+	_, err := client.FetchStores(context.Background(), "33101", 1)
+
+	assert.ErrorIs(t, err, api.ErrRequest)
+	assert.NotErrorIs(t, err, api.ErrUpstreamStatus)
+}
+
+func TestFetchAdVersion_RequestsOneDeal(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("pageSize"))
+		assert.Equal(t, "1425", r.Header.Get("PublixStore"))
+		json.NewEncoder(w).Encode(api.SavingsResponse{
+			Savings:                       []api.SavingItem{{ID: "a"}},
+			WeeklyAdLatestUpdatedDateTime: "2026-02-18T06:00:00",
+		})
+	}))
+	defer srv.Close()
+
+	version, err := api.NewClientWithBaseURLs(srv.URL, "").FetchAdVersion(context.Background(), "1425")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02-18T06:00:00", version)
+}
+
 func TestFetchStores(t *testing.T) {
 	stores := []api.Store{
 		{Key: "01425", Name: "Peachers Mill", City: "Clarksville", State: "TN", Zip: "37042", Distance: "5"},
@@ -137,11 +214,11 @@ func TestFetchSavings_TrailingJSONIsRejected(t *testing.T) {
 	client := api.NewClientWithBaseURLs(srv.URL, "")
 	_, err := client.FetchSavings(context.Background(), "1425")
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "decoding")
+	assert.ErrorIs(t, err, api.ErrDecode)
+	assert.Contains(t, err.Error(), "trailing content")
 }
 
-func TestFetchStores_MalformedJSONReturnsDecodeError(t *testing.T) {
+func TestFetchStores_MalformedJSONIsClassified(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write([]byte(`{"Stores":`))
@@ -151,8 +228,10 @@ func TestFetchStores_MalformedJSONReturnsDecodeError(t *testing.T) {
 	client := api.NewClientWithBaseURLs("", srv.URL)
 	_, err := client.FetchStores(context.Background(), "37042", 5)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "decoding")
+	var malformed *api.MalformedResponseError
+	require.ErrorAs(t, err, &malformed)
+	assert.Equal(t, "stores", malformed.Response)
+	assert.Equal(t, "truncated JSON", malformed.Reason)
 }
 
 func TestStoreNumber(t *testing.T) {
@@ -169,3 +248,61 @@ func TestStoreNumber(t *testing.T) {
 		assert.Equal(t, tt.want, api.StoreNumber(tt.input), "StoreNumber(%q)", tt.input)
 	}
 }
+
+func TestWithMemo_ReusesResponses(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(api.SavingsResponse{Savings: []api.SavingItem{{ID: "1"}}})
+	}))
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURLs(srv.URL, "", api.WithMemo())
+	for range 3 {
+		resp, err := client.FetchSavings(context.Background(), "1425")
+		require.NoError(t, err)
+		assert.Len(t, resp.Savings, 1)
+	}
+	_, err := client.FetchSavings(context.Background(), "1500")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calls, "one request per distinct store")
+}
+
+func TestPing(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	health := api.NewClientWithBaseURLs(up.URL, down.URL).Ping(context.Background())
+	require.Len(t, health, 2)
+	assert.Equal(t, "savings", health[0].Name)
+	assert.True(t, health[0].Up(), "any non-5xx answer means reachable")
+	assert.Equal(t, http.StatusMethodNotAllowed, health[0].Status)
+	assert.Equal(t, "stores", health[1].Name)
+	assert.False(t, health[1].Up())
+	assert.Equal(t, http.StatusServiceUnavailable, health[1].Status)
+}
+
+func TestFetchSavings_SavingsFilter(t *testing.T) {
+	srv := newTestSavingsServer(t, "1425", []api.SavingItem{{ID: "a"}, {ID: "b"}})
+	defer srv.Close()
+
+	dropB := func(items []api.SavingItem) []api.SavingItem {
+		return []api.SavingItem{items[0]}
+	}
+	client := api.NewClientWithBaseURLs(srv.URL, "", api.WithSavingsFilter(dropB))
+	resp, err := client.FetchSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	assert.Len(t, resp.Savings, 1)
+
+	resp, err = client.FetchUnfilteredSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	assert.Len(t, resp.Savings, 2)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"strings"
+	"time"
+)
+
+// dealDateLayouts are the dated formats seen in deal validity strings.
+var dealDateLayouts = []string{
+	"1/2/2006",
+	"01/02/2006",
+	"1/2/06",
+	"01/02/06",
+	"2006-01-02",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// ParseDealDate parses a deal validity date such as "10/21" or "10/21/2026"
+// to midnight of that day in now's location. Dates without a year ("10/21")
+// take the year that puts them closest to now, so ads spanning New Year
+// resolve correctly.
+func ParseDealDate(raw string, now time.Time) (time.Time, bool) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dealDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location()), true
+		}
+	}
+
+	day, err := time.Parse("1/2", value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	best := time.Time{}
+	for _, year := range []int{now.Year() - 1, now.Year(), now.Year() + 1} {
+		candidate := time.Date(year, day.Month(), day.Day(), 0, 0, 0, 0, now.Location())
+		if best.IsZero() || absDuration(candidate.Sub(now)) < absDuration(best.Sub(now)) {
+			best = candidate
+		}
+	}
+	return best, true
+}
+
+// ResolveDates fills in each deal's Start and End from its formatted
+// validity dates, resolving yearless dates against now. Dates that cannot be
+// parsed are left zero.
+func ResolveDates(items []SavingItem, now time.Time) {
+	for i := range items {
+		items[i].Start, _ = ParseDealDate(items[i].StartFormatted, now)
+		items[i].End, _ = ParseDealDate(items[i].EndFormatted, now)
+	}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AdCacheDir is the directory inside the cache directory that WithDiskCache
+// callers keep weekly ads in.
+const AdCacheDir = "ads"
+
+// WithDiskCache keeps the response body of every weekly ad FetchSavings
+// downloads in dir, keyed by store number and ad week, and serves later
+// fetches for the same store in the same week from it without a request.
+// A store's ads from earlier weeks are removed when a new one is saved.
+// Each ad is saved with a SHA-256 hash of its body, and one whose hash is
+// missing or does not match is downloaded again.
+// The cache only saves time, so one that cannot be read or written is
+// skipped rather than failing the fetch.
+func WithDiskCache(dir string) Option {
+	return func(c *Client) {
+		c.diskCache = dir
+	}
+}
+
+// AdWeekStart returns midnight on the Wednesday that starts the ad week
+// containing t. Publix weekly ads run Wednesday through Tuesday.
+func AdWeekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	back := (int(day.Weekday()) - int(time.Wednesday) + 7) % 7
+	return day.AddDate(0, 0, -back)
+}
+
+// cachedAdFile is a cached ad on disk: the response body and its digest.
+type cachedAdFile struct {
+	// Hash is the SHA-256 of Ad, in hex.
+	Hash string          `json:"hash"`
+	Ad   json.RawMessage `json:"ad"`
+}
+
+func adDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedAd returns the cached body of the store's ad for this week. A copy
+// without a hash, or whose hash does not match, such as a truncated file,
+// is removed and counts as not cached.
+func (c *Client) cachedAd(storeNumber string) ([]byte, bool) {
+	path, ok := c.adCachePath(storeNumber, time.Now())
+	if !ok {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var f cachedAdFile
+	if json.Unmarshal(data, &f) != nil || f.Hash == "" || f.Hash != adDigest(f.Ad) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return f.Ad, true
+}
+
+// cacheAd saves the store's ad for this week with its digest, written
+// atomically, and removes the store's older weeks.
+func (c *Client) cacheAd(storeNumber string, body []byte) {
+	path, ok := c.adCachePath(storeNumber, time.Now())
+	if !ok {
+		return
+	}
+	// Hash the compact form, which is what encoding the file stores.
+	var ad bytes.Buffer
+	if err := json.Compact(&ad, body); err != nil {
+		return
+	}
+	data, err := json.Marshal(cachedAdFile{Hash: adDigest(ad.Bytes()), Ad: ad.Bytes()})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.diskCache, 0o755); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return
+	}
+	old, _ := filepath.Glob(filepath.Join(c.diskCache, storeNumber+"-*.json"))
+	for _, p := range old {
+		if p != path {
+			_ = os.Remove(p)
+		}
+	}
+}
+
+// checkCachedAd removes the store's cached ad for this week when Publix has
+// updated the ad since it was saved.
+func (c *Client) checkCachedAd(storeNumber, version string) {
+	body, ok := c.cachedAd(storeNumber)
+	if !ok || version == "" {
+		return
+	}
+	var cached struct {
+		WeeklyAdLatestUpdatedDateTime string
+	}
+	if json.Unmarshal(body, &cached) == nil && cached.WeeklyAdLatestUpdatedDateTime == version {
+		return
+	}
+	if path, ok := c.adCachePath(storeNumber, time.Now()); ok {
+		_ = os.Remove(path)
+	}
+}
+
+// RevalidateCachedAd checks a cached copy of the store's ad for this week
+// against the ad's current version, as FetchAdVersion does, so the next
+// FetchSavings downloads an ad Publix has updated since it was cached.
+// Without a cached copy it makes no request.
+func (c *Client) RevalidateCachedAd(ctx context.Context, storeNumber string) error {
+	if _, ok := c.cachedAd(storeNumber); !ok {
+		return nil
+	}
+	_, err := c.FetchAdVersion(ctx, storeNumber)
+	return err
+}
+
+func (c *Client) adCachePath(storeNumber string, now time.Time) (string, bool) {
+	if c.diskCache == "" || storeNumber == "" || strings.ContainsAny(storeNumber, `/\*?[`) {
+		return "", false
+	}
+	week := AdWeekStart(now).Format("2006-01-02")
+	return filepath.Join(c.diskCache, storeNumber+"-"+week+".json"), true
+}
+
+// ClearDiskCache removes every ad cached in dir and reports how many there
+// were. A missing dir holds none.
+func ClearDiskCache(dir string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return removed, fmt.Errorf("clearing ad cache: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,160 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestWithDiskCache_ReusesAdAcrossClients(t *testing.T) {
+	calls := 0
+	version := "2025-02-12T08:00:00"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(api.SavingsResponse{
+			Savings:                       []api.SavingItem{{ID: "1", Title: ptr("Nutella")}},
+			WeeklyAdLatestUpdatedDateTime: version,
+		})
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	for range 2 {
+		client := api.NewClientWithBaseURLs(srv.URL, "", api.WithDiskCache(dir))
+		resp, err := client.FetchSavings(context.Background(), "1425")
+		require.NoError(t, err)
+		require.Len(t, resp.Savings, 1)
+		assert.Equal(t, "Nutella", *resp.Savings[0].Title)
+	}
+	assert.Equal(t, 1, calls, "the second client reads the cached ad")
+
+	client := api.NewClientWithBaseURLs(srv.URL, "", api.WithDiskCache(dir))
+	_, err := client.FetchAdVersion(context.Background(), "1425")
+	require.NoError(t, err)
+	_, err = client.FetchSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "an unchanged version keeps the cached ad")
+
+	version = "2025-02-13T08:00:00"
+	_, err = client.FetchAdVersion(context.Background(), "1425")
+	require.NoError(t, err)
+	_, err = client.FetchSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls, "a new version drops the cached ad")
+
+	removed, err := api.ClearDiskCache(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	_, err = client.FetchSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestWithDiskCache_RefetchesDamagedAd(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(api.SavingsResponse{
+			Savings:                       []api.SavingItem{{ID: "1", Title: ptr("Nutella")}},
+			WeeklyAdLatestUpdatedDateTime: "2025-02-12T08:00:00",
+		})
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	client := api.NewClientWithBaseURLs(srv.URL, "", api.WithDiskCache(dir))
+	ctx := context.Background()
+
+	_, err := client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	paths, err := filepath.Glob(filepath.Join(dir, "1425-*.json"))
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	path := paths[0]
+
+	tampered := func(edit func(string) string) {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, []byte(edit(string(data))), 0o644))
+	}
+
+	tampered(func(s string) string { return strings.Replace(s, "Nutella", "Nutellb", 1) })
+	resp, err := client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	assert.Equal(t, "Nutella", *resp.Savings[0].Title)
+	assert.Equal(t, 2, calls, "an ad that does not match its hash is downloaded again")
+
+	tampered(func(s string) string { return s[:len(s)/2] })
+	_, err = client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls, "a truncated ad is downloaded again")
+
+	var f map[string]json.RawMessage
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &f))
+	require.NoError(t, os.WriteFile(path, f["ad"], 0o644))
+	_, err = client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls, "an ad without a hash is a miss")
+
+	_, err = client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls, "the saved ad is served again")
+}
+
+func TestRevalidateCachedAd(t *testing.T) {
+	calls := 0
+	version := "2025-02-12T08:00:00"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(api.SavingsResponse{
+			Savings:                       []api.SavingItem{{ID: "1", Title: ptr("Nutella")}},
+			WeeklyAdLatestUpdatedDateTime: version,
+		})
+	}))
+	defer srv.Close()
+	client := api.NewClientWithBaseURLs(srv.URL, "", api.WithDiskCache(t.TempDir()))
+	ctx := context.Background()
+
+	require.NoError(t, client.RevalidateCachedAd(ctx, "1425"))
+	assert.Zero(t, calls, "nothing cached, nothing to check")
+
+	_, err := client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	require.NoError(t, client.RevalidateCachedAd(ctx, "1425"))
+	_, err = client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "an unchanged ad is read from the cache")
+
+	version = "2025-02-13T08:00:00"
+	require.NoError(t, client.RevalidateCachedAd(ctx, "1425"))
+	resp, err := client.FetchSavings(ctx, "1425")
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls, "an updated ad is downloaded again")
+	assert.Equal(t, version, resp.WeeklyAdLatestUpdatedDateTime)
+}
+
+func TestAdWeekStart(t *testing.T) {
+	tests := map[string]string{
+		"2025-02-12": "2025-02-12", // Wednesday
+		"2025-02-15": "2025-02-12",
+		"2025-02-18": "2025-02-12", // Tuesday
+		"2025-02-19": "2025-02-19",
+	}
+	for day, want := range tests {
+		d, err := time.Parse("2006-01-02", day)
+		require.NoError(t, err)
+		assert.Equal(t, want, api.AdWeekStart(d).Format("2006-01-02"), day)
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by Client calls match one of these with errors.Is, so
+// callers can tell failures apart without reading messages.
+var (
+	// ErrRequest matches the *RequestError returned when no response
+	// arrived, such as on a timeout or a refused connection.
+	ErrRequest = errors.New("request failed")
+	// ErrUpstreamStatus matches the *StatusError returned for responses
+	// other than 200 OK.
+	ErrUpstreamStatus = errors.New("unexpected upstream status")
+	// ErrDecode matches the *MalformedResponseError returned for responses
+	// that are not the JSON pubcli expects.
+	ErrDecode = errors.New("malformed response")
+	// ErrNoStores means a store lookup found no stores. FetchStores returns
+	// an empty list rather than this; callers that need a store return it.
+	ErrNoStores = errors.New("no stores found")
+)
+
+// RequestError reports a request that got no response.
+type RequestError struct {
+	URL string
+	Err error
+}
+
+func (e *RequestError) Error() string { return fmt.Sprintf("executing request: %v", e.Err) }
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// Is makes errors.Is(err, ErrRequest) match.
+func (e *RequestError) Is(target error) bool { return target == ErrRequest }
+
+// StatusError reports a response with a status other than 200 OK.
+type StatusError struct {
+	Code int
+	URL  string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d from %s", e.Code, e.URL)
+}
+
+// Is makes errors.Is(err, ErrUpstreamStatus) match.
+func (e *StatusError) Is(target error) bool { return target == ErrUpstreamStatus }
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// harRedacted replaces secret header and query values in a HAR capture.
+const harRedacted = "REDACTED"
+
+// harSecretHeaders are headers whose values never appear in a capture.
+var harSecretHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+	"x-auth-token":        true,
+}
+
+// harSecretParams are query parameter name fragments whose values are
+// redacted, e.g. "token" also covers "access_token".
+var harSecretParams = []string{"key", "token", "secret", "password", "signature", "sig", "auth"}
+
+// HARRecorder is an http.RoundTripper that records every request and
+// response passing through it, for writing out as a HAR 1.2 archive. Secret
+// headers and query parameters are redacted as they are recorded.
+type HARRecorder struct {
+	// Transport performs the requests; nil uses http.DefaultTransport.
+	Transport http.RoundTripper
+	// SecretHeaders names headers to redact on top of the built-in list,
+	// such as session headers added with WithHeaders.
+	SecretHeaders []string
+
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+// WithHAR records the client's traffic with rec. It wraps the transport of
+// the HTTP client in effect, so pass it after WithHTTPClient.
+func WithHAR(rec *HARRecorder) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		rec.mu.Lock()
+		if rec.Transport == nil {
+			rec.Transport = hc.Transport
+		}
+		rec.mu.Unlock()
+		hc.Transport = rec
+		c.httpClient = &hc
+	}
+}
+
+// RoundTrip performs req and records it.
+func (r *HARRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	started := time.Now()
+	resp, err := transport.RoundTrip(req)
+
+	entry := harEntry{
+		StartedDateTime: started.UTC().Format(time.RFC3339Nano),
+		Request:         harRequestOf(req, reqBody, r.SecretHeaders),
+		Cache:           struct{}{},
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		entry.Response = harResponse{HTTPVersion: "HTTP/1.1", Headers: []harNameValue{}, Cookies: []harNameValue{}, HeadersSize: -1, BodySize: -1}
+	} else {
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			// Hand the caller the same failure it would have seen.
+			entry.Error = readErr.Error()
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{readErr}))
+		} else {
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		entry.Response = harResponseOf(resp, body, r.SecretHeaders)
+	}
+	elapsed := float64(time.Since(started).Microseconds()) / 1000
+	entry.Time = elapsed
+	entry.Timings = harTimings{Send: 0, Wait: elapsed, Receive: 0}
+
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+	return resp, err
+}
+
+// Len returns the number of recorded requests.
+func (r *HARRecorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// WriteTo writes the recorded traffic as a HAR 1.2 JSON document.
+func (r *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	entries := append([]harEntry{}, r.entries...)
+	r.mu.Unlock()
+
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "pubcli", Version: buildVersion()},
+		Entries: entries,
+	}}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// Save writes the recorded traffic to path.
+func (r *HARRecorder) Save(path string) error {
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+func buildVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) { return 0, e.err }
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	// Error is set when the request failed without a response. Custom HAR
+	// fields start with an underscore.
+	Error string `json:"_error,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func harRequestOf(req *http.Request, body []byte, secretHeaders []string) harRequest {
+	u := *req.URL
+	query := u.Query()
+	params := []harNameValue{}
+	for name, values := range query {
+		secret := isSecretParam(name)
+		for i, v := range values {
+			if secret {
+				values[i] = harRedacted
+				v = harRedacted
+			}
+			params = append(params, harNameValue{Name: name, Value: v})
+		}
+	}
+	sortNameValues(params)
+	if u.RawQuery != "" {
+		u.RawQuery = query.Encode()
+	}
+	u.User = nil
+
+	out := harRequest{
+		Method:      req.Method,
+		URL:         u.String(),
+		HTTPVersion: protoOrDefault(req.Proto),
+		Headers:     harHeaders(req.Header, secretHeaders),
+		QueryString: params,
+		Cookies:     []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	if body != nil {
+		out.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: string(body)}
+	}
+	return out
+}
+
+func harResponseOf(resp *http.Response, body []byte, secretHeaders []string) harResponse {
+	mimeType := resp.Header.Get("Content-Type")
+	content := harContent{Size: len(body), MimeType: mimeType}
+	if isTextContent(mimeType) && utf8.Valid(body) {
+		content.Text = string(body)
+	} else if len(body) > 0 {
+		content.Text = base64.StdEncoding.EncodeToString(body)
+		content.Encoding = "base64"
+	}
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  statusText(resp),
+		HTTPVersion: protoOrDefault(resp.Proto),
+		Headers:     harHeaders(resp.Header, secretHeaders),
+		Cookies:     []harNameValue{},
+		Content:     content,
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+// statusText returns "OK" for a "200 OK" response.
+func statusText(resp *http.Response) string {
+	if text := strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)+" "); text != resp.Status {
+		return text
+	}
+	return http.StatusText(resp.StatusCode)
+}
+
+func harHeaders(h http.Header, secretHeaders []string) []harNameValue {
+	headers := []harNameValue{}
+	for name, values := range h {
+		secret := harSecretHeaders[strings.ToLower(name)]
+		for _, s := range secretHeaders {
+			secret = secret || strings.EqualFold(name, s)
+		}
+		for _, v := range values {
+			if secret {
+				v = harRedacted
+			}
+			headers = append(headers, harNameValue{Name: name, Value: v})
+		}
+	}
+	sortNameValues(headers)
+	return headers
+}
+
+func isSecretParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, fragment := range harSecretParams {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTextContent(mimeType string) bool {
+	media, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return mimeType == ""
+	}
+	return strings.HasPrefix(media, "text/") || strings.HasSuffix(media, "json") || strings.HasSuffix(media, "xml")
+}
+
+func protoOrDefault(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+func sortNameValues(list []harNameValue) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Value < list[j].Value
+	})
+}
@@ -0,0 +1,115 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+type harFile struct {
+	Log struct {
+		Version string `json:"version"`
+		Entries []struct {
+			Request struct {
+				URL         string `json:"url"`
+				Headers     []struct{ Name, Value string }
+				QueryString []struct{ Name, Value string }
+			} `json:"request"`
+			Response struct {
+				Status  int `json:"status"`
+				Content struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+			Error string `json:"_error"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+func decodeHAR(t *testing.T, rec *api.HARRecorder) harFile {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := rec.WriteTo(&buf)
+	require.NoError(t, err)
+	var har harFile
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &har))
+	return har
+}
+
+func TestHARRecorder_RecordsClientTraffic(t *testing.T) {
+	items := []api.SavingItem{{ID: "1", Title: ptr("Chicken Breasts")}}
+	srv := newTestSavingsServer(t, "1425", items)
+	defer srv.Close()
+
+	rec := &api.HARRecorder{}
+	client := api.NewClientWithBaseURLs(srv.URL, srv.URL, api.WithHAR(rec))
+	data, err := client.FetchSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	require.Len(t, data.Savings, 1, "the caller still gets the full response")
+
+	har := decodeHAR(t, rec)
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Entries, 1)
+	entry := har.Log.Entries[0]
+	assert.Equal(t, 200, entry.Response.Status)
+	assert.Contains(t, entry.Response.Content.Text, "Chicken Breasts")
+	assert.Empty(t, entry.Response.Content.Encoding)
+	assert.Contains(t, entry.Request.Headers, struct{ Name, Value string }{"Publixstore", "1425"})
+}
+
+func TestHARRecorder_RedactsSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cret"})
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G', 0xff})
+	}))
+	defer srv.Close()
+
+	rec := &api.HARRecorder{}
+	hc := &http.Client{Transport: rec}
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/img?access_token=abc123&w=150", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer abc123")
+	resp, err := hc.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Len(t, body, 5)
+
+	var buf bytes.Buffer
+	_, err = rec.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "abc123")
+	assert.NotContains(t, buf.String(), "s3cret")
+
+	har := decodeHAR(t, rec)
+	entry := har.Log.Entries[0]
+	assert.Contains(t, entry.Request.URL, "access_token=REDACTED")
+	assert.Contains(t, entry.Request.URL, "w=150")
+	assert.Equal(t, "base64", entry.Response.Content.Encoding, "binary bodies are base64")
+}
+
+func TestHARRecorder_RecordsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	srv.Close()
+
+	rec := &api.HARRecorder{}
+	client := api.NewClientWithBaseURLs(srv.URL, srv.URL, api.WithHAR(rec))
+	_, err := client.FetchSavings(context.Background(), "1425")
+	require.Error(t, err)
+
+	har := decodeHAR(t, rec)
+	require.Len(t, har.Log.Entries, 1)
+	assert.NotEmpty(t, har.Log.Entries[0].Error)
+	assert.Zero(t, har.Log.Entries[0].Response.Status)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WithHeaders adds h to every request. They are set after the client's own
+// headers, so they can also replace its User-Agent.
+func WithHeaders(h http.Header) Option {
+	return func(c *Client) {
+		c.headers = h.Clone()
+	}
+}
+
+// ParseHeader parses a "Name: Value" header as given to --header.
+func ParseHeader(raw string) (name, value string, err error) {
+	name, value, ok := strings.Cut(raw, ":")
+	name = strings.TrimSpace(name)
+	if !ok || !validHeaderName(name) {
+		return "", "", fmt.Errorf(`header %q must look like "Name: value"`, raw)
+	}
+	return http.CanonicalHeaderKey(name), strings.TrimSpace(value), nil
+}
+
+// validHeaderName reports whether name is an HTTP token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", userAgent)
+	for name, values := range c.headers {
+		req.Header[name] = values
+	}
+}
+
+// WithCookieJar sends and stores cookies through jar, for requests that
+// need a Publix session. Pass it after WithHTTPClient.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Jar = jar
+		c.httpClient = &hc
+	}
+}
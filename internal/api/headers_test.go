@@ -0,0 +1,62 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestParseHeader(t *testing.T) {
+	name, value, err := api.ParseHeader("x-store-session:  abc123 ")
+	require.NoError(t, err)
+	assert.Equal(t, "X-Store-Session", name)
+	assert.Equal(t, "abc123", value)
+
+	for _, raw := range []string{"no colon", ": value", "bad name: value"} {
+		_, _, err := api.ParseHeader(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestWithHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_ = json.NewEncoder(w).Encode(api.StoreResponse{})
+	}))
+	defer srv.Close()
+
+	headers := http.Header{}
+	headers.Set("User-Agent", "pubcli-test")
+	headers.Set("X-Experiment", "weekly-ad-b")
+	client := api.NewClientWithBaseURLs("", srv.URL, api.WithHeaders(headers))
+	headers.Set("X-Experiment", "changed later")
+
+	_, err := client.FetchStores(context.Background(), "33101", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pubcli-test", got.Get("User-Agent"))
+	assert.Equal(t, "weekly-ad-b", got.Get("X-Experiment"))
+	assert.Equal(t, "application/json", got.Get("Accept"))
+}
+
+func TestHARRecorder_RedactsAddedHeaders(t *testing.T) {
+	srv := newTestStoreServer(t, nil)
+	defer srv.Close()
+
+	headers := http.Header{}
+	headers.Set("X-Store-Session", "s3cret")
+	rec := &api.HARRecorder{SecretHeaders: []string{"X-Store-Session"}}
+	client := api.NewClientWithBaseURLs("", srv.URL, api.WithHeaders(headers), api.WithHAR(rec))
+	_, err := client.FetchStores(context.Background(), "33101", 1)
+	require.NoError(t, err)
+
+	har := decodeHAR(t, rec)
+	require.Len(t, har.Log.Entries, 1)
+	assert.Contains(t, har.Log.Entries[0].Request.Headers, struct{ Name, Value string }{"X-Store-Session", "REDACTED"})
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reClockTime matches a time of day such as "7:00 AM", "10 p.m.", or "7am".
+var reClockTime = regexp.MustCompile(`(?i)(\d{1,2})(?::(\d{2}))?\s*([ap])\.?\s*m\b\.?`)
+
+var storeDateLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"1/2/2006",
+	"01/02/2006 15:04:05",
+}
+
+// TemporarilyClosed reports whether the locator marks the store as closed.
+func (s Store) TemporarilyClosed() bool {
+	return strings.Contains(strings.ToLower(s.Status), "closed")
+}
+
+// OpenAt reports whether the store is open at t, read as local time at the
+// store. known is false when the locator sent no hours it could read.
+func (s Store) OpenAt(t time.Time) (open, known bool) {
+	if s.TemporarilyClosed() {
+		return false, true
+	}
+	if opening, ok := parseStoreDate(s.OpeningDate); ok && t.Before(opening) {
+		return false, true
+	}
+	if closing, ok := parseStoreDate(s.ClosingDate); ok && !t.Before(closing) {
+		return false, true
+	}
+
+	hours := strings.ToLower(s.Hours)
+	if strings.Contains(hours, "24 hours") {
+		return true, true
+	}
+	times := reClockTime.FindAllStringSubmatch(hours, 2)
+	if len(times) < 2 {
+		return false, false
+	}
+	now := t.Hour()*60 + t.Minute()
+	opens, closes := clockMinutes(times[0]), clockMinutes(times[1])
+	if closes <= opens {
+		// Open past midnight.
+		return now >= opens || now < closes, true
+	}
+	return now >= opens && now < closes, true
+}
+
+// clockMinutes converts a reClockTime match to minutes after midnight.
+func clockMinutes(m []string) int {
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	hour %= 12
+	if strings.EqualFold(m[3], "p") {
+		hour += 12
+	}
+	return hour*60 + minute
+}
+
+func parseStoreDate(raw string) (time.Time, bool) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range storeDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
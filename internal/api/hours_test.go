@@ -0,0 +1,40 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestStoreOpenAt(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 16, hour, minute, 0, 0, time.Local)
+	}
+	cases := []struct {
+		name  string
+		store api.Store
+		now   time.Time
+		open  bool
+		known bool
+	}{
+		{"during hours", api.Store{Hours: "7:00 AM - 10:00 PM"}, at(12, 0), true, true},
+		{"before opening", api.Store{Hours: "7:00 AM - 10:00 PM"}, at(6, 59), false, true},
+		{"at closing", api.Store{Hours: "7:00 AM - 10:00 PM"}, at(22, 0), false, true},
+		{"compact times", api.Store{Hours: "7am-9pm"}, at(20, 30), true, true},
+		{"past midnight", api.Store{Hours: "6:00 AM - 1:00 AM"}, at(0, 30), true, true},
+		{"open all day", api.Store{Hours: "Open 24 Hours"}, at(3, 0), true, true},
+		{"temporarily closed", api.Store{Hours: "7:00 AM - 10:00 PM", Status: "Temporarily Closed"}, at(12, 0), false, true},
+		{"not yet open", api.Store{Hours: "7:00 AM - 10:00 PM", OpeningDate: "2026-11-01T00:00:00"}, at(12, 0), false, true},
+		{"closed for good", api.Store{Hours: "7:00 AM - 10:00 PM", ClosingDate: "10/1/2026"}, at(12, 0), false, true},
+		{"no hours", api.Store{}, at(12, 0), false, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			open, known := tc.store.OpenAt(tc.now)
+			assert.Equal(t, tc.open, open)
+			assert.Equal(t, tc.known, known)
+		})
+	}
+}
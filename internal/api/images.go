@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// LargeImageWidth is the width requested from image URLs that take a size
+// parameter.
+const LargeImageWidth = 1200
+
+var (
+	imageWidthParams  = []string{"w", "width", "wid", "maxwidth"}
+	imageHeightParams = []string{"h", "height", "hei", "maxheight"}
+
+	// imageSizeSuffix matches a size marker at the end of an image file
+	// name, as in coffee_thumb.jpg or coffee-150x150.png.
+	imageSizeSuffix = regexp.MustCompile(`(?i)[-_](thumb|thumbnail|small|sm|medium|med|\d{2,4}x\d{2,4})(\.[a-z]+)$`)
+)
+
+// ImageVariants returns candidate URLs for an image, highest resolution
+// first and the original last. The ad's image URLs often point at a small
+// rendition; size query parameters are raised to LargeImageWidth and size
+// suffixes in the file name are swapped for "large" or dropped. The guesses
+// are not checked here, so callers that need a working URL should try them
+// in order. URLs without a recognized size marker yield only themselves.
+func ImageVariants(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return []string{raw}
+	}
+
+	var variants []string
+	if resized, ok := resizeImageQuery(*u); ok {
+		variants = append(variants, resized)
+	}
+	if m := imageSizeSuffix.FindStringSubmatchIndex(u.Path); m != nil {
+		ext := u.Path[m[4]:m[5]]
+		prefix := u.Path[:m[0]]
+		for _, p := range []string{prefix + "_large" + ext, prefix + ext} {
+			v := *u
+			v.Path, v.RawPath = p, ""
+			variants = append(variants, v.String())
+		}
+	}
+	return append(variants, raw)
+}
+
+// LargeImageURL returns the best high-resolution guess for an image URL,
+// which is the URL itself when it carries no size marker.
+func LargeImageURL(raw string) string {
+	if variants := ImageVariants(raw); len(variants) > 0 {
+		return variants[0]
+	}
+	return ""
+}
+
+func resizeImageQuery(u url.URL) (string, bool) {
+	q := u.Query()
+	changed := false
+	for _, key := range imageWidthParams {
+		if q.Has(key) {
+			q.Set(key, strconv.Itoa(LargeImageWidth))
+			changed = true
+		}
+	}
+	if !changed {
+		return "", false
+	}
+	// Dropping the height keeps the aspect ratio on CDNs that scale to fit.
+	for _, key := range imageHeightParams {
+		q.Del(key)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), true
+}
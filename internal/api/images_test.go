@@ -0,0 +1,27 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestImageVariants(t *testing.T) {
+	assert.Nil(t, api.ImageVariants(""))
+	assert.Equal(t, []string{"https://cdn.example.com/coffee.jpg"}, api.ImageVariants("https://cdn.example.com/coffee.jpg"))
+
+	assert.Equal(t, []string{
+		"https://cdn.example.com/coffee.jpg?quality=80&w=1200",
+		"https://cdn.example.com/coffee.jpg?w=150&h=150&quality=80",
+	}, api.ImageVariants("https://cdn.example.com/coffee.jpg?w=150&h=150&quality=80"))
+
+	assert.Equal(t, []string{
+		"https://cdn.example.com/img/coffee_large.png",
+		"https://cdn.example.com/img/coffee.png",
+		"https://cdn.example.com/img/coffee-150x150.png",
+	}, api.ImageVariants("https://cdn.example.com/img/coffee-150x150.png"))
+
+	assert.Equal(t, "https://cdn.example.com/a_large.JPG", api.LargeImageURL("https://cdn.example.com/a_thumb.JPG"))
+	assert.Equal(t, "not a url", api.LargeImageURL("not a url"))
+}
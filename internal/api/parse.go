@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MalformedResponseError reports an API response body that could not be
+// decoded, such as an HTML error page, a truncated body, or a field of the
+// wrong type.
+type MalformedResponseError struct {
+	// Response names the API that sent the body: "savings" or "stores".
+	Response string
+	// Reason says what is wrong, e.g. "truncated JSON".
+	Reason string
+	// Offset is the byte offset the problem was found at.
+	Offset int64
+	// Err is the underlying decoding error, if any.
+	Err error
+}
+
+func (e *MalformedResponseError) Error() string {
+	return fmt.Sprintf("malformed %s response: %s at byte %d", e.Response, e.Reason, e.Offset)
+}
+
+func (e *MalformedResponseError) Unwrap() error { return e.Err }
+
+// Is makes errors.Is(err, ErrDecode) match.
+func (e *MalformedResponseError) Is(target error) bool { return target == ErrDecode }
+
+// ParseSavingsResponse decodes a savings API response body and normalizes
+// its deals as FetchSavings does. Bodies that are not a single JSON object
+// of the expected shape return a *MalformedResponseError.
+func ParseSavingsResponse(data []byte) (*SavingsResponse, error) {
+	var resp SavingsResponse
+	if err := decodeResponse("savings", data, &resp); err != nil {
+		return nil, err
+	}
+	normalizeSavings(resp.Savings)
+	ResolveDates(resp.Savings, time.Now())
+	return &resp, nil
+}
+
+// ParseStoreResponse decodes a store locator response body. Bodies that
+// are not a single JSON object of the expected shape return a
+// *MalformedResponseError.
+func ParseStoreResponse(data []byte) (*StoreResponse, error) {
+	var resp StoreResponse
+	if err := decodeResponse("stores", data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func decodeResponse(name string, data []byte, out any) error {
+	malformed := func(reason string, offset int64, err error) error {
+		return &MalformedResponseError{Response: name, Reason: reason, Offset: offset, Err: err}
+	}
+
+	start := int64(len(data) - len(bytes.TrimLeft(data, " \t\r\n")))
+	switch {
+	case start == int64(len(data)):
+		return malformed("empty body", start, nil)
+	case data[start] != '{':
+		return malformed("not a JSON object", start, nil)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(out); err != nil {
+		var syntax *json.SyntaxError
+		var typ *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntax):
+			return malformed("invalid JSON: "+syntax.Error(), syntax.Offset, err)
+		case errors.As(err, &typ):
+			return malformed(fmt.Sprintf("%s is a JSON %s, want %s", typ.Field, typ.Value, typ.Type), typ.Offset, err)
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return malformed("truncated JSON", int64(len(data)), err)
+		default:
+			return malformed(err.Error(), dec.InputOffset(), err)
+		}
+	}
+	end := dec.InputOffset()
+	if err := dec.Decode(new(json.RawMessage)); !errors.Is(err, io.EOF) {
+		return malformed("trailing content after the JSON object", end, err)
+	}
+	return nil
+}
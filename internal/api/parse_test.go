@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/fixtures"
+)
+
+func TestParseSavingsResponse(t *testing.T) {
+	resp, err := api.ParseSavingsResponse([]byte(`{"Savings":[{"id":"1","title":"Coffee","categories":["BOGO"],` +
+		`"wa_startDateFormatted":"10/14","wa_endDateFormatted":"10/20"}],"WeeklyAdLatestUpdatedDateTime":"2026-10-14T06:00:00"}`))
+	require.NoError(t, err)
+	require.Len(t, resp.Savings, 1)
+	assert.Equal(t, []string{"bogo"}, resp.Savings[0].Categories)
+	assert.False(t, resp.Savings[0].End.IsZero())
+	assert.Equal(t, "2026-10-14T06:00:00", resp.WeeklyAdLatestUpdatedDateTime)
+}
+
+func TestParseResponse_Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+		offset int64
+	}{
+		{"empty", "", "empty body", 0},
+		{"whitespace", " \n", "empty body", 2},
+		{"html error page", "<html><body>Service Unavailable</body></html>", "not a JSON object", 0},
+		{"null", "null", "not a JSON object", 0},
+		{"array", `[{"id":"1"}]`, "not a JSON object", 0},
+		{"truncated", `{"Savings":[{"id":"1"`, "truncated JSON", 21},
+		{"syntax", `{"Savings":[}`, "invalid JSON: invalid character '}' looking for beginning of value", 13},
+		{"wrong type", `{"Savings":{"id":"1"}}`, "Savings is a JSON object, want []api.SavingItem", 12},
+		{"trailing", `{"Savings":[]} {}`, "trailing content after the JSON object", 14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := api.ParseSavingsResponse([]byte(tt.body))
+			var malformed *api.MalformedResponseError
+			require.ErrorAs(t, err, &malformed)
+			assert.ErrorIs(t, err, api.ErrDecode)
+			assert.Equal(t, "savings", malformed.Response)
+			assert.Equal(t, tt.reason, malformed.Reason)
+			assert.Equal(t, tt.offset, malformed.Offset)
+		})
+	}
+}
+
+func TestParseStoreResponse(t *testing.T) {
+	resp, err := api.ParseStoreResponse([]byte(`{"Stores":[{"KEY":"01425","NAME":"Peachers Mill"}]}`))
+	require.NoError(t, err)
+	require.Len(t, resp.Stores, 1)
+	assert.Equal(t, "01425", resp.Stores[0].Key)
+
+	_, err = api.ParseStoreResponse([]byte(`{"Stores":[{"KEY":1425}]}`))
+	assert.EqualError(t, err, "malformed stores response: Stores.0.KEY is a JSON number, want string at byte 22")
+}
+
+func fuzzSeeds(f *testing.F) ([]byte, []byte) {
+	f.Helper()
+	savings, stores := fixtures.Generate(fixtures.Options{Deals: 3, Stores: 2, Seed: 1, WeekStart: time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)})
+	s, err := json.Marshal(savings)
+	require.NoError(f, err)
+	st, err := json.Marshal(stores)
+	require.NoError(f, err)
+	return s, st
+}
+
+func FuzzParseSavingsResponse(f *testing.F) {
+	savings, _ := fuzzSeeds(f)
+	f.Add(savings)
+	for _, seed := range []string{"", "null", "<html></html>", `{"Savings":null}`, `{"Savings":[{"title":null}]}`, `{"Savings":[`} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		resp, err := api.ParseSavingsResponse(data)
+		if err != nil {
+			assert.ErrorIs(t, err, api.ErrDecode)
+			return
+		}
+		assert.NotNil(t, resp)
+	})
+}
+
+func FuzzParseStoreResponse(f *testing.F) {
+	_, stores := fuzzSeeds(f)
+	f.Add(stores)
+	for _, seed := range []string{"", "[]", `{"Stores":[{"KEY":"01425"}]}`, `{"Stores":{}}`, `{"Stores":[]} x`} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		resp, err := api.ParseStoreResponse(data)
+		if err != nil {
+			assert.ErrorIs(t, err, api.ErrDecode)
+			return
+		}
+		assert.NotNil(t, resp)
+	})
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultRetries is how many times a failed request is sent again.
+	DefaultRetries = 2
+	// DefaultRetryBackoff is the wait before the first retry; each later
+	// retry waits twice as long as the one before.
+	DefaultRetryBackoff = 500 * time.Millisecond
+)
+
+// WithRetries sends a request that failed with a network error, a 429, or
+// a 5xx status up to n more times, waiting backoff before the first retry
+// and doubling the wait after each. Retries stop early when the context is
+// done or a WithBreaker breaker opens, so a half-open trial call is sent
+// once.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = n
+		c.retryBackoff = backoff
+	}
+}
+
+// fetchWithRetries is fetchBody guarded by the breaker and retried as
+// WithRetries configures. It returns how many times the request was sent,
+// 0 when an open breaker refused it. The breaker sees one outcome per
+// request, not one per attempt, so a single failing store cannot open it.
+func (c *Client) fetchWithRetries(ctx context.Context, reqURL, storeNumber string) ([]byte, int, error) {
+	if c.breaker != nil {
+		if err := c.breaker.Allow(); err != nil {
+			return nil, 0, err
+		}
+	}
+	wait := c.retryBackoff
+	attempt := 1
+	body, err := c.fetchBody(ctx, reqURL, storeNumber)
+	for err != nil && attempt <= c.retries && retryable(err) && c.breakerAllows() && sleep(ctx, wait) {
+		attempt++
+		wait *= 2
+		body, err = c.fetchBody(ctx, reqURL, storeNumber)
+	}
+	if c.breaker != nil {
+		status, transportErr := outcome(err)
+		c.breaker.Record(ctx, status, transportErr)
+	}
+	return body, attempt, err
+}
+
+func (c *Client) breakerAllows() bool {
+	return c.breaker == nil || c.breaker.Allow() == nil
+}
+
+// sleep waits d and reports whether ctx was still live afterwards.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// retryable reports whether a request that failed with err may succeed
+// when sent again.
+func retryable(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code == http.StatusTooManyRequests || status.Code >= http.StatusInternalServerError
+	}
+	return errors.Is(err, ErrRequest)
+}
+
+// outcome turns a fetchBody error into the status and transport error
+// Breaker.Record takes.
+func outcome(err error) (int, error) {
+	var status *StatusError
+	switch {
+	case errors.As(err, &status):
+		return status.Code, nil
+	case errors.Is(err, ErrRequest):
+		return 0, err
+	default:
+		return http.StatusOK, nil
+	}
+}
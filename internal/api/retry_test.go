@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestWithRetries_RetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"Savings":[]}`))
+	}))
+	defer srv.Close()
+
+	var attempts int
+	client := api.NewClientWithBaseURLs(srv.URL, srv.URL,
+		api.WithRetries(2, time.Millisecond),
+		api.WithObserver(func(_ string, n int, _ error) { attempts = n }))
+	_, err := client.FetchSavings(context.Background(), "1425")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, 3, attempts)
+}
+
+func TestWithRetries_LeavesClientErrorsAlone(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := api.NewClientWithBaseURLs(srv.URL, srv.URL, api.WithRetries(2, time.Millisecond))
+	_, err := client.FetchSavings(context.Background(), "1425")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWithRetries_BreakerCountsRequestsNotAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	breaker := &api.Breaker{Threshold: 2, Cooldown: time.Minute}
+	client := api.NewClientWithBaseURLs(srv.URL, srv.URL, api.WithBreaker(breaker), api.WithRetries(2, time.Millisecond))
+	_, err := client.FetchSavings(context.Background(), "1425")
+	require.Error(t, err)
+	_, err = client.FetchSavings(context.Background(), "1425")
+	assert.False(t, errors.Is(err, api.ErrCircuitOpen), "one retried request is one failure")
+	_, err = client.FetchSavings(context.Background(), "1425")
+	assert.ErrorIs(t, err, api.ErrCircuitOpen)
+}
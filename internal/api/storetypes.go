@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultStoreTypes are the store locator's location type codes that store
+// lookups search unless WithStoreTypes says otherwise.
+const DefaultStoreTypes = "R,G,H,N,S"
+
+// StoreType is a named store locator location type.
+type StoreType struct {
+	Name        string
+	Code        string
+	Description string
+}
+
+// StoreTypes lists the location types that can be selected by name. Other
+// locator types can still be selected by their one-letter code.
+var StoreTypes = []StoreType{
+	{Name: "regular", Code: "R", Description: "Publix supermarkets"},
+	{Name: "greenwise", Code: "G", Description: "GreenWise Market stores"},
+	{Name: "pharmacy", Code: "H", Description: "Publix Pharmacy locations"},
+	{Name: "sabor", Code: "S", Description: "Publix Sabor stores"},
+	{Name: "liquor", Code: "L", Description: "Publix Liquors stores"},
+}
+
+// ParseStoreTypes converts a comma-separated list of store type names or
+// one-letter codes, such as "regular,greenwise" or "R,L", to the locator's
+// type codes. Duplicates are dropped.
+func ParseStoreTypes(raw string) (string, error) {
+	var codes []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		code, ok := storeTypeCode(part)
+		if !ok {
+			return "", fmt.Errorf("unknown store type %q", part)
+		}
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return "", fmt.Errorf("no store types given")
+	}
+	return strings.Join(codes, ","), nil
+}
+
+func storeTypeCode(s string) (string, bool) {
+	for _, t := range StoreTypes {
+		if strings.EqualFold(s, t.Name) {
+			return t.Code, true
+		}
+	}
+	if len(s) == 1 && (s[0]|0x20) >= 'a' && (s[0]|0x20) <= 'z' {
+		return strings.ToUpper(s), true
+	}
+	return "", false
+}
+
+// WithStoreTypes limits store lookups to the given comma-separated location
+// type codes, as returned by ParseStoreTypes.
+func WithStoreTypes(codes string) Option {
+	return func(c *Client) {
+		c.storeTypes = codes
+	}
+}
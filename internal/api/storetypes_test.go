@@ -0,0 +1,44 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+func TestParseStoreTypes(t *testing.T) {
+	got, err := api.ParseStoreTypes("regular, GreenWise,liquor,regular")
+	require.NoError(t, err)
+	assert.Equal(t, "R,G,L", got)
+
+	got, err = api.ParseStoreTypes("n,S")
+	require.NoError(t, err)
+	assert.Equal(t, "N,S", got)
+
+	_, err = api.ParseStoreTypes("bakery")
+	assert.ErrorContains(t, err, `unknown store type "bakery"`)
+	_, err = api.ParseStoreTypes(" , ")
+	assert.Error(t, err)
+}
+
+func TestFetchStores_StoreTypes(t *testing.T) {
+	var types []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		types = append(types, r.URL.Query().Get("types"))
+		_ = json.NewEncoder(w).Encode(api.StoreResponse{})
+	}))
+	defer srv.Close()
+
+	_, err := api.NewClientWithBaseURLs("", srv.URL).FetchStores(context.Background(), "33101", 1)
+	require.NoError(t, err)
+	_, err = api.NewClientWithBaseURLs("", srv.URL, api.WithStoreTypes("G,L")).FetchStores(context.Background(), "33101", 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{api.DefaultStoreTypes, "G,L"}, types)
+}
@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 // SavingsResponse is the top-level response from the Publix savings API.
 type SavingsResponse struct {
 	Savings                       []SavingItem `json:"Savings"`
@@ -21,6 +23,26 @@ type SavingItem struct {
 	ImageURL           *string  `json:"imageUrl"`
 	StartFormatted     string   `json:"wa_startDateFormatted"`
 	EndFormatted       string   `json:"wa_endDateFormatted"`
+
+	// Start and End are the first and last days the deal is valid, at
+	// midnight local time, parsed from StartFormatted and EndFormatted when
+	// the ad is fetched. They are zero when a date could not be parsed or
+	// the item was not decoded by FetchSavings.
+	Start time.Time `json:"-"`
+	End   time.Time `json:"-"`
+
+	// Stores lists the store numbers carrying the deal. It is only set when
+	// the ads of several stores are merged.
+	Stores []string `json:"-"`
+
+	// Flags says why the household profile in the config file flags the
+	// deal, e.g. "peanut" or "not vegetarian". It is set when the ad is
+	// fetched.
+	Flags []string `json:"-"`
+
+	// IsNew says whether the deal was missing from the store's previous ad.
+	// It is nil unless the ad was compared with an earlier one.
+	IsNew *bool `json:"-"`
 }
 
 // StoreResponse is the top-level response from the store locator API.
@@ -38,4 +60,13 @@ type Store struct {
 	Zip      string `json:"ZIP"`
 	Distance string `json:"DISTANCE"`
 	Phone    string `json:"PHONE"`
+
+	// Hours is the store's daily hours, e.g. "7:00 AM - 10:00 PM".
+	Hours string `json:"STRHOURS"`
+	// OpeningDate and ClosingDate are set for stores not yet open or closing
+	// for good; the locator sends them for includeOpenAndCloseDates.
+	OpeningDate string `json:"OPENINGDATE"`
+	ClosingDate string `json:"CLOSINGDATE"`
+	// Status is the locator's store status, e.g. "Temporarily Closed".
+	Status string `json:"STATUS"`
 }
@@ -0,0 +1,111 @@
+// Package cmdhistory keeps the pubcli invocations that succeeded, so they can
+// be listed and run again.
+package cmdhistory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// FileName is the command history's file name inside the data
+	// directory.
+	FileName = "commands.json"
+	// MaxEntries is how many invocations are kept; older ones are dropped.
+	MaxEntries = 500
+)
+
+// Entry is one successful invocation.
+type Entry struct {
+	// Args are the arguments after "pubcli".
+	Args []string  `json:"args"`
+	At   time.Time `json:"at"`
+}
+
+// Command is the invocation as a shell command line.
+func (e Entry) Command() string {
+	words := make([]string, 0, len(e.Args)+1)
+	words = append(words, "pubcli")
+	for _, arg := range e.Args {
+		words = append(words, quote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// quote single-quotes a word the shell would otherwise split or expand.
+func quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+type file struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Load returns the history at path, oldest first. A missing file is an empty
+// history.
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading command history: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing command history %s: %w", path, err)
+	}
+	return f.Entries, nil
+}
+
+// Append adds an invocation to the history at path. Repeating the last
+// invocation only updates its time, as shells ignore duplicates.
+func Append(path string, e Entry) error {
+	entries, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if n := len(entries); n > 0 && slices.Equal(entries[n-1].Args, e.Args) {
+		entries[n-1].At = e.At
+	} else {
+		entries = append(entries, e)
+	}
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+	return save(path, entries)
+}
+
+// Clear empties the history at path.
+func Clear(path string) error {
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func save(path string, entries []Entry) error {
+	data, err := json.MarshalIndent(file{Entries: entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing command history: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
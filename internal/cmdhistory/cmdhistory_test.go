@@ -0,0 +1,52 @@
+package cmdhistory_test
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/cmdhistory"
+)
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), cmdhistory.FileName)
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+
+	entries, err := cmdhistory.Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, cmdhistory.Append(path, cmdhistory.Entry{Args: []string{"--zip", "33101"}, At: start}))
+	require.NoError(t, cmdhistory.Append(path, cmdhistory.Entry{Args: []string{"bogo"}, At: start.Add(time.Minute)}))
+	require.NoError(t, cmdhistory.Append(path, cmdhistory.Entry{Args: []string{"bogo"}, At: start.Add(2 * time.Minute)}))
+
+	entries, err = cmdhistory.Load(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "repeats are kept once")
+	assert.Equal(t, []string{"--zip", "33101"}, entries[0].Args)
+	assert.Equal(t, start.Add(2*time.Minute), entries[1].At)
+
+	require.NoError(t, cmdhistory.Clear(path))
+	entries, err = cmdhistory.Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAppend_KeepsTheNewest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), cmdhistory.FileName)
+	for i := range cmdhistory.MaxEntries + 3 {
+		require.NoError(t, cmdhistory.Append(path, cmdhistory.Entry{Args: []string{"--limit", strconv.Itoa(i)}}))
+	}
+	entries, err := cmdhistory.Load(path)
+	require.NoError(t, err)
+	require.Len(t, entries, cmdhistory.MaxEntries)
+	assert.Equal(t, "3", entries[0].Args[1])
+}
+
+func TestEntry_Command(t *testing.T) {
+	e := cmdhistory.Entry{Args: []string{"--zip", "33101", "-q", "ice cream", "--category", "it's"}}
+	assert.Equal(t, `pubcli --zip 33101 -q 'ice cream' --category 'it'\''s'`, e.Command())
+}
@@ -0,0 +1,140 @@
+// Package compare ranks nearby stores by how well their weekly ads match a
+// set of deal filters.
+package compare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+var (
+	// ErrNoStores means the store lookup returned nothing for the ZIP code.
+	ErrNoStores = api.ErrNoStores
+	// ErrNoMatches means no store's ad had deals matching the filters.
+	ErrNoMatches = errors.New("no stores have deals matching your filters")
+)
+
+// Source provides store lookups and weekly ads. *api.Client satisfies it.
+type Source interface {
+	FetchStores(ctx context.Context, zipCode string, count int) ([]api.Store, error)
+	FetchSavings(ctx context.Context, storeNumber string) (*api.SavingsResponse, error)
+}
+
+// UpstreamError reports a failed Publix API call during a comparison.
+type UpstreamError struct {
+	Action string
+	Err    error
+}
+
+func (e *UpstreamError) Error() string { return fmt.Sprintf("%s: %v", e.Action, e.Err) }
+func (e *UpstreamError) Unwrap() error { return e.Err }
+
+// Result is one ranked store.
+type Result struct {
+	Rank         int     `json:"rank"`
+	Number       string  `json:"number"`
+	Name         string  `json:"name"`
+	City         string  `json:"city"`
+	State        string  `json:"state"`
+	Distance     string  `json:"distance"`
+	MatchedDeals int     `json:"matchedDeals"`
+	BogoDeals    int     `json:"bogoDeals"`
+	Score        float64 `json:"score"`
+	TopDeal      string  `json:"topDeal"`
+}
+
+// Stores ranks the stores near zipCode by how well their weekly ads match
+// opts: matched deal count, then total deal score, then distance. It also
+// returns how many stores were skipped because their ad failed to load.
+// The ads are fetched concurrently; the ranking does not depend on which
+// arrives first.
+func Stores(ctx context.Context, src Source, zipCode string, count int, opts filter.Options) ([]Result, int, error) {
+	stores, err := src.FetchStores(ctx, zipCode, count)
+	if err != nil {
+		return nil, 0, &UpstreamError{Action: "fetching stores", Err: err}
+	}
+	if len(stores) == 0 {
+		return nil, 0, ErrNoStores
+	}
+
+	ads, err := fetchAds(ctx, src, stores)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]Result, 0, len(stores))
+	errCount := 0
+	for i, store := range stores {
+		storeNumber := api.StoreNumber(store.Key)
+		if ads[i].err != nil {
+			errCount++
+			continue
+		}
+
+		items := filter.Apply(ads[i].resp.Savings, opts)
+		if len(items) == 0 {
+			continue
+		}
+
+		bogoDeals := 0
+		score := 0.0
+		for _, item := range items {
+			if filter.ContainsIgnoreCase(item.Categories, "bogo") {
+				bogoDeals++
+			}
+			score += filter.DealScore(item)
+		}
+
+		results = append(results, Result{
+			Number:       storeNumber,
+			Name:         store.Name,
+			City:         store.City,
+			State:        store.State,
+			Distance:     strings.TrimSpace(store.Distance),
+			MatchedDeals: len(items),
+			BogoDeals:    bogoDeals,
+			Score:        score,
+			TopDeal:      filter.Title(items[0]),
+		})
+	}
+
+	if len(results) == 0 {
+		if errCount == len(stores) {
+			return nil, 0, &UpstreamError{Action: "fetching deals", Err: fmt.Errorf("all %d store lookups failed", len(stores))}
+		}
+		return nil, 0, ErrNoMatches
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].MatchedDeals != results[j].MatchedDeals {
+			return results[i].MatchedDeals > results[j].MatchedDeals
+		}
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return ParseDistance(results[i].Distance) < ParseDistance(results[j].Distance)
+	})
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+	return results, errCount, nil
+}
+
+// ParseDistance extracts the first number in a store distance string. Unknown
+// distances sort last.
+func ParseDistance(raw string) float64 {
+	for _, token := range strings.Fields(raw) {
+		clean := strings.Trim(token, ",")
+		if d, err := strconv.ParseFloat(clean, 64); err == nil {
+			return d
+		}
+	}
+	return 999999
+}
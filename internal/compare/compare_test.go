@@ -0,0 +1,95 @@
+package compare_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/compare"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// slowSource answers each ad after a delay, later stores first, and
+// records how many fetches ran at once.
+type slowSource struct {
+	stores        []api.Store
+	mu            sync.Mutex
+	active, peak  int
+	circuitOpenAt string
+	calls         atomic.Int32
+}
+
+func (s *slowSource) FetchStores(context.Context, string, int) ([]api.Store, error) {
+	return s.stores, nil
+}
+
+func (s *slowSource) FetchSavings(ctx context.Context, store string) (*api.SavingsResponse, error) {
+	s.calls.Add(1)
+	if store == s.circuitOpenAt {
+		return nil, &api.CircuitOpenError{Until: time.Now().Add(time.Minute)}
+	}
+	s.mu.Lock()
+	s.active++
+	s.peak = max(s.peak, s.active)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.active--
+		s.mu.Unlock()
+	}()
+
+	var n int
+	fmt.Sscanf(store, "%d", &n)
+	select {
+	case <-time.After(time.Duration(20-n) * 2 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	items := make([]api.SavingItem, n)
+	for i := range items {
+		items[i] = api.SavingItem{ID: fmt.Sprintf("%d-%d", n, i), Title: ptr("Deal")}
+	}
+	return &api.SavingsResponse{Savings: items}, nil
+}
+
+func numberedStores(n int) []api.Store {
+	stores := make([]api.Store, n)
+	for i := range stores {
+		stores[i] = api.Store{Key: fmt.Sprintf("%05d", i+1), Distance: fmt.Sprintf("%d", i+1)}
+	}
+	return stores
+}
+
+func TestStores_FetchesConcurrentlyInStableOrder(t *testing.T) {
+	src := &slowSource{stores: numberedStores(10)}
+
+	results, skipped, err := compare.Stores(context.Background(), src, "33101", 10, filter.Options{})
+	require.NoError(t, err)
+	assert.Zero(t, skipped)
+	assert.Equal(t, compare.Concurrency, src.peak)
+	require.Len(t, results, 10)
+	for i, r := range results {
+		assert.Equal(t, i+1, r.Rank)
+		assert.Equal(t, fmt.Sprint(10-i), r.Number, "most matched deals first, whichever ad arrived first")
+	}
+
+	items, _, _, err := compare.Merge(context.Background(), src, "33101", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "1-0", items[0].ID, "merged in store order")
+}
+
+func TestStores_OpenCircuitStopsTheRemainingFetches(t *testing.T) {
+	src := &slowSource{stores: numberedStores(10), circuitOpenAt: "1"}
+
+	_, _, err := compare.Stores(context.Background(), src, "33101", 10, filter.Options{})
+	var upstream *compare.UpstreamError
+	require.ErrorAs(t, err, &upstream)
+	assert.ErrorIs(t, err, api.ErrCircuitOpen)
+	assert.Less(t, int(src.calls.Load()), 10)
+}
@@ -0,0 +1,64 @@
+package compare
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+// Concurrency is the number of weekly ads Stores and Merge fetch at once.
+const Concurrency = 4
+
+// ad is the outcome of fetching one store's weekly ad.
+type ad struct {
+	resp *api.SavingsResponse
+	err  error
+}
+
+// fetchAds fetches the weekly ads of stores, Concurrency at a time, and
+// returns them in store order. An open circuit breaker cancels the fetches
+// not yet started, since they would be refused the same way, and is
+// returned as an UpstreamError.
+func fetchAds(ctx context.Context, src Source, stores []api.Store) ([]ad, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ads := make([]ad, len(stores))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < Concurrency && w < len(stores); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := src.FetchSavings(ctx, api.StoreNumber(stores[i].Key))
+				ads[i] = ad{resp: resp, err: err}
+				if errors.Is(err, api.ErrCircuitOpen) {
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := range stores {
+		if ctx.Err() != nil {
+			ads[i].err = ctx.Err()
+			continue
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			ads[i].err = ctx.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, a := range ads {
+		if errors.Is(a.err, api.ErrCircuitOpen) {
+			return nil, &UpstreamError{Action: "fetching deals", Err: a.err}
+		}
+	}
+	return ads, nil
+}
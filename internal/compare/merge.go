@@ -0,0 +1,68 @@
+package compare
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// Merge fetches the weekly ad of every store near zipCode and merges their
+// deals into one list. A deal carried by several stores appears once, in
+// the position where it was first seen, with Stores listing the store
+// numbers that carry it in store lookup order. It also returns the stores
+// looked up and how many were skipped because their ad failed to load.
+// The ads are fetched concurrently and merged in store order.
+func Merge(ctx context.Context, src Source, zipCode string, count int) ([]api.SavingItem, []api.Store, int, error) {
+	stores, err := src.FetchStores(ctx, zipCode, count)
+	if err != nil {
+		return nil, nil, 0, &UpstreamError{Action: "fetching stores", Err: err}
+	}
+	if len(stores) == 0 {
+		return nil, nil, 0, ErrNoStores
+	}
+
+	ads, err := fetchAds(ctx, src, stores)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var merged []api.SavingItem
+	index := map[string]int{}
+	errCount := 0
+	for i, store := range stores {
+		storeNumber := api.StoreNumber(store.Key)
+		if ads[i].err != nil {
+			errCount++
+			continue
+		}
+		for _, item := range ads[i].resp.Savings {
+			key := DealKey(item)
+			if i, ok := index[key]; ok {
+				if !filter.ContainsIgnoreCase(merged[i].Stores, storeNumber) {
+					merged[i].Stores = append(merged[i].Stores, storeNumber)
+				}
+				continue
+			}
+			item.Stores = []string{storeNumber}
+			index[key] = len(merged)
+			merged = append(merged, item)
+		}
+	}
+
+	if errCount == len(stores) {
+		return nil, nil, 0, &UpstreamError{Action: "fetching deals", Err: fmt.Errorf("all %d store lookups failed", len(stores))}
+	}
+	return merged, stores, errCount, nil
+}
+
+// DealKey identifies the same deal across stores' ads: its ID, or its title
+// and savings text when the ID is missing.
+func DealKey(item api.SavingItem) string {
+	if id := strings.TrimSpace(item.ID); id != "" {
+		return "id:" + id
+	}
+	return "text:" + strings.ToLower(filter.Title(item)+"\x00"+filter.CleanText(filter.Deref(item.Savings)))
+}
@@ -0,0 +1,61 @@
+package compare_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/compare"
+)
+
+func ptr(s string) *string { return &s }
+
+type fakeSource struct {
+	stores []api.Store
+	ads    map[string][]api.SavingItem
+}
+
+func (f fakeSource) FetchStores(context.Context, string, int) ([]api.Store, error) {
+	return f.stores, nil
+}
+
+func (f fakeSource) FetchSavings(_ context.Context, store string) (*api.SavingsResponse, error) {
+	items, ok := f.ads[store]
+	if !ok {
+		return nil, errors.New("boom")
+	}
+	return &api.SavingsResponse{Savings: items}, nil
+}
+
+func TestMerge_AnnotatesStoresCarryingEachDeal(t *testing.T) {
+	src := fakeSource{
+		stores: []api.Store{{Key: "01425"}, {Key: "01500"}, {Key: "01600"}},
+		ads: map[string][]api.SavingItem{
+			"1425": {{ID: "a", Title: ptr("Apples")}, {Title: ptr("Bread"), Savings: ptr("2/$5")}},
+			"1500": {{ID: "b", Title: ptr("Bananas")}, {ID: "a", Title: ptr("Apples")}, {Title: ptr("bread"), Savings: ptr("2/$5")}},
+		},
+	}
+
+	items, stores, skipped, err := compare.Merge(context.Background(), src, "33101", 5)
+	require.NoError(t, err)
+	assert.Len(t, stores, 3)
+	assert.Equal(t, 1, skipped)
+	require.Len(t, items, 3)
+	assert.Equal(t, "a", items[0].ID)
+	assert.Equal(t, []string{"1425", "1500"}, items[0].Stores)
+	assert.Equal(t, []string{"1425", "1500"}, items[1].Stores, "deals without an ID match on title and savings")
+	assert.Equal(t, []string{"1500"}, items[2].Stores)
+}
+
+func TestMerge_FailsWhenEveryStoreFails(t *testing.T) {
+	src := fakeSource{stores: []api.Store{{Key: "01425"}}}
+	_, _, _, err := compare.Merge(context.Background(), src, "33101", 5)
+	var upstream *compare.UpstreamError
+	assert.ErrorAs(t, err, &upstream)
+
+	_, _, _, err = compare.Merge(context.Background(), fakeSource{}, "33101", 5)
+	assert.ErrorIs(t, err, compare.ErrNoStores)
+}
@@ -0,0 +1,364 @@
+// Package config loads the optional pubcli configuration file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvConfigDir overrides the directory that holds config.yaml.
+	EnvConfigDir = "PUBCLI_CONFIG_DIR"
+	// EnvDataDir overrides the directory that holds saved state such as the
+	// shopping list.
+	EnvDataDir = "PUBCLI_DATA_DIR"
+	// EnvCacheDir overrides the directory that holds downloaded weekly ads.
+	EnvCacheDir = "PUBCLI_CACHE_DIR"
+	// EnvProfile selects a profile when --profile is not given.
+	EnvProfile = "PUBCLI_PROFILE"
+
+	fileName = "config.yaml"
+)
+
+// Config is the user configuration. Every field is optional; the zero value
+// reproduces the CLI's built-in behavior.
+type Config struct {
+	// DefaultStore is used when neither --store nor --zip is given.
+	DefaultStore string `yaml:"default_store,omitempty"`
+	// DefaultZip is used when neither --store nor --zip is given and no
+	// default store is configured.
+	DefaultZip string `yaml:"default_zip,omitempty"`
+	// DefaultCommand selects what a bare interactive `pubcli` runs. Only
+	// "tui" is recognized; anything else prints the quick start.
+	DefaultCommand string `yaml:"default_command,omitempty"`
+	// Accessible turns on screen-reader-friendly output for every command.
+	Accessible bool `yaml:"accessible,omitempty"`
+	// Quiet turns off the hints that suggest other commands or flags, like
+	// --quiet on every command.
+	Quiet bool `yaml:"quiet,omitempty"`
+	// SchemaVersion pins the JSON schema version when --schema-version is
+	// not given. Zero means the newest version.
+	SchemaVersion int `yaml:"schema_version,omitempty"`
+	// Locale formats numbers, dollar amounts, and deal dates, e.g. "en-GB"
+	// or "de-DE", when --locale is not given. Empty means en-US.
+	Locale string `yaml:"locale,omitempty"`
+	// Lang is the interface language of labels and messages, "en" or "es",
+	// when --lang is not given. Empty means en.
+	Lang string `yaml:"lang,omitempty"`
+	// SlackSigningSecret enables the /slack endpoint of `pubcli serve`.
+	// The PUBCLI_SLACK_SIGNING_SECRET environment variable takes precedence.
+	SlackSigningSecret string `yaml:"slack_signing_secret,omitempty"`
+	// Headers are added to every request to the Publix API, e.g. a session
+	// header or a User-Agent. --header flags take precedence.
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// CookieJar keeps cookies from the Publix API in cookies.json in the
+	// data directory and sends them back on later runs, for features that
+	// need a Publix session.
+	CookieJar bool `yaml:"cookie_jar,omitempty"`
+	// Exclude hides deals in these categories and departments from every
+	// command and the TUI, unless --no-default-filters is given.
+	Exclude Exclude `yaml:"exclude,omitempty"`
+	// Profile flags the deals the household avoids, everywhere deals are
+	// shown.
+	Profile Profile `yaml:"profile,omitempty"`
+	// Alerts configures `pubcli alert run`.
+	Alerts Alerts `yaml:"alerts,omitempty"`
+	// Sync configures `pubcli sync`. Nil means sync is off.
+	Sync *Sync `yaml:"sync,omitempty"`
+	// Secrets chooses where `secret:NAME` values are kept.
+	Secrets Secrets `yaml:"secrets,omitempty"`
+	// Storage chooses where shopping lists, the watchlist, and the ad
+	// history are kept.
+	Storage Storage `yaml:"storage,omitempty"`
+	// Retention limits how long the ad history is kept.
+	Retention Retention `yaml:"retention,omitempty"`
+}
+
+// Storage configures the storage backend for saved state.
+type Storage struct {
+	// Backend is "json" or "sqlite"; see storage.Open. Empty means json.
+	Backend string `yaml:"backend,omitempty"`
+}
+
+// DefaultHistoryWeeks is how many ad weeks of history are kept when
+// retention.history_weeks is unset.
+const DefaultHistoryWeeks = 26
+
+// Retention configures how much saved state is kept.
+type Retention struct {
+	// HistoryWeeks is how many ad weeks of snapshots to keep, counting the
+	// current one. Zero means DefaultHistoryWeeks; a negative value keeps
+	// every snapshot.
+	HistoryWeeks int `yaml:"history_weeks,omitempty"`
+}
+
+// KeepWeeks returns the number of ad weeks of history to keep, or 0 to keep
+// them all.
+func (r Retention) KeepWeeks() int {
+	switch {
+	case r.HistoryWeeks == 0:
+		return DefaultHistoryWeeks
+	case r.HistoryWeeks < 0:
+		return 0
+	}
+	return r.HistoryWeeks
+}
+
+// Secrets configures the secrets store. Tokens, passwords, and webhook URLs
+// may be written as "secret:NAME" to read them from it.
+type Secrets struct {
+	// Backend is "auto", "keyring", or "file"; see secrets.Open. Empty
+	// means auto.
+	Backend string `yaml:"backend,omitempty"`
+}
+
+// Exclude lists categories and departments that are never shown, e.g.
+// tobacco or pet.
+type Exclude struct {
+	Categories  []string `yaml:"categories,omitempty"`
+	Departments []string `yaml:"departments,omitempty"`
+}
+
+// Profile is the household's dietary profile; see profile.Profile.
+type Profile struct {
+	Allergens []string `yaml:"allergens,omitempty"`
+	Dislikes  []string `yaml:"dislikes,omitempty"`
+	Diets     []string `yaml:"diets,omitempty"`
+	// Hide drops flagged deals instead of marking them.
+	Hide bool `yaml:"hide,omitempty"`
+}
+
+// Sync shares the shopping list and the watchlist with other devices.
+type Sync struct {
+	// Provider is "webdav" or "dir".
+	Provider string `yaml:"provider"`
+	// URL is the WebDAV collection holding the shared files.
+	URL      string `yaml:"url,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	// Password is the WebDAV password. The PUBCLI_SYNC_PASSWORD environment
+	// variable takes precedence.
+	Password string `yaml:"password,omitempty"`
+	// Path is the shared directory for the "dir" provider.
+	Path string `yaml:"path,omitempty"`
+	// Device names this machine in synced files. Empty uses the host name.
+	Device string `yaml:"device,omitempty"`
+}
+
+// Alerts holds the watched items and where matches are sent.
+type Alerts struct {
+	Rules []AlertRule `yaml:"rules,omitempty"`
+	// Webhooks receive a JSON POST with every match.
+	Webhooks []string  `yaml:"webhooks,omitempty"`
+	Telegram *Telegram `yaml:"telegram,omitempty"`
+}
+
+// AlertRule matches deals that meet all of its criteria, as an alert.Rule
+// does. Stores limits the rule to those store numbers instead of the store
+// given on the command line.
+type AlertRule struct {
+	Name           string   `yaml:"name"`
+	Keywords       []string `yaml:"keywords,omitempty"`
+	Stores         []string `yaml:"stores,omitempty"`
+	Category       string   `yaml:"category,omitempty"`
+	Department     string   `yaml:"department,omitempty"`
+	Query          string   `yaml:"query,omitempty"`
+	BOGO           bool     `yaml:"bogo,omitempty"`
+	MaxPrice       float64  `yaml:"max_price,omitempty"`
+	MinPercentOff  float64  `yaml:"min_percent_off,omitempty"`
+	Sort           string   `yaml:"sort,omitempty"`
+	Limit          int      `yaml:"limit,omitempty"`
+	IncludeExpired bool     `yaml:"include_expired,omitempty"`
+}
+
+// Telegram sends alert matches through a Telegram bot.
+type Telegram struct {
+	BotToken string `yaml:"bot_token"`
+	ChatID   string `yaml:"chat_id"`
+	// Template is a Go text/template for the message body. Empty uses the
+	// built-in template.
+	Template string `yaml:"template,omitempty"`
+	// MinInterval is the shortest gap between two messages, e.g. "1h".
+	// Empty means no limit.
+	MinInterval string `yaml:"min_interval,omitempty"`
+}
+
+// HasDefaultLocation reports whether a default store or ZIP is configured.
+func (c *Config) HasDefaultLocation() bool {
+	return c != nil && (strings.TrimSpace(c.DefaultStore) != "" || strings.TrimSpace(c.DefaultZip) != "")
+}
+
+// profile is the selected profile; empty is the default one.
+var profile string
+
+var validProfile = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// SetProfile selects a profile: its config file and data live in
+// profiles/NAME inside the default profile's directories, so people sharing
+// a machine keep their settings, lists, and history apart. Empty selects the
+// default profile.
+func SetProfile(name string) error {
+	if name != "" && !validProfile.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, '_', and '-')", name)
+	}
+	profile = name
+	return nil
+}
+
+// ActiveProfile returns the selected profile, or "" for the default one.
+func ActiveProfile() string {
+	return profile
+}
+
+// Profiles lists the profiles that have a config or data directory,
+// sorted.
+func Profiles() ([]string, error) {
+	seen := map[string]bool{}
+	for _, base := range []func() (string, error){baseDir, baseDataDir} {
+		dir, err := base()
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(filepath.Join(dir, profilesDir))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() && validProfile.MatchString(e.Name()) {
+				seen[e.Name()] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// profilesDir holds the other profiles inside the default one's
+// directories.
+const profilesDir = "profiles"
+
+func inProfile(dir string) string {
+	if profile == "" {
+		return dir
+	}
+	return filepath.Join(dir, profilesDir, profile)
+}
+
+// Dir returns the directory holding the config file.
+func Dir() (string, error) {
+	dir, err := baseDir()
+	if err != nil {
+		return "", err
+	}
+	return inProfile(dir), nil
+}
+
+func baseDir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv(EnvConfigDir)); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating config directory: %w", err)
+	}
+	return filepath.Join(base, "pubcli"), nil
+}
+
+// DataDir returns the directory for state pubcli writes itself: the
+// shopping list and the bookkeeping behind alerts and status. It is
+// $XDG_DATA_HOME/pubcli (~/.local/share/pubcli) on Linux and the config
+// directory elsewhere.
+func DataDir() (string, error) {
+	dir, err := baseDataDir()
+	if err != nil {
+		return "", err
+	}
+	return inProfile(dir), nil
+}
+
+func baseDataDir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv(EnvDataDir)); dir != "" {
+		return dir, nil
+	}
+	if base := strings.TrimSpace(os.Getenv("XDG_DATA_HOME")); base != "" {
+		return filepath.Join(base, "pubcli"), nil
+	}
+	if runtime.GOOS == "linux" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locating data directory: %w", err)
+		}
+		return filepath.Join(home, ".local", "share", "pubcli"), nil
+	}
+	return baseDir()
+}
+
+// CacheDir returns the directory for data pubcli can download again, such
+// as weekly ads: $XDG_CACHE_HOME/pubcli (~/.cache/pubcli) on Linux and the
+// OS cache directory elsewhere.
+func CacheDir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv(EnvCacheDir)); dir != "" {
+		return inProfile(dir), nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locating cache directory: %w", err)
+	}
+	return inProfile(filepath.Join(base, "pubcli")), nil
+}
+
+// DataPath returns the path of a file in the data directory.
+func DataPath(name string) (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Path returns the full path of the config file.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Load reads the config file. A missing file yields an empty config.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the config file at path. A missing file yields an empty config.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	return cfg, nil
+}
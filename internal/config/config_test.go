@@ -0,0 +1,161 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/config"
+)
+
+func TestLoad_MissingFileIsEmpty(t *testing.T) {
+	t.Setenv(config.EnvConfigDir, t.TempDir())
+
+	cfg, err := config.Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, &config.Config{}, cfg)
+	assert.False(t, cfg.HasDefaultLocation())
+}
+
+func TestLoad_ReadsYAML(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("default_store: \"1425\"\ndefault_command: tui\n"), 0o600))
+
+	cfg, err := config.Load()
+
+	require.NoError(t, err)
+	assert.Equal(t, "1425", cfg.DefaultStore)
+	assert.Equal(t, "tui", cfg.DefaultCommand)
+	assert.True(t, cfg.HasDefaultLocation())
+}
+
+func TestLoad_MalformedYAML(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("default_store: [unclosed"), 0o600))
+
+	_, err := config.Load()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing config")
+}
+
+func TestLoad_ReadsAlerts(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvConfigDir, dir)
+	yaml := `alerts:
+  rules:
+    - name: coffee
+      keywords: [coffee, espresso]
+  webhooks:
+    - https://hooks.example.com/pubcli
+  telegram:
+    bot_token: "123:ABC"
+    chat_id: "42"
+    min_interval: 1h
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0o600))
+
+	cfg, err := config.Load()
+
+	require.NoError(t, err)
+	require.Len(t, cfg.Alerts.Rules, 1)
+	assert.Equal(t, []string{"coffee", "espresso"}, cfg.Alerts.Rules[0].Keywords)
+	assert.Equal(t, []string{"https://hooks.example.com/pubcli"}, cfg.Alerts.Webhooks)
+	require.NotNil(t, cfg.Alerts.Telegram)
+	assert.Equal(t, "42", cfg.Alerts.Telegram.ChatID)
+	assert.Equal(t, "1h", cfg.Alerts.Telegram.MinInterval)
+}
+
+func TestDocument_SetGetUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("# my settings\ndefault_zip: \"33101\" # home\n"), 0o600))
+
+	doc, err := config.ReadDocument(path)
+	require.NoError(t, err)
+	require.NoError(t, doc.Set("default_store", []string{"1425"}))
+	require.NoError(t, doc.Set("default_zip", []string{"33139"}))
+	require.NoError(t, doc.Set("accessible", []string{"true"}))
+	require.NoError(t, doc.Set("profile.diets", []string{"vegan", "gluten-free"}))
+	require.NoError(t, doc.Set("headers.X-Session", []string{"abc"}))
+	require.NoError(t, doc.Set("alerts.telegram.chat_id", []string{"42"}))
+
+	node, err := doc.Get("default_store")
+	require.NoError(t, err)
+	assert.Equal(t, "1425", node.Value)
+
+	data, err := doc.Bytes()
+	require.NoError(t, err)
+	text := string(data)
+	assert.Contains(t, text, "# my settings")
+	assert.Contains(t, text, `default_zip: "33139" # home`)
+	assert.Contains(t, text, `default_store: "1425"`, "numbers set as strings stay strings")
+
+	cfg, err := config.Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, "1425", cfg.DefaultStore)
+	assert.True(t, cfg.Accessible)
+	assert.Equal(t, []string{"vegan", "gluten-free"}, cfg.Profile.Diets)
+	assert.Equal(t, "abc", cfg.Headers["X-Session"])
+	assert.Equal(t, "42", cfg.Alerts.Telegram.ChatID)
+
+	removed, err := doc.Unset("alerts.telegram.chat_id")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	node, err = doc.Get("alerts")
+	require.NoError(t, err)
+	assert.Nil(t, node, "emptied sections are removed")
+	removed, err = doc.Unset("default_command")
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func TestDocument_SetErrors(t *testing.T) {
+	doc, err := config.ReadDocument(filepath.Join(t.TempDir(), "config.yaml"))
+	require.NoError(t, err)
+
+	var unknown *config.UnknownKeyError
+	require.ErrorAs(t, doc.Set("default_stor", []string{"1425"}), &unknown)
+	assert.Equal(t, "default_stor", unknown.Key)
+	assert.ErrorContains(t, doc.Set("accessible", []string{"yes"}), "takes true or false")
+	assert.ErrorContains(t, doc.Set("schema_version", []string{"two"}), "takes a whole number")
+	assert.ErrorContains(t, doc.Set("default_store", []string{"1", "2"}), "takes one value")
+	assert.ErrorContains(t, doc.Set("profile", []string{"x"}), "is a section")
+	assert.ErrorContains(t, doc.Set("alerts.rules", []string{"x"}), "is a section")
+}
+
+func TestParse_RejectsUnknownFields(t *testing.T) {
+	_, err := config.Parse([]byte("default_stor: \"1425\"\n"))
+	assert.ErrorContains(t, err, "default_stor")
+
+	cfg, err := config.Parse(nil)
+	require.NoError(t, err)
+	assert.Equal(t, &config.Config{}, cfg)
+}
+
+func TestSetProfile_NamespacesDirs(t *testing.T) {
+	configDir, dataDir := t.TempDir(), t.TempDir()
+	t.Setenv(config.EnvConfigDir, configDir)
+	t.Setenv(config.EnvDataDir, dataDir)
+	t.Cleanup(func() { _ = config.SetProfile("") })
+
+	require.NoError(t, config.SetProfile("work"))
+	dir, err := config.Dir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(configDir, "profiles", "work"), dir)
+	data, err := config.DataDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataDir, "profiles", "work"), data)
+
+	require.NoError(t, os.MkdirAll(data, 0o755))
+	names, err := config.Profiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work"}, names)
+
+	assert.Error(t, config.SetProfile("../home"))
+	assert.Equal(t, "work", config.ActiveProfile())
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownKeyError reports a key that names no config field.
+type UnknownKeyError struct {
+	Key string
+}
+
+func (e *UnknownKeyError) Error() string {
+	return fmt.Sprintf("unknown config key %q", e.Key)
+}
+
+// Keys returns the dotted keys of every setting, e.g. "default_store" or
+// "alerts.telegram.chat_id", sorted. Headers take any name after
+// "headers.", and alert rules are edited as a whole.
+func Keys() []string {
+	var keys []string
+	var walk func(prefix string, t reflect.Type)
+	walk = func(prefix string, t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			name := yamlName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			ft := t.Field(i).Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				walk(prefix+name+".", ft)
+				continue
+			}
+			keys = append(keys, prefix+name)
+		}
+	}
+	walk("", reflect.TypeOf(Config{}))
+	sort.Strings(keys)
+	return keys
+}
+
+// keyType returns the Go type of the setting a dotted key names.
+func keyType(key string) (reflect.Type, error) {
+	t := reflect.TypeOf(Config{})
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			found := false
+			for j := 0; j < t.NumField(); j++ {
+				if yamlName(t.Field(j)) == part {
+					t, found = t.Field(j).Type, true
+					break
+				}
+			}
+			if !found || part == "" {
+				return nil, &UnknownKeyError{Key: key}
+			}
+		case reflect.Map:
+			if part == "" {
+				return nil, &UnknownKeyError{Key: key}
+			}
+			t = t.Elem()
+		default:
+			return nil, &UnknownKeyError{Key: strings.Join(parts[:i+1], ".")}
+		}
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t, nil
+}
+
+func yamlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// Document is the config file as YAML, so settings can be changed without
+// losing the comments and order of the rest of the file.
+type Document struct {
+	root yaml.Node
+}
+
+// ReadDocument reads the config file at path. A missing or empty file is an
+// empty document.
+func ReadDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	d := &Document{}
+	if err := yaml.Unmarshal(data, &d.root); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	if d.root.Kind == 0 {
+		d.root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if top := d.root.Content[0]; top.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parsing config %s: the file must be a mapping of settings", path)
+	}
+	return d, nil
+}
+
+// Get returns the node a dotted key names, or nil when it is not set. The
+// empty key names the whole document.
+func (d *Document) Get(key string) (*yaml.Node, error) {
+	if key == "" {
+		return d.root.Content[0], nil
+	}
+	if _, err := keyType(key); err != nil {
+		return nil, err
+	}
+	node := d.root.Content[0]
+	for _, part := range strings.Split(key, ".") {
+		node = mappingValue(node, part)
+		if node == nil {
+			return nil, nil
+		}
+	}
+	return node, nil
+}
+
+// Set sets a dotted key. Lists take every value; other settings take one,
+// checked against the setting's type.
+func (d *Document) Set(key string, values []string) error {
+	t, err := keyType(key)
+	if err != nil {
+		return err
+	}
+	value, err := valueNode(key, t, values)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(key, ".")
+	node := d.root.Content[0]
+	for _, part := range parts[:len(parts)-1] {
+		next := mappingValue(node, part)
+		if next == nil || next.Kind != yaml.MappingNode {
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(node, part, next)
+		}
+		node = next
+	}
+	setMappingValue(node, parts[len(parts)-1], value)
+	return nil
+}
+
+// Unset removes a dotted key, and any sections it leaves empty. It reports
+// whether the key was set.
+func (d *Document) Unset(key string) (bool, error) {
+	if _, err := keyType(key); err != nil {
+		return false, err
+	}
+	return unset(d.root.Content[0], strings.Split(key, ".")), nil
+}
+
+func unset(node *yaml.Node, parts []string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != parts[0] {
+			continue
+		}
+		if len(parts) > 1 {
+			child := node.Content[i+1]
+			if child.Kind != yaml.MappingNode || !unset(child, parts[1:]) {
+				return false
+			}
+			if len(child.Content) > 0 {
+				return true
+			}
+		}
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+		return true
+	}
+	return false
+}
+
+// Bytes encodes the document.
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.root.Content[0].Content) == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&d.root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			// Keep the comments of the value being replaced.
+			value.LineComment = node.Content[i+1].LineComment
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// valueNode builds the YAML for values as a setting of type t.
+func valueNode(key string, t reflect.Type, values []string) (*yaml.Node, error) {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String {
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, v := range values {
+			seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v})
+		}
+		return seq, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Float64:
+	default:
+		return nil, fmt.Errorf("%s is a section; set the settings in it, or use `pubcli config edit`", key)
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("%s takes one value, got %d", key, len(values))
+	}
+	v := values[0]
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s takes true or false, not %q", key, v)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(b)}, nil
+	case reflect.Int:
+		if _, err := strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("%s takes a whole number, not %q", key, v)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v}, nil
+	case reflect.Float64:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("%s takes a number, not %q", key, v)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: v}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}, nil
+}
+
+// Parse decodes config YAML strictly: unlike LoadFile, a setting that is
+// not a Config field is an error, so typos are caught.
+func Parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// WriteFile replaces the config file at path with data, readable only by
+// the user since it may hold secrets.
+func WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,69 @@
+package console
+
+import (
+	"io"
+	"os"
+
+	"github.com/muesli/termenv"
+)
+
+// Values of --color.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ColorModes lists the values of --color.
+var ColorModes = []string{ColorAuto, ColorAlways, ColorNever}
+
+// ValidColorMode reports whether mode is a value of --color.
+func ValidColorMode(mode string) bool {
+	switch mode {
+	case ColorAuto, ColorAlways, ColorNever:
+		return true
+	}
+	return false
+}
+
+// UseColor decides whether to print ANSI colors. ColorAlways and ColorNever
+// win outright. In ColorAuto mode the environment decides first:
+// NO_COLOR turns colors off, CLICOLOR_FORCE turns them on, and TERM=dumb or
+// CLICOLOR=0 turns them off. Otherwise colors are on only for a terminal.
+func UseColor(mode string, terminal bool, getenv func(string) string) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if getenv("TERM") == "dumb" || getenv("CLICOLOR") == "0" {
+		return false
+	}
+	return terminal
+}
+
+// ColorProfile returns the color profile for styled output to w under
+// mode, for lipgloss.SetColorProfile. Without colors it is termenv.Ascii.
+// With colors it is what the terminal advertises through TERM and
+// COLORTERM, and at least 16-color ANSI, so `--color always | less -R`
+// stays colored. Windows consoles without ANSI support count as not being
+// terminals.
+func ColorProfile(mode string, w io.Writer) termenv.Profile {
+	f, ok := File(w)
+	terminal := ok && IsTerminal(f) && output.VT
+	if !UseColor(mode, terminal, os.Getenv) {
+		return termenv.Ascii
+	}
+	profile := termenv.NewOutput(w, termenv.WithTTY(true)).ColorProfile()
+	if profile == termenv.Ascii {
+		return termenv.ANSI
+	}
+	return profile
+}
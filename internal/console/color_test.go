@@ -0,0 +1,59 @@
+package console_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/console"
+)
+
+func TestUseColor(t *testing.T) {
+	cases := []struct {
+		name     string
+		mode     string
+		terminal bool
+		env      map[string]string
+		want     bool
+	}{
+		{name: "auto on a terminal", mode: console.ColorAuto, terminal: true, want: true},
+		{name: "auto into a pipe", mode: console.ColorAuto, want: false},
+		{name: "always into a pipe", mode: console.ColorAlways, want: true},
+		{name: "always beats NO_COLOR", mode: console.ColorAlways, env: map[string]string{"NO_COLOR": "1"}, want: true},
+		{name: "never on a terminal", mode: console.ColorNever, terminal: true, want: false},
+		{name: "NO_COLOR", mode: console.ColorAuto, terminal: true, env: map[string]string{"NO_COLOR": "1"}, want: false},
+		{name: "NO_COLOR beats CLICOLOR_FORCE", mode: console.ColorAuto, env: map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, want: false},
+		{name: "CLICOLOR_FORCE into a pipe", mode: console.ColorAuto, env: map[string]string{"CLICOLOR_FORCE": "1"}, want: true},
+		{name: "CLICOLOR_FORCE=0", mode: console.ColorAuto, env: map[string]string{"CLICOLOR_FORCE": "0"}, want: false},
+		{name: "TERM=dumb", mode: console.ColorAuto, terminal: true, env: map[string]string{"TERM": "dumb"}, want: false},
+		{name: "CLICOLOR=0", mode: console.ColorAuto, terminal: true, env: map[string]string{"CLICOLOR": "0"}, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			getenv := func(key string) string { return tc.env[key] }
+			assert.Equal(t, tc.want, console.UseColor(tc.mode, tc.terminal, getenv))
+		})
+	}
+}
+
+func TestColorProfile(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("TERM", "dumb")
+
+	var buf bytes.Buffer
+	assert.Equal(t, termenv.Ascii, console.ColorProfile(console.ColorAuto, &buf))
+	assert.Equal(t, termenv.Ascii, console.ColorProfile(console.ColorNever, &buf))
+	assert.Equal(t, termenv.ANSI, console.ColorProfile(console.ColorAlways, &buf), "always keeps 16 colors on a dumb terminal")
+
+	t.Setenv("TERM", "xterm-256color")
+	assert.Equal(t, termenv.ANSI256, console.ColorProfile(console.ColorAlways, &buf))
+}
+
+func TestValidColorMode(t *testing.T) {
+	for _, mode := range console.ColorModes {
+		assert.True(t, console.ValidColorMode(mode), mode)
+	}
+	assert.False(t, console.ValidColorMode("sometimes"))
+}
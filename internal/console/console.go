@@ -0,0 +1,144 @@
+// Package console adapts pubcli's output to the terminal it writes to.
+// Windows consoles need virtual terminal processing turned on before they
+// understand ANSI colors, and a console on a legacy code page cannot show
+// the Unicode box drawing, block, and bullet glyphs the text output and
+// TUI use, so those are swapped for ASCII look-alikes there.
+package console
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-isatty"
+)
+
+// EnvASCII forces ASCII glyphs on any platform when set to a non-empty
+// value other than "0".
+const EnvASCII = "PUBCLI_ASCII"
+
+// Capabilities describes what a terminal can render.
+type Capabilities struct {
+	// VT is true when ANSI escape sequences are interpreted. It is false
+	// for Windows consoles that refused virtual terminal processing.
+	VT bool
+	// Unicode is true when the terminal shows non-ASCII glyphs.
+	Unicode bool
+}
+
+// output is what the process's terminal can render, as set by Configure.
+var output = Capabilities{VT: true, Unicode: true}
+
+// Prepare readies f for styled output and reports what it can render. It
+// turns on virtual terminal processing for Windows consoles. Files that are
+// not terminals report full capabilities, since their output is read
+// elsewhere. ASCII-only output is also chosen when EnvASCII is set.
+func Prepare(f *os.File) Capabilities {
+	caps := Capabilities{VT: true, Unicode: true}
+	if IsTerminal(f) {
+		caps = prepareTerminal(f)
+	}
+	if v := os.Getenv(EnvASCII); v != "" && v != "0" {
+		caps.Unicode = false
+	}
+	return caps
+}
+
+// Configure records what the process's terminal can render, as reported
+// by Prepare, for Unicode and ColorProfile.
+func Configure(caps Capabilities) {
+	output = caps
+}
+
+// Unicode reports whether output may use Unicode glyphs. Writers that do
+// not go through an ASCII Writer, such as the TUI renderer, check it and
+// pass their text through ASCII themselves.
+func Unicode() bool {
+	return output.Unicode
+}
+
+// IsTerminal reports whether f is a terminal. Besides consoles, including
+// ConPTY sessions such as Windows Terminal, it accepts the named pipes that
+// Cygwin and MSYS2 terminals like mintty give their programs.
+func IsTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	fd := f.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// File returns the file behind w, looking through an ASCII Writer.
+func File(w io.Writer) (*os.File, bool) {
+	if aw, ok := w.(*Writer); ok {
+		w = aw.w
+	}
+	f, ok := w.(*os.File)
+	return f, ok
+}
+
+// asciiGlyphs maps each Unicode glyph pubcli prints to one ASCII character,
+// so replacing them keeps column widths and table alignment.
+var asciiGlyphs = strings.NewReplacer(
+	// Box drawing, including lipgloss's rounded borders.
+	"─", "-", "│", "|", "┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"┬", "+", "┴", "+", "├", "+", "┤", "+", "┼", "+",
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+",
+	// Sparkline blocks, lowest to highest, and bar chart cells.
+	"▁", "_", "▂", ".", "▃", ",", "▄", "-", "▅", "~", "▆", "=", "▇", "*", "█", "#",
+	"▏", "|", "▎", "|", "▍", "|", "▌", "|", "▋", "#", "▊", "#", "▉", "#",
+	// Punctuation and symbols.
+	"•", "*", "·", "-", "—", "-", "–", "-", "×", "x", "✓", "+", "✗", "x",
+	"↑", "^", "↓", "v", "←", "<", "→", ">",
+)
+
+// ASCII returns s with the Unicode glyphs pubcli prints replaced by ASCII
+// look-alikes. Other text, such as accented letters in deal descriptions,
+// is left alone.
+func ASCII(s string) string {
+	return asciiGlyphs.Replace(s)
+}
+
+// Writer passes output through ASCII on its way to an underlying writer.
+type Writer struct {
+	w io.Writer
+	// partial holds the start of a UTF-8 sequence split across writes.
+	partial []byte
+}
+
+// NewASCIIWriter returns a Writer that writes to w.
+func NewASCIIWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write converts p and writes it. It reports len(p) written on success,
+// even though the converted output is usually shorter.
+func (w *Writer) Write(p []byte) (int, error) {
+	data := append(w.partial, p...)
+	w.partial = nil
+	if cut := incompleteTail(data); cut > 0 {
+		w.partial = append([]byte(nil), data[len(data)-cut:]...)
+		data = data[:len(data)-cut]
+	}
+	if _, err := io.WriteString(w.w, ASCII(string(data))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// incompleteTail returns how many bytes at the end of data begin a UTF-8
+// sequence that is not finished yet.
+func incompleteTail(data []byte) int {
+	for n := 1; n < utf8.UTFMax && n <= len(data); n++ {
+		b := data[len(data)-n]
+		if !utf8.RuneStart(b) {
+			continue
+		}
+		if !utf8.FullRune(data[len(data)-n:]) {
+			return n
+		}
+		return 0
+	}
+	return 0
+}
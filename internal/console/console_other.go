@@ -0,0 +1,11 @@
+//go:build !windows
+
+package console
+
+import "os"
+
+// prepareTerminal has nothing to set up outside Windows: terminals there
+// interpret ANSI sequences, and the locale decides the encoding.
+func prepareTerminal(*os.File) Capabilities {
+	return Capabilities{VT: true, Unicode: true}
+}
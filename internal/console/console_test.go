@@ -0,0 +1,52 @@
+package console_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/console"
+)
+
+func TestASCII(t *testing.T) {
+	assert.Equal(t, "+--+\n|ok|\n+--+", console.ASCII("╭──╮\n│ok│\n╰──╯"))
+	assert.Equal(t, "_.-#", console.ASCII("▁▂▄█"))
+	assert.Equal(t, "a * b - c", console.ASCII("a • b — c"))
+	assert.Equal(t, "Jalapeño crème", console.ASCII("Jalapeño crème"), "letters are left alone")
+}
+
+func TestASCIIWriter_SplitRunes(t *testing.T) {
+	var buf bytes.Buffer
+	w := console.NewASCIIWriter(&buf)
+	data := []byte("deals • ñ ─")
+	for i := range data {
+		n, err := w.Write(data[i : i+1])
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	}
+	assert.Equal(t, "deals * ñ -", buf.String())
+}
+
+func TestFile(t *testing.T) {
+	f, ok := console.File(console.NewASCIIWriter(os.Stdout))
+	assert.True(t, ok)
+	assert.Equal(t, os.Stdout, f)
+
+	_, ok = console.File(&bytes.Buffer{})
+	assert.False(t, ok)
+}
+
+func TestPrepare_NotATerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.False(t, console.IsTerminal(f))
+	assert.Equal(t, console.Capabilities{VT: true, Unicode: true}, console.Prepare(f))
+
+	t.Setenv(console.EnvASCII, "1")
+	assert.False(t, console.Prepare(f).Unicode)
+}
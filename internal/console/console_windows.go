@@ -0,0 +1,30 @@
+package console
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// utf8CodePage is the Windows code page identifier for UTF-8.
+const utf8CodePage = 65001
+
+func prepareTerminal(f *os.File) Capabilities {
+	caps := Capabilities{VT: true, Unicode: true}
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		// Cygwin and MSYS2 terminals are pipes to a terminal emulator that
+		// handles ANSI and UTF-8 itself.
+		return caps
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING == 0 {
+		if err := windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+			caps.VT = false
+		}
+	}
+	if cp, err := windows.GetConsoleOutputCP(); err == nil && cp != utf8CodePage {
+		caps.Unicode = false
+	}
+	return caps
+}
@@ -0,0 +1,256 @@
+// Package cookies provides an http.CookieJar that persists to a file, so
+// Publix session cookies survive from one pubcli invocation to the next.
+package cookies
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is the jar's file name inside the data directory.
+const FileName = "cookies.json"
+
+// Cookie is one stored cookie.
+type Cookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain"`
+	Path     string    `json:"path"`
+	Expires  time.Time `json:"expires,omitzero"`
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"httpOnly,omitempty"`
+	// HostOnly cookies are sent to Domain only, not its subdomains.
+	HostOnly bool `json:"hostOnly,omitempty"`
+}
+
+func (c Cookie) key() string {
+	return c.Domain + ";" + c.Path + ";" + c.Name
+}
+
+func (c Cookie) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// Jar is a cookie jar backed by a JSON file. Cookies without an expiry are
+// kept too, since the session they belong to outlives a single command.
+type Jar struct {
+	path string
+
+	mu      sync.Mutex
+	jar     *cookiejar.Jar
+	cookies map[string]Cookie
+	dirty   bool
+}
+
+// Open loads the jar at path. A missing file is an empty jar.
+func Open(path string) (*Jar, error) {
+	j := &Jar{path: path, cookies: map[string]Cookie{}}
+	j.jar, _ = cookiejar.New(nil)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cookie jar: %w", err)
+	}
+	var stored []Cookie
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("parsing cookie jar %s: %w", path, err)
+	}
+	now := time.Now()
+	for _, c := range stored {
+		if !c.expired(now) {
+			j.add(c)
+		}
+	}
+	return j, nil
+}
+
+// Cookies implements http.CookieJar.
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.jar.Cookies(u)
+}
+
+// SetCookies implements http.CookieJar, recording the cookies for Save.
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar.SetCookies(u, cookies)
+	now := time.Now()
+	for _, hc := range cookies {
+		c := fromHTTP(u, hc, now)
+		if c.expired(now) {
+			delete(j.cookies, c.key())
+		} else {
+			j.cookies[c.key()] = c
+		}
+		j.dirty = true
+	}
+}
+
+// Add stores cookies directly, as when importing them from a browser.
+func (j *Jar) Add(cookies ...Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, c := range cookies {
+		j.add(c)
+	}
+	j.dirty = true
+}
+
+func (j *Jar) add(c Cookie) {
+	c.Domain = strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	hc := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Expires:  c.Expires,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+	}
+	if !c.HostOnly {
+		hc.Domain = c.Domain
+	}
+	j.jar.SetCookies(&url.URL{Scheme: "https", Host: c.Domain, Path: c.Path}, []*http.Cookie{hc})
+	j.cookies[c.key()] = c
+}
+
+// List returns the stored, unexpired cookies sorted by domain and name.
+func (j *Jar) List() []Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	out := make([]Cookie, 0, len(j.cookies))
+	for _, c := range j.cookies {
+		if !c.expired(now) {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].Domain != out[b].Domain {
+			return out[a].Domain < out[b].Domain
+		}
+		return out[a].Name < out[b].Name
+	})
+	return out
+}
+
+// Save writes the jar back to its file if any cookie changed. The file is
+// readable by its owner only, since it holds session credentials.
+func (j *Jar) Save() error {
+	j.mu.Lock()
+	dirty := j.dirty
+	j.dirty = false
+	j.mu.Unlock()
+	if !dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(j.List(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return fmt.Errorf("creating cookie jar directory: %w", err)
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing cookie jar: %w", err)
+	}
+	return os.Rename(tmp, j.path)
+}
+
+// Clear deletes the jar's file.
+func Clear(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("clearing cookie jar: %w", err)
+	}
+	return nil
+}
+
+func fromHTTP(u *url.URL, hc *http.Cookie, now time.Time) Cookie {
+	c := Cookie{
+		Name:     hc.Name,
+		Value:    hc.Value,
+		Domain:   strings.TrimPrefix(strings.ToLower(hc.Domain), "."),
+		Path:     hc.Path,
+		Expires:  hc.Expires,
+		Secure:   hc.Secure,
+		HttpOnly: hc.HttpOnly,
+	}
+	if c.Domain == "" {
+		c.Domain = u.Hostname()
+		c.HostOnly = true
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	switch {
+	case hc.MaxAge > 0:
+		c.Expires = now.Add(time.Duration(hc.MaxAge) * time.Second)
+	case hc.MaxAge < 0:
+		c.Expires = now
+	}
+	return c
+}
+
+// ParseNetscape reads cookies in the Netscape cookies.txt format that
+// browser export extensions and curl write. "#HttpOnly_" lines are kept as
+// HttpOnly cookies; other comments and blank lines are skipped.
+func ParseNetscape(r io.Reader) ([]Cookie, error) {
+	var out []Cookie
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		httpOnly := false
+		if rest, ok := strings.CutPrefix(line, "#HttpOnly_"); ok {
+			line, httpOnly = rest, true
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d: want 7 tab-separated fields, got %d", n, len(fields))
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: bad expiry %q", n, fields[4])
+		}
+		c := Cookie{
+			Domain:   fields[0],
+			HostOnly: !strings.EqualFold(fields[1], "TRUE"),
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		if expires > 0 {
+			c.Expires = time.Unix(expires, 0)
+		}
+		c.Domain = strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+		out = append(out, c)
+	}
+	return out, scanner.Err()
+}
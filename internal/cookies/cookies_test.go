@@ -0,0 +1,84 @@
+package cookies_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/cookies"
+)
+
+func TestJar_PersistsAcrossOpens(t *testing.T) {
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err == nil {
+			seen = append(seen, c.Value)
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), cookies.FileName)
+
+	jar, err := cookies.Open(path)
+	require.NoError(t, err)
+	_, err = (&http.Client{Jar: jar}).Get(srv.URL)
+	require.NoError(t, err)
+	require.NoError(t, jar.Save())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	reopened, err := cookies.Open(path)
+	require.NoError(t, err)
+	_, err = (&http.Client{Jar: reopened}).Get(srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, seen, "the second run sends the first run's cookie")
+}
+
+func TestJar_DropsExpiredCookies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), cookies.FileName)
+	jar, err := cookies.Open(path)
+	require.NoError(t, err)
+	u, _ := url.Parse("https://services.publix.com/api")
+	jar.SetCookies(u, []*http.Cookie{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}})
+	jar.SetCookies(u, []*http.Cookie{{Name: "a", MaxAge: -1}})
+	require.NoError(t, jar.Save())
+
+	reopened, err := cookies.Open(path)
+	require.NoError(t, err)
+	list := reopened.List()
+	require.Len(t, list, 1)
+	assert.Equal(t, "b", list[0].Name)
+	assert.Equal(t, "services.publix.com", list[0].Domain)
+	assert.True(t, list[0].HostOnly)
+}
+
+func TestParseNetscape(t *testing.T) {
+	in := "# Netscape HTTP Cookie File\n\n" +
+		".publix.com\tTRUE\t/\tTRUE\t1893456000\tsession\tabc\n" +
+		"#HttpOnly_www.publix.com\tFALSE\t/account\tFALSE\t0\ttoken\txyz\n"
+	got, err := cookies.ParseNetscape(strings.NewReader(in))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, cookies.Cookie{Name: "session", Value: "abc", Domain: "publix.com", Path: "/", Expires: time.Unix(1893456000, 0), Secure: true}, got[0])
+	assert.Equal(t, cookies.Cookie{Name: "token", Value: "xyz", Domain: "www.publix.com", Path: "/account", HttpOnly: true, HostOnly: true}, got[1])
+
+	jar, err := cookies.Open(filepath.Join(t.TempDir(), cookies.FileName))
+	require.NoError(t, err)
+	jar.Add(got...)
+	u, _ := url.Parse("https://services.publix.com/api/v4/savings")
+	sent := jar.Cookies(u)
+	require.Len(t, sent, 1, "domain cookies reach subdomains; host-only ones do not")
+	assert.Equal(t, "session", sent[0].Name)
+
+	_, err = cookies.ParseNetscape(strings.NewReader("publix.com\tTRUE\t/\n"))
+	assert.ErrorContains(t, err, "line 1")
+}
@@ -0,0 +1,177 @@
+// Package csvimport reads spreadsheet exports with a header row, for
+// `pubcli list import` and `pubcli alert import`.
+package csvimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// utf8BOM starts files saved as "CSV UTF-8" by Excel.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Row is one data row, keyed by column name.
+type Row struct {
+	// Line is the row's line number in the file, for error messages.
+	Line   int
+	values map[string]string
+}
+
+// Get returns the trimmed value of a column, or "" when the file has no
+// such column.
+func (r Row) Get(column string) string {
+	return r.values[column]
+}
+
+// Errorf returns an error prefixed with the row's line number.
+func (r Row) Errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", r.Line, fmt.Sprintf(format, args...))
+}
+
+// Read parses CSV whose first row names the columns. Header names match
+// columns case-insensitively and ignore spaces, dashes, and underscores, so
+// "Max Price" is max_price. A column outside columns is an error, so a
+// misspelled header does not drop data silently. required must be in the
+// header and set on every row. Blank rows are skipped. Files exported with
+// semicolons, as spreadsheets in some locales do, are read as well.
+func Read(r io.Reader, columns []string, required string) ([]Row, error) {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(len(utf8BOM)); bytes.Equal(bom, utf8BOM) {
+		if _, err := br.Discard(len(utf8BOM)); err != nil {
+			return nil, err
+		}
+	}
+	// Peek returns what it has, up to the buffer size, with an error when
+	// that is short of n; a short first chunk is fine here.
+	head, _ := br.Peek(br.Size())
+	headerLine, _, _ := bytes.Cut(head, []byte("\n"))
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	if bytes.Count(headerLine, []byte(";")) > bytes.Count(headerLine, []byte(",")) {
+		cr.Comma = ';'
+	}
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty; the first row must name the columns")
+	}
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]string{}
+	for _, c := range columns {
+		known[columnKey(c)] = c
+	}
+	names := make([]string, len(header))
+	hasRequired := false
+	for i, h := range header {
+		if strings.TrimSpace(h) == "" {
+			continue
+		}
+		name, ok := known[columnKey(h)]
+		if !ok {
+			return nil, fmt.Errorf("line 1: unknown column %q (use %s)", strings.TrimSpace(h), strings.Join(columns, ", "))
+		}
+		names[i] = name
+		hasRequired = hasRequired || name == required
+	}
+	if !hasRequired {
+		return nil, fmt.Errorf("line 1: the header has no %s column", required)
+	}
+
+	var rows []Row
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		row := Row{Line: line, values: map[string]string{}}
+		blank := true
+		for i, v := range record {
+			v = strings.TrimSpace(v)
+			if i >= len(names) || names[i] == "" {
+				if v != "" {
+					return nil, row.Errorf("value %q has no column", v)
+				}
+				continue
+			}
+			row.values[names[i]] = v
+			blank = blank && v == ""
+		}
+		if blank {
+			continue
+		}
+		if row.Get(required) == "" {
+			return nil, row.Errorf("%s is empty", required)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// columnKey folds a header for matching.
+func columnKey(s string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// Bool reads a yes/no cell: true, yes, y, 1, or x, and false, no, n, 0, or
+// empty.
+func Bool(row Row, column string) (bool, error) {
+	switch strings.ToLower(row.Get(column)) {
+	case "true", "yes", "y", "1", "x":
+		return true, nil
+	case "false", "no", "n", "0", "":
+		return false, nil
+	}
+	return false, row.Errorf("%s %q is not yes or no", column, row.Get(column))
+}
+
+// Float reads a number cell, allowing a leading "$"; empty is zero.
+func Float(row Row, column string) (float64, error) {
+	v := strings.TrimPrefix(row.Get(column), "$")
+	if v == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, row.Errorf("%s %q is not a number", column, row.Get(column))
+	}
+	return f, nil
+}
+
+// Int reads a whole-number cell; empty is zero.
+func Int(row Row, column string) (int, error) {
+	v := row.Get(column)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, row.Errorf("%s %q is not a whole number", column, v)
+	}
+	return n, nil
+}
+
+// List splits a cell holding several values separated by semicolons or
+// commas.
+func List(row Row, column string) []string {
+	var out []string
+	for _, v := range strings.FieldsFunc(row.Get(column), func(r rune) bool { return r == ';' || r == ',' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package csvimport_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/csvimport"
+)
+
+func TestRead(t *testing.T) {
+	input := "\ufeffName, Max Price,BOGO\n" +
+		"coffee,$3.50,yes\n" +
+		",,\n" +
+		"\"eggs, large\",,no\n"
+	rows, err := csvimport.Read(strings.NewReader(input), []string{"name", "max_price", "bogo"}, "name")
+	require.NoError(t, err)
+	require.Len(t, rows, 2, "blank rows are skipped")
+
+	assert.Equal(t, "coffee", rows[0].Get("name"))
+	price, err := csvimport.Float(rows[0], "max_price")
+	require.NoError(t, err)
+	assert.InDelta(t, 3.5, price, 0.001)
+	bogo, err := csvimport.Bool(rows[0], "bogo")
+	require.NoError(t, err)
+	assert.True(t, bogo)
+
+	assert.Equal(t, "eggs, large", rows[1].Get("name"))
+	assert.Equal(t, 4, rows[1].Line)
+	assert.Equal(t, []string{"eggs", "large"}, csvimport.List(rows[1], "name"))
+}
+
+func TestRead_Semicolons(t *testing.T) {
+	rows, err := csvimport.Read(strings.NewReader("name;qty\nmilk;2\n"), []string{"name", "qty"}, "name")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	n, err := csvimport.Int(rows[0], "qty")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestRead_Errors(t *testing.T) {
+	columns := []string{"name", "qty"}
+	_, err := csvimport.Read(strings.NewReader(""), columns, "name")
+	assert.ErrorContains(t, err, "the file is empty")
+
+	_, err = csvimport.Read(strings.NewReader("name,quantity\n"), columns, "name")
+	assert.ErrorContains(t, err, `line 1: unknown column "quantity" (use name, qty)`)
+
+	_, err = csvimport.Read(strings.NewReader("qty\n2\n"), columns, "name")
+	assert.ErrorContains(t, err, "line 1: the header has no name column")
+
+	_, err = csvimport.Read(strings.NewReader("name,qty\nmilk,1\n,2\n"), columns, "name")
+	assert.ErrorContains(t, err, "line 3: name is empty")
+
+	_, err = csvimport.Read(strings.NewReader("name\nmilk,2\n"), columns, "name")
+	assert.ErrorContains(t, err, `line 2: value "2" has no column`)
+
+	rows, err := csvimport.Read(strings.NewReader("name,qty\nmilk,two\n"), columns, "name")
+	require.NoError(t, err)
+	_, err = csvimport.Int(rows[0], "qty")
+	assert.ErrorContains(t, err, `line 2: qty "two" is not a whole number`)
+	_, err = csvimport.Bool(rows[0], "qty")
+	assert.ErrorContains(t, err, "is not yes or no")
+}
@@ -0,0 +1,468 @@
+// Package daemon keeps weekly ads warm in memory and serves them to the CLI
+// over a unix socket.
+//
+// The socket speaks the same HTTP API shape as the upstream Publix services
+// (savings by PublixStore header, store lookup by zipCode), so the CLI reuses
+// its regular api.Client with a unix-socket transport.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/server"
+)
+
+const (
+	// EnvSocket overrides the daemon socket path.
+	EnvSocket = "PUBCLI_DAEMON_SOCKET"
+	// EnvDisable makes the CLI skip the daemon and always fetch directly.
+	EnvDisable = "PUBCLI_NO_DAEMON"
+
+	// DefaultRefresh is how often warm ads are checked for updates.
+	DefaultRefresh = 15 * time.Minute
+
+	// MaxStores is how many stores' ads are held warm at once. Warming one
+	// more drops the store used least recently.
+	MaxStores = 64
+
+	// SocketHost is a placeholder host for URLs routed over the socket.
+	SocketHost  = "http://pubcli-daemon"
+	savingsPath = "/savings"
+	storesPath  = "/storelocation"
+)
+
+// SocketPath returns the unix socket path used by the daemon and the CLI.
+// Without XDG_RUNTIME_DIR it lives in a per-user directory under the temp
+// directory, which Listen creates with mode 0700.
+func SocketPath() string {
+	if path := os.Getenv(EnvSocket); path != "" {
+		return path
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "pubcli.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("pubcli-%d", os.Getuid()), "pubcli.sock")
+}
+
+// checkSocketDir refuses a socket directory another user owns or can
+// write to, where they could put their own socket in the daemon's place.
+func checkSocketDir(dir string) error {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !ownedByUser(fi) || fi.Mode().Perm()&0o022 != 0 {
+		return fmt.Errorf("socket directory %s must be owned by you and not writable by others (mode %s)", dir, fi.Mode().Perm())
+	}
+	return nil
+}
+
+// checkSocket refuses to talk to a socket at path unless the current user
+// owns it and its directory, so another local user cannot stand in for the
+// daemon and serve fake deals.
+func checkSocket(path string) error {
+	if err := checkSocketDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if fi.Mode().Type() != os.ModeSocket || !ownedByUser(fi) {
+		return fmt.Errorf("%s is not a socket owned by you", path)
+	}
+	return nil
+}
+
+type warmAd struct {
+	resp      *api.SavingsResponse
+	body      []byte
+	fetchedAt time.Time
+	// usedAt is when the ad was last served, in Unix nanoseconds.
+	usedAt atomic.Int64
+}
+
+// Event is a change notification streamed from /v1/events.
+type Event struct {
+	// Type is "warmed", "updated", or "refresh_failed".
+	Type    string    `json:"type"`
+	Store   string    `json:"store"`
+	Deals   int       `json:"deals,omitempty"`
+	Updated string    `json:"updated,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Daemon holds warm weekly ads keyed by store number.
+type Daemon struct {
+	upstream *api.Client
+	refresh  time.Duration
+	logf     func(format string, args ...any)
+
+	mu          sync.RWMutex
+	ads         map[string]*warmAd
+	stores      map[string][]api.Store
+	subscribers map[chan Event]struct{}
+}
+
+// New creates a daemon that fetches from upstream. A nil logf discards logs.
+func New(upstream *api.Client, refresh time.Duration, logf func(format string, args ...any)) *Daemon {
+	if refresh <= 0 {
+		refresh = DefaultRefresh
+	}
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	return &Daemon{
+		upstream:    upstream,
+		refresh:     refresh,
+		logf:        logf,
+		ads:         make(map[string]*warmAd),
+		stores:      make(map[string][]api.Store),
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Warm fetches a store's ad into memory and keeps it refreshed from then on.
+func (d *Daemon) Warm(ctx context.Context, storeNumber string) error {
+	_, err := d.fetchAd(ctx, storeNumber)
+	return err
+}
+
+// Stores returns the store numbers currently held warm.
+func (d *Daemon) Stores() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make([]string, 0, len(d.ads))
+	for store := range d.ads {
+		out = append(out, store)
+	}
+	return out
+}
+
+// Run re-fetches every warm ad on the refresh interval until ctx is done.
+func (d *Daemon) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.RefreshAll(ctx)
+		}
+	}
+}
+
+// RefreshAll re-fetches every warm ad once. An ad whose version upstream is
+// unchanged is kept without downloading it again. Failed refreshes keep
+// serving the previous copy.
+func (d *Daemon) RefreshAll(ctx context.Context) {
+	for _, store := range d.Stores() {
+		if err := d.refreshAd(ctx, store); err != nil {
+			d.logf("store #%s: refresh failed: %v", store, err)
+			d.publish(Event{Type: "refresh_failed", Store: store, Error: err.Error()})
+		}
+	}
+}
+
+func (d *Daemon) refreshAd(ctx context.Context, storeNumber string) error {
+	if prev := d.cachedAd(storeNumber); prev != nil && prev.resp.WeeklyAdLatestUpdatedDateTime != "" {
+		version, err := d.upstream.FetchAdVersion(ctx, storeNumber)
+		if err != nil {
+			return err
+		}
+		if version == prev.resp.WeeklyAdLatestUpdatedDateTime {
+			return nil
+		}
+	}
+	_, err := d.fetchAd(ctx, storeNumber)
+	return err
+}
+
+func (d *Daemon) cachedAd(storeNumber string) *warmAd {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.ads[storeNumber]
+}
+
+func (d *Daemon) fetchAd(ctx context.Context, storeNumber string) (*warmAd, error) {
+	resp, err := d.upstream.FetchSavings(ctx, storeNumber)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	ad := &warmAd{resp: resp, body: body, fetchedAt: time.Now()}
+	ad.usedAt.Store(ad.fetchedAt.UnixNano())
+
+	d.mu.Lock()
+	prev := d.ads[storeNumber]
+	if prev != nil {
+		ad.usedAt.Store(prev.usedAt.Load())
+	} else if evicted := d.evictLocked(); evicted != "" {
+		d.logf("store #%s: dropped, unused the longest of %d warm stores", evicted, MaxStores)
+	}
+	d.ads[storeNumber] = ad
+	d.mu.Unlock()
+
+	updated := resp.WeeklyAdLatestUpdatedDateTime
+	switch {
+	case prev == nil:
+		d.logf("store #%s: warmed %d deals", storeNumber, len(resp.Savings))
+		d.publish(Event{Type: "warmed", Store: storeNumber, Deals: len(resp.Savings), Updated: updated})
+	case prev.resp.WeeklyAdLatestUpdatedDateTime != updated:
+		d.logf("store #%s: weekly ad updated (%s)", storeNumber, updated)
+		d.publish(Event{Type: "updated", Store: storeNumber, Deals: len(resp.Savings), Updated: updated})
+	}
+	return ad, nil
+}
+
+// evictLocked makes room for one more store when MaxStores are warm by
+// dropping the one served least recently, and returns its number. d.mu
+// must be held for writing.
+func (d *Daemon) evictLocked() string {
+	if len(d.ads) < MaxStores {
+		return ""
+	}
+	oldest := ""
+	var oldestAt int64
+	for store, ad := range d.ads {
+		if at := ad.usedAt.Load(); oldest == "" || at < oldestAt {
+			oldest, oldestAt = store, at
+		}
+	}
+	delete(d.ads, oldest)
+	return oldest
+}
+
+func (d *Daemon) warmOrFetch(ctx context.Context, storeNumber string) (*warmAd, error) {
+	if ad := d.cachedAd(storeNumber); ad != nil {
+		ad.usedAt.Store(time.Now().UnixNano())
+		return ad, nil
+	}
+	return d.fetchAd(ctx, storeNumber)
+}
+
+// FetchSavings returns a store's ad from memory, warming it on first use.
+// The response is shared and must not be modified.
+func (d *Daemon) FetchSavings(ctx context.Context, storeNumber string) (*api.SavingsResponse, error) {
+	ad, err := d.warmOrFetch(ctx, storeNumber)
+	if err != nil {
+		return nil, err
+	}
+	return ad.resp, nil
+}
+
+// FetchStores looks up stores near a ZIP code. Store locations rarely
+// change, so results are kept for the daemon's lifetime.
+func (d *Daemon) FetchStores(ctx context.Context, zipCode string, count int) ([]api.Store, error) {
+	key := zipCode + "|" + strconv.Itoa(count)
+	d.mu.RLock()
+	stores, ok := d.stores[key]
+	d.mu.RUnlock()
+	if ok {
+		return stores, nil
+	}
+
+	stores, err := d.upstream.FetchStores(ctx, zipCode, count)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	d.stores[key] = stores
+	d.mu.Unlock()
+	return stores, nil
+}
+
+// Subscribe returns a channel of events and a function that ends the
+// subscription. Slow subscribers miss events rather than block the daemon.
+func (d *Daemon) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+	d.mu.Lock()
+	d.subscribers[ch] = struct{}{}
+	d.mu.Unlock()
+	return ch, func() {
+		d.mu.Lock()
+		delete(d.subscribers, ch)
+		d.mu.Unlock()
+	}
+}
+
+func (d *Daemon) publish(ev Event) {
+	ev.Time = time.Now()
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for ch := range d.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Handler serves two APIs on the socket:
+//   - /savings and /storelocation mirror the upstream Publix API, used by the
+//     CLI fast path;
+//   - /v1/... is the JSON control API for other local tools: /v1/deals,
+//     /v1/categories, /v1/stores, and /v1/compare (same parameters as
+//     `pubcli serve`), plus /v1/events, an NDJSON stream of Event values.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"status": "ok", "stores": d.Stores()})
+	})
+	mux.HandleFunc("GET "+savingsPath, d.handleSavings)
+	mux.HandleFunc("GET "+storesPath, d.handleStores)
+	mux.HandleFunc("GET /v1/events", d.handleEvents)
+	mux.Handle("/v1/", http.StripPrefix("/v1", server.New(d, server.Config{}).Handler()))
+	return mux
+}
+
+func (d *Daemon) handleSavings(w http.ResponseWriter, r *http.Request) {
+	storeNumber := r.Header.Get("PublixStore")
+	if storeNumber == "" {
+		http.Error(w, "PublixStore header is required", http.StatusBadRequest)
+		return
+	}
+
+	ad, err := d.warmOrFetch(r.Context(), storeNumber)
+	if err != nil {
+		upstreamFailed(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(ad.body)
+}
+
+func (d *Daemon) handleStores(w http.ResponseWriter, r *http.Request) {
+	zip := r.URL.Query().Get("zipCode")
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if zip == "" || err != nil {
+		http.Error(w, "zipCode and count are required", http.StatusBadRequest)
+		return
+	}
+
+	stores, err := d.FetchStores(r.Context(), zip, count)
+	if err != nil {
+		upstreamFailed(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(api.StoreResponse{Stores: stores})
+}
+
+// upstreamFailed answers a failed upstream fetch: 503 while the circuit
+// breaker is open, so clients can tell it apart, and 502 otherwise.
+func upstreamFailed(w http.ResponseWriter, err error) {
+	if errors.Is(err, api.ErrCircuitOpen) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
+
+func (d *Daemon) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	events, cancel := d.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-events:
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// Listen opens the unix socket at path. A leftover socket from a daemon that
+// is no longer running is removed; a live one is reported as an error. The
+// socket's directory is created with mode 0700 and refused when another
+// user owns it or can write to it.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating socket directory: %w", err)
+	}
+	if err := checkSocketDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	if Running(path) {
+		return nil, fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("removing stale socket: %w", err)
+	}
+	return net.Listen("unix", path)
+}
+
+// Running reports whether a daemon accepts connections on path. A socket
+// checkSocket refuses does not count.
+func Running(path string) bool {
+	if checkSocket(path) != nil {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", path, 100*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// HTTPClient returns an HTTP client whose requests are sent to the daemon at
+// path. Use it with URLs on any host, e.g. "http://pubcli-daemon/v1/deals".
+// Every connection is checked with checkSocket first.
+func HTTPClient(path string) *http.Client {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			if err := checkSocket(path); err != nil {
+				return nil, err
+			}
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", path)
+		},
+	}
+	return &http.Client{Transport: transport}
+}
+
+// Client returns an API client that talks to the daemon at path, or false
+// when no daemon is running there (or the fast path is disabled). opts are
+// applied after the client is pointed at the socket.
+func Client(path string, opts ...api.Option) (*api.Client, bool) {
+	if os.Getenv(EnvDisable) != "" || !Running(path) {
+		return nil, false
+	}
+	hc := HTTPClient(path)
+	hc.Timeout = 15 * time.Second
+	opts = append([]api.Option{api.WithHTTPClient(hc)}, opts...)
+	return api.NewClientWithBaseURLs(SocketHost+savingsPath, SocketHost+storesPath, opts...), true
+}
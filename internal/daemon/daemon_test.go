@@ -0,0 +1,199 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/daemon"
+)
+
+func startDaemon(t *testing.T) (*daemon.Daemon, string, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("zipCode") != "" {
+			json.NewEncoder(w).Encode(api.StoreResponse{Stores: []api.Store{{Key: "01425"}}})
+			return
+		}
+		calls.Add(1)
+		json.NewEncoder(w).Encode(api.SavingsResponse{Savings: []api.SavingItem{{ID: r.Header.Get("PublixStore")}}})
+	}))
+	t.Cleanup(upstream.Close)
+
+	// Unix socket paths are length-limited, so avoid the long t.TempDir().
+	dir, err := os.MkdirTemp("", "pubcli")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "d.sock")
+
+	d := daemon.New(api.NewClientWithBaseURLs(upstream.URL, upstream.URL), 0, nil)
+	listener, err := daemon.Listen(path)
+	require.NoError(t, err)
+	srv := &http.Server{Handler: d.Handler()}
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Close() })
+	return d, path, &calls
+}
+
+func TestClient_ReadsThroughWarmCache(t *testing.T) {
+	d, path, calls := startDaemon(t)
+	require.NoError(t, d.Warm(context.Background(), "1425"))
+
+	client, ok := daemon.Client(path)
+	require.True(t, ok)
+	for range 3 {
+		resp, err := client.FetchSavings(context.Background(), "1425")
+		require.NoError(t, err)
+		require.Len(t, resp.Savings, 1)
+		assert.Equal(t, "1425", resp.Savings[0].ID)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	stores, err := client.FetchStores(context.Background(), "33101", 1)
+	require.NoError(t, err)
+	assert.Len(t, stores, 1)
+
+	d.RefreshAll(context.Background())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_UnknownStoreIsWarmedOnDemand(t *testing.T) {
+	d, path, _ := startDaemon(t)
+	client, ok := daemon.Client(path)
+	require.True(t, ok)
+
+	_, err := client.FetchSavings(context.Background(), "1500")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1500"}, d.Stores())
+}
+
+func TestClient_FallsBackWhenNotRunning(t *testing.T) {
+	_, ok := daemon.Client(filepath.Join(t.TempDir(), "missing.sock"))
+	assert.False(t, ok)
+
+	_, path, _ := startDaemon(t)
+	t.Setenv(daemon.EnvDisable, "1")
+	_, ok = daemon.Client(path)
+	assert.False(t, ok)
+}
+
+func TestWarm_DropsLeastRecentlyUsedStore(t *testing.T) {
+	d, _, _ := startDaemon(t)
+	ctx := context.Background()
+	for i := range daemon.MaxStores {
+		require.NoError(t, d.Warm(ctx, strconv.Itoa(1000+i)))
+		time.Sleep(time.Millisecond)
+	}
+	_, err := d.FetchSavings(ctx, "1000")
+	require.NoError(t, err)
+
+	require.NoError(t, d.Warm(ctx, "2000"))
+	stores := d.Stores()
+	assert.Len(t, stores, daemon.MaxStores)
+	assert.Contains(t, stores, "1000", "the store just served is kept")
+	assert.NotContains(t, stores, "1001", "the store unused the longest is dropped")
+	assert.Contains(t, stores, "2000")
+}
+
+func TestListen_RejectsLiveSocket(t *testing.T) {
+	_, path, _ := startDaemon(t)
+	_, err := daemon.Listen(path)
+	assert.ErrorContains(t, err, "already listening")
+}
+
+func TestClient_RefusesSocketInSharedDirectory(t *testing.T) {
+	_, path, _ := startDaemon(t)
+	_, ok := daemon.Client(path)
+	require.True(t, ok)
+
+	dir := filepath.Dir(path)
+	require.NoError(t, os.Chmod(dir, 0o777))
+	t.Cleanup(func() { os.Chmod(dir, 0o700) })
+	_, ok = daemon.Client(path)
+	assert.False(t, ok, "others could have replaced the socket")
+	_, err := daemon.HTTPClient(path).Get(daemon.SocketHost + "/healthz")
+	assert.ErrorContains(t, err, "not writable by others")
+
+	_, err = daemon.Listen(filepath.Join(dir, "other.sock"))
+	assert.ErrorContains(t, err, "not writable by others")
+}
+
+func TestSocketPath_PerUserDirectory(t *testing.T) {
+	t.Setenv(daemon.EnvSocket, "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	path := daemon.SocketPath()
+	assert.Equal(t, os.TempDir(), filepath.Dir(filepath.Dir(path)), "the socket sits in its own directory")
+}
+
+func TestControlAPI_ServesDealsAndStreamsEvents(t *testing.T) {
+	d, path, _ := startDaemon(t)
+	hc := daemon.HTTPClient(path)
+
+	resp, err := hc.Get(daemon.SocketHost + "/v1/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	require.NoError(t, d.Warm(context.Background(), "1425"))
+	var ev daemon.Event
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&ev))
+	assert.Equal(t, "warmed", ev.Type)
+	assert.Equal(t, "1425", ev.Store)
+	assert.Equal(t, 1, ev.Deals)
+
+	deals, err := hc.Get(daemon.SocketHost + "/v1/deals?store=1425")
+	require.NoError(t, err)
+	defer deals.Body.Close()
+	require.Equal(t, http.StatusOK, deals.StatusCode)
+	var payload struct {
+		Deals []json.RawMessage `json:"deals"`
+	}
+	require.NoError(t, json.NewDecoder(deals.Body).Decode(&payload))
+	assert.Len(t, payload.Deals, 1)
+}
+
+func TestRefreshAll_SkipsUnchangedAd(t *testing.T) {
+	var updated atomic.Value
+	updated.Store("a")
+	var full, checks atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageSize") == "1" {
+			checks.Add(1)
+		} else {
+			full.Add(1)
+		}
+		json.NewEncoder(w).Encode(api.SavingsResponse{
+			Savings:                       []api.SavingItem{{ID: "1"}},
+			WeeklyAdLatestUpdatedDateTime: updated.Load().(string),
+		})
+	}))
+	defer upstream.Close()
+
+	d := daemon.New(api.NewClientWithBaseURLs(upstream.URL, upstream.URL), 0, nil)
+	events, cancel := d.Subscribe()
+	defer cancel()
+	require.NoError(t, d.Warm(context.Background(), "1425"))
+	assert.Equal(t, "warmed", (<-events).Type)
+
+	d.RefreshAll(context.Background())
+	assert.Equal(t, int32(1), full.Load())
+	assert.Equal(t, int32(1), checks.Load())
+
+	updated.Store("b")
+	d.RefreshAll(context.Background())
+	assert.Equal(t, int32(2), full.Load())
+	ev := <-events
+	assert.Equal(t, "updated", ev.Type)
+	assert.Equal(t, "b", ev.Updated)
+}
@@ -0,0 +1,144 @@
+// Package diag tallies the upstream failures of commands that make many
+// Publix API requests, so one bad store does not go unnoticed in a run that
+// otherwise succeeded.
+package diag
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+// maxSamples is how many distinct messages are kept per error type.
+const maxSamples = 3
+
+// Summary is the diagnostics block of a command's JSON output.
+type Summary struct {
+	Requests int `json:"requests"`
+	Failed   int `json:"failed"`
+	// Retries is how many times requests were sent again after a
+	// transient failure, whether or not a retry then succeeded.
+	Retries int     `json:"retries"`
+	Errors  []Group `json:"errors"`
+}
+
+// Group is the failures of one type, such as "http_503" or "network".
+type Group struct {
+	Type    string   `json:"type"`
+	Count   int      `json:"count"`
+	Samples []string `json:"samples"`
+}
+
+// Recorder counts API requests and their retries and groups their
+// failures by type. It is safe for concurrent use; pass Record to
+// api.WithObserver.
+type Recorder struct {
+	mu       sync.Mutex
+	requests int
+	failed   int
+	retries  int
+	groups   map[string]*Group
+}
+
+// Record notes one finished request, sent attempts times. err is nil for a
+// success.
+func (r *Recorder) Record(storeNumber string, attempts int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests++
+	if attempts > 1 {
+		r.retries += attempts - 1
+	}
+	if err == nil {
+		return
+	}
+	r.failed++
+	if r.groups == nil {
+		r.groups = map[string]*Group{}
+	}
+	kind := Type(err)
+	g, ok := r.groups[kind]
+	if !ok {
+		g = &Group{Type: kind}
+		r.groups[kind] = g
+	}
+	g.Count++
+	msg := err.Error()
+	if storeNumber != "" {
+		msg = fmt.Sprintf("store #%s: %s", storeNumber, msg)
+	}
+	if len(g.Samples) < maxSamples && !slices.Contains(g.Samples, msg) {
+		g.Samples = append(g.Samples, msg)
+	}
+}
+
+// Summary returns the tally, or nil when no request failed or was retried.
+// Groups are ordered by count, most frequent first.
+func (r *Recorder) Summary() *Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failed == 0 && r.retries == 0 {
+		return nil
+	}
+	s := &Summary{Requests: r.requests, Failed: r.failed, Retries: r.retries, Errors: make([]Group, 0, len(r.groups))}
+	for _, g := range r.groups {
+		s.Errors = append(s.Errors, *g)
+	}
+	sort.Slice(s.Errors, func(a, b int) bool {
+		if s.Errors[a].Count != s.Errors[b].Count {
+			return s.Errors[a].Count > s.Errors[b].Count
+		}
+		return s.Errors[a].Type < s.Errors[b].Type
+	})
+	return s
+}
+
+// String is the one-line form of the summary for text output, e.g.
+// "2 of 6 upstream requests failed (http_503 ×2) after 3 retries".
+func (s *Summary) String() string {
+	var line string
+	if s.Failed == 0 {
+		line = fmt.Sprintf("all %d upstream requests succeeded", s.Requests)
+	} else {
+		parts := make([]string, 0, len(s.Errors))
+		for _, g := range s.Errors {
+			parts = append(parts, fmt.Sprintf("%s ×%d", g.Type, g.Count))
+		}
+		line = fmt.Sprintf("%d of %d upstream requests failed (%s)", s.Failed, s.Requests, strings.Join(parts, ", "))
+	}
+	switch s.Retries {
+	case 0:
+	case 1:
+		line += " after 1 retry"
+	default:
+		line += fmt.Sprintf(" after %d retries", s.Retries)
+	}
+	return line
+}
+
+// Type names the kind of an API error: "http_<status>", "network",
+// "timeout", "malformed_response", "circuit_open", or "other".
+func Type(err error) string {
+	var status *api.StatusError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &status):
+		return fmt.Sprintf("http_%d", status.Code)
+	case errors.Is(err, api.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, api.ErrDecode):
+		return "malformed_response"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, api.ErrRequest):
+		return "network"
+	default:
+		return "other"
+	}
+}
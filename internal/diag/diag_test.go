@@ -0,0 +1,72 @@
+package diag_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/diag"
+)
+
+func TestRecorder_GroupsFailuresByType(t *testing.T) {
+	var rec diag.Recorder
+	rec.Record("", 1, nil)
+	for _, store := range []string{"1425", "1500", "1501", "1502"} {
+		rec.Record(store, 1, &api.StatusError{Code: 503, URL: "https://services.publix.com/api/v4/savings"})
+	}
+	rec.Record("1600", 1, &api.MalformedResponseError{Response: "savings", Reason: "truncated JSON", Offset: 10})
+
+	s := rec.Summary()
+	require.NotNil(t, s)
+	assert.Equal(t, 6, s.Requests)
+	assert.Equal(t, 5, s.Failed)
+	require.Len(t, s.Errors, 2)
+	assert.Equal(t, "http_503", s.Errors[0].Type)
+	assert.Equal(t, 4, s.Errors[0].Count)
+	assert.Len(t, s.Errors[0].Samples, 3)
+	assert.Equal(t, "store #1425: unexpected status 503 from https://services.publix.com/api/v4/savings", s.Errors[0].Samples[0])
+	assert.Equal(t, "malformed_response", s.Errors[1].Type)
+	assert.Equal(t, "5 of 6 upstream requests failed (http_503 ×4, malformed_response ×1)", s.String())
+}
+
+func TestRecorder_NoFailuresHasNoSummary(t *testing.T) {
+	var rec diag.Recorder
+	rec.Record("1425", 1, nil)
+	assert.Nil(t, rec.Summary())
+}
+
+func TestRecorder_CountsRetries(t *testing.T) {
+	var rec diag.Recorder
+	rec.Record("1425", 3, nil)
+	rec.Record("1500", 1, nil)
+
+	s := rec.Summary()
+	require.NotNil(t, s, "retried requests are reported even when they succeeded")
+	assert.Equal(t, 2, s.Retries)
+	assert.Zero(t, s.Failed)
+	assert.Equal(t, "all 2 upstream requests succeeded after 2 retries", s.String())
+
+	rec.Record("1501", 2, &api.StatusError{Code: 503, URL: "https://services.publix.com/api/v4/savings"})
+	assert.Equal(t, "1 of 3 upstream requests failed (http_503 ×1) after 3 retries", rec.Summary().String())
+}
+
+func TestType(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("fetching savings: %w", &api.StatusError{Code: 404}), "http_404"},
+		{&api.CircuitOpenError{Failures: 3, Until: time.Now()}, "circuit_open"},
+		{&api.RequestError{Err: context.DeadlineExceeded}, "timeout"},
+		{&api.RequestError{Err: errors.New("connection refused")}, "network"},
+		{errors.New("boom"), "other"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, diag.Type(tt.err), tt.err.Error())
+	}
+}
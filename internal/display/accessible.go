@@ -0,0 +1,98 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+var accessible bool
+
+// SetAccessible switches text output to a linear, labeled form without
+// color, box-drawing, or symbol-only cues so screen readers can follow it.
+func SetAccessible(on bool) {
+	accessible = on
+}
+
+// Accessible reports whether accessible text output is enabled.
+func Accessible() bool {
+	return accessible
+}
+
+func printDealsAccessible(w io.Writer, items []api.SavingItem, by string) {
+	fmt.Fprint(w, T("a11y.deals_header", len(items)))
+	if len(items) > 0 && items[0].StartFormatted != "" {
+		fmt.Fprint(w, T("a11y.deals_valid", FormatDealDate(items[0].StartFormatted), FormatDealDate(items[0].EndFormatted)))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w)
+
+	for i, item := range items {
+		if by != "" && (i == 0 || filter.Group(item, by) != filter.Group(items[i-1], by)) {
+			fmt.Fprintln(w, T("a11y.deals_group", groupLabel(item, by), groupSize(items, i, by)))
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, T("a11y.deal", i+1, len(items), fallbackDealTitle(item)))
+		for _, field := range AccessibleDealFields(item) {
+			fmt.Fprintf(w, "  %s: %s\n", field[0], field[1])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// AccessibleDealFields returns the labeled fields of a deal in reading order,
+// skipping empty values. The TUI's linear layout reuses it.
+func AccessibleDealFields(item api.SavingItem) [][2]string {
+	fields := [][2]string{}
+	add := func(label, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fields = append(fields, [2]string{label, value})
+		}
+	}
+
+	if filter.ContainsIgnoreCase(item.Categories, "bogo") {
+		add(T("field.offer"), T("field.bogo"))
+	}
+	add(T("field.avoid"), strings.Join(item.Flags, ", "))
+	add(T("field.savings"), filter.CleanText(filter.Deref(item.Savings)))
+	add(T("field.deal_info"), filter.CleanText(filter.Deref(item.AdditionalDealInfo)))
+	add(T("field.description"), filter.CleanText(filter.Deref(item.Description)))
+	add(T("field.department"), filter.CleanText(filter.Deref(item.Department)))
+	add(T("field.brand"), filter.CleanText(filter.Deref(item.Brand)))
+	if item.StartFormatted != "" && item.EndFormatted != "" {
+		add(T("field.valid"), T("range.to", FormatDealDate(item.StartFormatted), FormatDealDate(item.EndFormatted)))
+	}
+	add(T("field.stores"), strings.Join(item.Stores, ", "))
+	return fields
+}
+
+func printStoresAccessible(w io.Writer, stores []api.Store, zipCode string) {
+	fmt.Fprintf(w, "%s\n\n", T("a11y.stores_header", zipCode, len(stores)))
+	for i, s := range stores {
+		fmt.Fprintln(w, T("a11y.store", i+1, len(stores), api.StoreNumber(s.Key), s.Name))
+		fmt.Fprintf(w, "  %s: %s, %s, %s %s\n", T("field.address"), s.Addr, s.City, s.State, s.Zip)
+		if s.Distance != "" {
+			fmt.Fprintf(w, "  %s: %s\n", T("field.distance"), T("stores.miles", s.Distance))
+		}
+		if open, known := s.OpenAt(time.Now()); known {
+			status := T("stores.open")
+			if !open {
+				status = closedText(s)
+			}
+			fmt.Fprintf(w, "  %s: %s\n", T("field.status"), status)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func printCategoriesAccessible(w io.Writer, sorted []categoryCount, storeNumber string) {
+	fmt.Fprintln(w, T("a11y.categories_header", storeNumber, len(sorted)))
+	for _, c := range sorted {
+		fmt.Fprintf(w, "  %s: %s\n", c.Name, T("categories.deals", c.Count))
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,55 @@
+package display_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func withAccessible(t *testing.T) {
+	t.Helper()
+	display.SetAccessible(true)
+	t.Cleanup(func() { display.SetAccessible(false) })
+}
+
+func TestPrintDeals_AccessibleIsLinearAndLabeled(t *testing.T) {
+	withAccessible(t)
+
+	var buf bytes.Buffer
+	display.PrintDeals(&buf, sampleDeals())
+	output := buf.String()
+
+	assert.Contains(t, output, "Publix weekly deals. 2 items. Valid 2/18 to 2/24.")
+	assert.Contains(t, output, "Deal 1 of 2: Chicken Breasts")
+	assert.Contains(t, output, "  Savings: $3.99 lb")
+	assert.Contains(t, output, "Deal 2 of 2: Nutella & More")
+	assert.Contains(t, output, "  Offer: Buy one, get one free")
+	assert.NotContains(t, output, "\x1b[")
+	assert.NotContains(t, output, "—")
+}
+
+func TestPrintStores_Accessible(t *testing.T) {
+	withAccessible(t)
+
+	var buf bytes.Buffer
+	display.PrintStores(&buf, []api.Store{{Key: "01425", Name: "Peachers Mill", Addr: "1 Main", City: "Clarksville", State: "TN", Zip: "37042", Distance: "5"}}, "37042")
+	output := buf.String()
+
+	assert.Contains(t, output, "Store 1 of 1: number 1425, Peachers Mill")
+	assert.Contains(t, output, "  Distance: 5 miles")
+}
+
+func TestPrintCategories_Accessible(t *testing.T) {
+	withAccessible(t)
+
+	var buf bytes.Buffer
+	display.PrintCategories(&buf, map[string]int{"bogo": 3, "meat": 1}, "1425")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	assert.Equal(t, "Categories for store number 1425 this week. 2 categories.", lines[0])
+	assert.Equal(t, "  bogo: 3 deals", lines[1])
+}
@@ -0,0 +1,109 @@
+package display
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// AlfredOutput is the Script Filter JSON read by Alfred and Raycast.
+type AlfredOutput struct {
+	Items []AlfredItem `json:"items"`
+}
+
+// AlfredItem is one row of Script Filter results.
+type AlfredItem struct {
+	UID          string      `json:"uid,omitempty"`
+	Title        string      `json:"title"`
+	Subtitle     string      `json:"subtitle"`
+	Arg          string      `json:"arg"`
+	Icon         *AlfredIcon `json:"icon,omitempty"`
+	QuickLookURL string      `json:"quicklookurl,omitempty"`
+	Text         *AlfredText `json:"text,omitempty"`
+	Valid        bool        `json:"valid"`
+}
+
+// AlfredIcon points at the row's icon.
+type AlfredIcon struct {
+	Path string `json:"path"`
+}
+
+// AlfredText is what ⌘C copies and ⌘L shows in large type.
+type AlfredText struct {
+	Copy      string `json:"copy,omitempty"`
+	LargeType string `json:"largetype,omitempty"`
+}
+
+// PrintDealsAlfred renders deals as Script Filter items. The arg is the deal
+// title so the default action can paste it into a shopping list.
+func PrintDealsAlfred(w io.Writer, items []api.SavingItem) error {
+	out := AlfredOutput{Items: make([]AlfredItem, 0, len(items))}
+	for _, item := range items {
+		title := filter.Title(item)
+		savings := filter.CleanText(filter.Deref(item.Savings))
+
+		row := AlfredItem{
+			UID:      item.ID,
+			Title:    title,
+			Subtitle: joinNonEmpty(" · ", savings, filter.CleanText(filter.Deref(item.Department)), validityLabel(item)),
+			Arg:      title,
+			Valid:    true,
+		}
+		if img := filter.Deref(item.ImageURL); img != "" {
+			row.Icon = &AlfredIcon{Path: img}
+			row.QuickLookURL = img
+		}
+		copyText := joinNonEmpty(" — ", title, savings)
+		row.Text = &AlfredText{Copy: copyText, LargeType: copyText}
+		out.Items = append(out.Items, row)
+	}
+	return encodeAlfred(w, out)
+}
+
+// PrintStoresAlfred renders stores as Script Filter items whose arg is the
+// store number, ready to feed into `pubcli --store`.
+func PrintStoresAlfred(w io.Writer, stores []api.Store) error {
+	out := AlfredOutput{Items: make([]AlfredItem, 0, len(stores))}
+	for _, s := range stores {
+		store := ToStoreJSON(s)
+		distance := ""
+		if s.Distance != "" {
+			distance = s.Distance + " mi"
+		}
+		out.Items = append(out.Items, AlfredItem{
+			UID:      "store-" + store.Number,
+			Title:    "#" + store.Number + " " + store.Name,
+			Subtitle: joinNonEmpty(" · ", store.Address, distance),
+			Arg:      store.Number,
+			Text:     &AlfredText{Copy: store.Number, LargeType: store.Address},
+			Valid:    true,
+		})
+	}
+	return encodeAlfred(w, out)
+}
+
+func encodeAlfred(w io.Writer, out AlfredOutput) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(out)
+}
+
+func validityLabel(item api.SavingItem) string {
+	if item.EndFormatted == "" {
+		return ""
+	}
+	return T("expiry.through", FormatDealDate(item.EndFormatted))
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := parts[:0:0]
+	for _, p := range parts {
+		if strings.TrimSpace(p) != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
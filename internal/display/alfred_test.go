@@ -0,0 +1,49 @@
+package display_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func TestPrintDealsAlfred(t *testing.T) {
+	deals := sampleDeals()
+	deals[0].ImageURL = ptr("https://example.com/chicken.jpg")
+
+	var buf bytes.Buffer
+	require.NoError(t, display.PrintDealsAlfred(&buf, deals))
+
+	var out display.AlfredOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	require.Len(t, out.Items, 2)
+
+	first := out.Items[0]
+	assert.Equal(t, "Chicken Breasts", first.Title)
+	assert.Equal(t, "Chicken Breasts", first.Arg)
+	assert.Equal(t, "$3.99 lb · Meat · through 2/24", first.Subtitle)
+	require.NotNil(t, first.Icon)
+	assert.Equal(t, "https://example.com/chicken.jpg", first.Icon.Path)
+	assert.True(t, first.Valid)
+
+	assert.Equal(t, "Nutella & More", out.Items[1].Title)
+	assert.Nil(t, out.Items[1].Icon)
+}
+
+func TestPrintStoresAlfred(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, display.PrintStoresAlfred(&buf, []api.Store{
+		{Key: "01425", Name: "Peachers Mill", Addr: "1490 Tiny Town Rd", City: "Clarksville", State: "TN", Zip: "37042", Distance: "1.2"},
+	}))
+
+	var out display.AlfredOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	require.Len(t, out.Items, 1)
+	assert.Equal(t, "#1425 Peachers Mill", out.Items[0].Title)
+	assert.Equal(t, "1425", out.Items[0].Arg)
+	assert.Equal(t, "1490 Tiny Town Rd, Clarksville, TN 37042 · 1.2 mi", out.Items[0].Subtitle)
+}
@@ -0,0 +1,92 @@
+package display
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// Budget caps the size of a JSON deal payload. Zero fields are unlimited.
+type Budget struct {
+	MaxItems int
+	MaxBytes int
+}
+
+// Enabled reports whether any limit is set.
+func (b Budget) Enabled() bool {
+	return b.MaxItems > 0 || b.MaxBytes > 0
+}
+
+// DealsEnvelope is the JSON shape used when a Budget is in effect, so
+// consumers can tell a trimmed result from a complete one.
+type DealsEnvelope struct {
+	SchemaVersion int        `json:"schemaVersion,omitempty"`
+	Deals         []DealJSON `json:"deals"`
+	Truncated     bool       `json:"truncated"`
+	TotalItems    int        `json:"totalItems"`
+}
+
+// TrimDeals keeps the highest-scoring deals that fit the budget. Ties are
+// broken by input position, and kept deals stay in their input order, so the
+// same input and budget always produce the same output.
+func TrimDeals(items []api.SavingItem, budget Budget) DealsEnvelope {
+	all := make([]DealJSON, len(items))
+	for i, item := range items {
+		all[i] = ToDealJSON(item)
+	}
+
+	ranked := make([]int, len(items))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return filter.DealScore(items[ranked[a]]) > filter.DealScore(items[ranked[b]])
+	})
+
+	keep := len(items)
+	if budget.MaxItems > 0 && budget.MaxItems < keep {
+		keep = budget.MaxItems
+	}
+	build := func(n int) DealsEnvelope {
+		chosen := append([]int(nil), ranked[:n]...)
+		sort.Ints(chosen)
+		deals := make([]DealJSON, 0, n)
+		for _, idx := range chosen {
+			deals = append(deals, all[idx])
+		}
+		env := DealsEnvelope{Deals: deals, Truncated: n < len(items), TotalItems: len(items)}
+		if schemaVersion >= SchemaV2 {
+			env.SchemaVersion = schemaVersion
+		}
+		return env
+	}
+
+	if budget.MaxBytes > 0 {
+		// Encoded size grows with every added deal, so binary search finds
+		// the largest prefix of the ranking that still fits.
+		keep = sort.Search(keep+1, func(n int) bool {
+			return encodedSize(build(n)) > budget.MaxBytes
+		}) - 1
+		if keep < 0 {
+			keep = 0
+		}
+	}
+	return build(keep)
+}
+
+// PrintDealsBudgetJSON renders deals trimmed to the budget inside a DealsEnvelope.
+func PrintDealsBudgetJSON(w io.Writer, items []api.SavingItem, budget Budget) error {
+	return json.NewEncoder(w).Encode(TrimDeals(items, budget))
+}
+
+func encodedSize(v any) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	// json.Encoder appends a trailing newline.
+	return len(data) + 1
+}
@@ -0,0 +1,66 @@
+package display_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func scoredDeals() []api.SavingItem {
+	return []api.SavingItem{
+		{ID: "a", Title: ptr("Small"), Savings: ptr("$1.00 off")},
+		{ID: "b", Title: ptr("Big"), Savings: ptr("$9.00 off")},
+		{ID: "c", Title: ptr("Bogo"), Savings: ptr("Buy 1 Get 1 FREE"), Categories: []string{"bogo"}},
+		{ID: "d", Title: ptr("Medium"), Savings: ptr("$4.00 off")},
+	}
+}
+
+func titles(env display.DealsEnvelope) []string {
+	out := make([]string, 0, len(env.Deals))
+	for _, d := range env.Deals {
+		out = append(out, d.Title)
+	}
+	return out
+}
+
+func TestTrimDeals_MaxItemsKeepsTopScoresInInputOrder(t *testing.T) {
+	env := display.TrimDeals(scoredDeals(), display.Budget{MaxItems: 2})
+
+	assert.Equal(t, []string{"Big", "Bogo"}, titles(env))
+	assert.True(t, env.Truncated)
+	assert.Equal(t, 4, env.TotalItems)
+}
+
+func TestTrimDeals_NoTruncationWhenBudgetFits(t *testing.T) {
+	env := display.TrimDeals(scoredDeals(), display.Budget{MaxItems: 10, MaxBytes: 1 << 20})
+
+	assert.Len(t, env.Deals, 4)
+	assert.False(t, env.Truncated)
+}
+
+func TestTrimDeals_MaxBytesIsRespected(t *testing.T) {
+	for _, limit := range []int{10, 200, 400, 800} {
+		var buf bytes.Buffer
+		require.NoError(t, display.PrintDealsBudgetJSON(&buf, scoredDeals(), display.Budget{MaxBytes: limit}))
+
+		var env display.DealsEnvelope
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &env))
+		if len(env.Deals) > 0 {
+			assert.LessOrEqual(t, buf.Len(), limit, fmt.Sprintf("limit %d", limit))
+		}
+		assert.True(t, env.Truncated || len(env.Deals) == 4)
+	}
+}
+
+func TestTrimDeals_IsDeterministic(t *testing.T) {
+	first := display.TrimDeals(scoredDeals(), display.Budget{MaxBytes: 500})
+	second := display.TrimDeals(scoredDeals(), display.Budget{MaxBytes: 500})
+
+	assert.Equal(t, first, second)
+}
@@ -0,0 +1,103 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ChartWidth is the number of cells the longest bar spans.
+const ChartWidth = 40
+
+var (
+	sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+	// barEighths are partial blocks of 1/8 to 7/8 of a cell.
+	barEighths = []rune("▏▎▍▌▋▊▉")
+	barStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+)
+
+// Bar is one row of a bar chart.
+type Bar struct {
+	Label string
+	Value float64
+	// Text follows the bar; it defaults to the value.
+	Text string
+}
+
+// PrintBars renders a horizontal bar chart, scaled so the largest value
+// spans width cells. Accessible output lists the labels and values instead.
+func PrintBars(w io.Writer, bars []Bar, width int) {
+	if width < 1 {
+		width = ChartWidth
+	}
+	labelWidth, max := 0, 0.0
+	for _, b := range bars {
+		labelWidth = maxInt(labelWidth, lipgloss.Width(b.Label))
+		max = math.Max(max, b.Value)
+	}
+
+	for _, b := range bars {
+		text := b.Text
+		if text == "" {
+			text = strconv.FormatFloat(b.Value, 'f', -1, 64)
+		}
+		if accessible {
+			fmt.Fprintf(w, "  %s: %s\n", b.Label, text)
+			continue
+		}
+		pad := strings.Repeat(" ", labelWidth-lipgloss.Width(b.Label))
+		bar := ""
+		if max > 0 && b.Value > 0 {
+			bar = barStyle.Render(barCells(b.Value / max * float64(width)))
+		}
+		fmt.Fprintf(w, "  %s%s  %s %s\n", b.Label, pad, bar, dimStyle.Render(text))
+	}
+}
+
+// barCells draws a bar of the given length in cells, to 1/8 of a cell.
+func barCells(cells float64) string {
+	eighths := int(math.Round(cells * 8))
+	if eighths < 1 {
+		eighths = 1 // a nonzero value is always visible
+	}
+	bar := strings.Repeat("█", eighths/8)
+	if rest := eighths % 8; rest > 0 {
+		bar += string(barEighths[rest-1])
+	}
+	return bar
+}
+
+// Sparkline draws one block per value, scaled between the series' minimum
+// and maximum. NaN values are missing data and drawn as blanks.
+func Sparkline(values []float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkBlocks[len(sparkBlocks)/2])
+		default:
+			b.WriteRune(sparkBlocks[int((v-lo)/(hi-lo)*float64(len(sparkBlocks)-1))])
+		}
+	}
+	return b.String()
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
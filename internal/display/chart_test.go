@@ -0,0 +1,45 @@
+package display_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▄█ ", display.Sparkline([]float64{0, 5, 10, math.NaN()}))
+	assert.Equal(t, "▅▅", display.Sparkline([]float64{7, 7}))
+	assert.Equal(t, "  ", display.Sparkline([]float64{math.NaN(), math.NaN()}))
+	assert.Empty(t, display.Sparkline(nil))
+}
+
+func TestPrintBars(t *testing.T) {
+	var buf bytes.Buffer
+	display.PrintBars(&buf, []display.Bar{
+		{Label: "bogo", Value: 10},
+		{Label: "produce", Value: 5, Text: "5 deals"},
+		{Label: "bakery", Value: 0.1},
+		{Label: "none", Value: 0},
+	}, 8)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assert.Equal(t, []string{
+		"  bogo     ████████ 10",
+		"  produce  ████ 5 deals",
+		"  bakery   ▏ 0.1",
+		"  none      0",
+	}, lines)
+}
+
+func TestPrintBars_Accessible(t *testing.T) {
+	display.SetAccessible(true)
+	t.Cleanup(func() { display.SetAccessible(false) })
+
+	var buf bytes.Buffer
+	display.PrintBars(&buf, []display.Bar{{Label: "bogo", Value: 10}}, 8)
+	assert.Equal(t, "  bogo: 10\n", buf.String())
+}
@@ -0,0 +1,36 @@
+package display
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+var dealCSVHeader = []string{
+	"title", "savings", "description", "department", "categories",
+	"additionalDealInfo", "brand", "validFrom", "validTo", "isBogo", "imageUrl",
+}
+
+// PrintDealsCSV renders deals as CSV with a header row. Columns follow the
+// DealJSON field names; categories are joined with ";".
+func PrintDealsCSV(w io.Writer, items []api.SavingItem) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(dealCSVHeader); err != nil {
+		return err
+	}
+	for _, item := range items {
+		d := ToDealJSON(item)
+		record := []string{
+			d.Title, d.Savings, d.Description, d.Department, strings.Join(d.Categories, ";"),
+			d.DealInfo, d.Brand, d.ValidFrom, d.ValidTo, strconv.FormatBool(d.IsBogo), d.ImageURL,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,24 @@
+package display_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func TestPrintDealsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, display.PrintDealsCSV(&buf, sampleDeals()))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "title", records[0][0])
+	assert.Equal(t, "Chicken Breasts", records[1][0])
+	assert.Equal(t, "Nutella & More", records[2][0])
+	assert.Equal(t, "true", records[2][9])
+}
@@ -0,0 +1,55 @@
+package display
+
+import (
+	"math"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+const (
+	// expiryUrgent is when a countdown turns red.
+	expiryUrgent = 24 * time.Hour
+	// expirySoon is when a countdown turns yellow.
+	expirySoon = 72 * time.Hour
+)
+
+// EndsIn returns a short countdown to a deal's expiry such as "ends in 2d"
+// or "ends in 5h", and the time left. It returns false for deals without a
+// readable end date.
+func EndsIn(item api.SavingItem, now time.Time) (string, time.Duration, bool) {
+	end, ok := filter.EndsAt(item, now)
+	if !ok {
+		return "", 0, false
+	}
+	left := end.Sub(now)
+	switch {
+	case left <= 0:
+		return T("expiry.ended"), left, true
+	case left < expiryUrgent:
+		return T("expiry.hours", int(math.Ceil(left.Hours()))), left, true
+	default:
+		return T("expiry.days", int(left/(24*time.Hour))), left, true
+	}
+}
+
+// RenderEndsIn returns the EndsIn countdown colored by urgency: red under a
+// day left, yellow under three days, and dim otherwise. It returns "" for
+// deals without a readable end date.
+func RenderEndsIn(item api.SavingItem, now time.Time) string {
+	text, left, ok := EndsIn(item, now)
+	if !ok {
+		return ""
+	}
+	switch {
+	case left <= 0:
+		return dimStyle.Render(text)
+	case left < expiryUrgent:
+		return errorStyle.Render(text)
+	case left < expirySoon:
+		return warningStyle.Render(text)
+	default:
+		return dimStyle.Render(text)
+	}
+}
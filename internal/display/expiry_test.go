@@ -0,0 +1,33 @@
+package display_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func TestEndsIn(t *testing.T) {
+	now := time.Date(2026, 10, 19, 18, 30, 0, 0, time.UTC)
+	cases := []struct {
+		end  string
+		want string
+	}{
+		{"10/19/2026", "ends in 6h"},
+		{"10/20/2026", "ends in 1d"},
+		{"10/21", "ends in 2d"},
+		{"10/28/2026", "ends in 9d"},
+		{"10/18/2026", "ended"},
+	}
+	for _, tc := range cases {
+		got, _, ok := display.EndsIn(api.SavingItem{EndFormatted: tc.end}, now)
+		assert.True(t, ok, tc.end)
+		assert.Equal(t, tc.want, got, tc.end)
+	}
+
+	_, _, ok := display.EndsIn(api.SavingItem{EndFormatted: "soon"}, now)
+	assert.False(t, ok)
+	assert.Empty(t, display.RenderEndsIn(api.SavingItem{}, now))
+}
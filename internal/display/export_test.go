@@ -0,0 +1,4 @@
+package display
+
+// Catalogs exposes the message catalogs to tests.
+var Catalogs = catalogs
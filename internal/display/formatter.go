@@ -1,11 +1,11 @@
 package display
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/tayloree/publix-deals/internal/api"
@@ -15,6 +15,7 @@ import (
 // Styles for terminal output.
 var (
 	titleStyle   = lipgloss.NewStyle().Bold(true)
+	flaggedStyle = lipgloss.NewStyle().Bold(true).Strikethrough(true)
 	bogoTag      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("5")) // magenta
 	priceStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))            // green
 	dealStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))            // yellow
@@ -38,6 +39,35 @@ type DealJSON struct {
 	ValidTo     string   `json:"validTo"`
 	IsBogo      bool     `json:"isBogo"`
 	ImageURL    string   `json:"imageUrl"`
+	// ImageURLLarge is a higher-resolution guess for ImageURL; see
+	// api.ImageVariants.
+	ImageURLLarge string `json:"imageUrlLarge"`
+	// Score is filter.DealScore, the value --sort savings orders by.
+	Score float64 `json:"score"`
+	// PercentOff is filter.Savings.Percent and PercentOffSource where it
+	// comes from: "ad", "bogo", or "estimated". Both are omitted when the
+	// savings text gives no way to tell.
+	PercentOff       float64 `json:"percentOff,omitempty"`
+	PercentOffSource string  `json:"percentOffSource,omitempty"`
+	// PriceAmount and PriceUnit are filter.Savings.Price and Unit: the sale
+	// price of one item ("each") or one pound ("lb"). Both are omitted when
+	// the savings text states no price.
+	PriceAmount float64 `json:"priceAmount,omitempty"`
+	PriceUnit   string  `json:"priceUnit,omitempty"`
+	// EffectivePrice is filter.Savings.EffectivePrice for BOGO deals, what
+	// one item costs with the second free; omitted for other deals.
+	EffectivePrice float64 `json:"effectivePrice,omitempty"`
+	// Stores lists the store numbers carrying the deal in --all-stores mode.
+	Stores []string `json:"stores,omitempty"`
+	// Expired is set for deals whose end date has passed, which are only
+	// listed with --include-expired.
+	Expired bool `json:"expired,omitempty"`
+	// Flags says why the household profile flags the deal; see
+	// api.SavingItem.Flags.
+	Flags []string `json:"flags,omitempty"`
+	// IsNew is api.SavingItem.IsNew: whether the store's previous ad lacked
+	// the deal. It is omitted when no earlier ad was saved to compare with.
+	IsNew *bool `json:"isNew,omitempty"`
 }
 
 // StoreJSON is the JSON output shape for a store.
@@ -46,47 +76,98 @@ type StoreJSON struct {
 	Name     string `json:"name"`
 	Address  string `json:"address"`
 	Distance string `json:"distance"`
+	// OpenNow is null when the locator sent no readable hours.
+	OpenNow *bool `json:"openNow"`
 }
 
 // PrintDeals renders a list of deals to the writer.
 func PrintDeals(w io.Writer, items []api.SavingItem) {
+	PrintDealsGrouped(w, items, "")
+}
+
+// PrintDealsGrouped renders deals under a heading for each group, as
+// filter.Group names them for by ("department" or "category"). The deals
+// should already be in their groups, as filter.Apply leaves them with
+// GroupBy. An empty by prints them as PrintDeals does.
+func PrintDealsGrouped(w io.Writer, items []api.SavingItem, by string) {
+	if accessible {
+		printDealsAccessible(w, items, by)
+		return
+	}
+
 	dateRange := ""
 	if len(items) > 0 && items[0].StartFormatted != "" {
-		dateRange = fmt.Sprintf(" (%s - %s)", items[0].StartFormatted, items[0].EndFormatted)
+		dateRange = fmt.Sprintf(" (%s - %s)", FormatDealDate(items[0].StartFormatted), FormatDealDate(items[0].EndFormatted))
 	}
 
 	fmt.Fprintf(w, "\n%s%s — %s\n\n",
-		headerStyle.Render("Publix Weekly Deals"),
+		headerStyle.Render(T("deals.header")),
 		dateRange,
-		cyanStyle.Render(fmt.Sprintf("%d items", len(items))),
+		cyanStyle.Render(T("deals.count", len(items))),
 	)
 
-	for _, item := range items {
+	for i, item := range items {
+		if by != "" && (i == 0 || filter.Group(item, by) != filter.Group(items[i-1], by)) {
+			fmt.Fprintf(w, "%s %s\n\n", titleStyle.Render(groupLabel(item, by)), dimStyle.Render(fmt.Sprintf("(%d)", groupSize(items, i, by))))
+		}
 		printDeal(w, item)
 		fmt.Fprintln(w)
 	}
 }
 
+// groupLabel is the heading of the deal's group.
+func groupLabel(item api.SavingItem, by string) string {
+	if name := filter.Group(item, by); name != "" {
+		return name
+	}
+	return T("deals.group_other")
+}
+
+// groupSize counts the deals of the group starting at items[start].
+func groupSize(items []api.SavingItem, start int, by string) int {
+	group := filter.Group(items[start], by)
+	n := 0
+	for _, item := range items[start:] {
+		if filter.Group(item, by) != group {
+			break
+		}
+		n++
+	}
+	return n
+}
+
 // PrintDealsJSON renders deals as JSON.
 func PrintDealsJSON(w io.Writer, items []api.SavingItem) error {
 	out := make([]DealJSON, 0, len(items))
 	for _, item := range items {
-		out = append(out, toDealJSON(item))
+		out = append(out, ToDealJSON(item))
 	}
-	return json.NewEncoder(w).Encode(out)
+	return PrintVersionedJSON(w, "deals", out)
 }
 
 // PrintStores renders a list of stores to the writer.
 func PrintStores(w io.Writer, stores []api.Store, zipCode string) {
+	if accessible {
+		printStoresAccessible(w, stores, zipCode)
+		return
+	}
+
 	fmt.Fprintf(w, "\n%s\n\n",
-		titleStyle.Render(fmt.Sprintf("Publix stores near %s:", zipCode)),
+		titleStyle.Render(T("stores.header", zipCode)),
 	)
 	for _, s := range stores {
 		num := api.StoreNumber(s.Key)
 		fmt.Fprintf(w, "  %s  %s\n", cyanStyle.Render("#"+num), titleStyle.Render(s.Name))
 		fmt.Fprintf(w, "        %s, %s, %s %s\n", s.Addr, s.City, s.State, s.Zip)
 		if s.Distance != "" {
-			fmt.Fprintf(w, "        %s\n", dimStyle.Render(s.Distance+" miles"))
+			fmt.Fprintf(w, "        %s\n", dimStyle.Render(T("stores.miles", s.Distance)))
+		}
+		if open, known := s.OpenAt(time.Now()); known {
+			if open {
+				fmt.Fprintf(w, "        %s\n", priceStyle.Render(T("stores.open")))
+			} else {
+				fmt.Fprintf(w, "        %s\n", warningStyle.Render(closedText(s)))
+			}
 		}
 		fmt.Fprintln(w)
 	}
@@ -96,47 +177,121 @@ func PrintStores(w io.Writer, stores []api.Store, zipCode string) {
 func PrintStoresJSON(w io.Writer, stores []api.Store) error {
 	out := make([]StoreJSON, 0, len(stores))
 	for _, s := range stores {
-		out = append(out, StoreJSON{
-			Number:   api.StoreNumber(s.Key),
-			Name:     s.Name,
-			Address:  fmt.Sprintf("%s, %s, %s %s", s.Addr, s.City, s.State, s.Zip),
-			Distance: s.Distance,
-		})
-	}
-	return json.NewEncoder(w).Encode(out)
+		out = append(out, ToStoreJSON(s))
+	}
+	return PrintVersionedJSON(w, "stores", out)
+}
+
+// ToStoreJSON converts a store to its JSON output shape.
+func ToStoreJSON(s api.Store) StoreJSON {
+	return StoreJSON{
+		Number:   api.StoreNumber(s.Key),
+		Name:     s.Name,
+		Address:  fmt.Sprintf("%s, %s, %s %s", s.Addr, s.City, s.State, s.Zip),
+		Distance: s.Distance,
+		OpenNow:  openNow(s, time.Now()),
+	}
+}
+
+func openNow(s api.Store, now time.Time) *bool {
+	open, known := s.OpenAt(now)
+	if !known {
+		return nil
+	}
+	return &open
+}
+
+// closedText describes a closed store, noting a temporary closure.
+func closedText(s api.Store) string {
+	if s.TemporarilyClosed() {
+		return T("stores.temp_closed")
+	}
+	return T("stores.closed")
+}
+
+// StoreClosedWarning returns a warning for deal output from a store that is
+// closed at now, or "" when it is open or its hours are unknown.
+func StoreClosedWarning(s api.Store, now time.Time) string {
+	open, known := s.OpenAt(now)
+	if !known || open {
+		return ""
+	}
+	num := api.StoreNumber(s.Key)
+	if s.TemporarilyClosed() {
+		return T("stores.warn_temp", num)
+	}
+	return T("stores.warn_closed", num)
 }
 
 // PrintCategories renders a list of categories and their counts.
 func PrintCategories(w io.Writer, cats map[string]int, storeNumber string) {
-	type catCount struct {
-		Name  string
-		Count int
+	sorted := sortedCategories(cats)
+
+	if accessible {
+		printCategoriesAccessible(w, sorted, storeNumber)
+		return
 	}
-	sorted := make([]catCount, 0, len(cats))
-	for k, v := range cats {
-		sorted = append(sorted, catCount{k, v})
+
+	fmt.Fprintf(w, "\n%s\n\n",
+		titleStyle.Render(T("categories.header", storeNumber)),
+	)
+	for _, c := range sorted {
+		fmt.Fprintf(w, "  %s: %s\n", cyanStyle.Render(c.Name), T("categories.deals", c.Count))
 	}
-	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Count > sorted[j].Count })
+	fmt.Fprintln(w)
+}
 
+// PrintCategoryChart renders category counts as a bar chart, largest first.
+func PrintCategoryChart(w io.Writer, cats map[string]int, storeNumber string) {
+	sorted := sortedCategories(cats)
+	if accessible {
+		printCategoriesAccessible(w, sorted, storeNumber)
+		return
+	}
 	fmt.Fprintf(w, "\n%s\n\n",
-		titleStyle.Render(fmt.Sprintf("Categories for store #%s this week:", storeNumber)),
+		titleStyle.Render(T("categories.header", storeNumber)),
 	)
+	bars := make([]Bar, 0, len(sorted))
 	for _, c := range sorted {
-		fmt.Fprintf(w, "  %s: %d deals\n", cyanStyle.Render(c.Name), c.Count)
+		bars = append(bars, Bar{Label: c.Name, Value: float64(c.Count)})
 	}
+	PrintBars(w, bars, ChartWidth)
 	fmt.Fprintln(w)
 }
 
+func sortedCategories(cats map[string]int) []categoryCount {
+	sorted := make([]categoryCount, 0, len(cats))
+	for k, v := range cats {
+		sorted = append(sorted, categoryCount{k, v})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+type categoryCount struct {
+	Name  string
+	Count int
+}
+
 // PrintCategoriesJSON renders categories as JSON.
 func PrintCategoriesJSON(w io.Writer, cats map[string]int) error {
-	return json.NewEncoder(w).Encode(cats)
+	return PrintVersionedJSON(w, "categories", cats)
 }
 
 // PrintStoreContext prints a dim line showing which store was auto-selected.
 func PrintStoreContext(w io.Writer, store api.Store) {
 	num := api.StoreNumber(store.Key)
+	if accessible {
+		fmt.Fprintf(w, "%s\n\n", T("a11y.context.store", num, store.Name, store.City, store.State))
+		return
+	}
 	fmt.Fprintf(w, "%s\n\n",
-		dimStyle.Render(fmt.Sprintf("Using store: #%s — %s (%s, %s)", num, store.Name, store.City, store.State)),
+		dimStyle.Render(T("context.store", num, store.Name, store.City, store.State)),
 	)
 }
 
@@ -163,7 +318,12 @@ func printDeal(w io.Writer, item api.SavingItem) {
 	if isBogo {
 		tag = bogoTag.Render("BOGO") + " "
 	}
-	fmt.Fprintf(w, "  %s%s\n", tag, titleStyle.Render(title))
+	if len(item.Flags) > 0 {
+		fmt.Fprintf(w, "  %s%s\n", tag, flaggedStyle.Render(title))
+		fmt.Fprintf(w, "    %s\n", warningStyle.Render(T("field.avoid")+": "+strings.Join(item.Flags, ", ")))
+	} else {
+		fmt.Fprintf(w, "  %s%s\n", tag, titleStyle.Render(title))
+	}
 
 	// Price / savings
 	var parts []string
@@ -185,14 +345,43 @@ func printDeal(w io.Writer, item api.SavingItem) {
 	// Meta
 	var meta []string
 	if item.StartFormatted != "" && item.EndFormatted != "" {
-		meta = append(meta, fmt.Sprintf("Valid %s - %s", item.StartFormatted, item.EndFormatted))
+		meta = append(meta, dimStyle.Render(T("deals.valid", FormatDealDate(item.StartFormatted), FormatDealDate(item.EndFormatted))))
+	}
+	if endsIn := RenderEndsIn(item, time.Now()); endsIn != "" {
+		meta = append(meta, endsIn)
 	}
 	if dept != "" {
-		meta = append(meta, dept)
+		meta = append(meta, dimStyle.Render(dept))
+	}
+	if len(item.Stores) > 0 {
+		meta = append(meta, dimStyle.Render(T("deals.stores", storeList(item.Stores))))
 	}
 	if len(meta) > 0 {
-		fmt.Fprintf(w, "    %s\n", dimStyle.Render(strings.Join(meta, " | ")))
+		fmt.Fprintf(w, "    %s\n", strings.Join(meta, dimStyle.Render(" | ")))
+	}
+}
+
+// storeList formats store numbers as "#1425, #1500".
+func storeList(numbers []string) string {
+	tagged := make([]string, len(numbers))
+	for i, n := range numbers {
+		tagged[i] = "#" + n
 	}
+	return strings.Join(tagged, ", ")
+}
+
+// PrintMergedStoreContext notes which stores near zipCode a merged deal
+// list was built from.
+func PrintMergedStoreContext(w io.Writer, zipCode string, stores []api.Store) {
+	numbers := make([]string, len(stores))
+	for i, s := range stores {
+		numbers[i] = api.StoreNumber(s.Key)
+	}
+	if accessible {
+		fmt.Fprintf(w, "%s\n\n", T("a11y.context.merged", len(stores), zipCode, strings.Join(numbers, ", ")))
+		return
+	}
+	fmt.Fprintf(w, "%s\n\n", dimStyle.Render(T("context.merged", len(stores), zipCode, storeList(numbers))))
 }
 
 func fallbackDealTitle(item api.SavingItem) string {
@@ -204,11 +393,11 @@ func fallbackDealTitle(item api.SavingItem) string {
 	dept := filter.CleanText(filter.Deref(item.Department))
 	switch {
 	case brand != "" && dept != "":
-		return fmt.Sprintf("%s deal (%s)", brand, dept)
+		return T("deal.brand_dept", brand, dept)
 	case brand != "":
-		return brand + " deal"
+		return T("deal.named", brand)
 	case dept != "":
-		return dept + " deal"
+		return T("deal.named", dept)
 	}
 
 	if desc := filter.CleanText(filter.Deref(item.Description)); desc != "" {
@@ -220,29 +409,46 @@ func fallbackDealTitle(item api.SavingItem) string {
 	}
 
 	if item.ID != "" {
-		return "Deal " + item.ID
+		return T("deal.id", item.ID)
 	}
 
-	return "Untitled deal"
+	return T("deal.untitled")
 }
 
-func toDealJSON(item api.SavingItem) DealJSON {
+// ToDealJSON converts a deal to its JSON output shape.
+func ToDealJSON(item api.SavingItem) DealJSON {
 	categories := item.Categories
 	if categories == nil {
 		categories = []string{}
 	}
+	savings := filter.ParseSavings(item)
+	effective := 0.0
+	if savings.BOGO {
+		effective = savings.EffectivePrice()
+	}
 	return DealJSON{
-		Title:       filter.CleanText(filter.Deref(item.Title)),
-		Savings:     filter.CleanText(filter.Deref(item.Savings)),
-		Description: filter.CleanText(filter.Deref(item.Description)),
-		Department:  filter.CleanText(filter.Deref(item.Department)),
-		Categories:  categories,
-		DealInfo:    filter.CleanText(filter.Deref(item.AdditionalDealInfo)),
-		Brand:       filter.CleanText(filter.Deref(item.Brand)),
-		ValidFrom:   item.StartFormatted,
-		ValidTo:     item.EndFormatted,
-		IsBogo:      filter.ContainsIgnoreCase(item.Categories, "bogo"),
-		ImageURL:    filter.Deref(item.ImageURL),
+		Title:            filter.CleanText(filter.Deref(item.Title)),
+		Savings:          filter.CleanText(filter.Deref(item.Savings)),
+		Description:      filter.CleanText(filter.Deref(item.Description)),
+		Department:       filter.CleanText(filter.Deref(item.Department)),
+		Categories:       categories,
+		DealInfo:         filter.CleanText(filter.Deref(item.AdditionalDealInfo)),
+		Brand:            filter.CleanText(filter.Deref(item.Brand)),
+		ValidFrom:        FormatDealDate(item.StartFormatted),
+		ValidTo:          FormatDealDate(item.EndFormatted),
+		IsBogo:           filter.ContainsIgnoreCase(item.Categories, "bogo"),
+		ImageURL:         filter.Deref(item.ImageURL),
+		ImageURLLarge:    api.LargeImageURL(filter.Deref(item.ImageURL)),
+		Score:            filter.DealScore(item),
+		PercentOff:       savings.Percent,
+		PercentOffSource: savings.PercentSource,
+		PriceAmount:      savings.Price,
+		PriceUnit:        savings.Unit,
+		EffectivePrice:   effective,
+		Stores:           item.Stores,
+		Expired:          filter.Expired(item, time.Now()),
+		Flags:            item.Flags,
+		IsNew:            item.IsNew,
 	}
 }
 
@@ -3,12 +3,15 @@ package display_test
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tayloree/publix-deals/internal/api"
 	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
 )
 
 func ptr(s string) *string { return &s }
@@ -55,6 +58,18 @@ func TestPrintDeals_ContainsExpectedContent(t *testing.T) {
 	assert.NotContains(t, output, "&amp;")
 }
 
+func TestPrintDealsGrouped_HeadsEachGroup(t *testing.T) {
+	var buf bytes.Buffer
+	items := append(sampleDeals(), api.SavingItem{ID: "3", Title: ptr("Mystery Box")})
+	display.PrintDealsGrouped(&buf, items, "department")
+	output := buf.String()
+
+	assert.Contains(t, output, "Meat (1)\n")
+	assert.Contains(t, output, "Grocery (1)\n")
+	assert.Contains(t, output, "Other (1)\n")
+	assert.Less(t, strings.Index(output, "Meat (1)"), strings.Index(output, "Chicken Breasts"))
+}
+
 func TestPrintDeals_FallbackTitleFromBrandAndDepartment(t *testing.T) {
 	items := []api.SavingItem{
 		{
@@ -96,10 +111,15 @@ func TestPrintDealsJSON(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotContains(t, buf.String(), "\n  ")
 
-	var deals []display.DealJSON
-	err = json.Unmarshal(buf.Bytes(), &deals)
+	var payload struct {
+		SchemaVersion int                `json:"schemaVersion"`
+		Deals         []display.DealJSON `json:"deals"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &payload)
 	require.NoError(t, err)
+	assert.Equal(t, display.LatestSchemaVersion, payload.SchemaVersion)
 
+	deals := payload.Deals
 	assert.Len(t, deals, 2)
 	assert.Equal(t, "Chicken Breasts", deals[0].Title)
 	assert.Equal(t, "$3.99 lb", deals[0].Savings)
@@ -111,15 +131,82 @@ func TestPrintDealsJSON(t *testing.T) {
 	assert.True(t, deals[1].IsBogo)
 }
 
+func TestToDealJSON_FlagsExpiredDeals(t *testing.T) {
+	expired := display.ToDealJSON(api.SavingItem{End: time.Now().AddDate(0, 0, -2)})
+	assert.True(t, expired.Expired)
+
+	current := display.ToDealJSON(api.SavingItem{End: time.Now().AddDate(0, 0, 2)})
+	assert.False(t, current.Expired)
+	data, err := json.Marshal(current)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "expired")
+}
+
+func TestToDealJSON_ScoreIsTheSortScore(t *testing.T) {
+	item := api.SavingItem{Savings: ptr("Save $2.50"), Categories: []string{"bogo"}}
+	assert.Equal(t, filter.DealScore(item), display.ToDealJSON(item).Score)
+	assert.Equal(t, 10.5, display.ToDealJSON(item).Score)
+}
+
+func TestToDealJSON_MarksEstimatedPercentOff(t *testing.T) {
+	d := display.ToDealJSON(api.SavingItem{Savings: ptr("$3.00"), AdditionalDealInfo: ptr("Save $1.00")})
+	assert.Equal(t, 25.0, d.PercentOff)
+	assert.Equal(t, filter.PercentEstimated, d.PercentOffSource)
+
+	d = display.ToDealJSON(api.SavingItem{Savings: ptr("Great deal")})
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "percentOff")
+}
+
+func TestToDealJSON_StructuredPrice(t *testing.T) {
+	d := display.ToDealJSON(api.SavingItem{Savings: ptr("$3.99 lb"), AdditionalDealInfo: ptr("Save Up To $1.00 lb")})
+	assert.Equal(t, 3.99, d.PriceAmount)
+	assert.Equal(t, filter.UnitPound, d.PriceUnit)
+	assert.Zero(t, d.EffectivePrice)
+
+	d = display.ToDealJSON(api.SavingItem{Savings: ptr("$7.00"), Categories: []string{"bogo"}})
+	assert.Equal(t, 7.0, d.PriceAmount)
+	assert.Equal(t, filter.UnitEach, d.PriceUnit)
+	assert.Equal(t, 3.5, d.EffectivePrice)
+
+	d = display.ToDealJSON(api.SavingItem{Savings: ptr("Great deal")})
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "price")
+}
+
+func TestPrintDeals_ListsStoresForMergedDeals(t *testing.T) {
+	items := []api.SavingItem{{Title: ptr("Apples"), Stores: []string{"1425", "1500"}}}
+	var buf bytes.Buffer
+	display.PrintDeals(&buf, items)
+	assert.Contains(t, buf.String(), "Stores #1425, #1500")
+
+	assert.Equal(t, []string{"1425", "1500"}, display.ToDealJSON(items[0]).Stores)
+}
+
+func TestPrintDeals_MarksFlaggedDeals(t *testing.T) {
+	items := []api.SavingItem{{Title: ptr("Peanut Butter"), Flags: []string{"peanut", "not vegan"}}}
+	var buf bytes.Buffer
+	display.PrintDeals(&buf, items)
+	assert.Contains(t, buf.String(), "Avoid: peanut, not vegan")
+
+	assert.Equal(t, []string{"peanut", "not vegan"}, display.ToDealJSON(items[0]).Flags)
+	assert.Contains(t, display.AccessibleDealFields(items[0]), [2]string{"Avoid", "peanut, not vegan"})
+}
+
 func TestPrintDealsJSON_NilFields(t *testing.T) {
 	items := []api.SavingItem{{ID: "nil-test"}}
 	var buf bytes.Buffer
 	err := display.PrintDealsJSON(&buf, items)
 	require.NoError(t, err)
 
-	var deals []display.DealJSON
-	err = json.Unmarshal(buf.Bytes(), &deals)
+	var payload struct {
+		Deals []display.DealJSON `json:"deals"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &payload)
 	require.NoError(t, err)
+	deals := payload.Deals
 	assert.Len(t, deals, 1)
 	assert.Equal(t, "", deals[0].Title)
 	assert.NotNil(t, deals[0].Categories)
@@ -148,14 +235,48 @@ func TestPrintStoresJSON(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotContains(t, buf.String(), "\n  ")
 
-	var out []display.StoreJSON
-	err = json.Unmarshal(buf.Bytes(), &out)
+	var payload struct {
+		Stores []display.StoreJSON `json:"stores"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &payload)
 	require.NoError(t, err)
 
+	out := payload.Stores
 	assert.Len(t, out, 1)
 	assert.Equal(t, "1425", out[0].Number)
 	assert.Equal(t, "Peachers Mill", out[0].Name)
 	assert.Contains(t, out[0].Address, "Clarksville")
+	assert.Nil(t, out[0].OpenNow)
+}
+
+func TestPrintStoresJSON_OpenNow(t *testing.T) {
+	stores := []api.Store{
+		{Key: "01425", Name: "Peachers Mill", Hours: "Open 24 Hours"},
+		{Key: "01500", Name: "Sango", Hours: "Open 24 Hours", Status: "Temporarily Closed"},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, display.PrintStoresJSON(&buf, stores))
+
+	var payload struct {
+		Stores []display.StoreJSON `json:"stores"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &payload))
+	require.Len(t, payload.Stores, 2)
+	require.NotNil(t, payload.Stores[0].OpenNow)
+	assert.True(t, *payload.Stores[0].OpenNow)
+	require.NotNil(t, payload.Stores[1].OpenNow)
+	assert.False(t, *payload.Stores[1].OpenNow)
+}
+
+func TestStoreClosedWarning(t *testing.T) {
+	now := time.Date(2026, 10, 16, 23, 30, 0, 0, time.Local)
+	assert.Equal(t, "Store #1425 is closed right now.",
+		display.StoreClosedWarning(api.Store{Key: "01425", Hours: "7:00 AM - 10:00 PM"}, now))
+	assert.Contains(t,
+		display.StoreClosedWarning(api.Store{Key: "01425", Status: "Temporarily Closed"}, now),
+		"temporarily closed")
+	assert.Empty(t, display.StoreClosedWarning(api.Store{Key: "01425", Hours: "Open 24 Hours"}, now))
+	assert.Empty(t, display.StoreClosedWarning(api.Store{Key: "01425"}, now))
 }
 
 func TestPrintCategories(t *testing.T) {
@@ -178,10 +299,13 @@ func TestPrintCategoriesJSON(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotContains(t, buf.String(), "\n  ")
 
-	var out map[string]int
-	err = json.Unmarshal(buf.Bytes(), &out)
+	var payload struct {
+		Categories map[string]int `json:"categories"`
+	}
+	err = json.Unmarshal(buf.Bytes(), &payload)
 	require.NoError(t, err)
 
+	out := payload.Categories
 	assert.Equal(t, 10, out["bogo"])
 	assert.Equal(t, 5, out["meat"])
 }
@@ -0,0 +1,130 @@
+package display
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocale is the locale used unless another is configured.
+const DefaultLocale = "en-US"
+
+// localeFormat holds how a locale writes numbers, dollar amounts, and deal
+// dates. Prices stay in US dollars; only their layout changes.
+type localeFormat struct {
+	decimal   string
+	thousands string
+	// money is a fmt pattern for a formatted amount, e.g. "$%s" or "%s $".
+	money string
+	// date and dateYear are time layouts for deal dates without and with a
+	// year.
+	date, dateYear string
+}
+
+var locales = map[string]localeFormat{
+	"en-US": {decimal: ".", thousands: ",", money: "$%s", date: "1/2", dateYear: "1/2/2006"},
+	"en-GB": {decimal: ".", thousands: ",", money: "$%s", date: "02/01", dateYear: "02/01/2006"},
+	"es-US": {decimal: ".", thousands: ",", money: "$%s", date: "2/1", dateYear: "2/1/2006"},
+	"es-MX": {decimal: ".", thousands: ",", money: "$%s", date: "02/01", dateYear: "02/01/2006"},
+	"es-ES": {decimal: ",", thousands: ".", money: "%s $", date: "2/1", dateYear: "2/1/2006"},
+	"fr-CA": {decimal: ",", thousands: " ", money: "%s $", date: "01-02", dateYear: "2006-01-02"},
+	"fr-FR": {decimal: ",", thousands: " ", money: "%s $", date: "02/01", dateYear: "02/01/2006"},
+	"de-DE": {decimal: ",", thousands: ".", money: "%s $", date: "2.1.", dateYear: "2.1.2006"},
+	"pt-BR": {decimal: ",", thousands: ".", money: "$ %s", date: "02/01", dateYear: "02/01/2006"},
+}
+
+var (
+	localeTag = DefaultLocale
+	locale    = locales[DefaultLocale]
+)
+
+// CanonicalLocale returns the supported locale tag matching tag, accepting
+// "de_DE", "de-de", or "de_DE.UTF-8" for "de-DE". It returns false for
+// unsupported locales.
+func CanonicalLocale(tag string) (string, bool) {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(tag, "_", "-")
+	for name := range locales {
+		if strings.EqualFold(name, tag) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Locales lists the supported locale tags, sorted.
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetLocale selects the locale used to format numbers, dollar amounts, and
+// deal dates. Unsupported locales fall back to DefaultLocale.
+func SetLocale(tag string) {
+	name, ok := CanonicalLocale(tag)
+	if !ok {
+		name = DefaultLocale
+	}
+	localeTag, locale = name, locales[name]
+}
+
+// Locale returns the locale tag in effect.
+func Locale() string {
+	return localeTag
+}
+
+// FormatNumber formats v with the given number of decimals, grouping
+// thousands the locale's way.
+func FormatNumber(v float64, decimals int) string {
+	digits := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	whole, frac, _ := strings.Cut(digits, ".")
+
+	var b strings.Builder
+	if v < 0 && strings.Trim(digits, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(locale.thousands)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteString(locale.decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// FormatMoney formats a dollar amount with cents.
+func FormatMoney(v float64) string {
+	return strings.Replace(locale.money, "%s", FormatNumber(v, 2), 1)
+}
+
+// FormatDealDate rewrites an upstream deal date such as "2/18" or
+// "2/18/2026" in the locale's date order. Dates it cannot read are returned
+// unchanged, as are all dates in the default locale.
+func FormatDealDate(raw string) string {
+	value := strings.TrimSpace(raw)
+	if localeTag == DefaultLocale || value == "" {
+		return raw
+	}
+	for _, layout := range []string{"1/2/2006", "1/2/06", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format(locale.dateYear)
+		}
+	}
+	if t, err := time.Parse("1/2", value); err == nil {
+		return t.Format(locale.date)
+	}
+	return raw
+}
@@ -0,0 +1,51 @@
+package display_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func useLocale(t *testing.T, tag string) {
+	t.Helper()
+	display.SetLocale(tag)
+	t.Cleanup(func() { display.SetLocale(display.DefaultLocale) })
+}
+
+func TestCanonicalLocale(t *testing.T) {
+	for _, tag := range []string{"de-DE", "de_DE", "de-de", "de_DE.UTF-8"} {
+		name, ok := display.CanonicalLocale(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, "de-DE", name, tag)
+	}
+	_, ok := display.CanonicalLocale("xx-XX")
+	assert.False(t, ok)
+}
+
+func TestFormatNumberAndMoney(t *testing.T) {
+	assert.Equal(t, "1,234.50", display.FormatNumber(1234.5, 2))
+	assert.Equal(t, "$3.00", display.FormatMoney(3))
+	assert.Equal(t, "-12", display.FormatNumber(-12, 0))
+
+	useLocale(t, "de-DE")
+	assert.Equal(t, "1.234.567,9", display.FormatNumber(1234567.89, 1))
+	assert.Equal(t, "3,50 $", display.FormatMoney(3.5))
+	assert.Equal(t, "0", display.FormatNumber(-0.2, 0), "no sign on a value that rounds to zero")
+}
+
+func TestFormatDealDate(t *testing.T) {
+	assert.Equal(t, "2/18", display.FormatDealDate("2/18"), "en-US keeps upstream dates")
+
+	useLocale(t, "en-GB")
+	assert.Equal(t, "18/02", display.FormatDealDate("2/18"))
+	assert.Equal(t, "18/02/2026", display.FormatDealDate("2/18/2026"))
+	assert.Equal(t, "Feb 18", display.FormatDealDate("Feb 18"), "unreadable dates pass through")
+
+	useLocale(t, "de-DE")
+	assert.Equal(t, "18.2.", display.FormatDealDate("2/18"))
+	d := display.ToDealJSON(api.SavingItem{StartFormatted: "2/18", EndFormatted: "2/24"})
+	assert.Equal(t, "18.2.", d.ValidFrom)
+	assert.Equal(t, "24.2.", d.ValidTo)
+}
@@ -0,0 +1,53 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// PrintDealsMarkdown renders deals as a GitHub-flavored Markdown table, for
+// pasting into chat or notes.
+func PrintDealsMarkdown(w io.Writer, items []api.SavingItem) {
+	writeMarkdownRow(w, T("md.deal"), T("field.savings"), T("field.department"), T("field.valid"), T("md.bogo"))
+	fmt.Fprintln(w, "|---|---|---|---|:-:|")
+	for _, item := range items {
+		d := ToDealJSON(item)
+		valid := d.ValidFrom
+		if d.ValidTo != "" {
+			valid = joinNonEmpty(" – ", d.ValidFrom, d.ValidTo)
+		}
+		bogo := ""
+		if d.IsBogo {
+			bogo = T("md.yes")
+		}
+		writeMarkdownRow(w, filter.Title(item), d.Savings, d.Department, valid, bogo)
+	}
+}
+
+// PrintStoresMarkdown renders stores as a GitHub-flavored Markdown table.
+func PrintStoresMarkdown(w io.Writer, stores []api.Store) {
+	writeMarkdownRow(w, T("md.store"), T("md.name"), T("field.address"), T("field.distance"))
+	fmt.Fprintln(w, "|--:|---|---|--:|")
+	for _, s := range stores {
+		distance := ""
+		if s.Distance != "" {
+			distance = T("stores.miles", s.Distance)
+		}
+		st := ToStoreJSON(s)
+		writeMarkdownRow(w, "#"+st.Number, st.Name, st.Address, distance)
+	}
+}
+
+// writeMarkdownRow writes one table row, escaping pipes so a cell cannot
+// split the row.
+func writeMarkdownRow(w io.Writer, cells ...string) {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		escaped[i] = strings.ReplaceAll(strings.TrimSpace(c), "|", `\|`)
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+}
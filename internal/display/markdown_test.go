@@ -0,0 +1,34 @@
+package display_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func TestPrintDealsMarkdown(t *testing.T) {
+	deals := sampleDeals()
+	deals[0].Savings = ptr("$3.99 | lb")
+
+	var buf bytes.Buffer
+	display.PrintDealsMarkdown(&buf, deals)
+
+	assert.Equal(t, "| Deal | Savings | Department | Valid | BOGO |\n"+
+		"|---|---|---|---|:-:|\n"+
+		"| Chicken Breasts | $3.99 \\| lb | Meat | 2/18 – 2/24 |  |\n"+
+		"| Nutella & More | Buy 1 Get 1 FREE | Grocery | 2/18 – 2/24 | Yes |\n", buf.String())
+}
+
+func TestPrintStoresMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	display.PrintStoresMarkdown(&buf, []api.Store{
+		{Key: "01425", Name: "Peachers Mill", Addr: "1490 Tiny Town Rd", City: "Clarksville", State: "TN", Zip: "37042", Distance: "1.2"},
+	})
+
+	assert.Equal(t, "| Store | Name | Address | Distance |\n"+
+		"|--:|---|---|--:|\n"+
+		"| #1425 | Peachers Mill | 1490 Tiny Town Rd, Clarksville, TN 37042 | 1.2 miles |\n", buf.String())
+}
@@ -0,0 +1,215 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the interface language used unless another is
+// configured.
+const DefaultLanguage = "en"
+
+// catalogs maps an interface language to its messages, keyed by message ID.
+// Every language must define the same IDs with the same format verbs as en.
+var catalogs = map[string]map[string]string{
+	"en": messagesEN,
+	"es": messagesES,
+}
+
+var (
+	language = DefaultLanguage
+	messages = catalogs[DefaultLanguage]
+)
+
+// CanonicalLanguage returns the supported language matching tag, accepting
+// locale tags such as "es-MX" or "es_US.UTF-8" for "es". It returns false
+// for unsupported languages.
+func CanonicalLanguage(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := catalogs[tag]; !ok {
+		return "", false
+	}
+	return tag, true
+}
+
+// Languages lists the supported interface languages, sorted.
+func Languages() []string {
+	names := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetLanguage selects the language of labels and messages in text output
+// and the TUI. Unsupported languages fall back to DefaultLanguage. Text
+// copied from the ad, such as deal titles, is never translated.
+func SetLanguage(tag string) {
+	name, ok := CanonicalLanguage(tag)
+	if !ok {
+		name = DefaultLanguage
+	}
+	language, messages = name, catalogs[name]
+}
+
+// Language returns the interface language in effect.
+func Language() string {
+	return language
+}
+
+// T returns the message with the given ID in the current language, formatted
+// with args like fmt.Sprintf. Messages missing from a catalog fall back to
+// English, and unknown IDs are returned as is.
+func T(id string, args ...any) string {
+	msg, ok := messages[id]
+	if !ok {
+		if msg, ok = messagesEN[id]; !ok {
+			msg = id
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+var messagesEN = map[string]string{
+	// Deal lists.
+	"deals.header":       "Publix Weekly Deals",
+	"deals.count":        "%d items",
+	"deals.valid":        "Valid %s - %s",
+	"deals.stores":       "Stores %s",
+	"deals.group_other":  "Other",
+	"deal.brand_dept":    "%s deal (%s)",
+	"deal.named":         "%s deal",
+	"deal.id":            "Deal %s",
+	"deal.untitled":      "Untitled deal",
+	"expiry.ended":       "ended",
+	"expiry.hours":       "ends in %dh",
+	"expiry.days":        "ends in %dd",
+	"expiry.through":     "through %s",
+	"context.store":      "Using store: #%s — %s (%s, %s)",
+	"context.merged":     "Merged deals from %d stores near %s: %s",
+	"categories.header":  "Categories for store #%s this week:",
+	"categories.deals":   "%d deals",
+	"stores.header":      "Publix stores near %s:",
+	"stores.miles":       "%s miles",
+	"stores.open":        "open now",
+	"stores.closed":      "closed now",
+	"stores.temp_closed": "temporarily closed",
+	"stores.warn_closed": "Store #%s is closed right now.",
+	"stores.warn_temp":   "Store #%s is temporarily closed; deals may not be available.",
+
+	// Field labels, shared by accessible output and the TUI detail pane.
+	"field.offer":       "Offer",
+	"field.avoid":       "Avoid",
+	"field.bogo":        "Buy one, get one free",
+	"field.savings":     "Savings",
+	"field.deal_info":   "Deal info",
+	"field.description": "Description",
+	"field.department":  "Department",
+	"field.brand":       "Brand",
+	"field.valid":       "Valid",
+	"field.stores":      "Stores",
+	"field.address":     "Address",
+	"field.distance":    "Distance",
+	"field.status":      "Status",
+	"field.score":       "Score",
+	"field.image_url":   "Image URL",
+	"field.categories":  "categories",
+	"range.to":          "%s to %s",
+
+	// Markdown table headers not covered by the field labels.
+	"md.deal":  "Deal",
+	"md.bogo":  "BOGO",
+	"md.yes":   "Yes",
+	"md.store": "Store",
+	"md.name":  "Name",
+
+	// Accessible output.
+	"a11y.deals_header":      "Publix weekly deals. %d items.",
+	"a11y.deals_valid":       " Valid %s to %s.",
+	"a11y.deal":              "Deal %d of %d: %s",
+	"a11y.deals_group":       "Section %s. %d items.",
+	"a11y.stores_header":     "Publix stores near %s. %d stores.",
+	"a11y.store":             "Store %d of %d: number %s, %s",
+	"a11y.categories_header": "Categories for store number %s this week. %d categories.",
+	"a11y.context.store":     "Using store number %s, %s, %s, %s.",
+	"a11y.context.merged":    "Deals from %d stores near %s: numbers %s.",
+
+	// TUI.
+	"tui.deals":               "Deals",
+	"tui.deals_visible":       "Deals • %d visible",
+	"tui.item":                "item",
+	"tui.items":               "items",
+	"tui.loading":             "Loading interface...",
+	"tui.preparing":           "Preparing interactive interface...",
+	"tui.fetching":            "Fetching store and weekly deals",
+	"tui.tip_cancel":          "Tip: press q to cancel.",
+	"tui.too_small":           "Terminal too small (%dx%d).\nResize to at least %dx%d for the two-pane deal explorer.",
+	"tui.loaded":              "Loaded %d deals",
+	"tui.images_failed":       "Images not saved: %v",
+	"tui.images_saved":        "Saved %d images offline",
+	"tui.images_some_failed":  " (%d failed)",
+	"tui.saved_image":         "Saved image:",
+	"tui.filters_reset":       "Inline filters reset.",
+	"tui.filters_none":        "none",
+	"tui.clear_fuzzy_section": "Clear fuzzy filter before section jumps.",
+	"tui.clear_fuzzy_letter":  "Clear fuzzy filter before letter jumps.",
+	"tui.no_letter":           "No deal starting with %q in this section.",
+	"tui.no_items":            "No items.",
+	"tui.no_matches":          "No deals match the current inline filters.\n\nTry pressing r to reset filters.",
+	"tui.page":                "page %d/%d",
+	"tui.header_status":       "deals: %d visible / %d total  |  filters: %s  |  focus: %s",
+	"tui.focus_list":          "list",
+	"tui.focus_detail":        "detail",
+	"tui.footer":              "Tab switch pane • / fuzzy filter • f<letter> jump • F star • s sort • g bogo • c category • a department • l limit • r reset • [/] section jump • 1-9 section index • q quit",
+	"tui.footer_columns":      "←/→ column • ",
+	"tui.footer_letter":       "Letter jump: type a letter to move to the next matching deal in this section • any other key cancels",
+	"tui.footer_detail":       "Detail: j/k or ↑/↓ scroll • u/d half-page • b/f page • esc list • ? help • q quit",
+	"tui.help_title":          "Key Help",
+	"tui.help_list":           "list pane: ↑/↓ or j/k move • ←/→ column (wide terminals) • / fuzzy filter • c category • a department • g bogo • s sort • l limit • F star/unstar",
+	"tui.help_groups":         "group jumps: ] next section • [ previous section • 1..9 jump to numbered section header • f<letter> next deal starting with letter",
+	"tui.help_detail":         "detail pane: j/k or ↑/↓ scroll • u/d half-page • b/f page up/down",
+	"tui.help_global":         "global: tab switch pane • esc list • r reset inline options • ? toggle help • q quit • ctrl+c force quit",
+	"tui.section":             "Section %d: %s",
+	"tui.section_header":      "Section header • %d deals",
+	"tui.section_count":       "%d deals in this section",
+	"tui.section_line":        "Section %d: %s, %d deals",
+	"tui.jump_keys":           "Jump keys:",
+	"tui.jump_sections":       "- `]` next section, `[` previous section",
+	"tui.jump_numbers":        "- `1..9` jump directly to section number",
+	"tui.preview":             "Preview:",
+	"tui.group_other":         "Other",
+	"tui.no_savings_text":     "No savings text",
+	"tui.no_savings_value":    "No savings value provided",
+	"tui.no_description":      "No description provided.",
+	"tui.ends":                "ends %s",
+	"tui.details":             "Details:",
+	"tui.deal":                "Deal: %s",
+	"tui.load_failed":         "Could not load deals",
+	"tui.store_placeholder":   "store number (1425) or ZIP code (33101)",
+	"tui.store_prompt":        "store or zip> ",
+	"tui.store_hint":          "enter load • esc cancel",
+	"tui.error_hint":          "r retry • S switch store • q quit",
+	"tui.starred":             "Starred %s",
+	"tui.unstarred":           "Unstarred %s",
+	"tui.star_failed":         "Star not saved: %v",
+
+	// Notes and hints written to stderr after a listing.
+	"note.next_page": "note: more deals match; continue with --offset %d",
+	"hint.grouped":   "hint: %d deals; add --limit-per-group 5 to show the top of each group",
+	"hint.filtered":  "hint: %d deals match; add %s for the best of them",
+	"hint.narrow":    "%s, or narrow with %s",
+	"hint.plain":     "hint: %d deals; %s",
+	"hint.browse":    "browse them with `pubcli tui --store %s`",
+	"hint.summary":   "see the highlights with `pubcli summary --store %s`",
+	"hint.limit":     "add --limit 20",
+	"hint.or":        " or ",
+	"hint.or_last":   "%s, or %s",
+}
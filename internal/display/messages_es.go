@@ -0,0 +1,137 @@
+package display
+
+var messagesES = map[string]string{
+	// Deal lists.
+	"deals.header":       "Ofertas semanales de Publix",
+	"deals.count":        "%d artículos",
+	"deals.valid":        "Válido %s - %s",
+	"deals.stores":       "Tiendas %s",
+	"deals.group_other":  "Otros",
+	"deal.brand_dept":    "Oferta de %s (%s)",
+	"deal.named":         "Oferta de %s",
+	"deal.id":            "Oferta %s",
+	"deal.untitled":      "Oferta sin título",
+	"expiry.ended":       "terminó",
+	"expiry.hours":       "termina en %dh",
+	"expiry.days":        "termina en %dd",
+	"expiry.through":     "hasta el %s",
+	"context.store":      "Usando la tienda: #%s — %s (%s, %s)",
+	"context.merged":     "Ofertas combinadas de %d tiendas cerca de %s: %s",
+	"categories.header":  "Categorías de la tienda #%s esta semana:",
+	"categories.deals":   "%d ofertas",
+	"stores.header":      "Tiendas Publix cerca de %s:",
+	"stores.miles":       "%s millas",
+	"stores.open":        "abierta ahora",
+	"stores.closed":      "cerrada ahora",
+	"stores.temp_closed": "cerrada temporalmente",
+	"stores.warn_closed": "La tienda #%s está cerrada en este momento.",
+	"stores.warn_temp":   "La tienda #%s está cerrada temporalmente; puede que las ofertas no estén disponibles.",
+
+	// Field labels, shared by accessible output and the TUI detail pane.
+	"field.offer":       "Oferta",
+	"field.bogo":        "Compre uno y llévese otro gratis",
+	"field.savings":     "Ahorro",
+	"field.deal_info":   "Detalles de la oferta",
+	"field.description": "Descripción",
+	"field.department":  "Departamento",
+	"field.brand":       "Marca",
+	"field.avoid":       "Evitar",
+	"field.valid":       "Válido",
+	"field.stores":      "Tiendas",
+	"field.address":     "Dirección",
+	"field.distance":    "Distancia",
+	"field.status":      "Estado",
+	"field.score":       "Puntuación",
+	"field.image_url":   "URL de la imagen",
+	"field.categories":  "categorías",
+	"range.to":          "del %s al %s",
+
+	// Markdown table headers not covered by the field labels.
+	"md.deal":  "Oferta",
+	"md.bogo":  "BOGO",
+	"md.yes":   "Sí",
+	"md.store": "Tienda",
+	"md.name":  "Nombre",
+
+	// Accessible output.
+	"a11y.deals_header":      "Ofertas semanales de Publix. %d artículos.",
+	"a11y.deals_valid":       " Válido del %s al %s.",
+	"a11y.deal":              "Oferta %d de %d: %s",
+	"a11y.deals_group":       "Sección %s. %d artículos.",
+	"a11y.stores_header":     "Tiendas Publix cerca de %s. %d tiendas.",
+	"a11y.store":             "Tienda %d de %d: número %s, %s",
+	"a11y.categories_header": "Categorías de la tienda número %s esta semana. %d categorías.",
+	"a11y.context.store":     "Usando la tienda número %s, %s, %s, %s.",
+	"a11y.context.merged":    "Ofertas de %d tiendas cerca de %s: números %s.",
+
+	// TUI.
+	"tui.deals":               "Ofertas",
+	"tui.deals_visible":       "Ofertas • %d visibles",
+	"tui.item":                "artículo",
+	"tui.items":               "artículos",
+	"tui.loading":             "Cargando la interfaz...",
+	"tui.preparing":           "Preparando la interfaz interactiva...",
+	"tui.fetching":            "Buscando la tienda y las ofertas de la semana",
+	"tui.tip_cancel":          "Consejo: pulse q para cancelar.",
+	"tui.too_small":           "Terminal demasiado pequeña (%dx%d).\nAmplíela a por lo menos %dx%d para el explorador de ofertas de dos paneles.",
+	"tui.loaded":              "%d ofertas cargadas",
+	"tui.images_failed":       "No se guardaron las imágenes: %v",
+	"tui.images_saved":        "%d imágenes guardadas sin conexión",
+	"tui.images_some_failed":  " (%d fallaron)",
+	"tui.saved_image":         "Imagen guardada:",
+	"tui.filters_reset":       "Filtros restablecidos.",
+	"tui.filters_none":        "ninguno",
+	"tui.clear_fuzzy_section": "Borre el filtro difuso antes de saltar entre secciones.",
+	"tui.clear_fuzzy_letter":  "Borre el filtro difuso antes de saltar por letra.",
+	"tui.no_letter":           "Ninguna oferta empieza con %q en esta sección.",
+	"tui.no_items":            "No hay artículos.",
+	"tui.no_matches":          "Ninguna oferta coincide con los filtros actuales.\n\nPulse r para restablecer los filtros.",
+	"tui.page":                "página %d/%d",
+	"tui.header_status":       "ofertas: %d visibles / %d en total  |  filtros: %s  |  foco: %s",
+	"tui.focus_list":          "lista",
+	"tui.focus_detail":        "detalle",
+	"tui.footer":              "Tab cambiar panel • / filtro difuso • f<letra> saltar • F favorito • s ordenar • g bogo • c categoría • a departamento • l límite • r restablecer • [/] saltar sección • 1-9 número de sección • q salir",
+	"tui.footer_columns":      "←/→ columna • ",
+	"tui.footer_letter":       "Salto por letra: escriba una letra para ir a la siguiente oferta que coincida en esta sección • cualquier otra tecla cancela",
+	"tui.footer_detail":       "Detalle: j/k o ↑/↓ desplazar • u/d media página • b/f página • esc lista • ? ayuda • q salir",
+	"tui.help_title":          "Ayuda de teclas",
+	"tui.help_list":           "panel de lista: ↑/↓ o j/k mover • ←/→ columna (terminales anchas) • / filtro difuso • c categoría • a departamento • g bogo • s ordenar • l límite • F marcar/desmarcar favorito",
+	"tui.help_groups":         "saltos de grupo: ] sección siguiente • [ sección anterior • 1..9 ir al encabezado de sección numerado • f<letra> siguiente oferta que empieza con la letra",
+	"tui.help_detail":         "panel de detalle: j/k o ↑/↓ desplazar • u/d media página • b/f página arriba/abajo",
+	"tui.help_global":         "global: tab cambiar panel • esc lista • r restablecer opciones • ? mostrar ayuda • q salir • ctrl+c forzar salida",
+	"tui.section":             "Sección %d: %s",
+	"tui.section_header":      "Encabezado de sección • %d ofertas",
+	"tui.section_count":       "%d ofertas en esta sección",
+	"tui.section_line":        "Sección %d: %s, %d ofertas",
+	"tui.jump_keys":           "Teclas de salto:",
+	"tui.jump_sections":       "- `]` sección siguiente, `[` sección anterior",
+	"tui.jump_numbers":        "- `1..9` ir directamente al número de sección",
+	"tui.preview":             "Vista previa:",
+	"tui.group_other":         "Otros",
+	"tui.no_savings_text":     "Sin texto de ahorro",
+	"tui.no_savings_value":    "No se indicó el ahorro",
+	"tui.no_description":      "No se indicó una descripción.",
+	"tui.ends":                "termina %s",
+	"tui.details":             "Detalles:",
+	"tui.deal":                "Oferta: %s",
+	"tui.load_failed":         "No se pudieron cargar las ofertas",
+	"tui.store_placeholder":   "número de tienda (1425) o código postal (33101)",
+	"tui.store_prompt":        "tienda o código postal> ",
+	"tui.store_hint":          "enter cargar • esc cancelar",
+	"tui.error_hint":          "r reintentar • S cambiar tienda • q salir",
+	"tui.starred":             "%s marcado como favorito",
+	"tui.unstarred":           "%s ya no es favorito",
+	"tui.star_failed":         "No se guardó el favorito: %v",
+
+	// Notes and hints written to stderr after a listing.
+	"note.next_page": "nota: hay más ofertas; continúe con --offset %d",
+	"hint.grouped":   "sugerencia: %d ofertas; añada --limit-per-group 5 para ver las mejores de cada grupo",
+	"hint.filtered":  "sugerencia: %d ofertas coinciden; añada %s para ver las mejores",
+	"hint.narrow":    "%s, o acote con %s",
+	"hint.plain":     "sugerencia: %d ofertas; %s",
+	"hint.browse":    "explórelas con `pubcli tui --store %s`",
+	"hint.summary":   "vea lo más destacado con `pubcli summary --store %s`",
+	"hint.limit":     "añada --limit 20",
+	"hint.or":        " o ",
+	"hint.or_last":   "%s o %s",
+}
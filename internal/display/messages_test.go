@@ -0,0 +1,86 @@
+package display_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func useLanguage(t *testing.T, tag string) {
+	t.Helper()
+	display.SetLanguage(tag)
+	t.Cleanup(func() { display.SetLanguage(display.DefaultLanguage) })
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	en := display.Catalogs[display.DefaultLanguage]
+	for lang, catalog := range display.Catalogs {
+		assert.Len(t, catalog, len(en), lang)
+		for id, msg := range en {
+			translated, ok := catalog[id]
+			if !assert.True(t, ok, "%s is missing %s", lang, id) {
+				continue
+			}
+			assert.Equal(t, formatVerb.FindAllString(msg, -1), formatVerb.FindAllString(translated, -1), "%s %s", lang, id)
+		}
+	}
+}
+
+func TestCanonicalLanguage(t *testing.T) {
+	for _, tag := range []string{"es", "ES", "es-MX", "es_US.UTF-8"} {
+		name, ok := display.CanonicalLanguage(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, "es", name, tag)
+	}
+	_, ok := display.CanonicalLanguage("tlh")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"en", "es"}, display.Languages())
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "42 items", display.T("deals.count", 42))
+	assert.Equal(t, "no.such.message", display.T("no.such.message"))
+
+	useLanguage(t, "es-MX")
+	assert.Equal(t, "es", display.Language())
+	assert.Equal(t, "42 artículos", display.T("deals.count", 42))
+}
+
+func TestPrintStores_Spanish(t *testing.T) {
+	useLanguage(t, "es")
+	stores := []api.Store{{Key: "01425", Name: "Peachers Mill", Addr: "1490 Tiny Town Rd", City: "Clarksville", State: "TN", Zip: "37042", Distance: "5"}}
+
+	var buf bytes.Buffer
+	display.PrintStores(&buf, stores, "37042")
+	assert.Contains(t, buf.String(), "Tiendas Publix cerca de 37042:")
+	assert.Contains(t, buf.String(), "5 millas")
+
+	buf.Reset()
+	display.SetAccessible(true)
+	t.Cleanup(func() { display.SetAccessible(false) })
+	display.PrintStores(&buf, stores, "37042")
+	assert.Contains(t, buf.String(), "Tienda 1 de 1: número 1425, Peachers Mill")
+	assert.Contains(t, buf.String(), "Dirección: 1490 Tiny Town Rd")
+}
+
+func TestAccessibleDealFields_Spanish(t *testing.T) {
+	useLanguage(t, "es")
+	savings := "Save $2.00"
+	fields := display.AccessibleDealFields(api.SavingItem{
+		Savings:        &savings,
+		Categories:     []string{"bogo"},
+		StartFormatted: "2/18",
+		EndFormatted:   "2/24",
+	})
+	assert.Equal(t, [][2]string{
+		{"Oferta", "Compre uno y llévese otro gratis"},
+		{"Ahorro", "Save $2.00"},
+		{"Válido", "del 2/18 al 2/24"},
+	}, fields, "deal text from the ad stays as Publix wrote it")
+}
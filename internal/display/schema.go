@@ -0,0 +1,96 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// JSON schema versions. Version 1 is the original bare-array/bare-map output;
+// version 2 wraps every payload in an object that carries schemaVersion.
+const (
+	SchemaV1            = 1
+	SchemaV2            = 2
+	LatestSchemaVersion = SchemaV2
+)
+
+var schemaVersion = LatestSchemaVersion
+
+// SetSchemaVersion selects the JSON schema version used by the Print*JSON
+// helpers. Invalid versions fall back to the latest.
+func SetSchemaVersion(v int) {
+	if !ValidSchemaVersion(v) {
+		v = LatestSchemaVersion
+	}
+	schemaVersion = v
+}
+
+// SchemaVersion returns the JSON schema version in effect.
+func SchemaVersion() int {
+	return schemaVersion
+}
+
+// ValidSchemaVersion reports whether v is a supported schema version.
+func ValidSchemaVersion(v int) bool {
+	return v >= SchemaV1 && v <= LatestSchemaVersion
+}
+
+// EncodeVersioned writes payload as-is for v1, or as
+// {"schemaVersion":N,"<key>":payload} for v2 and later.
+func EncodeVersioned(w io.Writer, version int, key string, payload any) error {
+	if version < SchemaV2 {
+		return json.NewEncoder(w).Encode(payload)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	name, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "{\"schemaVersion\":%d,%s:%s}\n", version, name, body)
+	return err
+}
+
+// PrintVersionedJSON is EncodeVersioned using the configured schema version.
+func PrintVersionedJSON(w io.Writer, key string, payload any) error {
+	return EncodeVersioned(w, schemaVersion, key, payload)
+}
+
+// PrintVersionedJSONWith is PrintVersionedJSON with extra members, such as
+// diagnostics, after the payload. Schema v1 has no envelope to hold them, so
+// they are left out.
+func PrintVersionedJSONWith(w io.Writer, key string, payload any, extra map[string]any) error {
+	return EncodeVersionedWith(w, schemaVersion, key, payload, extra)
+}
+
+// EncodeVersionedWith is EncodeVersioned with extra members after the
+// payload, left out for schema v1 as in PrintVersionedJSONWith.
+func EncodeVersionedWith(w io.Writer, version int, key string, payload any, extra map[string]any) error {
+	if version < SchemaV2 || len(extra) == 0 {
+		return EncodeVersioned(w, version, key, payload)
+	}
+	var buf bytes.Buffer
+	if err := EncodeVersioned(&buf, version, key, payload); err != nil {
+		return err
+	}
+	envelope := bytes.TrimSuffix(bytes.TrimSpace(buf.Bytes()), []byte("}"))
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		body, err := json.Marshal(extra[name])
+		if err != nil {
+			return err
+		}
+		quoted, _ := json.Marshal(name)
+		envelope = fmt.Appendf(envelope, ",%s:%s", quoted, body)
+	}
+	_, err := fmt.Fprintf(w, "%s}\n", envelope)
+	return err
+}
@@ -0,0 +1,54 @@
+package display_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+func TestEncodeVersioned_V1IsLegacyShape(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, display.EncodeVersioned(&buf, display.SchemaV1, "stores", []string{"a"}))
+	assert.Equal(t, "[\"a\"]\n", buf.String())
+}
+
+func TestEncodeVersioned_V2WrapsPayload(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, display.EncodeVersioned(&buf, display.SchemaV2, "stores", []string{"a"}))
+	assert.Equal(t, "{\"schemaVersion\":2,\"stores\":[\"a\"]}\n", buf.String())
+}
+
+func TestPrintVersionedJSONWith_AddsMembers(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, display.PrintVersionedJSONWith(&buf, "stores", []string{"a"}, map[string]any{"diagnostics": map[string]int{"failed": 1}}))
+	assert.Equal(t, "{\"schemaVersion\":2,\"stores\":[\"a\"],\"diagnostics\":{\"failed\":1}}\n", buf.String())
+
+	display.SetSchemaVersion(display.SchemaV1)
+	t.Cleanup(func() { display.SetSchemaVersion(display.LatestSchemaVersion) })
+	buf.Reset()
+	require.NoError(t, display.PrintVersionedJSONWith(&buf, "stores", []string{"a"}, map[string]any{"diagnostics": 1}))
+	assert.Equal(t, "[\"a\"]\n", buf.String())
+}
+
+func TestPrintDealsJSON_PinnedV1(t *testing.T) {
+	display.SetSchemaVersion(display.SchemaV1)
+	t.Cleanup(func() { display.SetSchemaVersion(display.LatestSchemaVersion) })
+
+	var buf bytes.Buffer
+	require.NoError(t, display.PrintDealsJSON(&buf, sampleDeals()))
+
+	var deals []display.DealJSON
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &deals))
+	assert.Len(t, deals, 2)
+}
+
+func TestSetSchemaVersion_InvalidFallsBackToLatest(t *testing.T) {
+	display.SetSchemaVersion(99)
+	assert.Equal(t, display.LatestSchemaVersion, display.SchemaVersion())
+	assert.False(t, display.ValidSchemaVersion(0))
+	assert.True(t, display.ValidSchemaVersion(1))
+}
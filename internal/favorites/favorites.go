@@ -0,0 +1,169 @@
+// Package favorites keeps the deals the user starred, so later weekly ads
+// can be checked for the same items.
+package favorites
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// FileName is the favorites' file name inside the data directory.
+const FileName = "favorites.json"
+
+// Star is a starred deal. Deal IDs change with every weekly ad, so a star
+// is identified by Key, the deal's title, and matches that title in any
+// later ad.
+type Star struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	// DealID, Department, and Store record the deal that was starred.
+	DealID     string    `json:"dealId,omitempty"`
+	Department string    `json:"department,omitempty"`
+	Store      string    `json:"store,omitempty"`
+	StarredAt  time.Time `json:"starredAt"`
+}
+
+// List holds the stars in the order they were added.
+type List struct {
+	Stars []Star `json:"stars"`
+}
+
+// Key is the stable ID of a deal: its title, lowercased with runs of
+// spaces collapsed.
+func Key(item api.SavingItem) string {
+	return normalize(filter.Title(item))
+}
+
+func normalize(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// NewStar stars the deal, found in the ad of store.
+func NewStar(store string, item api.SavingItem, now time.Time) Star {
+	return Star{
+		Key:        Key(item),
+		Title:      filter.Title(item),
+		DealID:     item.ID,
+		Department: filter.CleanText(filter.Deref(item.Department)),
+		Store:      store,
+		StarredAt:  now.UTC(),
+	}
+}
+
+// Load reads the favorites stored under key. Missing favorites are empty.
+func Load(b storage.Backend, key string) (*List, error) {
+	obj, err := b.Get(key)
+	if errors.Is(err, storage.ErrNotExist) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading favorites: %w", err)
+	}
+	l := &List{}
+	if err := json.Unmarshal(obj.Data, l); err != nil {
+		return nil, fmt.Errorf("parsing favorites %s: %w", key, err)
+	}
+	return l, nil
+}
+
+// Save stores the favorites under key, replacing the previous ones.
+func (l *List) Save(b storage.Backend, key string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.Put(key, storage.Object{Data: data}); err != nil {
+		return fmt.Errorf("writing favorites: %w", err)
+	}
+	return nil
+}
+
+// Has reports whether the deal is starred.
+func (l *List) Has(item api.SavingItem) bool {
+	return l.index(Key(item)) >= 0
+}
+
+// Add stars a deal. It reports false when the deal was already starred.
+func (l *List) Add(star Star) bool {
+	if l.index(star.Key) >= 0 {
+		return false
+	}
+	l.Stars = append(l.Stars, star)
+	return true
+}
+
+// Toggle stars the deal, or unstars it when it was starred, and reports
+// whether it is starred now.
+func (l *List) Toggle(star Star) bool {
+	if i := l.index(star.Key); i >= 0 {
+		l.Stars = append(l.Stars[:i], l.Stars[i+1:]...)
+		return false
+	}
+	l.Stars = append(l.Stars, star)
+	return true
+}
+
+// Remove unstars the deal identified by ref: a 1-based position as shown by
+// `fav list`, or a case-insensitive title.
+func (l *List) Remove(ref string) (Star, error) {
+	ref = strings.TrimSpace(ref)
+	i := -1
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(l.Stars) {
+			return Star{}, fmt.Errorf("no favorite #%d (there are %d)", n, len(l.Stars))
+		}
+		i = n - 1
+	} else if i = l.index(normalize(ref)); i < 0 {
+		return Star{}, fmt.Errorf("no favorite named %q", ref)
+	}
+	removed := l.Stars[i]
+	l.Stars = append(l.Stars[:i], l.Stars[i+1:]...)
+	return removed, nil
+}
+
+func (l *List) index(key string) int {
+	for i, s := range l.Stars {
+		if s.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Match is a star with the deals of a weekly ad that carry its title.
+type Match struct {
+	Star
+	Deals []display.DealJSON `json:"deals"`
+}
+
+// OnSale reports whether the ad carries the starred item.
+func (m Match) OnSale() bool {
+	return len(m.Deals) > 0
+}
+
+// Match looks every star up in the deals of a weekly ad, in star order.
+func (l *List) Match(deals []api.SavingItem) []Match {
+	byKey := map[string][]display.DealJSON{}
+	for _, d := range deals {
+		k := Key(d)
+		byKey[k] = append(byKey[k], display.ToDealJSON(d))
+	}
+	matches := make([]Match, 0, len(l.Stars))
+	for _, s := range l.Stars {
+		found := byKey[s.Key]
+		if found == nil {
+			found = []display.DealJSON{}
+		}
+		matches = append(matches, Match{Star: s, Deals: found})
+	}
+	return matches
+}
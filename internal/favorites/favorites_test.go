@@ -0,0 +1,81 @@
+package favorites_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/favorites"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func ptr(s string) *string { return &s }
+
+func TestList_StarsByTitle(t *testing.T) {
+	now := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	coffee := api.SavingItem{ID: "1", Title: ptr("Publix Coffee"), Department: ptr("Grocery")}
+
+	l := &favorites.List{}
+	star := favorites.NewStar("1425", coffee, now)
+	assert.Equal(t, favorites.Star{Key: "publix coffee", Title: "Publix Coffee", DealID: "1", Department: "Grocery", Store: "1425", StarredAt: now}, star)
+	assert.True(t, l.Add(star))
+	assert.False(t, l.Add(star), "already starred")
+
+	nextWeek := api.SavingItem{ID: "99", Title: ptr("publix  COFFEE")}
+	assert.True(t, l.Has(nextWeek), "a new deal ID with the same title is the same star")
+
+	assert.False(t, l.Toggle(favorites.NewStar("1425", nextWeek, now)))
+	assert.Empty(t, l.Stars)
+	assert.True(t, l.Toggle(star))
+	assert.Len(t, l.Stars, 1)
+}
+
+func TestList_Remove(t *testing.T) {
+	l := &favorites.List{Stars: []favorites.Star{{Key: "bananas", Title: "Bananas"}, {Key: "publix coffee", Title: "Publix Coffee"}}}
+
+	removed, err := l.Remove("PUBLIX COFFEE")
+	require.NoError(t, err)
+	assert.Equal(t, "Publix Coffee", removed.Title)
+
+	_, err = l.Remove("3")
+	assert.EqualError(t, err, "no favorite #3 (there are 1)")
+	_, err = l.Remove("caviar")
+	assert.EqualError(t, err, `no favorite named "caviar"`)
+
+	removed, err = l.Remove("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Bananas", removed.Title)
+	assert.Empty(t, l.Stars)
+}
+
+func TestList_Match(t *testing.T) {
+	l := &favorites.List{Stars: []favorites.Star{{Key: "publix coffee", Title: "Publix Coffee"}, {Key: "bananas", Title: "Bananas"}}}
+	deals := []api.SavingItem{
+		{ID: "7", Title: ptr("Publix Coffee"), Savings: ptr("Buy 1 Get 1 FREE")},
+		{ID: "8", Title: ptr("Publix Coffee Creamer")},
+	}
+
+	matches := l.Match(deals)
+	require.Len(t, matches, 2)
+	assert.True(t, matches[0].OnSale())
+	require.Len(t, matches[0].Deals, 1)
+	assert.Equal(t, "Buy 1 Get 1 FREE", matches[0].Deals[0].Savings)
+	assert.False(t, matches[1].OnSale())
+	assert.NotNil(t, matches[1].Deals, "JSON lists no deals as []")
+}
+
+func TestLoadAndSave(t *testing.T) {
+	b := &storage.Files{Dir: t.TempDir()}
+	l, err := favorites.Load(b, favorites.FileName)
+	require.NoError(t, err)
+	assert.Empty(t, l.Stars)
+
+	l.Add(favorites.Star{Key: "bananas", Title: "Bananas"})
+	require.NoError(t, l.Save(b, favorites.FileName))
+
+	loaded, err := favorites.Load(b, favorites.FileName)
+	require.NoError(t, err)
+	assert.Equal(t, l.Stars, loaded.Stars)
+}
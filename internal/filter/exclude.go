@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+// Exclusions hides deals in any of its categories or departments. Categories
+// match as Options.Category does, synonyms included, and departments as
+// Options.Department does, by case-insensitive substring.
+type Exclusions struct {
+	Categories  []string
+	Departments []string
+}
+
+// Empty reports whether nothing is excluded.
+func (e Exclusions) Empty() bool {
+	return len(e.Categories) == 0 && len(e.Departments) == 0
+}
+
+// Names lists the excluded categories and departments, for notes.
+func (e Exclusions) Names() []string {
+	return append(append([]string{}, e.Categories...), e.Departments...)
+}
+
+// Apply returns the items not excluded, in order. Items is not modified.
+func (e Exclusions) Apply(items []api.SavingItem) []api.SavingItem {
+	if e.Empty() {
+		return items
+	}
+	matchers := make([]categoryMatcher, 0, len(e.Categories))
+	for _, c := range e.Categories {
+		matchers = append(matchers, newCategoryMatcher(c))
+	}
+	departments := make([]string, 0, len(e.Departments))
+	for _, d := range e.Departments {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			departments = append(departments, d)
+		}
+	}
+
+	kept := make([]api.SavingItem, 0, len(items))
+	for _, item := range items {
+		if !excluded(item, matchers, departments) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func excluded(item api.SavingItem, matchers []categoryMatcher, departments []string) bool {
+	for _, c := range item.Categories {
+		for _, m := range matchers {
+			if m.matches(c) {
+				return true
+			}
+		}
+	}
+	if len(departments) > 0 {
+		dept := strings.ToLower(Deref(item.Department))
+		for _, d := range departments {
+			if strings.Contains(dept, d) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"html"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/tayloree/publix-deals/internal/api"
 )
@@ -16,6 +17,22 @@ type Options struct {
 	Query      string
 	Sort       string
 	Limit      int
+	// ExcludeExpired drops deals whose end date has passed.
+	ExcludeExpired bool
+	// MinPercentOff keeps deals whose Savings.Percent, stated or estimated,
+	// is at least this; 0 keeps every deal.
+	MinPercentOff float64
+	// GroupBy gathers the deals by "department" or "category", groups in
+	// the order their first deal comes, and LimitPerGroup keeps at most
+	// that many deals of each. LimitPerGroup is ignored without GroupBy;
+	// Limit still caps the total.
+	GroupBy       string
+	LimitPerGroup int
+	// NewOnly keeps the deals whose IsNew is true; see addiff.MarkNew.
+	NewOnly bool
+	// Offset skips that many deals of the final order before Limit applies,
+	// so pages of Limit deals at growing offsets cover the result once.
+	Offset int
 }
 
 // Apply filters a slice of SavingItems according to the given options.
@@ -23,30 +40,43 @@ func Apply(items []api.SavingItem, opts Options) []api.SavingItem {
 	wantCategory := opts.Category != ""
 	wantDepartment := opts.Department != ""
 	wantQuery := opts.Query != ""
-	needsFiltering := opts.BOGO || wantCategory || wantDepartment || wantQuery
+	needsFiltering := opts.BOGO || wantCategory || wantDepartment || wantQuery || opts.ExcludeExpired || opts.MinPercentOff > 0 || opts.NewOnly
 	sortMode := normalizeSortMode(opts.Sort)
 	hasSort := sortMode != ""
+	groupBy := normalizeGroupBy(opts.GroupBy)
+	offset := max(opts.Offset, 0)
+	// end is how many deals of the final order are needed; 0 is all.
+	end := 0
+	if opts.Limit > 0 {
+		end = offset + opts.Limit
+	}
 
-	if !needsFiltering && !hasSort {
-		if opts.Limit > 0 && opts.Limit < len(items) {
-			return items[:opts.Limit]
-		}
-		return items
+	if !needsFiltering && !hasSort && groupBy == "" {
+		return page(items, offset, end)
 	}
 
 	var result []api.SavingItem
-	if opts.Limit > 0 && opts.Limit < len(items) {
-		result = make([]api.SavingItem, 0, opts.Limit)
+	if end > 0 && end < len(items) {
+		result = make([]api.SavingItem, 0, end)
 	} else {
 		result = make([]api.SavingItem, 0, len(items))
 	}
 
 	department := strings.ToLower(opts.Department)
 	query := strings.ToLower(opts.Query)
-	applyLimitWhileFiltering := !hasSort && opts.Limit > 0
+	applyLimitWhileFiltering := !hasSort && groupBy == "" && end > 0
 	categoryMatcher := newCategoryMatcher(opts.Category)
+	now := time.Now()
 
 	for _, item := range items {
+		if opts.ExcludeExpired && Expired(item, now) {
+			continue
+		}
+
+		if opts.NewOnly && (item.IsNew == nil || !*item.IsNew) {
+			continue
+		}
+
 		if opts.BOGO || wantCategory {
 			hasBogo := !opts.BOGO
 			hasCategory := !wantCategory
@@ -72,6 +102,10 @@ func Apply(items []api.SavingItem, opts Options) []api.SavingItem {
 			continue
 		}
 
+		if opts.MinPercentOff > 0 && ParseSavings(item).Percent < opts.MinPercentOff {
+			continue
+		}
+
 		if wantQuery {
 			title := strings.ToLower(CleanText(Deref(item.Title)))
 			desc := strings.ToLower(CleanText(Deref(item.Description)))
@@ -81,7 +115,7 @@ func Apply(items []api.SavingItem, opts Options) []api.SavingItem {
 		}
 
 		result = append(result, item)
-		if applyLimitWhileFiltering && len(result) >= opts.Limit {
+		if applyLimitWhileFiltering && len(result) >= end {
 			break
 		}
 	}
@@ -89,9 +123,10 @@ func Apply(items []api.SavingItem, opts Options) []api.SavingItem {
 	if hasSort && len(result) > 1 {
 		sortItems(result, sortMode)
 	}
-	if opts.Limit > 0 && opts.Limit < len(result) {
-		result = result[:opts.Limit]
+	if groupBy != "" {
+		result = groupItems(result, groupBy, opts.LimitPerGroup)
 	}
+	result = page(result, offset, end)
 
 	if len(result) == 0 {
 		return nil
@@ -99,6 +134,18 @@ func Apply(items []api.SavingItem, opts Options) []api.SavingItem {
 	return result
 }
 
+// page returns items[offset:end], clamped to the slice; an end of 0 runs to
+// the last item.
+func page(items []api.SavingItem, offset, end int) []api.SavingItem {
+	if offset >= len(items) {
+		return nil
+	}
+	if end > 0 && end < len(items) {
+		items = items[:end]
+	}
+	return items[offset:]
+}
+
 // Categories returns a map of category name to count across all items.
 func Categories(items []api.SavingItem) map[string]int {
 	cats := make(map[string]int)
@@ -118,11 +165,15 @@ func Deref(s *string) string {
 	return *s
 }
 
-// CleanText unescapes HTML entities and normalizes whitespace.
+// CleanText strips HTML tags, unescapes HTML entities, and normalizes
+// whitespace to single-line text.
 func CleanText(s string) string {
-	if !strings.ContainsAny(s, "&\r\n") {
+	if !strings.ContainsAny(s, "&<\r\n") {
 		return strings.TrimSpace(s)
 	}
+	if strings.Contains(s, "<") {
+		return strings.Join(strings.Fields(CleanRichText(s)), " ")
+	}
 
 	s = html.UnescapeString(s)
 	if !strings.ContainsAny(s, "\r\n") {
@@ -152,27 +203,54 @@ func sortItems(items []api.SavingItem, mode string) {
 			left := DealScore(items[i])
 			right := DealScore(items[j])
 			if left == right {
-				return strings.ToLower(CleanText(Deref(items[i].Title))) < strings.ToLower(CleanText(Deref(items[j].Title)))
+				return tieBefore(items[i], items[j])
 			}
 			return left > right
 		})
 	case "ending":
+		now := time.Now()
 		sort.SliceStable(items, func(i, j int) bool {
-			leftDate, leftOK := parseDealDate(items[i].EndFormatted)
-			rightDate, rightOK := parseDealDate(items[j].EndFormatted)
+			leftDate, leftOK := EndDay(items[i], now)
+			rightDate, rightOK := EndDay(items[j], now)
 			switch {
-			case leftOK && rightOK:
-				if leftDate.Equal(rightDate) {
-					return DealScore(items[i]) > DealScore(items[j])
-				}
+			case leftOK && rightOK && !leftDate.Equal(rightDate):
 				return leftDate.Before(rightDate)
-			case leftOK:
-				return true
-			case rightOK:
-				return false
-			default:
-				return DealScore(items[i]) > DealScore(items[j])
+			case leftOK != rightOK:
+				return leftOK
+			}
+			left := DealScore(items[i])
+			right := DealScore(items[j])
+			if left == right {
+				return tieBefore(items[i], items[j])
 			}
+			return left > right
 		})
 	}
 }
+
+// tieBefore orders deals the sort modes rank equally: by lowercase cleaned
+// title, then by ID, so the order does not depend on the order the API
+// returned them in.
+func tieBefore(a, b api.SavingItem) bool {
+	left := strings.ToLower(CleanText(Deref(a.Title)))
+	right := strings.ToLower(CleanText(Deref(b.Title)))
+	if left != right {
+		return left < right
+	}
+	return a.ID < b.ID
+}
+
+// Title returns a deal's cleaned title, falling back to its description,
+// then its ID, so every deal has something to show.
+func Title(item api.SavingItem) string {
+	if title := CleanText(Deref(item.Title)); title != "" {
+		return title
+	}
+	if desc := CleanText(Deref(item.Description)); desc != "" {
+		return desc
+	}
+	if item.ID != "" {
+		return "Deal " + item.ID
+	}
+	return "Untitled deal"
+}
@@ -4,8 +4,10 @@ import (
 	"html"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tayloree/publix-deals/internal/api"
 	"github.com/tayloree/publix-deals/internal/filter"
 )
@@ -126,6 +128,28 @@ func TestApply_Limit(t *testing.T) {
 	assert.Len(t, result, 2)
 }
 
+func TestApply_OffsetPagesThroughTheResult(t *testing.T) {
+	ids := func(items []api.SavingItem) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+	for _, opts := range []filter.Options{{}, {Sort: "savings"}, {Query: "e"}} {
+		all := ids(filter.Apply(sampleItems(), opts))
+		var paged []string
+		for offset := 0; offset < len(all)+2; offset += 2 {
+			opts.Offset, opts.Limit = offset, 2
+			paged = append(paged, ids(filter.Apply(sampleItems(), opts))...)
+		}
+		assert.Equal(t, all, paged, opts.Sort+opts.Query)
+	}
+
+	assert.Equal(t, []string{"4", "5"}, ids(filter.Apply(sampleItems(), filter.Options{Offset: 3})))
+	assert.Nil(t, filter.Apply(sampleItems(), filter.Options{Offset: 5}))
+}
+
 func TestApply_CombinedFilters(t *testing.T) {
 	result := filter.Apply(sampleItems(), filter.Options{
 		BOGO:  true,
@@ -135,6 +159,49 @@ func TestApply_CombinedFilters(t *testing.T) {
 	assert.Equal(t, "2", result[0].ID)
 }
 
+func TestApply_MinPercentOff(t *testing.T) {
+	items := []api.SavingItem{
+		{ID: "stated", Savings: ptr("25% off")},
+		{ID: "bogo", Categories: []string{"bogo"}},
+		{ID: "estimated", Savings: ptr("$3.00"), AdditionalDealInfo: ptr("Save $1.00")},
+		{ID: "unknown", Savings: ptr("Great deal")},
+	}
+	var ids []string
+	for _, item := range filter.Apply(items, filter.Options{MinPercentOff: 25}) {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []string{"stated", "bogo", "estimated"}, ids)
+	assert.Len(t, filter.Apply(items, filter.Options{MinPercentOff: 50}), 1)
+}
+
+func TestApply_GroupByLimitPerGroup(t *testing.T) {
+	items := []api.SavingItem{
+		{ID: "a", Department: ptr("Meat"), Savings: ptr("$1.00 off")},
+		{ID: "b", Department: ptr("Produce"), Savings: ptr("$5.00 off")},
+		{ID: "c", Department: ptr("Meat"), Savings: ptr("$4.00 off")},
+		{ID: "d", Department: ptr("Meat"), Savings: ptr("$3.00 off")},
+		{ID: "e", Categories: []string{"bogo"}},
+	}
+	ids := func(result []api.SavingItem) []string {
+		var out []string
+		for _, item := range result {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+
+	grouped := filter.Apply(items, filter.Options{GroupBy: "department", LimitPerGroup: 2, Sort: "savings"})
+	assert.Equal(t, []string{"e", "b", "c", "d"}, ids(grouped), "groups in order of their best deal")
+
+	grouped = filter.Apply(items, filter.Options{GroupBy: "department", LimitPerGroup: 1, Limit: 2})
+	assert.Equal(t, []string{"a", "b"}, ids(grouped))
+
+	assert.Len(t, filter.Apply(items, filter.Options{LimitPerGroup: 1}), 5, "ignored without GroupBy")
+	assert.Equal(t, "meat", filter.Group(api.SavingItem{Categories: []string{"BOGO", "Meat"}}, "category"))
+	assert.True(t, filter.ValidGroupBy("Dept"))
+	assert.False(t, filter.ValidGroupBy("brand"))
+}
+
 func TestApply_SortSavings(t *testing.T) {
 	items := []api.SavingItem{
 		{ID: "a", Title: ptr("A"), Savings: ptr("$1.00 off")},
@@ -148,6 +215,34 @@ func TestApply_SortSavings(t *testing.T) {
 	assert.Equal(t, "b", result[1].ID)
 }
 
+func TestApply_SortSavingsIgnoresSalePrices(t *testing.T) {
+	items := []api.SavingItem{
+		{ID: "steak", Title: ptr("Steak"), Savings: ptr("$12.99 lb")},
+		{ID: "soup", Title: ptr("Soup"), Savings: ptr("$1.00 off")},
+		{ID: "coffee", Title: ptr("Coffee"), Savings: ptr("$8.99"), AdditionalDealInfo: ptr("Save $3.00")},
+	}
+	result := filter.Apply(items, filter.Options{Sort: "savings"})
+
+	assert.Equal(t, []string{"coffee", "soup", "steak"}, []string{result[0].ID, result[1].ID, result[2].ID})
+	assert.Equal(t, 0.01, filter.DealScore(items[0]), "a price alone saves nothing")
+}
+
+func TestApply_SortTiesAreStable(t *testing.T) {
+	items := []api.SavingItem{
+		{ID: "3", Title: ptr("beans"), Savings: ptr("$1.00 off")},
+		{ID: "2", Title: ptr("Apples"), Savings: ptr("$1.00 off")},
+		{ID: "1", Title: ptr("Beans"), Savings: ptr("$1.00 off")},
+	}
+	for _, mode := range []string{"savings", "ending"} {
+		result := filter.Apply(append([]api.SavingItem(nil), items...), filter.Options{Sort: mode})
+		var ids []string
+		for _, item := range result {
+			ids = append(ids, item.ID)
+		}
+		assert.Equal(t, []string{"2", "1", "3"}, ids, mode)
+	}
+}
+
 func TestApply_SortEnding(t *testing.T) {
 	items := []api.SavingItem{
 		{ID: "late", EndFormatted: "12/31/2026"},
@@ -162,6 +257,37 @@ func TestApply_SortEnding(t *testing.T) {
 	assert.Equal(t, "unknown", result[2].ID)
 }
 
+func TestApply_SortEndingUsesResolvedDates(t *testing.T) {
+	items := []api.SavingItem{
+		// End wins over the formatted string when it is set.
+		{ID: "late", EndFormatted: "1/1", End: time.Date(2026, 10, 28, 0, 0, 0, 0, time.UTC)},
+		{ID: "soon", End: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)},
+	}
+	result := filter.Apply(items, filter.Options{Sort: "ending"})
+	assert.Equal(t, "soon", result[0].ID)
+
+	end, ok := filter.EndsAt(items[0], time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 10, 29, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestApply_ExcludeExpired(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	items := []api.SavingItem{
+		{ID: "expired", End: yesterday},
+		{ID: "current", End: time.Now().AddDate(0, 0, 3)},
+		{ID: "undated"},
+	}
+
+	result := filter.Apply(items, filter.Options{ExcludeExpired: true})
+	require.Len(t, result, 2)
+	assert.Equal(t, "current", result[0].ID)
+	assert.Equal(t, "undated", result[1].ID)
+
+	assert.Len(t, filter.Apply(items, filter.Options{}), 3)
+	assert.True(t, filter.Expired(items[0], time.Now()))
+}
+
 func TestApply_NilFields(t *testing.T) {
 	// Item 5 has nil title/department/categories — should not panic
 	result := filter.Apply(sampleItems(), filter.Options{Query: "anything"})
@@ -194,6 +320,8 @@ func TestCleanText(t *testing.T) {
 		{"Line1\r\nLine2", "Line1 Line2"},
 		{"  spaces  ", "spaces"},
 		{"Eight O&#39;Clock", "Eight O'Clock"},
+		{"<b>Fresh</b> Salmon<br/>Wild caught", "Fresh Salmon Wild caught"},
+		{"Save 2 < 3", "Save 2 < 3"},
 		{"", ""},
 	}
 	for _, tt := range tests {
@@ -201,6 +329,23 @@ func TestCleanText(t *testing.T) {
 	}
 }
 
+func TestCleanRichText(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Plain text", "Plain text"},
+		{"<b>Fresh</b>  Salmon<br>Wild caught", "Fresh Salmon\nWild caught"},
+		{"<p>One</p><p>Two &amp; three</p>", "One\n\nTwo & three"},
+		{"Includes:<ul><li>Ham</li><li>Swiss</li></ul>", "Includes:\n\n• Ham\n• Swiss"},
+		{"Line1\r\n\r\n\r\nLine2", "Line1\n\nLine2"},
+		{"<BR>", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, filter.CleanRichText(tt.input), "CleanRichText(%q)", tt.input)
+	}
+}
+
 func legacyCleanText(s string) string {
 	s = html.UnescapeString(s)
 	s = strings.ReplaceAll(s, "\r\n", " ")
@@ -244,3 +389,31 @@ func BenchmarkCleanText_Escaped_Legacy(b *testing.B) {
 		_ = legacyCleanText(input)
 	}
 }
+
+func TestEndsAt(t *testing.T) {
+	now := time.Date(2026, 12, 30, 12, 0, 0, 0, time.UTC)
+
+	end, ok := filter.EndsAt(api.SavingItem{EndFormatted: "1/5"}, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2027, 1, 6, 0, 0, 0, 0, time.UTC), end, "a yearless date resolves to the nearest year")
+
+	end, ok = filter.EndsAt(api.SavingItem{EndFormatted: "12/29/2026"}, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 12, 30, 0, 0, 0, 0, time.UTC), end)
+
+	_, ok = filter.EndsAt(api.SavingItem{EndFormatted: "soon"}, now)
+	assert.False(t, ok)
+}
+
+func TestExclusions_Apply(t *testing.T) {
+	items := sampleItems()
+	assert.Equal(t, items, filter.Exclusions{}.Apply(items))
+
+	kept := filter.Exclusions{Categories: []string{"Pet"}, Departments: []string{"produce"}}.Apply(items)
+	ids := make([]string, 0, len(kept))
+	for _, item := range kept {
+		ids = append(ids, item.ID)
+	}
+	assert.Equal(t, []string{"1", "2", "5"}, ids)
+	assert.Len(t, items, 5, "the input is not modified")
+}
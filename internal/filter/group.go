@@ -0,0 +1,64 @@
+package filter
+
+import (
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+// ValidGroupBy reports whether raw names a grouping: department (or dept),
+// category, or empty for none.
+func ValidGroupBy(raw string) bool {
+	return strings.TrimSpace(raw) == "" || normalizeGroupBy(raw) != ""
+}
+
+func normalizeGroupBy(raw string) string {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "department", "dept":
+		return "department"
+	case "category":
+		return "category"
+	default:
+		return ""
+	}
+}
+
+// Group returns the group a deal belongs to when grouping by "department"
+// or "category": its cleaned department, or its first category other than
+// bogo. It is empty for a deal without one, which callers show as "Other".
+func Group(item api.SavingItem, by string) string {
+	switch normalizeGroupBy(by) {
+	case "department":
+		return CleanText(Deref(item.Department))
+	case "category":
+		for _, c := range item.Categories {
+			if c = strings.TrimSpace(c); c != "" && !strings.EqualFold(c, "bogo") {
+				return strings.ToLower(c)
+			}
+		}
+	}
+	return ""
+}
+
+// groupItems reorders items into their groups, each group where its first
+// deal was and keeping its deals' order, and keeps at most perGroup deals
+// of each when perGroup is positive.
+func groupItems(items []api.SavingItem, by string, perGroup int) []api.SavingItem {
+	var order []string
+	groups := map[string][]api.SavingItem{}
+	for _, item := range items {
+		key := strings.ToLower(Group(item, by))
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		if perGroup > 0 && len(groups[key]) >= perGroup {
+			continue
+		}
+		groups[key] = append(groups[key], item)
+	}
+	out := items[:0]
+	for _, key := range order {
+		out = append(out, groups[key]...)
+	}
+	return out
+}
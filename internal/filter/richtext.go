@@ -0,0 +1,60 @@
+package filter
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// htmlTag matches an opening, closing, or self-closing tag such as <br>,
+// </b>, or <img src="...">, but not a bare "<" in text like "2 < 3".
+var htmlTag = regexp.MustCompile(`<\s*(/?)\s*([a-zA-Z][a-zA-Z0-9]*)\b[^<>]*>`)
+
+// htmlBreakTags are tags that end a line of text.
+var htmlBreakTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "ul": true, "ol": true,
+	"tr": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// CleanRichText converts simple HTML to plain text, keeping its line breaks:
+// <br>, paragraphs, and other block tags become newlines, list items become
+// "• " lines, and all other tags are dropped. Entities are unescaped, spaces
+// within a line are collapsed, and blank lines are squeezed to one.
+func CleanRichText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	if strings.Contains(s, "<") {
+		s = htmlTag.ReplaceAllStringFunc(s, func(tag string) string {
+			m := htmlTag.FindStringSubmatch(tag)
+			name := strings.ToLower(m[2])
+			switch {
+			case name == "li":
+				if m[1] == "" {
+					return "\n• "
+				}
+				return ""
+			case htmlBreakTags[name]:
+				return "\n"
+			default:
+				return ""
+			}
+		})
+	}
+	s = html.UnescapeString(s)
+
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
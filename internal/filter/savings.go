@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+var (
+	rePercent = regexp.MustCompile(`(\d{1,3})\s*%`)
+	// "Save $3.00", "Save Up To $3.00", "$1.50 off"
+	reSaveDollars = regexp.MustCompile(`save\s+(?:up\s+to\s+)?\$(\d+(?:\.\d{1,2})?)|\$(\d+(?:\.\d{1,2})?)\s+off`)
+	// "2/$5", "2 for $5.00"
+	reMultiPrice = regexp.MustCompile(`(\d+)\s*(?:/|for)\s*\$(\d+(?:\.\d{1,2})?)`)
+	// "$3.99", "$3.99 lb", "$3.99/lb", "$3.99 per pound"
+	rePrice = regexp.MustCompile(`\$(\d+(?:\.\d{1,2})?)(\s*(?:/\s*|per\s+)?(?:lbs?|pounds?)\b)?`)
+)
+
+// Units of a Savings.Price.
+const (
+	// UnitEach prices one item, or one of a multi-buy such as "2/$5.00".
+	UnitEach = "each"
+	// UnitPound prices a pound, e.g. "$3.99 lb".
+	UnitPound = "lb"
+)
+
+// Where a Savings.Percent comes from.
+const (
+	// PercentStated is a percentage the ad states, e.g. "25% off".
+	PercentStated = "ad"
+	// PercentBOGO is the 50% a BOGO deal saves per item when buying two.
+	PercentBOGO = "bogo"
+	// PercentEstimated is worked out from a sale price and the dollars
+	// saved, taking their sum as the regular price.
+	PercentEstimated = "estimated"
+)
+
+// Savings is the structured form of a deal's savings text. Fields the text
+// does not state are zero.
+type Savings struct {
+	// Dollars is the amount saved, e.g. 3 for "Save Up To $3.00".
+	Dollars float64
+	// Percent is the discount, e.g. 25 for "25% off". BOGO deals count as
+	// 50%, the discount per item when buying two. A deal that states a
+	// price and the dollars saved but no percentage gets an estimate,
+	// rounded to a whole percent.
+	Percent float64
+	// PercentSource says where Percent comes from: PercentStated,
+	// PercentBOGO, or PercentEstimated. It is empty when Percent is zero.
+	PercentSource string
+	// Price is the sale price of one item, e.g. 2.50 for "2/$5.00", or of
+	// one pound when Unit is UnitPound.
+	Price float64
+	// Unit is what Price buys: UnitEach or UnitPound. It is empty when
+	// Price is zero.
+	Unit string
+	BOGO bool
+}
+
+// EffectivePrice is what one item costs on the deal: the sale price, or for
+// a BOGO deal half of it, since the second item is free. A BOGO deal that
+// states only its savings is priced from those, as the free item's value is
+// the regular price. It is zero when the text states neither.
+func (s Savings) EffectivePrice() float64 {
+	if !s.BOGO {
+		return s.Price
+	}
+	if s.Price > 0 {
+		return s.Price / 2
+	}
+	return s.Dollars / 2
+}
+
+// ParseSavings reads the savings and additional deal info texts of a deal.
+func ParseSavings(item api.SavingItem) Savings {
+	var s Savings
+	s.BOGO = ContainsIgnoreCase(item.Categories, "bogo")
+
+	for _, raw := range []string{Deref(item.Savings), Deref(item.AdditionalDealInfo)} {
+		text := strings.ToLower(CleanText(raw))
+		if text == "" {
+			continue
+		}
+		if strings.Contains(text, "buy 1 get 1") || strings.Contains(text, "bogo") {
+			s.BOGO = true
+		}
+		for _, m := range reSaveDollars.FindAllStringSubmatch(text, -1) {
+			amount := m[1]
+			if amount == "" {
+				amount = m[2]
+			}
+			if v, err := strconv.ParseFloat(amount, 64); err == nil && v > s.Dollars {
+				s.Dollars = v
+			}
+		}
+		for _, v := range percentages(text) {
+			if v > s.Percent && v <= 100 {
+				s.Percent = v
+				s.PercentSource = PercentStated
+			}
+		}
+		if s.Price == 0 {
+			s.Price, s.Unit = parsePrice(text)
+		}
+	}
+
+	if s.BOGO && s.Percent < 50 {
+		s.Percent = 50
+		s.PercentSource = PercentBOGO
+	}
+	if s.Percent == 0 && s.Dollars > 0 && s.Price > 0 {
+		s.Percent = math.Round(s.Dollars / (s.Price + s.Dollars) * 100)
+		s.PercentSource = PercentEstimated
+	}
+	if s.BOGO && s.Dollars == 0 && s.Price > 0 {
+		// The free item is worth the price of the one bought.
+		s.Dollars = s.Price
+	}
+	return s
+}
+
+// parsePrice finds a sale price that is not a savings amount and its unit,
+// e.g. 3.99 and UnitPound in "$3.99 lb, save up to $1.00 lb".
+func parsePrice(text string) (float64, string) {
+	if m := reMultiPrice.FindStringSubmatch(text); m != nil {
+		count, _ := strconv.ParseFloat(m[1], 64)
+		total, _ := strconv.ParseFloat(m[2], 64)
+		if count > 0 {
+			return total / count, UnitEach
+		}
+	}
+	text = reSaveDollars.ReplaceAllString(text, "")
+	if m := rePrice.FindStringSubmatch(text); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		if v == 0 {
+			return 0, ""
+		}
+		if m[2] != "" {
+			return v, UnitPound
+		}
+		return v, UnitEach
+	}
+	return 0, ""
+}
+
+// percentages returns every percentage in text, e.g. 25 in "25% off".
+func percentages(text string) []float64 {
+	var pcts []float64
+	for _, m := range rePercent.FindAllStringSubmatch(text, -1) {
+		if v, err := strconv.ParseFloat(m[1], 64); err == nil {
+			pcts = append(pcts, v)
+		}
+	}
+	return pcts
+}
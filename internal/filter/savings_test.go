@@ -0,0 +1,53 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+func TestParseSavings(t *testing.T) {
+	tests := []struct {
+		name string
+		item api.SavingItem
+		want filter.Savings
+	}{
+		{"save up to", api.SavingItem{Savings: ptr("Save Up To $3.00")}, filter.Savings{Dollars: 3}},
+		{"dollars off", api.SavingItem{Savings: ptr("$1.50 off")}, filter.Savings{Dollars: 1.5}},
+		{"percent", api.SavingItem{Savings: ptr("25% off")}, filter.Savings{Percent: 25, PercentSource: filter.PercentStated}},
+		{"multi price", api.SavingItem{Savings: ptr("2/$5.00")}, filter.Savings{Price: 2.5, Unit: filter.UnitEach}},
+		{"price", api.SavingItem{Savings: ptr("$2.99")}, filter.Savings{Price: 2.99, Unit: filter.UnitEach}},
+		{"price per pound", api.SavingItem{Savings: ptr("$2.99 lb")}, filter.Savings{Price: 2.99, Unit: filter.UnitPound}},
+		{"price slash pound", api.SavingItem{Savings: ptr("$5.49/lb")}, filter.Savings{Price: 5.49, Unit: filter.UnitPound}},
+		{"price per pound spelled out", api.SavingItem{Savings: ptr("$1.99 per pound")}, filter.Savings{Price: 1.99, Unit: filter.UnitPound}},
+		{"bogo with info", api.SavingItem{
+			Savings:            ptr("Buy 1 Get 1 FREE"),
+			AdditionalDealInfo: ptr("Save Up To $4.29"),
+		}, filter.Savings{Dollars: 4.29, Percent: 50, PercentSource: filter.PercentBOGO, BOGO: true}},
+		{"bogo category with price", api.SavingItem{
+			Savings:    ptr("$3.49"),
+			Categories: []string{"bogo"},
+		}, filter.Savings{Dollars: 3.49, Percent: 50, PercentSource: filter.PercentBOGO, Price: 3.49, Unit: filter.UnitEach, BOGO: true}},
+		{"estimated", api.SavingItem{
+			Savings:            ptr("$3.99 lb"),
+			AdditionalDealInfo: ptr("Save Up To $1.00 lb"),
+		}, filter.Savings{Dollars: 1, Percent: 20, PercentSource: filter.PercentEstimated, Price: 3.99, Unit: filter.UnitPound}},
+		{"price and savings in one text", api.SavingItem{Savings: ptr("$6.00, Save $2.00")},
+			filter.Savings{Dollars: 2, Percent: 25, PercentSource: filter.PercentEstimated, Price: 6, Unit: filter.UnitEach}},
+		{"nothing", api.SavingItem{Savings: ptr("Great deal")}, filter.Savings{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, filter.ParseSavings(tt.item))
+		})
+	}
+}
+
+func TestSavings_EffectivePrice(t *testing.T) {
+	assert.Equal(t, 2.5, filter.Savings{Price: 2.5}.EffectivePrice())
+	assert.Equal(t, 1.75, filter.Savings{Price: 3.5, BOGO: true}.EffectivePrice())
+	assert.Equal(t, 2.0, filter.Savings{Dollars: 4, BOGO: true}.EffectivePrice())
+	assert.Zero(t, filter.Savings{BOGO: true}.EffectivePrice())
+}
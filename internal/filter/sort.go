@@ -1,45 +1,26 @@
 package filter
 
 import (
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/tayloree/publix-deals/internal/api"
 )
 
-var (
-	reDollar  = regexp.MustCompile(`\$(\d+(?:\.\d{1,2})?)`)
-	rePercent = regexp.MustCompile(`(\d{1,3})\s*%`)
-)
-
-// DealScore estimates relative deal value for ranking.
+// DealScore estimates relative deal value for ranking from the deal's
+// ParseSavings: 8 for a BOGO deal, plus the dollars saved and a twentieth
+// of any other percent off, or 0.01 when the text states neither. Sale
+// prices are not savings, so "$12.99 lb" alone does not outrank "$1.00 off".
+// It is the score --sort savings orders by and the score field of a deal in
+// JSON.
 func DealScore(item api.SavingItem) float64 {
-	score := 0.0
-
-	if ContainsIgnoreCase(item.Categories, "bogo") {
+	s := ParseSavings(item)
+	score := s.Dollars
+	if s.BOGO {
 		score += 8
 	}
-
-	text := strings.ToLower(
-		CleanText(Deref(item.Savings) + " " + Deref(item.AdditionalDealInfo)),
-	)
-	for _, m := range reDollar.FindAllStringSubmatch(text, -1) {
-		if len(m) < 2 {
-			continue
-		}
-		if amount, err := strconv.ParseFloat(m[1], 64); err == nil {
-			score += amount
-		}
-	}
-	for _, m := range rePercent.FindAllStringSubmatch(text, -1) {
-		if len(m) < 2 {
-			continue
-		}
-		if pct, err := strconv.ParseFloat(m[1], 64); err == nil {
-			score += pct / 20.0
-		}
+	if s.PercentSource != PercentBOGO {
+		score += s.Percent / 20.0
 	}
 
 	if score == 0 {
@@ -48,6 +29,17 @@ func DealScore(item api.SavingItem) float64 {
 	return score
 }
 
+// ValidSort reports whether raw names a sort mode: relevance (or empty),
+// savings, or ending and its aliases.
+func ValidSort(raw string) bool {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "relevance", "savings", "ending", "end", "expiry", "expiration":
+		return true
+	default:
+		return false
+	}
+}
+
 func normalizeSortMode(raw string) string {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "", "relevance":
@@ -61,25 +53,29 @@ func normalizeSortMode(raw string) string {
 	}
 }
 
-func parseDealDate(raw string) (time.Time, bool) {
-	value := strings.TrimSpace(raw)
-	if value == "" {
+// EndsAt returns when a deal expires: midnight after its end date, in now's
+// location. Deals decoded without a resolved End are parsed from
+// EndFormatted, with yearless dates ("10/21") taking the year closest to now.
+func EndsAt(item api.SavingItem, now time.Time) (time.Time, bool) {
+	day, ok := EndDay(item, now)
+	if !ok {
 		return time.Time{}, false
 	}
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, now.Location()), true
+}
 
-	layouts := []string{
-		"1/2/2006",
-		"01/02/2006",
-		"1/2/06",
-		"01/02/06",
-		"2006-01-02",
-		"Jan 2, 2006",
-		"January 2, 2006",
-	}
-	for _, layout := range layouts {
-		if t, err := time.Parse(layout, value); err == nil {
-			return t, true
-		}
+// Expired reports whether a deal's end date has passed. Deals without a
+// readable end date never expire.
+func Expired(item api.SavingItem, now time.Time) bool {
+	end, ok := EndsAt(item, now)
+	return ok && !end.After(now)
+}
+
+// EndDay returns the last day a deal is valid: its End, or EndFormatted
+// resolved against now for items that were not decoded by the API client.
+func EndDay(item api.SavingItem, now time.Time) (time.Time, bool) {
+	if !item.End.IsZero() {
+		return item.End, true
 	}
-	return time.Time{}, false
+	return api.ParseDealDate(item.EndFormatted, now)
 }
@@ -0,0 +1,335 @@
+// Package fixtures generates realistic, randomized Publix API responses for
+// tests and benchmarks. The same seed and week always produce the same
+// responses.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+)
+
+// File names written by `pubcli fixtures generate`.
+const (
+	SavingsFile = "savings.json"
+	StoresFile  = "stores.json"
+)
+
+// MaxStores is the most stores Generate can produce with distinct numbers.
+const MaxStores = 1000
+
+// Options controls what Generate produces.
+type Options struct {
+	Deals int
+	// Stores is capped at MaxStores.
+	Stores int
+	Seed   uint64
+	// WeekStart is the first day of the ad week the deals are valid for.
+	WeekStart time.Time
+}
+
+type department struct {
+	name       string
+	categories []string
+	brands     []string
+	products   []string
+	sizes      []string
+}
+
+var departments = []department{
+	{
+		name:       "Grocery",
+		categories: []string{"grocery"},
+		brands:     []string{"Publix", "Barilla", "Campbell's", "Kellogg's", "General Mills", "Heinz", "Del Monte", "Progresso"},
+		products:   []string{"Pasta", "Pasta Sauce", "Cereal", "Canned Soup", "Peanut Butter", "Ketchup", "Green Beans", "Rice", "Olive Oil", "Coffee"},
+		sizes:      []string{"16 oz", "24 oz", "12 oz", "18.6 oz", "32 oz", "11.5 oz"},
+	},
+	{
+		name:       "Produce",
+		categories: []string{"produce"},
+		brands:     []string{"Publix", "GreenWise", "Dole", "Driscoll's", "Fresh Express"},
+		products:   []string{"Strawberries", "Blueberries", "Avocados", "Bananas", "Gala Apples", "Baby Spinach", "Sweet Corn", "Red Grapes"},
+		sizes:      []string{"1 lb", "16 oz", "5 oz", "per lb", "3 lb bag"},
+	},
+	{
+		name:       "Meat",
+		categories: []string{"meat", "meat & seafood"},
+		brands:     []string{"Publix", "GreenWise", "Boar's Head", "Oscar Mayer", "Perdue"},
+		products:   []string{"Boneless Chicken Breasts", "Ground Chuck", "Pork Tenderloin", "Ribeye Steak", "Bacon", "Italian Sausage", "Deli Ham"},
+		sizes:      []string{"per lb", "16 oz", "12 oz", "family pack"},
+	},
+	{
+		name:       "Seafood",
+		categories: []string{"seafood", "meat & seafood"},
+		brands:     []string{"Publix", "GreenWise", "Gorton's"},
+		products:   []string{"Atlantic Salmon Fillet", "Wild Caught Shrimp", "Tilapia Fillets", "Snow Crab Clusters"},
+		sizes:      []string{"per lb", "2 lb bag", "12 oz"},
+	},
+	{
+		name:       "Dairy",
+		categories: []string{"dairy"},
+		brands:     []string{"Publix", "Chobani", "Tillamook", "Kraft", "Land O Lakes"},
+		products:   []string{"Greek Yogurt", "Shredded Cheese", "Butter", "Milk", "Cream Cheese", "Sour Cream"},
+		sizes:      []string{"5.3 oz", "8 oz", "16 oz", "half gallon", "1 gal"},
+	},
+	{
+		name:       "Frozen",
+		categories: []string{"frozen"},
+		brands:     []string{"Publix", "Ben & Jerry's", "DiGiorno", "Stouffer's", "Birds Eye"},
+		products:   []string{"Ice Cream", "Pizza", "Lasagna", "Steamfresh Vegetables", "Waffles"},
+		sizes:      []string{"16 oz", "48 oz", "10.8 oz", "27 oz"},
+	},
+	{
+		name:       "Bakery",
+		categories: []string{"bakery"},
+		brands:     []string{"Publix", "Pepperidge Farm", "Sara Lee"},
+		products:   []string{"Italian Bread", "Chocolate Chip Cookies", "Bagels", "Key Lime Pie", "Sandwich Bread"},
+		sizes:      []string{"16 oz", "20 oz", "6 ct", "each"},
+	},
+	{
+		name:       "Snacks",
+		categories: []string{"snacks", "grocery"},
+		brands:     []string{"Lay's", "Doritos", "Goldfish", "Ritz", "Oreo", "Publix"},
+		products:   []string{"Potato Chips", "Tortilla Chips", "Crackers", "Cookies", "Pretzels"},
+		sizes:      []string{"8 oz", "9.25 oz", "13 oz", "6.6 oz"},
+	},
+	{
+		name:       "Beverages",
+		categories: []string{"beverages"},
+		brands:     []string{"Coca-Cola", "Pepsi", "Tropicana", "Publix", "LaCroix", "Gatorade"},
+		products:   []string{"Soda", "Orange Juice", "Sparkling Water", "Sports Drink", "Iced Tea"},
+		sizes:      []string{"12 pk", "52 oz", "2 liter", "8 pk"},
+	},
+	{
+		name:       "Pet",
+		categories: []string{"pet"},
+		brands:     []string{"Purina", "Blue Buffalo", "Fancy Feast", "Milk-Bone"},
+		products:   []string{"Dry Dog Food", "Cat Food", "Dog Treats", "Cat Litter"},
+		sizes:      []string{"3 oz", "16 lb", "24 oz", "14 lb"},
+	},
+	{
+		name:       "Health & Beauty",
+		categories: []string{"health & beauty"},
+		brands:     []string{"Crest", "Dove", "Tylenol", "Neutrogena", "Publix"},
+		products:   []string{"Toothpaste", "Body Wash", "Pain Reliever", "Face Wash", "Shampoo"},
+		sizes:      []string{"4.1 oz", "22 oz", "100 ct", "12.7 oz"},
+	},
+	{
+		name:       "Household",
+		categories: []string{"household"},
+		brands:     []string{"Tide", "Bounty", "Charmin", "Dawn", "Publix"},
+		products:   []string{"Laundry Detergent", "Paper Towels", "Bath Tissue", "Dish Soap", "Trash Bags"},
+		sizes:      []string{"92 oz", "6 rolls", "12 mega rolls", "19.4 oz", "45 ct"},
+	},
+}
+
+var places = []struct {
+	city, state, zip, area string
+	names                  []string
+}{
+	{"Lakeland", "FL", "33801", "863", []string{"Lake Miriam Square", "Merchants Walk", "Grove Park"}},
+	{"Tampa", "FL", "33606", "813", []string{"Hyde Park Village", "Westshore", "Carrollwood"}},
+	{"Orlando", "FL", "32801", "407", []string{"Lake Nona", "College Park", "Dr. Phillips"}},
+	{"Miami", "FL", "33133", "305", []string{"Coconut Grove", "Brickell", "Kendall Village"}},
+	{"Jacksonville", "FL", "32207", "904", []string{"San Marco", "Mandarin Landing", "Baymeadows"}},
+	{"Atlanta", "GA", "30309", "404", []string{"Midtown Promenade", "Buckhead Landing", "Edgewood"}},
+	{"Savannah", "GA", "31405", "912", []string{"Abercorn Walk", "Twelve Oaks"}},
+	{"Birmingham", "AL", "35209", "205", []string{"Homewood", "Cahaba Heights"}},
+	{"Charleston", "SC", "29407", "843", []string{"West Ashley", "Daniel Island"}},
+	{"Charlotte", "NC", "28203", "704", []string{"South End", "Ballantyne Village"}},
+	{"Clarksville", "TN", "37042", "931", []string{"Peachers Mill", "Sango"}},
+	{"Richmond", "VA", "23226", "804", []string{"Libbie Place", "Short Pump"}},
+}
+
+var streets = []string{"Main St", "Harden Blvd", "Dale Mabry Hwy", "Peachtree Rd", "Kings Hwy", "Oak Ave", "Gulf Blvd", "Park Rd", "Ridge Rd", "Tiny Town Rd"}
+
+var storeHours = []string{"7:00 AM - 10:00 PM", "7:00 AM - 10:00 PM", "7:00 AM - 9:00 PM", "6:00 AM - 11:00 PM", "Open 24 Hours"}
+
+// Generate returns a savings response with opts.Deals deals and a store
+// locator response with opts.Stores stores.
+func Generate(opts Options) (api.SavingsResponse, api.StoreResponse) {
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15))
+	week := time.Date(opts.WeekStart.Year(), opts.WeekStart.Month(), opts.WeekStart.Day(), 0, 0, 0, 0, time.UTC)
+
+	savings := api.SavingsResponse{
+		Savings:                       make([]api.SavingItem, 0, opts.Deals),
+		WeeklyAdLatestUpdatedDateTime: week.Add(6 * time.Hour).Format("2006-01-02T15:04:05"),
+		LanguageID:                    1,
+	}
+	for i := range opts.Deals {
+		savings.Savings = append(savings.Savings, deal(rng, i, week))
+	}
+
+	count := min(opts.Stores, MaxStores)
+	stores := api.StoreResponse{Stores: make([]api.Store, 0, count)}
+	keys := map[int]bool{}
+	for range count {
+		stores.Stores = append(stores.Stores, store(rng, keys))
+	}
+	sort.SliceStable(stores.Stores, func(a, b int) bool {
+		return distance(stores.Stores[a]) < distance(stores.Stores[b])
+	})
+	return savings, stores
+}
+
+func deal(rng *rand.Rand, i int, week time.Time) api.SavingItem {
+	dept := departments[rng.IntN(len(departments))]
+	brand := pick(rng, dept.brands)
+	product := pick(rng, dept.products)
+	size := pick(rng, dept.sizes)
+
+	title := brand + " " + product
+	if brand == "Publix" || brand == "GreenWise" {
+		title = product
+	}
+	title += ", " + size
+
+	price := float64(rng.IntN(1200)+99) / 100
+	categories := append([]string(nil), dept.categories...)
+	item := api.SavingItem{
+		ID:          fmt.Sprintf("fx-%06d", i+1),
+		Title:       ptr(title),
+		Description: ptr(description(rng, product)),
+		Department:  ptr(dept.name),
+		Brand:       ptr(strings.ToLower(brand)),
+		ImageURL:    ptr(fmt.Sprintf("https://images.example.com/publix/%s.jpg", slug(product))),
+	}
+
+	switch n := rng.IntN(100); {
+	case n < 30:
+		categories = append(categories, "bogo")
+		item.Savings = ptr("Buy 1 Get 1 FREE")
+		item.AdditionalDealInfo = ptr(fmt.Sprintf("Save Up To $%.2f", price))
+	case n < 50:
+		count := 2 + rng.IntN(3)
+		item.Savings = ptr(fmt.Sprintf("%d/$%d.00", count, int(price*float64(count)*0.8)+1))
+	case n < 70:
+		item.Savings = ptr(fmt.Sprintf("Save $%.2f", float64(rng.IntN(8)+1)*0.5))
+	case n < 80:
+		item.Savings = ptr(fmt.Sprintf("%d%% off", 10+5*rng.IntN(7)))
+	case n < 92 && strings.Contains(size, "lb"):
+		item.Savings = ptr(fmt.Sprintf("$%.2f lb", price))
+		item.AdditionalDealInfo = ptr(fmt.Sprintf("SAVE UP TO $%.2f LB", float64(rng.IntN(4)+1)))
+	default:
+		item.Savings = ptr(fmt.Sprintf("$%.2f", price))
+	}
+	item.Categories = categories
+
+	start, end := week, week.AddDate(0, 0, 6)
+	switch rng.IntN(10) {
+	case 0:
+		// Extra Savings running into next week's ad.
+		end = end.AddDate(0, 0, 7)
+	case 1:
+		// Weekend-only deals.
+		start = week.AddDate(0, 0, 3)
+		end = week.AddDate(0, 0, 5)
+	}
+	item.StartFormatted = start.Format("1/2")
+	item.EndFormatted = end.Format("1/2")
+
+	// The live API leaves some fields null.
+	if rng.IntN(20) == 0 {
+		item.Brand = nil
+	}
+	if rng.IntN(25) == 0 {
+		item.ImageURL = nil
+	}
+	return item
+}
+
+var descriptions = []string{
+	"Selected varieties.",
+	"Assorted varieties.",
+	"Limit 4 per customer.",
+	"Selected varieties. Quantity rights reserved.",
+	"Fresh from our stores.",
+}
+
+func description(rng *rand.Rand, product string) string {
+	d := pick(rng, descriptions)
+	if rng.IntN(3) == 0 {
+		d = "Great for weeknight meals. " + d
+	}
+	if rng.IntN(4) == 0 {
+		d = product + ". " + d
+	}
+	return d
+}
+
+func store(rng *rand.Rand, keys map[int]bool) api.Store {
+	place := places[rng.IntN(len(places))]
+	key := 100 + rng.IntN(1900)
+	for keys[key] {
+		key = 100 + rng.IntN(1900)
+	}
+	keys[key] = true
+
+	s := api.Store{
+		Key:      fmt.Sprintf("%05d", key),
+		Name:     pick(rng, place.names),
+		Addr:     fmt.Sprintf("%d %s", 100+rng.IntN(9900), pick(rng, streets)),
+		City:     place.city,
+		State:    place.state,
+		Zip:      place.zip,
+		Distance: fmt.Sprintf("%.1f", float64(rng.IntN(300))/10),
+		Phone:    fmt.Sprintf("(%s) 555-%04d", place.area, rng.IntN(10000)),
+		Hours:    pick(rng, storeHours),
+	}
+	if rng.IntN(50) == 0 {
+		s.Status = "Temporarily Closed"
+	}
+	return s
+}
+
+func distance(s api.Store) float64 {
+	var d float64
+	fmt.Sscanf(s.Distance, "%g", &d)
+	return d
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.IntN(len(values))]
+}
+
+func slug(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), " ", "-")
+}
+
+func ptr(s string) *string { return &s }
+
+// Write generates fixtures and writes them to dir as SavingsFile and
+// StoresFile, returning the paths written.
+func Write(dir string, opts Options) ([]string, error) {
+	savings, stores := Generate(opts)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating fixtures directory: %w", err)
+	}
+	files := []struct {
+		name string
+		v    any
+	}{
+		{SavingsFile, savings},
+		{StoresFile, stores},
+	}
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		data, err := json.MarshalIndent(f.v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", f.name, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,102 @@
+package fixtures_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/fixtures"
+)
+
+var week = time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+
+func TestGenerate_SameSeedSameOutput(t *testing.T) {
+	opts := fixtures.Options{Deals: 200, Stores: 20, Seed: 42, WeekStart: week}
+	a1, s1 := fixtures.Generate(opts)
+	a2, s2 := fixtures.Generate(opts)
+	assert.Equal(t, a1, a2)
+	assert.Equal(t, s1, s2)
+
+	opts.Seed = 43
+	a3, _ := fixtures.Generate(opts)
+	assert.NotEqual(t, a1, a3)
+}
+
+func TestGenerate_RealisticDeals(t *testing.T) {
+	savings, stores := fixtures.Generate(fixtures.Options{Deals: 1000, Stores: 50, Seed: 1, WeekStart: week})
+	require.Len(t, savings.Savings, 1000)
+	require.Len(t, stores.Stores, 50)
+	assert.Equal(t, "2026-10-14T06:00:00", savings.WeeklyAdLatestUpdatedDateTime)
+
+	ids := map[string]bool{}
+	bogos := 0
+	for _, item := range savings.Savings {
+		assert.False(t, ids[item.ID], "duplicate id %s", item.ID)
+		ids[item.ID] = true
+		assert.NotEmpty(t, filter.Deref(item.Title))
+		assert.NotEmpty(t, filter.Deref(item.Department))
+		assert.NotEmpty(t, item.Categories)
+
+		start, ok := api.ParseDealDate(item.StartFormatted, week)
+		require.True(t, ok, item.StartFormatted)
+		end, ok := api.ParseDealDate(item.EndFormatted, week)
+		require.True(t, ok, item.EndFormatted)
+		assert.False(t, end.Before(start))
+
+		s := filter.ParseSavings(item)
+		if s.BOGO {
+			bogos++
+		} else {
+			assert.True(t, s.Dollars > 0 || s.Percent > 0 || s.Price > 0, "unparsed savings %q", filter.Deref(item.Savings))
+		}
+	}
+	assert.Greater(t, bogos, 200)
+
+	keys := map[string]bool{}
+	for i, s := range stores.Stores {
+		assert.False(t, keys[s.Key], "duplicate store %s", s.Key)
+		keys[s.Key] = true
+		assert.Len(t, s.Key, 5)
+		if i > 0 {
+			assert.LessOrEqual(t, miles(t, stores.Stores[i-1]), miles(t, s), "stores sorted by distance")
+		}
+	}
+}
+
+func miles(t *testing.T, s api.Store) float64 {
+	t.Helper()
+	d, err := strconv.ParseFloat(s.Distance, 64)
+	require.NoError(t, err)
+	return d
+}
+
+func TestGenerate_CapsStores(t *testing.T) {
+	_, stores := fixtures.Generate(fixtures.Options{Stores: fixtures.MaxStores + 10, WeekStart: week})
+	assert.Len(t, stores.Stores, fixtures.MaxStores)
+}
+
+func TestWrite_DecodesAsAPIResponses(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "fx")
+	paths, err := fixtures.Write(dir, fixtures.Options{Deals: 10, Stores: 2, Seed: 7, WeekStart: week})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, fixtures.SavingsFile), filepath.Join(dir, fixtures.StoresFile)}, paths)
+
+	var savings api.SavingsResponse
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &savings))
+	assert.Len(t, savings.Savings, 10)
+
+	var stores api.StoreResponse
+	data, err = os.ReadFile(paths[1])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &stores))
+	assert.Len(t, stores.Stores, 2)
+}
@@ -0,0 +1,185 @@
+// Package history keeps a snapshot of each store's weekly ad on disk so
+// reports can compare one ad cycle with the ones before it.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// DirName is the archive's directory name inside the data directory, and
+// the prefix of its storage keys.
+const DirName = "history"
+
+const fileDateLayout = "2006-01-02"
+
+// Snapshot is one store's weekly ad as it was when saved.
+type Snapshot struct {
+	Store string `json:"store"`
+	// Updated is the ad's WeeklyAdLatestUpdatedDateTime; it identifies the
+	// ad version.
+	Updated string           `json:"updated,omitempty"`
+	SavedAt time.Time        `json:"savedAt"`
+	Deals   []api.SavingItem `json:"deals"`
+	// Hash is the snapshot's Digest, set by Save and checked on read.
+	Hash string `json:"hash,omitempty"`
+}
+
+// Archive stores snapshots under history/<store>/<date>_<version>.json in a
+// storage backend, one per ad version.
+type Archive struct {
+	Backend storage.Backend
+	// KeepWeeks, when positive, makes Save remove the store's snapshots from
+	// before the last KeepWeeks ad weeks; see Cutoff.
+	KeepWeeks int
+	// Index, when set, is told about every snapshot the archive saves or
+	// removes.
+	Index Indexer
+}
+
+// Indexer follows the snapshots in an archive, such as a search index. It
+// only speeds up reads, so the archive ignores its errors; an index that
+// misses a change can be rebuilt from the archive.
+type Indexer interface {
+	Add(key string, s Snapshot) error
+	Remove(keys ...string) error
+}
+
+// Save records a snapshot. When the newest stored snapshot is the same ad
+// version, it is replaced, so rerunning within a cycle keeps one entry.
+// Corrupt snapshots of the store are removed first; the fresh ad takes
+// their place.
+func (a Archive) Save(s Snapshot) error {
+	if strings.TrimSpace(s.Store) == "" || strings.ContainsAny(s.Store, `/\`) {
+		return fmt.Errorf("invalid store number %q", s.Store)
+	}
+
+	existing, err := a.List(s.Store)
+	if errors.Is(err, ErrCorrupt) {
+		if _, err := a.verify(DirName+"/"+s.Store+"/", true); err != nil {
+			return err
+		}
+		existing, err = a.List(s.Store)
+	}
+	if err != nil {
+		return err
+	}
+	target := a.key(s)
+	if n := len(existing); n > 0 && s.Updated != "" && existing[n-1].Updated == s.Updated {
+		if old := a.key(existing[n-1]); old != target {
+			if err := a.Backend.Delete(old); err != nil && !errors.Is(err, storage.ErrNotExist) {
+				return fmt.Errorf("replacing snapshot: %w", err)
+			}
+			a.unindex(old)
+		}
+	}
+
+	s.Hash = s.Digest()
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := a.Backend.Put(target, storage.Object{Data: data}); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if a.Index != nil {
+		_ = a.Index.Add(target, s)
+	}
+	if a.KeepWeeks > 0 {
+		if _, err := a.prune(DirName+"/"+s.Store+"/", Cutoff(s.SavedAt, a.KeepWeeks), false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns a store's snapshots, oldest first. A store without history
+// has none. A snapshot that fails its integrity check is an ErrCorrupt
+// error rather than a wrong answer.
+func (a Archive) List(store string) ([]Snapshot, error) {
+	keys, err := a.Backend.Keys(DirName + "/" + store + "/")
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, key := range keys {
+		if strings.Contains(strings.TrimPrefix(key, DirName+"/"+store+"/"), "/") || path.Ext(key) != ".json" {
+			continue
+		}
+		obj, err := a.Backend.Get(key)
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot: %w", err)
+		}
+		s, err := decodeSnapshot(key, obj.Data)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].SavedAt.Before(snapshots[j].SavedAt) })
+	return snapshots, nil
+}
+
+// Load returns the snapshot stored under key, checked against its hash.
+func (a Archive) Load(key string) (Snapshot, error) {
+	obj, err := a.Backend.Get(key)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("reading snapshot: %w", err)
+	}
+	return decodeSnapshot(key, obj.Data)
+}
+
+// SnapshotKeys returns the storage keys of every store's snapshots, sorted.
+func (a Archive) SnapshotKeys() ([]string, error) {
+	keys, err := a.Backend.Keys(DirName + "/")
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	var out []string
+	for _, key := range keys {
+		if _, _, ok := snapshotKey(key); ok {
+			out = append(out, key)
+		}
+	}
+	return out, nil
+}
+
+// unindex tells the index that snapshots were removed.
+func (a Archive) unindex(keys ...string) {
+	if a.Index != nil && len(keys) > 0 {
+		_ = a.Index.Remove(keys...)
+	}
+}
+
+// key is history/<store>/<date>_<version>.json, where version is the ad's
+// Updated time with only its letters and digits kept, so an ad updated
+// mid-week does not replace the version saved earlier that day. Snapshots
+// without an Updated time are history/<store>/<date>.json.
+func (a Archive) key(s Snapshot) string {
+	name := s.SavedAt.Format(fileDateLayout)
+	version := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return -1
+	}, s.Updated)
+	if version != "" {
+		name += "_" + version
+	}
+	return DirName + "/" + s.Store + "/" + name + ".json"
+}
+
+// WeekStart returns midnight on the Wednesday that starts the ad week
+// containing t; see api.AdWeekStart.
+func WeekStart(t time.Time) time.Time {
+	return api.AdWeekStart(t)
+}
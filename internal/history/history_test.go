@@ -0,0 +1,201 @@
+package history_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func ptr(s string) *string { return &s }
+
+func TestArchive_OneSnapshotPerAdVersion(t *testing.T) {
+	dir := t.TempDir()
+	archive := history.Archive{Backend: &storage.Files{Dir: dir}}
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC) }
+
+	list, err := archive.List("1425")
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: day(8), Deals: []api.SavingItem{{ID: "1"}}}))
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "b", SavedAt: day(15), Deals: []api.SavingItem{{ID: "2"}}}))
+	// Rerunning in the same cycle replaces that cycle's snapshot.
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "b", SavedAt: day(16), Deals: []api.SavingItem{{ID: "3"}}}))
+
+	list, err = archive.List("1425")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "a", list[0].Updated)
+	assert.Equal(t, "3", list[1].Deals[0].ID)
+	assert.NoFileExists(t, filepath.Join(dir, history.DirName, "1425", "2026-10-15.json"))
+
+	assert.Error(t, archive.Save(history.Snapshot{Store: "../x", SavedAt: day(1)}))
+}
+
+func TestArchive_MidWeekUpdateKeepsBothVersions(t *testing.T) {
+	archive := history.Archive{Backend: &storage.Files{Dir: t.TempDir()}}
+	morning := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "2026-10-14T08:00:00", SavedAt: morning, Deals: []api.SavingItem{{ID: "1"}}}))
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "2026-10-15T12:30:00", SavedAt: morning.Add(5 * time.Hour), Deals: []api.SavingItem{{ID: "2"}}}))
+
+	list, err := archive.List("1425")
+	require.NoError(t, err)
+	require.Len(t, list, 2, "the same day's earlier version is kept")
+	assert.Equal(t, "1", list[0].Deals[0].ID)
+	assert.Equal(t, "2", list[1].Deals[0].ID)
+
+	keys, err := archive.SnapshotKeys()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"history/1425/2026-10-15_20261014T080000.json", "history/1425/2026-10-15_20261015T123000.json"}, keys)
+}
+
+func TestArchive_ListReportsCorruptSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	archive := history.Archive{Backend: &storage.Files{Dir: dir}}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, history.DirName, "1425"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, history.DirName, "1425", "2026-10-15.json"), []byte("{"), 0o644))
+
+	_, err := archive.List("1425")
+	assert.ErrorContains(t, err, "parsing snapshot")
+}
+
+func TestArchive_VerifyDetectsTampering(t *testing.T) {
+	dir := t.TempDir()
+	archive := history.Archive{Backend: &storage.Files{Dir: dir}}
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC) }
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: day(8), Deals: []api.SavingItem{{ID: "1", Title: ptr("Nutella")}}}))
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "b", SavedAt: day(15), Deals: []api.SavingItem{{ID: "2"}}}))
+
+	list, err := archive.List("1425")
+	require.NoError(t, err)
+	assert.Equal(t, list[0].Digest(), list[0].Hash)
+
+	path := filepath.Join(dir, history.DirName, "1425", "2026-10-08_a.json")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, bytes.Replace(data, []byte("Nutella"), []byte("Nutello"), 1), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, history.DirName, "1425", "2026-10-01.json"), []byte(`{"store":"1425","savedAt":"2026-10-01T09:00:00Z"}`), 0o644))
+
+	_, err = archive.List("1425")
+	assert.ErrorIs(t, err, history.ErrCorrupt)
+
+	checks, err := archive.Verify(false)
+	require.NoError(t, err)
+	require.Len(t, checks, 3)
+	assert.Equal(t, history.CheckUnhashed, checks[0].Status)
+	assert.Equal(t, history.CheckCorrupt, checks[1].Status)
+	assert.Equal(t, history.CheckOK, checks[2].Status)
+	assert.FileExists(t, path)
+
+	// Saving a fresh snapshot replaces the corrupt one.
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "c", SavedAt: day(22)}))
+	assert.NoFileExists(t, path)
+	list, err = archive.List("1425")
+	require.NoError(t, err)
+	assert.Len(t, list, 3)
+}
+
+func TestWeekStart(t *testing.T) {
+	wed := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, wed, history.WeekStart(time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)))
+	assert.Equal(t, wed, history.WeekStart(time.Date(2026, 10, 20, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, wed.AddDate(0, 0, 7), history.WeekStart(time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestArchive_Prune(t *testing.T) {
+	archive := history.Archive{Backend: &storage.Files{Dir: t.TempDir()}}
+	for i, d := range []int{1, 8, 15} {
+		require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: string(rune('a' + i)), SavedAt: time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC)}))
+	}
+	require.NoError(t, archive.Save(history.Snapshot{Store: "1500", SavedAt: time.Date(2026, 9, 30, 9, 0, 0, 0, time.UTC)}))
+	require.NoError(t, archive.AddTrip(history.Trip{List: "default", Week: "2026-01-07"}))
+
+	usage, err := archive.Usage()
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage.Stores)
+	assert.Equal(t, 4, usage.Snapshots)
+	assert.Positive(t, usage.Bytes)
+
+	// Two weeks back from 2026-10-16 keeps the weeks of Oct 7 and Oct 14.
+	cutoff := history.Cutoff(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), 2)
+	assert.Equal(t, time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC), cutoff)
+
+	dry, err := archive.Prune(cutoff, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"history/1425/2026-10-01_a.json", "history/1500/2026-09-30.json"}, dry.Keys)
+	after, err := archive.Usage()
+	require.NoError(t, err)
+	assert.Equal(t, usage, after, "a dry run removes nothing")
+
+	pruned, err := archive.Prune(cutoff, false)
+	require.NoError(t, err)
+	assert.Equal(t, dry, pruned)
+	after, err = archive.Usage()
+	require.NoError(t, err)
+	assert.Equal(t, 1, after.Stores)
+	assert.Equal(t, 2, after.Snapshots)
+	assert.Equal(t, usage.Bytes-pruned.Bytes, after.Bytes)
+	trips, err := archive.Trips()
+	require.NoError(t, err)
+	assert.Len(t, trips, 1, "the trip log is not a snapshot")
+}
+
+func TestArchive_SaveKeepsWeeks(t *testing.T) {
+	archive := history.Archive{Backend: &storage.Files{Dir: t.TempDir()}, KeepWeeks: 2}
+	for i, d := range []int{1, 8, 15} {
+		require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: string(rune('a' + i)), SavedAt: time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC)}))
+	}
+	list, err := archive.List("1425")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "b", list[0].Updated)
+}
+
+func TestArchive_Trips(t *testing.T) {
+	archive := history.Archive{Backend: &storage.Files{Dir: t.TempDir()}}
+	trips, err := archive.Trips()
+	require.NoError(t, err)
+	assert.Empty(t, trips)
+
+	late := history.Trip{List: "default", Week: "2026-10-14", CompletedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), Savings: 4}
+	early := history.Trip{List: "bob", Week: "2026-10-07", CompletedAt: time.Date(2026, 10, 9, 9, 0, 0, 0, time.UTC), Savings: 2.5,
+		Items: []history.TripItem{{Name: "Milk", Qty: 2}}}
+	require.NoError(t, archive.AddTrip(late))
+	require.NoError(t, archive.AddTrip(early))
+
+	trips, err = archive.Trips()
+	require.NoError(t, err)
+	require.Len(t, trips, 2)
+	assert.Equal(t, "bob", trips[0].List, "oldest first")
+	assert.Equal(t, []history.TripItem{{Name: "Milk", Qty: 2}}, trips[0].Items)
+}
+
+func TestTrip_Reconcile(t *testing.T) {
+	paid := func(v float64) *float64 { return &v }
+	trip := history.Trip{Savings: 9, Items: []history.TripItem{
+		{Name: "Yogurt", EstimatedCost: 10, EstimatedSavings: 4, Paid: paid(11)},
+		{Name: "Coffee", EstimatedSavings: 3, Paid: paid(8)},
+		{Name: "Chips", EstimatedCost: 2, EstimatedSavings: 2, Paid: paid(7)},
+		{Name: "Milk", EstimatedCost: 3, EstimatedSavings: 1},
+	}}
+	trip.Reconcile()
+	assert.True(t, trip.Reconciled)
+	assert.InDelta(t, 26.0, trip.Paid, 0.001)
+	// Yogurt saved $1 less than estimated, coffee has no price to compare,
+	// chips cost more than they saved, and milk was not entered.
+	assert.InDelta(t, 3.0+3.0+0+1.0, trip.Savings, 0.001)
+
+	unpaid := history.Trip{Items: []history.TripItem{{Name: "Milk", EstimatedSavings: 1}}}
+	unpaid.Reconcile()
+	assert.False(t, unpaid.Reconciled)
+	assert.InDelta(t, 1.0, unpaid.Savings, 0.001)
+}
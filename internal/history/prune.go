@@ -0,0 +1,109 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// Usage is how much the archive holds.
+type Usage struct {
+	Stores    int
+	Snapshots int
+	Bytes     int64
+}
+
+// Pruned is what Prune removed, or would remove.
+type Pruned struct {
+	Keys  []string
+	Bytes int64
+}
+
+// Cutoff returns the start of the oldest of the weeks ad weeks, counting
+// the one containing now, that retention keeps. Snapshots saved before it
+// are pruned.
+func Cutoff(now time.Time, weeks int) time.Time {
+	return WeekStart(now).AddDate(0, 0, -7*(weeks-1))
+}
+
+// Prune removes every store's snapshots saved before cutoff. With dryRun it
+// only reports what it would remove.
+func (a Archive) Prune(cutoff time.Time, dryRun bool) (Pruned, error) {
+	return a.prune(DirName+"/", cutoff, dryRun)
+}
+
+func (a Archive) prune(prefix string, cutoff time.Time, dryRun bool) (Pruned, error) {
+	var pruned Pruned
+	keys, err := a.Backend.Keys(prefix)
+	if err != nil {
+		return pruned, fmt.Errorf("reading history: %w", err)
+	}
+	cutoffDay := cutoff.Format(fileDateLayout)
+	for _, key := range keys {
+		_, day, ok := snapshotKey(key)
+		if !ok || day >= cutoffDay {
+			continue
+		}
+		obj, err := a.Backend.Get(key)
+		if errors.Is(err, storage.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return pruned, fmt.Errorf("reading snapshot: %w", err)
+		}
+		if !dryRun {
+			if err := a.Backend.Delete(key); err != nil && !errors.Is(err, storage.ErrNotExist) {
+				return pruned, fmt.Errorf("pruning %s: %w", key, err)
+			}
+		}
+		pruned.Keys = append(pruned.Keys, key)
+		pruned.Bytes += int64(len(obj.Data))
+	}
+	if !dryRun {
+		a.unindex(pruned.Keys...)
+	}
+	return pruned, nil
+}
+
+// Usage counts the stores and snapshots in the archive and their size.
+func (a Archive) Usage() (Usage, error) {
+	var u Usage
+	keys, err := a.Backend.Keys(DirName + "/")
+	if err != nil {
+		return u, fmt.Errorf("reading history: %w", err)
+	}
+	stores := map[string]bool{}
+	for _, key := range keys {
+		store, _, ok := snapshotKey(key)
+		if !ok {
+			continue
+		}
+		obj, err := a.Backend.Get(key)
+		if err != nil {
+			continue
+		}
+		stores[store] = true
+		u.Snapshots++
+		u.Bytes += int64(len(obj.Data))
+	}
+	u.Stores = len(stores)
+	return u, nil
+}
+
+// snapshotKey splits a history/<store>/<date>_<version>.json key, or a
+// history/<store>/<date>.json one. Other keys under history/, such as the
+// trip log, are not snapshots.
+func snapshotKey(key string) (store, day string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(key, DirName+"/"), "/")
+	if len(parts) != 2 || !strings.HasSuffix(parts[1], ".json") {
+		return "", "", false
+	}
+	day, _, _ = strings.Cut(strings.TrimSuffix(parts[1], ".json"), "_")
+	if _, err := time.Parse(fileDateLayout, day); err != nil {
+		return "", "", false
+	}
+	return parts[0], day, true
+}
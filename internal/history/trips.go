@@ -0,0 +1,113 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// TripsFile is the shopping trip log's file name inside the archive
+// directory.
+const TripsFile = "trips.json"
+
+const tripsKey = DirName + "/" + TripsFile
+
+// Trip is a shopping list marked done, with the savings estimated from its
+// items' deals.
+type Trip struct {
+	// List is the name of the shopping list the trip came from.
+	List string `json:"list"`
+	// Week is the first day of the ad week the trip was in, as YYYY-MM-DD.
+	Week        string     `json:"week"`
+	Store       string     `json:"store,omitempty"`
+	CompletedAt time.Time  `json:"completedAt"`
+	Items       []TripItem `json:"items"`
+	// Cost and Savings are estimates from the items' savings texts; items
+	// without a price are left out of Cost. Reconcile replaces Savings with
+	// what the paid prices show.
+	Cost    float64 `json:"cost"`
+	Savings float64 `json:"savings"`
+	// Paid is the sum of the items' paid prices, set by Reconcile, and
+	// Reconciled whether any item has one.
+	Paid       float64 `json:"paid,omitempty"`
+	Reconciled bool    `json:"reconciled,omitempty"`
+}
+
+// TripItem is one item bought on a trip.
+type TripItem struct {
+	Name    string `json:"name"`
+	Qty     int    `json:"qty,omitempty"`
+	Savings string `json:"savings,omitempty"`
+	// EstimatedCost and EstimatedSavings are read from Savings, times the
+	// quantity; zero when it does not state them.
+	EstimatedCost    float64 `json:"estimatedCost,omitempty"`
+	EstimatedSavings float64 `json:"estimatedSavings,omitempty"`
+	// Paid is what the item cost at the register, for the whole quantity,
+	// as entered with `list reconcile`; nil until then.
+	Paid *float64 `json:"paid,omitempty"`
+}
+
+// Reconcile recomputes Paid and Savings from the items' paid prices. Paying
+// more than an item's estimated cost means that much less was saved, and
+// paying less, more, but never below nothing. Items without a paid price, or
+// without an estimated cost to compare it with, keep their estimate.
+func (t *Trip) Reconcile() {
+	t.Paid, t.Savings, t.Reconciled = 0, 0, false
+	for _, it := range t.Items {
+		saved := it.EstimatedSavings
+		if it.Paid != nil {
+			t.Reconciled = true
+			t.Paid += *it.Paid
+			if it.EstimatedCost > 0 {
+				saved = max(0, it.EstimatedSavings+it.EstimatedCost-*it.Paid)
+			}
+		}
+		t.Savings += saved
+	}
+}
+
+type tripLog struct {
+	Trips []Trip `json:"trips"`
+}
+
+// Trips returns the logged trips, oldest first.
+func (a Archive) Trips() ([]Trip, error) {
+	obj, err := a.Backend.Get(tripsKey)
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading trips: %w", err)
+	}
+	var log tripLog
+	if err := json.Unmarshal(obj.Data, &log); err != nil {
+		return nil, fmt.Errorf("parsing trips %s: %w", tripsKey, err)
+	}
+	sort.SliceStable(log.Trips, func(i, j int) bool { return log.Trips[i].CompletedAt.Before(log.Trips[j].CompletedAt) })
+	return log.Trips, nil
+}
+
+// AddTrip appends a trip to the log.
+func (a Archive) AddTrip(t Trip) error {
+	trips, err := a.Trips()
+	if err != nil {
+		return err
+	}
+	return a.SaveTrips(append(trips, t))
+}
+
+// SaveTrips replaces the log with trips.
+func (a Archive) SaveTrips(trips []Trip) error {
+	data, err := json.MarshalIndent(tripLog{Trips: trips}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := a.Backend.Put(tripsKey, storage.Object{Data: data}); err != nil {
+		return fmt.Errorf("writing trips: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// ErrCorrupt marks a stored snapshot that cannot be parsed or whose hash
+// does not match its contents.
+var ErrCorrupt = errors.New("corrupt snapshot")
+
+// Check statuses.
+const (
+	CheckOK = "ok"
+	// CheckUnhashed is a snapshot saved before snapshots were hashed; it
+	// parses but cannot be verified.
+	CheckUnhashed = "unhashed"
+	CheckCorrupt  = "corrupt"
+)
+
+// Check is the result of verifying one stored snapshot.
+type Check struct {
+	Key    string
+	Store  string
+	Status string
+	// Err says what is wrong with a corrupt snapshot.
+	Err error
+	// Removed is set when Verify deleted the corrupt snapshot.
+	Removed bool
+}
+
+// Digest returns the SHA-256 of the snapshot's JSON encoding with Hash
+// left out, in hex. The encoding is deterministic, so the same snapshot
+// always has the same digest.
+func (s Snapshot) Digest() string {
+	s.Hash = ""
+	data, _ := json.Marshal(s)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// decodeSnapshot parses a stored snapshot and checks it against its hash.
+// A snapshot without a hash is returned unverified.
+func decodeSnapshot(key string, data []byte) (Snapshot, error) {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("parsing snapshot %s: %w: %v", key, ErrCorrupt, err)
+	}
+	if s.Hash != "" && s.Hash != s.Digest() {
+		return s, fmt.Errorf("%w %s: hash does not match its contents", ErrCorrupt, key)
+	}
+	return s, nil
+}
+
+// Verify checks every store's snapshots against their hashes. With repair,
+// corrupt snapshots are deleted, so the next fetch of that week's ad saves
+// a good one.
+func (a Archive) Verify(repair bool) ([]Check, error) {
+	return a.verify(DirName+"/", repair)
+}
+
+func (a Archive) verify(prefix string, repair bool) ([]Check, error) {
+	keys, err := a.Backend.Keys(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	var checks []Check
+	for _, key := range keys {
+		store, _, ok := snapshotKey(key)
+		if !ok {
+			continue
+		}
+		c := Check{Key: key, Store: store, Status: CheckOK}
+		obj, err := a.Backend.Get(key)
+		if errors.Is(err, storage.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return checks, fmt.Errorf("reading snapshot: %w", err)
+		}
+		s, err := decodeSnapshot(key, obj.Data)
+		switch {
+		case err != nil:
+			c.Status, c.Err = CheckCorrupt, err
+		case s.Hash == "":
+			c.Status = CheckUnhashed
+		}
+		if c.Status == CheckCorrupt && repair {
+			if err := a.Backend.Delete(key); err != nil && !errors.Is(err, storage.ErrNotExist) {
+				return checks, fmt.Errorf("removing %s: %w", key, err)
+			}
+			c.Removed = true
+			a.unindex(key)
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
@@ -0,0 +1,261 @@
+// Package images downloads deal images into a local directory so exports
+// and the TUI can use them offline.
+package images
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ManifestFile maps image URLs to file names inside the directory.
+	ManifestFile = "index.json"
+
+	DefaultMaxBytes    = 5 << 20
+	DefaultConcurrency = 4
+)
+
+// Downloader fetches images into Dir. The zero value of the optional
+// fields uses the defaults above.
+type Downloader struct {
+	Dir string
+	// Concurrency is the number of simultaneous downloads.
+	Concurrency int
+	// MaxBytes skips images larger than this.
+	MaxBytes int64
+	Client   *http.Client
+	// Variants, when set, lists the URLs to try for an image in order of
+	// preference, such as api.ImageVariants. The first one that downloads
+	// is saved under the original URL.
+	Variants func(string) []string
+}
+
+// Result reports one Download call.
+type Result struct {
+	// Paths maps every available image URL to its local file.
+	Paths      map[string]string
+	Downloaded int
+	// Cached counts images already present from an earlier run.
+	Cached int
+	// Failed maps URLs that could not be saved to the reason.
+	Failed map[string]string
+}
+
+// Download saves every distinct http(s) URL not already in the directory.
+// Individual failures are recorded in the result; the error reports only
+// problems with the directory itself.
+func (d *Downloader) Download(ctx context.Context, urls []string) (Result, error) {
+	if err := os.MkdirAll(d.Dir, 0o755); err != nil {
+		return Result{}, fmt.Errorf("creating image directory: %w", err)
+	}
+	manifest, err := d.loadManifest()
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Paths: map[string]string{}, Failed: map[string]string{}}
+	var todo []string
+	seen := map[string]bool{}
+	for _, raw := range urls {
+		raw = strings.TrimSpace(raw)
+		if raw == "" || seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		if name, ok := manifest[raw]; ok && fileExists(filepath.Join(d.Dir, name)) {
+			result.Paths[raw] = filepath.Join(d.Dir, name)
+			result.Cached++
+			continue
+		}
+		todo = append(todo, raw)
+	}
+
+	var mu sync.Mutex
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < d.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for raw := range jobs {
+				name, err := d.fetch(ctx, raw)
+				mu.Lock()
+				if err != nil {
+					result.Failed[raw] = err.Error()
+				} else {
+					manifest[raw] = name
+					result.Paths[raw] = filepath.Join(d.Dir, name)
+					result.Downloaded++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, raw := range todo {
+		jobs <- raw
+	}
+	close(jobs)
+	wg.Wait()
+
+	if result.Downloaded > 0 {
+		if err := d.saveManifest(manifest); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (d *Downloader) fetch(ctx context.Context, raw string) (string, error) {
+	candidates := []string{raw}
+	if d.Variants != nil {
+		if v := d.Variants(raw); len(v) > 0 {
+			candidates = v
+		}
+	}
+	var lastErr error
+	for _, candidate := range candidates {
+		data, contentType, err := d.get(ctx, candidate)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		name := FileName(raw, contentType)
+		target := filepath.Join(d.Dir, name)
+		tmp := target + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o644); err != nil {
+			return "", err
+		}
+		return name, os.Rename(tmp, target)
+	}
+	return "", lastErr
+}
+
+// get downloads one image, enforcing the size limit and an image content type.
+func (d *Downloader) get(ctx context.Context, raw string) ([]byte, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, "", errors.New("not an http(s) URL")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, raw, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	client := d.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	limit := d.maxBytes()
+	if resp.ContentLength > limit {
+		return nil, "", fmt.Errorf("%d bytes exceeds the %d-byte limit", resp.ContentLength, limit)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > limit {
+		return nil, "", fmt.Errorf("larger than the %d-byte limit", limit)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, "", fmt.Errorf("not an image (%s)", contentType)
+	}
+	return data, contentType, nil
+}
+
+// FileName is the stable local name for an image URL: a hash of the URL
+// plus an extension from the URL or, failing that, the content type.
+func FileName(raw, contentType string) string {
+	sum := sha256.Sum256([]byte(raw))
+	base := hex.EncodeToString(sum[:8])
+
+	ext := ""
+	if u, err := url.Parse(raw); err == nil {
+		ext = strings.ToLower(path.Ext(u.Path))
+	}
+	switch ext {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+		return base + ext
+	}
+	switch strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]) {
+	case "image/png":
+		return base + ".png"
+	case "image/gif":
+		return base + ".gif"
+	case "image/webp":
+		return base + ".webp"
+	default:
+		return base + ".jpg"
+	}
+}
+
+func (d *Downloader) concurrency() int {
+	if d.Concurrency > 0 {
+		return d.Concurrency
+	}
+	return DefaultConcurrency
+}
+
+func (d *Downloader) maxBytes() int64 {
+	if d.MaxBytes > 0 {
+		return d.MaxBytes
+	}
+	return DefaultMaxBytes
+}
+
+func (d *Downloader) loadManifest() (map[string]string, error) {
+	manifest := map[string]string{}
+	data, err := os.ReadFile(filepath.Join(d.Dir, ManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading image index: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing image index %s: %w", filepath.Join(d.Dir, ManifestFile), err)
+	}
+	return manifest, nil
+}
+
+func (d *Downloader) saveManifest(manifest map[string]string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(d.Dir, ManifestFile)
+	if err := os.WriteFile(target+".tmp", data, 0o644); err != nil {
+		return fmt.Errorf("writing image index: %w", err)
+	}
+	return os.Rename(target+".tmp", target)
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
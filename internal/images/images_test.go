@@ -0,0 +1,91 @@
+package images_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/images"
+)
+
+// A 1x1 transparent GIF.
+var gif = []byte("GIF89a\x01\x00\x01\x00\x80\x00\x00\x00\x00\x00\xff\xff\xff!\xf9\x04\x01\x00\x00\x00\x00,\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02D\x01\x00;")
+
+func TestDownloader_DedupesCachesAndLimits(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/big.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte(strings.Repeat("x", 200)))
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		case "/missing.png":
+			http.NotFound(w, r)
+		default:
+			w.Write(gif)
+		}
+	}))
+	defer srv.Close()
+
+	d := &images.Downloader{Dir: t.TempDir(), Concurrency: 2, MaxBytes: 100}
+	urls := []string{
+		srv.URL + "/a.gif", srv.URL + "/a.gif", srv.URL + "/b", srv.URL + "/big.jpg",
+		srv.URL + "/page.html", srv.URL + "/missing.png", "ftp://example.com/c.gif", "",
+	}
+
+	result, err := d.Download(context.Background(), urls)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Downloaded)
+	assert.Equal(t, 0, result.Cached)
+	assert.Len(t, result.Failed, 4)
+	assert.Contains(t, result.Failed[srv.URL+"/big.jpg"], "limit")
+	assert.Contains(t, result.Failed[srv.URL+"/page.html"], "not an image")
+	assert.Equal(t, int32(5), hits.Load(), "duplicate URLs are fetched once; ftp is never fetched")
+
+	saved, err := os.ReadFile(result.Paths[srv.URL+"/a.gif"])
+	require.NoError(t, err)
+	assert.Equal(t, gif, saved)
+	assert.True(t, strings.HasSuffix(result.Paths[srv.URL+"/b"], ".gif"), "extension from the sniffed content type")
+
+	again, err := d.Download(context.Background(), urls[:3])
+	require.NoError(t, err)
+	assert.Equal(t, 0, again.Downloaded)
+	assert.Equal(t, 2, again.Cached)
+	assert.Equal(t, int32(5), hits.Load())
+}
+
+func TestDownloader_FallsBackThroughVariants(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/coffee.gif" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(gif)
+	}))
+	defer srv.Close()
+
+	raw := srv.URL + "/coffee_thumb.gif"
+	d := &images.Downloader{
+		Dir:         t.TempDir(),
+		Concurrency: 1,
+		Variants: func(string) []string {
+			return []string{srv.URL + "/coffee_large.gif", srv.URL + "/coffee.gif", raw}
+		},
+	}
+	result, err := d.Download(context.Background(), []string{raw})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Downloaded)
+	assert.Contains(t, result.Paths, raw, "saved under the original URL")
+	assert.Equal(t, []string{"/coffee_large.gif", "/coffee.gif"}, paths)
+}
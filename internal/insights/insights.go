@@ -0,0 +1,347 @@
+// Package insights summarizes how a weekly ad's deals spread across
+// categories and departments, and how that spread moved since the previous
+// ad cycle.
+package insights
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+// MaxChanges is the number of week-over-week changes kept, largest first.
+const MaxChanges = 10
+
+// Metrics is the insights for one ad cycle.
+type Metrics struct {
+	Store     string `json:"store"`
+	StoreName string `json:"storeName,omitempty"`
+	Deals     int    `json:"deals"`
+	BogoDeals int    `json:"bogoDeals"`
+	// BogoCategories and BogoDepartments count the BOGO deals in each
+	// category and department, most first.
+	BogoCategories  []Share      `json:"bogoCategories"`
+	BogoDepartments []Share      `json:"bogoDepartments"`
+	Departments     []Department `json:"departments"`
+	PercentOff      PercentOff   `json:"percentOff"`
+	// ComparedTo is when the previous ad cycle was saved; it is empty when
+	// there is no earlier cycle, and Changes is then empty too.
+	ComparedTo *time.Time `json:"comparedTo,omitempty"`
+	Changes    []Change   `json:"changes"`
+}
+
+// Share is a category's or department's part of the BOGO deals.
+type Share struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	// Share is Count over all BOGO deals, from 0 to 1.
+	Share float64 `json:"share"`
+}
+
+// PercentOff summarizes the discounts of the deals whose percent off is
+// known, as filter.Savings.Percent reads it.
+type PercentOff struct {
+	// Deals counts the deals with a known percent off, and Estimated those
+	// of them whose percentage was estimated rather than stated.
+	Deals     int `json:"deals"`
+	Estimated int `json:"estimated"`
+	// Average is their mean percent off, 0 when Deals is 0.
+	Average float64 `json:"average"`
+}
+
+// Department is the deals of one department. AverageScore is the mean
+// filter.DealScore of its deals.
+type Department struct {
+	Name         string  `json:"name"`
+	Deals        int     `json:"deals"`
+	BogoDeals    int     `json:"bogoDeals"`
+	AverageScore float64 `json:"averageScore"`
+}
+
+// Change is a count that moved since the previous ad cycle.
+type Change struct {
+	// Scope is "ad" for the whole ad, or "department" or "category".
+	Scope string `json:"scope"`
+	// Name is the department or category; it is empty for the whole ad.
+	Name string `json:"name,omitempty"`
+	// Metric is "deals" or "bogoDeals".
+	Metric   string `json:"metric"`
+	Previous int    `json:"previous"`
+	Current  int    `json:"current"`
+	Delta    int    `json:"delta"`
+}
+
+// Build computes the insights for deals, comparing against previous when it
+// is not nil.
+func Build(store, storeName string, deals []api.SavingItem, previous *history.Snapshot) Metrics {
+	current := tally(deals)
+	m := Metrics{
+		Store:           store,
+		StoreName:       storeName,
+		Deals:           len(deals),
+		BogoDeals:       current.bogo,
+		BogoCategories:  shares(current.bogoByCategory, current.bogo),
+		BogoDepartments: shares(current.bogoByDepartment, current.bogo),
+		Departments:     departments(deals),
+		PercentOff:      percentOff(deals),
+		Changes:         []Change{},
+	}
+	if previous != nil {
+		savedAt := previous.SavedAt.UTC()
+		m.ComparedTo = &savedAt
+		m.Changes = changes(tally(previous.Deals), current)
+	}
+	return m
+}
+
+// counts tallies one ad cycle.
+type counts struct {
+	deals, bogo      int
+	byDepartment     map[string]int
+	byCategory       map[string]int
+	bogoByDepartment map[string]int
+	bogoByCategory   map[string]int
+}
+
+func tally(deals []api.SavingItem) counts {
+	c := counts{
+		deals:            len(deals),
+		byDepartment:     map[string]int{},
+		byCategory:       map[string]int{},
+		bogoByDepartment: map[string]int{},
+		bogoByCategory:   map[string]int{},
+	}
+	for _, item := range deals {
+		bogo := filter.ContainsIgnoreCase(item.Categories, "bogo")
+		dept := departmentName(item)
+		c.byDepartment[dept]++
+		if bogo {
+			c.bogo++
+			c.bogoByDepartment[dept]++
+		}
+		for _, category := range categoryNames(item) {
+			c.byCategory[category]++
+			if bogo {
+				c.bogoByCategory[category]++
+			}
+		}
+	}
+	return c
+}
+
+func departmentName(item api.SavingItem) string {
+	if dept := filter.CleanText(filter.Deref(item.Department)); dept != "" {
+		return dept
+	}
+	return "Other"
+}
+
+// categoryNames returns an item's categories, lowercased and without "bogo",
+// which every BOGO deal shares.
+func categoryNames(item api.SavingItem) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, raw := range item.Categories {
+		name := strings.ToLower(strings.TrimSpace(raw))
+		if name == "" || name == "bogo" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+func shares(byName map[string]int, total int) []Share {
+	list := make([]Share, 0, len(byName))
+	for name, count := range byName {
+		list = append(list, Share{Name: name, Count: count, Share: float64(count) / float64(total)})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// departments lists every department, best average score first.
+func departments(deals []api.SavingItem) []Department {
+	byName := map[string]*Department{}
+	totals := map[string]float64{}
+	for _, item := range deals {
+		name := departmentName(item)
+		d, ok := byName[name]
+		if !ok {
+			d = &Department{Name: name}
+			byName[name] = d
+		}
+		d.Deals++
+		if filter.ContainsIgnoreCase(item.Categories, "bogo") {
+			d.BogoDeals++
+		}
+		totals[name] += filter.DealScore(item)
+	}
+
+	list := make([]Department, 0, len(byName))
+	for name, d := range byName {
+		d.AverageScore = math.Round(totals[name]/float64(d.Deals)*100) / 100
+		list = append(list, *d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].AverageScore != list[j].AverageScore {
+			return list[i].AverageScore > list[j].AverageScore
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+func percentOff(deals []api.SavingItem) PercentOff {
+	var p PercentOff
+	total := 0.0
+	for _, item := range deals {
+		s := filter.ParseSavings(item)
+		if s.Percent == 0 {
+			continue
+		}
+		p.Deals++
+		total += s.Percent
+		if s.PercentSource == filter.PercentEstimated {
+			p.Estimated++
+		}
+	}
+	if p.Deals > 0 {
+		p.Average = math.Round(total/float64(p.Deals)*10) / 10
+	}
+	return p
+}
+
+// changes lists the counts that moved between two cycles, largest first.
+func changes(before, after counts) []Change {
+	list := []Change{}
+	add := func(scope, name, metric string, previous, current int) {
+		if previous != current {
+			list = append(list, Change{Scope: scope, Name: name, Metric: metric, Previous: previous, Current: current, Delta: current - previous})
+		}
+	}
+	add("ad", "", "deals", before.deals, after.deals)
+	add("ad", "", "bogoDeals", before.bogo, after.bogo)
+	for _, name := range union(before.byDepartment, after.byDepartment) {
+		add("department", name, "deals", before.byDepartment[name], after.byDepartment[name])
+	}
+	for _, name := range union(before.bogoByDepartment, after.bogoByDepartment) {
+		add("department", name, "bogoDeals", before.bogoByDepartment[name], after.bogoByDepartment[name])
+	}
+	for _, name := range union(before.byCategory, after.byCategory) {
+		add("category", name, "deals", before.byCategory[name], after.byCategory[name])
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return absInt(list[i].Delta) > absInt(list[j].Delta)
+	})
+	if len(list) > MaxChanges {
+		list = list[:MaxChanges]
+	}
+	return list
+}
+
+// union returns the keys of a and b, sorted.
+func union(a, b map[string]int) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range []map[string]int{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Write renders the insights as a plain text summary.
+func Write(w io.Writer, m Metrics) {
+	store := "Publix #" + m.Store
+	if m.StoreName != "" {
+		store = "Publix " + m.StoreName
+	}
+	fmt.Fprintf(w, "Insights: %s\n", store)
+	fmt.Fprintf(w, "%d deals, %d BOGO.\n", m.Deals, m.BogoDeals)
+	if p := m.PercentOff; p.Deals > 0 {
+		fmt.Fprintf(w, "Average %g%% off across %d deals with a known discount (%d estimated).\n", p.Average, p.Deals, p.Estimated)
+	}
+
+	section := func(text string) {
+		fmt.Fprintf(w, "\n%s\n", text)
+	}
+
+	section("BOGO deals by department")
+	if m.BogoDeals == 0 {
+		fmt.Fprintln(w, "  No BOGO deals this week.")
+	}
+	for _, s := range top(m.BogoDepartments, 5) {
+		fmt.Fprintf(w, "  %-20s %3d  %3.0f%%\n", s.Name, s.Count, s.Share*100)
+	}
+	if len(m.BogoCategories) > 0 {
+		section("BOGO deals by category")
+		for _, s := range top(m.BogoCategories, 5) {
+			fmt.Fprintf(w, "  %-20s %3d  %3.0f%%\n", s.Name, s.Count, s.Share*100)
+		}
+	}
+
+	section("Average deal score by department")
+	for _, d := range m.Departments {
+		fmt.Fprintf(w, "  %-20s %5.2f  (%d deals)\n", d.Name, d.AverageScore, d.Deals)
+	}
+
+	section("Changes since last week")
+	switch {
+	case m.ComparedTo == nil:
+		fmt.Fprintln(w, "  No earlier ad saved; changes are tracked from next cycle.")
+	case len(m.Changes) == 0:
+		fmt.Fprintf(w, "  No changes since %s.\n", m.ComparedTo.Format("Jan 2"))
+	}
+	for _, c := range m.Changes {
+		fmt.Fprintf(w, "  %s: %d → %d (%+d)\n", changeLabel(c), c.Previous, c.Current, c.Delta)
+	}
+}
+
+func top(list []Share, n int) []Share {
+	if len(list) > n {
+		return list[:n]
+	}
+	return list
+}
+
+func changeLabel(c Change) string {
+	metric := "deals"
+	if c.Metric == "bogoDeals" {
+		metric = "BOGO deals"
+	}
+	switch c.Scope {
+	case "department":
+		return c.Name + " " + metric
+	case "category":
+		return "Category " + c.Name + " " + metric
+	default:
+		return "All " + metric
+	}
+}
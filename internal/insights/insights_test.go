@@ -0,0 +1,92 @@
+package insights_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/insights"
+)
+
+func ptr(s string) *string { return &s }
+
+func deal(title, department string, categories ...string) api.SavingItem {
+	return api.SavingItem{Title: ptr(title), Department: ptr(department), Categories: categories}
+}
+
+func TestBuild_BogoSharesAndDepartments(t *testing.T) {
+	deals := []api.SavingItem{
+		deal("Chips", "Grocery", "bogo", "snacks"),
+		deal("Salsa", "Grocery", "BOGO", "snacks", "snacks"),
+		deal("Yogurt", "Dairy", "bogo", "dairy"),
+		deal("Apples", "Produce", "produce"),
+		deal("Mystery", ""),
+	}
+
+	m := insights.Build("1425", "#1425", deals, nil)
+	assert.Equal(t, 5, m.Deals)
+	assert.Equal(t, 3, m.BogoDeals)
+	require.Len(t, m.BogoDepartments, 2)
+	assert.Equal(t, insights.Share{Name: "Grocery", Count: 2, Share: 2.0 / 3}, m.BogoDepartments[0])
+	require.Len(t, m.BogoCategories, 2)
+	assert.Equal(t, "snacks", m.BogoCategories[0].Name)
+	assert.Equal(t, 2, m.BogoCategories[0].Count, "a repeated category counts once per deal")
+
+	require.Len(t, m.Departments, 4)
+	assert.Contains(t, []string{"Grocery", "Dairy"}, m.Departments[0].Name, "BOGO departments score highest")
+	names := map[string]insights.Department{}
+	for _, d := range m.Departments {
+		names[d.Name] = d
+	}
+	assert.Equal(t, 1, names["Other"].Deals)
+	assert.Equal(t, 2, names["Grocery"].BogoDeals)
+	assert.Nil(t, m.ComparedTo)
+	assert.Empty(t, m.Changes)
+}
+
+func TestBuild_WeekOverWeekChanges(t *testing.T) {
+	saved := time.Date(2026, 10, 8, 9, 0, 0, 0, time.UTC)
+	previous := &history.Snapshot{SavedAt: saved, Deals: []api.SavingItem{
+		deal("Chips", "Grocery", "bogo"),
+		deal("Apples", "Produce", "produce"),
+	}}
+	current := []api.SavingItem{
+		deal("Chips", "Grocery", "bogo"),
+		deal("Salsa", "Grocery", "bogo"),
+		deal("Soda", "Grocery", "bogo"),
+		deal("Apples", "Produce", "produce"),
+	}
+
+	m := insights.Build("1425", "", current, previous)
+	require.NotNil(t, m.ComparedTo)
+	assert.Equal(t, saved, *m.ComparedTo)
+	require.NotEmpty(t, m.Changes)
+	assert.Equal(t, insights.Change{Scope: "ad", Metric: "deals", Previous: 2, Current: 4, Delta: 2}, m.Changes[0])
+	for _, c := range m.Changes {
+		assert.NotEqual(t, "Produce", c.Name, "unchanged counts are left out")
+	}
+
+	var out bytes.Buffer
+	insights.Write(&out, m)
+	assert.Contains(t, out.String(), "Insights: Publix #1425\n4 deals, 3 BOGO.\n")
+	assert.Contains(t, out.String(), "  Grocery BOGO deals: 1 → 3 (+2)\n")
+}
+
+func TestBuild_PercentOff(t *testing.T) {
+	estimated := deal("Steak", "Meat")
+	estimated.Savings = ptr("$6.00, Save $2.00")
+	stated := deal("Candles", "Home")
+	stated.Savings = ptr("30% off")
+	deals := []api.SavingItem{deal("Chips", "Grocery", "bogo"), estimated, stated, deal("Apples", "Produce")}
+
+	m := insights.Build("1425", "", deals, nil)
+	assert.Equal(t, insights.PercentOff{Deals: 3, Estimated: 1, Average: 35}, m.PercentOff)
+
+	var out bytes.Buffer
+	insights.Write(&out, m)
+	assert.Contains(t, out.String(), "Average 35% off across 3 deals with a known discount (1 estimated).\n")
+}
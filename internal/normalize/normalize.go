@@ -0,0 +1,161 @@
+// Package normalize canonicalizes the category, department, and brand labels
+// in upstream weekly ad data, which vary in casing, punctuation, and wording
+// from deal to deal ("Meat" vs "meat & seafood"). Every function is
+// idempotent, so already-normalized data passes through unchanged.
+package normalize
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// categoryAliases maps category slugs to their canonical slug.
+var categoryAliases = map[string]string{
+	"bogos":                 "bogo",
+	"bogof":                 "bogo",
+	"buy-one-get-one":       "bogo",
+	"buy-1-get-1":           "bogo",
+	"meats":                 "meat",
+	"meat-and-seafood":      "meat",
+	"meat-seafood":          "meat",
+	"fresh-meat":            "meat",
+	"fresh-produce":         "produce",
+	"fruits-and-vegetables": "produce",
+	"dairy-and-eggs":        "dairy",
+	"frozen-foods":          "frozen",
+	"frozen-food":           "frozen",
+	"bakery-and-bread":      "bakery",
+	"groceries":             "grocery",
+	"snack":                 "snacks",
+	"beverage":              "beverages",
+	"drinks":                "beverages",
+	"beer-and-wine":         "beer-wine",
+	"pets":                  "pet",
+	"pet-bogo":              "pet-bogos",
+}
+
+// departmentAliases maps lowercased department labels to their canonical
+// label.
+var departmentAliases = map[string]string{
+	"meat & seafood":        "Meat",
+	"meat/seafood":          "Meat",
+	"meats":                 "Meat",
+	"seafood & meat":        "Meat",
+	"fresh produce":         "Produce",
+	"fruits & vegetables":   "Produce",
+	"dairy & eggs":          "Dairy",
+	"frozen foods":          "Frozen",
+	"frozen food":           "Frozen",
+	"groceries":             "Grocery",
+	"deli & prepared foods": "Deli",
+	"health & beauty":       "Health & Beauty",
+	"beer & wine":           "Beer & Wine",
+	"pet care":              "Pet Care",
+}
+
+// brandAliases maps lowercased brand names to their canonical spelling.
+var brandAliases = map[string]string{
+	"publix":           "Publix",
+	"publix premium":   "Publix Premium",
+	"greenwise":        "GreenWise",
+	"publix greenwise": "GreenWise",
+	"green wise":       "GreenWise",
+	"publix deli":      "Publix Deli",
+	"publix bakery":    "Publix Bakery",
+}
+
+// Category returns the canonical slug for a category label: lowercase, with
+// "&" spelled "and" and words joined by hyphens, then mapped through the
+// alias table, so "Meat & Seafood" and "meat_and_seafood" both become "meat".
+func Category(raw string) string {
+	s := strings.ToLower(clean(raw))
+	s = strings.ReplaceAll(s, "&", " and ")
+	s = strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_' || r == '/' || r == ','
+	}), "-")
+	if canonical, ok := categoryAliases[s]; ok {
+		return canonical
+	}
+	return s
+}
+
+// Categories canonicalizes each label and drops empty and duplicate ones,
+// keeping the first occurrence's position.
+func Categories(raw []string) []string {
+	if raw == nil {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, c := range raw {
+		c = Category(c)
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	return out
+}
+
+// Department returns the canonical department label: whitespace collapsed,
+// "and" written "&", mapped through the alias table, and title-cased when
+// upstream sent it all in one case.
+func Department(raw string) string {
+	s := clean(raw)
+	s = strings.Join(strings.Fields(strings.ReplaceAll(s, "&", " & ")), " ")
+	words := strings.Fields(s)
+	for i, w := range words {
+		if i > 0 && i < len(words)-1 && strings.EqualFold(w, "and") {
+			words[i] = "&"
+		}
+	}
+	s = strings.Join(words, " ")
+	if canonical, ok := departmentAliases[strings.ToLower(s)]; ok {
+		return canonical
+	}
+	return titleIfSingleCase(s)
+}
+
+// Brand returns the canonical brand name: whitespace collapsed, trademark
+// symbols dropped, mapped through the alias table, and title-cased when
+// upstream sent it all in lowercase. All-caps names such as "KIND" are kept.
+func Brand(raw string) string {
+	s := strings.NewReplacer("®", "", "™", "", "©", "").Replace(clean(raw))
+	s = strings.Join(strings.Fields(s), " ")
+	if canonical, ok := brandAliases[strings.ToLower(s)]; ok {
+		return canonical
+	}
+	if s != strings.ToLower(s) {
+		return s
+	}
+	return title(s)
+}
+
+// clean unescapes HTML entities and collapses whitespace.
+func clean(s string) string {
+	if strings.Contains(s, "&") {
+		s = html.UnescapeString(s)
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// titleIfSingleCase title-cases s when it has no mixed case ("FROZEN" or
+// "frozen"), leaving deliberate spellings such as "GreenWise" alone.
+func titleIfSingleCase(s string) string {
+	if s != strings.ToUpper(s) && s != strings.ToLower(s) {
+		return s
+	}
+	return title(s)
+}
+
+func title(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		runes := []rune(strings.ToLower(w))
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,69 @@
+package normalize_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tayloree/publix-deals/internal/normalize"
+)
+
+func TestCategory(t *testing.T) {
+	cases := map[string]string{
+		"meat":               "meat",
+		"Meat":               "meat",
+		"meat & seafood":     "meat",
+		"Meat &amp; Seafood": "meat",
+		"meat_and_seafood":   "meat",
+		"  Frozen Foods ":    "frozen",
+		"BOGOs":              "bogo",
+		"pet-bogos":          "pet-bogos",
+		"Snack":              "snacks",
+		"":                   "",
+	}
+	for in, want := range cases {
+		got := normalize.Category(in)
+		assert.Equal(t, want, got, in)
+		assert.Equal(t, got, normalize.Category(got), "idempotent for %q", in)
+	}
+}
+
+func TestCategories_DropsDuplicatesAndBlanks(t *testing.T) {
+	got := normalize.Categories([]string{"Meat", "bogo", "meat & seafood", " ", "BOGO"})
+	assert.Equal(t, []string{"meat", "bogo"}, got)
+	assert.Nil(t, normalize.Categories(nil))
+}
+
+func TestDepartment(t *testing.T) {
+	cases := map[string]string{
+		"Meat":                    "Meat",
+		"MEAT & SEAFOOD":          "Meat",
+		"meat and seafood":        "Meat",
+		"Peanut Butter & Jelly":   "Peanut Butter & Jelly",
+		"Peanut Butter and Jelly": "Peanut Butter & Jelly",
+		"health  and beauty":      "Health & Beauty",
+		"FROZEN":                  "Frozen",
+		"Pet Food":                "Pet Food",
+	}
+	for in, want := range cases {
+		got := normalize.Department(in)
+		assert.Equal(t, want, got, in)
+		assert.Equal(t, got, normalize.Department(got), "idempotent for %q", in)
+	}
+}
+
+func TestBrand(t *testing.T) {
+	cases := map[string]string{
+		"Publix":            "Publix",
+		"PUBLIX":            "Publix",
+		"publix greenwise":  "GreenWise",
+		"GreenWise®":        "GreenWise",
+		"KIND":              "KIND",
+		"3M":                "3M",
+		"ben &amp; jerry's": "Ben & Jerry's",
+	}
+	for in, want := range cases {
+		got := normalize.Brand(in)
+		assert.Equal(t, want, got, in)
+		assert.Equal(t, got, normalize.Brand(got), "idempotent for %q", in)
+	}
+}
@@ -8,10 +8,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/tayloree/publix-deals/internal/api"
 	"github.com/tayloree/publix-deals/internal/display"
 	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/fixtures"
+	"github.com/tayloree/publix-deals/internal/history"
 )
 
 func strPtr(v string) *string { return &v }
@@ -55,20 +58,35 @@ func benchmarkDeals(count int) []api.SavingItem {
 
 func setupPipelineServer(b *testing.B, dealCount int) (*httptest.Server, *api.Client) {
 	b.Helper()
-
-	storesPayload, err := json.Marshal(api.StoreResponse{
-		Stores: []api.Store{
+	return servePipeline(b,
+		api.SavingsResponse{Savings: benchmarkDeals(dealCount), LanguageID: 1},
+		api.StoreResponse{Stores: []api.Store{
 			{Key: "01425", Name: "Peachers Mill", Addr: "1490 Tiny Town Rd", City: "Clarksville", State: "TN", Zip: "37042", Distance: "5"},
-		},
+		}},
+	)
+}
+
+// setupFixtureServer serves a generated ad, which varies its deals like the
+// live one does.
+func setupFixtureServer(b *testing.B, dealCount int) (*httptest.Server, *api.Client) {
+	b.Helper()
+	savings, stores := fixtures.Generate(fixtures.Options{
+		Deals:     dealCount,
+		Stores:    5,
+		Seed:      1,
+		WeekStart: history.WeekStart(time.Now()),
 	})
+	return servePipeline(b, savings, stores)
+}
+
+func servePipeline(b *testing.B, savings api.SavingsResponse, stores api.StoreResponse) (*httptest.Server, *api.Client) {
+	b.Helper()
+
+	storesPayload, err := json.Marshal(stores)
 	if err != nil {
 		b.Fatalf("marshal stores payload: %v", err)
 	}
-
-	savingsPayload, err := json.Marshal(api.SavingsResponse{
-		Savings:    benchmarkDeals(dealCount),
-		LanguageID: 1,
-	})
+	savingsPayload, err := json.Marshal(savings)
 	if err != nil {
 		b.Fatalf("marshal savings payload: %v", err)
 	}
@@ -90,7 +108,7 @@ func setupPipelineServer(b *testing.B, dealCount int) (*httptest.Server, *api.Cl
 	return server, client
 }
 
-func runPipeline(b *testing.B, client *api.Client) {
+func runPipeline(b *testing.B, client *api.Client, opts filter.Options) {
 	b.Helper()
 
 	ctx := context.Background()
@@ -107,13 +125,7 @@ func runPipeline(b *testing.B, client *api.Client) {
 		b.Fatalf("fetch savings: %v", err)
 	}
 
-	filtered := filter.Apply(resp.Savings, filter.Options{
-		BOGO:       true,
-		Category:   "grocery",
-		Department: "grocery",
-		Query:      "fresh",
-		Limit:      50,
-	})
+	filtered := filter.Apply(resp.Savings, opts)
 	if len(filtered) == 0 {
 		b.Fatalf("filter returned no deals")
 	}
@@ -128,6 +140,22 @@ func BenchmarkZipPipeline_1kDeals(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for range b.N {
-		runPipeline(b, client)
+		runPipeline(b, client, filter.Options{
+			BOGO:       true,
+			Category:   "grocery",
+			Department: "grocery",
+			Query:      "fresh",
+			Limit:      50,
+		})
+	}
+}
+
+func BenchmarkZipPipeline_5kFixtureDeals(b *testing.B) {
+	_, client := setupFixtureServer(b, 5000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		runPipeline(b, client, filter.Options{BOGO: true, Category: "grocery", Sort: "savings", Limit: 50})
 	}
 }
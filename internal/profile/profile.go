@@ -0,0 +1,262 @@
+// Package profile flags the deals a household avoids: foods with its
+// allergens, foods it dislikes, and foods outside its diets. Matching reads
+// the deal's title, description, and department, so it is a reminder to
+// check the label, not a guarantee.
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// Profile is the household's dietary profile from the config file.
+type Profile struct {
+	// Allergens are flagged by name, e.g. "peanut" or "shellfish". Known
+	// allergens also match the foods that contain them; others match as
+	// words.
+	Allergens []string
+	// Dislikes are words or phrases, e.g. "olive" or "blue cheese".
+	Dislikes []string
+	// Diets are the household's diets; see Diets.
+	Diets []string
+	// Hide drops flagged deals instead of marking them.
+	Hide bool
+}
+
+// Empty reports whether the profile flags nothing.
+func (p Profile) Empty() bool {
+	return len(p.Allergens) == 0 && len(p.Dislikes) == 0 && len(p.Diets) == 0
+}
+
+var (
+	meat      = []string{"beef", "steak", "chicken", "pork", "turkey", "ham", "bacon", "sausage", "lamb", "veal", "salami", "pepperoni", "hot dog", "bratwurst", "jerky", "ribs", "brisket", "chorizo", "prosciutto", "wings", "meatball", "deli meat"}
+	fish      = []string{"fish", "salmon", "tuna", "cod", "tilapia", "mahi", "grouper", "trout", "anchovy", "sardine", "catfish", "flounder", "snapper", "swordfish", "halibut", "pollock"}
+	shellfish = []string{"shellfish", "shrimp", "crab", "lobster", "scallop", "clam", "mussel", "oyster", "crawfish", "prawn"}
+	dairy     = []string{"milk", "cheese", "butter", "cream", "yogurt", "ice cream", "whey", "queso", "cheddar", "mozzarella", "parmesan"}
+	egg       = []string{"egg", "mayonnaise", "mayo", "meringue"}
+	gluten    = []string{"wheat", "gluten", "bread", "flour", "pasta", "spaghetti", "noodle", "bagel", "bun", "croissant", "muffin", "cracker", "cookie", "cake", "pretzel", "barley", "rye", "beer", "cereal"}
+	treeNuts  = []string{"almond", "cashew", "pecan", "walnut", "pistachio", "hazelnut", "macadamia", "brazil nut", "nutella", "praline"}
+)
+
+// allergenFoods maps known allergens, and their common spellings, to the
+// words that suggest a deal contains them.
+var allergenFoods = map[string][]string{
+	"peanut":    {"peanut"},
+	"tree nut":  treeNuts,
+	"nut":       append([]string{"peanut"}, treeNuts...),
+	"milk":      dairy,
+	"dairy":     dairy,
+	"egg":       egg,
+	"wheat":     gluten,
+	"gluten":    gluten,
+	"soy":       {"soy", "soybean", "tofu", "edamame", "miso", "tempeh"},
+	"fish":      fish,
+	"shellfish": shellfish,
+	"sesame":    {"sesame", "tahini", "hummus"},
+}
+
+// diet is one of the diets a profile can name: deals in its departments or
+// with its words are flagged, unless they name one of its exceptions.
+type diet struct {
+	departments []string
+	words       []string
+	exceptions  []string
+}
+
+var diets = map[string]diet{
+	"vegetarian":  {departments: []string{"meat", "seafood"}, words: concat(meat, fish, shellfish), exceptions: []string{"vegetarian", "vegan", "meatless", "plant based"}},
+	"pescatarian": {departments: []string{"meat"}, words: meat, exceptions: []string{"vegetarian", "vegan", "meatless", "plant based"}},
+	"vegan":       {departments: []string{"meat", "seafood"}, words: concat(meat, fish, shellfish, dairy, egg, []string{"honey"}), exceptions: []string{"vegan", "plant based", "dairy free"}},
+	"gluten-free": {words: gluten, exceptions: []string{"gluten free"}},
+	"dairy-free":  {words: dairy, exceptions: []string{"dairy free", "non dairy", "vegan", "plant based"}},
+}
+
+func concat(lists ...[]string) []string {
+	var out []string
+	for _, l := range lists {
+		out = append(out, l...)
+	}
+	return out
+}
+
+// Diets returns the names of the diets a profile can name.
+func Diets() []string {
+	names := make([]string, 0, len(diets))
+	for name := range diets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// check is one reason to flag a deal.
+type check struct {
+	flag        string
+	departments []string
+	terms       [][]string
+	exceptions  [][]string
+}
+
+// Checker flags deals against a profile.
+type Checker struct {
+	checks []check
+	hide   bool
+}
+
+// New prepares a profile for checking deals. It fails for a diet Diets does
+// not list.
+func New(p Profile) (*Checker, error) {
+	c := &Checker{hide: p.Hide}
+	for _, a := range p.Allergens {
+		name := normalize(a)
+		if name == "" {
+			continue
+		}
+		foods, ok := allergenFoods[singular(name)]
+		if !ok {
+			foods = []string{name}
+		}
+		c.checks = append(c.checks, check{
+			flag:       name,
+			terms:      words(foods),
+			exceptions: words([]string{singular(name) + " free"}),
+		})
+	}
+	for _, d := range p.Dislikes {
+		if name := normalize(d); name != "" {
+			c.checks = append(c.checks, check{flag: name, terms: words([]string{name})})
+		}
+	}
+	for _, d := range p.Diets {
+		name := strings.ToLower(strings.TrimSpace(d))
+		if name == "" {
+			continue
+		}
+		spec, ok := diets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown diet %q (use %s)", d, strings.Join(Diets(), ", "))
+		}
+		c.checks = append(c.checks, check{
+			flag:        "not " + name,
+			departments: spec.departments,
+			terms:       words(spec.words),
+			exceptions:  words(spec.exceptions),
+		})
+	}
+	return c, nil
+}
+
+// Hides reports whether flagged deals are dropped rather than marked.
+func (c *Checker) Hides() bool {
+	return c != nil && c.hide && len(c.checks) > 0
+}
+
+// Flags returns why the profile flags a deal, e.g. "peanut" or "not
+// vegetarian"; nil when it does not.
+func (c *Checker) Flags(item api.SavingItem) []string {
+	if c == nil || len(c.checks) == 0 {
+		return nil
+	}
+	text := tokens(filter.CleanText(filter.Deref(item.Title)) + " " + filter.CleanText(filter.Deref(item.Description)))
+	dept := strings.ToLower(filter.Deref(item.Department))
+
+	var flags []string
+	for _, ch := range c.checks {
+		if ch.matches(text, dept) {
+			flags = append(flags, ch.flag)
+		}
+	}
+	return flags
+}
+
+func (ch check) matches(text []string, dept string) bool {
+	for _, e := range ch.exceptions {
+		if containsPhrase(text, e) {
+			return false
+		}
+	}
+	for _, d := range ch.departments {
+		if strings.Contains(dept, d) {
+			return true
+		}
+	}
+	for _, t := range ch.terms {
+		if containsPhrase(text, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply sets the Flags of the flagged deals, or drops them when the profile
+// hides them. Items is not modified.
+func (c *Checker) Apply(items []api.SavingItem) []api.SavingItem {
+	if c == nil || len(c.checks) == 0 {
+		return items
+	}
+	out := make([]api.SavingItem, 0, len(items))
+	for _, item := range items {
+		flags := c.Flags(item)
+		if len(flags) > 0 && c.hide {
+			continue
+		}
+		item.Flags = flags
+		out = append(out, item)
+	}
+	return out
+}
+
+// containsPhrase reports whether the phrase's words appear in text in a
+// row. A word matches its plural, so "olive" finds "Olives".
+func containsPhrase(text, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(text); i++ {
+		match := true
+		for j, w := range phrase {
+			if t := text[i+j]; t != w && t != w+"s" && t != w+"es" {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// tokens lowercases text and splits it into words, so "Gluten-Free" is
+// "gluten free".
+func tokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func words(phrases []string) [][]string {
+	out := make([][]string, 0, len(phrases))
+	for _, p := range phrases {
+		out = append(out, tokens(p))
+	}
+	return out
+}
+
+// normalize lowercases a profile entry and joins its words with spaces.
+func normalize(s string) string {
+	return strings.Join(tokens(s), " ")
+}
+
+// singular drops a plural "s", so "peanuts" names the peanut allergen.
+func singular(s string) string {
+	if strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss") {
+		return strings.TrimSuffix(s, "s")
+	}
+	return s
+}
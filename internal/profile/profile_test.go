@@ -0,0 +1,71 @@
+package profile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/profile"
+)
+
+func ptr(s string) *string { return &s }
+
+func deal(id, title, dept string) api.SavingItem {
+	return api.SavingItem{ID: id, Title: ptr(title), Department: ptr(dept)}
+}
+
+func TestChecker_Flags(t *testing.T) {
+	c, err := profile.New(profile.Profile{
+		Allergens: []string{"Peanuts", "gluten"},
+		Dislikes:  []string{"olive"},
+		Diets:     []string{"vegetarian"},
+	})
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		item api.SavingItem
+		want []string
+	}{
+		"allergen plural":      {deal("1", "Jif Peanut Butter", "Grocery"), []string{"peanuts"}},
+		"allergen food":        {deal("2", "Publix Bagels", "Bakery"), []string{"gluten"}},
+		"free of the allergen": {deal("3", "Gluten-Free Bread", "Bakery"), nil},
+		"dislike plural":       {deal("4", "Kalamata Olives", "Deli"), []string{"olive"}},
+		"diet by department":   {deal("5", "Boneless Thighs", "Meat"), []string{"not vegetarian"}},
+		"diet by word":         {deal("6", "Shrimp Fried Rice", "Frozen"), []string{"not vegetarian"}},
+		"diet exception":       {deal("7", "Vegetarian Chicken Nuggets", "Frozen"), nil},
+		"ham inside graham":    {deal("8", "Graham Crackers", "Grocery"), []string{"gluten"}},
+		"nothing":              {deal("9", "Bananas", "Produce"), nil},
+	}
+	for name, tc := range cases {
+		assert.Equal(t, tc.want, c.Flags(tc.item), name)
+	}
+}
+
+func TestChecker_Apply(t *testing.T) {
+	items := []api.SavingItem{deal("1", "Peanut Butter", "Grocery"), deal("2", "Bananas", "Produce")}
+
+	marker, err := profile.New(profile.Profile{Allergens: []string{"peanut"}})
+	require.NoError(t, err)
+	marked := marker.Apply(items)
+	require.Len(t, marked, 2)
+	assert.Equal(t, []string{"peanut"}, marked[0].Flags)
+	assert.Empty(t, marked[1].Flags)
+	assert.Empty(t, items[0].Flags, "the input is not modified")
+	assert.False(t, marker.Hides())
+
+	hider, err := profile.New(profile.Profile{Allergens: []string{"peanut"}, Hide: true})
+	require.NoError(t, err)
+	assert.True(t, hider.Hides())
+	kept := hider.Apply(items)
+	require.Len(t, kept, 1)
+	assert.Equal(t, "2", kept[0].ID)
+
+	var none *profile.Checker
+	assert.Equal(t, items, none.Apply(items))
+}
+
+func TestNew_UnknownDiet(t *testing.T) {
+	_, err := profile.New(profile.Profile{Diets: []string{"keto"}})
+	assert.ErrorContains(t, err, `unknown diet "keto" (use dairy-free, gluten-free, pescatarian, vegan, vegetarian)`)
+}
@@ -0,0 +1,377 @@
+// Package publish writes a read-only static mirror of weekly ads: an HTML
+// page, JSON files, and an RSS feed per store, ready for GitHub Pages or
+// any static file host.
+package publish
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+// StoreAd is one store's weekly ad.
+type StoreAd struct {
+	Store   display.StoreJSON
+	Updated string
+	Deals   []api.SavingItem
+}
+
+// Site is everything one publish run writes.
+type Site struct {
+	Stores []StoreAd
+	// BaseURL is the public URL the site is served from, e.g.
+	// https://me.github.io/deals/. RSS needs absolute links; without it the
+	// feeds use relative ones.
+	BaseURL       string
+	GeneratedAt   time.Time
+	SchemaVersion int
+}
+
+// storeSummary is an entry of api/stores.json.
+type storeSummary struct {
+	display.StoreJSON
+	Deals     int    `json:"deals"`
+	BogoDeals int    `json:"bogoDeals"`
+	Updated   string `json:"updated,omitempty"`
+	DealsURL  string `json:"dealsUrl"`
+	FeedURL   string `json:"feedUrl"`
+}
+
+// Write renders the site into dir, creating it as needed. Files from an
+// earlier run are overwritten; stores no longer published are left alone.
+//
+// Layout:
+//
+//	index.html                       store list
+//	stores/<number>/index.html       deals by department
+//	stores/<number>/feed.xml         RSS 2.0 feed
+//	api/stores.json                  store summaries
+//	api/stores/<number>/deals.json   deals in the CLI's JSON shape
+func Write(dir string, site Site) error {
+	summaries := make([]storeSummary, 0, len(site.Stores))
+	for _, ad := range site.Stores {
+		summaries = append(summaries, summarize(ad))
+		if err := writeStore(dir, site, ad); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := display.EncodeVersioned(&buf, site.SchemaVersion, "stores", summaries); err != nil {
+		return err
+	}
+	if err := writeFile(dir, "api/stores.json", buf.Bytes()); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	if err := indexTemplate.Execute(&buf, struct {
+		Site
+		Summaries []storeSummary
+	}{site, summaries}); err != nil {
+		return err
+	}
+	if err := writeFile(dir, "index.html", buf.Bytes()); err != nil {
+		return err
+	}
+	// GitHub Pages would otherwise run the files through Jekyll.
+	return writeFile(dir, ".nojekyll", nil)
+}
+
+func summarize(ad StoreAd) storeSummary {
+	s := storeSummary{
+		StoreJSON: ad.Store,
+		Deals:     len(ad.Deals),
+		Updated:   ad.Updated,
+		DealsURL:  "api/stores/" + ad.Store.Number + "/deals.json",
+		FeedURL:   "stores/" + ad.Store.Number + "/feed.xml",
+	}
+	for _, item := range ad.Deals {
+		if display.ToDealJSON(item).IsBogo {
+			s.BogoDeals++
+		}
+	}
+	return s
+}
+
+func writeStore(dir string, site Site, ad StoreAd) error {
+	number := ad.Store.Number
+	deals := make([]display.DealJSON, 0, len(ad.Deals))
+	for _, item := range ad.Deals {
+		deals = append(deals, display.ToDealJSON(item))
+	}
+
+	var buf bytes.Buffer
+	if err := display.EncodeVersioned(&buf, site.SchemaVersion, "deals", deals); err != nil {
+		return err
+	}
+	if err := writeFile(dir, "api/stores/"+number+"/deals.json", buf.Bytes()); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	if err := storeTemplate.Execute(&buf, storePage{
+		Store:       ad.Store,
+		Week:        adWeek(deals),
+		Count:       len(deals),
+		Groups:      groupByDepartment(deals),
+		GeneratedAt: site.GeneratedAt,
+	}); err != nil {
+		return err
+	}
+	if err := writeFile(dir, "stores/"+number+"/index.html", buf.Bytes()); err != nil {
+		return err
+	}
+
+	feed, err := renderFeed(site, ad.Store, deals)
+	if err != nil {
+		return err
+	}
+	return writeFile(dir, "stores/"+number+"/feed.xml", feed)
+}
+
+type departmentGroup struct {
+	Department string
+	Deals      []display.DealJSON
+}
+
+type storePage struct {
+	Store       display.StoreJSON
+	Week        string
+	Count       int
+	Groups      []departmentGroup
+	GeneratedAt time.Time
+}
+
+func groupByDepartment(deals []display.DealJSON) []departmentGroup {
+	index := map[string]int{}
+	var groups []departmentGroup
+	for _, d := range deals {
+		dept := d.Department
+		if dept == "" {
+			dept = "Other"
+		}
+		i, ok := index[dept]
+		if !ok {
+			i = len(groups)
+			index[dept] = i
+			groups = append(groups, departmentGroup{Department: dept})
+		}
+		groups[i].Deals = append(groups[i].Deals, d)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Department < groups[j].Department })
+	return groups
+}
+
+// adWeek returns the most common validity range among the deals.
+func adWeek(deals []display.DealJSON) string {
+	counts := map[string]int{}
+	best := ""
+	for _, d := range deals {
+		if d.ValidFrom == "" || d.ValidTo == "" {
+			continue
+		}
+		week := d.ValidFrom + " – " + d.ValidTo
+		counts[week]++
+		if counts[week] > counts[best] {
+			best = week
+		}
+	}
+	return best
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+func renderFeed(site Site, store display.StoreJSON, deals []display.DealJSON) ([]byte, error) {
+	link := siteURL(site.BaseURL, "stores/"+store.Number+"/")
+	channel := rssChannel{
+		Title:         fmt.Sprintf("Publix #%s weekly deals", store.Number),
+		Link:          link,
+		Description:   "This week's deals at " + storeLabel(store),
+		LastBuildDate: site.GeneratedAt.UTC().Format(time.RFC1123Z),
+	}
+	for _, d := range deals {
+		desc := strings.TrimSpace(strings.Join(nonEmpty(d.Savings, d.Description, validThrough(d.ValidTo)), " — "))
+		channel.Items = append(channel.Items, rssItem{
+			Title:       d.Title,
+			Link:        link,
+			Description: desc,
+			Category:    d.Department,
+			// Deals carry no stable public URL, so the GUID is the deal
+			// within its ad week: readers show each week's deals once.
+			GUID: rssGUID{Value: fmt.Sprintf("publix-%s-%s-%s", store.Number, d.ValidFrom, d.Title)},
+		})
+	}
+
+	out, err := xml.MarshalIndent(rss{Version: "2.0", Channel: channel}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+func siteURL(base, path string) string {
+	if base == "" {
+		return path
+	}
+	return strings.TrimSuffix(base, "/") + "/" + path
+}
+
+func storeLabel(store display.StoreJSON) string {
+	if store.Name == "" {
+		return "Publix #" + store.Number
+	}
+	return fmt.Sprintf("Publix #%s — %s", store.Number, store.Name)
+}
+
+func validThrough(validTo string) string {
+	if validTo == "" {
+		return ""
+	}
+	return "through " + validTo
+}
+
+func nonEmpty(parts ...string) []string {
+	out := parts[:0]
+	for _, p := range parts {
+		if strings.TrimSpace(p) != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func writeFile(dir, name string, data []byte) error {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+var funcs = template.FuncMap{"storeLabel": storeLabel}
+
+const pageStyle = `
+  body { margin: 0; font: 16px/1.45 -apple-system, system-ui, sans-serif; background: #f4f4f4; color: #222; }
+  main { max-width: 760px; margin: 0 auto; padding: 16px; }
+  header { background: #3a7d2c; color: #fff; border-radius: 12px; padding: 16px; }
+  header h1 { margin: 0 0 4px; font-size: 22px; }
+  header p { margin: 2px 0; opacity: .9; }
+  header a { color: #fff; }
+  section { background: #fff; border-radius: 12px; margin-top: 12px; padding: 4px 16px 8px; }
+  h2 { font-size: 13px; text-transform: uppercase; letter-spacing: .05em; color: #666; margin: 12px 0 4px; }
+  article { display: flex; gap: 12px; padding: 10px 0; border-top: 1px solid #eee; }
+  h2 + article { border-top: 0; }
+  article img { width: 64px; height: 64px; object-fit: contain; flex: none; }
+  .savings { color: #3a7d2c; font-weight: 600; }
+  .bogo { background: #8e2c8e; color: #fff; border-radius: 4px; font-size: 12px; padding: 0 4px; }
+  small { color: #777; display: block; }
+  ul { padding-left: 20px; }
+  footer { text-align: center; color: #999; font-size: 12px; margin: 16px 0; }
+`
+
+var indexTemplate = template.Must(template.New("index").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Publix weekly deals</title>
+<style>` + pageStyle + `</style>
+</head>
+<body>
+<main>
+<header>
+  <h1>Publix weekly deals</h1>
+  <p>{{len .Summaries}} store(s)</p>
+</header>
+<section>
+<ul>
+{{- range .Summaries}}
+  <li><a href="stores/{{.Number}}/">{{storeLabel .StoreJSON}}</a> — {{.Deals}} deals, {{.BogoDeals}} BOGO{{if .Address}}<small>{{.Address}}</small>{{end}}</li>
+{{- end}}
+</ul>
+<p><small>JSON: <a href="api/stores.json">api/stores.json</a></small></p>
+</section>
+<footer>Generated {{.GeneratedAt.Format "Jan 2, 2006 3:04 PM MST"}} by pubcli. Not affiliated with Publix.</footer>
+</main>
+</body>
+</html>
+`))
+
+var storeTemplate = template.Must(template.New("store").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{storeLabel .Store}} weekly deals</title>
+<link rel="alternate" type="application/rss+xml" title="{{storeLabel .Store}}" href="feed.xml">
+<style>` + pageStyle + `</style>
+</head>
+<body>
+<main>
+<header>
+  <h1>{{storeLabel .Store}}</h1>
+  {{- if .Store.Address}}
+  <p>{{.Store.Address}}</p>
+  {{- end}}
+  <p>{{if .Week}}Weekly ad {{.Week}} · {{end}}{{.Count}} deals</p>
+  <p><a href="../../">All stores</a> · <a href="feed.xml">RSS</a> · <a href="../../api/stores/{{.Store.Number}}/deals.json">JSON</a></p>
+</header>
+{{- range .Groups}}
+<section>
+  <h2>{{.Department}}</h2>
+  {{- range .Deals}}
+  <article>
+    {{- if .ImageURL}}<img src="{{.ImageURL}}" alt="" loading="lazy">{{end}}
+    <div>
+      <strong>{{.Title}}</strong>{{if .IsBogo}} <span class="bogo">BOGO</span>{{end}}
+      {{- if .Savings}}<div class="savings">{{.Savings}}</div>{{end}}
+      {{- if .Description}}<small>{{.Description}}</small>{{end}}
+      {{- if .ValidTo}}<small>Through {{.ValidTo}}</small>{{end}}
+    </div>
+  </article>
+  {{- end}}
+</section>
+{{- end}}
+<footer>Generated {{.GeneratedAt.Format "Jan 2, 2006 3:04 PM MST"}} by pubcli. Not affiliated with Publix.</footer>
+</main>
+</body>
+</html>
+`))
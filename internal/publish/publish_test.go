@@ -0,0 +1,83 @@
+package publish_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/publish"
+)
+
+func ptr(s string) *string { return &s }
+
+func TestWrite_GeneratesSite(t *testing.T) {
+	dir := t.TempDir()
+	site := publish.Site{
+		BaseURL:       "https://me.github.io/deals/",
+		GeneratedAt:   time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		SchemaVersion: display.LatestSchemaVersion,
+		Stores: []publish.StoreAd{{
+			Store: display.StoreJSON{Number: "1425", Name: "Peachers Mill", Address: "1490 Tiny Town Rd, Clarksville, TN 37042"},
+			Deals: []api.SavingItem{
+				{ID: "1", Title: ptr("Publix Coffee"), Savings: ptr("Buy 1 Get 1 FREE"), Department: ptr("Grocery"),
+					Categories: []string{"bogo"}, StartFormatted: "10/15", EndFormatted: "10/21"},
+				{ID: "2", Title: ptr("&lt;script&gt;Bananas&lt;/script&gt;"), Department: ptr("Produce"), StartFormatted: "10/15", EndFormatted: "10/21"},
+			},
+		}},
+	}
+	require.NoError(t, publish.Write(dir, site))
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		require.NoError(t, err, name)
+		return string(data)
+	}
+
+	index := read("index.html")
+	assert.Contains(t, index, `<a href="stores/1425/">Publix #1425 — Peachers Mill</a> — 2 deals, 1 BOGO`)
+	assert.FileExists(t, filepath.Join(dir, ".nojekyll"))
+
+	page := read("stores/1425/index.html")
+	assert.Contains(t, page, "Weekly ad 10/15 – 10/21 · 2 deals")
+	assert.Contains(t, page, "&lt;script&gt;Bananas&lt;/script&gt;")
+	assert.Less(t, strings.Index(page, "<h2>Grocery</h2>"), strings.Index(page, "<h2>Produce</h2>"))
+
+	var stores struct {
+		SchemaVersion int `json:"schemaVersion"`
+		Stores        []struct {
+			Number    string `json:"number"`
+			Deals     int    `json:"deals"`
+			BogoDeals int    `json:"bogoDeals"`
+			DealsURL  string `json:"dealsUrl"`
+		} `json:"stores"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(read("api/stores.json")), &stores))
+	require.Len(t, stores.Stores, 1)
+	assert.Equal(t, 2, stores.Stores[0].Deals)
+	assert.Equal(t, 1, stores.Stores[0].BogoDeals)
+	assert.Contains(t, read(stores.Stores[0].DealsURL), `"title":"Publix Coffee"`)
+
+	var feed struct {
+		Channel struct {
+			Link  string `xml:"link"`
+			Items []struct {
+				Title       string `xml:"title"`
+				Description string `xml:"description"`
+				GUID        string `xml:"guid"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(read("stores/1425/feed.xml")), &feed))
+	assert.Equal(t, "https://me.github.io/deals/stores/1425/", feed.Channel.Link)
+	require.Len(t, feed.Channel.Items, 2)
+	assert.Equal(t, "Buy 1 Get 1 FREE — through 10/21", feed.Channel.Items[0].Description)
+	assert.NotEqual(t, feed.Channel.Items[0].GUID, feed.Channel.Items[1].GUID)
+}
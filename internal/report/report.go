@@ -0,0 +1,304 @@
+// Package report composes the weekly summary of watched items: what changed
+// since the last ad, what matches alert rules, what is about to expire, and
+// which watched deals are the best seen so far.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/alert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+// Input is everything a report is built from.
+type Input struct {
+	Store string
+	// StoreName labels the store, e.g. "#1425 — Peachers Mill (Clarksville, TN)".
+	StoreName string
+	Updated   string
+	Deals     []api.SavingItem
+	Rules     []alert.Rule
+	// History holds earlier ad cycles, oldest first, without the current one.
+	History      []history.Snapshot
+	EndingWithin time.Duration
+	Now          time.Time
+}
+
+// Weekly is one consolidated report.
+type Weekly struct {
+	Store       string    `json:"store"`
+	StoreName   string    `json:"storeName,omitempty"`
+	Updated     string    `json:"updated,omitempty"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	// ComparedTo is when the previous ad cycle was saved; it is empty on
+	// the first run, when there is nothing to compare with.
+	ComparedTo *time.Time    `json:"comparedTo,omitempty"`
+	Deals      int           `json:"deals"`
+	BogoDeals  int           `json:"bogoDeals"`
+	Watch      []WatchChange `json:"watch"`
+	Matches    []alert.Match `json:"matches"`
+	EndingSoon []EndingDeal  `json:"endingSoon"`
+	BestPrices []BestPrice   `json:"bestPrices"`
+}
+
+// WatchChange lists a rule's deals that appeared or disappeared since the
+// previous ad cycle.
+type WatchChange struct {
+	Rule string             `json:"rule"`
+	New  []display.DealJSON `json:"new"`
+	Gone []display.DealJSON `json:"gone"`
+}
+
+// EndingDeal is a deal that expires within the report's window.
+type EndingDeal struct {
+	display.DealJSON
+	EndsAt time.Time `json:"endsAt"`
+}
+
+// BestPrice flags a watched deal whose savings match or beat every earlier
+// cycle it appeared in. Scores are filter.DealScore values.
+type BestPrice struct {
+	Rule              string           `json:"rule"`
+	Deal              display.DealJSON `json:"deal"`
+	Score             float64          `json:"score"`
+	PreviousBestScore float64          `json:"previousBestScore"`
+	// Weeks is the number of earlier cycles the deal appeared in.
+	Weeks int `json:"weeks"`
+}
+
+// Build composes the report.
+func Build(in Input) Weekly {
+	r := Weekly{
+		Store:       in.Store,
+		StoreName:   in.StoreName,
+		Updated:     in.Updated,
+		GeneratedAt: in.Now.UTC(),
+		Deals:       len(in.Deals),
+		Watch:       []WatchChange{},
+		Matches:     alert.Evaluate(in.Rules, in.Deals),
+		EndingSoon:  []EndingDeal{},
+		BestPrices:  []BestPrice{},
+	}
+	if r.Matches == nil {
+		r.Matches = []alert.Match{}
+	}
+	for _, item := range in.Deals {
+		if filter.ContainsIgnoreCase(item.Categories, "bogo") {
+			r.BogoDeals++
+		}
+	}
+
+	if n := len(in.History); n > 0 {
+		previous := in.History[n-1]
+		savedAt := previous.SavedAt.UTC()
+		r.ComparedTo = &savedAt
+		r.Watch = watchChanges(in.Rules, previous.Deals, in.Deals)
+	}
+	r.EndingSoon = endingSoon(in.Deals, in.Now, in.EndingWithin)
+	r.BestPrices = bestPrices(in.Rules, in.Deals, in.History)
+	return r
+}
+
+// dealKey identifies a deal across cycles, whose IDs change every week.
+func dealKey(item api.SavingItem) string {
+	return strings.ToLower(filter.Title(item))
+}
+
+func watchChanges(rules []alert.Rule, previous, current []api.SavingItem) []WatchChange {
+	changes := []WatchChange{}
+	for _, rule := range rules {
+		before := matching(rule, previous)
+		after := matching(rule, current)
+		change := WatchChange{Rule: rule.Name, New: []display.DealJSON{}, Gone: []display.DealJSON{}}
+		for key, item := range after.byKey {
+			if _, ok := before.byKey[key]; !ok {
+				change.New = append(change.New, display.ToDealJSON(item))
+			}
+		}
+		for key, item := range before.byKey {
+			if _, ok := after.byKey[key]; !ok {
+				change.Gone = append(change.Gone, display.ToDealJSON(item))
+			}
+		}
+		if len(change.New) == 0 && len(change.Gone) == 0 {
+			continue
+		}
+		sortDeals(change.New)
+		sortDeals(change.Gone)
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+type matchSet struct {
+	byKey map[string]api.SavingItem
+}
+
+func matching(rule alert.Rule, items []api.SavingItem) matchSet {
+	set := matchSet{byKey: map[string]api.SavingItem{}}
+	for _, item := range items {
+		if rule.Matches(item) {
+			set.byKey[dealKey(item)] = item
+		}
+	}
+	return set
+}
+
+func endingSoon(items []api.SavingItem, now time.Time, within time.Duration) []EndingDeal {
+	deals := []EndingDeal{}
+	if within <= 0 {
+		return deals
+	}
+	for _, item := range items {
+		end, ok := filter.EndsAt(item, now)
+		if !ok || end.Before(now) || end.Sub(now) > within {
+			continue
+		}
+		deals = append(deals, EndingDeal{DealJSON: display.ToDealJSON(item), EndsAt: end})
+	}
+	sort.SliceStable(deals, func(i, j int) bool { return deals[i].EndsAt.Before(deals[j].EndsAt) })
+	return deals
+}
+
+func bestPrices(rules []alert.Rule, current []api.SavingItem, past []history.Snapshot) []BestPrice {
+	flags := []BestPrice{}
+	if len(past) == 0 {
+		return flags
+	}
+	for _, rule := range rules {
+		for _, item := range matching(rule, current).sorted() {
+			key := dealKey(item)
+			score := filter.DealScore(item)
+			best, weeks := 0.0, 0
+			for _, snapshot := range past {
+				seen := false
+				for _, old := range snapshot.Deals {
+					if dealKey(old) != key {
+						continue
+					}
+					seen = true
+					if s := filter.DealScore(old); s > best {
+						best = s
+					}
+				}
+				if seen {
+					weeks++
+				}
+			}
+			if weeks > 0 && score >= best {
+				flags = append(flags, BestPrice{
+					Rule: rule.Name, Deal: display.ToDealJSON(item),
+					Score: score, PreviousBestScore: best, Weeks: weeks,
+				})
+			}
+		}
+	}
+	return flags
+}
+
+func (s matchSet) sorted() []api.SavingItem {
+	keys := make([]string, 0, len(s.byKey))
+	for key := range s.byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	items := make([]api.SavingItem, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, s.byKey[key])
+	}
+	return items
+}
+
+func sortDeals(deals []display.DealJSON) {
+	sort.Slice(deals, func(i, j int) bool { return strings.ToLower(deals[i].Title) < strings.ToLower(deals[j].Title) })
+}
+
+// Write renders the report as plain text, or as Markdown when markdown is
+// set, for email bodies and chat.
+func Write(w io.Writer, r Weekly, markdown bool) {
+	heading := func(text string) {
+		if markdown {
+			fmt.Fprintf(w, "\n## %s\n\n", text)
+		} else {
+			fmt.Fprintf(w, "\n%s\n%s\n", text, strings.Repeat("-", len(text)))
+		}
+	}
+	bullet := func(format string, args ...any) {
+		fmt.Fprintf(w, "- "+format+"\n", args...)
+	}
+
+	store := "Publix #" + r.Store
+	if r.StoreName != "" {
+		store = "Publix " + r.StoreName
+	}
+	if markdown {
+		fmt.Fprintf(w, "# Weekly report: %s\n\n", store)
+	} else {
+		fmt.Fprintf(w, "Weekly report: %s\n", store)
+	}
+	fmt.Fprintf(w, "%d deals, %d BOGO. Generated %s.\n", r.Deals, r.BogoDeals, r.GeneratedAt.Format("Mon Jan 2 2006 15:04 MST"))
+
+	heading("Watch list changes")
+	switch {
+	case r.ComparedTo == nil:
+		fmt.Fprintln(w, "First report for this store; changes are tracked from next cycle.")
+	case len(r.Watch) == 0:
+		fmt.Fprintf(w, "No changes since %s.\n", r.ComparedTo.Format("Jan 2"))
+	}
+	for _, change := range r.Watch {
+		for _, d := range change.New {
+			bullet("%s: new — %s", change.Rule, dealLine(d, markdown))
+		}
+		for _, d := range change.Gone {
+			bullet("%s: no longer on sale — %s", change.Rule, emphasize(d.Title, markdown))
+		}
+	}
+
+	heading("Alert matches")
+	if len(r.Matches) == 0 {
+		fmt.Fprintln(w, "No watched items are on sale.")
+	}
+	for _, m := range r.Matches {
+		for _, d := range m.Deals {
+			bullet("%s: %s", m.Rule, dealLine(d, markdown))
+		}
+	}
+
+	heading("Ending soon")
+	if len(r.EndingSoon) == 0 {
+		fmt.Fprintln(w, "Nothing expires soon.")
+	}
+	for _, d := range r.EndingSoon {
+		bullet("%s — ends %s", dealLine(d.DealJSON, markdown), d.EndsAt.Add(-time.Second).Format("Mon Jan 2"))
+	}
+
+	heading("Best prices")
+	if len(r.BestPrices) == 0 {
+		fmt.Fprintln(w, "No watched deal beats its earlier weeks.")
+	}
+	for _, b := range r.BestPrices {
+		bullet("%s: %s — best in %d earlier week(s)", b.Rule, dealLine(b.Deal, markdown), b.Weeks)
+	}
+}
+
+func dealLine(d display.DealJSON, markdown bool) string {
+	line := emphasize(d.Title, markdown)
+	if d.Savings != "" {
+		line += " (" + d.Savings + ")"
+	}
+	return line
+}
+
+func emphasize(text string, markdown bool) string {
+	if markdown {
+		return "**" + text + "**"
+	}
+	return text
+}
@@ -0,0 +1,85 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/alert"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/report"
+)
+
+func ptr(s string) *string { return &s }
+
+func deal(title, savings, end string, categories ...string) api.SavingItem {
+	return api.SavingItem{Title: ptr(title), Savings: ptr(savings), Categories: categories, StartFormatted: "10/15", EndFormatted: end}
+}
+
+func TestBuild_ComposesSections(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	rules := []alert.Rule{{Name: "coffee", Keywords: []string{"coffee"}}, {Name: "beef", Keywords: []string{"beef"}}}
+	past := []history.Snapshot{
+		{Store: "1425", Updated: "w1", SavedAt: now.AddDate(0, 0, -14), Deals: []api.SavingItem{deal("Publix Coffee", "Save $3.00", "10/7")}},
+		{Store: "1425", Updated: "w2", SavedAt: now.AddDate(0, 0, -7), Deals: []api.SavingItem{
+			deal("Publix Coffee", "Save $1.00", "10/14"),
+			deal("Ground Beef", "Save $2.00", "10/14"),
+		}},
+	}
+	current := []api.SavingItem{
+		deal("Publix Coffee", "Buy 1 Get 1 FREE", "10/21", "bogo"),
+		deal("Espresso Coffee Beans", "Save $1.50", "10/20"),
+		deal("Bananas", "Save $0.20", "10/25"),
+	}
+
+	r := report.Build(report.Input{
+		Store: "1425", Updated: "w3", Deals: current, Rules: rules, History: past,
+		EndingWithin: 48 * time.Hour, Now: now,
+	})
+
+	assert.Equal(t, 3, r.Deals)
+	assert.Equal(t, 1, r.BogoDeals)
+	require.NotNil(t, r.ComparedTo)
+
+	require.Len(t, r.Watch, 2)
+	assert.Equal(t, "coffee", r.Watch[0].Rule)
+	require.Len(t, r.Watch[0].New, 1)
+	assert.Equal(t, "Espresso Coffee Beans", r.Watch[0].New[0].Title)
+	assert.Empty(t, r.Watch[0].Gone)
+	require.Len(t, r.Watch[1].Gone, 1)
+	assert.Equal(t, "Ground Beef", r.Watch[1].Gone[0].Title)
+
+	require.Len(t, r.Matches, 1)
+	assert.Len(t, r.Matches[0].Deals, 2)
+
+	require.Len(t, r.EndingSoon, 1)
+	assert.Equal(t, "Espresso Coffee Beans", r.EndingSoon[0].Title)
+	assert.Equal(t, time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC), r.EndingSoon[0].EndsAt)
+
+	require.Len(t, r.BestPrices, 1)
+	assert.Equal(t, "Publix Coffee", r.BestPrices[0].Deal.Title)
+	assert.Equal(t, 2, r.BestPrices[0].Weeks)
+	assert.Greater(t, r.BestPrices[0].Score, r.BestPrices[0].PreviousBestScore)
+
+	var md bytes.Buffer
+	report.Write(&md, r, true)
+	assert.Contains(t, md.String(), "# Weekly report: Publix #1425")
+	assert.Contains(t, md.String(), "- coffee: new — **Espresso Coffee Beans** (Save $1.50)")
+	assert.Contains(t, md.String(), "- beef: no longer on sale — **Ground Beef**")
+	assert.Contains(t, md.String(), "ends Tue Oct 20")
+}
+
+func TestBuild_FirstRunHasNoComparison(t *testing.T) {
+	r := report.Build(report.Input{Store: "1425", Now: time.Now(), Deals: []api.SavingItem{deal("Publix Coffee", "Save $1.00", "")}})
+	assert.Nil(t, r.ComparedTo)
+	assert.Empty(t, r.Watch)
+	assert.Empty(t, r.BestPrices)
+	assert.NotNil(t, r.Matches)
+
+	var text bytes.Buffer
+	report.Write(&text, r, false)
+	assert.Contains(t, text.String(), "First report for this store")
+}
@@ -0,0 +1,185 @@
+package schedule
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+)
+
+// Systemd installs a user service and timer pair in Dir, usually
+// ~/.config/systemd/user.
+type Systemd struct {
+	Dir string
+}
+
+func (Systemd) Name() string { return "systemd" }
+
+func (s Systemd) unit(job Job) string { return "pubcli-" + job.Name }
+
+func (s Systemd) InstallPlan(job Job, executable string) Plan {
+	unit := s.unit(job)
+	words := make([]string, 0, len(job.Args)+1)
+	for _, arg := range append([]string{executable}, job.Args...) {
+		words = append(words, systemdQuote(arg))
+	}
+	service := fmt.Sprintf(`[Unit]
+Description=pubcli %s
+
+[Service]
+Type=oneshot
+ExecStart=%s
+`, strings.ReplaceAll(strings.Join(job.Args, " "), "%", "%%"), strings.Join(words, " "))
+
+	timer := fmt.Sprintf(`[Unit]
+Description=Run pubcli %s %s
+
+[Timer]
+OnCalendar=%s
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, job.Name, job.Describe(), systemdCalendar(job))
+
+	return Plan{
+		Write: []File{
+			{Path: filepath.Join(s.Dir, unit+".service"), Content: service},
+			{Path: filepath.Join(s.Dir, unit+".timer"), Content: timer},
+		},
+		After: [][]string{
+			{"systemctl", "--user", "daemon-reload"},
+			{"systemctl", "--user", "enable", "--now", unit + ".timer"},
+		},
+	}
+}
+
+func (s Systemd) RemovePlan(job Job) Plan {
+	unit := s.unit(job)
+	return Plan{
+		Before: [][]string{{"systemctl", "--user", "disable", "--now", unit + ".timer"}},
+		Remove: []string{filepath.Join(s.Dir, unit+".service"), filepath.Join(s.Dir, unit+".timer")},
+		After:  [][]string{{"systemctl", "--user", "daemon-reload"}},
+	}
+}
+
+func systemdCalendar(job Job) string {
+	hour, minute, _ := job.clock()
+	switch job.Frequency {
+	case Hourly:
+		return fmt.Sprintf("*-*-* *:%02d:00", minute)
+	case Daily:
+		return fmt.Sprintf("*-*-* %02d:%02d:00", hour, minute)
+	default:
+		return fmt.Sprintf("%s *-*-* %02d:%02d:00", strings.ToUpper(job.Day[:1])+job.Day[1:], hour, minute)
+	}
+}
+
+// systemdQuote quotes a word for ExecStart, where % starts a specifier.
+func systemdQuote(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\;") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+// Launchd installs a user agent in Dir, usually ~/Library/LaunchAgents.
+type Launchd struct {
+	Dir string
+}
+
+func (Launchd) Name() string { return "launchd" }
+
+func (l Launchd) label(job Job) string { return "com.pubcli." + job.Name }
+
+func (l Launchd) path(job Job) string { return filepath.Join(l.Dir, l.label(job)+".plist") }
+
+func (l Launchd) InstallPlan(job Job, executable string) Plan {
+	var args strings.Builder
+	for _, arg := range append([]string{executable}, job.Args...) {
+		fmt.Fprintf(&args, "\t\t<string>%s</string>\n", html.EscapeString(arg))
+	}
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>StartCalendarInterval</key>
+	<dict>
+%s	</dict>
+</dict>
+</plist>
+`, l.label(job), args.String(), launchdInterval(job))
+
+	path := l.path(job)
+	return Plan{
+		// Unloading first lets a reinstall pick up the new file.
+		Before: [][]string{{"launchctl", "unload", path}},
+		Write:  []File{{Path: path, Content: plist}},
+		After:  [][]string{{"launchctl", "load", "-w", path}},
+	}
+}
+
+func (l Launchd) RemovePlan(job Job) Plan {
+	path := l.path(job)
+	return Plan{
+		Before: [][]string{{"launchctl", "unload", "-w", path}},
+		Remove: []string{path},
+	}
+}
+
+func launchdInterval(job Job) string {
+	hour, minute, _ := job.clock()
+	entry := func(key string, value int) string {
+		return fmt.Sprintf("\t\t<key>%s</key>\n\t\t<integer>%d</integer>\n", key, value)
+	}
+	switch job.Frequency {
+	case Hourly:
+		return entry("Minute", minute)
+	case Daily:
+		return entry("Hour", hour) + entry("Minute", minute)
+	default:
+		return entry("Weekday", int(weekdays[job.Day])) + entry("Hour", hour) + entry("Minute", minute)
+	}
+}
+
+// TaskScheduler registers a task under \pubcli\ with schtasks.exe.
+type TaskScheduler struct{}
+
+func (TaskScheduler) Name() string { return "schtasks" }
+
+func (TaskScheduler) task(job Job) string { return `\pubcli\` + job.Name }
+
+func (t TaskScheduler) InstallPlan(job Job, executable string) Plan {
+	words := make([]string, 0, len(job.Args)+1)
+	for _, arg := range append([]string{executable}, job.Args...) {
+		words = append(words, windowsQuote(arg))
+	}
+	hour, minute, _ := job.clock()
+	create := []string{"schtasks", "/Create", "/F", "/TN", t.task(job), "/TR", strings.Join(words, " ")}
+	switch job.Frequency {
+	case Hourly:
+		create = append(create, "/SC", "HOURLY", "/ST", fmt.Sprintf("00:%02d", minute))
+	case Daily:
+		create = append(create, "/SC", "DAILY", "/ST", fmt.Sprintf("%02d:%02d", hour, minute))
+	default:
+		create = append(create, "/SC", "WEEKLY", "/D", strings.ToUpper(job.Day), "/ST", fmt.Sprintf("%02d:%02d", hour, minute))
+	}
+	return Plan{After: [][]string{create}}
+}
+
+func (t TaskScheduler) RemovePlan(job Job) Plan {
+	return Plan{After: [][]string{{"schtasks", "/Delete", "/F", "/TN", t.task(job)}}}
+}
+
+func windowsQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"") {
+		return arg
+	}
+	return `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+}
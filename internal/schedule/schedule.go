@@ -0,0 +1,308 @@
+// Package schedule installs pubcli commands as recurring jobs in the
+// operating system's scheduler: systemd user timers on Linux, launchd agents
+// on macOS, and Task Scheduler on Windows.
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RegistryFile is the list of installed jobs inside the data directory.
+const RegistryFile = "schedules.json"
+
+// Frequencies.
+const (
+	Hourly = "hourly"
+	Daily  = "daily"
+	Weekly = "weekly"
+)
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Job is one scheduled pubcli invocation.
+type Job struct {
+	Name string `json:"name"`
+	// Args are the pubcli arguments, without the executable.
+	Args      []string `json:"args"`
+	Frequency string   `json:"frequency"`
+	// Day is the weekday of weekly jobs: sun, mon, ... sat.
+	Day string `json:"day,omitempty"`
+	// At is the time of day, HH:MM; hourly jobs use only the minutes.
+	At          string    `json:"at"`
+	Backend     string    `json:"backend"`
+	Files       []string  `json:"files,omitempty"`
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// Validate normalizes the job's day and checks every field.
+func (j *Job) Validate() error {
+	if !validName.MatchString(j.Name) {
+		return fmt.Errorf("invalid job name %q: use lowercase letters, digits, and dashes", j.Name)
+	}
+	if len(j.Args) == 0 {
+		return errors.New("no pubcli command to schedule")
+	}
+	switch j.Frequency {
+	case Hourly, Daily:
+		j.Day = ""
+	case Weekly:
+		j.Day = strings.ToLower(strings.TrimSpace(j.Day))
+		if len(j.Day) > 3 {
+			j.Day = j.Day[:3]
+		}
+		if _, ok := weekdays[j.Day]; !ok {
+			return fmt.Errorf("invalid day %q: use a weekday like wed", j.Day)
+		}
+	default:
+		return fmt.Errorf("invalid frequency %q", j.Frequency)
+	}
+	if _, _, err := j.clock(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Describe is a human summary of when the job runs.
+func (j Job) Describe() string {
+	hour, minute, _ := j.clock()
+	switch j.Frequency {
+	case Hourly:
+		return fmt.Sprintf("hourly at :%02d", minute)
+	case Daily:
+		return fmt.Sprintf("daily at %02d:%02d", hour, minute)
+	default:
+		return fmt.Sprintf("weekly on %s at %02d:%02d", weekdays[j.Day], hour, minute)
+	}
+}
+
+func (j Job) clock() (hour, minute int, err error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(j.At), ":")
+	hour, errH := strconv.Atoi(h)
+	minute, errM := strconv.Atoi(m)
+	if !ok || errH != nil || errM != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time %q: use HH:MM, e.g. 09:00", j.At)
+	}
+	return hour, minute, nil
+}
+
+// SplitArgs splits a command line into arguments the way a POSIX shell
+// would for simple cases: whitespace separates words, quotes group them, and
+// a backslash escapes the next character outside single quotes.
+func SplitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// File is a file a plan writes.
+type File struct {
+	Path    string
+	Content string
+}
+
+// Plan is what installing or removing a job does, so it can be shown with
+// --dry-run before anything changes.
+type Plan struct {
+	// Before runs first; its errors are ignored, since stopping a job that
+	// is not loaded is not a failure.
+	Before [][]string
+	Write  []File
+	Remove []string
+	After  [][]string
+}
+
+// Runner runs an external command.
+type Runner func(ctx context.Context, name string, args ...string) error
+
+// Exec runs a command and reports its output on failure.
+func Exec(ctx context.Context, name string, args ...string) error {
+	var out bytes.Buffer
+	c := exec.CommandContext(ctx, name, args...)
+	c.Stdout, c.Stderr = &out, &out
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Apply carries out a plan.
+func (p Plan) Apply(ctx context.Context, run Runner) error {
+	for _, c := range p.Before {
+		_ = run(ctx, c[0], c[1:]...)
+	}
+	for _, f := range p.Write {
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+			return fmt.Errorf("creating %s: %w", filepath.Dir(f.Path), err)
+		}
+		if err := os.WriteFile(f.Path, []byte(f.Content), 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", f.Path, err)
+		}
+	}
+	for _, path := range p.Remove {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing %s: %w", path, err)
+		}
+	}
+	for _, c := range p.After {
+		if err := run(ctx, c[0], c[1:]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backend generates plans for one scheduler.
+type Backend interface {
+	Name() string
+	InstallPlan(job Job, executable string) Plan
+	RemovePlan(job Job) Plan
+}
+
+// ForOS returns the backend for an operating system (runtime.GOOS).
+func ForOS(goos string) (Backend, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("locating home directory: %w", err)
+	}
+	switch goos {
+	case "linux":
+		dir := filepath.Join(home, ".config", "systemd", "user")
+		if base := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); base != "" {
+			dir = filepath.Join(base, "systemd", "user")
+		}
+		return Systemd{Dir: dir}, nil
+	case "darwin":
+		return Launchd{Dir: filepath.Join(home, "Library", "LaunchAgents")}, nil
+	case "windows":
+		return TaskScheduler{}, nil
+	default:
+		return nil, fmt.Errorf("scheduling is not supported on %s; use cron instead", goos)
+	}
+}
+
+// Registry records installed jobs, since the schedulers themselves have no
+// common way to list them.
+type Registry struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// LoadRegistry reads the registry at path. A missing file is empty.
+func LoadRegistry(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Registry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading schedules: %w", err)
+	}
+	r := &Registry{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("parsing schedules %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Save writes the registry to path, replacing the previous file atomically.
+func (r *Registry) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing schedules: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Find returns the job with the given name.
+func (r *Registry) Find(name string) (Job, bool) {
+	for _, j := range r.Jobs {
+		if j.Name == name {
+			return j, true
+		}
+	}
+	return Job{}, false
+}
+
+// Set adds a job, replacing any job with the same name.
+func (r *Registry) Set(job Job) {
+	for i, existing := range r.Jobs {
+		if existing.Name == job.Name {
+			r.Jobs[i] = job
+			return
+		}
+	}
+	r.Jobs = append(r.Jobs, job)
+}
+
+// Remove deletes the job with the given name.
+func (r *Registry) Remove(name string) bool {
+	for i, existing := range r.Jobs {
+		if existing.Name == name {
+			r.Jobs = append(r.Jobs[:i], r.Jobs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package schedule_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/schedule"
+)
+
+func TestSplitArgs(t *testing.T) {
+	args, err := schedule.SplitArgs(`report --weekly  --query "ground beef" --name 'a "b"' x\ y`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"report", "--weekly", "--query", "ground beef", "--name", `a "b"`, "x y"}, args)
+
+	_, err = schedule.SplitArgs(`report "open`)
+	assert.Error(t, err)
+}
+
+func TestJobValidate(t *testing.T) {
+	job := schedule.Job{Name: "report", Args: []string{"report"}, Frequency: schedule.Weekly, Day: "Thursday", At: "18:30"}
+	require.NoError(t, job.Validate())
+	assert.Equal(t, "thu", job.Day)
+	assert.Equal(t, "weekly on Thursday at 18:30", job.Describe())
+
+	for _, bad := range []schedule.Job{
+		{Name: "Report!", Args: []string{"report"}, Frequency: schedule.Daily, At: "09:00"},
+		{Name: "report", Frequency: schedule.Daily, At: "09:00"},
+		{Name: "report", Args: []string{"report"}, Frequency: schedule.Daily, At: "25:00"},
+		{Name: "report", Args: []string{"report"}, Frequency: schedule.Weekly, Day: "someday", At: "09:00"},
+		{Name: "report", Args: []string{"report"}, Frequency: "monthly", At: "09:00"},
+	} {
+		assert.Error(t, bad.Validate(), "%+v", bad)
+	}
+}
+
+func TestBackendPlans(t *testing.T) {
+	job := schedule.Job{Name: "report", Args: []string{"report", "--query", "50% off"}, Frequency: schedule.Weekly, Day: "wed", At: "09:05"}
+	require.NoError(t, job.Validate())
+
+	systemd := schedule.Systemd{Dir: "/units"}.InstallPlan(job, "/usr/bin/pubcli")
+	require.Len(t, systemd.Write, 2)
+	assert.Contains(t, systemd.Write[0].Content, `ExecStart=/usr/bin/pubcli report --query "50%% off"`)
+	assert.Contains(t, systemd.Write[1].Content, "OnCalendar=Wed *-*-* 09:05:00")
+	assert.Equal(t, []string{"systemctl", "--user", "enable", "--now", "pubcli-report.timer"}, systemd.After[1])
+
+	launchd := schedule.Launchd{Dir: "/agents"}.InstallPlan(job, "/usr/local/bin/pubcli")
+	assert.Equal(t, "/agents/com.pubcli.report.plist", launchd.Write[0].Path)
+	assert.Contains(t, launchd.Write[0].Content, "<key>Weekday</key>\n\t\t<integer>3</integer>")
+	assert.Contains(t, launchd.Write[0].Content, "<string>50% off</string>")
+
+	tasks := schedule.TaskScheduler{}.InstallPlan(job, `C:\Program Files\pubcli.exe`)
+	assert.Equal(t, []string{"schtasks", "/Create", "/F", "/TN", `\pubcli\report`, "/TR", `"C:\Program Files\pubcli.exe" report --query "50% off"`,
+		"/SC", "WEEKLY", "/D", "WED", "/ST", "09:05"}, tasks.After[0])
+}
+
+func TestPlanApply(t *testing.T) {
+	dir := t.TempDir()
+	backend := schedule.Systemd{Dir: dir}
+	job := schedule.Job{Name: "status", Args: []string{"status"}, Frequency: schedule.Hourly, At: "00:15"}
+	require.NoError(t, job.Validate())
+
+	var ran []string
+	run := func(_ context.Context, name string, args ...string) error {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		if args[1] == "disable" {
+			return os.ErrNotExist // stopping an unloaded timer is not fatal
+		}
+		return nil
+	}
+
+	require.NoError(t, backend.InstallPlan(job, "pubcli").Apply(context.Background(), run))
+	timer, err := os.ReadFile(filepath.Join(dir, "pubcli-status.timer"))
+	require.NoError(t, err)
+	assert.Contains(t, string(timer), "OnCalendar=*-*-* *:15:00")
+
+	require.NoError(t, backend.RemovePlan(job).Apply(context.Background(), run))
+	assert.NoFileExists(t, filepath.Join(dir, "pubcli-status.timer"))
+	assert.NoFileExists(t, filepath.Join(dir, "pubcli-status.service"))
+	assert.Equal(t, []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable --now pubcli-status.timer",
+		"systemctl --user disable --now pubcli-status.timer",
+		"systemctl --user daemon-reload",
+	}, ran)
+}
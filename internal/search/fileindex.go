@@ -0,0 +1,168 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// IndexKey is the storage key of the inverted index used with backends
+// other than SQLite.
+const IndexKey = "search-index.json"
+
+// fileIndex is an inverted index kept as one JSON value: each word maps to
+// the deals, by snapshot and position, whose text contains it.
+type fileIndex struct {
+	backend storage.Backend
+	data    *fileIndexData
+}
+
+type fileIndexData struct {
+	// Docs numbers the indexed snapshot keys.
+	Docs map[string]int `json:"docs"`
+	Next int            `json:"next"`
+	// Terms maps a word to snapshot numbers to deal positions.
+	Terms map[string]map[int][]int `json:"terms"`
+}
+
+func (f *fileIndex) load() error {
+	if f.data != nil {
+		return nil
+	}
+	f.data = &fileIndexData{Docs: map[string]int{}, Terms: map[string]map[int][]int{}}
+	obj, err := f.backend.Get(IndexKey)
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading search index: %w", err)
+	}
+	var d fileIndexData
+	if err := json.Unmarshal(obj.Data, &d); err != nil || d.Docs == nil || d.Terms == nil {
+		// A damaged index is rebuilt from the history by Refresh.
+		return nil
+	}
+	f.data = &d
+	return nil
+}
+
+func (f *fileIndex) save() error {
+	data, err := json.Marshal(f.data)
+	if err != nil {
+		return err
+	}
+	if err := f.backend.Put(IndexKey, storage.Object{Data: data}); err != nil {
+		return fmt.Errorf("writing search index: %w", err)
+	}
+	return nil
+}
+
+func (f *fileIndex) Add(key string, s history.Snapshot) error {
+	if err := f.load(); err != nil {
+		return err
+	}
+	f.drop(key)
+	doc := f.data.Next
+	f.data.Next++
+	f.data.Docs[key] = doc
+	for i, item := range s.Deals {
+		for _, w := range Words(dealText(item)) {
+			docs := f.data.Terms[w]
+			if docs == nil {
+				docs = map[int][]int{}
+				f.data.Terms[w] = docs
+			}
+			if n := len(docs[doc]); n == 0 || docs[doc][n-1] != i {
+				docs[doc] = append(docs[doc], i)
+			}
+		}
+	}
+	return f.save()
+}
+
+func (f *fileIndex) Remove(keys ...string) error {
+	if err := f.load(); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		f.drop(key)
+	}
+	return f.save()
+}
+
+// drop forgets a snapshot without saving.
+func (f *fileIndex) drop(key string) {
+	doc, ok := f.data.Docs[key]
+	if !ok {
+		return
+	}
+	delete(f.data.Docs, key)
+	for w, docs := range f.data.Terms {
+		delete(docs, doc)
+		if len(docs) == 0 {
+			delete(f.data.Terms, w)
+		}
+	}
+}
+
+func (f *fileIndex) Keys() ([]string, error) {
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(f.data.Docs))
+	for key := range f.data.Docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (f *fileIndex) Lookup(words []string) ([]Posting, error) {
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	keyOf := make(map[int]string, len(f.data.Docs))
+	for key, doc := range f.data.Docs {
+		keyOf[doc] = key
+	}
+
+	var matched map[Posting]bool
+	for _, w := range words {
+		found := map[Posting]bool{}
+		for term, docs := range f.data.Terms {
+			if !strings.HasPrefix(term, w) {
+				continue
+			}
+			for doc, deals := range docs {
+				for _, i := range deals {
+					p := Posting{Key: keyOf[doc], Deal: i}
+					if matched == nil || matched[p] {
+						found[p] = true
+					}
+				}
+			}
+		}
+		matched = found
+		if len(matched) == 0 {
+			break
+		}
+	}
+
+	out := make([]Posting, 0, len(matched))
+	for p := range matched {
+		if p.Key != "" {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (f *fileIndex) Reset() error {
+	f.data = &fileIndexData{Docs: map[string]int{}, Terms: map[string]map[int][]int{}}
+	return f.save()
+}
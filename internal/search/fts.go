@@ -0,0 +1,121 @@
+package search
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/history"
+)
+
+// ftsSchema indexes one row per deal. deal_search_docs lists the snapshot
+// keys indexed, including ones without deals.
+const ftsSchema = `CREATE VIRTUAL TABLE IF NOT EXISTS deal_search USING fts5(
+	text,
+	key UNINDEXED,
+	deal UNINDEXED,
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+CREATE TABLE IF NOT EXISTS deal_search_docs (key TEXT PRIMARY KEY)`
+
+// fts is an FTS5 index in the SQLite storage database.
+type fts struct {
+	db *sql.DB
+}
+
+func openFTS(db *sql.DB) (*fts, error) {
+	if _, err := db.Exec(ftsSchema); err != nil {
+		return nil, fmt.Errorf("opening search index: %w", err)
+	}
+	return &fts{db: db}, nil
+}
+
+func (f *fts) Add(key string, s history.Snapshot) error {
+	tx, err := f.db.Begin()
+	if err != nil {
+		return fmt.Errorf("indexing %s: %w", key, err)
+	}
+	defer tx.Rollback()
+	if err := ftsDelete(tx, key); err != nil {
+		return err
+	}
+	for i, item := range s.Deals {
+		if _, err := tx.Exec(`INSERT INTO deal_search (text, key, deal) VALUES (?, ?, ?)`, dealText(item), key, i); err != nil {
+			return fmt.Errorf("indexing %s: %w", key, err)
+		}
+	}
+	if _, err := tx.Exec(`INSERT INTO deal_search_docs (key) VALUES (?)`, key); err != nil {
+		return fmt.Errorf("indexing %s: %w", key, err)
+	}
+	return tx.Commit()
+}
+
+func (f *fts) Remove(keys ...string) error {
+	tx, err := f.db.Begin()
+	if err != nil {
+		return fmt.Errorf("updating search index: %w", err)
+	}
+	defer tx.Rollback()
+	for _, key := range keys {
+		if err := ftsDelete(tx, key); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func ftsDelete(tx *sql.Tx, key string) error {
+	if _, err := tx.Exec(`DELETE FROM deal_search WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("updating search index: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM deal_search_docs WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("updating search index: %w", err)
+	}
+	return nil
+}
+
+func (f *fts) Keys() ([]string, error) {
+	rows, err := f.db.Query(`SELECT key FROM deal_search_docs ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("reading search index: %w", err)
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (f *fts) Lookup(words []string) ([]Posting, error) {
+	// Each word is a quoted prefix query; FTS5 joins them with AND.
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, `"`+strings.ReplaceAll(w, `"`, `""`)+`"*`)
+	}
+	rows, err := f.db.Query(`SELECT key, deal FROM deal_search WHERE deal_search MATCH ?`, strings.Join(terms, " "))
+	if err != nil {
+		return nil, fmt.Errorf("searching: %w", err)
+	}
+	defer rows.Close()
+	var out []Posting
+	for rows.Next() {
+		var p Posting
+		if err := rows.Scan(&p.Key, &p.Deal); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (f *fts) Reset() error {
+	if _, err := f.db.Exec(`DELETE FROM deal_search; DELETE FROM deal_search_docs`); err != nil {
+		return fmt.Errorf("clearing search index: %w", err)
+	}
+	return nil
+}
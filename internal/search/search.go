@@ -0,0 +1,169 @@
+// Package search indexes the deals in the ad history by word, so a search
+// across many weeks reads only the snapshots that match instead of every
+// one. With the SQLite storage backend the index is an FTS5 table in the
+// same database; otherwise it is an inverted index stored beside the
+// history.
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// Posting locates one deal: the snapshot's storage key and the deal's
+// position in it.
+type Posting struct {
+	Key  string
+	Deal int
+}
+
+// Index maps words in deal titles, descriptions, and brands to deals. It is
+// a history.Indexer, so an archive with it keeps it current.
+type Index interface {
+	history.Indexer
+	// Keys returns the snapshot keys indexed.
+	Keys() ([]string, error)
+	// Lookup returns the deals matching every word, each as a word prefix.
+	Lookup(words []string) ([]Posting, error)
+	// Reset empties the index.
+	Reset() error
+}
+
+// Open returns the index for a storage backend: FTS5 in a SQLite database,
+// an inverted index under IndexKey otherwise.
+func Open(b storage.Backend) (Index, error) {
+	if db, ok := b.(*storage.SQLite); ok {
+		return openFTS(db.DB())
+	}
+	return &fileIndex{backend: b}, nil
+}
+
+// Hit is one deal found in one saved ad.
+type Hit struct {
+	Store string
+	// Date is the day the ad was saved, as YYYY-MM-DD.
+	Date    string
+	Updated string
+	Deal    api.SavingItem
+}
+
+// Search finds the deals in the archive that match every word of query,
+// newest ad first, optionally only in one store's ads. It first brings the
+// index up to date with the archive, indexing snapshots it has not seen
+// and forgetting ones that are gone.
+func Search(a history.Archive, idx Index, query, store string) ([]Hit, error) {
+	words := Words(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+	if err := Refresh(a, idx); err != nil {
+		return nil, err
+	}
+	postings, err := idx.Lookup(words)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := map[string][]int{}
+	for _, p := range postings {
+		byKey[p.Key] = append(byKey[p.Key], p.Deal)
+	}
+	var hits []Hit
+	for key, deals := range byKey {
+		s, err := a.Load(key)
+		if err != nil {
+			return nil, err
+		}
+		if store != "" && s.Store != store {
+			continue
+		}
+		sort.Ints(deals)
+		for _, i := range deals {
+			if i < 0 || i >= len(s.Deals) {
+				continue
+			}
+			hits = append(hits, Hit{
+				Store:   s.Store,
+				Date:    s.SavedAt.Local().Format("2006-01-02"),
+				Updated: s.Updated,
+				Deal:    s.Deals[i],
+			})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Date != hits[j].Date {
+			return hits[i].Date > hits[j].Date
+		}
+		return hits[i].Store < hits[j].Store
+	})
+	return hits, nil
+}
+
+// Refresh indexes the archive's snapshots that idx has not seen and
+// removes the ones the archive no longer has.
+func Refresh(a history.Archive, idx Index) error {
+	stored, err := a.SnapshotKeys()
+	if err != nil {
+		return err
+	}
+	indexed, err := idx.Keys()
+	if err != nil {
+		return err
+	}
+	have := map[string]bool{}
+	for _, key := range indexed {
+		have[key] = true
+	}
+	for _, key := range stored {
+		if have[key] {
+			delete(have, key)
+			continue
+		}
+		s, err := a.Load(key)
+		if err != nil {
+			return err
+		}
+		if err := idx.Add(key, s); err != nil {
+			return err
+		}
+	}
+	if len(have) == 0 {
+		return nil
+	}
+	gone := make([]string, 0, len(have))
+	for key := range have {
+		gone = append(gone, key)
+	}
+	sort.Strings(gone)
+	return idx.Remove(gone...)
+}
+
+// Rebuild empties the index and indexes every snapshot again.
+func Rebuild(a history.Archive, idx Index) error {
+	if err := idx.Reset(); err != nil {
+		return err
+	}
+	return Refresh(a, idx)
+}
+
+// Words splits text into lowercase words of letters and digits.
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// dealText is the text of a deal that is indexed.
+func dealText(item api.SavingItem) string {
+	return strings.Join([]string{
+		filter.CleanText(filter.Deref(item.Title)),
+		filter.CleanText(filter.Deref(item.Description)),
+		filter.CleanText(filter.Deref(item.Brand)),
+	}, " ")
+}
@@ -0,0 +1,91 @@
+package search_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/search"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func ptr(s string) *string { return &s }
+
+func backends(t *testing.T) map[string]storage.Backend {
+	t.Helper()
+	db, err := storage.OpenSQLite(filepath.Join(t.TempDir(), storage.DBFile))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return map[string]storage.Backend{
+		"json":   &storage.Files{Dir: t.TempDir()},
+		"sqlite": db,
+	}
+}
+
+func titles(hits []search.Hit) []string {
+	var out []string
+	for _, h := range hits {
+		out = append(out, h.Date+" "+h.Store+" "+*h.Deal.Title)
+	}
+	return out
+}
+
+func TestSearch(t *testing.T) {
+	for name, b := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			idx, err := search.Open(b)
+			require.NoError(t, err)
+			archive := history.Archive{Backend: b}
+			day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.Local) }
+
+			// Saved before the index existed: Search indexes it first.
+			require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "a", SavedAt: day(1), Deals: []api.SavingItem{
+				{ID: "1", Title: ptr("Café Bustelo Coffee"), Description: ptr("10 oz bag")},
+				{ID: "2", Title: ptr("Tortilla Chips")},
+			}}))
+			archive.Index = idx
+			require.NoError(t, archive.Save(history.Snapshot{Store: "1425", Updated: "b", SavedAt: day(8), Deals: []api.SavingItem{
+				{ID: "3", Title: ptr("Iced Coffee"), Brand: ptr("Starbucks")},
+			}}))
+			require.NoError(t, archive.Save(history.Snapshot{Store: "1500", Updated: "b", SavedAt: day(8), Deals: []api.SavingItem{
+				{ID: "4", Title: ptr("Coffee Creamer")},
+			}}))
+
+			hits, err := search.Search(archive, idx, "coff", "")
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				"2026-10-08 1425 Iced Coffee",
+				"2026-10-08 1500 Coffee Creamer",
+				"2026-10-01 1425 Café Bustelo Coffee",
+			}, titles(hits))
+
+			hits, err = search.Search(archive, idx, "coffee bag", "1425")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"2026-10-01 1425 Café Bustelo Coffee"}, titles(hits))
+
+			hits, err = search.Search(archive, idx, "STARBUCKS", "")
+			require.NoError(t, err)
+			assert.Len(t, hits, 1)
+
+			// Pruned snapshots leave the index.
+			_, err = archive.Prune(day(5), false)
+			require.NoError(t, err)
+			hits, err = search.Search(archive, idx, "coffee", "1425")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"2026-10-08 1425 Iced Coffee"}, titles(hits))
+
+			require.NoError(t, search.Rebuild(archive, idx))
+			keys, err := idx.Keys()
+			require.NoError(t, err)
+			assert.Len(t, keys, 2)
+
+			hits, err = search.Search(archive, idx, "  ", "")
+			require.NoError(t, err)
+			assert.Empty(t, hits)
+		})
+	}
+}
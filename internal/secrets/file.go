@@ -0,0 +1,202 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// FileName is the encrypted secrets file's name inside the data
+	// directory.
+	FileName = "secrets.json"
+	// KeyFileName holds the random key of a secrets file without a
+	// passphrase.
+	KeyFileName = "secrets.key"
+
+	kdfPBKDF2  = "pbkdf2-sha256"
+	kdfKeyFile = "keyfile"
+	iterations = 210000
+)
+
+// File keeps secrets in an AES-GCM encrypted file in Dir. With a Passphrase
+// the key is derived from it; without one the key is random and kept in
+// KeyFileName beside the file, which keeps secrets out of the config file
+// but not from someone who can read the data directory.
+type File struct {
+	Dir        string
+	Passphrase string
+}
+
+// NewFile returns the encrypted file store in dir.
+func NewFile(dir, passphrase string) *File {
+	return &File{Dir: dir, Passphrase: passphrase}
+}
+
+type fileFormat struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt,omitempty"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+func (f *File) Name() string { return "encrypted file " + filepath.Join(f.Dir, FileName) }
+
+func (f *File) Get(name string) (string, error) {
+	values, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	v, ok := values[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (f *File) Set(name, value string) error {
+	values, err := f.load()
+	if err != nil {
+		return err
+	}
+	values[name] = value
+	return f.save(values)
+}
+
+func (f *File) Delete(name string) error {
+	values, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := values[name]; !ok {
+		return ErrNotFound
+	}
+	delete(values, name)
+	return f.save(values)
+}
+
+func (f *File) load() (map[string]string, error) {
+	path := filepath.Join(f.Dir, FileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets: %w", err)
+	}
+	var file fileFormat
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing secrets %s: %w", path, err)
+	}
+
+	var key []byte
+	switch file.KDF {
+	case kdfPBKDF2:
+		if f.Passphrase == "" {
+			return nil, fmt.Errorf("%s is encrypted with a passphrase, and none was given", path)
+		}
+		key, err = pbkdf2.Key(sha256.New, f.Passphrase, file.Salt, iterations, 32)
+	case kdfKeyFile:
+		key, err = os.ReadFile(filepath.Join(f.Dir, KeyFileName))
+	default:
+		return nil, fmt.Errorf("parsing secrets %s: unknown key derivation %q", path, file.KDF)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		if file.KDF == kdfPBKDF2 {
+			return nil, fmt.Errorf("decrypting %s: wrong passphrase", path)
+		}
+		return nil, fmt.Errorf("decrypting %s: the key in %s does not match", path, KeyFileName)
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, fmt.Errorf("parsing secrets %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func (f *File) save(values map[string]string) error {
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return fmt.Errorf("creating secrets directory: %w", err)
+	}
+	file := fileFormat{Version: 1}
+	var key []byte
+	var err error
+	if f.Passphrase != "" {
+		file.KDF = kdfPBKDF2
+		file.Salt = make([]byte, 16)
+		rand.Read(file.Salt)
+		key, err = pbkdf2.Key(sha256.New, f.Passphrase, file.Salt, iterations, 32)
+	} else {
+		file.KDF = kdfKeyFile
+		key, err = f.keyFile()
+	}
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	rand.Read(file.Nonce)
+	file.Data = gcm.Seal(nil, file.Nonce, plain, nil)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(f.Dir, FileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing secrets: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// keyFile returns the random key, creating it the first time.
+func (f *File) keyFile() ([]byte, error) {
+	path := filepath.Join(f.Dir, KeyFileName)
+	key, err := os.ReadFile(path)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading secrets key: %w", err)
+	}
+	key = make([]byte, 32)
+	rand.Read(key)
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		return nil, fmt.Errorf("writing secrets key: %w", err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secrets key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
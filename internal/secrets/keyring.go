@@ -0,0 +1,166 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Keyring returns the OS keyring, if this system has one pubcli can use.
+func Keyring() (Store, bool) {
+	switch runtime.GOOS {
+	case "darwin":
+		if path, err := exec.LookPath("security"); err == nil {
+			return &Keychain{run: execRunner(path)}, true
+		}
+	case "windows":
+		return wincred()
+	default:
+		if path, err := exec.LookPath("keyctl"); err == nil {
+			return &Keyctl{run: execRunner(path)}, true
+		}
+	}
+	return nil, false
+}
+
+// runner runs a keyring tool with args and stdin, returning its output and
+// exit code. A tool that cannot be started is an error.
+type runner func(stdin string, args ...string) (string, int, error)
+
+func execRunner(path string) runner {
+	return func(stdin string, args ...string) (string, int, error) {
+		cmd := exec.Command(path, args...)
+		cmd.Stdin = strings.NewReader(stdin)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		err := cmd.Run()
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return strings.TrimSpace(stderr.String()), exit.ExitCode(), nil
+		}
+		if err != nil {
+			return "", 0, err
+		}
+		return stdout.String(), 0, nil
+	}
+}
+
+// Keyctl keeps secrets in the Linux kernel's user keyring with keyctl(1).
+// The keyring lasts until the user's last session ends or the machine
+// reboots.
+type Keyctl struct {
+	run runner
+}
+
+func (k *Keyctl) Name() string { return "kernel keyring" }
+
+func (k *Keyctl) find(name string) (string, error) {
+	out, code, err := k.run("", "search", "@u", "user", service+":"+name)
+	if err != nil {
+		return "", err
+	}
+	if code != 0 {
+		return "", ErrNotFound
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (k *Keyctl) Get(name string) (string, error) {
+	id, err := k.find(name)
+	if err != nil {
+		return "", err
+	}
+	out, code, err := k.run("", "pipe", id)
+	if err != nil {
+		return "", err
+	}
+	if code != 0 {
+		return "", fmt.Errorf("keyctl pipe: %s", out)
+	}
+	return out, nil
+}
+
+func (k *Keyctl) Set(name, value string) error {
+	out, code, err := k.run(value, "padd", "user", service+":"+name, "@u")
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("keyctl padd: %s", out)
+	}
+	return nil
+}
+
+func (k *Keyctl) Delete(name string) error {
+	id, err := k.find(name)
+	if err != nil {
+		return err
+	}
+	out, code, err := k.run("", "unlink", id, "@u")
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("keyctl unlink: %s", out)
+	}
+	return nil
+}
+
+// keychainNotFound is the exit code of security(1) for a missing item.
+const keychainNotFound = 44
+
+// Keychain keeps secrets in the macOS login keychain with security(1), as
+// generic passwords of the "pubcli" service.
+type Keychain struct {
+	run runner
+}
+
+func (k *Keychain) Name() string { return "macOS Keychain" }
+
+func (k *Keychain) Get(name string) (string, error) {
+	out, code, err := k.run("", "find-generic-password", "-s", service, "-a", name, "-w")
+	if err != nil {
+		return "", err
+	}
+	switch code {
+	case 0:
+		return strings.TrimSuffix(out, "\n"), nil
+	case keychainNotFound:
+		return "", ErrNotFound
+	default:
+		return "", fmt.Errorf("security find-generic-password: %s", out)
+	}
+}
+
+// Set passes the secret as the value of -w, as other keychain libraries
+// do. Without a value, security(1) prompts for it on the controlling
+// terminal rather than reading stdin, and a secret read that way ends at
+// its first newline.
+func (k *Keychain) Set(name, value string) error {
+	out, code, err := k.run("", "add-generic-password", "-U", "-s", service, "-a", name, "-w", value)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("security add-generic-password: %s", out)
+	}
+	return nil
+}
+
+func (k *Keychain) Delete(name string) error {
+	out, code, err := k.run("", "delete-generic-password", "-s", service, "-a", name)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 0:
+		return nil
+	case keychainNotFound:
+		return ErrNotFound
+	default:
+		return fmt.Errorf("security delete-generic-password: %s", out)
+	}
+}
@@ -0,0 +1,34 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeychainSet_SecretIsTheValueOfW(t *testing.T) {
+	var gotStdin string
+	var gotArgs []string
+	k := &Keychain{run: func(stdin string, args ...string) (string, int, error) {
+		gotStdin, gotArgs = stdin, args
+		return "", 0, nil
+	}}
+
+	require.NoError(t, k.Set("telegram_bot_token", "s3cret\nvalue"))
+	assert.Equal(t, []string{"-w", "s3cret\nvalue"}, gotArgs[len(gotArgs)-2:], "a -w without a value makes security prompt")
+	assert.Empty(t, gotStdin)
+}
+
+func TestKeyctlSet_SecretOnStdin(t *testing.T) {
+	var gotStdin string
+	var gotArgs []string
+	k := &Keyctl{run: func(stdin string, args ...string) (string, int, error) {
+		gotStdin, gotArgs = stdin, args
+		return "", 0, nil
+	}}
+
+	require.NoError(t, k.Set("telegram_bot_token", "s3cret-value"))
+	assert.NotContains(t, gotArgs, "s3cret-value")
+	assert.Equal(t, "s3cret-value", gotStdin)
+}
@@ -0,0 +1,107 @@
+// Package secrets keeps tokens and passwords out of the config file. The
+// config names a secret as "secret:NAME", and the value lives in the OS
+// keyring or in an encrypted file in the data directory.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Prefix marks a config value that names a secret, e.g.
+// "secret:telegram-token".
+const Prefix = "secret:"
+
+// ErrNotFound is returned for a secret that is not stored.
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps named secrets.
+type Store interface {
+	// Name describes where the secrets are kept, e.g. "macOS Keychain".
+	Name() string
+	// Get returns the secret's value, or ErrNotFound.
+	Get(name string) (string, error)
+	// Set stores the secret, replacing any earlier value.
+	Set(name, value string) error
+	// Delete removes the secret, or returns ErrNotFound.
+	Delete(name string) error
+}
+
+// service is the keyring service, or target prefix, secrets are stored
+// under.
+const service = "pubcli"
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateName rejects names that cannot be used as keyring entries.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q (use letters, digits, '.', '_', and '-')", name)
+	}
+	return nil
+}
+
+// Ref returns the name of the secret a config value refers to.
+func Ref(value string) (string, bool) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(value), Prefix)
+	return name, ok
+}
+
+// Resolve returns value, or the secret it names when it is a reference.
+func Resolve(s Store, value string) (string, error) {
+	name, ok := Ref(value)
+	if !ok {
+		return value, nil
+	}
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	v, err := s.Get(name)
+	if errors.Is(err, ErrNotFound) {
+		return "", fmt.Errorf("secret %q is not stored in the %s", name, s.Name())
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading secret %q from the %s: %w", name, s.Name(), err)
+	}
+	return v, nil
+}
+
+// Backends are the values of the `secrets.backend` setting.
+const (
+	// BackendAuto uses the OS keyring on macOS and Windows and the
+	// encrypted file elsewhere, since the Linux kernel keyring does not
+	// survive a reboot.
+	BackendAuto = "auto"
+	// BackendKeyring uses the OS keyring: the Keychain on macOS, the
+	// Credential Manager on Windows, and keyctl's user keyring on Linux.
+	BackendKeyring = "keyring"
+	// BackendFile uses the encrypted file.
+	BackendFile = "file"
+)
+
+// Open returns the store a backend names. dataDir holds the encrypted file
+// and passphrase, if not empty, encrypts it; see File.
+func Open(backend, dataDir, passphrase string) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", BackendAuto:
+		if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+			if s, ok := Keyring(); ok {
+				return s, nil
+			}
+		}
+		return NewFile(dataDir, passphrase), nil
+	case BackendKeyring:
+		s, ok := Keyring()
+		if !ok {
+			return nil, fmt.Errorf("no OS keyring is available on %s", runtime.GOOS)
+		}
+		return s, nil
+	case BackendFile:
+		return NewFile(dataDir, passphrase), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets backend %q (use auto, keyring, or file)", backend)
+	}
+}
@@ -0,0 +1,82 @@
+package secrets_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/secrets"
+)
+
+func TestFile_KeyFile(t *testing.T) {
+	dir := t.TempDir()
+	store := secrets.NewFile(dir, "")
+
+	_, err := store.Get("telegram-token")
+	assert.ErrorIs(t, err, secrets.ErrNotFound)
+
+	require.NoError(t, store.Set("telegram-token", "123:abc"))
+	require.NoError(t, store.Set("webhook", "https://hooks.example.com/x"))
+	v, err := store.Get("telegram-token")
+	require.NoError(t, err)
+	assert.Equal(t, "123:abc", v)
+
+	data, err := os.ReadFile(filepath.Join(dir, secrets.FileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "123:abc", "values are encrypted")
+	info, err := os.Stat(filepath.Join(dir, secrets.KeyFileName))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	require.NoError(t, store.Delete("telegram-token"))
+	_, err = store.Get("telegram-token")
+	assert.ErrorIs(t, err, secrets.ErrNotFound)
+	assert.ErrorIs(t, store.Delete("telegram-token"), secrets.ErrNotFound)
+	v, err = secrets.NewFile(dir, "").Get("webhook")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hooks.example.com/x", v)
+}
+
+func TestFile_Passphrase(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, secrets.NewFile(dir, "correct horse").Set("sync-password", "hunter2"))
+	assert.NoFileExists(t, filepath.Join(dir, secrets.KeyFileName))
+
+	v, err := secrets.NewFile(dir, "correct horse").Get("sync-password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", v)
+
+	_, err = secrets.NewFile(dir, "wrong").Get("sync-password")
+	assert.ErrorContains(t, err, "wrong passphrase")
+	_, err = secrets.NewFile(dir, "").Get("sync-password")
+	assert.ErrorContains(t, err, "encrypted with a passphrase")
+}
+
+func TestResolve(t *testing.T) {
+	store := secrets.NewFile(t.TempDir(), "")
+	require.NoError(t, store.Set("token", "s3cret"))
+
+	v, err := secrets.Resolve(store, "plain value")
+	require.NoError(t, err)
+	assert.Equal(t, "plain value", v)
+
+	v, err = secrets.Resolve(store, "secret:token")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", v)
+
+	_, err = secrets.Resolve(store, "secret:missing")
+	assert.ErrorContains(t, err, `secret "missing" is not stored`)
+	_, err = secrets.Resolve(store, "secret:bad name")
+	assert.ErrorContains(t, err, "invalid secret name")
+}
+
+func TestOpen(t *testing.T) {
+	store, err := secrets.Open(secrets.BackendFile, t.TempDir(), "")
+	require.NoError(t, err)
+	assert.Contains(t, store.Name(), secrets.FileName)
+
+	_, err = secrets.Open("vault", t.TempDir(), "")
+	assert.ErrorContains(t, err, `unknown secrets backend "vault"`)
+}
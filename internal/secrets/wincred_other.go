@@ -0,0 +1,8 @@
+//go:build !windows
+
+package secrets
+
+// wincred is only available on Windows.
+func wincred() (Store, bool) {
+	return nil, false
+}
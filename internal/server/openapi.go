@@ -0,0 +1,334 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/compare"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+// Parameter is a query parameter of an API operation. The names mirror the
+// CLI flags.
+type Parameter struct {
+	Name        string
+	Description string
+	// Type is a JSON Schema type: string, integer, number, or boolean.
+	Type     string
+	Enum     []any
+	Minimum  *int
+	Maximum  *int
+	Required bool
+}
+
+// Operation describes one GET endpoint of the JSON API.
+type Operation struct {
+	Path        string
+	ID          string
+	Summary     string
+	Description string
+	Parameters  []Parameter
+	// Key is the payload's key in the versioned response envelope.
+	Key string
+	// Payload is a value of the payload's Go type; its schema is derived
+	// from the JSON struct tags.
+	Payload any
+	// Extra holds the schemas of optional envelope members after the
+	// payload, by name.
+	Extra map[string]any
+}
+
+func intPtr(v int) *int { return &v }
+
+var (
+	storeParam = Parameter{Name: "store", Type: "string", Description: "Publix store number, e.g. 1425. Takes precedence over zip."}
+	zipParam   = Parameter{Name: "zip", Type: "string", Description: "ZIP code; the nearest store is used."}
+
+	dealFilterParams = []Parameter{
+		{Name: "category", Type: "string", Description: "Only deals in this category, e.g. bogo, meat, produce."},
+		{Name: "department", Type: "string", Description: "Only deals whose department contains this text, case-insensitively."},
+		{Name: "query", Type: "string", Description: "Only deals whose title or description contains this text."},
+		{Name: "bogo", Type: "boolean", Description: "Only buy-one-get-one deals."},
+		{Name: "sort", Type: "string", Enum: []any{"relevance", "savings", "ending"}, Description: "Sort order; relevance is the default."},
+		{Name: "limit", Type: "integer", Minimum: intPtr(0), Description: "Return at most this many deals; 0 means no limit."},
+		{Name: "include-expired", Type: "boolean", Description: "Include deals whose end date has passed; they are left out by default."},
+		{Name: "min-percent-off", Type: "number", Minimum: intPtr(0), Maximum: intPtr(100), Description: "Only deals saving at least this percent, stated or estimated."},
+	}
+
+	dealPageParams = []Parameter{
+		{Name: "group-by", Type: "string", Enum: []any{"department", "category"}, Description: "Order deals in groups by department or category, groups in the order of their first deal."},
+		{Name: "limit-per-group", Type: "integer", Minimum: intPtr(0), Description: "Return at most this many deals of each group; groups by department when group-by is not given."},
+		{Name: "offset", Type: "integer", Minimum: intPtr(0), Description: "Skip this many deals before limit applies, to page through results."},
+	}
+
+	schemaVersionParam = Parameter{
+		Name: "schemaVersion", Type: "integer", Enum: []any{1, 2},
+		Description: "Response shape: 2 (default) wraps the payload as {\"schemaVersion\":2,<key>:...}; 1 returns the bare payload.",
+	}
+)
+
+// Operations lists the JSON endpoints served by Handler. The Slack endpoint
+// is left out: it speaks Slack's form-encoded protocol, not this API.
+func Operations() []Operation {
+	withStore := func(extra ...Parameter) []Parameter {
+		params := []Parameter{storeParam, zipParam}
+		params = append(params, extra...)
+		return append(params, schemaVersionParam)
+	}
+	requiredZip := zipParam
+	requiredZip.Required = true
+	requiredZip.Description = "ZIP code to search near."
+
+	return []Operation{
+		{
+			Path: "/deals", ID: "listDeals", Key: "deals", Payload: []display.DealJSON{},
+			Summary:     "List this week's deals at a store",
+			Description: "Weekly ad deals for the store given by store or zip (one is required), filtered and sorted like `pubcli`.",
+			Parameters:  withStore(append(append([]Parameter{}, dealFilterParams...), dealPageParams...)...),
+			Extra: map[string]any{
+				"nextOffset": map[string]any{
+					"type":        "integer",
+					"description": "The offset of the next page; present only when more deals match than limit returned.",
+				},
+			},
+		},
+		{
+			Path: "/categories", ID: "listCategories", Key: "categories", Payload: map[string]int{},
+			Summary:     "Count this week's deals per category at a store",
+			Description: "Category names mapped to deal counts for the store given by store or zip (one is required).",
+			Parameters:  withStore(),
+		},
+		{
+			Path: "/stores", ID: "listStores", Key: "stores", Payload: []display.StoreJSON{},
+			Summary:    "Find the stores nearest a ZIP code",
+			Parameters: []Parameter{requiredZip, schemaVersionParam},
+		},
+		{
+			Path: "/compare", ID: "compareStores", Key: "stores", Payload: []compare.Result{},
+			Summary:     "Rank nearby stores by how well their deals match the filters",
+			Description: "Like `pubcli compare`: stores near zip ranked by matching deals, then total deal score, then distance.",
+			Parameters: append(append([]Parameter{requiredZip,
+				{Name: "count", Type: "integer", Minimum: intPtr(1), Maximum: intPtr(10), Description: "Number of nearby stores to compare (default 5)."},
+			}, dealFilterParams...), schemaVersionParam),
+		},
+	}
+}
+
+// OpenAPI returns an OpenAPI 3.1 document describing the JSON API, with
+// serverURL as its only server when it is not empty.
+func OpenAPI(serverURL string) map[string]any {
+	components := map[string]any{
+		"Error": map[string]any{
+			"type":     "object",
+			"required": []string{"error"},
+			"properties": map[string]any{
+				"error": map[string]any{
+					"type":     "object",
+					"required": []string{"code", "message"},
+					"properties": map[string]any{
+						"code":    map[string]any{"type": "string", "enum": []string{"INVALID_ARGS", "NOT_FOUND", "UPSTREAM_ERROR", "INTERNAL_ERROR"}},
+						"message": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+	gen := schemaGenerator{
+		components: components,
+		names: map[reflect.Type]string{
+			reflect.TypeOf(display.DealJSON{}):  "Deal",
+			reflect.TypeOf(display.StoreJSON{}): "Store",
+			reflect.TypeOf(compare.Result{}):    "CompareResult",
+		},
+	}
+
+	errorResponse := func(description string) map[string]any {
+		return map[string]any{
+			"description": description,
+			"content":     map[string]any{"application/json": map[string]any{"schema": ref("Error")}},
+		}
+	}
+
+	paths := map[string]any{
+		"/healthz": map[string]any{"get": map[string]any{
+			"operationId": "health",
+			"summary":     "Liveness check",
+			"responses": map[string]any{"200": map[string]any{
+				"description": "The server is up.",
+				"content": map[string]any{"application/json": map[string]any{"schema": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"status": map[string]any{"type": "string", "const": "ok"}},
+				}}},
+			}},
+		}},
+	}
+	for _, op := range Operations() {
+		params := make([]any, 0, len(op.Parameters))
+		for _, p := range op.Parameters {
+			params = append(params, parameterObject(p))
+		}
+		properties := map[string]any{
+			"schemaVersion": map[string]any{"type": "integer"},
+			op.Key:          gen.schema(reflect.TypeOf(op.Payload)),
+		}
+		for name, schema := range op.Extra {
+			properties[name] = schema
+		}
+		get := map[string]any{
+			"operationId": op.ID,
+			"summary":     op.Summary,
+			"parameters":  params,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "Success. Responses carry ETag and Cache-Control headers; send If-None-Match to get 304 when nothing changed.",
+					"content": map[string]any{"application/json": map[string]any{"schema": map[string]any{
+						"type":       "object",
+						"required":   []string{"schemaVersion", op.Key},
+						"properties": properties,
+					}}},
+				},
+				"304": map[string]any{"description": "Not modified since the ETag in If-None-Match."},
+				"400": errorResponse("Invalid parameters."),
+				"404": errorResponse("No store or no matching deals."),
+				"502": errorResponse("The Publix API failed."),
+				"503": errorResponse("The Publix API failed repeatedly; calls are paused until Retry-After."),
+			},
+		}
+		if op.Description != "" {
+			get["description"] = op.Description
+		}
+		paths[op.Path] = map[string]any{"get": get}
+	}
+
+	doc := map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "pubcli",
+			"version":     "1.0.0",
+			"description": "Publix weekly ad deals and store lookups, served by `pubcli serve`.",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": components},
+	}
+	if serverURL != "" {
+		doc["servers"] = []any{map[string]any{"url": serverURL}}
+	}
+	return doc
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	host := r.Host
+	if fwd := r.Header.Get("X-Forwarded-Host"); fwd != "" {
+		host = fwd
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	writeJSON(w, http.StatusOK, OpenAPI(scheme+"://"+host))
+}
+
+func parameterObject(p Parameter) map[string]any {
+	return map[string]any{
+		"name":        p.Name,
+		"in":          "query",
+		"required":    p.Required,
+		"description": p.Description,
+		"schema":      ParameterSchema(p),
+	}
+}
+
+// ParameterSchema returns the JSON Schema of a parameter's value.
+func ParameterSchema(p Parameter) map[string]any {
+	schema := map[string]any{"type": p.Type}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+	return schema
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// schemaGenerator derives JSON Schemas from Go types using their JSON
+// struct tags. Types listed in names become shared components.
+type schemaGenerator struct {
+	components map[string]any
+	names      map[reflect.Type]string
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if name, ok := g.names[t]; ok {
+			if _, done := g.components[name]; !done {
+				g.components[name] = nil // guards against recursion
+				g.components[name] = g.object(t)
+			}
+			return ref(name)
+		}
+		return g.object(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	g.fields(t, properties, &required)
+	obj := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+func (g *schemaGenerator) fields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if f.Anonymous && tag == "" {
+			g.fields(f.Type, properties, required)
+			continue
+		}
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = g.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
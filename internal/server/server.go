@@ -0,0 +1,491 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/compare"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// DefaultMaxAge is how long clients may reuse a response before revalidating.
+const DefaultMaxAge = 5 * time.Minute
+
+// weeklyAdTimeLayouts are the formats seen in WeeklyAdLatestUpdatedDateTime.
+var weeklyAdTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"1/2/2006 3:04:05 PM",
+}
+
+// Config controls response caching, the default JSON schema version, and
+// the optional Slack slash-command endpoint.
+type Config struct {
+	// MaxAge is sent as Cache-Control max-age. Negative values mean zero.
+	MaxAge time.Duration
+	// SchemaVersion is used when a request has no schemaVersion parameter.
+	// Zero means the latest version.
+	SchemaVersion int
+	// SlackSigningSecret enables POST /slack. Requests must carry a valid
+	// Slack signature made with this secret.
+	SlackSigningSecret string
+	// DefaultStore and DefaultZip pick the store for slash commands that
+	// name neither.
+	DefaultStore string
+	DefaultZip   string
+}
+
+// Source provides store lookups and weekly ads, for example an *api.Client
+// or a daemon's warm cache.
+type Source = compare.Source
+
+// Server exposes deal lookups over HTTP.
+type Server struct {
+	client Source
+	cfg    Config
+}
+
+// New creates a server backed by the given source.
+func New(client Source, cfg Config) *Server {
+	if cfg.MaxAge < 0 {
+		cfg.MaxAge = 0
+	}
+	if !display.ValidSchemaVersion(cfg.SchemaVersion) {
+		cfg.SchemaVersion = display.LatestSchemaVersion
+	}
+	return &Server{client: client, cfg: cfg}
+}
+
+// Handler returns the HTTP routes for the server.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	mux.HandleFunc("GET /deals", s.handleDeals)
+	mux.HandleFunc("GET /categories", s.handleCategories)
+	mux.HandleFunc("GET /stores", s.handleStores)
+	mux.HandleFunc("GET /compare", s.handleCompare)
+	if s.cfg.SlackSigningSecret != "" {
+		mux.HandleFunc("POST /slack", s.handleSlack)
+	}
+	return mux
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleDeals(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts, err := filterOptions(q)
+	if err == nil {
+		err = pageOptions(q, &opts)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGS", err.Error())
+		return
+	}
+	version, ok := s.schemaVersion(w, r)
+	if !ok {
+		return
+	}
+
+	storeNumber, data, ok := s.fetchSavings(w, r)
+	if !ok {
+		return
+	}
+
+	// Ask for one deal past the page to learn whether another page exists,
+	// as the CLI does.
+	limit := opts.Limit
+	if limit > 0 {
+		opts.Limit++
+	}
+	items := filter.Apply(data.Savings, opts)
+	// Which deals survive, and which of them are marked expired with
+	// include-expired, also depends on the day, as deals expire within an
+	// ad version, so both are part of the ETag.
+	now := time.Now()
+	ids := make([]string, 0, len(items))
+	var expired []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+		if filter.Expired(item, now) {
+			expired = append(expired, item.ID)
+		}
+	}
+	if s.notModified(w, r, storeNumber, data.WeeklyAdLatestUpdatedDateTime, "deals", strconv.Itoa(version), q.Encode(),
+		strings.Join(ids, ","), strings.Join(expired, ",")) {
+		return
+	}
+
+	extra := map[string]any{}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+		extra["nextOffset"] = opts.Offset + limit
+	}
+	out := make([]display.DealJSON, 0, len(items))
+	for _, item := range items {
+		out = append(out, display.ToDealJSON(item))
+	}
+	writeVersionedWith(w, version, "deals", out, extra)
+}
+
+func (s *Server) handleCategories(w http.ResponseWriter, r *http.Request) {
+	version, ok := s.schemaVersion(w, r)
+	if !ok {
+		return
+	}
+	storeNumber, data, ok := s.fetchSavings(w, r)
+	if !ok {
+		return
+	}
+	if s.notModified(w, r, storeNumber, data.WeeklyAdLatestUpdatedDateTime, "categories", strconv.Itoa(version)) {
+		return
+	}
+	writeVersioned(w, version, "categories", filter.Categories(data.Savings))
+}
+
+func (s *Server) handleStores(w http.ResponseWriter, r *http.Request) {
+	zip := strings.TrimSpace(r.URL.Query().Get("zip"))
+	if zip == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGS", "zip query parameter is required")
+		return
+	}
+	version, ok := s.schemaVersion(w, r)
+	if !ok {
+		return
+	}
+
+	stores, err := s.client.FetchStores(r.Context(), zip, 5)
+	if err != nil {
+		writeStatusError(w, upstreamFailure("fetching stores", err))
+		return
+	}
+	if len(stores) == 0 {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no stores found near %s", zip))
+		return
+	}
+
+	out := make([]display.StoreJSON, 0, len(stores))
+	for _, store := range stores {
+		out = append(out, display.ToStoreJSON(store))
+	}
+	var body bytes.Buffer
+	if err := display.EncodeVersioned(&body, version, "stores", out); err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+		return
+	}
+	// Store locations carry no update timestamp, so hash the payload instead.
+	if s.checkETag(w, r, etagFor(body.String())) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts, err := filterOptions(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGS", err.Error())
+		return
+	}
+	zip := strings.TrimSpace(q.Get("zip"))
+	if zip == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGS", "zip query parameter is required")
+		return
+	}
+	count := 5
+	if raw := q.Get("count"); raw != "" {
+		count, err = strconv.Atoi(raw)
+		if err != nil || count < 1 || count > 10 {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGS", "count must be between 1 and 10")
+			return
+		}
+	}
+	version, ok := s.schemaVersion(w, r)
+	if !ok {
+		return
+	}
+
+	results, _, err := compare.Stores(r.Context(), s.client, zip, count, opts)
+	var upstream *compare.UpstreamError
+	switch {
+	case errors.As(err, &upstream):
+		writeStatusError(w, upstreamFailure(upstream.Action, upstream.Err))
+		return
+	case errors.Is(err, compare.ErrNoStores):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no stores found near %s", zip))
+		return
+	case err != nil:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeVersioned(w, version, "stores", results)
+}
+
+// fetchSavings resolves the store from the store/zip query parameters and
+// loads its weekly ad, writing an error response when it cannot.
+func (s *Server) fetchSavings(w http.ResponseWriter, r *http.Request) (string, *api.SavingsResponse, bool) {
+	storeNumber, err := s.resolveStore(r.Context(), r.URL.Query())
+	if err != nil {
+		writeStatusError(w, err)
+		return "", nil, false
+	}
+
+	data, err := s.client.FetchSavings(r.Context(), storeNumber)
+	if err != nil {
+		writeStatusError(w, upstreamFailure("fetching deals", err))
+		return "", nil, false
+	}
+	if len(data.Savings) == 0 {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no deals found for store #%s", storeNumber))
+		return "", nil, false
+	}
+	return storeNumber, data, true
+}
+
+func (s *Server) resolveStore(ctx context.Context, q map[string][]string) (string, error) {
+	store := strings.TrimSpace(first(q["store"]))
+	if store != "" {
+		return store, nil
+	}
+	zip := strings.TrimSpace(first(q["zip"]))
+	if zip == "" {
+		return "", statusError{status: http.StatusBadRequest, code: "INVALID_ARGS", message: "store or zip query parameter is required"}
+	}
+
+	stores, err := s.client.FetchStores(ctx, zip, 1)
+	if err != nil {
+		return "", upstreamFailure("finding stores", err)
+	}
+	if len(stores) == 0 {
+		return "", statusError{status: http.StatusNotFound, code: "NOT_FOUND", message: fmt.Sprintf("no Publix stores found near %s", zip)}
+	}
+	return api.StoreNumber(stores[0].Key), nil
+}
+
+// notModified sets validators derived from the weekly ad's update time and
+// reports whether a 304 was written.
+func (s *Server) notModified(w http.ResponseWriter, r *http.Request, storeNumber, updated string, parts ...string) bool {
+	if lastModified, ok := parseWeeklyAdTime(updated); ok {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	return s.checkETag(w, r, etagFor(append([]string{storeNumber, updated}, parts...)...))
+}
+
+func (s *Server) checkETag(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(s.cfg.MaxAge.Seconds())))
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// schemaVersion reads the optional schemaVersion query parameter, writing a
+// 400 response when it is not a supported version.
+func (s *Server) schemaVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+	raw := r.URL.Query().Get("schemaVersion")
+	if raw == "" {
+		return s.cfg.SchemaVersion, true
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil || !display.ValidSchemaVersion(version) {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGS", fmt.Sprintf("invalid schemaVersion %q (use 1 or 2)", raw))
+		return 0, false
+	}
+	return version, true
+}
+
+func etagFor(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func parseWeeklyAdTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range weeklyAdTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// filterOptions reads the deal filter parameters, named like the CLI flags.
+// As in the CLI, expired deals are left out unless include-expired is set.
+func filterOptions(q map[string][]string) (filter.Options, error) {
+	opts := filter.Options{
+		Category:   first(q["category"]),
+		Department: first(q["department"]),
+		Query:      first(q["query"]),
+		Sort:       strings.ToLower(first(q["sort"])),
+	}
+
+	switch opts.Sort {
+	case "", "relevance", "savings", "ending", "end", "expiry", "expiration":
+	default:
+		return opts, fmt.Errorf("invalid sort %q (use relevance, savings, or ending)", opts.Sort)
+	}
+
+	var err error
+	if opts.BOGO, err = boolParam(q, "bogo"); err != nil {
+		return opts, err
+	}
+	includeExpired, err := boolParam(q, "include-expired")
+	if err != nil {
+		return opts, err
+	}
+	opts.ExcludeExpired = !includeExpired
+	if opts.Limit, err = intParam(q, "limit"); err != nil {
+		return opts, err
+	}
+	if raw := first(q["min-percent-off"]); raw != "" {
+		percent, err := strconv.ParseFloat(raw, 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return opts, fmt.Errorf("invalid min-percent-off %q (use a number from 0 to 100)", raw)
+		}
+		opts.MinPercentOff = percent
+	}
+	return opts, nil
+}
+
+// pageOptions reads the grouping and paging parameters of /deals into opts.
+// As with --limit-per-group, a per-group limit alone groups by department.
+func pageOptions(q map[string][]string, opts *filter.Options) error {
+	opts.GroupBy = first(q["group-by"])
+	if !filter.ValidGroupBy(opts.GroupBy) {
+		return fmt.Errorf("invalid group-by %q (use department or category)", opts.GroupBy)
+	}
+	var err error
+	if opts.LimitPerGroup, err = intParam(q, "limit-per-group"); err != nil {
+		return err
+	}
+	if opts.LimitPerGroup > 0 && strings.TrimSpace(opts.GroupBy) == "" {
+		opts.GroupBy = "department"
+	}
+	opts.Offset, err = intParam(q, "offset")
+	return err
+}
+
+func boolParam(q map[string][]string, name string) (bool, error) {
+	raw := first(q[name])
+	if raw == "" {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q (use true or false)", name, raw)
+	}
+	return v, nil
+}
+
+func intParam(q map[string][]string, name string) (int, error) {
+	raw := first(q[name])
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid %s %q (use a non-negative integer)", name, raw)
+	}
+	return v, nil
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+type statusError struct {
+	status  int
+	code    string
+	message string
+	// retryAfter sets the Retry-After header when positive.
+	retryAfter time.Duration
+}
+
+func (e statusError) Error() string { return e.message }
+
+// upstreamFailure describes a failed Publix API call. An open circuit
+// breaker is a 503 with Retry-After; other failures are a 502, coded
+// MALFORMED_RESPONSE when the API answered with unreadable JSON.
+func upstreamFailure(action string, err error) statusError {
+	se := statusError{status: http.StatusBadGateway, code: "UPSTREAM_ERROR", message: action + ": " + err.Error()}
+	var open *api.CircuitOpenError
+	if errors.As(err, &open) {
+		se.status, se.code = http.StatusServiceUnavailable, "CIRCUIT_OPEN"
+		se.retryAfter = time.Until(open.Until)
+	}
+	if errors.Is(err, api.ErrDecode) {
+		se.code = "MALFORMED_RESPONSE"
+	}
+	return se
+}
+
+func writeStatusError(w http.ResponseWriter, err error) {
+	if se, ok := err.(statusError); ok {
+		if se.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((se.retryAfter+time.Second-1)/time.Second)))
+		}
+		writeError(w, se.status, se.code, se.message)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{"code": code, "message": message},
+	})
+}
+
+func writeVersioned(w http.ResponseWriter, version int, key string, payload any) {
+	writeVersionedWith(w, version, key, payload, nil)
+}
+
+// writeVersionedWith writes payload with extra envelope members, such as
+// nextOffset, which schema v1 leaves out.
+func writeVersionedWith(w http.ResponseWriter, version int, key string, payload any, extra map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	display.EncodeVersionedWith(w, version, key, payload, extra)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,339 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/server"
+)
+
+func ptr(s string) *string { return &s }
+
+type upstream struct {
+	updated atomic.Value
+	// expired ends the chicken deal yesterday, without a new ad version.
+	expired atomic.Bool
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *upstream) {
+	t.Helper()
+	up := &upstream{}
+	up.updated.Store("2026-10-14T08:00:00")
+
+	savings := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chicken := api.SavingItem{ID: "1", Title: ptr("Chicken Breasts"), Categories: []string{"meat"}}
+		if up.expired.Load() {
+			chicken.EndFormatted = time.Now().AddDate(0, 0, -1).Format("1/2/2006")
+		}
+		json.NewEncoder(w).Encode(api.SavingsResponse{
+			Savings: []api.SavingItem{
+				chicken,
+				{ID: "2", Title: ptr("Nutella"), Categories: []string{"bogo", "grocery"}},
+			},
+			WeeklyAdLatestUpdatedDateTime: up.updated.Load().(string),
+		})
+	}))
+	t.Cleanup(savings.Close)
+
+	stores := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(api.StoreResponse{Stores: []api.Store{
+			{Key: "01425", Name: "Peachers Mill", City: "Clarksville", State: "TN"},
+		}})
+	}))
+	t.Cleanup(stores.Close)
+
+	client := api.NewClientWithBaseURLs(savings.URL, stores.URL)
+	srv := httptest.NewServer(server.New(client, server.Config{MaxAge: server.DefaultMaxAge}).Handler())
+	t.Cleanup(srv.Close)
+	return srv, up
+}
+
+func get(t *testing.T, url, etag string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestDeals_FiltersAndSetsCacheHeaders(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	resp := get(t, srv.URL+"/deals?store=1425&bogo=true", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("ETag"))
+	assert.Equal(t, "public, max-age=300", resp.Header.Get("Cache-Control"))
+	assert.Equal(t, "Wed, 14 Oct 2026 08:00:00 GMT", resp.Header.Get("Last-Modified"))
+
+	var payload struct {
+		SchemaVersion int                `json:"schemaVersion"`
+		Deals         []display.DealJSON `json:"deals"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	assert.Equal(t, display.LatestSchemaVersion, payload.SchemaVersion)
+	require.Len(t, payload.Deals, 1)
+	assert.Equal(t, "Nutella", payload.Deals[0].Title)
+}
+
+func TestDeals_PagesLikeTheCLI(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	var titles []string
+	for _, path := range []string{"/deals?store=1425&limit=1", "/deals?store=1425&limit=1&offset=1", "/deals?store=1425&offset=2"} {
+		resp := get(t, srv.URL+path, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+		var payload struct {
+			Deals []display.DealJSON `json:"deals"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+		for _, d := range payload.Deals {
+			titles = append(titles, d.Title)
+		}
+	}
+	assert.Equal(t, []string{"Chicken Breasts", "Nutella"}, titles)
+
+	resp := get(t, srv.URL+"/deals?store=1425&min-percent-off=50", "")
+	var payload struct {
+		Deals []display.DealJSON `json:"deals"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	require.Len(t, payload.Deals, 1)
+	assert.Equal(t, "Nutella", payload.Deals[0].Title, "BOGO counts as 50% off")
+}
+
+func TestDeals_NextOffset(t *testing.T) {
+	srv, _ := newTestServer(t)
+	type page struct {
+		Deals      []display.DealJSON `json:"deals"`
+		NextOffset *int               `json:"nextOffset"`
+	}
+
+	var first page
+	require.NoError(t, json.NewDecoder(get(t, srv.URL+"/deals?store=1425&limit=1", "").Body).Decode(&first))
+	require.Len(t, first.Deals, 1)
+	require.NotNil(t, first.NextOffset, "more pages")
+	assert.Equal(t, 1, *first.NextOffset)
+
+	var last page
+	require.NoError(t, json.NewDecoder(get(t, srv.URL+"/deals?store=1425&limit=1&offset=1", "").Body).Decode(&last))
+	require.Len(t, last.Deals, 1)
+	assert.Nil(t, last.NextOffset, "last page")
+
+	var all page
+	require.NoError(t, json.NewDecoder(get(t, srv.URL+"/deals?store=1425&limit=2", "").Body).Decode(&all))
+	assert.Len(t, all.Deals, 2)
+	assert.Nil(t, all.NextOffset, "a limit that fits every deal has no next page")
+}
+
+func TestDeals_SchemaVersionParam(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	resp := get(t, srv.URL+"/deals?store=1425&schemaVersion=1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var deals []display.DealJSON
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&deals))
+	assert.Len(t, deals, 2)
+
+	bad := get(t, srv.URL+"/deals?store=1425&schemaVersion=7", "")
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
+}
+
+func TestDeals_IfNoneMatchReturnsNotModifiedUntilAdChanges(t *testing.T) {
+	srv, up := newTestServer(t)
+
+	etag := get(t, srv.URL+"/deals?zip=33101", "").Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	resp := get(t, srv.URL+"/deals?zip=33101", etag)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	other := get(t, srv.URL+"/deals?zip=33101&category=meat", etag)
+	assert.Equal(t, http.StatusOK, other.StatusCode, "different filters must not share an ETag")
+
+	up.updated.Store("2026-10-21T08:00:00")
+	resp = get(t, srv.URL+"/deals?zip=33101", etag)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+}
+
+func TestDeals_ExpiredDealChangesETag(t *testing.T) {
+	srv, up := newTestServer(t)
+
+	etag := get(t, srv.URL+"/deals?store=1425", "").Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	up.expired.Store(true)
+	resp := get(t, srv.URL+"/deals?store=1425", etag)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "a deal expiring mid-week changes the list")
+	var payload struct {
+		Deals []display.DealJSON `json:"deals"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	require.Len(t, payload.Deals, 1)
+	assert.Equal(t, "Nutella", payload.Deals[0].Title)
+}
+
+func TestDeals_IncludedDealExpiringChangesETag(t *testing.T) {
+	srv, up := newTestServer(t)
+	const path = "/deals?store=1425&include-expired=true"
+
+	etag := get(t, srv.URL+path, "").Header.Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, http.StatusNotModified, get(t, srv.URL+path, etag).StatusCode)
+
+	// The chicken deal's end date passes; it stays listed, now marked expired.
+	up.expired.Store(true)
+	resp := get(t, srv.URL+path, etag)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "the deal's expired field changed")
+	var payload struct {
+		Deals []display.DealJSON `json:"deals"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	require.Len(t, payload.Deals, 2)
+	assert.True(t, payload.Deals[0].Expired)
+}
+
+func TestCategoriesAndStores_SupportConditionalRequests(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	for _, path := range []string{"/categories?store=1425", "/stores?zip=33101"} {
+		first := get(t, srv.URL+path, "")
+		require.Equal(t, http.StatusOK, first.StatusCode, path)
+		second := get(t, srv.URL+path, first.Header.Get("ETag"))
+		assert.Equal(t, http.StatusNotModified, second.StatusCode, path)
+	}
+}
+
+func TestDeals_InvalidParams(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	for _, path := range []string{
+		"/deals", "/deals?store=1425&sort=price", "/deals?store=1425&limit=-1",
+		"/deals?store=1425&min-percent-off=120", "/deals?store=1425&group-by=brand", "/deals?store=1425&offset=x",
+	} {
+		resp := get(t, srv.URL+path, "")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, path)
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"), path)
+	}
+}
+
+func TestDeals_OpenCircuitIsServiceUnavailable(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(failing.Close)
+	breaker := &api.Breaker{Threshold: 1, Cooldown: time.Minute}
+	client := api.NewClientWithBaseURLs(failing.URL, failing.URL, api.WithBreaker(breaker))
+	srv := httptest.NewServer(server.New(client, server.Config{}).Handler())
+	t.Cleanup(srv.Close)
+
+	resp := get(t, srv.URL+"/deals?store=1425", "")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+
+	resp = get(t, srv.URL+"/deals?store=1425", "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+	var body struct {
+		Error struct{ Code string } `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "CIRCUIT_OPEN", body.Error.Code)
+}
+
+func TestCompare_RanksStores(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	resp := get(t, srv.URL+"/compare?zip=37040&bogo=true", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var payload struct {
+		Stores []struct {
+			Number       string `json:"number"`
+			MatchedDeals int    `json:"matchedDeals"`
+			TopDeal      string `json:"topDeal"`
+		} `json:"stores"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	require.Len(t, payload.Stores, 1)
+	assert.Equal(t, "1425", payload.Stores[0].Number)
+	assert.Equal(t, 1, payload.Stores[0].MatchedDeals)
+	assert.Equal(t, "Nutella", payload.Stores[0].TopDeal)
+
+	resp = get(t, srv.URL+"/compare", "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestOpenAPI_DescribesEndpoints(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/openapi.json", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]struct {
+			Get struct {
+				OperationID string `json:"operationId"`
+				Parameters  []struct {
+					Name     string `json:"name"`
+					Required bool   `json:"required"`
+				} `json:"parameters"`
+			} `json:"get"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]any `json:"properties"`
+				Required   []string       `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+	require.Len(t, doc.Servers, 1)
+	assert.Equal(t, "https://"+strings.TrimPrefix(srv.URL, "http://"), doc.Servers[0].URL)
+	assert.ElementsMatch(t, []string{"/healthz", "/deals", "/categories", "/stores", "/compare"}, keys(doc.Paths))
+	assert.Equal(t, "listDeals", doc.Paths["/deals"].Get.OperationID)
+
+	var names []string
+	for _, p := range doc.Paths["/deals"].Get.Parameters {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"store", "zip", "category", "department", "query", "bogo", "sort", "limit", "include-expired", "min-percent-off", "group-by", "limit-per-group", "offset", "schemaVersion"}, names)
+	assert.True(t, doc.Paths["/stores"].Get.Parameters[0].Required, "zip is required for /stores")
+
+	deal := doc.Components.Schemas["Deal"]
+	assert.Contains(t, deal.Properties, "imageUrlLarge")
+	assert.Contains(t, deal.Required, "title")
+	assert.Contains(t, doc.Components.Schemas["Store"].Properties, "distance")
+	assert.Contains(t, doc.Components.Schemas["CompareResult"].Properties, "matchedDeals")
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,309 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+const (
+	// slackMaxSkew is how old a signed request may be before it is treated
+	// as a replay.
+	slackMaxSkew      = 5 * time.Minute
+	slackMaxBody      = 64 << 10
+	slackDefaultLimit = 10
+	// slackMaxLimit keeps responses under Slack's 50-block message limit.
+	slackMaxLimit = 40
+)
+
+var (
+	zipPattern   = regexp.MustCompile(`^\d{5}$`)
+	storePattern = regexp.MustCompile(`^\d{1,4}$`)
+)
+
+// slackMessage is a slash-command response in Block Kit form.
+type slackMessage struct {
+	ResponseType string       `json:"response_type"`
+	Text         string       `json:"text"`
+	Blocks       []slackBlock `json:"blocks,omitempty"`
+}
+
+type slackBlock struct {
+	Type      string      `json:"type"`
+	Text      *slackText  `json:"text,omitempty"`
+	Elements  []slackText `json:"elements,omitempty"`
+	Accessory *slackImage `json:"accessory,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackImage struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// slackQuery is a parsed slash-command argument string such as
+// "bogo produce limit:5".
+type slackQuery struct {
+	store string
+	zip   string
+	opts  filter.Options
+	terms string
+	share bool
+	help  bool
+}
+
+func (s *Server) handleSlack(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, slackMaxBody))
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+	if err := verifySlackSignature(s.cfg.SlackSigningSecret, r.Header, body, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return
+	}
+
+	command := form.Get("command")
+	if command == "" {
+		command = "/publix"
+	}
+	q, err := parseSlackQuery(form.Get("text"))
+	if err != nil {
+		writeJSON(w, http.StatusOK, slackEphemeral(err.Error()+"\n"+slackUsage(command)))
+		return
+	}
+	if q.help {
+		writeJSON(w, http.StatusOK, slackEphemeral(slackUsage(command)))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.slackDeals(r, q))
+}
+
+func (s *Server) slackDeals(r *http.Request, q slackQuery) slackMessage {
+	if q.store == "" && q.zip == "" {
+		q.store, q.zip = s.cfg.DefaultStore, s.cfg.DefaultZip
+	}
+	params := url.Values{}
+	if q.store != "" {
+		params.Set("store", q.store)
+	} else if q.zip != "" {
+		params.Set("zip", q.zip)
+	}
+	storeNumber, err := s.resolveStore(r.Context(), params)
+	if se, ok := err.(statusError); ok && se.status == http.StatusBadRequest {
+		return slackEphemeral("No store given. Add `store:1425` or a ZIP code, or start `pubcli serve` with --store.")
+	}
+	if err != nil {
+		return slackEphemeral(err.Error())
+	}
+	data, err := s.client.FetchSavings(r.Context(), storeNumber)
+	if err != nil {
+		return slackEphemeral("Could not fetch the weekly ad: " + err.Error())
+	}
+
+	opts := q.opts
+	if q.terms != "" {
+		// Bare words are a category when the ad has one by that name, and a
+		// search otherwise, so both `/publix produce` and `/publix coffee` work.
+		if len(filter.Apply(data.Savings, filter.Options{Category: q.terms, Limit: 1})) > 0 {
+			opts.Category = q.terms
+		} else {
+			opts.Query = q.terms
+		}
+	}
+	limit := opts.Limit
+	opts.Limit = 0
+	items := filter.Apply(data.Savings, opts)
+
+	msg := buildSlackMessage(storeNumber, describeSlackFilters(opts), items, limit)
+	if q.share {
+		msg.ResponseType = "in_channel"
+	}
+	return msg
+}
+
+func buildSlackMessage(storeNumber, filters string, items []api.SavingItem, limit int) slackMessage {
+	summary := fmt.Sprintf("%d deal(s) at Publix #%s", len(items), storeNumber)
+	if filters != "" {
+		summary += " matching " + filters
+	}
+	msg := slackMessage{ResponseType: "ephemeral", Text: summary}
+	msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "*" + slackEscape(summary) + "*"}})
+	if len(items) == 0 {
+		msg.Blocks = append(msg.Blocks, slackContext("Try fewer filters, e.g. just `bogo`."))
+		return msg
+	}
+
+	shown := items
+	if len(shown) > limit {
+		shown = shown[:limit]
+	}
+	msg.Blocks = append(msg.Blocks, slackBlock{Type: "divider"})
+	for _, item := range shown {
+		msg.Blocks = append(msg.Blocks, slackDealBlock(item))
+	}
+	if rest := len(items) - len(shown); rest > 0 {
+		msg.Blocks = append(msg.Blocks, slackContext(fmt.Sprintf("…and %d more. Add `limit:%d` to see more.", rest, min(len(items), slackMaxLimit))))
+	}
+	return msg
+}
+
+func slackDealBlock(item api.SavingItem) slackBlock {
+	title := filter.Title(item)
+	lines := []string{"*" + slackEscape(title) + "*"}
+	var details []string
+	if savings := filter.CleanText(filter.Deref(item.Savings)); savings != "" {
+		details = append(details, slackEscape(savings))
+	}
+	if dept := filter.CleanText(filter.Deref(item.Department)); dept != "" {
+		details = append(details, slackEscape(dept))
+	}
+	if len(details) > 0 {
+		lines = append(lines, strings.Join(details, " · "))
+	}
+	if item.EndFormatted != "" {
+		lines = append(lines, "_through "+slackEscape(display.FormatDealDate(item.EndFormatted))+"_")
+	}
+
+	block := slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: strings.Join(lines, "\n")}}
+	if img := filter.Deref(item.ImageURL); img != "" {
+		block.Accessory = &slackImage{Type: "image", ImageURL: img, AltText: title}
+	}
+	return block
+}
+
+func slackContext(text string) slackBlock {
+	return slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: text}}}
+}
+
+func slackEphemeral(text string) slackMessage {
+	return slackMessage{
+		ResponseType: "ephemeral",
+		Text:         text,
+		Blocks:       []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: text}}},
+	}
+}
+
+func slackUsage(command string) string {
+	return fmt.Sprintf("Usage: `%[1]s [bogo] [CATEGORY or SEARCH] [store:NUMBER | ZIP] [dept:NAME] [sort:savings|ending] [limit:N] [share]`\n"+
+		"Examples: `%[1]s bogo produce`, `%[1]s coffee sort:savings`, `%[1]s bogo 33101 share`", command)
+}
+
+// parseSlackQuery reads the slash-command text. Keywords and key:value
+// options may appear in any order; everything else is joined into a
+// category-or-search term.
+func parseSlackQuery(text string) (slackQuery, error) {
+	q := slackQuery{opts: filter.Options{Limit: slackDefaultLimit}}
+	var terms []string
+	for _, field := range strings.Fields(text) {
+		lower := strings.ToLower(field)
+		key, value, hasValue := strings.Cut(lower, ":")
+		switch {
+		case lower == "help":
+			q.help = true
+		case lower == "bogo":
+			q.opts.BOGO = true
+		case lower == "share":
+			q.share = true
+		case zipPattern.MatchString(lower):
+			q.zip = lower
+		case hasValue && key == "store":
+			q.store = value
+		case hasValue && key == "zip":
+			q.zip = value
+		case hasValue && (key == "dept" || key == "department"):
+			q.opts.Department = field[len(key)+1:]
+		case hasValue && key == "sort":
+			switch value {
+			case "relevance", "savings", "ending", "end", "expiry", "expiration":
+				q.opts.Sort = value
+			default:
+				return q, fmt.Errorf("Unknown sort `%s`.", value)
+			}
+		case hasValue && key == "limit":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > slackMaxLimit {
+				return q, fmt.Errorf("`limit` must be between 1 and %d.", slackMaxLimit)
+			}
+			q.opts.Limit = n
+		default:
+			terms = append(terms, field)
+		}
+	}
+	if q.store != "" && !storePattern.MatchString(q.store) {
+		return q, fmt.Errorf("`store:%s` is not a store number.", q.store)
+	}
+	q.terms = strings.Join(terms, " ")
+	return q, nil
+}
+
+func describeSlackFilters(opts filter.Options) string {
+	var parts []string
+	if opts.BOGO {
+		parts = append(parts, "BOGO")
+	}
+	if opts.Category != "" {
+		parts = append(parts, "category "+opts.Category)
+	}
+	if opts.Query != "" {
+		parts = append(parts, `"`+opts.Query+`"`)
+	}
+	if opts.Department != "" {
+		parts = append(parts, "department "+opts.Department)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// verifySlackSignature checks Slack's v0 request signature: an HMAC-SHA256
+// of "v0:<timestamp>:<body>" keyed with the app's signing secret.
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	ts := header.Get("X-Slack-Request-Timestamp")
+	sig := header.Get("X-Slack-Signature")
+	if ts == "" || sig == "" {
+		return fmt.Errorf("missing Slack signature headers")
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Slack request timestamp")
+	}
+	if skew := now.Sub(time.Unix(unix, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return fmt.Errorf("stale Slack request timestamp")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", ts)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return fmt.Errorf("invalid Slack signature")
+	}
+	return nil
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func slackEscape(s string) string {
+	return slackEscaper.Replace(s)
+}
@@ -0,0 +1,123 @@
+package server_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/server"
+)
+
+const testSlackSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+type slackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+	Blocks       []struct {
+		Type string `json:"type"`
+		Text *struct {
+			Text string `json:"text"`
+		} `json:"text"`
+	} `json:"blocks"`
+}
+
+func newSlackServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	savings := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(api.SavingsResponse{Savings: []api.SavingItem{
+			{ID: "1", Title: ptr("Bananas"), Savings: ptr("Buy 1 Get 1 FREE"), Categories: []string{"bogo", "produce"}},
+			{ID: "2", Title: ptr("Apples"), Categories: []string{"produce"}},
+			{ID: "3", Title: ptr("Cold Brew Coffee"), Categories: []string{"bogo", "grocery"}},
+		}})
+	}))
+	t.Cleanup(savings.Close)
+
+	client := api.NewClientWithBaseURLs(savings.URL, savings.URL)
+	srv := httptest.NewServer(server.New(client, server.Config{
+		SlackSigningSecret: testSlackSecret,
+		DefaultStore:       "1425",
+	}).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func postSlack(t *testing.T, srv *httptest.Server, text string, sign func(ts, body string) string) *http.Response {
+	t.Helper()
+	body := url.Values{"command": {"/publix"}, "text": {text}}.Encode()
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/slack", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", sign(ts, body))
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func slackSign(ts, body string) string {
+	mac := hmac.New(sha256.New, []byte(testSlackSecret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestSlack_BogoCategoryCommand(t *testing.T) {
+	srv := newSlackServer(t)
+
+	resp := postSlack(t, srv, "bogo produce", slackSign)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var msg slackResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&msg))
+	assert.Equal(t, "ephemeral", msg.ResponseType)
+	assert.Equal(t, "1 deal(s) at Publix #1425 matching BOGO, category produce", msg.Text)
+	require.Len(t, msg.Blocks, 3)
+	assert.Equal(t, "divider", msg.Blocks[1].Type)
+	assert.Contains(t, msg.Blocks[2].Text.Text, "*Bananas*")
+}
+
+func TestSlack_BareWordsFallBackToSearchAndShare(t *testing.T) {
+	srv := newSlackServer(t)
+
+	resp := postSlack(t, srv, "coffee share", slackSign)
+	var msg slackResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&msg))
+	assert.Equal(t, "in_channel", msg.ResponseType)
+	assert.Contains(t, msg.Text, `"coffee"`)
+	require.Len(t, msg.Blocks, 3)
+	assert.Contains(t, msg.Blocks[2].Text.Text, "Cold Brew Coffee")
+}
+
+func TestSlack_RejectsBadSignature(t *testing.T) {
+	srv := newSlackServer(t)
+
+	resp := postSlack(t, srv, "bogo", func(string, string) string { return "v0=deadbeef" })
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	stale := postSlack(t, srv, "bogo", func(_, body string) string {
+		return slackSign(strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10), body)
+	})
+	assert.Equal(t, http.StatusUnauthorized, stale.StatusCode)
+}
+
+func TestSlack_DisabledWithoutSecret(t *testing.T) {
+	srv, _ := newTestServer(t)
+	resp, err := http.Post(srv.URL+"/slack", "application/x-www-form-urlencoded", strings.NewReader("text=bogo"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,38 @@
+package shoplist
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/csvimport"
+)
+
+// ImportColumns are the columns ImportCSV reads; only name is required.
+var ImportColumns = []string{"name", "qty", "note"}
+
+// ImportCSV reads items from CSV with a header row naming ImportColumns, as
+// plain-text items added at now.
+func ImportCSV(r io.Reader, now time.Time) ([]Item, error) {
+	rows, err := csvimport.Read(r, ImportColumns, "name")
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(rows))
+	for _, row := range rows {
+		qty, err := csvimport.Int(row, "qty")
+		if err != nil {
+			return nil, err
+		}
+		if qty < 0 {
+			return nil, row.Errorf("qty must be at least 1")
+		}
+		items = append(items, Item{
+			Name:    strings.Join(strings.Fields(row.Get("name")), " "),
+			Qty:     qty,
+			Note:    row.Get("note"),
+			AddedAt: now,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,51 @@
+package shoplist
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	publixSearchURL    = "https://www.publix.com/search?searchTerm="
+	instacartSearchURL = "https://delivery.publix.com/store/publix/s?k="
+)
+
+// reSizeSuffix matches a trailing size such as ", 16 oz" or " 2-lb" that
+// would narrow an online search too far.
+var reSizeSuffix = regexp.MustCompile(`(?i)[,\s]+\d+(\.\d+)?[\s-]*(oz|fl oz|lb|lbs|ct|count|pk|pack|l|ml|gal)\.?$`)
+
+// Links are the online ordering searches for one list item.
+type Links struct {
+	Name      string `json:"name"`
+	Search    string `json:"search"`
+	Publix    string `json:"publix"`
+	Instacart string `json:"instacart"`
+}
+
+// ItemLinks returns search links for each item: Publix's own site, for
+// curbside pickup, and Publix Delivery, which is run by Instacart.
+func ItemLinks(items []Item) []Links {
+	out := make([]Links, 0, len(items))
+	for _, item := range items {
+		term := SearchTerm(item.Name)
+		q := url.QueryEscape(term)
+		out = append(out, Links{
+			Name:      item.Name,
+			Search:    term,
+			Publix:    publixSearchURL + q,
+			Instacart: instacartSearchURL + q,
+		})
+	}
+	return out
+}
+
+// SearchTerm turns an item name into a search: whitespace collapsed and a
+// trailing package size dropped.
+func SearchTerm(name string) string {
+	term := strings.Join(strings.Fields(name), " ")
+	if trimmed := reSizeSuffix.ReplaceAllString(term, ""); trimmed != "" {
+		term = trimmed
+	}
+	return term
+}
@@ -0,0 +1,64 @@
+package shoplist
+
+import (
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+)
+
+// Matched is one list item and the deals in an ad that match it.
+type Matched struct {
+	Name  string             `json:"name"`
+	Deals []display.DealJSON `json:"deals"`
+}
+
+// OnSale reports whether any deal matched the item.
+func (m Matched) OnSale() bool { return len(m.Deals) > 0 }
+
+// Match looks up every item of the list in an ad's deals without changing
+// the list. A deal matches when each word of the item's name starts a word
+// of the deal's title or description, so "chicken thigh" finds "Boneless
+// Chicken Thighs". An item linked to a deal still in the ad matches that
+// deal only.
+func (l *List) Match(deals []api.SavingItem) []Matched {
+	byID := map[string]api.SavingItem{}
+	for _, deal := range deals {
+		byID[deal.ID] = deal
+	}
+	out := make([]Matched, 0, len(l.Items))
+	for _, item := range l.Items {
+		m := Matched{Name: item.Name, Deals: []display.DealJSON{}}
+		if deal, ok := byID[item.DealID]; ok && item.DealID != "" {
+			m.Deals = append(m.Deals, display.ToDealJSON(deal))
+			out = append(out, m)
+			continue
+		}
+		words := titleWords(item.Name)
+		for _, deal := range deals {
+			d := display.ToDealJSON(deal)
+			if len(words) > 0 && hasWordPrefixes(titleWords(d.Title+" "+d.Description), words) {
+				m.Deals = append(m.Deals, d)
+			}
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// hasWordPrefixes reports whether every one of want starts a word of have.
+func hasWordPrefixes(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.HasPrefix(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
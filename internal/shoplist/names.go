@@ -0,0 +1,90 @@
+package shoplist
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// DefaultName names the list kept in FileName, used when no name is given.
+const DefaultName = "default"
+
+// ListsDir is the directory inside the data directory that holds the named
+// lists, one NAME.json file each.
+const ListsDir = "lists"
+
+var reListName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+
+// NormalizeName lowercases and trims a list name; empty is DefaultName.
+func NormalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultName
+	}
+	return name
+}
+
+// ValidName reports whether a normalized name can name a list: up to 40
+// lowercase letters, digits, dashes, and underscores, starting with a letter
+// or digit.
+func ValidName(name string) bool {
+	return reListName.MatchString(name)
+}
+
+// KeyFor returns the storage key of the named list.
+func KeyFor(name string) string {
+	name = NormalizeName(name)
+	if name == DefaultName {
+		return FileName
+	}
+	return ListsDir + "/" + name + ".json"
+}
+
+// Names returns the saved lists, the default list first and the others
+// sorted. The default list is included even before it is saved.
+func Names(b storage.Backend) ([]string, error) {
+	keys, err := b.Keys(ListsDir + "/")
+	if err != nil {
+		return nil, fmt.Errorf("reading lists: %w", err)
+	}
+	var names []string
+	for _, key := range keys {
+		name, ok := strings.CutSuffix(strings.TrimPrefix(key, ListsDir+"/"), ".json")
+		if !ok || !ValidName(name) || name == DefaultName {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultName}, names...), nil
+}
+
+// Rename moves the list named from to the name to. It fails with
+// fs.ErrNotExist when from is not saved and fs.ErrExist when to is.
+func Rename(b storage.Backend, from, to string) error {
+	src, dst := KeyFor(from), KeyFor(to)
+	obj, err := b.Get(src)
+	if err != nil {
+		return err
+	}
+	if _, err := b.Get(dst); err == nil {
+		return fs.ErrExist
+	} else if !errors.Is(err, storage.ErrNotExist) {
+		return err
+	}
+	if err := b.Put(dst, obj); err != nil {
+		return err
+	}
+	return b.Delete(src)
+}
+
+// Delete removes the named list. It fails with fs.ErrNotExist when the list
+// is not saved.
+func Delete(b storage.Backend, name string) error {
+	return b.Delete(KeyFor(name))
+}
@@ -0,0 +1,211 @@
+package shoplist
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// Refresh statuses.
+const (
+	// RefreshCurrent is an item whose deal is still in the ad.
+	RefreshCurrent = "current"
+	// RefreshRelinked is an item moved to the deal with the most similar
+	// title.
+	RefreshRelinked = "relinked"
+	// RefreshEnded is an item no deal in the ad resembles.
+	RefreshEnded = "ended"
+)
+
+// minTitleSimilarity is the share of title words two deals must have in
+// common to count as the same product, e.g. a new week's deal for the same
+// item under another ID.
+const minTitleSimilarity = 0.5
+
+// maxSuggestions is how many replacements Refresh offers for an ended item.
+const maxSuggestions = 3
+
+// Link attaches the deal's details to the item, clearing OffSale.
+func (it *Item) Link(store string, deal api.SavingItem) {
+	d := display.ToDealJSON(deal)
+	it.Name = d.Title
+	it.DealID = deal.ID
+	it.Savings = d.Savings
+	it.Department = d.Department
+	it.Categories = productCategories(d.Categories)
+	it.ValidTo = d.ValidTo
+	it.Store = store
+	it.OffSale = false
+}
+
+// Suggestion is a deal offered in place of an item no longer on sale.
+type Suggestion struct {
+	DealID  string `json:"dealId"`
+	Title   string `json:"title"`
+	Savings string `json:"savings,omitempty"`
+	ValidTo string `json:"validTo,omitempty"`
+}
+
+// Refreshed is the outcome of Refresh for one deal-linked item.
+type Refreshed struct {
+	// Name is the item's name after the refresh.
+	Name string `json:"name"`
+	// Previous is the name before a relink.
+	Previous    string       `json:"previous,omitempty"`
+	Status      string       `json:"status"`
+	Suggestions []Suggestion `json:"suggestions,omitempty"`
+}
+
+// Refresh re-matches the deal-linked items with the deals of a new ad from
+// store. An item whose deal is still in the ad takes its current details;
+// otherwise it is linked to the deal with the most similar title, if one is
+// similar enough, or marked OffSale with deals from its categories as
+// suggestions. Free-text items are left alone and not reported.
+func (l *List) Refresh(store string, deals []api.SavingItem) []Refreshed {
+	byID := map[string]api.SavingItem{}
+	for _, deal := range deals {
+		byID[deal.ID] = deal
+	}
+	// Deals already on the list are not offered to other items.
+	used := map[string]bool{}
+	for _, item := range l.Items {
+		if _, ok := byID[item.DealID]; ok && item.DealID != "" {
+			used[item.DealID] = true
+		}
+	}
+
+	results := []Refreshed{}
+	for i := range l.Items {
+		item := &l.Items[i]
+		if item.DealID == "" {
+			continue
+		}
+		if deal, ok := byID[item.DealID]; ok {
+			item.Link(store, deal)
+			results = append(results, Refreshed{Name: item.Name, Status: RefreshCurrent})
+			continue
+		}
+
+		words := titleWords(item.Name)
+		best, bestScore := -1, 0.0
+		for j, deal := range deals {
+			if used[deal.ID] {
+				continue
+			}
+			if score := similarity(words, titleWords(filter.Title(deal))); score > bestScore {
+				best, bestScore = j, score
+			}
+		}
+		if best >= 0 && bestScore >= minTitleSimilarity {
+			previous := item.Name
+			used[deals[best].ID] = true
+			item.Link(store, deals[best])
+			results = append(results, Refreshed{Name: item.Name, Previous: previous, Status: RefreshRelinked})
+			continue
+		}
+
+		item.OffSale = true
+		item.Savings = ""
+		item.ValidTo = ""
+		results = append(results, Refreshed{
+			Name:        item.Name,
+			Status:      RefreshEnded,
+			Suggestions: suggest(*item, words, deals, used),
+		})
+	}
+	return results
+}
+
+// suggest ranks the unused deals sharing a category with item, or its
+// department when it has no categories, by title similarity and then deal
+// score.
+func suggest(item Item, words []string, deals []api.SavingItem, used map[string]bool) []Suggestion {
+	type candidate struct {
+		deal       api.SavingItem
+		similarity float64
+		score      float64
+	}
+	var candidates []candidate
+	for _, deal := range deals {
+		if used[deal.ID] || !sameCategory(item, deal) {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			deal:       deal,
+			similarity: similarity(words, titleWords(filter.Title(deal))),
+			score:      filter.DealScore(deal),
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].similarity != candidates[j].similarity {
+			return candidates[i].similarity > candidates[j].similarity
+		}
+		return candidates[i].score > candidates[j].score
+	})
+
+	var suggestions []Suggestion
+	for _, c := range candidates {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+		d := display.ToDealJSON(c.deal)
+		suggestions = append(suggestions, Suggestion{DealID: c.deal.ID, Title: d.Title, Savings: d.Savings, ValidTo: d.ValidTo})
+	}
+	return suggestions
+}
+
+func sameCategory(item Item, deal api.SavingItem) bool {
+	if len(item.Categories) == 0 {
+		return item.Department != "" && strings.EqualFold(item.Department, filter.CleanText(filter.Deref(deal.Department)))
+	}
+	for _, c := range item.Categories {
+		if filter.ContainsIgnoreCase(deal.Categories, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// productCategories drops the categories that describe the offer rather
+// than the product, so suggestions are not every other BOGO deal.
+func productCategories(categories []string) []string {
+	var kept []string
+	for _, c := range categories {
+		if !strings.EqualFold(c, "bogo") {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+func titleWords(title string) []string {
+	return strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// similarity is the Jaccard index of two titles' word sets: the words they
+// share over all the words either has.
+func similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := map[string]int{}
+	for _, w := range a {
+		set[w] |= 1
+	}
+	for _, w := range b {
+		set[w] |= 2
+	}
+	shared := 0
+	for _, v := range set {
+		if v == 3 {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(set))
+}
@@ -0,0 +1,131 @@
+// Package shoplist stores the user's shopping list on disk.
+package shoplist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// FileName is the list's file name inside the data directory.
+const FileName = "list.json"
+
+// Item is one entry on the list. Entries added from the weekly ad carry the
+// deal's details; free-text entries only have a Name.
+type Item struct {
+	Name       string `json:"name"`
+	DealID     string `json:"dealId,omitempty"`
+	Savings    string `json:"savings,omitempty"`
+	Department string `json:"department,omitempty"`
+	// Categories are the deal's product categories, for suggesting
+	// replacements when it ends.
+	Categories []string `json:"categories,omitempty"`
+	Store      string   `json:"store,omitempty"`
+	ValidTo    string   `json:"validTo,omitempty"`
+	// OffSale is set by Refresh when the deal is no longer in the ad.
+	OffSale bool `json:"offSale,omitempty"`
+	// Qty is how many to buy; zero means one.
+	Qty int `json:"qty,omitempty"`
+	// Note is free text shown with the item, e.g. "for Sunday".
+	Note string `json:"note,omitempty"`
+	// Checked is set once the item is in the cart, by `list shop`.
+	Checked bool      `json:"checked,omitempty"`
+	AddedAt time.Time `json:"addedAt"`
+}
+
+// Quantity is how many of the item to buy, at least one.
+func (it Item) Quantity() int {
+	if it.Qty < 1 {
+		return 1
+	}
+	return it.Qty
+}
+
+// List is the saved shopping list.
+type List struct {
+	Items []Item `json:"items"`
+}
+
+// Load reads the list stored under key. A missing list is empty.
+func Load(b storage.Backend, key string) (*List, error) {
+	obj, err := b.Get(key)
+	if errors.Is(err, storage.ErrNotExist) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading shopping list: %w", err)
+	}
+	l := &List{}
+	if err := json.Unmarshal(obj.Data, l); err != nil {
+		return nil, fmt.Errorf("parsing shopping list %s: %w", key, err)
+	}
+	return l, nil
+}
+
+// Save stores the list under key, replacing the previous one.
+func (l *List) Save(b storage.Backend, key string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.Put(key, storage.Object{Data: data}); err != nil {
+		return fmt.Errorf("writing shopping list: %w", err)
+	}
+	return nil
+}
+
+// Add appends an item. It reports false when an item with the same deal ID,
+// or the same name for free-text items, is already on the list.
+func (l *List) Add(item Item) bool {
+	if l.Existing(item) != nil {
+		return false
+	}
+	l.Items = append(l.Items, item)
+	return true
+}
+
+// Existing returns the entry Add treats as the same item, or nil.
+func (l *List) Existing(item Item) *Item {
+	for i, existing := range l.Items {
+		if item.DealID != "" && existing.DealID == item.DealID {
+			return &l.Items[i]
+		}
+		if item.DealID == "" && existing.DealID == "" && strings.EqualFold(existing.Name, item.Name) {
+			return &l.Items[i]
+		}
+	}
+	return nil
+}
+
+// Remove deletes the item identified by ref: a 1-based position as shown by
+// `list show`, or a case-insensitive name.
+func (l *List) Remove(ref string) (Item, error) {
+	i, err := l.find(ref)
+	if err != nil {
+		return Item{}, err
+	}
+	removed := l.Items[i]
+	l.Items = append(l.Items[:i], l.Items[i+1:]...)
+	return removed, nil
+}
+
+func (l *List) find(ref string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(l.Items) {
+			return 0, fmt.Errorf("no item #%d (the list has %d)", n, len(l.Items))
+		}
+		return n - 1, nil
+	}
+	for i, item := range l.Items {
+		if strings.EqualFold(item.Name, ref) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no item named %q", ref)
+}
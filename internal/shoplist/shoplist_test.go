@@ -0,0 +1,227 @@
+package shoplist_test
+
+import (
+	"bytes"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/shoplist"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func TestList_AddRemoveAndPersist(t *testing.T) {
+	store := &storage.Files{Dir: filepath.Join(t.TempDir(), "nested")}
+	key := shoplist.FileName
+
+	l, err := shoplist.Load(store, key)
+	require.NoError(t, err)
+	assert.Empty(t, l.Items)
+
+	assert.True(t, l.Add(shoplist.Item{Name: "Chicken Thighs", DealID: "42", Department: "Meat"}))
+	assert.True(t, l.Add(shoplist.Item{Name: "paper towels"}))
+	assert.True(t, l.Add(shoplist.Item{Name: "Milk"}))
+	assert.False(t, l.Add(shoplist.Item{Name: "Thighs again", DealID: "42"}), "same deal twice")
+	assert.False(t, l.Add(shoplist.Item{Name: "Paper Towels"}), "same text twice")
+	require.NoError(t, l.Save(store, key))
+
+	loaded, err := shoplist.Load(store, key)
+	require.NoError(t, err)
+	require.Len(t, loaded.Items, 3)
+
+	removed, err := loaded.Remove("2")
+	require.NoError(t, err)
+	assert.Equal(t, "paper towels", removed.Name)
+	removed, err = loaded.Remove("milk")
+	require.NoError(t, err)
+	assert.Equal(t, "Milk", removed.Name)
+
+	_, err = loaded.Remove("5")
+	assert.ErrorContains(t, err, "no item #5")
+	_, err = loaded.Remove("eggs")
+	assert.ErrorContains(t, err, `no item named "eggs"`)
+	assert.Len(t, loaded.Items, 1)
+}
+
+func TestRenderWallet(t *testing.T) {
+	l := &shoplist.List{}
+	l.Add(shoplist.Item{Name: "Bananas", DealID: "1", Department: "Produce", Savings: "49¢ lb", ValidTo: "10/21"})
+	l.Add(shoplist.Item{Name: "<b>foil</b>"})
+	l.Add(shoplist.Item{Name: "Ground Beef", DealID: "2", Department: "Meat", Qty: 2, Note: "for Sunday"})
+
+	var buf bytes.Buffer
+	require.NoError(t, shoplist.RenderWallet(&buf, l, shoplist.Pass{
+		StoreName:    "Publix #1425 — Peachers Mill",
+		StoreAddress: "1490 Tiny Town Rd, Clarksville, TN 37042",
+		AdWeek:       "10/15 – 10/21",
+		GeneratedAt:  time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+	}))
+	page := buf.String()
+
+	assert.Contains(t, page, `<meta name="viewport"`)
+	assert.Contains(t, page, "1490 Tiny Town Rd, Clarksville, TN 37042")
+	assert.Contains(t, page, "Weekly ad: 10/15 – 10/21")
+	assert.Contains(t, page, "49¢ lb · through 10/21")
+	assert.Contains(t, page, "&lt;b&gt;foil&lt;/b&gt;", "item names are escaped")
+	assert.Contains(t, page, "2 × Ground Beef<em>for Sunday</em>")
+	assert.NotContains(t, page, "<link", "page must not need network assets")
+
+	meat := strings.Index(page, "<h2>Meat</h2>")
+	produce := strings.Index(page, "<h2>Produce</h2>")
+	other := strings.Index(page, "<h2>Other</h2>")
+	assert.True(t, meat < produce && produce < other, "departments sorted, free text last")
+}
+
+func TestList_Totals(t *testing.T) {
+	l := &shoplist.List{}
+	l.Add(shoplist.Item{Name: "Yogurt", DealID: "1", Savings: "2/$5.00", Qty: 4})
+	l.Add(shoplist.Item{Name: "Chips", DealID: "2", Savings: "Buy 1 Get 1 FREE $4.00"})
+	l.Add(shoplist.Item{Name: "Coffee", DealID: "3", Savings: "Save Up To $3.00", Qty: 2})
+	l.Add(shoplist.Item{Name: "paper towels", Qty: 3})
+
+	totals := l.Totals()
+	assert.InDelta(t, 12.0, totals.Cost, 0.001, "4 × $2.50 plus one BOGO chip bag at $2")
+	assert.InDelta(t, 8.0, totals.Savings, 0.001, "one BOGO bag saves $2, two coffees $6")
+	assert.Equal(t, 2, totals.Priced)
+	assert.Equal(t, 10, totals.Units)
+
+	existing := l.Existing(shoplist.Item{Name: "Paper Towels"})
+	require.NotNil(t, existing)
+	existing.Qty = 1
+	assert.Equal(t, 1, l.Items[3].Quantity(), "Existing points into the list")
+	assert.Equal(t, 1, shoplist.Item{}.Quantity())
+}
+
+func TestItemLinks(t *testing.T) {
+	links := shoplist.ItemLinks([]shoplist.Item{
+		{Name: "Publix Greek Yogurt, 32 oz"},
+		{Name: "  paper   towels "},
+	})
+	require.Len(t, links, 2)
+	assert.Equal(t, "Publix Greek Yogurt", links[0].Search)
+	assert.Equal(t, "https://www.publix.com/search?searchTerm=Publix+Greek+Yogurt", links[0].Publix)
+	assert.Equal(t, "https://delivery.publix.com/store/publix/s?k=Publix+Greek+Yogurt", links[0].Instacart)
+	assert.Equal(t, "paper towels", links[1].Search)
+}
+
+func TestSearchTerm(t *testing.T) {
+	assert.Equal(t, "Chicken Thighs", shoplist.SearchTerm("Chicken Thighs 2-lb"))
+	assert.Equal(t, "Orange Juice", shoplist.SearchTerm("Orange Juice 52 fl oz"))
+	assert.Equal(t, "7up", shoplist.SearchTerm("7up"))
+}
+
+func TestList_Refresh(t *testing.T) {
+	str := func(s string) *string { return &s }
+	l := &shoplist.List{Items: []shoplist.Item{
+		{Name: "Greek Yogurt", DealID: "1", Savings: "2/$5.00", Store: "1425"},
+		{Name: "Boneless Chicken Thighs", DealID: "2", Savings: "$2.99 lb", Qty: 2},
+		{Name: "Kettle Chips", DealID: "3", Savings: "BOGO", Categories: []string{"snacks"}},
+		{Name: "paper towels"},
+	}}
+	deals := []api.SavingItem{
+		{ID: "1", Title: str("Greek Yogurt"), Savings: str("3/$5.00"), Categories: []string{"dairy"}},
+		{ID: "20", Title: str("Publix Boneless Chicken Thighs"), Savings: str("$2.49 lb"), Department: str("Meat")},
+		{ID: "30", Title: str("Tortilla Chips"), Savings: str("Save $1.00"), Categories: []string{"snacks", "bogo"}},
+		{ID: "31", Title: str("Pretzels"), Savings: str("Save $2.00"), Categories: []string{"snacks"}},
+		{ID: "40", Title: str("Orange Juice"), Categories: []string{"bogo"}},
+	}
+
+	results := l.Refresh("1425", deals)
+	require.Len(t, results, 3, "free-text items are not refreshed")
+
+	assert.Equal(t, shoplist.Refreshed{Name: "Greek Yogurt", Status: shoplist.RefreshCurrent}, results[0])
+	assert.Equal(t, "3/$5.00", l.Items[0].Savings)
+	assert.Equal(t, []string{"dairy"}, l.Items[0].Categories)
+
+	assert.Equal(t, shoplist.RefreshRelinked, results[1].Status)
+	assert.Equal(t, "Boneless Chicken Thighs", results[1].Previous)
+	assert.Equal(t, "Publix Boneless Chicken Thighs", results[1].Name)
+	assert.Equal(t, "20", l.Items[1].DealID)
+	assert.Equal(t, 2, l.Items[1].Qty, "quantity survives a relink")
+
+	assert.Equal(t, shoplist.RefreshEnded, results[2].Status)
+	assert.True(t, l.Items[2].OffSale)
+	assert.Empty(t, l.Items[2].Savings)
+	require.Len(t, results[2].Suggestions, 2, "only deals in the same category")
+	assert.Equal(t, "30", results[2].Suggestions[0].DealID, "most similar title first")
+	assert.Equal(t, "31", results[2].Suggestions[1].DealID)
+}
+
+func TestList_Match(t *testing.T) {
+	str := func(s string) *string { return &s }
+	l := &shoplist.List{Items: []shoplist.Item{
+		{Name: "Greek Yogurt", DealID: "1"},
+		{Name: "oat milk"},
+		{Name: "Chips"},
+		{Name: "paper towels"},
+	}}
+	deals := []api.SavingItem{
+		{ID: "1", Title: str("Greek Yogurt")},
+		{ID: "2", Title: str("Greek Yogurt Cups")},
+		{ID: "3", Title: str("Silk Oatmilk"), Description: str("Oat or almond milk, 64 oz")},
+		{ID: "4", Title: str("Tortilla Chips")},
+		{ID: "5", Title: str("Kettle Chips")},
+	}
+	before := append([]shoplist.Item(nil), l.Items...)
+
+	matches := l.Match(deals)
+	require.Len(t, matches, 4)
+	require.Len(t, matches[0].Deals, 1, "a linked item matches its own deal")
+	require.Len(t, matches[1].Deals, 1)
+	assert.Equal(t, "Silk Oatmilk", matches[1].Deals[0].Title)
+	assert.Len(t, matches[2].Deals, 2)
+	assert.False(t, matches[3].OnSale())
+	assert.NotNil(t, matches[3].Deals)
+	assert.Equal(t, before, l.Items, "the list is not changed")
+}
+
+func TestNamedLists(t *testing.T) {
+	dir := &storage.Files{Dir: t.TempDir()}
+	assert.Equal(t, shoplist.FileName, shoplist.KeyFor(""))
+	assert.Equal(t, shoplist.FileName, shoplist.KeyFor("Default"))
+	assert.Equal(t, "lists/thanksgiving.json", shoplist.KeyFor(" Thanksgiving "))
+	assert.True(t, shoplist.ValidName("bob_s-list2"))
+	assert.False(t, shoplist.ValidName("../etc"))
+	assert.False(t, shoplist.ValidName("-x"))
+
+	names, err := shoplist.Names(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default"}, names, "the default list exists before it is saved")
+
+	for _, name := range []string{"thanksgiving", "bob"} {
+		l := &shoplist.List{}
+		l.Add(shoplist.Item{Name: "milk"})
+		require.NoError(t, l.Save(dir, shoplist.KeyFor(name)))
+	}
+	names, err = shoplist.Names(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "bob", "thanksgiving"}, names)
+
+	assert.ErrorIs(t, shoplist.Rename(dir, "bob", "thanksgiving"), fs.ErrExist)
+	assert.ErrorIs(t, shoplist.Rename(dir, "alice", "carol"), fs.ErrNotExist)
+	require.NoError(t, shoplist.Rename(dir, "bob", "alice"))
+	require.NoError(t, shoplist.Delete(dir, "thanksgiving"))
+	assert.ErrorIs(t, shoplist.Delete(dir, "thanksgiving"), fs.ErrNotExist)
+
+	names, err = shoplist.Names(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "alice"}, names)
+}
+
+func TestImportCSV(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	items, err := shoplist.ImportCSV(strings.NewReader("Qty,Name,Note\n2,  Greek   yogurt ,plain\n,bananas,\n"), now)
+	require.NoError(t, err)
+	assert.Equal(t, []shoplist.Item{
+		{Name: "Greek yogurt", Qty: 2, Note: "plain", AddedAt: now},
+		{Name: "bananas", AddedAt: now},
+	}, items)
+
+	_, err = shoplist.ImportCSV(strings.NewReader("name,qty\nmilk,-1\n"), now)
+	assert.ErrorContains(t, err, "line 2: qty must be at least 1")
+}
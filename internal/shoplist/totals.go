@@ -0,0 +1,53 @@
+package shoplist
+
+import (
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/filter"
+)
+
+// Totals is an estimate of what the list costs and saves, read from the
+// savings text of the deals its items are linked to.
+type Totals struct {
+	// Cost is the sum of each priced item's sale price times its quantity.
+	Cost float64 `json:"cost"`
+	// Savings is the sum of each item's savings times its quantity. A BOGO
+	// deal saves half the price of one item per item bought.
+	Savings float64 `json:"savings"`
+	// Priced is how many items have a price in their savings text; the
+	// others are left out of Cost.
+	Priced int `json:"priced"`
+	// Units is the number of things to buy, counting quantities.
+	Units int `json:"units"`
+}
+
+// Totals estimates the list's cost and savings. Items without savings text,
+// such as free-text entries, only count towards Units.
+func (l *List) Totals() Totals {
+	var t Totals
+	for _, item := range l.Items {
+		t.Units += item.Quantity()
+		cost, savings := item.Estimate()
+		if cost > 0 {
+			t.Cost += cost
+			t.Priced++
+		}
+		t.Savings += savings
+	}
+	return t
+}
+
+// Estimate is the item's sale price and savings, read from its savings text
+// and times its quantity. Either is zero when the text does not state it.
+func (it Item) Estimate() (cost, savings float64) {
+	if it.Savings == "" {
+		return 0, 0
+	}
+	text := it.Savings
+	s := filter.ParseSavings(api.SavingItem{Savings: &text})
+	qty := float64(it.Quantity())
+	saved := s.Dollars
+	if s.BOGO {
+		saved /= 2
+	}
+	return s.EffectivePrice() * qty, saved * qty
+}
@@ -0,0 +1,138 @@
+package shoplist
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+// Pass is the header shown on the wallet-style export.
+type Pass struct {
+	StoreName    string
+	StoreAddress string
+	// AdWeek is the ad's validity range, e.g. "10/15 – 10/21".
+	AdWeek      string
+	GeneratedAt time.Time
+}
+
+type walletGroup struct {
+	Department string
+	Items      []Item
+}
+
+type walletData struct {
+	Pass
+	Count  int
+	Groups []walletGroup
+}
+
+// RenderWallet writes the list as a single self-contained HTML page sized
+// for phones: no external assets, so it works offline once saved, and
+// ticked boxes are remembered in the browser's local storage.
+func RenderWallet(w io.Writer, l *List, pass Pass) error {
+	return walletTemplate.Execute(w, walletData{Pass: pass, Count: len(l.Items), Groups: groupByDepartment(l.Items)})
+}
+
+// groupByDepartment keeps list order within a department and sorts the
+// departments, with free-text items last.
+func groupByDepartment(items []Item) []walletGroup {
+	var groups []walletGroup
+	for _, i := range ShoppingOrder(items) {
+		dept := DepartmentOf(items[i])
+		if len(groups) == 0 || groups[len(groups)-1].Department != dept {
+			groups = append(groups, walletGroup{Department: dept})
+		}
+		groups[len(groups)-1].Items = append(groups[len(groups)-1].Items, items[i])
+	}
+	return groups
+}
+
+// DepartmentOf is the department an item is grouped under; free-text items
+// are in "Other".
+func DepartmentOf(item Item) string {
+	if item.Department == "" {
+		return "Other"
+	}
+	return item.Department
+}
+
+// ShoppingOrder returns the indexes of items grouped by department: the
+// departments sorted by name with "Other" last, and list order kept within
+// each one.
+func ShoppingOrder(items []Item) []int {
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		da, db := DepartmentOf(items[order[a]]), DepartmentOf(items[order[b]])
+		if (da == "Other") != (db == "Other") {
+			return db == "Other"
+		}
+		return da < db
+	})
+	return order
+}
+
+var walletTemplate = template.Must(template.New("wallet").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="apple-mobile-web-app-capable" content="yes">
+<title>Shopping list{{if .StoreName}} · {{.StoreName}}{{end}}</title>
+<style>
+  body { margin: 0; font: 17px/1.4 -apple-system, system-ui, sans-serif; background: #f2f2f2; color: #222; }
+  .pass { max-width: 480px; margin: 0 auto; padding: 12px; }
+  header { background: #3a7d2c; color: #fff; border-radius: 14px; padding: 16px; }
+  header h1 { margin: 0 0 4px; font-size: 22px; }
+  header p { margin: 2px 0; opacity: .9; }
+  section { background: #fff; border-radius: 14px; margin-top: 12px; padding: 4px 16px; }
+  h2 { font-size: 13px; text-transform: uppercase; letter-spacing: .05em; color: #666; margin: 12px 0 4px; }
+  label { display: flex; gap: 12px; align-items: flex-start; padding: 10px 0; border-top: 1px solid #eee; }
+  h2 + label { border-top: 0; }
+  input { width: 22px; height: 22px; margin: 2px 0 0; flex: none; }
+  input:checked + span { text-decoration: line-through; color: #999; }
+  small { display: block; color: #3a7d2c; }
+  em { display: block; font-size: 15px; color: #666; }
+  footer { text-align: center; color: #999; font-size: 12px; margin: 16px 0; }
+</style>
+</head>
+<body>
+<div class="pass">
+<header>
+  <h1>{{if .StoreName}}{{.StoreName}}{{else}}Shopping list{{end}}</h1>
+  {{- if .StoreAddress}}
+  <p>{{.StoreAddress}}</p>
+  {{- end}}
+  {{- if .AdWeek}}
+  <p>Weekly ad: {{.AdWeek}}</p>
+  {{- end}}
+  <p>{{.Count}} item(s)</p>
+</header>
+{{- range .Groups}}
+<section>
+  <h2>{{.Department}}</h2>
+  {{- range .Items}}
+  <label><input type="checkbox" data-key="{{if .DealID}}{{.DealID}}{{else}}{{.Name}}{{end}}"><span>{{if gt .Qty 1}}{{.Qty}} × {{end}}{{.Name}}{{if or .Savings .ValidTo}}<small>{{.Savings}}{{if and .Savings .ValidTo}} · {{end}}{{if .ValidTo}}through {{.ValidTo}}{{end}}</small>{{end}}{{if .Note}}<em>{{.Note}}</em>{{end}}</span></label>
+  {{- end}}
+</section>
+{{- end}}
+<footer>Generated {{.GeneratedAt.Format "Jan 2, 2006 3:04 PM"}} by pubcli</footer>
+</div>
+<script>
+  var store = "pubcli-list:" + location.pathname;
+  var checked = {};
+  try { checked = JSON.parse(localStorage.getItem(store)) || {}; } catch (e) {}
+  document.querySelectorAll("input[data-key]").forEach(function (box) {
+    box.checked = !!checked[box.dataset.key];
+    box.addEventListener("change", function () {
+      checked[box.dataset.key] = box.checked;
+      try { localStorage.setItem(store, JSON.stringify(checked)); } catch (e) {}
+    });
+  });
+</script>
+</body>
+</html>
+`))
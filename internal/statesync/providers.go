@@ -0,0 +1,135 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Dir stores objects as files in a shared directory, such as a folder kept
+// in step by Syncthing or Dropbox, or a network mount.
+type Dir struct {
+	Path string
+}
+
+func (d *Dir) Name() string { return "dir " + d.Path }
+
+func (d *Dir) Get(_ context.Context, name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(d.Path, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+	return data, err
+}
+
+func (d *Dir) Put(_ context.Context, name string, data []byte) error {
+	return writeAtomic(filepath.Join(d.Path, name), data)
+}
+
+// WebDAV stores objects under a collection URL on a WebDAV server, such as
+// Nextcloud, ownCloud, or Apache mod_dav.
+type WebDAV struct {
+	// URL is the collection, e.g. https://cloud.example.com/remote.php/dav/files/me/pubcli/.
+	URL      string
+	Username string
+	Password string
+	Client   *http.Client
+}
+
+func (w *WebDAV) Name() string { return "webdav " + w.URL }
+
+func (w *WebDAV) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := w.do(ctx, http.MethodGet, w.objectURL(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotExist
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("GET %s: %s", name, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Put uploads the object. A missing collection is created once and the
+// upload retried.
+func (w *WebDAV) Put(ctx context.Context, name string, data []byte) error {
+	status, err := w.put(ctx, name, data)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusConflict || status == http.StatusNotFound {
+		resp, err := w.do(ctx, "MKCOL", w.collectionURL(), nil)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMethodNotAllowed {
+			return fmt.Errorf("MKCOL: %s", resp.Status)
+		}
+		if status, err = w.put(ctx, name, data); err != nil {
+			return err
+		}
+	}
+	if status < 200 || status > 299 {
+		return fmt.Errorf("PUT %s: %d %s", name, status, http.StatusText(status))
+	}
+	return nil
+}
+
+func (w *WebDAV) put(ctx context.Context, name string, data []byte) (int, error) {
+	resp, err := w.do(ctx, http.MethodPut, w.objectURL(name), data)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (w *WebDAV) do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if w.Username != "" || w.Password != "" {
+		req.SetBasicAuth(w.Username, w.Password)
+	}
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, url, err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s (check sync.username and the password)", method, url, resp.Status)
+	}
+	return resp, nil
+}
+
+func (w *WebDAV) collectionURL() string {
+	return strings.TrimSuffix(w.URL, "/") + "/"
+}
+
+func (w *WebDAV) objectURL(name string) string {
+	return w.collectionURL() + name
+}
@@ -0,0 +1,309 @@
+// Package statesync shares pubcli's saved state, such as the shopping list
+// and the alert watchlist, between devices through a remote store.
+//
+// Each file is synced as a whole. A file changed on only one side since the
+// last sync is copied to the other side; when both sides changed, the copy
+// with the newer modification time wins.
+package statesync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// ErrNotExist is returned by a Provider for an object it does not hold.
+var ErrNotExist = errors.New("object does not exist")
+
+// Provider stores opaque objects by name. Implementations only move bytes;
+// the sync decisions are made by Syncer.
+type Provider interface {
+	// Name describes the remote for output. It must not contain secrets.
+	Name() string
+	// Get returns the object, or ErrNotExist.
+	Get(ctx context.Context, name string) ([]byte, error)
+	Put(ctx context.Context, name string, data []byte) error
+}
+
+// Action is what a sync does, or would do, with one file.
+type Action string
+
+const (
+	ActionNone Action = "none"
+	ActionPush Action = "push"
+	ActionPull Action = "pull"
+)
+
+// FileResult describes one synced file.
+type FileResult struct {
+	File   string `json:"file"`
+	Action Action `json:"action"`
+	// Conflict is set when both copies changed since the last sync; the
+	// newer one was kept.
+	Conflict       bool       `json:"conflict,omitempty"`
+	LocalModified  *time.Time `json:"localModified,omitempty"`
+	RemoteModified *time.Time `json:"remoteModified,omitempty"`
+	RemoteDevice   string     `json:"remoteDevice,omitempty"`
+}
+
+// Report is the outcome of Sync or Status.
+type Report struct {
+	Remote   string       `json:"remote"`
+	LastSync *time.Time   `json:"lastSync,omitempty"`
+	Files    []FileResult `json:"files"`
+}
+
+// envelope is the remote form of a file. It carries the file's modification
+// time, which remotes do not reliably preserve, for conflict resolution.
+type envelope struct {
+	File     string    `json:"file"`
+	Modified time.Time `json:"modified"`
+	Device   string    `json:"device,omitempty"`
+	Content  []byte    `json:"content"`
+}
+
+// state records the content hash of every file as of the last sync, which
+// tells local and remote changes apart.
+type state struct {
+	LastSync time.Time         `json:"lastSync"`
+	Hashes   map[string]string `json:"hashes"`
+}
+
+// Syncer syncs a fixed set of files, by storage key, in the local backend.
+type Syncer struct {
+	Provider Provider
+	// Local holds the local files.
+	Local storage.Backend
+	Files []string
+	// StatePath is where the last-sync bookkeeping is kept.
+	StatePath string
+	// Device names this machine in pushed files.
+	Device string
+	// Now returns the current time; nil uses time.Now.
+	Now func() time.Time
+}
+
+type localFile struct {
+	content  []byte
+	modified time.Time
+	hash     string
+}
+
+type plan struct {
+	result FileResult
+	local  *localFile
+	remote *envelope
+}
+
+// Status reports what Sync would do without changing anything.
+func (s *Syncer) Status(ctx context.Context) (Report, error) {
+	st, err := s.loadState()
+	if err != nil {
+		return Report{}, err
+	}
+	plans, err := s.plan(ctx, st)
+	if err != nil {
+		return Report{}, err
+	}
+	return s.report(st, plans), nil
+}
+
+// Sync pushes and pulls every file as needed and records the new state.
+func (s *Syncer) Sync(ctx context.Context) (Report, error) {
+	st, err := s.loadState()
+	if err != nil {
+		return Report{}, err
+	}
+	plans, err := s.plan(ctx, st)
+	if err != nil {
+		return Report{}, err
+	}
+
+	for _, p := range plans {
+		name := p.result.File
+		switch p.result.Action {
+		case ActionPush:
+			data, err := json.Marshal(envelope{File: name, Modified: p.local.modified, Device: s.Device, Content: p.local.content})
+			if err != nil {
+				return Report{}, err
+			}
+			if err := s.Provider.Put(ctx, name, data); err != nil {
+				return Report{}, fmt.Errorf("uploading %s: %w", name, err)
+			}
+			st.Hashes[name] = p.local.hash
+		case ActionPull:
+			if err := writeLocal(s.Local, name, p.remote); err != nil {
+				return Report{}, err
+			}
+			st.Hashes[name] = hash(p.remote.Content)
+		default:
+			if p.local != nil {
+				st.Hashes[name] = p.local.hash
+			}
+		}
+	}
+
+	st.LastSync = s.now().UTC()
+	if err := s.saveState(st); err != nil {
+		return Report{}, err
+	}
+	return s.report(st, plans), nil
+}
+
+func (s *Syncer) plan(ctx context.Context, st *state) ([]plan, error) {
+	plans := make([]plan, 0, len(s.Files))
+	for _, name := range s.Files {
+		local, err := readLocal(s.Local, name)
+		if err != nil {
+			return nil, err
+		}
+		remote, err := s.fetch(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		p := plan{result: FileResult{File: name, Action: ActionNone}, local: local, remote: remote}
+		if local != nil {
+			p.result.LocalModified = &local.modified
+		}
+		if remote != nil {
+			p.result.RemoteModified = &remote.Modified
+			p.result.RemoteDevice = remote.Device
+		}
+		p.result.Action, p.result.Conflict = decide(local, remote, st.Hashes[name])
+		plans = append(plans, p)
+	}
+	return plans, nil
+}
+
+// decide picks the sync direction. A file missing on one side is copied
+// from the other; deletions are never propagated.
+func decide(local *localFile, remote *envelope, base string) (Action, bool) {
+	switch {
+	case local == nil && remote == nil:
+		return ActionNone, false
+	case remote == nil:
+		return ActionPush, false
+	case local == nil:
+		return ActionPull, false
+	}
+
+	remoteHash := hash(remote.Content)
+	if local.hash == remoteHash {
+		return ActionNone, false
+	}
+	localChanged := local.hash != base
+	remoteChanged := remoteHash != base
+	switch {
+	case localChanged && !remoteChanged:
+		return ActionPush, false
+	case remoteChanged && !localChanged:
+		return ActionPull, false
+	case remote.Modified.After(local.modified):
+		return ActionPull, true
+	default:
+		return ActionPush, true
+	}
+}
+
+func (s *Syncer) fetch(ctx context.Context, name string) (*envelope, error) {
+	data, err := s.Provider.Get(ctx, name)
+	if errors.Is(err, ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", name, err)
+	}
+	env := &envelope{}
+	if err := json.Unmarshal(data, env); err != nil {
+		return nil, fmt.Errorf("remote %s is not a pubcli sync file: %w", name, err)
+	}
+	return env, nil
+}
+
+func (s *Syncer) report(st *state, plans []plan) Report {
+	r := Report{Remote: s.Provider.Name(), Files: make([]FileResult, 0, len(plans))}
+	if !st.LastSync.IsZero() {
+		last := st.LastSync
+		r.LastSync = &last
+	}
+	for _, p := range plans {
+		r.Files = append(r.Files, p.result)
+	}
+	return r
+}
+
+func (s *Syncer) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *Syncer) loadState() (*state, error) {
+	st := &state{Hashes: map[string]string{}}
+	data, err := os.ReadFile(s.StatePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("parsing sync state %s: %w", s.StatePath, err)
+	}
+	if st.Hashes == nil {
+		st.Hashes = map[string]string{}
+	}
+	return st, nil
+}
+
+func (s *Syncer) saveState(st *state) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(s.StatePath, data)
+}
+
+func readLocal(b storage.Backend, name string) (*localFile, error) {
+	obj, err := b.Get(name)
+	if errors.Is(err, storage.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &localFile{content: obj.Data, modified: obj.Modified.UTC(), hash: hash(obj.Data)}, nil
+}
+
+// writeLocal replaces the local file and stamps it with the remote
+// modification time, so the next conflict compares the original edit times.
+func writeLocal(b storage.Backend, name string, env *envelope) error {
+	return b.Put(name, storage.Object{Data: env.Content, Modified: env.Modified})
+}
+
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
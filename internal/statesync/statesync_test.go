@@ -0,0 +1,161 @@
+package statesync_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/statesync"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func newDevice(t *testing.T, provider statesync.Provider, name string) *statesync.Syncer {
+	t.Helper()
+	dir := t.TempDir()
+	return &statesync.Syncer{
+		Provider:  provider,
+		Local:     &storage.Files{Dir: dir},
+		Files:     []string{"list.json", "watchlist.json"},
+		StatePath: filepath.Join(dir, "sync-state.json"),
+		Device:    name,
+	}
+}
+
+func writeAt(t *testing.T, s *statesync.Syncer, name, content string, modified time.Time) {
+	t.Helper()
+	require.NoError(t, s.Local.Put(name, storage.Object{Data: []byte(content), Modified: modified}))
+}
+
+func readFile(t *testing.T, s *statesync.Syncer, name string) string {
+	t.Helper()
+	obj, err := s.Local.Get(name)
+	require.NoError(t, err)
+	return string(obj.Data)
+}
+
+func actions(r statesync.Report) map[string]statesync.Action {
+	m := map[string]statesync.Action{}
+	for _, f := range r.Files {
+		m[f.File] = f.Action
+	}
+	return m
+}
+
+func TestSyncer_PushPullAndConflicts(t *testing.T) {
+	ctx := context.Background()
+	remote := &statesync.Dir{Path: t.TempDir()}
+	laptop := newDevice(t, remote, "laptop")
+	phone := newDevice(t, remote, "phone")
+	t0 := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+
+	writeAt(t, laptop, "list.json", `{"items":["milk"]}`, t0)
+	report, err := laptop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]statesync.Action{"list.json": statesync.ActionPush, "watchlist.json": statesync.ActionNone}, actions(report))
+	require.NotNil(t, report.LastSync)
+
+	status, err := phone.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, statesync.ActionPull, actions(status)["list.json"])
+	assert.Equal(t, "laptop", status.Files[0].RemoteDevice)
+	assert.Nil(t, status.LastSync, "status does not sync")
+
+	_, err = phone.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, `{"items":["milk"]}`, readFile(t, phone, "list.json"))
+
+	report, err = phone.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, statesync.ActionNone, actions(report)["list.json"], "nothing changed")
+
+	// Only the phone edits: a plain push, whatever the clocks say.
+	writeAt(t, phone, "list.json", `{"items":["milk","eggs"]}`, t0.Add(-time.Hour))
+	report, err = phone.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, statesync.ActionPush, actions(report)["list.json"])
+	assert.False(t, report.Files[0].Conflict)
+
+	// Both edit; the newer edit wins.
+	writeAt(t, laptop, "list.json", `{"items":["bread"]}`, t0.Add(2*time.Hour))
+	report, err = laptop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, statesync.ActionPush, actions(report)["list.json"])
+	assert.True(t, report.Files[0].Conflict)
+
+	writeAt(t, phone, "list.json", `{"items":["eggs","butter"]}`, t0.Add(3*time.Hour))
+	report, err = phone.Sync(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.Files[0].Conflict)
+	assert.Equal(t, statesync.ActionPush, actions(report)["list.json"], "phone edit is newer")
+
+	_, err = laptop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, `{"items":["eggs","butter"]}`, readFile(t, laptop, "list.json"))
+}
+
+// davServer is a minimal in-memory WebDAV collection.
+type davServer struct {
+	mu         sync.Mutex
+	objects    map[string][]byte
+	collection bool
+}
+
+func (d *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if user, pass, _ := r.BasicAuth(); user != "me" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.Method {
+	case "MKCOL":
+		d.collection = true
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodPut:
+		if !d.collection {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		d.objects[r.URL.Path] = data
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		data, ok := d.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	}
+}
+
+func TestWebDAV_CreatesCollectionAndRoundTrips(t *testing.T) {
+	dav := &davServer{objects: map[string][]byte{}}
+	srv := httptest.NewServer(dav)
+	defer srv.Close()
+	ctx := context.Background()
+
+	provider := &statesync.WebDAV{URL: srv.URL + "/pubcli", Username: "me", Password: "secret"}
+	_, err := provider.Get(ctx, "list.json")
+	assert.ErrorIs(t, err, statesync.ErrNotExist)
+
+	require.NoError(t, provider.Put(ctx, "list.json", []byte("{}")))
+	assert.True(t, dav.collection)
+	data, err := provider.Get(ctx, "list.json")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+
+	bad := &statesync.WebDAV{URL: srv.URL + "/pubcli", Username: "me", Password: "wrong"}
+	_, err = bad.Get(ctx, "list.json")
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "401"))
+	assert.NotContains(t, provider.Name(), "secret")
+}
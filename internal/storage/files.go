@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Files keeps each value in the file its key names inside Dir, written
+// atomically.
+type Files struct {
+	Dir string
+}
+
+func (f *Files) Name() string { return "json files in " + f.Dir }
+
+func (f *Files) path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(f.Dir, filepath.FromSlash(key)), nil
+}
+
+func (f *Files) Get(key string) (Object, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return Object{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Object{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Object{}, err
+	}
+	return Object{Data: data, Modified: info.ModTime().UTC()}, nil
+}
+
+func (f *Files) Put(key string, obj Object) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, obj.Data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", key, err)
+	}
+	if !obj.Modified.IsZero() {
+		if err := os.Chtimes(tmp, obj.Modified, obj.Modified); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp, path)
+}
+
+func (f *Files) Delete(key string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Keys walks Dir for the files under prefix. Leftover .tmp files from an
+// interrupted write are skipped.
+func (f *Files) Keys(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(f.Dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == f.Dir {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		rel, err := filepath.Rel(f.Dir, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", f.Dir, err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (f *Files) Close() error { return nil }
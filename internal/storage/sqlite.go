@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// DBFile is the SQLite database's file name inside the data directory.
+const DBFile = "pubcli.db"
+
+// schema keeps every value as a row. JSON values are stored as text, so
+// SQLite's JSON functions can query them, e.g.
+//
+//	SELECT json_extract(value, '$.items') FROM documents WHERE key = 'list.json'
+const schema = `CREATE TABLE IF NOT EXISTS documents (
+	key      TEXT PRIMARY KEY,
+	value    TEXT NOT NULL,
+	modified TEXT NOT NULL
+)`
+
+// SQLite keeps values in the documents table of a SQLite database.
+type SQLite struct {
+	path string
+	db   *sql.DB
+}
+
+// OpenSQLite opens, and creates if needed, the database at path.
+func OpenSQLite(path string) (*SQLite, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating data directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+filepath.ToSlash(path)+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	return &SQLite{path: path, db: db}, nil
+}
+
+func (s *SQLite) Name() string { return "sqlite " + s.path }
+
+func (s *SQLite) Get(key string) (Object, error) {
+	if err := validKey(key); err != nil {
+		return Object{}, err
+	}
+	var value, modified string
+	err := s.db.QueryRow(`SELECT value, modified FROM documents WHERE key = ?`, key).Scan(&value, &modified)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Object{}, ErrNotExist
+	}
+	if err != nil {
+		return Object{}, fmt.Errorf("reading %s: %w", key, err)
+	}
+	t, err := time.Parse(time.RFC3339Nano, modified)
+	if err != nil {
+		return Object{}, fmt.Errorf("reading %s: bad modification time %q", key, modified)
+	}
+	return Object{Data: []byte(value), Modified: t}, nil
+}
+
+func (s *SQLite) Put(key string, obj Object) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	modified := obj.Modified
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO documents (key, value, modified) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, modified = excluded.modified`,
+		key, string(obj.Data), modified.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *SQLite) Delete(key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	res, err := s.db.Exec(`DELETE FROM documents WHERE key = ?`, key)
+	if err != nil {
+		return fmt.Errorf("deleting %s: %w", key, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotExist
+	}
+	return nil
+}
+
+func (s *SQLite) Keys(prefix string) ([]string, error) {
+	// substr rather than LIKE, so '%' and '_' in prefix are literal.
+	rows, err := s.db.Query(`SELECT key FROM documents WHERE substr(key, 1, ?) = ? ORDER BY key`, utf8.RuneCountInString(prefix), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("listing keys: %w", err)
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *SQLite) Close() error { return s.db.Close() }
+
+// DB exposes the database for queries beyond the Backend interface.
+func (s *SQLite) DB() *sql.DB { return s.db }
@@ -0,0 +1,112 @@
+// Package storage keeps pubcli's saved state — shopping lists, the alert
+// watchlist, and the ad history — behind one interface, so it can live in
+// JSON files in the data directory or in a SQLite database.
+//
+// Values are opaque bytes under slash-separated keys that mirror the JSON
+// file layout, e.g. "list.json" or "history/1425/2025-02-12.json". Packages
+// that own the state decide what the bytes are; a Backend only moves them.
+package storage
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotExist is returned for a key a backend does not hold. It is
+// fs.ErrNotExist, so errors.Is works the same for every backend.
+var ErrNotExist = fs.ErrNotExist
+
+// Object is a stored value.
+type Object struct {
+	Data []byte
+	// Modified is when the value was last written. Put stamps the current
+	// time when it is zero.
+	Modified time.Time
+}
+
+// Backend stores values by key.
+type Backend interface {
+	// Name describes where the values are kept, e.g. "sqlite
+	// /home/me/.local/share/pubcli/pubcli.db".
+	Name() string
+	// Get returns the value under key, or ErrNotExist.
+	Get(key string) (Object, error)
+	// Put stores the value, replacing any earlier one.
+	Put(key string, obj Object) error
+	// Delete removes the value, or returns ErrNotExist.
+	Delete(key string) error
+	// Keys returns the stored keys that start with prefix, sorted.
+	Keys(prefix string) ([]string, error)
+	Close() error
+}
+
+// Backends are the values of the `storage.backend` setting.
+const (
+	// BackendJSON keeps each value in its own JSON file in the data
+	// directory. It is the default.
+	BackendJSON = "json"
+	// BackendSQLite keeps every value in one SQLite database, DBFile in the
+	// data directory, which other tools can query.
+	BackendSQLite = "sqlite"
+)
+
+// Open returns the backend a `storage.backend` setting names, keeping its
+// data in dataDir. Empty means json.
+func Open(backend, dataDir string) (Backend, error) {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", BackendJSON:
+		return &Files{Dir: dataDir}, nil
+	case BackendSQLite:
+		return OpenSQLite(filepath.Join(dataDir, DBFile))
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (use json or sqlite)", backend)
+	}
+}
+
+// ValidateBackend rejects a `storage.backend` setting Open would not accept,
+// without opening anything.
+func ValidateBackend(backend string) error {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", BackendJSON, BackendSQLite:
+		return nil
+	default:
+		return fmt.Errorf("unknown storage backend %q (use json or sqlite)", backend)
+	}
+}
+
+// validKey rejects keys that are empty, absolute, or escape the data
+// directory.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	return nil
+}
+
+// Migrate copies every value under the given key prefixes from one backend
+// to another, keeping modification times, and returns the keys it copied.
+// Values already in to are replaced; nothing is removed from from.
+func Migrate(from, to Backend, prefixes []string) ([]string, error) {
+	var copied []string
+	for _, prefix := range prefixes {
+		keys, err := from.Keys(prefix)
+		if err != nil {
+			return copied, fmt.Errorf("listing %s: %w", from.Name(), err)
+		}
+		for _, key := range keys {
+			obj, err := from.Get(key)
+			if err != nil {
+				return copied, fmt.Errorf("reading %s from %s: %w", key, from.Name(), err)
+			}
+			if err := to.Put(key, obj); err != nil {
+				return copied, fmt.Errorf("writing %s to %s: %w", key, to.Name(), err)
+			}
+			copied = append(copied, key)
+		}
+	}
+	return copied, nil
+}
@@ -0,0 +1,74 @@
+package storage_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+func backends(t *testing.T) map[string]storage.Backend {
+	db, err := storage.OpenSQLite(filepath.Join(t.TempDir(), storage.DBFile))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return map[string]storage.Backend{
+		"json":   &storage.Files{Dir: t.TempDir()},
+		"sqlite": db,
+	}
+}
+
+func TestBackends_RoundTrip(t *testing.T) {
+	for name, b := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := b.Get("list.json")
+			assert.True(t, errors.Is(err, storage.ErrNotExist))
+
+			modified := time.Date(2025, 2, 12, 9, 0, 0, 0, time.UTC)
+			require.NoError(t, b.Put("list.json", storage.Object{Data: []byte(`{"items":[]}`), Modified: modified}))
+			require.NoError(t, b.Put("history/1425/2025-02-12.json", storage.Object{Data: []byte(`{}`)}))
+			require.NoError(t, b.Put("history/1425/2025-02-05.json", storage.Object{Data: []byte(`{}`)}))
+
+			obj, err := b.Get("list.json")
+			require.NoError(t, err)
+			assert.Equal(t, `{"items":[]}`, string(obj.Data))
+			assert.True(t, modified.Equal(obj.Modified))
+
+			keys, err := b.Keys("history/")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"history/1425/2025-02-05.json", "history/1425/2025-02-12.json"}, keys)
+
+			require.NoError(t, b.Delete("list.json"))
+			assert.True(t, errors.Is(b.Delete("list.json"), storage.ErrNotExist))
+			assert.Error(t, b.Put("../escape.json", storage.Object{}))
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	b := backends(t)
+	from, to := b["json"], b["sqlite"]
+	require.NoError(t, from.Put("list.json", storage.Object{Data: []byte("list")}))
+	require.NoError(t, from.Put("lists/party.json", storage.Object{Data: []byte("party")}))
+	require.NoError(t, from.Put("cookies.json", storage.Object{Data: []byte("cookies")}))
+
+	copied, err := storage.Migrate(from, to, []string{"list.json", "lists/"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"list.json", "lists/party.json"}, copied)
+	obj, err := to.Get("lists/party.json")
+	require.NoError(t, err)
+	assert.Equal(t, "party", string(obj.Data))
+	_, err = to.Get("cookies.json")
+	assert.True(t, errors.Is(err, storage.ErrNotExist))
+}
+
+func TestOpen_UnknownBackend(t *testing.T) {
+	_, err := storage.Open("postgres", t.TempDir())
+	assert.Error(t, err)
+	assert.Error(t, storage.ValidateBackend("postgres"))
+	assert.NoError(t, storage.ValidateBackend("SQLite"))
+}
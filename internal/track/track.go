@@ -0,0 +1,184 @@
+// Package track keeps price thresholds for items and checks weekly ads for
+// deals priced below them, using the prices the savings parser reads from
+// deal text and the ad history for context.
+package track
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/display"
+	"github.com/tayloree/publix-deals/internal/filter"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+)
+
+// FileName is the tracked list's file name inside the data directory.
+const FileName = "tracked.json"
+
+// Item is a price threshold for deals matching a query.
+type Item struct {
+	// Query matches deals whose title or description contains every one
+	// of its words, case-insensitively.
+	Query string `json:"query"`
+	// Below is the price one item must cost less than.
+	Below float64 `json:"below"`
+}
+
+// List holds the tracked items.
+type List struct {
+	Items []Item `json:"items"`
+}
+
+// Load reads the list stored under key. A missing list is empty.
+func Load(b storage.Backend, key string) (*List, error) {
+	obj, err := b.Get(key)
+	if errors.Is(err, storage.ErrNotExist) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading tracked items: %w", err)
+	}
+	l := &List{}
+	if err := json.Unmarshal(obj.Data, l); err != nil {
+		return nil, fmt.Errorf("parsing tracked items %s: %w", key, err)
+	}
+	return l, nil
+}
+
+// Save stores the list under key, replacing the previous one.
+func (l *List) Save(b storage.Backend, key string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.Put(key, storage.Object{Data: data}); err != nil {
+		return fmt.Errorf("writing tracked items: %w", err)
+	}
+	return nil
+}
+
+// Set adds an item, replacing the threshold of an item with the same
+// query. It reports whether an existing item was replaced.
+func (l *List) Set(item Item) bool {
+	for i, existing := range l.Items {
+		if sameQuery(existing.Query, item.Query) {
+			l.Items[i] = item
+			return true
+		}
+	}
+	l.Items = append(l.Items, item)
+	return false
+}
+
+// Remove deletes the item with the given query.
+func (l *List) Remove(query string) bool {
+	for i, existing := range l.Items {
+		if sameQuery(existing.Query, query) {
+			l.Items = append(l.Items[:i], l.Items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func sameQuery(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+// Matches reports whether the deal's title or description contains every
+// word of the query.
+func (it Item) Matches(deal api.SavingItem) bool {
+	words := strings.Fields(strings.ToLower(it.Query))
+	if len(words) == 0 {
+		return false
+	}
+	text := strings.ToLower(filter.CleanText(filter.Deref(deal.Title)) + " " + filter.CleanText(filter.Deref(deal.Description)))
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// Price is what one item costs on the deal, as filter.Savings.EffectivePrice
+// reads it. It is zero when the deal text states no price.
+func Price(deal api.SavingItem) float64 {
+	return filter.ParseSavings(deal).EffectivePrice()
+}
+
+// Status is a tracked item checked against one store's ad.
+type Status struct {
+	Query string  `json:"query"`
+	Below float64 `json:"below"`
+	// Firing is set when a matching deal costs less than Below.
+	Firing bool `json:"firing"`
+	// Price is the lowest price among matching deals in the current ad,
+	// or zero when none states a price.
+	Price float64 `json:"price"`
+	// Deals are the matching deals priced below the threshold, cheapest
+	// first.
+	Deals []PricedDeal `json:"deals"`
+	// PreviousLow is the lowest price of a matching deal in past ads, and
+	// PreviousLowWeek the ad week it was seen in; zero and empty without
+	// history.
+	PreviousLow     float64 `json:"previousLow,omitempty"`
+	PreviousLowWeek string  `json:"previousLowWeek,omitempty"`
+
+	// Hits are the deals behind Deals, for notifications.
+	Hits []api.SavingItem `json:"-"`
+}
+
+// PricedDeal is a deal with the price the threshold was compared against.
+type PricedDeal struct {
+	display.DealJSON
+	Price float64 `json:"price"`
+}
+
+// Check compares every item with the current ad, and with past snapshots
+// of the same store for the previous low.
+func Check(items []Item, deals []api.SavingItem, past []history.Snapshot) []Status {
+	statuses := make([]Status, 0, len(items))
+	for _, it := range items {
+		st := Status{Query: it.Query, Below: it.Below, Deals: []PricedDeal{}}
+		for _, deal := range deals {
+			if !it.Matches(deal) {
+				continue
+			}
+			price := Price(deal)
+			if price <= 0 {
+				continue
+			}
+			if st.Price == 0 || price < st.Price {
+				st.Price = price
+			}
+			if price < it.Below {
+				st.Hits = append(st.Hits, deal)
+			}
+		}
+		sort.SliceStable(st.Hits, func(i, j int) bool { return Price(st.Hits[i]) < Price(st.Hits[j]) })
+		for _, deal := range st.Hits {
+			st.Deals = append(st.Deals, PricedDeal{DealJSON: display.ToDealJSON(deal), Price: Price(deal)})
+		}
+		st.Firing = len(st.Hits) > 0
+
+		for _, snapshot := range past {
+			for _, deal := range snapshot.Deals {
+				if !it.Matches(deal) {
+					continue
+				}
+				if price := Price(deal); price > 0 && (st.PreviousLow == 0 || price < st.PreviousLow) {
+					st.PreviousLow = price
+					st.PreviousLowWeek = history.WeekStart(snapshot.SavedAt).Format("2006-01-02")
+				}
+			}
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
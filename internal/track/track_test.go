@@ -0,0 +1,68 @@
+package track_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tayloree/publix-deals/internal/api"
+	"github.com/tayloree/publix-deals/internal/history"
+	"github.com/tayloree/publix-deals/internal/storage"
+	"github.com/tayloree/publix-deals/internal/track"
+)
+
+func ptr(s string) *string { return &s }
+
+func TestItem_MatchesEveryWord(t *testing.T) {
+	it := track.Item{Query: "Boneless  chicken"}
+	assert.True(t, it.Matches(api.SavingItem{Title: ptr("Publix Boneless Skinless Chicken Breasts")}))
+	assert.True(t, it.Matches(api.SavingItem{Title: ptr("Chicken Breasts"), Description: ptr("Boneless, family pack")}))
+	assert.False(t, it.Matches(api.SavingItem{Title: ptr("Bone-In Chicken Thighs")}))
+}
+
+func TestCheck_FiresBelowThreshold(t *testing.T) {
+	deals := []api.SavingItem{
+		{ID: "1", Title: ptr("Boneless Chicken Breasts"), Savings: ptr("$3.49 lb")},
+		{ID: "2", Title: ptr("Boneless Chicken Thighs"), Savings: ptr("Buy 1 Get 1 FREE"), AdditionalDealInfo: ptr("$4.98")},
+		{ID: "3", Title: ptr("Boneless Chicken Wings")},
+		{ID: "4", Title: ptr("Ground Coffee"), Savings: ptr("$7.99")},
+	}
+	past := []history.Snapshot{
+		{SavedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), Deals: []api.SavingItem{{Title: ptr("Boneless Chicken Breasts"), Savings: ptr("2/$4")}}},
+	}
+	items := []track.Item{{Query: "boneless chicken", Below: 2.99}, {Query: "ground coffee", Below: 5}, {Query: "salmon", Below: 9}}
+
+	statuses := track.Check(items, deals, past)
+
+	require.Len(t, statuses, 3)
+	chicken := statuses[0]
+	assert.True(t, chicken.Firing)
+	assert.Equal(t, 2.49, chicken.Price, "BOGO at $4.98 is $2.49 each")
+	require.Len(t, chicken.Deals, 1)
+	assert.Equal(t, "Boneless Chicken Thighs", chicken.Deals[0].Title)
+	assert.Equal(t, 2.0, chicken.PreviousLow)
+	assert.Equal(t, "2026-09-30", chicken.PreviousLowWeek)
+
+	assert.False(t, statuses[1].Firing)
+	assert.Equal(t, 7.99, statuses[1].Price)
+	assert.Zero(t, statuses[2].Price)
+	assert.Empty(t, statuses[2].Deals)
+}
+
+func TestList_SetRemovePersist(t *testing.T) {
+	b := &storage.Files{Dir: t.TempDir()}
+	l, err := track.Load(b, track.FileName)
+	require.NoError(t, err)
+
+	assert.False(t, l.Set(track.Item{Query: "boneless chicken", Below: 2.99}))
+	assert.True(t, l.Set(track.Item{Query: "Boneless Chicken", Below: 2.49}), "queries compare case-insensitively")
+	require.NoError(t, l.Save(b, track.FileName))
+
+	l, err = track.Load(b, track.FileName)
+	require.NoError(t, err)
+	require.Len(t, l.Items, 1)
+	assert.Equal(t, 2.49, l.Items[0].Below)
+	assert.True(t, l.Remove("boneless   chicken"))
+	assert.False(t, l.Remove("salmon"))
+}